@@ -1,9 +1,12 @@
 package chatkit
 
 import (
+	"github.com/pusher/chatkit-server-go/internal/authenticator"
 	"github.com/pusher/chatkit-server-go/internal/authorizer"
+	"github.com/pusher/chatkit-server-go/internal/common"
 	"github.com/pusher/chatkit-server-go/internal/core"
 	"github.com/pusher/chatkit-server-go/internal/cursors"
+	"github.com/pusher/chatkit-server-go/internal/presence"
 
 	auth "github.com/pusher/pusher-platform-go/auth"
 	platformclient "github.com/pusher/pusher-platform-go/client"
@@ -11,18 +14,65 @@ import (
 
 const GrantTypeClientCredentials = auth.GrantTypeClientCredentials
 
+const (
+	PresenceStateOnline  = presence.StateOnline
+	PresenceStateOffline = presence.StateOffline
+
+	DeleteStatusPending   = core.DeleteStatusPending
+	DeleteStatusCompleted = core.DeleteStatusCompleted
+	DeleteStatusFailed    = core.DeleteStatusFailed
+
+	ThreadPartType = core.ThreadPartType
+
+	CursorTypeRead = cursors.CursorTypeRead
+
+	RoleScopeGlobal = authorizer.ScopeGlobal
+	RoleScopeRoom   = authorizer.ScopeRoom
+
+	PermissionRoomCreate              = authorizer.PermissionRoomCreate
+	PermissionRoomDelete              = authorizer.PermissionRoomDelete
+	PermissionRoomUpdate              = authorizer.PermissionRoomUpdate
+	PermissionRoomGet                 = authorizer.PermissionRoomGet
+	PermissionRoomJoin                = authorizer.PermissionRoomJoin
+	PermissionRoomLeave               = authorizer.PermissionRoomLeave
+	PermissionRoomMembersAdd          = authorizer.PermissionRoomMembersAdd
+	PermissionRoomMembersRemove       = authorizer.PermissionRoomMembersRemove
+	PermissionRoomMessagesGet         = authorizer.PermissionRoomMessagesGet
+	PermissionRoomTypingIndicatorSend = authorizer.PermissionRoomTypingIndicatorSend
+	PermissionMessageCreate           = authorizer.PermissionMessageCreate
+	PermissionPresenceSubscribe       = authorizer.PermissionPresenceSubscribe
+	PermissionUserGet                 = authorizer.PermissionUserGet
+	PermissionUserRoomsGet            = authorizer.PermissionUserRoomsGet
+	PermissionFileCreate              = authorizer.PermissionFileCreate
+	PermissionFileGet                 = authorizer.PermissionFileGet
+	PermissionCursorsReadGet          = authorizer.PermissionCursorsReadGet
+	PermissionCursorsReadSet          = authorizer.PermissionCursorsReadSet
+)
+
 type (
 	AuthenticatePayload = auth.Payload
 	AuthenticateOptions = auth.Options
 
+	Claims       = authenticator.Claims
+	TokenOptions = authenticator.Options
+
 	ErrorResponse  = platformclient.ErrorResponse
 	RequestOptions = platformclient.RequestOptions
 
+	UnknownField           = common.UnknownField
+	Interceptor            = common.Interceptor
+	Invoker                = common.Invoker
+	RawEvent               = common.SubscriptionEvent
+	EndOfSubscriptionError = common.EndOfSubscriptionError
+
 	CreateRoleOptions            = authorizer.CreateRoleOptions
 	UpdateRolePermissionsOptions = authorizer.UpdateRolePermissionsOptions
 	Role                         = authorizer.Role
 
-	Cursor = cursors.Cursor
+	Cursor                       = cursors.Cursor
+	GetReadCursorsForRoomOptions = cursors.GetReadCursorsForRoomOptions
+
+	UserPresence = presence.UserPresence
 
 	GetUsersOptions               = core.GetUsersOptions
 	CreateUserOptions             = core.CreateUserOptions
@@ -37,8 +87,11 @@ type (
 	NewInlinePart                 = core.NewInlinePart
 	NewURLPart                    = core.NewURLPart
 	NewAttachmentPart             = core.NewAttachmentPart
+	NewExistingAttachmentPart     = core.NewExistingAttachmentPart
 	GetRoomMessagesOptions        = core.GetRoomMessagesOptions
+	GetMessagesOptions            = core.GetMessagesOptions
 	DeleteMessageOptions          = core.DeleteMessageOptions
+	DeleteStatus                  = core.DeleteStatus
 	EditMessageOptions            = core.EditMessageOptions
 	EditSimpleMessageOptions      = core.EditSimpleMessageOptions
 	EditMultipartMessageOptions   = core.EditMultipartMessageOptions
@@ -49,8 +102,20 @@ type (
 	RoomWithoutMembers            = core.RoomWithoutMembers
 	Message                       = core.Message
 	MultipartMessage              = core.MultipartMessage
+	ReactionSummary               = core.ReactionSummary
 	Part                          = core.Part
 	Attachment                    = core.Attachment
+	RolloutOptions                = core.RolloutOptions
+	RolloutDivergence             = core.RolloutDivergence
 )
 
+// Errors returned by VerifyToken.
+var (
+	ErrInvalidToken = authenticator.ErrInvalidToken
+	ErrTokenExpired = authenticator.ErrTokenExpired
+)
+
+// Permissions lists every permission that can be granted by a role.
+var Permissions = authorizer.Permissions
+
 var ExplicitlyResetPushNotificationTitleOverride = &core.ExplicitlyResetPushNotificationTitleOverride
@@ -0,0 +1,710 @@
+package chatkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/core"
+	"github.com/pusher/pusher-platform-go/auth"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// API is the set of methods of Client, for code that makes calls to Chatkit to depend on
+// instead, so that they can be mocked in its tests.
+// It keeps the flat methods of Client, which are deprecated in favour of its sub-clients, such as
+// Client.Rooms, so that existing mocks keep working. The sub-clients are returned as interfaces,
+// such as RoomsAPI, so that code using them can be mocked as well.
+type API interface {
+	// Sub-clients
+	Users() UsersAPI
+	Rooms() RoomsAPI
+	Messages() MessagesAPI
+	Cursors() CursorsAPI
+	Roles() RolesAPI
+	Auth() AuthAPI
+
+	// Cursors
+	GetUserReadCursors(ctx context.Context, userID string) ([]Cursor, error)
+	SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error
+	GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error)
+	GetReadCursorsForRoomPage(
+		ctx context.Context,
+		roomID string,
+		options GetReadCursorsForRoomOptions,
+	) ([]Cursor, error)
+	GetReadCursor(ctx context.Context, userID string, roomID string) (Cursor, error)
+	DeleteReadCursor(ctx context.Context, userID string, roomID string) error
+	GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]Cursor, error)
+	SetCursor(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error
+	GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]Cursor, error)
+	GetCursor(ctx context.Context, cursorType uint, userID string, roomID string) (Cursor, error)
+	DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error
+	CursorsRequest(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*http.Response, error)
+	CursorsSubscribe(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*RawEventStream, error)
+
+	// Presence
+	GetUserPresence(ctx context.Context, userID string) (UserPresence, error)
+	GetUsersPresence(ctx context.Context, userIDs []string) ([]UserPresence, error)
+	PresenceRequest(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*http.Response, error)
+	PresenceSubscribe(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*RawEventStream, error)
+
+	// Roles and permissions
+	GetRoles(ctx context.Context) ([]Role, error)
+	CreateGlobalRole(ctx context.Context, options CreateRoleOptions) error
+	CreateRoomRole(ctx context.Context, options CreateRoleOptions) error
+	DeleteGlobalRole(ctx context.Context, roleName string) error
+	DeleteRoomRole(ctx context.Context, roleName string) error
+	GetPermissionsForGlobalRole(
+		ctx context.Context,
+		roleName string,
+	) ([]string, error)
+	GetPermissionsForRoomRole(
+		ctx context.Context,
+		roleName string,
+	) ([]string, error)
+	UpdatePermissionsForGlobalRole(
+		ctx context.Context,
+		roleName string,
+		options UpdateRolePermissionsOptions,
+	) error
+	UpdatePermissionsForRoomRole(
+		ctx context.Context,
+		roleName string,
+		options UpdateRolePermissionsOptions,
+	) error
+	GetUserRoles(ctx context.Context, userID string) ([]Role, error)
+	AssignGlobalRoleToUser(ctx context.Context, userID string, roleName string) error
+	AssignRoomRoleToUser(
+		ctx context.Context,
+		userID string,
+		roomID string,
+		roleName string,
+	) error
+	RemoveGlobalRoleForUser(ctx context.Context, userID string) error
+	RemoveRoomRoleForUser(ctx context.Context, userID string, roomID string) error
+	AuthorizerRequest(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*http.Response, error)
+	AuthorizerSubscribe(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*RawEventStream, error)
+
+	// Users
+	GetUser(ctx context.Context, userID string) (User, error)
+	GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error)
+	GetUsersByID(ctx context.Context, userIDs []string) ([]User, error)
+	CreateUser(ctx context.Context, options CreateUserOptions) error
+	CreateUserAndGet(ctx context.Context, options CreateUserOptions) (User, error)
+	CreateUsers(ctx context.Context, users []CreateUserOptions) error
+	UpdateUser(ctx context.Context, userID string, options UpdateUserOptions) error
+	DeleteUser(ctx context.Context, userID string) error
+
+	// Rooms
+	GetRoom(ctx context.Context, roomID string) (Room, error)
+	GetRooms(ctx context.Context, options GetRoomsOptions) ([]core.RoomWithoutMembers, error)
+	GetUserRooms(ctx context.Context, userID string) ([]Room, error)
+	GetUserJoinableRooms(ctx context.Context, userID string) ([]Room, error)
+	CreateRoom(ctx context.Context, options CreateRoomOptions) (Room, error)
+	UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) error
+	UpdateRoomAndGet(ctx context.Context, roomID string, options UpdateRoomOptions) (Room, error)
+	DeleteRoom(ctx context.Context, roomID string) error
+	AsyncDeleteRoom(ctx context.Context, roomID string) (string, error)
+	GetDeleteStatus(ctx context.Context, jobID string) (DeleteStatus, error)
+	AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error
+	RemoveUsersFromRoom(ctx context.Context, roomID string, userIDs []string) error
+	JoinRoom(ctx context.Context, roomID string, userID string) (Room, error)
+	LeaveRoom(ctx context.Context, roomID string, userID string) error
+
+	// Messages
+	SendMessage(ctx context.Context, options SendMessageOptions) (uint, error)
+	SendMultipartMessage(
+		ctx context.Context,
+		options SendMultipartMessageOptions,
+	) (uint, error)
+	SendMessageAsService(
+		ctx context.Context,
+		options SendMultipartMessageOptions,
+	) (uint, error)
+	SendSimpleMessage(
+		ctx context.Context,
+		options SendSimpleMessageOptions,
+	) (uint, error)
+	GetRoomMessages(
+		ctx context.Context,
+		roomID string,
+		options GetRoomMessagesOptions,
+	) ([]Message, error)
+	FetchMultipartMessage(
+		ctx context.Context,
+		options FetchMultipartMessageOptions,
+	) (MultipartMessage, error)
+	FetchMultipartMessages(
+		ctx context.Context,
+		roomID string,
+		options GetRoomMessagesOptions,
+	) ([]MultipartMessage, error)
+	DeleteMessage(ctx context.Context, options DeleteMessageOptions) error
+	EditMessage(ctx context.Context, roomID string, messageID uint, options EditMessageOptions) error
+	EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options EditMultipartMessageOptions) error
+	EditSimpleMessage(ctx context.Context, roomID string, messageID uint, options EditSimpleMessageOptions) error
+
+	// Generic requests and subscriptions
+	CoreRequest(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*http.Response, error)
+	CoreSubscribe(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+	) (*RawEventStream, error)
+
+	// Tokens
+	Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error)
+	GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error)
+	GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error)
+	VerifyToken(ctx context.Context, tokenString string) (Claims, error)
+
+	// Attachments
+	RefreshAttachment(ctx context.Context, att Attachment) (Attachment, error)
+	DownloadAttachment(ctx context.Context, att Attachment, w io.Writer) error
+
+	// Read cursors
+	GetUnreadCounts(ctx context.Context, userID string) (map[string]UnreadCount, error)
+	IterateRoomReadCursors(
+		ctx context.Context,
+		roomID string,
+		options IterateRoomReadCursorsOptions,
+	) *CursorsIterator
+	GetMessageReadBy(ctx context.Context, roomID string, messageID uint) (MessageReadBy, error)
+
+	// Debugging
+	SetDebugHTTP(w io.Writer)
+
+	// Export
+	ExportUsers(ctx context.Context, w io.Writer, format ExportFormat) error
+	UsersNDJSON(ctx context.Context, w io.Writer) error
+	RoomsNDJSON(ctx context.Context, w io.Writer, options IterateRoomsOptions) error
+	RoomMessagesNDJSON(ctx context.Context, w io.Writer, roomID string) error
+	ExportRoomMessages(
+		ctx context.Context,
+		roomID string,
+		w io.Writer,
+		format ExportFormat,
+		options ExportRoomMessagesOptions,
+	) error
+	RolesNDJSON(ctx context.Context, w io.Writer) error
+	RoomReadCursorsNDJSON(ctx context.Context, w io.Writer, roomID string) error
+	UserReadCursorsNDJSON(ctx context.Context, w io.Writer, userID string) error
+
+	// Message history
+	IterateRoomMessages(
+		ctx context.Context,
+		roomID string,
+		options IterateRoomMessagesOptions,
+	) *MessageIterator
+	GetMessages(ctx context.Context, roomID string, options GetMessagesOptions) ([]Message, error)
+	FetchLatestMessagesForRooms(
+		ctx context.Context,
+		roomIDs []string,
+		limit uint,
+	) (map[string][]MultipartMessage, error)
+	DeleteMessages(
+		ctx context.Context,
+		roomID string,
+		messageIDs []uint,
+		options BatchOptions,
+	) *BatchResult
+	SendMessageAndGet(ctx context.Context, options SendMessageOptions) (Message, error)
+	SendMultipartMessageAndGet(
+		ctx context.Context,
+		options SendMultipartMessageOptions,
+	) (MultipartMessage, error)
+	SendSimpleMessageAndGet(
+		ctx context.Context,
+		options SendSimpleMessageOptions,
+	) (MultipartMessage, error)
+
+	// Middleware
+	AuthMiddleware(next http.Handler, options VerifierOptions) http.Handler
+
+	// Pinned messages
+	PinMessage(ctx context.Context, roomID string, messageID uint) error
+	UnpinMessage(ctx context.Context, roomID string, messageID uint) error
+	GetPinnedMessages(ctx context.Context, roomID string) ([]MultipartMessage, error)
+
+	// Reactions
+	AddReaction(
+		ctx context.Context,
+		roomID string,
+		messageID uint,
+		userID string,
+		reaction string,
+	) error
+	RemoveReaction(
+		ctx context.Context,
+		roomID string,
+		messageID uint,
+		userID string,
+		reaction string,
+	) error
+
+	// Redaction
+	RedactMessage(
+		ctx context.Context,
+		roomID string,
+		messageID uint,
+		replacementText string,
+	) error
+
+	// Refresh tokens
+	IssueRefreshToken(ctx context.Context, userID string) (string, error)
+	RefreshAccessToken(
+		ctx context.Context,
+		refreshToken string,
+		options auth.Options,
+	) (TokenResponse, error)
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+
+	// Token revocation
+	RevokeToken(ctx context.Context, tokenString string) error
+	RevokeTokensForUser(ctx context.Context, userID string) error
+
+	// Role assignments
+	CreateDefaultRoles(ctx context.Context) error
+	GetRole(ctx context.Context, name string, scope string) (Role, error)
+	UpsertGlobalRole(ctx context.Context, options CreateRoleOptions) error
+	UpsertRoomRole(ctx context.Context, options CreateRoleOptions) error
+	GetEffectivePermissions(ctx context.Context, userID string, roomID string) ([]string, error)
+	AssignGlobalRoleToUsers(
+		ctx context.Context,
+		userIDs []string,
+		roleName string,
+		options BatchOptions,
+	) *BatchResult
+	AssignRoomRoleToUsers(
+		ctx context.Context,
+		userIDs []string,
+		roomID string,
+		roleName string,
+		options BatchOptions,
+	) *BatchResult
+	GetUsersWithRole(
+		ctx context.Context,
+		roleName string,
+		scope string,
+		options GetUsersWithRoleOptions,
+	) ([]RoleAssignment, error)
+	ListRoleAssignments(
+		ctx context.Context,
+		options ListRoleAssignmentsOptions,
+	) *RoleAssignmentsIterator
+
+	// Roles configuration
+	ApplyRolesConfig(
+		ctx context.Context,
+		config RolesConfig,
+		options ApplyRolesConfigOptions,
+	) ([]RoleChange, error)
+	ExportRoles(ctx context.Context) (RolesConfig, error)
+	ImportRoles(
+		ctx context.Context,
+		config RolesConfig,
+		options ImportRolesOptions,
+	) ([]RoleChange, error)
+
+	// Room listing
+	TransferRoomOwnership(
+		ctx context.Context,
+		roomID string,
+		newOwnerID string,
+		options TransferRoomOwnershipOptions,
+	) error
+	IterateRooms(ctx context.Context, options IterateRoomsOptions) *RoomsIterator
+	CreateDirectRoom(
+		ctx context.Context,
+		userA string,
+		userB string,
+		options CreateDirectRoomOptions,
+	) (Room, error)
+	GetRoomsByID(ctx context.Context, roomIDs []string) ([]Room, error)
+	GetRoomCounts(ctx context.Context, roomID string) (RoomCounts, error)
+	WaitForDelete(ctx context.Context, jobID string, interval time.Duration) (DeleteStatus, error)
+
+	// Scoped tokens
+	GenerateScopedToken(ctx context.Context, options ScopedTokenOptions) (auth.TokenWithExpiry, error)
+	GenerateReadOnlyToken(ctx context.Context, options ReadOnlyTokenOptions) (auth.TokenWithExpiry, error)
+
+	// Search
+	SearchRoomMessages(
+		ctx context.Context,
+		roomID string,
+		query string,
+		options SearchRoomMessagesOptions,
+	) ([]MessageSearchResult, error)
+
+	// Stats
+	Stats() Stats
+
+	// Subscription manager
+	NewSubscriptionManager(options SubscriptionManagerOptions) *SubscriptionManager
+
+	// Subscriptions
+	SubscribeToRoomMessages(
+		ctx context.Context,
+		roomID string,
+		options SubscribeToRoomMessagesOptions,
+	) (<-chan MultipartMessage, error)
+	SubscribeToUserEvents(
+		ctx context.Context,
+		userID string,
+		options SubscriptionOptions,
+	) (<-chan UserSubscriptionEvent, error)
+	SubscribeToRoomMemberships(
+		ctx context.Context,
+		roomID string,
+		options SubscriptionOptions,
+	) (<-chan MembershipEvent, error)
+	SubscribeToPresence(
+		ctx context.Context,
+		userIDs []string,
+		options SubscriptionOptions,
+	) (<-chan UserPresence, error)
+
+	// System messages
+	SendSystemMessage(ctx context.Context, options SendSystemMessageOptions) (uint, error)
+
+	// Threads
+	FetchThread(
+		ctx context.Context,
+		roomID string,
+		parentID uint,
+		options FetchThreadOptions,
+	) ([]MultipartMessage, error)
+
+	// Token provider
+	TokenProviderHandler(options TokenProviderOptions) http.Handler
+
+	// User listing
+	IterateUsers(ctx context.Context, options IterateUsersOptions) *UsersIterator
+	SearchUsers(
+		ctx context.Context,
+		query string,
+		options SearchUsersOptions,
+	) *UsersIterator
+	UpdateUsers(
+		ctx context.Context,
+		updates map[string]UpdateUserOptions,
+		options UpdateUsersOptions,
+	) *BatchResult
+	RenameUser(ctx context.Context, userID string, options RenameUserOptions) error
+}
+
+var _ API = (*Client)(nil)
+
+// UsersAPI is the set of methods of the users sub-client, Client.Users.
+type UsersAPI interface {
+	GetUser(ctx context.Context, userID string) (User, error)
+	GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error)
+	CreateUser(ctx context.Context, options CreateUserOptions) error
+	CreateUserAndGet(ctx context.Context, options CreateUserOptions) (User, error)
+	CreateUsers(ctx context.Context, users []CreateUserOptions) error
+	CreateUsersInBatches(
+		ctx context.Context,
+		users []CreateUserOptions,
+		options BatchOptions,
+	) *BatchResult
+	UpdateUser(ctx context.Context, userID string, options UpdateUserOptions) error
+	DeleteUser(ctx context.Context, userID string) error
+	IterateUsers(ctx context.Context, options IterateUsersOptions) *UsersIterator
+	SearchUsers(
+		ctx context.Context,
+		query string,
+		options SearchUsersOptions,
+	) *UsersIterator
+	GetUsersByID(ctx context.Context, userIDs []string) ([]User, error)
+	UpdateUsers(
+		ctx context.Context,
+		updates map[string]UpdateUserOptions,
+		options UpdateUsersOptions,
+	) *BatchResult
+	RenameUser(ctx context.Context, userID string, options RenameUserOptions) error
+}
+
+// RoomsAPI is the set of methods of the rooms sub-client, Client.Rooms.
+type RoomsAPI interface {
+	GetRoom(ctx context.Context, roomID string) (Room, error)
+	GetRooms(ctx context.Context, options GetRoomsOptions) ([]core.RoomWithoutMembers, error)
+	GetUserRooms(ctx context.Context, userID string) ([]Room, error)
+	GetUserJoinableRooms(ctx context.Context, userID string) ([]Room, error)
+	CreateRoom(ctx context.Context, options CreateRoomOptions) (Room, error)
+	UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) error
+	UpdateRoomAndGet(ctx context.Context, roomID string, options UpdateRoomOptions) (Room, error)
+	DeleteRoom(ctx context.Context, roomID string) error
+	AsyncDeleteRoom(ctx context.Context, roomID string) (string, error)
+	GetDeleteStatus(ctx context.Context, jobID string) (DeleteStatus, error)
+	AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error
+	RemoveUsersFromRoom(ctx context.Context, roomID string, userIDs []string) error
+	JoinRoom(ctx context.Context, roomID string, userID string) (Room, error)
+	LeaveRoom(ctx context.Context, roomID string, userID string) error
+	TransferRoomOwnership(
+		ctx context.Context,
+		roomID string,
+		newOwnerID string,
+		options TransferRoomOwnershipOptions,
+	) error
+	IterateRooms(ctx context.Context, options IterateRoomsOptions) *RoomsIterator
+	CreateDirectRoom(
+		ctx context.Context,
+		userA string,
+		userB string,
+		options CreateDirectRoomOptions,
+	) (Room, error)
+	GetRoomsByID(ctx context.Context, roomIDs []string) ([]Room, error)
+	GetRoomCounts(ctx context.Context, roomID string) (RoomCounts, error)
+	WaitForDelete(ctx context.Context, jobID string, interval time.Duration) (DeleteStatus, error)
+}
+
+// MessagesAPI is the set of methods of the messages sub-client, Client.Messages.
+type MessagesAPI interface {
+	SendMessage(ctx context.Context, options SendMessageOptions) (uint, error)
+	SendMultipartMessage(
+		ctx context.Context,
+		options SendMultipartMessageOptions,
+	) (uint, error)
+	SendMessageAsService(
+		ctx context.Context,
+		options SendMultipartMessageOptions,
+	) (uint, error)
+	SendSimpleMessage(
+		ctx context.Context,
+		options SendSimpleMessageOptions,
+	) (uint, error)
+	GetRoomMessages(
+		ctx context.Context,
+		roomID string,
+		options GetRoomMessagesOptions,
+	) ([]Message, error)
+	FetchMultipartMessage(
+		ctx context.Context,
+		options FetchMultipartMessageOptions,
+	) (MultipartMessage, error)
+	FetchMultipartMessages(
+		ctx context.Context,
+		roomID string,
+		options GetRoomMessagesOptions,
+	) ([]MultipartMessage, error)
+	DeleteMessage(ctx context.Context, options DeleteMessageOptions) error
+	EditMessage(ctx context.Context, roomID string, messageID uint, options EditMessageOptions) error
+	EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options EditMultipartMessageOptions) error
+	EditSimpleMessage(ctx context.Context, roomID string, messageID uint, options EditSimpleMessageOptions) error
+	IterateRoomMessages(
+		ctx context.Context,
+		roomID string,
+		options IterateRoomMessagesOptions,
+	) *MessageIterator
+	GetMessages(ctx context.Context, roomID string, options GetMessagesOptions) ([]Message, error)
+	FetchLatestMessagesForRooms(
+		ctx context.Context,
+		roomIDs []string,
+		limit uint,
+	) (map[string][]MultipartMessage, error)
+	SendMessageAndGet(ctx context.Context, options SendMessageOptions) (Message, error)
+	SendMultipartMessageAndGet(
+		ctx context.Context,
+		options SendMultipartMessageOptions,
+	) (MultipartMessage, error)
+	SendSimpleMessageAndGet(
+		ctx context.Context,
+		options SendSimpleMessageOptions,
+	) (MultipartMessage, error)
+	DeleteMessages(
+		ctx context.Context,
+		roomID string,
+		messageIDs []uint,
+		options BatchOptions,
+	) *BatchResult
+	PinMessage(ctx context.Context, roomID string, messageID uint) error
+	UnpinMessage(ctx context.Context, roomID string, messageID uint) error
+	GetPinnedMessages(ctx context.Context, roomID string) ([]MultipartMessage, error)
+	AddReaction(
+		ctx context.Context,
+		roomID string,
+		messageID uint,
+		userID string,
+		reaction string,
+	) error
+	RemoveReaction(
+		ctx context.Context,
+		roomID string,
+		messageID uint,
+		userID string,
+		reaction string,
+	) error
+	RedactMessage(
+		ctx context.Context,
+		roomID string,
+		messageID uint,
+		replacementText string,
+	) error
+	SearchRoomMessages(
+		ctx context.Context,
+		roomID string,
+		query string,
+		options SearchRoomMessagesOptions,
+	) ([]MessageSearchResult, error)
+	SendSystemMessage(ctx context.Context, options SendSystemMessageOptions) (uint, error)
+	FetchThread(
+		ctx context.Context,
+		roomID string,
+		parentID uint,
+		options FetchThreadOptions,
+	) ([]MultipartMessage, error)
+}
+
+// CursorsAPI is the set of methods of the cursors sub-client, Client.Cursors.
+type CursorsAPI interface {
+	GetUserReadCursors(ctx context.Context, userID string) ([]Cursor, error)
+	SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error
+	GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error)
+	GetReadCursorsForRoomPage(
+		ctx context.Context,
+		roomID string,
+		options GetReadCursorsForRoomOptions,
+	) ([]Cursor, error)
+	GetReadCursor(ctx context.Context, userID string, roomID string) (Cursor, error)
+	DeleteReadCursor(ctx context.Context, userID string, roomID string) error
+	GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]Cursor, error)
+	SetCursor(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error
+	GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]Cursor, error)
+	GetCursor(ctx context.Context, cursorType uint, userID string, roomID string) (Cursor, error)
+	DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error
+	GetUnreadCounts(ctx context.Context, userID string) (map[string]UnreadCount, error)
+	IterateRoomReadCursors(
+		ctx context.Context,
+		roomID string,
+		options IterateRoomReadCursorsOptions,
+	) *CursorsIterator
+	GetMessageReadBy(ctx context.Context, roomID string, messageID uint) (MessageReadBy, error)
+}
+
+// RolesAPI is the set of methods of the roles sub-client, Client.Roles.
+type RolesAPI interface {
+	GetRoles(ctx context.Context) ([]Role, error)
+	CreateGlobalRole(ctx context.Context, options CreateRoleOptions) error
+	CreateRoomRole(ctx context.Context, options CreateRoleOptions) error
+	DeleteGlobalRole(ctx context.Context, roleName string) error
+	DeleteRoomRole(ctx context.Context, roleName string) error
+	GetPermissionsForGlobalRole(
+		ctx context.Context,
+		roleName string,
+	) ([]string, error)
+	GetPermissionsForRoomRole(
+		ctx context.Context,
+		roleName string,
+	) ([]string, error)
+	UpdatePermissionsForGlobalRole(
+		ctx context.Context,
+		roleName string,
+		options UpdateRolePermissionsOptions,
+	) error
+	UpdatePermissionsForRoomRole(
+		ctx context.Context,
+		roleName string,
+		options UpdateRolePermissionsOptions,
+	) error
+	GetUserRoles(ctx context.Context, userID string) ([]Role, error)
+	AssignGlobalRoleToUser(ctx context.Context, userID string, roleName string) error
+	AssignRoomRoleToUser(
+		ctx context.Context,
+		userID string,
+		roomID string,
+		roleName string,
+	) error
+	RemoveGlobalRoleForUser(ctx context.Context, userID string) error
+	RemoveRoomRoleForUser(ctx context.Context, userID string, roomID string) error
+	CreateDefaultRoles(ctx context.Context) error
+	GetRole(ctx context.Context, name string, scope string) (Role, error)
+	UpsertGlobalRole(ctx context.Context, options CreateRoleOptions) error
+	UpsertRoomRole(ctx context.Context, options CreateRoleOptions) error
+	GetEffectivePermissions(ctx context.Context, userID string, roomID string) ([]string, error)
+	AssignGlobalRoleToUsers(
+		ctx context.Context,
+		userIDs []string,
+		roleName string,
+		options BatchOptions,
+	) *BatchResult
+	AssignRoomRoleToUsers(
+		ctx context.Context,
+		userIDs []string,
+		roomID string,
+		roleName string,
+		options BatchOptions,
+	) *BatchResult
+	GetUsersWithRole(
+		ctx context.Context,
+		roleName string,
+		scope string,
+		options GetUsersWithRoleOptions,
+	) ([]RoleAssignment, error)
+	ListRoleAssignments(
+		ctx context.Context,
+		options ListRoleAssignmentsOptions,
+	) *RoleAssignmentsIterator
+	ApplyRolesConfig(
+		ctx context.Context,
+		config RolesConfig,
+		options ApplyRolesConfigOptions,
+	) ([]RoleChange, error)
+	ExportRoles(ctx context.Context) (RolesConfig, error)
+	ImportRoles(
+		ctx context.Context,
+		config RolesConfig,
+		options ImportRolesOptions,
+	) ([]RoleChange, error)
+}
+
+// AuthAPI is the set of methods of the auth sub-client, Client.Auth.
+type AuthAPI interface {
+	Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error)
+	GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error)
+	GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error)
+	VerifyToken(ctx context.Context, tokenString string) (Claims, error)
+	AuthMiddleware(next http.Handler, options VerifierOptions) http.Handler
+	IssueRefreshToken(ctx context.Context, userID string) (string, error)
+	RefreshAccessToken(
+		ctx context.Context,
+		refreshToken string,
+		options auth.Options,
+	) (TokenResponse, error)
+	RevokeRefreshToken(ctx context.Context, refreshToken string) error
+	RevokeToken(ctx context.Context, tokenString string) error
+	RevokeTokensForUser(ctx context.Context, userID string) error
+	GenerateScopedToken(ctx context.Context, options ScopedTokenOptions) (auth.TokenWithExpiry, error)
+	GenerateReadOnlyToken(ctx context.Context, options ReadOnlyTokenOptions) (auth.TokenWithExpiry, error)
+	TokenProviderHandler(options TokenProviderOptions) http.Handler
+}
+
+var (
+	_ UsersAPI    = UsersClient{}
+	_ RoomsAPI    = RoomsClient{}
+	_ MessagesAPI = MessagesClient{}
+	_ CursorsAPI  = CursorsClient{}
+	_ RolesAPI    = RolesClient{}
+	_ AuthAPI     = AuthClient{}
+)
@@ -0,0 +1,104 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// onboard is application code written against API, as services using the SDK are: it creates a
+// user, adds them to the lobby and welcomes them there.
+func onboard(ctx context.Context, api API, userID string, name string) error {
+	if err := api.Users().CreateUser(ctx, CreateUserOptions{ID: userID, Name: name}); err != nil {
+		return err
+	}
+
+	if err := api.Rooms().AddUsersToRoom(ctx, "lobby", []string{userID}); err != nil {
+		return err
+	}
+
+	_, err := api.Messages().SendSimpleMessage(ctx, SendSimpleMessageOptions{
+		RoomID:   "lobby",
+		SenderID: "bot",
+		Text:     "Welcome, " + name + "!",
+	})
+	return err
+}
+
+// fakeAPI mocks the parts of API onboard uses. Embedding the interfaces makes the fakes satisfy
+// them, while calls to any other method panic.
+type fakeAPI struct {
+	API
+	users    fakeUsers
+	rooms    fakeRooms
+	messages fakeMessages
+}
+
+func (f *fakeAPI) Users() UsersAPI       { return &f.users }
+func (f *fakeAPI) Rooms() RoomsAPI       { return &f.rooms }
+func (f *fakeAPI) Messages() MessagesAPI { return &f.messages }
+
+type fakeUsers struct {
+	UsersAPI
+	err     error
+	created []CreateUserOptions
+}
+
+func (u *fakeUsers) CreateUser(ctx context.Context, options CreateUserOptions) error {
+	u.created = append(u.created, options)
+	return u.err
+}
+
+type fakeRooms struct {
+	RoomsAPI
+	added map[string][]string
+}
+
+func (r *fakeRooms) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
+	if r.added == nil {
+		r.added = map[string][]string{}
+	}
+	r.added[roomID] = append(r.added[roomID], userIDs...)
+	return nil
+}
+
+type fakeMessages struct {
+	MessagesAPI
+	sent []SendSimpleMessageOptions
+}
+
+func (m *fakeMessages) SendSimpleMessage(ctx context.Context, options SendSimpleMessageOptions) (uint, error) {
+	m.sent = append(m.sent, options)
+	return uint(len(m.sent)), nil
+}
+
+func TestOnboardWithMockedAPI(t *testing.T) {
+	api := &fakeAPI{}
+
+	if err := onboard(context.Background(), api, "alice", "Alice"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(api.users.created) != 1 || api.users.created[0].ID != "alice" {
+		t.Errorf("Expected alice to be created, got %+v", api.users.created)
+	}
+	if added := api.rooms.added["lobby"]; len(added) != 1 || added[0] != "alice" {
+		t.Errorf("Expected alice to be added to the lobby, got %v", api.rooms.added)
+	}
+	if len(api.messages.sent) != 1 || api.messages.sent[0].Text != "Welcome, Alice!" {
+		t.Errorf("Expected alice to be welcomed, got %+v", api.messages.sent)
+	}
+}
+
+func TestOnboardStopsWhenUserCreationFails(t *testing.T) {
+	createErr := errors.New("user already exists")
+	api := &fakeAPI{users: fakeUsers{err: createErr}}
+
+	if err := onboard(context.Background(), api, "alice", "Alice"); err != createErr {
+		t.Errorf("Expected the creation error, got %v", err)
+	}
+
+	if len(api.rooms.added) != 0 || len(api.messages.sent) != 0 {
+		t.Errorf("Expected nothing else to be done, got %v and %+v", api.rooms.added, api.messages.sent)
+	}
+}
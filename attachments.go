@@ -0,0 +1,102 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pusher/pusher-platform-go/auth"
+)
+
+// attachmentExpiryMargin is how long before an attachment's download URL expires that it is
+// refreshed rather than used.
+const attachmentExpiryMargin = 30 * time.Second
+
+// RefreshAttachment returns att with a new download URL and expiration, obtained from its
+// refresh URL. Download URLs are only valid for a limited time, after which they must be
+// refreshed.
+func (c *Client) RefreshAttachment(ctx context.Context, att Attachment) (Attachment, error) {
+	if att.RefreshURL == "" {
+		return Attachment{}, errors.New("You must provide an attachment with a refresh URL")
+	}
+
+	token, err := c.Auth().GenerateSUToken(auth.Options{})
+	if err != nil {
+		return Attachment{}, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, att.RefreshURL, nil)
+	if err != nil {
+		return Attachment{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+
+	res, err := c.httpClient.Do(req.WithContext(ctx))
+	if res != nil {
+		defer res.Body.Close()
+	}
+	if err != nil {
+		return Attachment{}, err
+	}
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return Attachment{}, fmt.Errorf("Failed to refresh attachment %s: unexpected status: %v", att.ID, res.Status)
+	}
+
+	// Fields missing from the response keep their previous values.
+	refreshed := att
+	if err := json.NewDecoder(res.Body).Decode(&refreshed); err != nil {
+		return Attachment{}, fmt.Errorf("Failed to decode response body: %s", err.Error())
+	}
+
+	return refreshed, nil
+}
+
+// DownloadAttachment streams the contents of an attachment to w.
+// If the attachment's download URL has expired, or is rejected, it is refreshed first.
+func (c *Client) DownloadAttachment(ctx context.Context, att Attachment, w io.Writer) error {
+	refreshed := false
+	if !att.Expiration.IsZero() && time.Now().Add(attachmentExpiryMargin).After(att.Expiration) {
+		var err error
+		att, err = c.RefreshAttachment(ctx, att)
+		if err != nil {
+			return err
+		}
+		refreshed = true
+	}
+
+	for {
+		req, err := http.NewRequest(http.MethodGet, att.DownloadURL, nil)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.httpClient.Do(req.WithContext(ctx))
+		if err != nil {
+			return err
+		}
+
+		if (res.StatusCode == http.StatusForbidden || res.StatusCode == http.StatusUnauthorized) && !refreshed {
+			res.Body.Close()
+
+			att, err = c.RefreshAttachment(ctx, att)
+			if err != nil {
+				return err
+			}
+			refreshed = true
+			continue
+		}
+
+		if res.StatusCode < 200 || res.StatusCode >= 300 {
+			res.Body.Close()
+			return fmt.Errorf("Failed to download attachment %s: unexpected status: %v", att.ID, res.Status)
+		}
+
+		_, err = io.Copy(w, res.Body)
+		res.Body.Close()
+		return err
+	}
+}
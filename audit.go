@@ -0,0 +1,108 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// actorContextKey is the key the actor of calls is stored under in a context.
+type actorContextKey struct{}
+
+// WithActor returns a context that attributes the calls made with it to actor, e.g. the ID of the
+// administrator on whose behalf the application made them, in AuditRecords.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor set with WithActor.
+func ActorFromContext(ctx context.Context) (string, bool) {
+	actor, ok := ctx.Value(actorContextKey{}).(string)
+	return actor, ok
+}
+
+// AuditRecord describes a request made to Chatkit with a token with the `su` claim, which
+// bypasses permission checks.
+type AuditRecord struct {
+	Time      time.Time
+	Actor     string // Set with WithActor, or empty
+	Operation string // Method and route of the request, e.g. "DELETE /rooms/{room_id}"
+	Service   string // Name of the service, e.g. chatkit_authorizer
+	Path      string // Path of the request, with tokens redacted
+	// Resources the request targets, e.g. room_id or user_id, from its route.
+	Resources map[string]string
+	// ID of the user the token was issued to, for requests made on behalf of a user.
+	UserID string
+	Status int // Status of the response, or 0 if the request failed without one
+	Err    error
+}
+
+// auditSURequests is an interceptor that calls audit once each request made with a token with the
+// `su` claim has completed.
+func auditSURequests(audit func(ctx context.Context, record AuditRecord)) common.Interceptor {
+	return func(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+		next common.Invoker,
+	) (*http.Response, error) {
+		claims, su := suTokenClaims(options.Jwt)
+		if !su {
+			return next(ctx, options)
+		}
+
+		start := time.Now()
+		response, err := next(ctx, options)
+
+		r := parseRoute(options.Path)
+		actor, _ := ActorFromContext(ctx)
+		audit(ctx, AuditRecord{
+			Time:      start,
+			Actor:     actor,
+			Operation: options.Method + " " + r.Template,
+			Service:   r.Service,
+			Path:      redactedPath(options),
+			Resources: r.Params,
+			UserID:    claims.Subject,
+			Status:    responseStatus(response, err),
+			Err:       err,
+		})
+
+		return response, err
+	}
+}
+
+type suClaims struct {
+	SU      bool   `json:"su"`
+	Subject string `json:"sub"`
+}
+
+// suTokenClaims returns the claims of a token, and whether it has the `su` claim. The token is
+// not verified, as it was generated by the client.
+func suTokenClaims(token *string) (suClaims, bool) {
+	if token == nil {
+		return suClaims{}, false
+	}
+
+	segments := strings.Split(*token, ".")
+	if len(segments) != 3 {
+		return suClaims{}, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(segments[1])
+	if err != nil {
+		return suClaims{}, false
+	}
+
+	var claims suClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return suClaims{}, false
+	}
+
+	return claims, claims.SU
+}
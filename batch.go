@@ -0,0 +1,382 @@
+package chatkit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// defaultBatchConcurrency is the number of requests bulk helpers have in flight at once
+// when no concurrency is configured.
+const defaultBatchConcurrency = 10
+
+// BatchError is returned by bulk helpers when some of the operations they performed failed.
+// Operations that are not present in Errors succeeded.
+type BatchError struct {
+	Errors map[string]error // Errors keyed by the ID of the item the operation was for
+}
+
+func (e *BatchError) Error() string {
+	ids := make([]string, 0, len(e.Errors))
+	for id := range e.Errors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	failures := make([]string, len(ids))
+	for i, id := range ids {
+		failures[i] = fmt.Sprintf("%s: %v", id, e.Errors[id])
+	}
+
+	return fmt.Sprintf("%d operation(s) failed: %s", len(ids), strings.Join(failures, "; "))
+}
+
+// Defaults of BatchOptions.
+const (
+	defaultBatchMaxAttempts  = 3
+	defaultBatchRetryBackoff = 100 * time.Millisecond
+)
+
+// BatchOptions contains parameters to configure a BatchExecutor.
+type BatchOptions struct {
+	// Maximum number of operations in flight at once. Defaults to 10, or for the bulk helpers of
+	// a Client to its batch concurrency.
+	Concurrency int
+	// Number of times an operation is attempted before it fails. Only operations that failed
+	// transiently, by being rate limited, failing with a server error or failing to connect, are
+	// retried. Defaults to 3, or to 1 for bulk helpers whose operations aren't idempotent.
+	MaxAttempts int
+	// Delay before the first retry of an operation, doubled for each subsequent retry.
+	// Defaults to 100ms.
+	RetryBackoff time.Duration
+}
+
+// BatchResult reports the outcome of a batch of operations, per item.
+type BatchResult struct {
+	Succeeded []string         // IDs of the items the operation succeeded for, in the order given
+	Failed    map[string]error // Errors keyed by the ID of the item the operation failed for
+}
+
+// Err returns a *BatchError describing the operations that failed, or nil if they all
+// succeeded.
+func (r *BatchResult) Err() error {
+	if len(r.Failed) == 0 {
+		return nil
+	}
+
+	return &BatchError{Errors: r.Failed}
+}
+
+// BatchExecutor performs an operation for each of many items, such as users or messages, with a
+// bounded number in flight at once. The bulk helpers of a Client are built on it, and it can be
+// used to perform other operations in bulk:
+//
+//	executor := chatkit.NewBatchExecutor(chatkit.BatchOptions{Concurrency: 5})
+//	result := executor.Execute(ctx, userIDs, func(ctx context.Context, userID string) error {
+//		return client.DeleteUser(ctx, userID)
+//	})
+//	if err := result.Err(); err != nil {
+//		return err
+//	}
+//
+// Operations that failed transiently are retried with exponential backoff, so they must be
+// idempotent; set MaxAttempts to 1 for operations that aren't. When an operation is rate limited,
+// no operation is attempted until the delay requested in the Retry-After header of the response
+// has passed, so that the whole batch backs off rather than each operation in turn.
+// A BatchExecutor is safe for concurrent use, and batches executed concurrently back off
+// together.
+type BatchExecutor struct {
+	options BatchOptions
+
+	mu          sync.Mutex
+	pausedUntil time.Time
+}
+
+// NewBatchExecutor returns a BatchExecutor configured with options.
+func NewBatchExecutor(options BatchOptions) *BatchExecutor {
+	if options.Concurrency <= 0 {
+		options.Concurrency = defaultBatchConcurrency
+	}
+
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultBatchMaxAttempts
+	}
+
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaultBatchRetryBackoff
+	}
+
+	return &BatchExecutor{options: options}
+}
+
+// newBatchExecutor returns a BatchExecutor for a bulk helper, with the client's batch
+// concurrency unless options set one.
+func (c *Client) newBatchExecutor(options BatchOptions) *BatchExecutor {
+	options.Concurrency = c.concurrencyFor(options.Concurrency)
+	return NewBatchExecutor(options)
+}
+
+// Execute calls fn for every id and reports the outcome of each call. Once ctx is done, the
+// remaining calls fail with its error.
+func (e *BatchExecutor) Execute(
+	ctx context.Context,
+	ids []string,
+	fn func(ctx context.Context, id string) error,
+) *BatchResult {
+	var (
+		mu     sync.Mutex
+		errs   = map[string]error{}
+		wg     sync.WaitGroup
+		idsCh  = make(chan string)
+		worker = func() {
+			defer wg.Done()
+			for id := range idsCh {
+				if err := e.attempt(ctx, id, fn); err != nil {
+					mu.Lock()
+					errs[id] = err
+					mu.Unlock()
+				}
+			}
+		}
+	)
+
+	for i := 0; i < e.options.Concurrency && i < len(ids); i++ {
+		wg.Add(1)
+		go worker()
+	}
+
+	for _, id := range ids {
+		idsCh <- id
+	}
+	close(idsCh)
+	wg.Wait()
+
+	result := &BatchResult{Succeeded: []string{}, Failed: errs}
+	for _, id := range ids {
+		if _, failed := errs[id]; !failed {
+			result.Succeeded = append(result.Succeeded, id)
+		}
+	}
+
+	return result
+}
+
+// attempt calls fn for id until it succeeds, fails with an error that isn't worth retrying, or
+// has been attempted MaxAttempts times.
+func (e *BatchExecutor) attempt(
+	ctx context.Context,
+	id string,
+	fn func(ctx context.Context, id string) error,
+) error {
+	var err error
+	for attempt := 0; attempt < e.options.MaxAttempts; attempt++ {
+		delay := time.Duration(0)
+		if attempt > 0 {
+			delay = e.options.RetryBackoff << uint(attempt-1)
+		}
+		if err := e.wait(ctx, delay); err != nil {
+			return err
+		}
+
+		err = fn(ctx, id)
+		if err == nil || !isRetryableBatchError(err) {
+			return err
+		}
+
+		if retryAfter, ok := rateLimitRetryAfter(err); ok {
+			e.pause(retryAfter)
+		}
+	}
+
+	return err
+}
+
+// wait waits for delay, and for the executor to no longer be paused.
+func (e *BatchExecutor) wait(ctx context.Context, delay time.Duration) error {
+	e.mu.Lock()
+	until := e.pausedUntil
+	e.mu.Unlock()
+
+	if paused := time.Until(until); paused > delay {
+		delay = paused
+	}
+
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// pause stops operations being attempted for d.
+func (e *BatchExecutor) pause(d time.Duration) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(e.pausedUntil) {
+		e.pausedUntil = until
+	}
+}
+
+// isRetryableBatchError reports whether an operation that failed with err may succeed if
+// attempted again. Only errors known to be transient are: rate limiting, server errors that
+// report the service being unavailable and failures to connect or get a response. Anything
+// else, such as the item not existing, invalid options or the context being done, isn't.
+func isRetryableBatchError(err error) bool {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return false
+	}
+
+	switch err := err.(type) {
+	case *platformclient.ErrorResponse:
+		switch err.Status {
+		case http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	case *url.Error:
+		return err.Err != context.Canceled && err.Err != context.DeadlineExceeded
+	case net.Error:
+		return err.Timeout() || err.Temporary()
+	default:
+		return false
+	}
+}
+
+// rateLimitRetryAfter returns how long to wait before retrying, as requested by a rate limited
+// response, if err is one that requests it.
+func rateLimitRetryAfter(err error) (time.Duration, bool) {
+	errorResponse, ok := err.(*platformclient.ErrorResponse)
+	if !ok || errorResponse.Status != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	seconds, parseErr := strconv.Atoi(errorResponse.Headers.Get("Retry-After"))
+	if parseErr != nil || seconds < 0 {
+		return 0, false
+	}
+
+	return time.Duration(seconds) * time.Second, true
+}
+
+// concurrencyFor returns the concurrency a bulk operation should use, preferring the one
+// passed to the individual call over the one the client was configured with.
+func (c *Client) concurrencyFor(override int) int {
+	if override > 0 {
+		return override
+	}
+
+	return c.batchConcurrency
+}
+
+// concurrencyForLookups returns the concurrency lookups of many resources by ID should use.
+func (c *Client) concurrencyForLookups() int {
+	if c.lookupConcurrency > 0 {
+		return c.lookupConcurrency
+	}
+
+	return c.concurrencyFor(0)
+}
+
+// chunkStrings splits values into consecutive chunks of at most size values.
+func chunkStrings(values []string, size int) [][]string {
+	chunks := make([][]string, 0, (len(values)+size-1)/size)
+	for size < len(values) {
+		values, chunks = values[size:], append(chunks, values[:size])
+	}
+
+	return append(chunks, values)
+}
+
+// forEachChunkConcurrently calls fn for every chunk of at most size ids using at most concurrency
+// goroutines. The error for a failed chunk is reported against each of the ids in it.
+func forEachChunkConcurrently(
+	ctx context.Context,
+	ids []string,
+	size int,
+	concurrency int,
+	fn func(ctx context.Context, chunk []string) error,
+) error {
+	chunks := chunkStrings(ids, size)
+	err := forEachIndexConcurrently(ctx, len(chunks), concurrency, func(ctx context.Context, i int) error {
+		return fn(ctx, chunks[i])
+	})
+
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		return err
+	}
+
+	idErrs := map[string]error{}
+	for key, chunkErr := range batchErr.Errors {
+		i, _ := strconv.Atoi(key)
+		for _, id := range chunks[i] {
+			idErrs[id] = chunkErr
+		}
+	}
+
+	return &BatchError{Errors: idErrs}
+}
+
+// forEachIndexConcurrently calls fn for every index from 0 to n-1 using at most concurrency
+// goroutines. Failures are reported in a *BatchError keyed by index.
+func forEachIndexConcurrently(
+	ctx context.Context,
+	n int,
+	concurrency int,
+	fn func(ctx context.Context, i int) error,
+) error {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	return forEachConcurrently(ctx, keys, concurrency, func(ctx context.Context, key string) error {
+		i, _ := strconv.Atoi(key)
+		return fn(ctx, i)
+	})
+}
+
+// forEachConcurrently calls fn for every id using at most concurrency goroutines, retrying
+// transient failures, so fn must be idempotent. It returns a *BatchError describing the calls
+// that failed, or nil if they all succeeded.
+func forEachConcurrently(
+	ctx context.Context,
+	ids []string,
+	concurrency int,
+	fn func(ctx context.Context, id string) error,
+) error {
+	return NewBatchExecutor(BatchOptions{Concurrency: concurrency}).Execute(ctx, ids, fn).Err()
+}
+
+// forEachConcurrentlyOnce is like forEachConcurrently, but calls fn only once for every id, for
+// operations that aren't idempotent.
+func forEachConcurrentlyOnce(
+	ctx context.Context,
+	ids []string,
+	concurrency int,
+	fn func(ctx context.Context, id string) error,
+) error {
+	return NewBatchExecutor(BatchOptions{Concurrency: concurrency, MaxAttempts: 1}).Execute(ctx, ids, fn).Err()
+}
@@ -0,0 +1,227 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+func TestBatchExecutorRetriesTransientErrors(t *testing.T) {
+	executor := NewBatchExecutor(BatchOptions{RetryBackoff: time.Millisecond})
+
+	var calls int32
+	result := executor.Execute(context.Background(), []string{"a"}, func(ctx context.Context, id string) error {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return &platformclient.ErrorResponse{Status: http.StatusServiceUnavailable}
+		}
+		return nil
+	})
+
+	if err := result.Err(); err != nil {
+		t.Fatalf("Expected the operation to succeed once retried, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestBatchExecutorDoesNotRetryPermanentErrors(t *testing.T) {
+	errs := map[string]error{
+		"validation": errors.New("You must provide the ID of the user"),
+		"not found":  &platformclient.ErrorResponse{Status: http.StatusNotFound},
+		"canceled":   context.Canceled,
+		"decoding":   fmt.Errorf("Failed to decode response body: unexpected EOF"),
+	}
+	ids := []string{}
+	for id := range errs {
+		ids = append(ids, id)
+	}
+
+	executor := NewBatchExecutor(BatchOptions{RetryBackoff: time.Millisecond})
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	result := executor.Execute(context.Background(), ids, func(ctx context.Context, id string) error {
+		mu.Lock()
+		calls[id]++
+		mu.Unlock()
+		return errs[id]
+	})
+
+	for id, err := range errs {
+		if calls[id] != 1 {
+			t.Errorf("Expected the %s error not to be retried, got %d attempts", id, calls[id])
+		}
+		if result.Failed[id] != err {
+			t.Errorf("Expected the %s error to be reported, got %v", id, result.Failed[id])
+		}
+	}
+	if len(result.Succeeded) != 0 {
+		t.Errorf("Expected no operations to succeed, got %v", result.Succeeded)
+	}
+}
+
+func TestBatchExecutorMaxAttempts(t *testing.T) {
+	executor := NewBatchExecutor(BatchOptions{MaxAttempts: 1})
+
+	var calls int32
+	result := executor.Execute(context.Background(), []string{"a"}, func(ctx context.Context, id string) error {
+		atomic.AddInt32(&calls, 1)
+		return &platformclient.ErrorResponse{Status: http.StatusGatewayTimeout}
+	})
+
+	if result.Err() == nil {
+		t.Error("Expected the operation to fail")
+	}
+	if calls != 1 {
+		t.Errorf("Expected a single attempt, got %d", calls)
+	}
+}
+
+func TestBatchResult(t *testing.T) {
+	executor := NewBatchExecutor(BatchOptions{Concurrency: 2})
+	failure := errors.New("failed")
+
+	result := executor.Execute(context.Background(), []string{"a", "b", "c", "d"}, func(ctx context.Context, id string) error {
+		if id == "b" {
+			return failure
+		}
+		return nil
+	})
+
+	if strings.Join(result.Succeeded, ",") != "a,c,d" {
+		t.Errorf("Expected a, c and d to succeed in order, got %v", result.Succeeded)
+	}
+
+	batchErr, ok := result.Err().(*BatchError)
+	if !ok || len(batchErr.Errors) != 1 || batchErr.Errors["b"] != failure {
+		t.Errorf("Expected a *BatchError for b, got %v", result.Err())
+	}
+}
+
+func TestIsRetryableBatchError(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{&platformclient.ErrorResponse{Status: http.StatusTooManyRequests}, true},
+		{&platformclient.ErrorResponse{Status: http.StatusInternalServerError}, true},
+		{&platformclient.ErrorResponse{Status: http.StatusBadGateway}, true},
+		{&platformclient.ErrorResponse{Status: http.StatusServiceUnavailable}, true},
+		{&platformclient.ErrorResponse{Status: http.StatusGatewayTimeout}, true},
+		{&platformclient.ErrorResponse{Status: http.StatusNotImplemented}, false},
+		{&platformclient.ErrorResponse{Status: http.StatusBadRequest}, false},
+		{&platformclient.ErrorResponse{Status: http.StatusConflict}, false},
+		{&url.Error{Op: "Post", URL: "https://example.com", Err: errors.New("connection refused")}, true},
+		{&url.Error{Op: "Post", URL: "https://example.com", Err: context.Canceled}, false},
+		{&url.Error{Op: "Post", URL: "https://example.com", Err: context.DeadlineExceeded}, false},
+		{context.Canceled, false},
+		{context.DeadlineExceeded, false},
+		{errors.New("You must provide the ID of the room"), false},
+		{&BatchError{Errors: map[string]error{}}, false},
+	}
+
+	for _, test := range tests {
+		if retryable := isRetryableBatchError(test.err); retryable != test.retryable {
+			t.Errorf("isRetryableBatchError(%v) = %v, expected %v", test.err, retryable, test.retryable)
+		}
+	}
+}
+
+func TestCreateUsersInBatches(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		batches [][]CreateUserOptions
+	)
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		var users struct {
+			Users []CreateUserOptions `json:"users"`
+		}
+		json.NewDecoder(r.Body).Decode(&users)
+
+		mu.Lock()
+		batches = append(batches, users.Users)
+		mu.Unlock()
+
+		if users.Users[0].ID == "user-100" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+	defer server.Close()
+
+	users := make([]CreateUserOptions, 150)
+	for i := range users {
+		users[i] = CreateUserOptions{ID: fmt.Sprintf("user-%d", i), Name: "User"}
+	}
+
+	result := client.Users().CreateUsersInBatches(context.Background(), users, BatchOptions{})
+
+	if len(batches) != 2 {
+		t.Fatalf("Expected 2 requests, since creating users isn't retried, got %d", len(batches))
+	}
+	if len(result.Succeeded) != 100 || result.Succeeded[0] != "user-0" || result.Succeeded[99] != "user-99" {
+		t.Errorf("Expected the first 100 users to be created, got %v", result.Succeeded)
+	}
+	if len(result.Failed) != 50 || result.Failed["user-149"] == nil {
+		t.Errorf("Expected the last 50 users to fail, got %v", result.Failed)
+	}
+
+	err := client.Users().CreateUsers(context.Background(), users[:100])
+	if err != nil {
+		t.Errorf("Expected a batch of 100 users to be created, got %v", err)
+	}
+
+	err = client.Users().CreateUsers(context.Background(), users[100:])
+	if errorResponse, ok := err.(*platformclient.ErrorResponse); !ok || errorResponse.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected the error of the single request, got %v", err)
+	}
+}
+
+func TestRenameUserAnnouncesOnce(t *testing.T) {
+	var announcements int32
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/alice"):
+			writeTestJSON(w, map[string]interface{}{"id": "alice", "name": "Alice"})
+		case r.Method == http.MethodPut && strings.HasSuffix(r.URL.Path, "/users/alice"):
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/users/alice/rooms"):
+			writeTestJSON(w, []map[string]interface{}{{"id": "room-1"}, {"id": "room-2"}})
+		case r.Method == http.MethodPost && strings.Contains(r.URL.Path, "/rooms/"):
+			// The message may have been sent, so sending it again could duplicate it.
+			atomic.AddInt32(&announcements, 1)
+			w.WriteHeader(http.StatusGatewayTimeout)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	defer server.Close()
+
+	name := "Alicia"
+	err := client.Users().RenameUser(context.Background(), "alice", RenameUserOptions{Name: &name, Announce: true})
+
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Errors) != 2 {
+		t.Errorf("Expected a *BatchError for both rooms, got %v", err)
+	}
+	if announcements != 2 {
+		t.Errorf("Expected one announcement per room, got %d", announcements)
+	}
+}
+
+func writeTestJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(body)
+}
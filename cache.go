@@ -0,0 +1,153 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+// Defaults of ResponseCacheOptions.
+const (
+	defaultResponseCacheTTL        = 30 * time.Second
+	defaultResponseCacheMaxEntries = 1000
+)
+
+// Prefix of the keys responses are cached under in the store.
+const responseCacheKeyPrefix = "response_cache/"
+
+// ResponseCacheOptions contains parameters to configure the response cache of a Client.
+type ResponseCacheOptions struct {
+	// How long responses are cached for. Defaults to 30s.
+	TTL time.Duration
+	// Store responses are cached in, e.g. a Redis store shared by the processes of a service.
+	// Defaults to an in-memory store of up to MaxEntries responses, evicting the least recently
+	// used.
+	Store Store
+	// Maximum number of responses the default store holds. Defaults to 1000.
+	MaxEntries int
+}
+
+// Number of stripes the generations of cache keys are tracked in.
+const responseCacheStripes = 64
+
+// responseCache is a read-through cache of responses. A nil *responseCache caches nothing.
+//
+// A response fetched before a call that changes it may arrive after the call has invalidated the
+// cache, so responses are only cached if the generation of their key, taken before fetching them,
+// is still current. Generations are tracked per process, so a stale response may still be cached
+// by a process sharing the Store with the one that invalidated it.
+type responseCache struct {
+	store   Store
+	ttl     time.Duration
+	stripes [responseCacheStripes]responseCacheStripe
+}
+
+// responseCacheStripe is the generation of the keys hashed to a stripe, incremented whenever one
+// of them is invalidated.
+type responseCacheStripe struct {
+	mu         sync.RWMutex
+	generation uint64
+}
+
+func newResponseCache(options ResponseCacheOptions) *responseCache {
+	if options.TTL <= 0 {
+		options.TTL = defaultResponseCacheTTL
+	}
+
+	if options.MaxEntries <= 0 {
+		options.MaxEntries = defaultResponseCacheMaxEntries
+	}
+
+	if options.Store == nil {
+		options.Store = newLRUStore(options.MaxEntries)
+	}
+
+	return &responseCache{store: options.Store, ttl: options.TTL}
+}
+
+func userCacheKey(userID string) string {
+	return responseCacheKeyPrefix + "users/" + userID
+}
+
+func roomCacheKey(roomID string) string {
+	return responseCacheKeyPrefix + "rooms/" + roomID
+}
+
+const rolesCacheKey = responseCacheKeyPrefix + "roles"
+
+// stripe returns the stripe the generation of key is tracked in.
+func (c *responseCache) stripe(key string) *responseCacheStripe {
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return &c.stripes[hash.Sum32()%responseCacheStripes]
+}
+
+// generation returns the current generation of key, to be passed to put the response fetched
+// afterwards.
+func (c *responseCache) generation(key string) uint64 {
+	if c == nil {
+		return 0
+	}
+
+	stripe := c.stripe(key)
+	stripe.mu.RLock()
+	defer stripe.mu.RUnlock()
+	return stripe.generation
+}
+
+// get decodes the response cached under key into dest, and reports whether there was one.
+// The cache is only an optimisation, so failing to read it is reported as a miss.
+func (c *responseCache) get(ctx context.Context, key string, dest interface{}) bool {
+	if c == nil {
+		return false
+	}
+
+	value, err := c.store.Get(ctx, key)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(value, dest) == nil
+}
+
+// put caches a response under key, unless key has been invalidated since generation, so the
+// response may be stale. Failing to cache it is ignored.
+func (c *responseCache) put(ctx context.Context, key string, response interface{}, generation uint64) {
+	if c == nil {
+		return
+	}
+
+	value, err := json.Marshal(response)
+	if err != nil {
+		return
+	}
+
+	// The stripe is held until the response is stored, so that an invalidation either happens
+	// first and is seen here, or deletes the response afterwards.
+	stripe := c.stripe(key)
+	stripe.mu.RLock()
+	defer stripe.mu.RUnlock()
+	if stripe.generation != generation {
+		return
+	}
+
+	c.store.Put(ctx, key, value, c.ttl)
+}
+
+// invalidate discards the responses cached under keys, after a call that may have changed them.
+func (c *responseCache) invalidate(ctx context.Context, keys ...string) {
+	if c == nil {
+		return
+	}
+
+	for _, key := range keys {
+		stripe := c.stripe(key)
+		stripe.mu.Lock()
+		stripe.generation++
+		stripe.mu.Unlock()
+
+		c.store.Delete(ctx, key)
+	}
+}
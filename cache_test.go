@@ -0,0 +1,211 @@
+package chatkit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// cacheStub is a server counting the reads of users, rooms and roles made by a client with a
+// response cache, and accepting any change to them.
+type cacheStub struct {
+	mu    sync.Mutex
+	reads map[string]int
+	// If set, the next read blocks until it is closed.
+	block chan struct{}
+	// Receives a value when a read has started blocking.
+	blocked chan struct{}
+}
+
+func newCacheStub(t *testing.T) (*cacheStub, *Client, func()) {
+	stub := &cacheStub{reads: map[string]int{}, blocked: make(chan struct{}, 1)}
+	client, server := newStubServer(t, stub.serveHTTP, WithResponseCache(ResponseCacheOptions{}))
+	return stub, client, server.Close
+}
+
+func (s *cacheStub) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodGet {
+		s.mu.Lock()
+		s.reads[resourceOf(r.URL.Path)]++
+		block := s.block
+		s.block = nil
+		s.mu.Unlock()
+
+		if block != nil {
+			s.blocked <- struct{}{}
+			<-block
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/roles") {
+			writeTestJSON(w, []interface{}{})
+			return
+		}
+	}
+
+	writeTestJSON(w, map[string]interface{}{
+		"id":         "resource",
+		"name":       "resource",
+		"job_id":     "job",
+		"created_at": "2018-01-01T00:00:00Z",
+		"updated_at": "2018-01-01T00:00:00Z",
+	})
+}
+
+// resourceOf returns the resource a path reads, e.g. "users/alice" or "roles".
+func resourceOf(path string) string {
+	if strings.HasSuffix(path, "/roles") {
+		return "roles"
+	}
+
+	segments := strings.Split(path, "/")
+	return strings.Join(segments[len(segments)-2:], "/")
+}
+
+func (s *cacheStub) readsOf(resource string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reads[resource]
+}
+
+func TestResponseCacheInvalidatedByMutators(t *testing.T) {
+	ctx := context.Background()
+	getUser := func(client *Client) error {
+		_, err := client.Users().GetUser(ctx, "alice")
+		return err
+	}
+	getRoom := func(client *Client) error {
+		_, err := client.Rooms().GetRoom(ctx, "room")
+		return err
+	}
+	getRoles := func(client *Client) error {
+		_, err := client.Roles().GetRoles(ctx)
+		return err
+	}
+	role := CreateRoleOptions{Name: "role", Permissions: []string{"message:create"}}
+
+	for _, test := range []struct {
+		name     string
+		resource string
+		get      func(client *Client) error
+		mutate   func(client *Client) error
+	}{
+		{"CreateUser", "users/alice", getUser, func(client *Client) error {
+			return client.Users().CreateUser(ctx, CreateUserOptions{ID: "alice", Name: "Alice"})
+		}},
+		{"CreateUsers", "users/alice", getUser, func(client *Client) error {
+			return client.Users().CreateUsers(ctx, []CreateUserOptions{{ID: "alice", Name: "Alice"}})
+		}},
+		{"UpdateUser", "users/alice", getUser, func(client *Client) error {
+			name := "Alice"
+			return client.Users().UpdateUser(ctx, "alice", UpdateUserOptions{Name: &name})
+		}},
+		{"DeleteUser", "users/alice", getUser, func(client *Client) error {
+			return client.Users().DeleteUser(ctx, "alice")
+		}},
+		{"UpdateRoom", "rooms/room", getRoom, func(client *Client) error {
+			name := "renamed"
+			return client.Rooms().UpdateRoom(ctx, "room", UpdateRoomOptions{Name: &name})
+		}},
+		{"DeleteRoom", "rooms/room", getRoom, func(client *Client) error {
+			return client.Rooms().DeleteRoom(ctx, "room")
+		}},
+		{"AsyncDeleteRoom", "rooms/room", getRoom, func(client *Client) error {
+			_, err := client.Rooms().AsyncDeleteRoom(ctx, "room")
+			return err
+		}},
+		{"AddUsersToRoom", "rooms/room", getRoom, func(client *Client) error {
+			return client.Rooms().AddUsersToRoom(ctx, "room", []string{"alice"})
+		}},
+		{"RemoveUsersFromRoom", "rooms/room", getRoom, func(client *Client) error {
+			return client.Rooms().RemoveUsersFromRoom(ctx, "room", []string{"alice"})
+		}},
+		{"JoinRoom", "rooms/room", getRoom, func(client *Client) error {
+			_, err := client.Rooms().JoinRoom(ctx, "room", "alice")
+			return err
+		}},
+		{"LeaveRoom", "rooms/room", getRoom, func(client *Client) error {
+			return client.Rooms().LeaveRoom(ctx, "room", "alice")
+		}},
+		{"CreateGlobalRole", "roles", getRoles, func(client *Client) error {
+			return client.Roles().CreateGlobalRole(ctx, role)
+		}},
+		{"CreateRoomRole", "roles", getRoles, func(client *Client) error {
+			return client.Roles().CreateRoomRole(ctx, role)
+		}},
+		{"DeleteGlobalRole", "roles", getRoles, func(client *Client) error {
+			return client.Roles().DeleteGlobalRole(ctx, "role")
+		}},
+		{"DeleteRoomRole", "roles", getRoles, func(client *Client) error {
+			return client.Roles().DeleteRoomRole(ctx, "role")
+		}},
+		{"UpdatePermissionsForGlobalRole", "roles", getRoles, func(client *Client) error {
+			return client.Roles().UpdatePermissionsForGlobalRole(ctx, "role", UpdateRolePermissionsOptions{
+				PermissionsToAdd: []string{"room:join"},
+			})
+		}},
+		{"UpdatePermissionsForRoomRole", "roles", getRoles, func(client *Client) error {
+			return client.Roles().UpdatePermissionsForRoomRole(ctx, "role", UpdateRolePermissionsOptions{
+				PermissionsToAdd: []string{"room:join"},
+			})
+		}},
+	} {
+		stub, client, closeServer := newCacheStub(t)
+
+		for i := 0; i < 2; i++ {
+			if err := test.get(client); err != nil {
+				t.Fatalf("%s: failed to read: %v", test.name, err)
+			}
+		}
+		if reads := stub.readsOf(test.resource); reads != 1 {
+			t.Fatalf("%s: expected the second read to be cached, got %d reads of %s", test.name, reads, test.resource)
+		}
+
+		if err := test.mutate(client); err != nil {
+			t.Fatalf("%s: failed: %v", test.name, err)
+		}
+
+		if err := test.get(client); err != nil {
+			t.Fatalf("%s: failed to read: %v", test.name, err)
+		}
+		if reads := stub.readsOf(test.resource); reads != 2 {
+			t.Errorf("%s: expected the cache to be invalidated, got %d reads of %s", test.name, reads, test.resource)
+		}
+
+		closeServer()
+	}
+}
+
+func TestResponseCacheDoesNotCacheReadsOverlappingInvalidation(t *testing.T) {
+	ctx := context.Background()
+	stub, client, closeServer := newCacheStub(t)
+	defer closeServer()
+
+	release := make(chan struct{})
+	stub.block = release
+
+	done := make(chan error)
+	go func() {
+		_, err := client.Users().GetUser(ctx, "alice")
+		done <- err
+	}()
+
+	// The read has fetched the user as it was before the update, but not cached it yet.
+	<-stub.blocked
+	name := "Alice"
+	if err := client.Users().UpdateUser(ctx, "alice", UpdateUserOptions{Name: &name}); err != nil {
+		t.Fatal(err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Users().GetUser(ctx, "alice"); err != nil {
+		t.Fatal(err)
+	}
+	if reads := stub.readsOf("users/alice"); reads != 2 {
+		t.Errorf("Expected the read overlapping the update not to be cached, got %d reads", reads)
+	}
+}
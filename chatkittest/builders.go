@@ -0,0 +1,297 @@
+package chatkittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+// FixtureTime is the time built resources are created and updated at, unless set otherwise.
+var FixtureTime = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// Counters the default IDs of built resources are numbered with, so that they are unique.
+var lastUserID, lastRoomID, lastMessageID uint64
+
+// UserBuilder builds users, and the options to create them. Builders are values, so a builder
+// can be extended without affecting the users built from it.
+//
+//	user := chatkittest.AUser().WithName("Alice").Build()
+type UserBuilder struct {
+	user chatkit.User
+}
+
+// AUser returns a builder of a user with a unique ID.
+func AUser() UserBuilder {
+	n := atomic.AddUint64(&lastUserID, 1)
+	return UserBuilder{user: chatkit.User{
+		ID:        fmt.Sprintf("user-%d", n),
+		Name:      fmt.Sprintf("User %d", n),
+		CreatedAt: FixtureTime,
+		UpdatedAt: FixtureTime,
+	}}
+}
+
+func (b UserBuilder) WithID(id string) UserBuilder {
+	b.user.ID = id
+	return b
+}
+
+func (b UserBuilder) WithName(name string) UserBuilder {
+	b.user.Name = name
+	return b
+}
+
+func (b UserBuilder) WithAvatarURL(avatarURL string) UserBuilder {
+	b.user.AvatarURL = avatarURL
+	return b
+}
+
+func (b UserBuilder) WithCustomData(customData map[string]interface{}) UserBuilder {
+	b.user.CustomData = customData
+	return b
+}
+
+func (b UserBuilder) CreatedAt(createdAt time.Time) UserBuilder {
+	b.user.CreatedAt, b.user.UpdatedAt = createdAt, createdAt
+	return b
+}
+
+// Build returns the user, as Chatkit returns it.
+func (b UserBuilder) Build() chatkit.User {
+	user := b.user
+	user.CustomData = copyCustomData(b.user.CustomData)
+	return user
+}
+
+// CreateOptions returns the options to create the user with.
+func (b UserBuilder) CreateOptions() chatkit.CreateUserOptions {
+	options := chatkit.CreateUserOptions{ID: b.user.ID, Name: b.user.Name}
+	if b.user.AvatarURL != "" {
+		avatarURL := b.user.AvatarURL
+		options.AvatarURL = &avatarURL
+	}
+	if b.user.CustomData != nil {
+		options.CustomData = copyCustomData(b.user.CustomData)
+	}
+
+	return options
+}
+
+// RoomBuilder builds rooms, and the options to create them. Builders are values, so a builder
+// can be extended without affecting the rooms built from it.
+//
+//	room := chatkittest.ARoom().Private().WithMembers("alice", "bob").Build()
+type RoomBuilder struct {
+	room      chatkit.Room
+	memberIDs []string // Members other than the creator
+}
+
+// ARoom returns a builder of a public room with a unique ID, created by "creator".
+func ARoom() RoomBuilder {
+	n := atomic.AddUint64(&lastRoomID, 1)
+	return RoomBuilder{room: chatkit.Room{RoomWithoutMembers: chatkit.RoomWithoutMembers{
+		ID:          fmt.Sprintf("room-%d", n),
+		CreatedByID: "creator",
+		Name:        fmt.Sprintf("Room %d", n),
+		CreatedAt:   FixtureTime,
+		UpdatedAt:   FixtureTime,
+	}}}
+}
+
+func (b RoomBuilder) WithID(id string) RoomBuilder {
+	b.room.ID = id
+	return b
+}
+
+func (b RoomBuilder) WithName(name string) RoomBuilder {
+	b.room.Name = name
+	return b
+}
+
+func (b RoomBuilder) Private() RoomBuilder {
+	b.room.Private = true
+	return b
+}
+
+func (b RoomBuilder) CreatedBy(userID string) RoomBuilder {
+	b.room.CreatedByID = userID
+	return b
+}
+
+// WithMembers adds members to the room, besides its creator.
+func (b RoomBuilder) WithMembers(userIDs ...string) RoomBuilder {
+	b.memberIDs = append(b.memberIDs[:len(b.memberIDs):len(b.memberIDs)], userIDs...)
+	return b
+}
+
+func (b RoomBuilder) WithPushNotificationTitleOverride(title string) RoomBuilder {
+	b.room.PushNotificationTitleOverride = &title
+	return b
+}
+
+func (b RoomBuilder) WithCustomData(customData interface{}) RoomBuilder {
+	b.room.CustomData = customData
+	return b
+}
+
+func (b RoomBuilder) CreatedAt(createdAt time.Time) RoomBuilder {
+	b.room.CreatedAt, b.room.UpdatedAt = createdAt, createdAt
+	return b
+}
+
+// Build returns the room, as Chatkit returns it, with its creator as its first member.
+func (b RoomBuilder) Build() chatkit.Room {
+	room := b.room
+	room.MemberUserIDs = []string{b.room.CreatedByID}
+	for _, userID := range b.memberIDs {
+		if !containsString(room.MemberUserIDs, userID) {
+			room.MemberUserIDs = append(room.MemberUserIDs, userID)
+		}
+	}
+
+	return room
+}
+
+// CreateOptions returns the options to create the room with.
+func (b RoomBuilder) CreateOptions() chatkit.CreateRoomOptions {
+	id := b.room.ID
+	return chatkit.CreateRoomOptions{
+		ID:                            &id,
+		Name:                          b.room.Name,
+		PushNotificationTitleOverride: b.room.PushNotificationTitleOverride,
+		Private:                       b.room.Private,
+		UserIDs:                       append([]string(nil), b.memberIDs...),
+		CustomData:                    b.room.CustomData,
+		CreatorID:                     b.room.CreatedByID,
+	}
+}
+
+// MultipartMessageBuilder builds multipart messages, and the options to send them. Builders are
+// values, so a builder can be extended without affecting the messages built from it.
+//
+//	message := chatkittest.AMultipartMessage().InRoom(room.ID).From("alice").WithText("hi").Build()
+type MultipartMessageBuilder struct {
+	message chatkit.MultipartMessage
+	text    bool // Whether the parts were set, replacing the default one
+}
+
+// AMultipartMessage returns a builder of a message with a unique ID and a single text part, sent
+// by "sender" to "room".
+func AMultipartMessage() MultipartMessageBuilder {
+	n := atomic.AddUint64(&lastMessageID, 1)
+	content := fmt.Sprintf("Message %d", n)
+	return MultipartMessageBuilder{message: chatkit.MultipartMessage{
+		ID:        uint(n),
+		UserID:    "sender",
+		RoomID:    "room",
+		Parts:     []chatkit.Part{{Type: "text/plain", Content: &content}},
+		CreatedAt: FixtureTime,
+		UpdatedAt: FixtureTime,
+	}}
+}
+
+func (b MultipartMessageBuilder) WithID(id uint) MultipartMessageBuilder {
+	b.message.ID = id
+	return b
+}
+
+func (b MultipartMessageBuilder) InRoom(roomID string) MultipartMessageBuilder {
+	b.message.RoomID = roomID
+	return b
+}
+
+func (b MultipartMessageBuilder) From(userID string) MultipartMessageBuilder {
+	b.message.UserID = userID
+	return b
+}
+
+// WithText adds a text/plain part.
+func (b MultipartMessageBuilder) WithText(text string) MultipartMessageBuilder {
+	return b.WithPart(chatkit.Part{Type: "text/plain", Content: &text})
+}
+
+// WithURL adds a part of type partType referring to url.
+func (b MultipartMessageBuilder) WithURL(partType string, url string) MultipartMessageBuilder {
+	return b.WithPart(chatkit.Part{Type: partType, URL: &url})
+}
+
+// WithAttachment adds a part of type partType with an attachment.
+func (b MultipartMessageBuilder) WithAttachment(partType string, attachment chatkit.Attachment) MultipartMessageBuilder {
+	return b.WithPart(chatkit.Part{Type: partType, Attachment: &attachment})
+}
+
+// WithPart adds a part. The first part added replaces the default one.
+func (b MultipartMessageBuilder) WithPart(part chatkit.Part) MultipartMessageBuilder {
+	parts := b.message.Parts
+	if !b.text {
+		parts, b.text = nil, true
+	}
+
+	b.message.Parts = append(parts[:len(parts):len(parts)], part)
+	return b
+}
+
+// InReplyTo makes the message a reply in the thread of another message.
+func (b MultipartMessageBuilder) InReplyTo(parentMessageID uint) MultipartMessageBuilder {
+	b.message.ParentMessageID = &parentMessageID
+	return b
+}
+
+func (b MultipartMessageBuilder) CreatedAt(createdAt time.Time) MultipartMessageBuilder {
+	b.message.CreatedAt, b.message.UpdatedAt = createdAt, createdAt
+	return b
+}
+
+// Build returns the message, as Chatkit returns it. Replies end with the part that marks them
+// as such.
+func (b MultipartMessageBuilder) Build() chatkit.MultipartMessage {
+	message := b.message
+	message.Parts = append([]chatkit.Part(nil), b.message.Parts...)
+
+	if b.message.ParentMessageID != nil {
+		parentMessageID := *b.message.ParentMessageID
+		message.ParentMessageID = &parentMessageID
+
+		content, _ := json.Marshal(map[string]uint{"parent_message_id": parentMessageID})
+		threadContent := string(content)
+		message.Parts = append(message.Parts, chatkit.Part{Type: chatkit.ThreadPartType, Content: &threadContent})
+	}
+
+	return message
+}
+
+// SendOptions returns the options to send the message with. Attachments are referred to by ID.
+func (b MultipartMessageBuilder) SendOptions() chatkit.SendMultipartMessageOptions {
+	parts := make([]chatkit.NewPart, len(b.message.Parts))
+	for i, part := range b.message.Parts {
+		parts[i] = part.AsNewPart()
+	}
+
+	options := chatkit.SendMultipartMessageOptions{
+		RoomID:   b.message.RoomID,
+		SenderID: b.message.UserID,
+		Parts:    parts,
+	}
+	if b.message.ParentMessageID != nil {
+		parentMessageID := *b.message.ParentMessageID
+		options.ParentMessageID = &parentMessageID
+	}
+
+	return options
+}
+
+func copyCustomData(customData map[string]interface{}) map[string]interface{} {
+	if customData == nil {
+		return nil
+	}
+
+	copied := make(map[string]interface{}, len(customData))
+	for key, value := range customData {
+		copied[key] = value
+	}
+
+	return copied
+}
@@ -0,0 +1,229 @@
+package chatkittest
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+func TestBuildersAssignUniqueIDs(t *testing.T) {
+	if a, b := AUser().Build(), AUser().Build(); a.ID == b.ID {
+		t.Errorf("Expected users with unique IDs, both were %q", a.ID)
+	}
+	if a, b := ARoom().Build(), ARoom().Build(); a.ID == b.ID {
+		t.Errorf("Expected rooms with unique IDs, both were %q", a.ID)
+	}
+	if a, b := AMultipartMessage().Build(), AMultipartMessage().Build(); a.ID == b.ID {
+		t.Errorf("Expected messages with unique IDs, both were %d", a.ID)
+	}
+}
+
+func TestUserBuilder(t *testing.T) {
+	createdAt := time.Date(2021, time.March, 4, 5, 6, 7, 0, time.UTC)
+	base := AUser().WithID("alice").WithName("Alice")
+	builder := base.
+		WithAvatarURL("https://example.com/alice.png").
+		WithCustomData(map[string]interface{}{"team": "blue"}).
+		CreatedAt(createdAt)
+
+	expected := chatkit.User{
+		ID:         "alice",
+		Name:       "Alice",
+		AvatarURL:  "https://example.com/alice.png",
+		CustomData: map[string]interface{}{"team": "blue"},
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+	if user := builder.Build(); !reflect.DeepEqual(user, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, user)
+	}
+
+	if user := base.Build(); user.AvatarURL != "" || user.CustomData != nil || !user.CreatedAt.Equal(FixtureTime) {
+		t.Errorf("Expected the base builder to be unaffected by extending it, got %+v", user)
+	}
+
+	builder.Build().CustomData["team"] = "red"
+	builder.CreateOptions().CustomData.(map[string]interface{})["team"] = "red"
+	if team := builder.Build().CustomData["team"]; team != "blue" {
+		t.Errorf("Expected built users not to share custom data with the builder, got team %v", team)
+	}
+
+	options := builder.CreateOptions()
+	if options.AvatarURL == nil || *options.AvatarURL != "https://example.com/alice.png" {
+		t.Errorf("Expected the avatar URL in the create options, got %v", options.AvatarURL)
+	}
+	if options := base.CreateOptions(); options.AvatarURL != nil || options.CustomData != nil {
+		t.Errorf("Expected unset fields to be left out of the create options, got %+v", options)
+	}
+}
+
+func TestUserBuilderCreateOptionsCreateTheBuiltUser(t *testing.T) {
+	ctx := context.Background()
+	server, client := newFakeServerClient(t)
+	defer server.Close()
+
+	builder := AUser().
+		WithAvatarURL("https://example.com/avatar.png").
+		WithCustomData(map[string]interface{}{"team": "blue"})
+
+	user, err := client.Users().CreateUserAndGet(ctx, builder.CreateOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := builder.CreatedAt(user.CreatedAt).Build()
+	if !reflect.DeepEqual(user, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, user)
+	}
+}
+
+func TestRoomBuilder(t *testing.T) {
+	base := ARoom().WithID("general").CreatedBy("alice").WithMembers("bob")
+	builder := base.
+		Private().
+		WithMembers("alice", "carol").
+		WithPushNotificationTitleOverride("General").
+		WithCustomData(map[string]interface{}{"topic": "anything"})
+
+	room := builder.Build()
+	if !room.Private || room.ID != "general" || room.CreatedByID != "alice" {
+		t.Errorf("Expected a private room general created by alice, got %+v", room)
+	}
+	if expected := []string{"alice", "bob", "carol"}; !reflect.DeepEqual(room.MemberUserIDs, expected) {
+		t.Errorf("Expected the creator first and members once each %v, got %v", expected, room.MemberUserIDs)
+	}
+	if room.PushNotificationTitleOverride == nil || *room.PushNotificationTitleOverride != "General" {
+		t.Errorf("Expected the push notification title override, got %v", room.PushNotificationTitleOverride)
+	}
+
+	if room := base.Build(); room.Private || !reflect.DeepEqual(room.MemberUserIDs, []string{"alice", "bob"}) {
+		t.Errorf("Expected the base builder to be unaffected by extending it, got %+v", room)
+	}
+
+	options := builder.CreateOptions()
+	if options.ID == nil || *options.ID != "general" || options.CreatorID != "alice" || !options.Private {
+		t.Errorf("Expected create options for private room general by alice, got %+v", options)
+	}
+	if expected := []string{"bob", "alice", "carol"}; !reflect.DeepEqual(options.UserIDs, expected) {
+		t.Errorf("Expected the members to be added to be %v, got %v", expected, options.UserIDs)
+	}
+}
+
+func TestRoomBuilderCreateOptionsCreateTheBuiltRoom(t *testing.T) {
+	ctx := context.Background()
+	server, client := newFakeServerClient(t)
+	defer server.Close()
+
+	for _, id := range []string{"alice", "bob"} {
+		if err := client.Users().CreateUser(ctx, AUser().WithID(id).CreateOptions()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	builder := ARoom().
+		CreatedBy("alice").
+		WithMembers("bob").
+		Private().
+		WithPushNotificationTitleOverride("Title").
+		WithCustomData(map[string]interface{}{"topic": "anything"})
+
+	room, err := client.Rooms().CreateRoom(ctx, builder.CreateOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := builder.CreatedAt(room.CreatedAt).Build()
+	if !reflect.DeepEqual(room, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, room)
+	}
+}
+
+func TestMultipartMessageBuilder(t *testing.T) {
+	if parts := AMultipartMessage().Build().Parts; len(parts) != 1 || parts[0].Type != "text/plain" {
+		t.Errorf("Expected a single text part by default, got %+v", parts)
+	}
+
+	base := AMultipartMessage().InRoom("general").From("alice").WithText("hi")
+	builder := base.
+		WithURL("image/png", "https://example.com/cat.png").
+		InReplyTo(42)
+
+	message := builder.Build()
+	if message.RoomID != "general" || message.UserID != "alice" {
+		t.Errorf("Expected a message from alice in general, got %+v", message)
+	}
+	if len(message.Parts) != 3 {
+		t.Fatalf("Expected the text, URL and thread parts, got %+v", message.Parts)
+	}
+	if part := message.Parts[0]; part.Type != "text/plain" || part.Content == nil || *part.Content != "hi" {
+		t.Errorf("Expected the text part to replace the default one, got %+v", part)
+	}
+	if part := message.Parts[1]; part.Type != "image/png" || part.URL == nil || *part.URL != "https://example.com/cat.png" {
+		t.Errorf("Expected the URL part, got %+v", part)
+	}
+	if part := message.Parts[2]; part.Type != chatkit.ThreadPartType || part.Content == nil ||
+		*part.Content != `{"parent_message_id":42}` {
+		t.Errorf("Expected replies to end with a thread part, got %+v", part)
+	}
+	if message.ParentMessageID == nil || *message.ParentMessageID != 42 {
+		t.Errorf("Expected the parent message ID 42, got %v", message.ParentMessageID)
+	}
+
+	if message := base.Build(); len(message.Parts) != 1 || message.ParentMessageID != nil {
+		t.Errorf("Expected the base builder to be unaffected by extending it, got %+v", message)
+	}
+
+	options := builder.SendOptions()
+	if options.RoomID != "general" || options.SenderID != "alice" || len(options.Parts) != 2 {
+		t.Errorf("Expected send options for the text and URL parts from alice in general, got %+v", options)
+	}
+	if options.ParentMessageID == nil || *options.ParentMessageID != 42 {
+		t.Errorf("Expected the send options to reply to 42, got %v", options.ParentMessageID)
+	}
+}
+
+func TestMultipartMessageBuilderSendOptionsSendTheBuiltMessage(t *testing.T) {
+	ctx := context.Background()
+	server, client := newFakeServerClient(t)
+	defer server.Close()
+
+	if err := client.Users().CreateUser(ctx, AUser().WithID("alice").CreateOptions()); err != nil {
+		t.Fatal(err)
+	}
+	room, err := client.Rooms().CreateRoom(ctx, ARoom().CreatedBy("alice").CreateOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	base := AMultipartMessage().InRoom(room.ID).From("alice")
+	parentID, err := client.Messages().SendMultipartMessage(ctx, base.SendOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	builder := base.
+		WithText("hi").
+		WithURL("image/png", "https://example.com/cat.png").
+		InReplyTo(parentID)
+
+	messageID, err := client.Messages().SendMultipartMessage(ctx, builder.SendOptions())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	message, err := client.Messages().FetchMultipartMessage(ctx, chatkit.FetchMultipartMessageOptions{
+		RoomID:    room.ID,
+		MessageID: messageID,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := builder.WithID(messageID).CreatedAt(message.CreatedAt).Build()
+	if !reflect.DeepEqual(message, expected) {
+		t.Errorf("Expected %+v, got %+v", expected, message)
+	}
+}
@@ -0,0 +1,219 @@
+package chatkittest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+var authorizerRoutes = []fakeRoute{
+	{http.MethodGet, "roles", (*FakeServer).getRoles},
+	{http.MethodPost, "roles", (*FakeServer).createRole},
+	{http.MethodDelete, "roles/*/scope/*", (*FakeServer).deleteRole},
+	{http.MethodGet, "roles/*/scope/*/permissions", (*FakeServer).getPermissions},
+	{http.MethodPut, "roles/*/scope/*/permissions", (*FakeServer).updatePermissions},
+	{http.MethodGet, "users/*/roles", (*FakeServer).getUserRoles},
+	{http.MethodPut, "users/*/roles", (*FakeServer).assignRole},
+	{http.MethodDelete, "users/*/roles", (*FakeServer).removeRole},
+}
+
+type roleKey struct {
+	name  string
+	scope string
+}
+
+// roleAssignment is a role assigned to a user, globally or in a room.
+type roleAssignment struct {
+	name   string
+	roomID *string
+}
+
+func (a roleAssignment) scope() string {
+	if a.roomID == nil {
+		return chatkit.RoleScopeGlobal
+	}
+
+	return chatkit.RoleScopeRoom
+}
+
+// removeAssignment removes the role assigned in a room, or globally if roomID is nil.
+func removeAssignment(assignments []roleAssignment, roomID *string) []roleAssignment {
+	kept := assignments[:0]
+	for _, a := range assignments {
+		if (a.roomID == nil) != (roomID == nil) || a.roomID != nil && *a.roomID != *roomID {
+			kept = append(kept, a)
+		}
+	}
+
+	return kept
+}
+
+func (s *FakeServer) getRoles(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	roles := []*chatkit.Role{}
+	for _, role := range s.roles {
+		roles = append(roles, role)
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].Scope != roles[j].Scope {
+			return roles[i].Scope < roles[j].Scope
+		}
+		return roles[i].Name < roles[j].Name
+	})
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *FakeServer) createRole(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	var role chatkit.Role
+	if !r.decode(w, &role) {
+		return
+	}
+
+	if role.Scope != chatkit.RoleScopeGlobal && role.Scope != chatkit.RoleScopeRoom {
+		writeError(
+			w,
+			http.StatusBadRequest,
+			"services/chatkit_authorizer/bad_request/invalid_scope",
+			fmt.Sprintf("Invalid scope %s", role.Scope),
+		)
+		return
+	}
+
+	key := roleKey{name: role.Name, scope: role.Scope}
+	if _, ok := s.roles[key]; ok {
+		writeError(
+			w,
+			http.StatusConflict,
+			"services/chatkit_authorizer/conflict/role_already_exists",
+			fmt.Sprintf("Role %s already exists in scope %s", role.Name, role.Scope),
+		)
+		return
+	}
+
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+	s.roles[key] = &role
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *FakeServer) deleteRole(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key := roleKey{name: params[0], scope: params[1]}
+	if _, ok := s.roles[key]; !ok {
+		writeRoleNotFound(w, key)
+		return
+	}
+
+	delete(s.roles, key)
+
+	for userID, assignments := range s.userRoles {
+		kept := assignments[:0]
+		for _, a := range assignments {
+			if a.name != key.name || a.scope() != key.scope {
+				kept = append(kept, a)
+			}
+		}
+		s.userRoles[userID] = kept
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) getPermissions(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key := roleKey{name: params[0], scope: params[1]}
+	role, ok := s.roles[key]
+	if !ok {
+		writeRoleNotFound(w, key)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, role.Permissions)
+}
+
+func (s *FakeServer) updatePermissions(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key := roleKey{name: params[0], scope: params[1]}
+	role, ok := s.roles[key]
+	if !ok {
+		writeRoleNotFound(w, key)
+		return
+	}
+
+	var body chatkit.UpdateRolePermissionsOptions
+	if !r.decode(w, &body) {
+		return
+	}
+
+	for _, permission := range body.PermissionsToRemove {
+		role.Permissions = removeString(role.Permissions, permission)
+	}
+	for _, permission := range body.PermissionsToAdd {
+		if !containsString(role.Permissions, permission) {
+			role.Permissions = append(role.Permissions, permission)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) getUserRoles(w http.ResponseWriter, r *fakeRequest, params []string) {
+	roles := []chatkit.Role{}
+	for _, a := range s.userRoles[params[0]] {
+		role := *s.roles[roleKey{name: a.name, scope: a.scope()}]
+		if a.roomID != nil {
+			role.RoomID = *a.roomID
+		}
+		roles = append(roles, role)
+	}
+
+	writeJSON(w, http.StatusOK, roles)
+}
+
+func (s *FakeServer) assignRole(w http.ResponseWriter, r *fakeRequest, params []string) {
+	userID := params[0]
+
+	var body struct {
+		Name   string  `json:"name"`
+		RoomID *string `json:"room_id"`
+	}
+	if !r.decode(w, &body) {
+		return
+	}
+
+	a := roleAssignment{name: body.Name, roomID: body.RoomID}
+	key := roleKey{name: a.name, scope: a.scope()}
+	if _, ok := s.roles[key]; !ok {
+		writeRoleNotFound(w, key)
+		return
+	}
+
+	// A user has a single role globally, and in each room.
+	s.userRoles[userID] = append(removeAssignment(s.userRoles[userID], a.roomID), a)
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *FakeServer) removeRole(w http.ResponseWriter, r *fakeRequest, params []string) {
+	userID := params[0]
+
+	var roomID *string
+	if id := r.URL.Query().Get("room_id"); id != "" {
+		roomID = &id
+	}
+
+	s.userRoles[userID] = removeAssignment(s.userRoles[userID], roomID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeRoleNotFound(w http.ResponseWriter, key roleKey) {
+	writeError(
+		w,
+		http.StatusNotFound,
+		"services/chatkit_authorizer/not_found/role_not_found",
+		fmt.Sprintf("Role %s not found in scope %s", key.name, key.scope),
+	)
+}
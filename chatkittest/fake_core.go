@@ -0,0 +1,796 @@
+package chatkittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+// Default and largest page sizes of the core service.
+const (
+	defaultUsersLimit    = 20
+	defaultMessagesLimit = 20
+	maxMessagesLimit     = 100
+	roomsPageSize        = 100
+)
+
+var coreRoutes = []fakeRoute{
+	{http.MethodPost, "users", (*FakeServer).createUser},
+	{http.MethodPost, "batch_users", (*FakeServer).createUsers},
+	{http.MethodGet, "users", (*FakeServer).getUsers},
+	{http.MethodGet, "users_by_ids", (*FakeServer).getUsersByID},
+	{http.MethodGet, "users/*", (*FakeServer).getUser},
+	{http.MethodPut, "users/*", (*FakeServer).updateUser},
+	{http.MethodDelete, "users/*", (*FakeServer).deleteUser},
+	{http.MethodGet, "users/*/rooms", (*FakeServer).getUserRooms},
+	{http.MethodPost, "users/*/rooms/*/join", (*FakeServer).joinRoom},
+	{http.MethodPost, "users/*/rooms/*/leave", (*FakeServer).leaveRoom},
+
+	{http.MethodPost, "rooms", (*FakeServer).createRoom},
+	{http.MethodGet, "rooms", (*FakeServer).getRooms},
+	{http.MethodGet, "rooms/*", (*FakeServer).getRoom},
+	{http.MethodPut, "rooms/*", (*FakeServer).updateRoom},
+	{http.MethodDelete, "rooms/*", (*FakeServer).deleteRoom},
+	{http.MethodPut, "rooms/*/users/add", (*FakeServer).addUsersToRoom},
+	{http.MethodPut, "rooms/*/users/remove", (*FakeServer).removeUsersFromRoom},
+
+	{http.MethodPost, "rooms/*/messages", (*FakeServer).sendMessage},
+	{http.MethodGet, "rooms/*/messages", (*FakeServer).getMessages},
+	{http.MethodGet, "rooms/*/messages/*", (*FakeServer).getMessage},
+	{http.MethodPut, "rooms/*/messages/*", (*FakeServer).editMessage},
+	{http.MethodDelete, "rooms/*/messages/*", (*FakeServer).deleteMessage},
+}
+
+type fakeNewUser struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	AvatarURL  string                 `json:"avatar_url"`
+	CustomData map[string]interface{} `json:"custom_data"`
+}
+
+func (s *FakeServer) addUser(newUser fakeNewUser) *chatkit.User {
+	createdAt := now()
+	user := &chatkit.User{
+		ID:         newUser.ID,
+		Name:       newUser.Name,
+		AvatarURL:  newUser.AvatarURL,
+		CustomData: newUser.CustomData,
+		CreatedAt:  createdAt,
+		UpdatedAt:  createdAt,
+	}
+
+	s.users[user.ID] = user
+	s.userIDs = append(s.userIDs, user.ID)
+	return user
+}
+
+func (s *FakeServer) createUser(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	var newUser fakeNewUser
+	if !r.decode(w, &newUser) {
+		return
+	}
+
+	if newUser.ID == "" || newUser.Name == "" {
+		writeError(w, http.StatusBadRequest, "services/chatkit/bad_request/invalid_user", "Users need an ID and a name")
+		return
+	}
+
+	if _, ok := s.users[newUser.ID]; ok {
+		writeUserExists(w, newUser.ID)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, s.addUser(newUser))
+}
+
+func (s *FakeServer) createUsers(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	var body struct {
+		Users []fakeNewUser `json:"users"`
+	}
+	if !r.decode(w, &body) {
+		return
+	}
+
+	for _, newUser := range body.Users {
+		if newUser.ID == "" || newUser.Name == "" {
+			writeError(w, http.StatusBadRequest, "services/chatkit/bad_request/invalid_user", "Users need an ID and a name")
+			return
+		}
+
+		if _, ok := s.users[newUser.ID]; ok {
+			writeUserExists(w, newUser.ID)
+			return
+		}
+	}
+
+	users := make([]*chatkit.User, len(body.Users))
+	for i, newUser := range body.Users {
+		users[i] = s.addUser(newUser)
+	}
+
+	writeJSON(w, http.StatusCreated, users)
+}
+
+func (s *FakeServer) getUsers(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	query := r.URL.Query()
+
+	var from time.Time
+	if fromTimestamp := query.Get("from_ts"); fromTimestamp != "" {
+		var err error
+		from, err = time.Parse(time.RFC3339Nano, fromTimestamp)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "services/chatkit/bad_request/invalid_query", "Invalid from_ts")
+			return
+		}
+	}
+
+	limit, ok := queryUint(w, r, "limit", defaultUsersLimit)
+	if !ok {
+		return
+	}
+
+	users := []*chatkit.User{}
+	for _, id := range s.userIDs {
+		if uint(len(users)) == limit {
+			break
+		}
+
+		if user := s.users[id]; !user.CreatedAt.Before(from) {
+			users = append(users, user)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *FakeServer) getUsersByID(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	users := []*chatkit.User{}
+	for _, id := range r.URL.Query()["id"] {
+		if user, ok := s.users[id]; ok {
+			users = append(users, user)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, users)
+}
+
+func (s *FakeServer) getUser(w http.ResponseWriter, r *fakeRequest, params []string) {
+	user, ok := s.users[params[0]]
+	if !ok {
+		writeUserNotFound(w, params[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, user)
+}
+
+func (s *FakeServer) updateUser(w http.ResponseWriter, r *fakeRequest, params []string) {
+	user, ok := s.users[params[0]]
+	if !ok {
+		writeUserNotFound(w, params[0])
+		return
+	}
+
+	var body struct {
+		Name       *string                `json:"name"`
+		AvatarURL  *string                `json:"avatar_url"`
+		CustomData map[string]interface{} `json:"custom_data"`
+	}
+	if !r.decode(w, &body) {
+		return
+	}
+
+	if body.Name != nil {
+		user.Name = *body.Name
+	}
+	if body.AvatarURL != nil {
+		user.AvatarURL = *body.AvatarURL
+	}
+	if body.CustomData != nil {
+		user.CustomData = body.CustomData
+	}
+	user.UpdatedAt = now()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) deleteUser(w http.ResponseWriter, r *fakeRequest, params []string) {
+	userID := params[0]
+	if _, ok := s.users[userID]; !ok {
+		writeUserNotFound(w, userID)
+		return
+	}
+
+	delete(s.users, userID)
+	s.userIDs = removeString(s.userIDs, userID)
+	delete(s.userRoles, userID)
+
+	for _, room := range s.rooms {
+		room.MemberUserIDs = removeString(room.MemberUserIDs, userID)
+	}
+
+	for key := range s.cursors {
+		if key.userID == userID {
+			delete(s.cursors, key)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) getUserRooms(w http.ResponseWriter, r *fakeRequest, params []string) {
+	userID := params[0]
+	if _, ok := s.users[userID]; !ok {
+		writeUserNotFound(w, userID)
+		return
+	}
+
+	joinable := r.URL.Query().Get("joinable") == "true"
+
+	rooms := []*chatkit.Room{}
+	for _, id := range s.roomIDs {
+		room := s.rooms[id]
+		member := containsString(room.MemberUserIDs, userID)
+
+		if joinable && !member && !room.Private || !joinable && member {
+			rooms = append(rooms, room)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, rooms)
+}
+
+func (s *FakeServer) joinRoom(w http.ResponseWriter, r *fakeRequest, params []string) {
+	userID, roomID := params[0], params[1]
+	if _, ok := s.users[userID]; !ok {
+		writeUserNotFound(w, userID)
+		return
+	}
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		writeRoomNotFound(w, roomID)
+		return
+	}
+
+	if !containsString(room.MemberUserIDs, userID) {
+		room.MemberUserIDs = append(room.MemberUserIDs, userID)
+	}
+
+	writeJSON(w, http.StatusOK, room)
+}
+
+func (s *FakeServer) leaveRoom(w http.ResponseWriter, r *fakeRequest, params []string) {
+	userID, roomID := params[0], params[1]
+
+	room, ok := s.rooms[roomID]
+	if !ok {
+		writeRoomNotFound(w, roomID)
+		return
+	}
+
+	room.MemberUserIDs = removeString(room.MemberUserIDs, userID)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) createRoom(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	var body struct {
+		ID                            *string     `json:"id"`
+		Name                          string      `json:"name"`
+		PushNotificationTitleOverride *string     `json:"push_notification_title_override"`
+		Private                       bool        `json:"private"`
+		UserIDs                       []string    `json:"user_ids"`
+		CustomData                    interface{} `json:"custom_data"`
+	}
+	if !r.decode(w, &body) {
+		return
+	}
+
+	if r.claims.Sub == "" {
+		writeError(w, http.StatusBadRequest, "services/chatkit/bad_request/missing_user_id", "Rooms are created by a user")
+		return
+	}
+
+	memberIDs := []string{r.claims.Sub}
+	for _, userID := range body.UserIDs {
+		if !containsString(memberIDs, userID) {
+			memberIDs = append(memberIDs, userID)
+		}
+	}
+	for _, userID := range memberIDs {
+		if _, ok := s.users[userID]; !ok {
+			writeUserNotFound(w, userID)
+			return
+		}
+	}
+
+	var id string
+	if body.ID != nil {
+		id = *body.ID
+		if _, ok := s.rooms[id]; ok {
+			writeError(
+				w,
+				http.StatusConflict,
+				"services/chatkit/conflict/room_already_exists",
+				fmt.Sprintf("Room %s already exists", id),
+			)
+			return
+		}
+	} else {
+		for {
+			s.lastRoomID++
+			id = strconv.FormatUint(uint64(s.lastRoomID), 10)
+			if _, ok := s.rooms[id]; !ok {
+				break
+			}
+		}
+	}
+
+	createdAt := now()
+	room := &chatkit.Room{
+		RoomWithoutMembers: chatkit.RoomWithoutMembers{
+			ID:                            id,
+			CreatedByID:                   r.claims.Sub,
+			Name:                          body.Name,
+			PushNotificationTitleOverride: body.PushNotificationTitleOverride,
+			Private:                       body.Private,
+			CustomData:                    body.CustomData,
+			CreatedAt:                     createdAt,
+			UpdatedAt:                     createdAt,
+		},
+		MemberUserIDs: memberIDs,
+	}
+
+	s.rooms[id] = room
+	s.roomIDs = append(s.roomIDs, id)
+
+	writeJSON(w, http.StatusCreated, room)
+}
+
+func (s *FakeServer) getRooms(w http.ResponseWriter, r *fakeRequest, _ []string) {
+	query := r.URL.Query()
+	includePrivate := query.Get("include_private") == "true"
+
+	// Rooms are listed in the order they were created, after the room with from_id.
+	ids := s.roomIDs
+	if fromID := query.Get("from_id"); fromID != "" {
+		for i, id := range ids {
+			if id == fromID {
+				ids = ids[i+1:]
+				break
+			}
+		}
+	}
+
+	rooms := []chatkit.RoomWithoutMembers{}
+	for _, id := range ids {
+		if len(rooms) == roomsPageSize {
+			break
+		}
+
+		if room := s.rooms[id]; includePrivate || !room.Private {
+			rooms = append(rooms, room.RoomWithoutMembers)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, rooms)
+}
+
+func (s *FakeServer) getRoom(w http.ResponseWriter, r *fakeRequest, params []string) {
+	room, ok := s.rooms[params[0]]
+	if !ok {
+		writeRoomNotFound(w, params[0])
+		return
+	}
+
+	writeJSON(w, http.StatusOK, room)
+}
+
+func (s *FakeServer) updateRoom(w http.ResponseWriter, r *fakeRequest, params []string) {
+	room, ok := s.rooms[params[0]]
+	if !ok {
+		writeRoomNotFound(w, params[0])
+		return
+	}
+
+	// The fields are decoded one by one, as an explicit null push notification title override
+	// removes it.
+	var body map[string]json.RawMessage
+	if !r.decode(w, &body) {
+		return
+	}
+
+	fields := map[string]interface{}{
+		"name":                             &room.Name,
+		"private":                          &room.Private,
+		"custom_data":                      &room.CustomData,
+		"push_notification_title_override": &room.PushNotificationTitleOverride,
+	}
+	for name, value := range body {
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		if err := json.Unmarshal(value, field); err != nil {
+			writeError(
+				w,
+				http.StatusBadRequest,
+				"services/chatkit/bad_request/invalid_json_body",
+				fmt.Sprintf("Invalid %s: %v", name, err),
+			)
+			return
+		}
+	}
+	room.UpdatedAt = now()
+
+	writeJSON(w, http.StatusOK, room)
+}
+
+func (s *FakeServer) deleteRoom(w http.ResponseWriter, r *fakeRequest, params []string) {
+	roomID := params[0]
+	if _, ok := s.rooms[roomID]; !ok {
+		writeRoomNotFound(w, roomID)
+		return
+	}
+
+	delete(s.rooms, roomID)
+	s.roomIDs = removeString(s.roomIDs, roomID)
+	delete(s.messages, roomID)
+
+	for key := range s.cursors {
+		if key.roomID == roomID {
+			delete(s.cursors, key)
+		}
+	}
+
+	for userID, assignments := range s.userRoles {
+		s.userRoles[userID] = removeAssignment(assignments, &roomID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) addUsersToRoom(w http.ResponseWriter, r *fakeRequest, params []string) {
+	room, userIDs, ok := s.decodeRoomUsers(w, r, params[0])
+	if !ok {
+		return
+	}
+
+	for _, userID := range userIDs {
+		if _, ok := s.users[userID]; !ok {
+			writeUserNotFound(w, userID)
+			return
+		}
+	}
+
+	for _, userID := range userIDs {
+		if !containsString(room.MemberUserIDs, userID) {
+			room.MemberUserIDs = append(room.MemberUserIDs, userID)
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) removeUsersFromRoom(w http.ResponseWriter, r *fakeRequest, params []string) {
+	room, userIDs, ok := s.decodeRoomUsers(w, r, params[0])
+	if !ok {
+		return
+	}
+
+	for _, userID := range userIDs {
+		room.MemberUserIDs = removeString(room.MemberUserIDs, userID)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) decodeRoomUsers(
+	w http.ResponseWriter,
+	r *fakeRequest,
+	roomID string,
+) (*chatkit.Room, []string, bool) {
+	room, ok := s.rooms[roomID]
+	if !ok {
+		writeRoomNotFound(w, roomID)
+		return nil, nil, false
+	}
+
+	var body struct {
+		UserIDs []string `json:"user_ids"`
+	}
+	if !r.decode(w, &body) {
+		return nil, nil, false
+	}
+
+	return room, body.UserIDs, true
+}
+
+// fakeNewPart is a part of a message sent to the v6 and later core services.
+type fakeNewPart struct {
+	Type       string  `json:"type"`
+	Content    *string `json:"content"`
+	URL        *string `json:"url"`
+	Attachment *struct {
+		ID string `json:"id"`
+	} `json:"attachment"`
+}
+
+// decodeParts decodes the parts of a message sent or edited, which the v2 core service takes as
+// text.
+func decodeParts(w http.ResponseWriter, r *fakeRequest) (parts []chatkit.Part, senderID string, ok bool) {
+	var body struct {
+		Text     *string       `json:"text"`
+		Parts    []fakeNewPart `json:"parts"`
+		SenderID string        `json:"sender_id"`
+	}
+	if !r.decode(w, &body) {
+		return nil, "", false
+	}
+
+	if r.version == "v2" {
+		if body.Text == nil {
+			writeError(w, http.StatusBadRequest, "services/chatkit/bad_request/invalid_message", "Messages need text")
+			return nil, "", false
+		}
+
+		return []chatkit.Part{{Type: "text/plain", Content: body.Text}}, body.SenderID, true
+	}
+
+	if len(body.Parts) == 0 {
+		writeError(w, http.StatusBadRequest, "services/chatkit/bad_request/invalid_message", "Messages need parts")
+		return nil, "", false
+	}
+
+	for _, newPart := range body.Parts {
+		part := chatkit.Part{Type: newPart.Type, Content: newPart.Content, URL: newPart.URL}
+		if newPart.Attachment != nil {
+			part.Attachment = &chatkit.Attachment{ID: newPart.Attachment.ID}
+		}
+		parts = append(parts, part)
+	}
+
+	return parts, body.SenderID, true
+}
+
+func (s *FakeServer) sendMessage(w http.ResponseWriter, r *fakeRequest, params []string) {
+	roomID := params[0]
+	if _, ok := s.rooms[roomID]; !ok {
+		writeRoomNotFound(w, roomID)
+		return
+	}
+
+	parts, senderID, ok := decodeParts(w, r)
+	if !ok {
+		return
+	}
+
+	// Services name the sender in the body, and users are named by their token.
+	if senderID == "" || !r.claims.Su {
+		senderID = r.claims.Sub
+	}
+	if _, ok := s.users[senderID]; !ok {
+		writeUserNotFound(w, senderID)
+		return
+	}
+
+	s.lastMessageID++
+	createdAt := now()
+	s.messages[roomID] = append(s.messages[roomID], &chatkit.MultipartMessage{
+		ID:        s.lastMessageID,
+		UserID:    senderID,
+		RoomID:    roomID,
+		Parts:     parts,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+	})
+
+	writeJSON(w, http.StatusCreated, map[string]uint{"message_id": s.lastMessageID})
+}
+
+func (s *FakeServer) getMessages(w http.ResponseWriter, r *fakeRequest, params []string) {
+	roomID := params[0]
+	if _, ok := s.rooms[roomID]; !ok {
+		writeRoomNotFound(w, roomID)
+		return
+	}
+
+	query := r.URL.Query()
+	newer := query.Get("direction") == "newer"
+
+	initialID, ok := queryUint(w, r, "initial_id", 0)
+	if !ok {
+		return
+	}
+
+	limit, ok := queryUint(w, r, "limit", defaultMessagesLimit)
+	if !ok {
+		return
+	}
+	if limit > maxMessagesLimit {
+		limit = maxMessagesLimit
+	}
+
+	// Messages are returned from the initial ID, exclusive, in the direction requested.
+	messages := []*chatkit.MultipartMessage{}
+	for _, message := range s.messages[roomID] {
+		if initialID == 0 || newer && message.ID > initialID || !newer && message.ID < initialID {
+			messages = append(messages, message)
+		}
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return newer == (messages[i].ID < messages[j].ID)
+	})
+	if uint(len(messages)) > limit {
+		messages = messages[:limit]
+	}
+
+	if r.version == "v2" {
+		writeJSON(w, http.StatusOK, asMessages(messages))
+	} else {
+		writeJSON(w, http.StatusOK, messages)
+	}
+}
+
+func (s *FakeServer) getMessage(w http.ResponseWriter, r *fakeRequest, params []string) {
+	message, ok := s.findMessage(w, params)
+	if !ok {
+		return
+	}
+
+	if r.version == "v2" {
+		writeJSON(w, http.StatusOK, asMessages([]*chatkit.MultipartMessage{message})[0])
+	} else {
+		writeJSON(w, http.StatusOK, message)
+	}
+}
+
+func (s *FakeServer) editMessage(w http.ResponseWriter, r *fakeRequest, params []string) {
+	message, ok := s.findMessage(w, params)
+	if !ok {
+		return
+	}
+
+	parts, _, ok := decodeParts(w, r)
+	if !ok {
+		return
+	}
+
+	message.Parts = parts
+	message.UpdatedAt = now()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) deleteMessage(w http.ResponseWriter, r *fakeRequest, params []string) {
+	message, ok := s.findMessage(w, params)
+	if !ok {
+		return
+	}
+
+	roomID := params[0]
+	messages := s.messages[roomID][:0]
+	for _, m := range s.messages[roomID] {
+		if m != message {
+			messages = append(messages, m)
+		}
+	}
+	s.messages[roomID] = messages
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// findMessage finds the message with the ID in params in the room with the ID in params, and
+// responds with 404 if there is none.
+func (s *FakeServer) findMessage(w http.ResponseWriter, params []string) (*chatkit.MultipartMessage, bool) {
+	roomID := params[0]
+	if _, ok := s.rooms[roomID]; !ok {
+		writeRoomNotFound(w, roomID)
+		return nil, false
+	}
+
+	if id, err := strconv.ParseUint(params[1], 10, 0); err == nil {
+		for _, message := range s.messages[roomID] {
+			if message.ID == uint(id) {
+				return message, true
+			}
+		}
+	}
+
+	writeError(
+		w,
+		http.StatusNotFound,
+		"services/chatkit/not_found/message_not_found",
+		fmt.Sprintf("Message %s not found in room %s", params[1], roomID),
+	)
+	return nil, false
+}
+
+// asMessages returns messages as the v2 core service represents them, with their text rather
+// than their parts.
+func asMessages(messages []*chatkit.MultipartMessage) []chatkit.Message {
+	asMessages := make([]chatkit.Message, len(messages))
+	for i, message := range messages {
+		asMessages[i] = message.AsMessage()
+		asMessages[i].Parts = nil
+	}
+
+	return asMessages
+}
+
+// queryUint returns the value of a query parameter, or def if it isn't set, and responds with
+// 400 if it isn't a number.
+func queryUint(w http.ResponseWriter, r *fakeRequest, name string, def uint) (uint, bool) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return def, true
+	}
+
+	n, err := strconv.ParseUint(value, 10, 0)
+	if err != nil {
+		writeError(
+			w,
+			http.StatusBadRequest,
+			"services/chatkit/bad_request/invalid_query",
+			fmt.Sprintf("Invalid %s", name),
+		)
+		return 0, false
+	}
+
+	return uint(n), true
+}
+
+func writeUserNotFound(w http.ResponseWriter, userID string) {
+	writeError(
+		w,
+		http.StatusNotFound,
+		"services/chatkit/not_found/user_not_found",
+		fmt.Sprintf("User %s not found", userID),
+	)
+}
+
+func writeUserExists(w http.ResponseWriter, userID string) {
+	writeError(
+		w,
+		http.StatusConflict,
+		"services/chatkit/conflict/user_already_exists",
+		fmt.Sprintf("User %s already exists", userID),
+	)
+}
+
+func writeRoomNotFound(w http.ResponseWriter, roomID string) {
+	writeError(
+		w,
+		http.StatusNotFound,
+		"services/chatkit/not_found/room_not_found",
+		fmt.Sprintf("Room %s not found", roomID),
+	)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func removeString(values []string, value string) []string {
+	kept := values[:0]
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+
+	return kept
+}
@@ -0,0 +1,157 @@
+package chatkittest
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+var cursorsRoutes = []fakeRoute{
+	{http.MethodPut, "cursors/*/rooms/*/users/*", (*FakeServer).setCursor},
+	{http.MethodGet, "cursors/*/rooms/*/users/*", (*FakeServer).getCursor},
+	{http.MethodDelete, "cursors/*/rooms/*/users/*", (*FakeServer).deleteCursor},
+	{http.MethodGet, "cursors/*/users/*", (*FakeServer).getUserCursors},
+	{http.MethodGet, "cursors/*/rooms/*", (*FakeServer).getRoomCursors},
+}
+
+type cursorKey struct {
+	cursorType uint
+	roomID     string
+	userID     string
+}
+
+// parseCursorKey parses the cursor type, room and user in params, and responds with 400 if the
+// cursor type isn't a number.
+func parseCursorKey(w http.ResponseWriter, params []string) (cursorKey, bool) {
+	cursorType, err := strconv.ParseUint(params[0], 10, 0)
+	if err != nil {
+		writeError(
+			w,
+			http.StatusBadRequest,
+			"services/chatkit_cursors/bad_request/invalid_cursor_type",
+			fmt.Sprintf("Invalid cursor type %s", params[0]),
+		)
+		return cursorKey{}, false
+	}
+
+	key := cursorKey{cursorType: uint(cursorType)}
+	if len(params) == 3 {
+		key.roomID, key.userID = params[1], params[2]
+	}
+
+	return key, true
+}
+
+func (s *FakeServer) setCursor(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key, ok := parseCursorKey(w, params)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Position uint `json:"position"`
+	}
+	if !r.decode(w, &body) {
+		return
+	}
+
+	s.cursors[key] = chatkit.Cursor{
+		CursorType: key.cursorType,
+		RoomID:     key.roomID,
+		UserID:     key.userID,
+		Position:   body.Position,
+		UpdatedAt:  now(),
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *FakeServer) getCursor(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key, ok := parseCursorKey(w, params)
+	if !ok {
+		return
+	}
+
+	cursor, ok := s.cursors[key]
+	if !ok {
+		writeError(
+			w,
+			http.StatusNotFound,
+			"services/chatkit_cursors/not_found/cursor_not_found",
+			fmt.Sprintf("Cursor of user %s in room %s not found", key.userID, key.roomID),
+		)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, cursor)
+}
+
+func (s *FakeServer) deleteCursor(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key, ok := parseCursorKey(w, params)
+	if !ok {
+		return
+	}
+
+	delete(s.cursors, key)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *FakeServer) getUserCursors(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key, ok := parseCursorKey(w, params)
+	if !ok {
+		return
+	}
+
+	cursors := s.findCursors(func(cursor chatkit.Cursor) bool {
+		return cursor.CursorType == key.cursorType && cursor.UserID == params[1]
+	})
+
+	writeJSON(w, http.StatusOK, cursors)
+}
+
+func (s *FakeServer) getRoomCursors(w http.ResponseWriter, r *fakeRequest, params []string) {
+	key, ok := parseCursorKey(w, params)
+	if !ok {
+		return
+	}
+
+	limit, ok := queryUint(w, r, "limit", 0)
+	if !ok {
+		return
+	}
+
+	// Cursors are paged by the ID of their user, from the one after from_user_id.
+	fromUserID := r.URL.Query().Get("from_user_id")
+	cursors := s.findCursors(func(cursor chatkit.Cursor) bool {
+		return cursor.CursorType == key.cursorType &&
+			cursor.RoomID == params[1] &&
+			cursor.UserID > fromUserID
+	})
+	if limit > 0 && uint(len(cursors)) > limit {
+		cursors = cursors[:limit]
+	}
+
+	writeJSON(w, http.StatusOK, cursors)
+}
+
+// findCursors returns the cursors that match, sorted by room and user.
+func (s *FakeServer) findCursors(match func(chatkit.Cursor) bool) []chatkit.Cursor {
+	cursors := []chatkit.Cursor{}
+	for _, cursor := range s.cursors {
+		if match(cursor) {
+			cursors = append(cursors, cursor)
+		}
+	}
+
+	sort.Slice(cursors, func(i, j int) bool {
+		if cursors[i].RoomID != cursors[j].RoomID {
+			return cursors[i].RoomID < cursors[j].RoomID
+		}
+		return cursors[i].UserID < cursors[j].UserID
+	})
+
+	return cursors
+}
@@ -0,0 +1,296 @@
+package chatkittest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+// Credentials of the clients of a FakeServer.
+const (
+	FakeInstanceLocator = "v1:fake:instance"
+	FakeKey             = "key:secret"
+)
+
+// FakeServer is an in-memory fake of the core, cursors and authorizer services of Chatkit, for
+// running tests offline. It keeps users, rooms, messages, roles and cursors, and serves the
+// endpoints a Client calls to manage them. Other endpoints respond with 404.
+//
+//	server := chatkittest.NewFakeServer()
+//	defer server.Close()
+//
+//	client, err := server.NewClient()
+//
+// Tokens are decoded but not verified, and permissions aren't enforced. Gzipped requests are
+// accepted, and responses are gzipped for clients that accept it, as with WithCompression.
+type FakeServer struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	users         map[string]*chatkit.User
+	userIDs       []string // In the order the users were created
+	rooms         map[string]*chatkit.Room
+	roomIDs       []string // In the order the rooms were created
+	lastRoomID    uint
+	messages      map[string][]*chatkit.MultipartMessage // By room, in the order they were sent
+	lastMessageID uint
+	roles         map[roleKey]*chatkit.Role
+	userRoles     map[string][]roleAssignment // By user
+	cursors       map[cursorKey]chatkit.Cursor
+}
+
+// NewFakeServer starts a FakeServer with no state. It must be closed when done with.
+func NewFakeServer() *FakeServer {
+	s := &FakeServer{}
+	s.reset()
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Reset deletes all the state of the server.
+func (s *FakeServer) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.reset()
+}
+
+func (s *FakeServer) reset() {
+	s.users = map[string]*chatkit.User{}
+	s.userIDs = nil
+	s.rooms = map[string]*chatkit.Room{}
+	s.roomIDs = nil
+	s.lastRoomID = 0
+	s.messages = map[string][]*chatkit.MultipartMessage{}
+	s.lastMessageID = 0
+	s.roles = map[roleKey]*chatkit.Role{}
+	s.userRoles = map[string][]roleAssignment{}
+	s.cursors = map[cursorKey]chatkit.Cursor{}
+}
+
+// ClientOption returns an option that makes a Client send its requests to the server instead of
+// Chatkit.
+func (s *FakeServer) ClientOption() chatkit.ClientOption {
+	target, _ := url.Parse(s.URL)
+	return chatkit.WithHTTPClient(&http.Client{
+		Transport: redirectTransport{target: target, transport: s.Client().Transport},
+	})
+}
+
+// NewClient returns a Client of the server, created with options.
+func (s *FakeServer) NewClient(options ...chatkit.ClientOption) (*chatkit.Client, error) {
+	options = append(options[:len(options):len(options)], s.ClientOption())
+	return chatkit.NewClient(FakeInstanceLocator, FakeKey, options...)
+}
+
+// redirectTransport sends requests to a target server, whatever their host.
+type redirectTransport struct {
+	target    *url.URL
+	transport http.RoundTripper
+}
+
+func (t redirectTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	redirected := *request
+	target := *request.URL
+	target.Scheme, target.Host = t.target.Scheme, t.target.Host
+	redirected.URL, redirected.Host = &target, ""
+
+	return t.transport.RoundTrip(&redirected)
+}
+
+// fakeRequest is a request to a service of the server.
+type fakeRequest struct {
+	*http.Request
+	service string
+	version string
+	path    []string // Segments of the path following the instance ID, unescaped
+	claims  tokenClaims
+}
+
+type tokenClaims struct {
+	Sub string `json:"sub"`
+	Su  bool   `json:"su"`
+}
+
+// fakeRoute is an endpoint of the server. Segments of its pattern that are "*" match any
+// segment, and are passed to its handler as params.
+type fakeRoute struct {
+	method  string
+	pattern string
+	handle  func(s *FakeServer, w http.ResponseWriter, r *fakeRequest, params []string)
+}
+
+func (route fakeRoute) match(r *fakeRequest) ([]string, bool) {
+	if r.Method != route.method {
+		return nil, false
+	}
+
+	pattern := strings.Split(route.pattern, "/")
+	if len(pattern) != len(r.path) {
+		return nil, false
+	}
+
+	var params []string
+	for i, segment := range pattern {
+		switch segment {
+		case "*":
+			params = append(params, r.path[i])
+		case r.path[i]:
+		default:
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+func (s *FakeServer) serveHTTP(w http.ResponseWriter, httpRequest *http.Request) {
+	// Paths are /services/{service}/{version}/{instance_id}/...
+	segments := strings.Split(strings.Trim(httpRequest.URL.EscapedPath(), "/"), "/")
+	if len(segments) < 4 || segments[0] != "services" {
+		writeError(w, http.StatusNotFound, "services/chatkit/not_found/route_not_found", "Route not found")
+		return
+	}
+
+	if httpRequest.Header.Get("Content-Encoding") == "gzip" {
+		body, err := gzip.NewReader(httpRequest.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "services/chatkit/bad_request/invalid_body", err.Error())
+			return
+		}
+		httpRequest.Body = body
+	}
+
+	if strings.Contains(httpRequest.Header.Get("Accept-Encoding"), "gzip") {
+		recorder := httptest.NewRecorder()
+		defer writeGzipped(w, recorder)
+		w = recorder
+	}
+
+	r := &fakeRequest{Request: httpRequest, service: segments[1], version: segments[2]}
+	for _, segment := range segments[4:] {
+		if value, err := url.PathUnescape(segment); err == nil {
+			segment = value
+		}
+		r.path = append(r.path, segment)
+	}
+
+	claims, err := parseClaims(httpRequest.Header.Get("Authorization"))
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "services/chatkit/unauthorized", err.Error())
+		return
+	}
+	r.claims = claims
+
+	var routes []fakeRoute
+	switch r.service {
+	case "chatkit":
+		routes = coreRoutes
+	case "chatkit_authorizer":
+		routes = authorizerRoutes
+	case "chatkit_cursors":
+		routes = cursorsRoutes
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, route := range routes {
+		if params, ok := route.match(r); ok {
+			route.handle(s, w, r, params)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "services/chatkit/not_found/route_not_found", "Route not found")
+}
+
+// writeGzipped writes the response recorded by recorder to w, with its body gzipped.
+func writeGzipped(w http.ResponseWriter, recorder *httptest.ResponseRecorder) {
+	for name, values := range recorder.Header() {
+		w.Header()[name] = values
+	}
+
+	body := recorder.Body.Bytes()
+	if len(body) > 0 {
+		var compressed bytes.Buffer
+		writer := gzip.NewWriter(&compressed)
+		writer.Write(body)
+		writer.Close()
+
+		body = compressed.Bytes()
+		w.Header().Set("Content-Encoding", "gzip")
+	}
+
+	w.WriteHeader(recorder.Code)
+	w.Write(body)
+}
+
+// parseClaims decodes the claims of the bearer token in an Authorization header, without
+// verifying it.
+func parseClaims(authorization string) (tokenClaims, error) {
+	if !strings.HasPrefix(authorization, "Bearer ") {
+		return tokenClaims{}, errors.New("Missing bearer token")
+	}
+
+	parts := strings.Split(strings.TrimPrefix(authorization, "Bearer "), ".")
+	if len(parts) != 3 {
+		return tokenClaims{}, errors.New("Malformed token")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return tokenClaims{}, fmt.Errorf("Malformed token payload: %v", err)
+	}
+
+	var claims tokenClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return tokenClaims{}, fmt.Errorf("Malformed token claims: %v", err)
+	}
+
+	return claims, nil
+}
+
+// decode decodes the body of a request into dest, and responds with 400 if it can't.
+func (r *fakeRequest) decode(w http.ResponseWriter, dest interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dest); err != nil {
+		writeError(
+			w,
+			http.StatusBadRequest,
+			"services/chatkit/bad_request/invalid_json_body",
+			fmt.Sprintf("Failed to decode request body: %v", err),
+		)
+		return false
+	}
+
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+func writeError(w http.ResponseWriter, status int, errorType string, description string) {
+	writeJSON(w, status, map[string]string{
+		"error":             errorType,
+		"error_description": description,
+	})
+}
+
+// now returns the time resources are created or updated at, as Chatkit would encode it.
+func now() time.Time {
+	return time.Now().UTC()
+}
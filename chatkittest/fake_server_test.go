@@ -0,0 +1,119 @@
+package chatkittest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+func newFakeServerClient(t *testing.T, options ...chatkit.ClientOption) (*FakeServer, *chatkit.Client) {
+	server := NewFakeServer()
+	client, err := server.NewClient(options...)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+
+	return server, client
+}
+
+func expectStatus(t *testing.T, err error, status int) {
+	t.Helper()
+
+	errorResponse, ok := err.(*chatkit.ErrorResponse)
+	if !ok || errorResponse.Status != status {
+		t.Errorf("Expected an error response with status %d, got %v", status, err)
+	}
+}
+
+func TestFakeServerReset(t *testing.T) {
+	ctx := context.Background()
+	server, client := newFakeServerClient(t)
+	defer server.Close()
+
+	if err := client.Users().CreateUser(ctx, chatkit.CreateUserOptions{ID: "alice", Name: "Alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Users().GetUser(ctx, "alice"); err != nil {
+		t.Fatalf("Expected alice to exist, got %v", err)
+	}
+
+	server.Reset()
+
+	_, err := client.Users().GetUser(ctx, "alice")
+	expectStatus(t, err, http.StatusNotFound)
+}
+
+func TestFakeServerCompression(t *testing.T) {
+	ctx := context.Background()
+	server, client := newFakeServerClient(t, chatkit.WithCompression(0))
+	defer server.Close()
+
+	user, err := client.Users().CreateUserAndGet(ctx, chatkit.CreateUserOptions{ID: "alice", Name: "Alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Alice" {
+		t.Errorf("Expected alice to be created from a gzipped request, got %+v", user)
+	}
+
+	room, err := client.Rooms().CreateRoom(ctx, chatkit.CreateRoomOptions{Name: "general", CreatorID: "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Messages().SendSimpleMessage(ctx, chatkit.SendSimpleMessageOptions{
+		RoomID:   room.ID,
+		SenderID: "alice",
+		Text:     "hello",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	messages, err := client.Messages().FetchMultipartMessages(ctx, room.ID, chatkit.GetRoomMessagesOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != 1 || *messages[0].Parts[0].Content != "hello" {
+		t.Errorf("Expected the message to be read from a gzipped response, got %+v", messages)
+	}
+}
+
+func TestFakeServerRequiresToken(t *testing.T) {
+	server := NewFakeServer()
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/services/chatkit/v6/instance/users/alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Expected a request without a token to be refused with 401, got %d", response.StatusCode)
+	}
+}
+
+func TestFakeServerUnknownRoute(t *testing.T) {
+	ctx := context.Background()
+	server, client := newFakeServerClient(t)
+	defer server.Close()
+
+	token, err := client.Auth().GenerateSUToken(chatkit.AuthenticateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	response, err := client.CoreRequest(ctx, chatkit.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "/no_such_route",
+		Jwt:    &token.Token,
+	})
+	if response != nil {
+		response.Body.Close()
+	}
+	expectStatus(t, err, http.StatusNotFound)
+}
@@ -0,0 +1,413 @@
+//go:build ignore
+// +build ignore
+
+// gen_mock generates mock_client.go, a MockClient implementing every method of chatkit.API,
+// including those of the sub-clients it returns.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const chatkitPath = "github.com/pusher/chatkit-server-go"
+
+// Names used by the generated methods, which parameters are renamed to avoid.
+var reservedNames = map[string]bool{"m": true, "sub": true, "returns": true, "ok": true}
+
+type param struct {
+	name string
+	typ  string
+}
+
+type method struct {
+	name    string
+	params  []param
+	results []string
+	client  string // Name of the sub-client accessor the method belongs to, if any, e.g. Users
+}
+
+// subClient is an accessor of chatkit.API returning a sub-client interface, e.g. Users.
+type subClient struct {
+	name  string // Name of the accessor
+	iface string // Name of the interface it returns
+}
+
+// qualifiedName is the name the method's calls are recorded and expected with, e.g.
+// Users.GetUser for the GetUser method of the Users sub-client.
+func (m method) qualifiedName() string {
+	if m.client == "" {
+		return m.name
+	}
+
+	return m.client + "." + m.name
+}
+
+// funcField is the name of the MockClient field the method calls if set, e.g. UsersGetUserFunc.
+func (m method) funcField() string {
+	return m.client + m.name + "Func"
+}
+
+func main() {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "../api.go", nil, 0)
+	if err != nil {
+		log.Fatalf("Failed to parse api.go: %v", err)
+	}
+
+	imports := map[string]string{"chatkit": chatkitPath}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = path
+	}
+
+	api := findInterface(file, "API")
+	if api == nil {
+		log.Fatal("Failed to find the API interface in api.go")
+	}
+
+	used := map[string]bool{"chatkit": true}
+	typeString := func(expr ast.Expr) string {
+		return types.ExprString(qualify(expr, used))
+	}
+
+	var (
+		methods    []method
+		subClients []subClient
+	)
+	methods = interfaceMethods(file, api, "", typeString)
+	for _, field := range api.Methods.List {
+		if sub, ok := subClientOf(file, field); ok {
+			subClients = append(subClients, sub)
+			methods = append(methods, interfaceMethods(file, findInterface(file, sub.iface), sub.name, typeString)...)
+		}
+	}
+
+	var buf bytes.Buffer
+	writeFile(&buf, methods, subClients, imports, used)
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		log.Fatalf("Failed to format mock_client.go: %v\n%s", err, buf.Bytes())
+	}
+
+	if err := ioutil.WriteFile("mock_client.go", src, 0644); err != nil {
+		log.Fatalf("Failed to write mock_client.go: %v", err)
+	}
+}
+
+// subClientOf returns the sub-client a method of chatkit.API returns, if it is an accessor
+// without parameters returning an interface of api.go.
+func subClientOf(file *ast.File, field *ast.Field) (subClient, bool) {
+	fn := field.Type.(*ast.FuncType)
+	if len(fn.Params.List) != 0 || fn.Results == nil || len(fn.Results.List) != 1 {
+		return subClient{}, false
+	}
+
+	result, ok := fn.Results.List[0].Type.(*ast.Ident)
+	if !ok || findInterface(file, result.Name) == nil {
+		return subClient{}, false
+	}
+
+	return subClient{name: field.Names[0].Name, iface: result.Name}, true
+}
+
+// interfaceMethods returns the methods of an interface, other than sub-client accessors, as
+// methods of the given sub-client, if any.
+func interfaceMethods(
+	file *ast.File,
+	iface *ast.InterfaceType,
+	client string,
+	typeString func(ast.Expr) string,
+) []method {
+	var methods []method
+	for _, field := range iface.Methods.List {
+		if _, ok := subClientOf(file, field); ok {
+			continue
+		}
+
+		fn := field.Type.(*ast.FuncType)
+		m := method{name: field.Names[0].Name, client: client}
+
+		for _, p := range fn.Params.List {
+			typ := typeString(p.Type)
+			if len(p.Names) == 0 {
+				m.params = append(m.params, param{name: fmt.Sprintf("p%d", len(m.params)), typ: typ})
+			}
+			for _, name := range p.Names {
+				n := name.Name
+				if reservedNames[n] {
+					n += "_"
+				}
+				m.params = append(m.params, param{name: n, typ: typ})
+			}
+		}
+
+		if fn.Results != nil {
+			for _, r := range fn.Results.List {
+				typ := typeString(r.Type)
+				for i := 0; i < len(r.Names) || (i == 0 && len(r.Names) == 0); i++ {
+					m.results = append(m.results, typ)
+				}
+			}
+		}
+
+		methods = append(methods, m)
+	}
+
+	return methods
+}
+
+func findInterface(file *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == name {
+				iface, _ := ts.Type.(*ast.InterfaceType)
+				return iface
+			}
+		}
+	}
+
+	return nil
+}
+
+// qualify returns a type of the chatkit package qualified with its name, and records the
+// packages it refers to in used.
+func qualify(expr ast.Expr, used map[string]bool) ast.Expr {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if ast.IsExported(e.Name) {
+			return &ast.SelectorExpr{X: ast.NewIdent("chatkit"), Sel: e}
+		}
+		return e
+	case *ast.SelectorExpr:
+		used[e.X.(*ast.Ident).Name] = true
+		return e
+	case *ast.StarExpr:
+		return &ast.StarExpr{X: qualify(e.X, used)}
+	case *ast.ArrayType:
+		return &ast.ArrayType{Len: e.Len, Elt: qualify(e.Elt, used)}
+	case *ast.MapType:
+		return &ast.MapType{Key: qualify(e.Key, used), Value: qualify(e.Value, used)}
+	case *ast.ChanType:
+		return &ast.ChanType{Dir: e.Dir, Value: qualify(e.Value, used)}
+	case *ast.Ellipsis:
+		return &ast.Ellipsis{Elt: qualify(e.Elt, used)}
+	case *ast.FuncType:
+		return &ast.FuncType{Params: qualifyFields(e.Params, used), Results: qualifyFields(e.Results, used)}
+	default:
+		return e
+	}
+}
+
+func qualifyFields(fields *ast.FieldList, used map[string]bool) *ast.FieldList {
+	if fields == nil {
+		return nil
+	}
+
+	qualified := &ast.FieldList{}
+	for _, f := range fields.List {
+		qualified.List = append(qualified.List, &ast.Field{Names: f.Names, Type: qualify(f.Type, used)})
+	}
+
+	return qualified
+}
+
+func writeFile(
+	buf *bytes.Buffer,
+	methods []method,
+	subClients []subClient,
+	imports map[string]string,
+	used map[string]bool,
+) {
+	fmt.Fprintln(buf, "// Code generated by gen_mock.go; DO NOT EDIT.")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "package chatkittest")
+	fmt.Fprintln(buf)
+
+	var std, other []string
+	for name := range used {
+		path := imports[name]
+		spec := strconv.Quote(path)
+		if name != path[strings.LastIndex(path, "/")+1:] {
+			spec = name + " " + spec
+		}
+		if strings.Contains(path, ".") {
+			other = append(other, spec)
+		} else {
+			std = append(std, spec)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	fmt.Fprintln(buf, "import (")
+	for _, spec := range std {
+		fmt.Fprintln(buf, spec)
+	}
+	fmt.Fprintln(buf)
+	for _, spec := range other {
+		fmt.Fprintln(buf, spec)
+	}
+	fmt.Fprintln(buf, ")")
+	fmt.Fprintln(buf)
+
+	fmt.Fprintln(buf, `// MockClient is a mock of chatkit.API. Each of its methods calls its function field, e.g.
+// GetUserFunc for GetUser, if it is set, or else returns the values of the first expectation
+// its arguments match. Unexpected calls return zero values, and an error if their method
+// returns one, and are reported by AssertExpectations.
+//
+// The sub-clients it returns, such as Users(), are mocked by the MockClient too: their calls are
+// expected and recorded with the name of their sub-client, e.g. "Users.GetUser", and call the
+// function fields named after it, e.g. UsersGetUserFunc.
+type MockClient struct {
+	Mock
+`)
+	for _, m := range methods {
+		fmt.Fprintf(buf, "%s %s\n", m.funcField(), m.signature())
+	}
+	fmt.Fprintln(buf, "}")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "var _ chatkit.API = (*MockClient)(nil)")
+
+	for _, sub := range subClients {
+		fmt.Fprintln(buf)
+		fmt.Fprintf(buf, "// %s is the %s sub-client of a MockClient.\n", sub.mockType(), sub.name)
+		fmt.Fprintf(buf, "type %s struct {\nm *MockClient\n}\n\n", sub.mockType())
+		fmt.Fprintf(buf, "// %s returns the mock of the %s sub-client.\n", sub.name, sub.name)
+		fmt.Fprintf(buf, "func (m *MockClient) %s() chatkit.%s {\n", sub.name, sub.iface)
+		fmt.Fprintf(buf, "return %s{m: m}\n}\n", sub.mockType())
+	}
+
+	for _, m := range methods {
+		fmt.Fprintln(buf)
+		m.write(buf)
+	}
+}
+
+// mockType is the name of the type mocking the sub-client, e.g. mockUsers.
+func (s subClient) mockType() string {
+	return "mock" + s.name
+}
+
+func (m method) paramList() string {
+	params := make([]string, len(m.params))
+	for i, p := range m.params {
+		params[i] = p.name + " " + p.typ
+	}
+
+	return strings.Join(params, ", ")
+}
+
+func (m method) resultList() string {
+	switch len(m.results) {
+	case 0:
+		return ""
+	case 1:
+		return " " + m.results[0]
+	default:
+		return " (" + strings.Join(m.results, ", ") + ")"
+	}
+}
+
+func (m method) signature() string {
+	return "func(" + m.paramList() + ")" + m.resultList()
+}
+
+// recordedArgs returns the arguments a call is recorded with, which leave out its context.
+func (m method) recordedArgs() string {
+	args := []string{strconv.Quote(m.qualifiedName())}
+	for _, p := range m.params {
+		if p.typ != "context.Context" {
+			args = append(args, p.name)
+		}
+	}
+
+	return strings.Join(args, ", ")
+}
+
+// calledArgs returns the arguments of Mock.called for a call.
+func (m method) calledArgs() string {
+	args := []string{strconv.Quote(m.qualifiedName()), strconv.Itoa(len(m.results))}
+	for _, p := range m.params {
+		if p.typ != "context.Context" {
+			args = append(args, p.name)
+		}
+	}
+
+	return strings.Join(args, ", ")
+}
+
+func (m method) write(buf *bytes.Buffer) {
+	names := make([]string, len(m.params))
+	for i, p := range m.params {
+		names[i] = p.name
+	}
+
+	if m.client == "" {
+		fmt.Fprintf(buf, "func (m *MockClient) %s(%s)%s {\n", m.name, m.paramList(), m.resultList())
+	} else {
+		fmt.Fprintf(buf, "func (sub mock%s) %s(%s)%s {\n", m.client, m.name, m.paramList(), m.resultList())
+		fmt.Fprintln(buf, "m := sub.m")
+	}
+
+	fmt.Fprintf(buf, "if m.%s != nil {\n", m.funcField())
+	fmt.Fprintf(buf, "m.record(%s)\n", m.recordedArgs())
+	if len(m.results) > 0 {
+		fmt.Fprint(buf, "return ")
+	}
+	fmt.Fprintf(buf, "m.%s(%s)\n", m.funcField(), strings.Join(names, ", "))
+	if len(m.results) == 0 {
+		fmt.Fprintln(buf, "return")
+	}
+	fmt.Fprintln(buf, "}")
+	fmt.Fprintln(buf)
+
+	if len(m.results) == 0 {
+		fmt.Fprintf(buf, "m.called(%s)\n", m.calledArgs())
+		fmt.Fprintln(buf, "}")
+		return
+	}
+
+	results := make([]string, len(m.results))
+	for i, typ := range m.results {
+		results[i] = fmt.Sprintf("r%d", i)
+		fmt.Fprintf(buf, "var %s %s\n", results[i], typ)
+	}
+	fmt.Fprintf(buf, "returns, ok := m.called(%s)\n", m.calledArgs())
+
+	fmt.Fprintln(buf, "if !ok {")
+	if last := len(m.results) - 1; m.results[last] == "error" {
+		fmt.Fprintf(buf, "%s = unexpectedCall(%s)\n", results[last], m.recordedArgs())
+	}
+	fmt.Fprintf(buf, "return %s\n", strings.Join(results, ", "))
+	fmt.Fprintln(buf, "}")
+
+	for i, typ := range m.results {
+		fmt.Fprintf(buf, "if returns[%d] != nil {\n", i)
+		fmt.Fprintf(buf, "%s = returns[%d].(%s)\n", results[i], i, typ)
+		fmt.Fprintln(buf, "}")
+	}
+
+	fmt.Fprintf(buf, "return %s\n", strings.Join(results, ", "))
+	fmt.Fprintln(buf, "}")
+}
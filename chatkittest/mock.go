@@ -0,0 +1,241 @@
+// Package chatkittest provides test doubles of the Chatkit client, for testing code that
+// depends on chatkit.API without making calls to Chatkit.
+package chatkittest
+
+//go:generate go run gen_mock.go
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Anything matches any argument in an expectation.
+var Anything interface{} = anything{}
+
+type anything struct{}
+
+// Matcher matches the arguments for which its function returns true in an expectation.
+type Matcher struct {
+	match func(arg interface{}) bool
+}
+
+// MatchedBy returns a Matcher that matches the arguments for which match returns true.
+func MatchedBy(match func(arg interface{}) bool) Matcher {
+	return Matcher{match: match}
+}
+
+// TestingT is the subset of testing.TB that assertions report failures with.
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Call is a call made to a mock.
+type Call struct {
+	Method string
+	Args   []interface{} // Arguments of the call, except its context
+}
+
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = fmt.Sprintf("%#v", arg)
+	}
+
+	return c.Method + "(" + strings.Join(args, ", ") + ")"
+}
+
+// Expectation is a call a mock expects, and the values it returns when it is made.
+type Expectation struct {
+	mock    *Mock
+	method  string
+	args    []interface{}
+	returns []interface{}
+	times   int // Number of calls expected, or 0 for at least one
+	calls   int
+}
+
+// Return sets the values returned by the calls matching the expectation, one per result of its
+// method. A nil value returns the zero value of its result.
+func (e *Expectation) Return(values ...interface{}) *Expectation {
+	e.mock.mu.Lock()
+	defer e.mock.mu.Unlock()
+
+	e.returns = values
+	return e
+}
+
+// Times sets the number of calls expected, after which the expectation no longer matches.
+func (e *Expectation) Times(n int) *Expectation {
+	e.mock.mu.Lock()
+	defer e.mock.mu.Unlock()
+
+	e.times = n
+	return e
+}
+
+// Once expects a single call.
+func (e *Expectation) Once() *Expectation {
+	return e.Times(1)
+}
+
+func (e *Expectation) matches(method string, args []interface{}) bool {
+	if e.method != method || (e.times > 0 && e.calls >= e.times) {
+		return false
+	}
+
+	// Expectations without arguments match every call to their method.
+	if len(e.args) == 0 {
+		return true
+	}
+	if len(e.args) != len(args) {
+		return false
+	}
+
+	for i, expected := range e.args {
+		switch expected := expected.(type) {
+		case anything:
+		case Matcher:
+			if !expected.match(args[i]) {
+				return false
+			}
+		default:
+			if !reflect.DeepEqual(expected, args[i]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func (e *Expectation) satisfied() bool {
+	if e.times > 0 {
+		return e.calls == e.times
+	}
+
+	return e.calls > 0
+}
+
+// Mock records the calls made to a mock, and matches them against its expectations.
+type Mock struct {
+	mu           sync.Mutex
+	expectations []*Expectation
+	calls        []Call
+	unexpected   []Call
+}
+
+// On adds an expectation of a call to method with args, which may include Anything and
+// Matchers. Context arguments are left out. Calls are matched against the expectations in the
+// order they were added.
+func (m *Mock) On(method string, args ...interface{}) *Expectation {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	e := &Expectation{mock: m, method: method, args: args}
+	m.expectations = append(m.expectations, e)
+	return e
+}
+
+// Calls returns the calls made to the mock, in order.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]Call(nil), m.calls...)
+}
+
+// AssertExpectations reports every expectation that wasn't met, and every call that matched
+// none, and returns whether there were none.
+func (m *Mock) AssertExpectations(t TestingT) bool {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ok := true
+	for _, e := range m.expectations {
+		if !e.satisfied() {
+			call := Call{Method: e.method, Args: e.args}
+			if e.times > 0 {
+				t.Errorf("Expected %d calls to %s, got %d", e.times, call, e.calls)
+			} else {
+				t.Errorf("Expected a call to %s", call)
+			}
+			ok = false
+		}
+	}
+
+	for _, call := range m.unexpected {
+		t.Errorf("Unexpected call to %s", call)
+		ok = false
+	}
+
+	return ok
+}
+
+// AssertCalled reports a failure unless a call to method with args was made, and returns
+// whether it was.
+func (m *Mock) AssertCalled(t TestingT, method string, args ...interface{}) bool {
+	t.Helper()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	expected := Expectation{method: method, args: args}
+	for _, call := range m.calls {
+		if expected.matches(call.Method, call.Args) {
+			return true
+		}
+	}
+
+	t.Errorf("Expected a call to %s", Call{Method: method, Args: args})
+	return false
+}
+
+// record records a call answered without an expectation.
+func (m *Mock) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.calls = append(m.calls, Call{Method: method, Args: args})
+}
+
+// called records a call, and returns the values of the first expectation it matches, if any.
+func (m *Mock) called(method string, results int, args ...interface{}) ([]interface{}, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	call := Call{Method: method, Args: args}
+	m.calls = append(m.calls, call)
+
+	for _, e := range m.expectations {
+		if !e.matches(method, args) {
+			continue
+		}
+
+		if e.returns == nil {
+			e.returns = make([]interface{}, results)
+		}
+		if len(e.returns) != results {
+			panic(fmt.Sprintf(
+				"chatkittest: expectation of %s returns %d values, want %d",
+				call,
+				len(e.returns),
+				results,
+			))
+		}
+
+		e.calls++
+		return e.returns, true
+	}
+
+	m.unexpected = append(m.unexpected, call)
+	return nil, false
+}
+
+func unexpectedCall(method string, args ...interface{}) error {
+	return fmt.Errorf("Unexpected call to %s", Call{Method: method, Args: args})
+}
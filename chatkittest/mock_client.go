@@ -0,0 +1,5496 @@
+// Code generated by gen_mock.go; DO NOT EDIT.
+
+package chatkittest
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+	"github.com/pusher/chatkit-server-go/internal/core"
+	"github.com/pusher/pusher-platform-go/auth"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// MockClient is a mock of chatkit.API. Each of its methods calls its function field, e.g.
+// GetUserFunc for GetUser, if it is set, or else returns the values of the first expectation
+// its arguments match. Unexpected calls return zero values, and an error if their method
+// returns one, and are reported by AssertExpectations.
+//
+// The sub-clients it returns, such as Users(), are mocked by the MockClient too: their calls are
+// expected and recorded with the name of their sub-client, e.g. "Users.GetUser", and call the
+// function fields named after it, e.g. UsersGetUserFunc.
+type MockClient struct {
+	Mock
+
+	GetUserReadCursorsFunc                  func(ctx context.Context, userID string) ([]chatkit.Cursor, error)
+	SetReadCursorFunc                       func(ctx context.Context, userID string, roomID string, position uint) error
+	GetReadCursorsForRoomFunc               func(ctx context.Context, roomID string) ([]chatkit.Cursor, error)
+	GetReadCursorsForRoomPageFunc           func(ctx context.Context, roomID string, options chatkit.GetReadCursorsForRoomOptions) ([]chatkit.Cursor, error)
+	GetReadCursorFunc                       func(ctx context.Context, userID string, roomID string) (chatkit.Cursor, error)
+	DeleteReadCursorFunc                    func(ctx context.Context, userID string, roomID string) error
+	GetUserCursorsFunc                      func(ctx context.Context, cursorType uint, userID string) ([]chatkit.Cursor, error)
+	SetCursorFunc                           func(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error
+	GetCursorsForRoomFunc                   func(ctx context.Context, cursorType uint, roomID string) ([]chatkit.Cursor, error)
+	GetCursorFunc                           func(ctx context.Context, cursorType uint, userID string, roomID string) (chatkit.Cursor, error)
+	DeleteCursorFunc                        func(ctx context.Context, cursorType uint, userID string, roomID string) error
+	CursorsRequestFunc                      func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error)
+	CursorsSubscribeFunc                    func(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error)
+	GetUserPresenceFunc                     func(ctx context.Context, userID string) (chatkit.UserPresence, error)
+	GetUsersPresenceFunc                    func(ctx context.Context, userIDs []string) ([]chatkit.UserPresence, error)
+	PresenceRequestFunc                     func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error)
+	PresenceSubscribeFunc                   func(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error)
+	GetRolesFunc                            func(ctx context.Context) ([]chatkit.Role, error)
+	CreateGlobalRoleFunc                    func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	CreateRoomRoleFunc                      func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	DeleteGlobalRoleFunc                    func(ctx context.Context, roleName string) error
+	DeleteRoomRoleFunc                      func(ctx context.Context, roleName string) error
+	GetPermissionsForGlobalRoleFunc         func(ctx context.Context, roleName string) ([]string, error)
+	GetPermissionsForRoomRoleFunc           func(ctx context.Context, roleName string) ([]string, error)
+	UpdatePermissionsForGlobalRoleFunc      func(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error
+	UpdatePermissionsForRoomRoleFunc        func(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error
+	GetUserRolesFunc                        func(ctx context.Context, userID string) ([]chatkit.Role, error)
+	AssignGlobalRoleToUserFunc              func(ctx context.Context, userID string, roleName string) error
+	AssignRoomRoleToUserFunc                func(ctx context.Context, userID string, roomID string, roleName string) error
+	RemoveGlobalRoleForUserFunc             func(ctx context.Context, userID string) error
+	RemoveRoomRoleForUserFunc               func(ctx context.Context, userID string, roomID string) error
+	AuthorizerRequestFunc                   func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error)
+	AuthorizerSubscribeFunc                 func(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error)
+	GetUserFunc                             func(ctx context.Context, userID string) (chatkit.User, error)
+	GetUsersFunc                            func(ctx context.Context, options *chatkit.GetUsersOptions) ([]chatkit.User, error)
+	GetUsersByIDFunc                        func(ctx context.Context, userIDs []string) ([]chatkit.User, error)
+	CreateUserFunc                          func(ctx context.Context, options chatkit.CreateUserOptions) error
+	CreateUserAndGetFunc                    func(ctx context.Context, options chatkit.CreateUserOptions) (chatkit.User, error)
+	CreateUsersFunc                         func(ctx context.Context, users []chatkit.CreateUserOptions) error
+	UpdateUserFunc                          func(ctx context.Context, userID string, options chatkit.UpdateUserOptions) error
+	DeleteUserFunc                          func(ctx context.Context, userID string) error
+	GetRoomFunc                             func(ctx context.Context, roomID string) (chatkit.Room, error)
+	GetRoomsFunc                            func(ctx context.Context, options chatkit.GetRoomsOptions) ([]core.RoomWithoutMembers, error)
+	GetUserRoomsFunc                        func(ctx context.Context, userID string) ([]chatkit.Room, error)
+	GetUserJoinableRoomsFunc                func(ctx context.Context, userID string) ([]chatkit.Room, error)
+	CreateRoomFunc                          func(ctx context.Context, options chatkit.CreateRoomOptions) (chatkit.Room, error)
+	UpdateRoomFunc                          func(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) error
+	UpdateRoomAndGetFunc                    func(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) (chatkit.Room, error)
+	DeleteRoomFunc                          func(ctx context.Context, roomID string) error
+	AsyncDeleteRoomFunc                     func(ctx context.Context, roomID string) (string, error)
+	GetDeleteStatusFunc                     func(ctx context.Context, jobID string) (chatkit.DeleteStatus, error)
+	AddUsersToRoomFunc                      func(ctx context.Context, roomID string, userIDs []string) error
+	RemoveUsersFromRoomFunc                 func(ctx context.Context, roomID string, userIDs []string) error
+	JoinRoomFunc                            func(ctx context.Context, roomID string, userID string) (chatkit.Room, error)
+	LeaveRoomFunc                           func(ctx context.Context, roomID string, userID string) error
+	SendMessageFunc                         func(ctx context.Context, options chatkit.SendMessageOptions) (uint, error)
+	SendMultipartMessageFunc                func(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error)
+	SendMessageAsServiceFunc                func(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error)
+	SendSimpleMessageFunc                   func(ctx context.Context, options chatkit.SendSimpleMessageOptions) (uint, error)
+	GetRoomMessagesFunc                     func(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.Message, error)
+	FetchMultipartMessageFunc               func(ctx context.Context, options chatkit.FetchMultipartMessageOptions) (chatkit.MultipartMessage, error)
+	FetchMultipartMessagesFunc              func(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.MultipartMessage, error)
+	DeleteMessageFunc                       func(ctx context.Context, options chatkit.DeleteMessageOptions) error
+	EditMessageFunc                         func(ctx context.Context, roomID string, messageID uint, options chatkit.EditMessageOptions) error
+	EditMultipartMessageFunc                func(ctx context.Context, roomID string, messageID uint, options chatkit.EditMultipartMessageOptions) error
+	EditSimpleMessageFunc                   func(ctx context.Context, roomID string, messageID uint, options chatkit.EditSimpleMessageOptions) error
+	CoreRequestFunc                         func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error)
+	CoreSubscribeFunc                       func(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error)
+	AuthenticateFunc                        func(payload auth.Payload, options auth.Options) (*auth.Response, error)
+	GenerateAccessTokenFunc                 func(options auth.Options) (auth.TokenWithExpiry, error)
+	GenerateSUTokenFunc                     func(options auth.Options) (auth.TokenWithExpiry, error)
+	VerifyTokenFunc                         func(ctx context.Context, tokenString string) (chatkit.Claims, error)
+	RefreshAttachmentFunc                   func(ctx context.Context, att chatkit.Attachment) (chatkit.Attachment, error)
+	DownloadAttachmentFunc                  func(ctx context.Context, att chatkit.Attachment, w io.Writer) error
+	GetUnreadCountsFunc                     func(ctx context.Context, userID string) (map[string]chatkit.UnreadCount, error)
+	IterateRoomReadCursorsFunc              func(ctx context.Context, roomID string, options chatkit.IterateRoomReadCursorsOptions) *chatkit.CursorsIterator
+	GetMessageReadByFunc                    func(ctx context.Context, roomID string, messageID uint) (chatkit.MessageReadBy, error)
+	SetDebugHTTPFunc                        func(w io.Writer)
+	ExportUsersFunc                         func(ctx context.Context, w io.Writer, format chatkit.ExportFormat) error
+	UsersNDJSONFunc                         func(ctx context.Context, w io.Writer) error
+	RoomsNDJSONFunc                         func(ctx context.Context, w io.Writer, options chatkit.IterateRoomsOptions) error
+	RoomMessagesNDJSONFunc                  func(ctx context.Context, w io.Writer, roomID string) error
+	ExportRoomMessagesFunc                  func(ctx context.Context, roomID string, w io.Writer, format chatkit.ExportFormat, options chatkit.ExportRoomMessagesOptions) error
+	RolesNDJSONFunc                         func(ctx context.Context, w io.Writer) error
+	RoomReadCursorsNDJSONFunc               func(ctx context.Context, w io.Writer, roomID string) error
+	UserReadCursorsNDJSONFunc               func(ctx context.Context, w io.Writer, userID string) error
+	IterateRoomMessagesFunc                 func(ctx context.Context, roomID string, options chatkit.IterateRoomMessagesOptions) *chatkit.MessageIterator
+	GetMessagesFunc                         func(ctx context.Context, roomID string, options chatkit.GetMessagesOptions) ([]chatkit.Message, error)
+	FetchLatestMessagesForRoomsFunc         func(ctx context.Context, roomIDs []string, limit uint) (map[string][]chatkit.MultipartMessage, error)
+	DeleteMessagesFunc                      func(ctx context.Context, roomID string, messageIDs []uint, options chatkit.BatchOptions) *chatkit.BatchResult
+	SendMessageAndGetFunc                   func(ctx context.Context, options chatkit.SendMessageOptions) (chatkit.Message, error)
+	SendMultipartMessageAndGetFunc          func(ctx context.Context, options chatkit.SendMultipartMessageOptions) (chatkit.MultipartMessage, error)
+	SendSimpleMessageAndGetFunc             func(ctx context.Context, options chatkit.SendSimpleMessageOptions) (chatkit.MultipartMessage, error)
+	AuthMiddlewareFunc                      func(next http.Handler, options chatkit.VerifierOptions) http.Handler
+	PinMessageFunc                          func(ctx context.Context, roomID string, messageID uint) error
+	UnpinMessageFunc                        func(ctx context.Context, roomID string, messageID uint) error
+	GetPinnedMessagesFunc                   func(ctx context.Context, roomID string) ([]chatkit.MultipartMessage, error)
+	AddReactionFunc                         func(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error
+	RemoveReactionFunc                      func(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error
+	RedactMessageFunc                       func(ctx context.Context, roomID string, messageID uint, replacementText string) error
+	IssueRefreshTokenFunc                   func(ctx context.Context, userID string) (string, error)
+	RefreshAccessTokenFunc                  func(ctx context.Context, refreshToken string, options auth.Options) (chatkit.TokenResponse, error)
+	RevokeRefreshTokenFunc                  func(ctx context.Context, refreshToken string) error
+	RevokeTokenFunc                         func(ctx context.Context, tokenString string) error
+	RevokeTokensForUserFunc                 func(ctx context.Context, userID string) error
+	CreateDefaultRolesFunc                  func(ctx context.Context) error
+	GetRoleFunc                             func(ctx context.Context, name string, scope string) (chatkit.Role, error)
+	UpsertGlobalRoleFunc                    func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	UpsertRoomRoleFunc                      func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	GetEffectivePermissionsFunc             func(ctx context.Context, userID string, roomID string) ([]string, error)
+	AssignGlobalRoleToUsersFunc             func(ctx context.Context, userIDs []string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult
+	AssignRoomRoleToUsersFunc               func(ctx context.Context, userIDs []string, roomID string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult
+	GetUsersWithRoleFunc                    func(ctx context.Context, roleName string, scope string, options chatkit.GetUsersWithRoleOptions) ([]chatkit.RoleAssignment, error)
+	ListRoleAssignmentsFunc                 func(ctx context.Context, options chatkit.ListRoleAssignmentsOptions) *chatkit.RoleAssignmentsIterator
+	ApplyRolesConfigFunc                    func(ctx context.Context, config chatkit.RolesConfig, options chatkit.ApplyRolesConfigOptions) ([]chatkit.RoleChange, error)
+	ExportRolesFunc                         func(ctx context.Context) (chatkit.RolesConfig, error)
+	ImportRolesFunc                         func(ctx context.Context, config chatkit.RolesConfig, options chatkit.ImportRolesOptions) ([]chatkit.RoleChange, error)
+	TransferRoomOwnershipFunc               func(ctx context.Context, roomID string, newOwnerID string, options chatkit.TransferRoomOwnershipOptions) error
+	IterateRoomsFunc                        func(ctx context.Context, options chatkit.IterateRoomsOptions) *chatkit.RoomsIterator
+	CreateDirectRoomFunc                    func(ctx context.Context, userA string, userB string, options chatkit.CreateDirectRoomOptions) (chatkit.Room, error)
+	GetRoomsByIDFunc                        func(ctx context.Context, roomIDs []string) ([]chatkit.Room, error)
+	GetRoomCountsFunc                       func(ctx context.Context, roomID string) (chatkit.RoomCounts, error)
+	WaitForDeleteFunc                       func(ctx context.Context, jobID string, interval time.Duration) (chatkit.DeleteStatus, error)
+	GenerateScopedTokenFunc                 func(ctx context.Context, options chatkit.ScopedTokenOptions) (auth.TokenWithExpiry, error)
+	GenerateReadOnlyTokenFunc               func(ctx context.Context, options chatkit.ReadOnlyTokenOptions) (auth.TokenWithExpiry, error)
+	SearchRoomMessagesFunc                  func(ctx context.Context, roomID string, query string, options chatkit.SearchRoomMessagesOptions) ([]chatkit.MessageSearchResult, error)
+	StatsFunc                               func() chatkit.Stats
+	NewSubscriptionManagerFunc              func(options chatkit.SubscriptionManagerOptions) *chatkit.SubscriptionManager
+	SubscribeToRoomMessagesFunc             func(ctx context.Context, roomID string, options chatkit.SubscribeToRoomMessagesOptions) (<-chan chatkit.MultipartMessage, error)
+	SubscribeToUserEventsFunc               func(ctx context.Context, userID string, options chatkit.SubscriptionOptions) (<-chan chatkit.UserSubscriptionEvent, error)
+	SubscribeToRoomMembershipsFunc          func(ctx context.Context, roomID string, options chatkit.SubscriptionOptions) (<-chan chatkit.MembershipEvent, error)
+	SubscribeToPresenceFunc                 func(ctx context.Context, userIDs []string, options chatkit.SubscriptionOptions) (<-chan chatkit.UserPresence, error)
+	SendSystemMessageFunc                   func(ctx context.Context, options chatkit.SendSystemMessageOptions) (uint, error)
+	FetchThreadFunc                         func(ctx context.Context, roomID string, parentID uint, options chatkit.FetchThreadOptions) ([]chatkit.MultipartMessage, error)
+	TokenProviderHandlerFunc                func(options chatkit.TokenProviderOptions) http.Handler
+	IterateUsersFunc                        func(ctx context.Context, options chatkit.IterateUsersOptions) *chatkit.UsersIterator
+	SearchUsersFunc                         func(ctx context.Context, query string, options chatkit.SearchUsersOptions) *chatkit.UsersIterator
+	UpdateUsersFunc                         func(ctx context.Context, updates map[string]chatkit.UpdateUserOptions, options chatkit.UpdateUsersOptions) *chatkit.BatchResult
+	RenameUserFunc                          func(ctx context.Context, userID string, options chatkit.RenameUserOptions) error
+	UsersGetUserFunc                        func(ctx context.Context, userID string) (chatkit.User, error)
+	UsersGetUsersFunc                       func(ctx context.Context, options *chatkit.GetUsersOptions) ([]chatkit.User, error)
+	UsersCreateUserFunc                     func(ctx context.Context, options chatkit.CreateUserOptions) error
+	UsersCreateUserAndGetFunc               func(ctx context.Context, options chatkit.CreateUserOptions) (chatkit.User, error)
+	UsersCreateUsersFunc                    func(ctx context.Context, users []chatkit.CreateUserOptions) error
+	UsersCreateUsersInBatchesFunc           func(ctx context.Context, users []chatkit.CreateUserOptions, options chatkit.BatchOptions) *chatkit.BatchResult
+	UsersUpdateUserFunc                     func(ctx context.Context, userID string, options chatkit.UpdateUserOptions) error
+	UsersDeleteUserFunc                     func(ctx context.Context, userID string) error
+	UsersIterateUsersFunc                   func(ctx context.Context, options chatkit.IterateUsersOptions) *chatkit.UsersIterator
+	UsersSearchUsersFunc                    func(ctx context.Context, query string, options chatkit.SearchUsersOptions) *chatkit.UsersIterator
+	UsersGetUsersByIDFunc                   func(ctx context.Context, userIDs []string) ([]chatkit.User, error)
+	UsersUpdateUsersFunc                    func(ctx context.Context, updates map[string]chatkit.UpdateUserOptions, options chatkit.UpdateUsersOptions) *chatkit.BatchResult
+	UsersRenameUserFunc                     func(ctx context.Context, userID string, options chatkit.RenameUserOptions) error
+	RoomsGetRoomFunc                        func(ctx context.Context, roomID string) (chatkit.Room, error)
+	RoomsGetRoomsFunc                       func(ctx context.Context, options chatkit.GetRoomsOptions) ([]core.RoomWithoutMembers, error)
+	RoomsGetUserRoomsFunc                   func(ctx context.Context, userID string) ([]chatkit.Room, error)
+	RoomsGetUserJoinableRoomsFunc           func(ctx context.Context, userID string) ([]chatkit.Room, error)
+	RoomsCreateRoomFunc                     func(ctx context.Context, options chatkit.CreateRoomOptions) (chatkit.Room, error)
+	RoomsUpdateRoomFunc                     func(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) error
+	RoomsUpdateRoomAndGetFunc               func(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) (chatkit.Room, error)
+	RoomsDeleteRoomFunc                     func(ctx context.Context, roomID string) error
+	RoomsAsyncDeleteRoomFunc                func(ctx context.Context, roomID string) (string, error)
+	RoomsGetDeleteStatusFunc                func(ctx context.Context, jobID string) (chatkit.DeleteStatus, error)
+	RoomsAddUsersToRoomFunc                 func(ctx context.Context, roomID string, userIDs []string) error
+	RoomsRemoveUsersFromRoomFunc            func(ctx context.Context, roomID string, userIDs []string) error
+	RoomsJoinRoomFunc                       func(ctx context.Context, roomID string, userID string) (chatkit.Room, error)
+	RoomsLeaveRoomFunc                      func(ctx context.Context, roomID string, userID string) error
+	RoomsTransferRoomOwnershipFunc          func(ctx context.Context, roomID string, newOwnerID string, options chatkit.TransferRoomOwnershipOptions) error
+	RoomsIterateRoomsFunc                   func(ctx context.Context, options chatkit.IterateRoomsOptions) *chatkit.RoomsIterator
+	RoomsCreateDirectRoomFunc               func(ctx context.Context, userA string, userB string, options chatkit.CreateDirectRoomOptions) (chatkit.Room, error)
+	RoomsGetRoomsByIDFunc                   func(ctx context.Context, roomIDs []string) ([]chatkit.Room, error)
+	RoomsGetRoomCountsFunc                  func(ctx context.Context, roomID string) (chatkit.RoomCounts, error)
+	RoomsWaitForDeleteFunc                  func(ctx context.Context, jobID string, interval time.Duration) (chatkit.DeleteStatus, error)
+	MessagesSendMessageFunc                 func(ctx context.Context, options chatkit.SendMessageOptions) (uint, error)
+	MessagesSendMultipartMessageFunc        func(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error)
+	MessagesSendMessageAsServiceFunc        func(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error)
+	MessagesSendSimpleMessageFunc           func(ctx context.Context, options chatkit.SendSimpleMessageOptions) (uint, error)
+	MessagesGetRoomMessagesFunc             func(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.Message, error)
+	MessagesFetchMultipartMessageFunc       func(ctx context.Context, options chatkit.FetchMultipartMessageOptions) (chatkit.MultipartMessage, error)
+	MessagesFetchMultipartMessagesFunc      func(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.MultipartMessage, error)
+	MessagesDeleteMessageFunc               func(ctx context.Context, options chatkit.DeleteMessageOptions) error
+	MessagesEditMessageFunc                 func(ctx context.Context, roomID string, messageID uint, options chatkit.EditMessageOptions) error
+	MessagesEditMultipartMessageFunc        func(ctx context.Context, roomID string, messageID uint, options chatkit.EditMultipartMessageOptions) error
+	MessagesEditSimpleMessageFunc           func(ctx context.Context, roomID string, messageID uint, options chatkit.EditSimpleMessageOptions) error
+	MessagesIterateRoomMessagesFunc         func(ctx context.Context, roomID string, options chatkit.IterateRoomMessagesOptions) *chatkit.MessageIterator
+	MessagesGetMessagesFunc                 func(ctx context.Context, roomID string, options chatkit.GetMessagesOptions) ([]chatkit.Message, error)
+	MessagesFetchLatestMessagesForRoomsFunc func(ctx context.Context, roomIDs []string, limit uint) (map[string][]chatkit.MultipartMessage, error)
+	MessagesSendMessageAndGetFunc           func(ctx context.Context, options chatkit.SendMessageOptions) (chatkit.Message, error)
+	MessagesSendMultipartMessageAndGetFunc  func(ctx context.Context, options chatkit.SendMultipartMessageOptions) (chatkit.MultipartMessage, error)
+	MessagesSendSimpleMessageAndGetFunc     func(ctx context.Context, options chatkit.SendSimpleMessageOptions) (chatkit.MultipartMessage, error)
+	MessagesDeleteMessagesFunc              func(ctx context.Context, roomID string, messageIDs []uint, options chatkit.BatchOptions) *chatkit.BatchResult
+	MessagesPinMessageFunc                  func(ctx context.Context, roomID string, messageID uint) error
+	MessagesUnpinMessageFunc                func(ctx context.Context, roomID string, messageID uint) error
+	MessagesGetPinnedMessagesFunc           func(ctx context.Context, roomID string) ([]chatkit.MultipartMessage, error)
+	MessagesAddReactionFunc                 func(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error
+	MessagesRemoveReactionFunc              func(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error
+	MessagesRedactMessageFunc               func(ctx context.Context, roomID string, messageID uint, replacementText string) error
+	MessagesSearchRoomMessagesFunc          func(ctx context.Context, roomID string, query string, options chatkit.SearchRoomMessagesOptions) ([]chatkit.MessageSearchResult, error)
+	MessagesSendSystemMessageFunc           func(ctx context.Context, options chatkit.SendSystemMessageOptions) (uint, error)
+	MessagesFetchThreadFunc                 func(ctx context.Context, roomID string, parentID uint, options chatkit.FetchThreadOptions) ([]chatkit.MultipartMessage, error)
+	CursorsGetUserReadCursorsFunc           func(ctx context.Context, userID string) ([]chatkit.Cursor, error)
+	CursorsSetReadCursorFunc                func(ctx context.Context, userID string, roomID string, position uint) error
+	CursorsGetReadCursorsForRoomFunc        func(ctx context.Context, roomID string) ([]chatkit.Cursor, error)
+	CursorsGetReadCursorsForRoomPageFunc    func(ctx context.Context, roomID string, options chatkit.GetReadCursorsForRoomOptions) ([]chatkit.Cursor, error)
+	CursorsGetReadCursorFunc                func(ctx context.Context, userID string, roomID string) (chatkit.Cursor, error)
+	CursorsDeleteReadCursorFunc             func(ctx context.Context, userID string, roomID string) error
+	CursorsGetUserCursorsFunc               func(ctx context.Context, cursorType uint, userID string) ([]chatkit.Cursor, error)
+	CursorsSetCursorFunc                    func(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error
+	CursorsGetCursorsForRoomFunc            func(ctx context.Context, cursorType uint, roomID string) ([]chatkit.Cursor, error)
+	CursorsGetCursorFunc                    func(ctx context.Context, cursorType uint, userID string, roomID string) (chatkit.Cursor, error)
+	CursorsDeleteCursorFunc                 func(ctx context.Context, cursorType uint, userID string, roomID string) error
+	CursorsGetUnreadCountsFunc              func(ctx context.Context, userID string) (map[string]chatkit.UnreadCount, error)
+	CursorsIterateRoomReadCursorsFunc       func(ctx context.Context, roomID string, options chatkit.IterateRoomReadCursorsOptions) *chatkit.CursorsIterator
+	CursorsGetMessageReadByFunc             func(ctx context.Context, roomID string, messageID uint) (chatkit.MessageReadBy, error)
+	RolesGetRolesFunc                       func(ctx context.Context) ([]chatkit.Role, error)
+	RolesCreateGlobalRoleFunc               func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	RolesCreateRoomRoleFunc                 func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	RolesDeleteGlobalRoleFunc               func(ctx context.Context, roleName string) error
+	RolesDeleteRoomRoleFunc                 func(ctx context.Context, roleName string) error
+	RolesGetPermissionsForGlobalRoleFunc    func(ctx context.Context, roleName string) ([]string, error)
+	RolesGetPermissionsForRoomRoleFunc      func(ctx context.Context, roleName string) ([]string, error)
+	RolesUpdatePermissionsForGlobalRoleFunc func(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error
+	RolesUpdatePermissionsForRoomRoleFunc   func(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error
+	RolesGetUserRolesFunc                   func(ctx context.Context, userID string) ([]chatkit.Role, error)
+	RolesAssignGlobalRoleToUserFunc         func(ctx context.Context, userID string, roleName string) error
+	RolesAssignRoomRoleToUserFunc           func(ctx context.Context, userID string, roomID string, roleName string) error
+	RolesRemoveGlobalRoleForUserFunc        func(ctx context.Context, userID string) error
+	RolesRemoveRoomRoleForUserFunc          func(ctx context.Context, userID string, roomID string) error
+	RolesCreateDefaultRolesFunc             func(ctx context.Context) error
+	RolesGetRoleFunc                        func(ctx context.Context, name string, scope string) (chatkit.Role, error)
+	RolesUpsertGlobalRoleFunc               func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	RolesUpsertRoomRoleFunc                 func(ctx context.Context, options chatkit.CreateRoleOptions) error
+	RolesGetEffectivePermissionsFunc        func(ctx context.Context, userID string, roomID string) ([]string, error)
+	RolesAssignGlobalRoleToUsersFunc        func(ctx context.Context, userIDs []string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult
+	RolesAssignRoomRoleToUsersFunc          func(ctx context.Context, userIDs []string, roomID string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult
+	RolesGetUsersWithRoleFunc               func(ctx context.Context, roleName string, scope string, options chatkit.GetUsersWithRoleOptions) ([]chatkit.RoleAssignment, error)
+	RolesListRoleAssignmentsFunc            func(ctx context.Context, options chatkit.ListRoleAssignmentsOptions) *chatkit.RoleAssignmentsIterator
+	RolesApplyRolesConfigFunc               func(ctx context.Context, config chatkit.RolesConfig, options chatkit.ApplyRolesConfigOptions) ([]chatkit.RoleChange, error)
+	RolesExportRolesFunc                    func(ctx context.Context) (chatkit.RolesConfig, error)
+	RolesImportRolesFunc                    func(ctx context.Context, config chatkit.RolesConfig, options chatkit.ImportRolesOptions) ([]chatkit.RoleChange, error)
+	AuthAuthenticateFunc                    func(payload auth.Payload, options auth.Options) (*auth.Response, error)
+	AuthGenerateAccessTokenFunc             func(options auth.Options) (auth.TokenWithExpiry, error)
+	AuthGenerateSUTokenFunc                 func(options auth.Options) (auth.TokenWithExpiry, error)
+	AuthVerifyTokenFunc                     func(ctx context.Context, tokenString string) (chatkit.Claims, error)
+	AuthAuthMiddlewareFunc                  func(next http.Handler, options chatkit.VerifierOptions) http.Handler
+	AuthIssueRefreshTokenFunc               func(ctx context.Context, userID string) (string, error)
+	AuthRefreshAccessTokenFunc              func(ctx context.Context, refreshToken string, options auth.Options) (chatkit.TokenResponse, error)
+	AuthRevokeRefreshTokenFunc              func(ctx context.Context, refreshToken string) error
+	AuthRevokeTokenFunc                     func(ctx context.Context, tokenString string) error
+	AuthRevokeTokensForUserFunc             func(ctx context.Context, userID string) error
+	AuthGenerateScopedTokenFunc             func(ctx context.Context, options chatkit.ScopedTokenOptions) (auth.TokenWithExpiry, error)
+	AuthGenerateReadOnlyTokenFunc           func(ctx context.Context, options chatkit.ReadOnlyTokenOptions) (auth.TokenWithExpiry, error)
+	AuthTokenProviderHandlerFunc            func(options chatkit.TokenProviderOptions) http.Handler
+}
+
+var _ chatkit.API = (*MockClient)(nil)
+
+// mockUsers is the Users sub-client of a MockClient.
+type mockUsers struct {
+	m *MockClient
+}
+
+// Users returns the mock of the Users sub-client.
+func (m *MockClient) Users() chatkit.UsersAPI {
+	return mockUsers{m: m}
+}
+
+// mockRooms is the Rooms sub-client of a MockClient.
+type mockRooms struct {
+	m *MockClient
+}
+
+// Rooms returns the mock of the Rooms sub-client.
+func (m *MockClient) Rooms() chatkit.RoomsAPI {
+	return mockRooms{m: m}
+}
+
+// mockMessages is the Messages sub-client of a MockClient.
+type mockMessages struct {
+	m *MockClient
+}
+
+// Messages returns the mock of the Messages sub-client.
+func (m *MockClient) Messages() chatkit.MessagesAPI {
+	return mockMessages{m: m}
+}
+
+// mockCursors is the Cursors sub-client of a MockClient.
+type mockCursors struct {
+	m *MockClient
+}
+
+// Cursors returns the mock of the Cursors sub-client.
+func (m *MockClient) Cursors() chatkit.CursorsAPI {
+	return mockCursors{m: m}
+}
+
+// mockRoles is the Roles sub-client of a MockClient.
+type mockRoles struct {
+	m *MockClient
+}
+
+// Roles returns the mock of the Roles sub-client.
+func (m *MockClient) Roles() chatkit.RolesAPI {
+	return mockRoles{m: m}
+}
+
+// mockAuth is the Auth sub-client of a MockClient.
+type mockAuth struct {
+	m *MockClient
+}
+
+// Auth returns the mock of the Auth sub-client.
+func (m *MockClient) Auth() chatkit.AuthAPI {
+	return mockAuth{m: m}
+}
+
+func (m *MockClient) GetUserReadCursors(ctx context.Context, userID string) ([]chatkit.Cursor, error) {
+	if m.GetUserReadCursorsFunc != nil {
+		m.record("GetUserReadCursors", userID)
+		return m.GetUserReadCursorsFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("GetUserReadCursors", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUserReadCursors", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error {
+	if m.SetReadCursorFunc != nil {
+		m.record("SetReadCursor", userID, roomID, position)
+		return m.SetReadCursorFunc(ctx, userID, roomID, position)
+	}
+
+	var r0 error
+	returns, ok := m.called("SetReadCursor", 1, userID, roomID, position)
+	if !ok {
+		r0 = unexpectedCall("SetReadCursor", userID, roomID, position)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetReadCursorsForRoom(ctx context.Context, roomID string) ([]chatkit.Cursor, error) {
+	if m.GetReadCursorsForRoomFunc != nil {
+		m.record("GetReadCursorsForRoom", roomID)
+		return m.GetReadCursorsForRoomFunc(ctx, roomID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("GetReadCursorsForRoom", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetReadCursorsForRoom", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetReadCursorsForRoomPage(ctx context.Context, roomID string, options chatkit.GetReadCursorsForRoomOptions) ([]chatkit.Cursor, error) {
+	if m.GetReadCursorsForRoomPageFunc != nil {
+		m.record("GetReadCursorsForRoomPage", roomID, options)
+		return m.GetReadCursorsForRoomPageFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("GetReadCursorsForRoomPage", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("GetReadCursorsForRoomPage", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetReadCursor(ctx context.Context, userID string, roomID string) (chatkit.Cursor, error) {
+	if m.GetReadCursorFunc != nil {
+		m.record("GetReadCursor", userID, roomID)
+		return m.GetReadCursorFunc(ctx, userID, roomID)
+	}
+
+	var r0 chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("GetReadCursor", 2, userID, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetReadCursor", userID, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) DeleteReadCursor(ctx context.Context, userID string, roomID string) error {
+	if m.DeleteReadCursorFunc != nil {
+		m.record("DeleteReadCursor", userID, roomID)
+		return m.DeleteReadCursorFunc(ctx, userID, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("DeleteReadCursor", 1, userID, roomID)
+	if !ok {
+		r0 = unexpectedCall("DeleteReadCursor", userID, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]chatkit.Cursor, error) {
+	if m.GetUserCursorsFunc != nil {
+		m.record("GetUserCursors", cursorType, userID)
+		return m.GetUserCursorsFunc(ctx, cursorType, userID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("GetUserCursors", 2, cursorType, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUserCursors", cursorType, userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SetCursor(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error {
+	if m.SetCursorFunc != nil {
+		m.record("SetCursor", cursorType, userID, roomID, position)
+		return m.SetCursorFunc(ctx, cursorType, userID, roomID, position)
+	}
+
+	var r0 error
+	returns, ok := m.called("SetCursor", 1, cursorType, userID, roomID, position)
+	if !ok {
+		r0 = unexpectedCall("SetCursor", cursorType, userID, roomID, position)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]chatkit.Cursor, error) {
+	if m.GetCursorsForRoomFunc != nil {
+		m.record("GetCursorsForRoom", cursorType, roomID)
+		return m.GetCursorsForRoomFunc(ctx, cursorType, roomID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("GetCursorsForRoom", 2, cursorType, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetCursorsForRoom", cursorType, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetCursor(ctx context.Context, cursorType uint, userID string, roomID string) (chatkit.Cursor, error) {
+	if m.GetCursorFunc != nil {
+		m.record("GetCursor", cursorType, userID, roomID)
+		return m.GetCursorFunc(ctx, cursorType, userID, roomID)
+	}
+
+	var r0 chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("GetCursor", 2, cursorType, userID, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetCursor", cursorType, userID, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error {
+	if m.DeleteCursorFunc != nil {
+		m.record("DeleteCursor", cursorType, userID, roomID)
+		return m.DeleteCursorFunc(ctx, cursorType, userID, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("DeleteCursor", 1, cursorType, userID, roomID)
+	if !ok {
+		r0 = unexpectedCall("DeleteCursor", cursorType, userID, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) CursorsRequest(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+	if m.CursorsRequestFunc != nil {
+		m.record("CursorsRequest", options)
+		return m.CursorsRequestFunc(ctx, options)
+	}
+
+	var r0 *http.Response
+	var r1 error
+	returns, ok := m.called("CursorsRequest", 2, options)
+	if !ok {
+		r1 = unexpectedCall("CursorsRequest", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*http.Response)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) CursorsSubscribe(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error) {
+	if m.CursorsSubscribeFunc != nil {
+		m.record("CursorsSubscribe", options)
+		return m.CursorsSubscribeFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RawEventStream
+	var r1 error
+	returns, ok := m.called("CursorsSubscribe", 2, options)
+	if !ok {
+		r1 = unexpectedCall("CursorsSubscribe", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RawEventStream)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetUserPresence(ctx context.Context, userID string) (chatkit.UserPresence, error) {
+	if m.GetUserPresenceFunc != nil {
+		m.record("GetUserPresence", userID)
+		return m.GetUserPresenceFunc(ctx, userID)
+	}
+
+	var r0 chatkit.UserPresence
+	var r1 error
+	returns, ok := m.called("GetUserPresence", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUserPresence", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.UserPresence)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetUsersPresence(ctx context.Context, userIDs []string) ([]chatkit.UserPresence, error) {
+	if m.GetUsersPresenceFunc != nil {
+		m.record("GetUsersPresence", userIDs)
+		return m.GetUsersPresenceFunc(ctx, userIDs)
+	}
+
+	var r0 []chatkit.UserPresence
+	var r1 error
+	returns, ok := m.called("GetUsersPresence", 2, userIDs)
+	if !ok {
+		r1 = unexpectedCall("GetUsersPresence", userIDs)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.UserPresence)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) PresenceRequest(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+	if m.PresenceRequestFunc != nil {
+		m.record("PresenceRequest", options)
+		return m.PresenceRequestFunc(ctx, options)
+	}
+
+	var r0 *http.Response
+	var r1 error
+	returns, ok := m.called("PresenceRequest", 2, options)
+	if !ok {
+		r1 = unexpectedCall("PresenceRequest", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*http.Response)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) PresenceSubscribe(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error) {
+	if m.PresenceSubscribeFunc != nil {
+		m.record("PresenceSubscribe", options)
+		return m.PresenceSubscribeFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RawEventStream
+	var r1 error
+	returns, ok := m.called("PresenceSubscribe", 2, options)
+	if !ok {
+		r1 = unexpectedCall("PresenceSubscribe", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RawEventStream)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetRoles(ctx context.Context) ([]chatkit.Role, error) {
+	if m.GetRolesFunc != nil {
+		m.record("GetRoles")
+		return m.GetRolesFunc(ctx)
+	}
+
+	var r0 []chatkit.Role
+	var r1 error
+	returns, ok := m.called("GetRoles", 2)
+	if !ok {
+		r1 = unexpectedCall("GetRoles")
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Role)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) CreateGlobalRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	if m.CreateGlobalRoleFunc != nil {
+		m.record("CreateGlobalRole", options)
+		return m.CreateGlobalRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("CreateGlobalRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("CreateGlobalRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) CreateRoomRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	if m.CreateRoomRoleFunc != nil {
+		m.record("CreateRoomRole", options)
+		return m.CreateRoomRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("CreateRoomRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("CreateRoomRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) DeleteGlobalRole(ctx context.Context, roleName string) error {
+	if m.DeleteGlobalRoleFunc != nil {
+		m.record("DeleteGlobalRole", roleName)
+		return m.DeleteGlobalRoleFunc(ctx, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("DeleteGlobalRole", 1, roleName)
+	if !ok {
+		r0 = unexpectedCall("DeleteGlobalRole", roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) DeleteRoomRole(ctx context.Context, roleName string) error {
+	if m.DeleteRoomRoleFunc != nil {
+		m.record("DeleteRoomRole", roleName)
+		return m.DeleteRoomRoleFunc(ctx, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("DeleteRoomRole", 1, roleName)
+	if !ok {
+		r0 = unexpectedCall("DeleteRoomRole", roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetPermissionsForGlobalRole(ctx context.Context, roleName string) ([]string, error) {
+	if m.GetPermissionsForGlobalRoleFunc != nil {
+		m.record("GetPermissionsForGlobalRole", roleName)
+		return m.GetPermissionsForGlobalRoleFunc(ctx, roleName)
+	}
+
+	var r0 []string
+	var r1 error
+	returns, ok := m.called("GetPermissionsForGlobalRole", 2, roleName)
+	if !ok {
+		r1 = unexpectedCall("GetPermissionsForGlobalRole", roleName)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetPermissionsForRoomRole(ctx context.Context, roleName string) ([]string, error) {
+	if m.GetPermissionsForRoomRoleFunc != nil {
+		m.record("GetPermissionsForRoomRole", roleName)
+		return m.GetPermissionsForRoomRoleFunc(ctx, roleName)
+	}
+
+	var r0 []string
+	var r1 error
+	returns, ok := m.called("GetPermissionsForRoomRole", 2, roleName)
+	if !ok {
+		r1 = unexpectedCall("GetPermissionsForRoomRole", roleName)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) UpdatePermissionsForGlobalRole(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error {
+	if m.UpdatePermissionsForGlobalRoleFunc != nil {
+		m.record("UpdatePermissionsForGlobalRole", roleName, options)
+		return m.UpdatePermissionsForGlobalRoleFunc(ctx, roleName, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("UpdatePermissionsForGlobalRole", 1, roleName, options)
+	if !ok {
+		r0 = unexpectedCall("UpdatePermissionsForGlobalRole", roleName, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) UpdatePermissionsForRoomRole(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error {
+	if m.UpdatePermissionsForRoomRoleFunc != nil {
+		m.record("UpdatePermissionsForRoomRole", roleName, options)
+		return m.UpdatePermissionsForRoomRoleFunc(ctx, roleName, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("UpdatePermissionsForRoomRole", 1, roleName, options)
+	if !ok {
+		r0 = unexpectedCall("UpdatePermissionsForRoomRole", roleName, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetUserRoles(ctx context.Context, userID string) ([]chatkit.Role, error) {
+	if m.GetUserRolesFunc != nil {
+		m.record("GetUserRoles", userID)
+		return m.GetUserRolesFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Role
+	var r1 error
+	returns, ok := m.called("GetUserRoles", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUserRoles", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Role)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) AssignGlobalRoleToUser(ctx context.Context, userID string, roleName string) error {
+	if m.AssignGlobalRoleToUserFunc != nil {
+		m.record("AssignGlobalRoleToUser", userID, roleName)
+		return m.AssignGlobalRoleToUserFunc(ctx, userID, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("AssignGlobalRoleToUser", 1, userID, roleName)
+	if !ok {
+		r0 = unexpectedCall("AssignGlobalRoleToUser", userID, roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) AssignRoomRoleToUser(ctx context.Context, userID string, roomID string, roleName string) error {
+	if m.AssignRoomRoleToUserFunc != nil {
+		m.record("AssignRoomRoleToUser", userID, roomID, roleName)
+		return m.AssignRoomRoleToUserFunc(ctx, userID, roomID, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("AssignRoomRoleToUser", 1, userID, roomID, roleName)
+	if !ok {
+		r0 = unexpectedCall("AssignRoomRoleToUser", userID, roomID, roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RemoveGlobalRoleForUser(ctx context.Context, userID string) error {
+	if m.RemoveGlobalRoleForUserFunc != nil {
+		m.record("RemoveGlobalRoleForUser", userID)
+		return m.RemoveGlobalRoleForUserFunc(ctx, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("RemoveGlobalRoleForUser", 1, userID)
+	if !ok {
+		r0 = unexpectedCall("RemoveGlobalRoleForUser", userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RemoveRoomRoleForUser(ctx context.Context, userID string, roomID string) error {
+	if m.RemoveRoomRoleForUserFunc != nil {
+		m.record("RemoveRoomRoleForUser", userID, roomID)
+		return m.RemoveRoomRoleForUserFunc(ctx, userID, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("RemoveRoomRoleForUser", 1, userID, roomID)
+	if !ok {
+		r0 = unexpectedCall("RemoveRoomRoleForUser", userID, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) AuthorizerRequest(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+	if m.AuthorizerRequestFunc != nil {
+		m.record("AuthorizerRequest", options)
+		return m.AuthorizerRequestFunc(ctx, options)
+	}
+
+	var r0 *http.Response
+	var r1 error
+	returns, ok := m.called("AuthorizerRequest", 2, options)
+	if !ok {
+		r1 = unexpectedCall("AuthorizerRequest", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*http.Response)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) AuthorizerSubscribe(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error) {
+	if m.AuthorizerSubscribeFunc != nil {
+		m.record("AuthorizerSubscribe", options)
+		return m.AuthorizerSubscribeFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RawEventStream
+	var r1 error
+	returns, ok := m.called("AuthorizerSubscribe", 2, options)
+	if !ok {
+		r1 = unexpectedCall("AuthorizerSubscribe", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RawEventStream)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetUser(ctx context.Context, userID string) (chatkit.User, error) {
+	if m.GetUserFunc != nil {
+		m.record("GetUser", userID)
+		return m.GetUserFunc(ctx, userID)
+	}
+
+	var r0 chatkit.User
+	var r1 error
+	returns, ok := m.called("GetUser", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUser", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetUsers(ctx context.Context, options *chatkit.GetUsersOptions) ([]chatkit.User, error) {
+	if m.GetUsersFunc != nil {
+		m.record("GetUsers", options)
+		return m.GetUsersFunc(ctx, options)
+	}
+
+	var r0 []chatkit.User
+	var r1 error
+	returns, ok := m.called("GetUsers", 2, options)
+	if !ok {
+		r1 = unexpectedCall("GetUsers", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetUsersByID(ctx context.Context, userIDs []string) ([]chatkit.User, error) {
+	if m.GetUsersByIDFunc != nil {
+		m.record("GetUsersByID", userIDs)
+		return m.GetUsersByIDFunc(ctx, userIDs)
+	}
+
+	var r0 []chatkit.User
+	var r1 error
+	returns, ok := m.called("GetUsersByID", 2, userIDs)
+	if !ok {
+		r1 = unexpectedCall("GetUsersByID", userIDs)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) CreateUser(ctx context.Context, options chatkit.CreateUserOptions) error {
+	if m.CreateUserFunc != nil {
+		m.record("CreateUser", options)
+		return m.CreateUserFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("CreateUser", 1, options)
+	if !ok {
+		r0 = unexpectedCall("CreateUser", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) CreateUserAndGet(ctx context.Context, options chatkit.CreateUserOptions) (chatkit.User, error) {
+	if m.CreateUserAndGetFunc != nil {
+		m.record("CreateUserAndGet", options)
+		return m.CreateUserAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.User
+	var r1 error
+	returns, ok := m.called("CreateUserAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("CreateUserAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) CreateUsers(ctx context.Context, users []chatkit.CreateUserOptions) error {
+	if m.CreateUsersFunc != nil {
+		m.record("CreateUsers", users)
+		return m.CreateUsersFunc(ctx, users)
+	}
+
+	var r0 error
+	returns, ok := m.called("CreateUsers", 1, users)
+	if !ok {
+		r0 = unexpectedCall("CreateUsers", users)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) UpdateUser(ctx context.Context, userID string, options chatkit.UpdateUserOptions) error {
+	if m.UpdateUserFunc != nil {
+		m.record("UpdateUser", userID, options)
+		return m.UpdateUserFunc(ctx, userID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("UpdateUser", 1, userID, options)
+	if !ok {
+		r0 = unexpectedCall("UpdateUser", userID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) DeleteUser(ctx context.Context, userID string) error {
+	if m.DeleteUserFunc != nil {
+		m.record("DeleteUser", userID)
+		return m.DeleteUserFunc(ctx, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("DeleteUser", 1, userID)
+	if !ok {
+		r0 = unexpectedCall("DeleteUser", userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetRoom(ctx context.Context, roomID string) (chatkit.Room, error) {
+	if m.GetRoomFunc != nil {
+		m.record("GetRoom", roomID)
+		return m.GetRoomFunc(ctx, roomID)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("GetRoom", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetRoom", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetRooms(ctx context.Context, options chatkit.GetRoomsOptions) ([]core.RoomWithoutMembers, error) {
+	if m.GetRoomsFunc != nil {
+		m.record("GetRooms", options)
+		return m.GetRoomsFunc(ctx, options)
+	}
+
+	var r0 []core.RoomWithoutMembers
+	var r1 error
+	returns, ok := m.called("GetRooms", 2, options)
+	if !ok {
+		r1 = unexpectedCall("GetRooms", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]core.RoomWithoutMembers)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetUserRooms(ctx context.Context, userID string) ([]chatkit.Room, error) {
+	if m.GetUserRoomsFunc != nil {
+		m.record("GetUserRooms", userID)
+		return m.GetUserRoomsFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Room
+	var r1 error
+	returns, ok := m.called("GetUserRooms", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUserRooms", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetUserJoinableRooms(ctx context.Context, userID string) ([]chatkit.Room, error) {
+	if m.GetUserJoinableRoomsFunc != nil {
+		m.record("GetUserJoinableRooms", userID)
+		return m.GetUserJoinableRoomsFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Room
+	var r1 error
+	returns, ok := m.called("GetUserJoinableRooms", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUserJoinableRooms", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) CreateRoom(ctx context.Context, options chatkit.CreateRoomOptions) (chatkit.Room, error) {
+	if m.CreateRoomFunc != nil {
+		m.record("CreateRoom", options)
+		return m.CreateRoomFunc(ctx, options)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("CreateRoom", 2, options)
+	if !ok {
+		r1 = unexpectedCall("CreateRoom", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) UpdateRoom(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) error {
+	if m.UpdateRoomFunc != nil {
+		m.record("UpdateRoom", roomID, options)
+		return m.UpdateRoomFunc(ctx, roomID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("UpdateRoom", 1, roomID, options)
+	if !ok {
+		r0 = unexpectedCall("UpdateRoom", roomID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) UpdateRoomAndGet(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) (chatkit.Room, error) {
+	if m.UpdateRoomAndGetFunc != nil {
+		m.record("UpdateRoomAndGet", roomID, options)
+		return m.UpdateRoomAndGetFunc(ctx, roomID, options)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("UpdateRoomAndGet", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("UpdateRoomAndGet", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) DeleteRoom(ctx context.Context, roomID string) error {
+	if m.DeleteRoomFunc != nil {
+		m.record("DeleteRoom", roomID)
+		return m.DeleteRoomFunc(ctx, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("DeleteRoom", 1, roomID)
+	if !ok {
+		r0 = unexpectedCall("DeleteRoom", roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) AsyncDeleteRoom(ctx context.Context, roomID string) (string, error) {
+	if m.AsyncDeleteRoomFunc != nil {
+		m.record("AsyncDeleteRoom", roomID)
+		return m.AsyncDeleteRoomFunc(ctx, roomID)
+	}
+
+	var r0 string
+	var r1 error
+	returns, ok := m.called("AsyncDeleteRoom", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("AsyncDeleteRoom", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetDeleteStatus(ctx context.Context, jobID string) (chatkit.DeleteStatus, error) {
+	if m.GetDeleteStatusFunc != nil {
+		m.record("GetDeleteStatus", jobID)
+		return m.GetDeleteStatusFunc(ctx, jobID)
+	}
+
+	var r0 chatkit.DeleteStatus
+	var r1 error
+	returns, ok := m.called("GetDeleteStatus", 2, jobID)
+	if !ok {
+		r1 = unexpectedCall("GetDeleteStatus", jobID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.DeleteStatus)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
+	if m.AddUsersToRoomFunc != nil {
+		m.record("AddUsersToRoom", roomID, userIDs)
+		return m.AddUsersToRoomFunc(ctx, roomID, userIDs)
+	}
+
+	var r0 error
+	returns, ok := m.called("AddUsersToRoom", 1, roomID, userIDs)
+	if !ok {
+		r0 = unexpectedCall("AddUsersToRoom", roomID, userIDs)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RemoveUsersFromRoom(ctx context.Context, roomID string, userIDs []string) error {
+	if m.RemoveUsersFromRoomFunc != nil {
+		m.record("RemoveUsersFromRoom", roomID, userIDs)
+		return m.RemoveUsersFromRoomFunc(ctx, roomID, userIDs)
+	}
+
+	var r0 error
+	returns, ok := m.called("RemoveUsersFromRoom", 1, roomID, userIDs)
+	if !ok {
+		r0 = unexpectedCall("RemoveUsersFromRoom", roomID, userIDs)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) JoinRoom(ctx context.Context, roomID string, userID string) (chatkit.Room, error) {
+	if m.JoinRoomFunc != nil {
+		m.record("JoinRoom", roomID, userID)
+		return m.JoinRoomFunc(ctx, roomID, userID)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("JoinRoom", 2, roomID, userID)
+	if !ok {
+		r1 = unexpectedCall("JoinRoom", roomID, userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) LeaveRoom(ctx context.Context, roomID string, userID string) error {
+	if m.LeaveRoomFunc != nil {
+		m.record("LeaveRoom", roomID, userID)
+		return m.LeaveRoomFunc(ctx, roomID, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("LeaveRoom", 1, roomID, userID)
+	if !ok {
+		r0 = unexpectedCall("LeaveRoom", roomID, userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) SendMessage(ctx context.Context, options chatkit.SendMessageOptions) (uint, error) {
+	if m.SendMessageFunc != nil {
+		m.record("SendMessage", options)
+		return m.SendMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("SendMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SendMultipartMessage(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error) {
+	if m.SendMultipartMessageFunc != nil {
+		m.record("SendMultipartMessage", options)
+		return m.SendMultipartMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("SendMultipartMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendMultipartMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SendMessageAsService(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error) {
+	if m.SendMessageAsServiceFunc != nil {
+		m.record("SendMessageAsService", options)
+		return m.SendMessageAsServiceFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("SendMessageAsService", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendMessageAsService", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SendSimpleMessage(ctx context.Context, options chatkit.SendSimpleMessageOptions) (uint, error) {
+	if m.SendSimpleMessageFunc != nil {
+		m.record("SendSimpleMessage", options)
+		return m.SendSimpleMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("SendSimpleMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendSimpleMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetRoomMessages(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.Message, error) {
+	if m.GetRoomMessagesFunc != nil {
+		m.record("GetRoomMessages", roomID, options)
+		return m.GetRoomMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.Message
+	var r1 error
+	returns, ok := m.called("GetRoomMessages", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("GetRoomMessages", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Message)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) FetchMultipartMessage(ctx context.Context, options chatkit.FetchMultipartMessageOptions) (chatkit.MultipartMessage, error) {
+	if m.FetchMultipartMessageFunc != nil {
+		m.record("FetchMultipartMessage", options)
+		return m.FetchMultipartMessageFunc(ctx, options)
+	}
+
+	var r0 chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("FetchMultipartMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("FetchMultipartMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) FetchMultipartMessages(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.MultipartMessage, error) {
+	if m.FetchMultipartMessagesFunc != nil {
+		m.record("FetchMultipartMessages", roomID, options)
+		return m.FetchMultipartMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("FetchMultipartMessages", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("FetchMultipartMessages", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) DeleteMessage(ctx context.Context, options chatkit.DeleteMessageOptions) error {
+	if m.DeleteMessageFunc != nil {
+		m.record("DeleteMessage", options)
+		return m.DeleteMessageFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("DeleteMessage", 1, options)
+	if !ok {
+		r0 = unexpectedCall("DeleteMessage", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) EditMessage(ctx context.Context, roomID string, messageID uint, options chatkit.EditMessageOptions) error {
+	if m.EditMessageFunc != nil {
+		m.record("EditMessage", roomID, messageID, options)
+		return m.EditMessageFunc(ctx, roomID, messageID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("EditMessage", 1, roomID, messageID, options)
+	if !ok {
+		r0 = unexpectedCall("EditMessage", roomID, messageID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options chatkit.EditMultipartMessageOptions) error {
+	if m.EditMultipartMessageFunc != nil {
+		m.record("EditMultipartMessage", roomID, messageID, options)
+		return m.EditMultipartMessageFunc(ctx, roomID, messageID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("EditMultipartMessage", 1, roomID, messageID, options)
+	if !ok {
+		r0 = unexpectedCall("EditMultipartMessage", roomID, messageID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) EditSimpleMessage(ctx context.Context, roomID string, messageID uint, options chatkit.EditSimpleMessageOptions) error {
+	if m.EditSimpleMessageFunc != nil {
+		m.record("EditSimpleMessage", roomID, messageID, options)
+		return m.EditSimpleMessageFunc(ctx, roomID, messageID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("EditSimpleMessage", 1, roomID, messageID, options)
+	if !ok {
+		r0 = unexpectedCall("EditSimpleMessage", roomID, messageID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) CoreRequest(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+	if m.CoreRequestFunc != nil {
+		m.record("CoreRequest", options)
+		return m.CoreRequestFunc(ctx, options)
+	}
+
+	var r0 *http.Response
+	var r1 error
+	returns, ok := m.called("CoreRequest", 2, options)
+	if !ok {
+		r1 = unexpectedCall("CoreRequest", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*http.Response)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) CoreSubscribe(ctx context.Context, options platformclient.RequestOptions) (*chatkit.RawEventStream, error) {
+	if m.CoreSubscribeFunc != nil {
+		m.record("CoreSubscribe", options)
+		return m.CoreSubscribeFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RawEventStream
+	var r1 error
+	returns, ok := m.called("CoreSubscribe", 2, options)
+	if !ok {
+		r1 = unexpectedCall("CoreSubscribe", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RawEventStream)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error) {
+	if m.AuthenticateFunc != nil {
+		m.record("Authenticate", payload, options)
+		return m.AuthenticateFunc(payload, options)
+	}
+
+	var r0 *auth.Response
+	var r1 error
+	returns, ok := m.called("Authenticate", 2, payload, options)
+	if !ok {
+		r1 = unexpectedCall("Authenticate", payload, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*auth.Response)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	if m.GenerateAccessTokenFunc != nil {
+		m.record("GenerateAccessToken", options)
+		return m.GenerateAccessTokenFunc(options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("GenerateAccessToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("GenerateAccessToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	if m.GenerateSUTokenFunc != nil {
+		m.record("GenerateSUToken", options)
+		return m.GenerateSUTokenFunc(options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("GenerateSUToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("GenerateSUToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) VerifyToken(ctx context.Context, tokenString string) (chatkit.Claims, error) {
+	if m.VerifyTokenFunc != nil {
+		m.record("VerifyToken", tokenString)
+		return m.VerifyTokenFunc(ctx, tokenString)
+	}
+
+	var r0 chatkit.Claims
+	var r1 error
+	returns, ok := m.called("VerifyToken", 2, tokenString)
+	if !ok {
+		r1 = unexpectedCall("VerifyToken", tokenString)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Claims)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) RefreshAttachment(ctx context.Context, att chatkit.Attachment) (chatkit.Attachment, error) {
+	if m.RefreshAttachmentFunc != nil {
+		m.record("RefreshAttachment", att)
+		return m.RefreshAttachmentFunc(ctx, att)
+	}
+
+	var r0 chatkit.Attachment
+	var r1 error
+	returns, ok := m.called("RefreshAttachment", 2, att)
+	if !ok {
+		r1 = unexpectedCall("RefreshAttachment", att)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Attachment)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) DownloadAttachment(ctx context.Context, att chatkit.Attachment, w io.Writer) error {
+	if m.DownloadAttachmentFunc != nil {
+		m.record("DownloadAttachment", att, w)
+		return m.DownloadAttachmentFunc(ctx, att, w)
+	}
+
+	var r0 error
+	returns, ok := m.called("DownloadAttachment", 1, att, w)
+	if !ok {
+		r0 = unexpectedCall("DownloadAttachment", att, w)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetUnreadCounts(ctx context.Context, userID string) (map[string]chatkit.UnreadCount, error) {
+	if m.GetUnreadCountsFunc != nil {
+		m.record("GetUnreadCounts", userID)
+		return m.GetUnreadCountsFunc(ctx, userID)
+	}
+
+	var r0 map[string]chatkit.UnreadCount
+	var r1 error
+	returns, ok := m.called("GetUnreadCounts", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("GetUnreadCounts", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(map[string]chatkit.UnreadCount)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) IterateRoomReadCursors(ctx context.Context, roomID string, options chatkit.IterateRoomReadCursorsOptions) *chatkit.CursorsIterator {
+	if m.IterateRoomReadCursorsFunc != nil {
+		m.record("IterateRoomReadCursors", roomID, options)
+		return m.IterateRoomReadCursorsFunc(ctx, roomID, options)
+	}
+
+	var r0 *chatkit.CursorsIterator
+	returns, ok := m.called("IterateRoomReadCursors", 1, roomID, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.CursorsIterator)
+	}
+	return r0
+}
+
+func (m *MockClient) GetMessageReadBy(ctx context.Context, roomID string, messageID uint) (chatkit.MessageReadBy, error) {
+	if m.GetMessageReadByFunc != nil {
+		m.record("GetMessageReadBy", roomID, messageID)
+		return m.GetMessageReadByFunc(ctx, roomID, messageID)
+	}
+
+	var r0 chatkit.MessageReadBy
+	var r1 error
+	returns, ok := m.called("GetMessageReadBy", 2, roomID, messageID)
+	if !ok {
+		r1 = unexpectedCall("GetMessageReadBy", roomID, messageID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MessageReadBy)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SetDebugHTTP(w io.Writer) {
+	if m.SetDebugHTTPFunc != nil {
+		m.record("SetDebugHTTP", w)
+		m.SetDebugHTTPFunc(w)
+		return
+	}
+
+	m.called("SetDebugHTTP", 0, w)
+}
+
+func (m *MockClient) ExportUsers(ctx context.Context, w io.Writer, format chatkit.ExportFormat) error {
+	if m.ExportUsersFunc != nil {
+		m.record("ExportUsers", w, format)
+		return m.ExportUsersFunc(ctx, w, format)
+	}
+
+	var r0 error
+	returns, ok := m.called("ExportUsers", 1, w, format)
+	if !ok {
+		r0 = unexpectedCall("ExportUsers", w, format)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) UsersNDJSON(ctx context.Context, w io.Writer) error {
+	if m.UsersNDJSONFunc != nil {
+		m.record("UsersNDJSON", w)
+		return m.UsersNDJSONFunc(ctx, w)
+	}
+
+	var r0 error
+	returns, ok := m.called("UsersNDJSON", 1, w)
+	if !ok {
+		r0 = unexpectedCall("UsersNDJSON", w)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RoomsNDJSON(ctx context.Context, w io.Writer, options chatkit.IterateRoomsOptions) error {
+	if m.RoomsNDJSONFunc != nil {
+		m.record("RoomsNDJSON", w, options)
+		return m.RoomsNDJSONFunc(ctx, w, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("RoomsNDJSON", 1, w, options)
+	if !ok {
+		r0 = unexpectedCall("RoomsNDJSON", w, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RoomMessagesNDJSON(ctx context.Context, w io.Writer, roomID string) error {
+	if m.RoomMessagesNDJSONFunc != nil {
+		m.record("RoomMessagesNDJSON", w, roomID)
+		return m.RoomMessagesNDJSONFunc(ctx, w, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("RoomMessagesNDJSON", 1, w, roomID)
+	if !ok {
+		r0 = unexpectedCall("RoomMessagesNDJSON", w, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) ExportRoomMessages(ctx context.Context, roomID string, w io.Writer, format chatkit.ExportFormat, options chatkit.ExportRoomMessagesOptions) error {
+	if m.ExportRoomMessagesFunc != nil {
+		m.record("ExportRoomMessages", roomID, w, format, options)
+		return m.ExportRoomMessagesFunc(ctx, roomID, w, format, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("ExportRoomMessages", 1, roomID, w, format, options)
+	if !ok {
+		r0 = unexpectedCall("ExportRoomMessages", roomID, w, format, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RolesNDJSON(ctx context.Context, w io.Writer) error {
+	if m.RolesNDJSONFunc != nil {
+		m.record("RolesNDJSON", w)
+		return m.RolesNDJSONFunc(ctx, w)
+	}
+
+	var r0 error
+	returns, ok := m.called("RolesNDJSON", 1, w)
+	if !ok {
+		r0 = unexpectedCall("RolesNDJSON", w)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RoomReadCursorsNDJSON(ctx context.Context, w io.Writer, roomID string) error {
+	if m.RoomReadCursorsNDJSONFunc != nil {
+		m.record("RoomReadCursorsNDJSON", w, roomID)
+		return m.RoomReadCursorsNDJSONFunc(ctx, w, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("RoomReadCursorsNDJSON", 1, w, roomID)
+	if !ok {
+		r0 = unexpectedCall("RoomReadCursorsNDJSON", w, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) UserReadCursorsNDJSON(ctx context.Context, w io.Writer, userID string) error {
+	if m.UserReadCursorsNDJSONFunc != nil {
+		m.record("UserReadCursorsNDJSON", w, userID)
+		return m.UserReadCursorsNDJSONFunc(ctx, w, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("UserReadCursorsNDJSON", 1, w, userID)
+	if !ok {
+		r0 = unexpectedCall("UserReadCursorsNDJSON", w, userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) IterateRoomMessages(ctx context.Context, roomID string, options chatkit.IterateRoomMessagesOptions) *chatkit.MessageIterator {
+	if m.IterateRoomMessagesFunc != nil {
+		m.record("IterateRoomMessages", roomID, options)
+		return m.IterateRoomMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 *chatkit.MessageIterator
+	returns, ok := m.called("IterateRoomMessages", 1, roomID, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.MessageIterator)
+	}
+	return r0
+}
+
+func (m *MockClient) GetMessages(ctx context.Context, roomID string, options chatkit.GetMessagesOptions) ([]chatkit.Message, error) {
+	if m.GetMessagesFunc != nil {
+		m.record("GetMessages", roomID, options)
+		return m.GetMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.Message
+	var r1 error
+	returns, ok := m.called("GetMessages", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("GetMessages", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Message)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) FetchLatestMessagesForRooms(ctx context.Context, roomIDs []string, limit uint) (map[string][]chatkit.MultipartMessage, error) {
+	if m.FetchLatestMessagesForRoomsFunc != nil {
+		m.record("FetchLatestMessagesForRooms", roomIDs, limit)
+		return m.FetchLatestMessagesForRoomsFunc(ctx, roomIDs, limit)
+	}
+
+	var r0 map[string][]chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("FetchLatestMessagesForRooms", 2, roomIDs, limit)
+	if !ok {
+		r1 = unexpectedCall("FetchLatestMessagesForRooms", roomIDs, limit)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(map[string][]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) DeleteMessages(ctx context.Context, roomID string, messageIDs []uint, options chatkit.BatchOptions) *chatkit.BatchResult {
+	if m.DeleteMessagesFunc != nil {
+		m.record("DeleteMessages", roomID, messageIDs, options)
+		return m.DeleteMessagesFunc(ctx, roomID, messageIDs, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("DeleteMessages", 1, roomID, messageIDs, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (m *MockClient) SendMessageAndGet(ctx context.Context, options chatkit.SendMessageOptions) (chatkit.Message, error) {
+	if m.SendMessageAndGetFunc != nil {
+		m.record("SendMessageAndGet", options)
+		return m.SendMessageAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.Message
+	var r1 error
+	returns, ok := m.called("SendMessageAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendMessageAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Message)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SendMultipartMessageAndGet(ctx context.Context, options chatkit.SendMultipartMessageOptions) (chatkit.MultipartMessage, error) {
+	if m.SendMultipartMessageAndGetFunc != nil {
+		m.record("SendMultipartMessageAndGet", options)
+		return m.SendMultipartMessageAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("SendMultipartMessageAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendMultipartMessageAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SendSimpleMessageAndGet(ctx context.Context, options chatkit.SendSimpleMessageOptions) (chatkit.MultipartMessage, error) {
+	if m.SendSimpleMessageAndGetFunc != nil {
+		m.record("SendSimpleMessageAndGet", options)
+		return m.SendSimpleMessageAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("SendSimpleMessageAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendSimpleMessageAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) AuthMiddleware(next http.Handler, options chatkit.VerifierOptions) http.Handler {
+	if m.AuthMiddlewareFunc != nil {
+		m.record("AuthMiddleware", next, options)
+		return m.AuthMiddlewareFunc(next, options)
+	}
+
+	var r0 http.Handler
+	returns, ok := m.called("AuthMiddleware", 1, next, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(http.Handler)
+	}
+	return r0
+}
+
+func (m *MockClient) PinMessage(ctx context.Context, roomID string, messageID uint) error {
+	if m.PinMessageFunc != nil {
+		m.record("PinMessage", roomID, messageID)
+		return m.PinMessageFunc(ctx, roomID, messageID)
+	}
+
+	var r0 error
+	returns, ok := m.called("PinMessage", 1, roomID, messageID)
+	if !ok {
+		r0 = unexpectedCall("PinMessage", roomID, messageID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) UnpinMessage(ctx context.Context, roomID string, messageID uint) error {
+	if m.UnpinMessageFunc != nil {
+		m.record("UnpinMessage", roomID, messageID)
+		return m.UnpinMessageFunc(ctx, roomID, messageID)
+	}
+
+	var r0 error
+	returns, ok := m.called("UnpinMessage", 1, roomID, messageID)
+	if !ok {
+		r0 = unexpectedCall("UnpinMessage", roomID, messageID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetPinnedMessages(ctx context.Context, roomID string) ([]chatkit.MultipartMessage, error) {
+	if m.GetPinnedMessagesFunc != nil {
+		m.record("GetPinnedMessages", roomID)
+		return m.GetPinnedMessagesFunc(ctx, roomID)
+	}
+
+	var r0 []chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("GetPinnedMessages", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetPinnedMessages", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) AddReaction(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error {
+	if m.AddReactionFunc != nil {
+		m.record("AddReaction", roomID, messageID, userID, reaction)
+		return m.AddReactionFunc(ctx, roomID, messageID, userID, reaction)
+	}
+
+	var r0 error
+	returns, ok := m.called("AddReaction", 1, roomID, messageID, userID, reaction)
+	if !ok {
+		r0 = unexpectedCall("AddReaction", roomID, messageID, userID, reaction)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RemoveReaction(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error {
+	if m.RemoveReactionFunc != nil {
+		m.record("RemoveReaction", roomID, messageID, userID, reaction)
+		return m.RemoveReactionFunc(ctx, roomID, messageID, userID, reaction)
+	}
+
+	var r0 error
+	returns, ok := m.called("RemoveReaction", 1, roomID, messageID, userID, reaction)
+	if !ok {
+		r0 = unexpectedCall("RemoveReaction", roomID, messageID, userID, reaction)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RedactMessage(ctx context.Context, roomID string, messageID uint, replacementText string) error {
+	if m.RedactMessageFunc != nil {
+		m.record("RedactMessage", roomID, messageID, replacementText)
+		return m.RedactMessageFunc(ctx, roomID, messageID, replacementText)
+	}
+
+	var r0 error
+	returns, ok := m.called("RedactMessage", 1, roomID, messageID, replacementText)
+	if !ok {
+		r0 = unexpectedCall("RedactMessage", roomID, messageID, replacementText)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) IssueRefreshToken(ctx context.Context, userID string) (string, error) {
+	if m.IssueRefreshTokenFunc != nil {
+		m.record("IssueRefreshToken", userID)
+		return m.IssueRefreshTokenFunc(ctx, userID)
+	}
+
+	var r0 string
+	var r1 error
+	returns, ok := m.called("IssueRefreshToken", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("IssueRefreshToken", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) RefreshAccessToken(ctx context.Context, refreshToken string, options auth.Options) (chatkit.TokenResponse, error) {
+	if m.RefreshAccessTokenFunc != nil {
+		m.record("RefreshAccessToken", refreshToken, options)
+		return m.RefreshAccessTokenFunc(ctx, refreshToken, options)
+	}
+
+	var r0 chatkit.TokenResponse
+	var r1 error
+	returns, ok := m.called("RefreshAccessToken", 2, refreshToken, options)
+	if !ok {
+		r1 = unexpectedCall("RefreshAccessToken", refreshToken, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.TokenResponse)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	if m.RevokeRefreshTokenFunc != nil {
+		m.record("RevokeRefreshToken", refreshToken)
+		return m.RevokeRefreshTokenFunc(ctx, refreshToken)
+	}
+
+	var r0 error
+	returns, ok := m.called("RevokeRefreshToken", 1, refreshToken)
+	if !ok {
+		r0 = unexpectedCall("RevokeRefreshToken", refreshToken)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RevokeToken(ctx context.Context, tokenString string) error {
+	if m.RevokeTokenFunc != nil {
+		m.record("RevokeToken", tokenString)
+		return m.RevokeTokenFunc(ctx, tokenString)
+	}
+
+	var r0 error
+	returns, ok := m.called("RevokeToken", 1, tokenString)
+	if !ok {
+		r0 = unexpectedCall("RevokeToken", tokenString)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) RevokeTokensForUser(ctx context.Context, userID string) error {
+	if m.RevokeTokensForUserFunc != nil {
+		m.record("RevokeTokensForUser", userID)
+		return m.RevokeTokensForUserFunc(ctx, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("RevokeTokensForUser", 1, userID)
+	if !ok {
+		r0 = unexpectedCall("RevokeTokensForUser", userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) CreateDefaultRoles(ctx context.Context) error {
+	if m.CreateDefaultRolesFunc != nil {
+		m.record("CreateDefaultRoles")
+		return m.CreateDefaultRolesFunc(ctx)
+	}
+
+	var r0 error
+	returns, ok := m.called("CreateDefaultRoles", 1)
+	if !ok {
+		r0 = unexpectedCall("CreateDefaultRoles")
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetRole(ctx context.Context, name string, scope string) (chatkit.Role, error) {
+	if m.GetRoleFunc != nil {
+		m.record("GetRole", name, scope)
+		return m.GetRoleFunc(ctx, name, scope)
+	}
+
+	var r0 chatkit.Role
+	var r1 error
+	returns, ok := m.called("GetRole", 2, name, scope)
+	if !ok {
+		r1 = unexpectedCall("GetRole", name, scope)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Role)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) UpsertGlobalRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	if m.UpsertGlobalRoleFunc != nil {
+		m.record("UpsertGlobalRole", options)
+		return m.UpsertGlobalRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("UpsertGlobalRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("UpsertGlobalRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) UpsertRoomRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	if m.UpsertRoomRoleFunc != nil {
+		m.record("UpsertRoomRole", options)
+		return m.UpsertRoomRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("UpsertRoomRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("UpsertRoomRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) GetEffectivePermissions(ctx context.Context, userID string, roomID string) ([]string, error) {
+	if m.GetEffectivePermissionsFunc != nil {
+		m.record("GetEffectivePermissions", userID, roomID)
+		return m.GetEffectivePermissionsFunc(ctx, userID, roomID)
+	}
+
+	var r0 []string
+	var r1 error
+	returns, ok := m.called("GetEffectivePermissions", 2, userID, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetEffectivePermissions", userID, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) AssignGlobalRoleToUsers(ctx context.Context, userIDs []string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult {
+	if m.AssignGlobalRoleToUsersFunc != nil {
+		m.record("AssignGlobalRoleToUsers", userIDs, roleName, options)
+		return m.AssignGlobalRoleToUsersFunc(ctx, userIDs, roleName, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("AssignGlobalRoleToUsers", 1, userIDs, roleName, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (m *MockClient) AssignRoomRoleToUsers(ctx context.Context, userIDs []string, roomID string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult {
+	if m.AssignRoomRoleToUsersFunc != nil {
+		m.record("AssignRoomRoleToUsers", userIDs, roomID, roleName, options)
+		return m.AssignRoomRoleToUsersFunc(ctx, userIDs, roomID, roleName, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("AssignRoomRoleToUsers", 1, userIDs, roomID, roleName, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (m *MockClient) GetUsersWithRole(ctx context.Context, roleName string, scope string, options chatkit.GetUsersWithRoleOptions) ([]chatkit.RoleAssignment, error) {
+	if m.GetUsersWithRoleFunc != nil {
+		m.record("GetUsersWithRole", roleName, scope, options)
+		return m.GetUsersWithRoleFunc(ctx, roleName, scope, options)
+	}
+
+	var r0 []chatkit.RoleAssignment
+	var r1 error
+	returns, ok := m.called("GetUsersWithRole", 2, roleName, scope, options)
+	if !ok {
+		r1 = unexpectedCall("GetUsersWithRole", roleName, scope, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.RoleAssignment)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) ListRoleAssignments(ctx context.Context, options chatkit.ListRoleAssignmentsOptions) *chatkit.RoleAssignmentsIterator {
+	if m.ListRoleAssignmentsFunc != nil {
+		m.record("ListRoleAssignments", options)
+		return m.ListRoleAssignmentsFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RoleAssignmentsIterator
+	returns, ok := m.called("ListRoleAssignments", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RoleAssignmentsIterator)
+	}
+	return r0
+}
+
+func (m *MockClient) ApplyRolesConfig(ctx context.Context, config chatkit.RolesConfig, options chatkit.ApplyRolesConfigOptions) ([]chatkit.RoleChange, error) {
+	if m.ApplyRolesConfigFunc != nil {
+		m.record("ApplyRolesConfig", config, options)
+		return m.ApplyRolesConfigFunc(ctx, config, options)
+	}
+
+	var r0 []chatkit.RoleChange
+	var r1 error
+	returns, ok := m.called("ApplyRolesConfig", 2, config, options)
+	if !ok {
+		r1 = unexpectedCall("ApplyRolesConfig", config, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.RoleChange)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) ExportRoles(ctx context.Context) (chatkit.RolesConfig, error) {
+	if m.ExportRolesFunc != nil {
+		m.record("ExportRoles")
+		return m.ExportRolesFunc(ctx)
+	}
+
+	var r0 chatkit.RolesConfig
+	var r1 error
+	returns, ok := m.called("ExportRoles", 2)
+	if !ok {
+		r1 = unexpectedCall("ExportRoles")
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.RolesConfig)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) ImportRoles(ctx context.Context, config chatkit.RolesConfig, options chatkit.ImportRolesOptions) ([]chatkit.RoleChange, error) {
+	if m.ImportRolesFunc != nil {
+		m.record("ImportRoles", config, options)
+		return m.ImportRolesFunc(ctx, config, options)
+	}
+
+	var r0 []chatkit.RoleChange
+	var r1 error
+	returns, ok := m.called("ImportRoles", 2, config, options)
+	if !ok {
+		r1 = unexpectedCall("ImportRoles", config, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.RoleChange)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) TransferRoomOwnership(ctx context.Context, roomID string, newOwnerID string, options chatkit.TransferRoomOwnershipOptions) error {
+	if m.TransferRoomOwnershipFunc != nil {
+		m.record("TransferRoomOwnership", roomID, newOwnerID, options)
+		return m.TransferRoomOwnershipFunc(ctx, roomID, newOwnerID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("TransferRoomOwnership", 1, roomID, newOwnerID, options)
+	if !ok {
+		r0 = unexpectedCall("TransferRoomOwnership", roomID, newOwnerID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (m *MockClient) IterateRooms(ctx context.Context, options chatkit.IterateRoomsOptions) *chatkit.RoomsIterator {
+	if m.IterateRoomsFunc != nil {
+		m.record("IterateRooms", options)
+		return m.IterateRoomsFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RoomsIterator
+	returns, ok := m.called("IterateRooms", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RoomsIterator)
+	}
+	return r0
+}
+
+func (m *MockClient) CreateDirectRoom(ctx context.Context, userA string, userB string, options chatkit.CreateDirectRoomOptions) (chatkit.Room, error) {
+	if m.CreateDirectRoomFunc != nil {
+		m.record("CreateDirectRoom", userA, userB, options)
+		return m.CreateDirectRoomFunc(ctx, userA, userB, options)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("CreateDirectRoom", 2, userA, userB, options)
+	if !ok {
+		r1 = unexpectedCall("CreateDirectRoom", userA, userB, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetRoomsByID(ctx context.Context, roomIDs []string) ([]chatkit.Room, error) {
+	if m.GetRoomsByIDFunc != nil {
+		m.record("GetRoomsByID", roomIDs)
+		return m.GetRoomsByIDFunc(ctx, roomIDs)
+	}
+
+	var r0 []chatkit.Room
+	var r1 error
+	returns, ok := m.called("GetRoomsByID", 2, roomIDs)
+	if !ok {
+		r1 = unexpectedCall("GetRoomsByID", roomIDs)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GetRoomCounts(ctx context.Context, roomID string) (chatkit.RoomCounts, error) {
+	if m.GetRoomCountsFunc != nil {
+		m.record("GetRoomCounts", roomID)
+		return m.GetRoomCountsFunc(ctx, roomID)
+	}
+
+	var r0 chatkit.RoomCounts
+	var r1 error
+	returns, ok := m.called("GetRoomCounts", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("GetRoomCounts", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.RoomCounts)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) WaitForDelete(ctx context.Context, jobID string, interval time.Duration) (chatkit.DeleteStatus, error) {
+	if m.WaitForDeleteFunc != nil {
+		m.record("WaitForDelete", jobID, interval)
+		return m.WaitForDeleteFunc(ctx, jobID, interval)
+	}
+
+	var r0 chatkit.DeleteStatus
+	var r1 error
+	returns, ok := m.called("WaitForDelete", 2, jobID, interval)
+	if !ok {
+		r1 = unexpectedCall("WaitForDelete", jobID, interval)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.DeleteStatus)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GenerateScopedToken(ctx context.Context, options chatkit.ScopedTokenOptions) (auth.TokenWithExpiry, error) {
+	if m.GenerateScopedTokenFunc != nil {
+		m.record("GenerateScopedToken", options)
+		return m.GenerateScopedTokenFunc(ctx, options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("GenerateScopedToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("GenerateScopedToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) GenerateReadOnlyToken(ctx context.Context, options chatkit.ReadOnlyTokenOptions) (auth.TokenWithExpiry, error) {
+	if m.GenerateReadOnlyTokenFunc != nil {
+		m.record("GenerateReadOnlyToken", options)
+		return m.GenerateReadOnlyTokenFunc(ctx, options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("GenerateReadOnlyToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("GenerateReadOnlyToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SearchRoomMessages(ctx context.Context, roomID string, query string, options chatkit.SearchRoomMessagesOptions) ([]chatkit.MessageSearchResult, error) {
+	if m.SearchRoomMessagesFunc != nil {
+		m.record("SearchRoomMessages", roomID, query, options)
+		return m.SearchRoomMessagesFunc(ctx, roomID, query, options)
+	}
+
+	var r0 []chatkit.MessageSearchResult
+	var r1 error
+	returns, ok := m.called("SearchRoomMessages", 2, roomID, query, options)
+	if !ok {
+		r1 = unexpectedCall("SearchRoomMessages", roomID, query, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MessageSearchResult)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) Stats() chatkit.Stats {
+	if m.StatsFunc != nil {
+		m.record("Stats")
+		return m.StatsFunc()
+	}
+
+	var r0 chatkit.Stats
+	returns, ok := m.called("Stats", 1)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Stats)
+	}
+	return r0
+}
+
+func (m *MockClient) NewSubscriptionManager(options chatkit.SubscriptionManagerOptions) *chatkit.SubscriptionManager {
+	if m.NewSubscriptionManagerFunc != nil {
+		m.record("NewSubscriptionManager", options)
+		return m.NewSubscriptionManagerFunc(options)
+	}
+
+	var r0 *chatkit.SubscriptionManager
+	returns, ok := m.called("NewSubscriptionManager", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.SubscriptionManager)
+	}
+	return r0
+}
+
+func (m *MockClient) SubscribeToRoomMessages(ctx context.Context, roomID string, options chatkit.SubscribeToRoomMessagesOptions) (<-chan chatkit.MultipartMessage, error) {
+	if m.SubscribeToRoomMessagesFunc != nil {
+		m.record("SubscribeToRoomMessages", roomID, options)
+		return m.SubscribeToRoomMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 <-chan chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("SubscribeToRoomMessages", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("SubscribeToRoomMessages", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(<-chan chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SubscribeToUserEvents(ctx context.Context, userID string, options chatkit.SubscriptionOptions) (<-chan chatkit.UserSubscriptionEvent, error) {
+	if m.SubscribeToUserEventsFunc != nil {
+		m.record("SubscribeToUserEvents", userID, options)
+		return m.SubscribeToUserEventsFunc(ctx, userID, options)
+	}
+
+	var r0 <-chan chatkit.UserSubscriptionEvent
+	var r1 error
+	returns, ok := m.called("SubscribeToUserEvents", 2, userID, options)
+	if !ok {
+		r1 = unexpectedCall("SubscribeToUserEvents", userID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(<-chan chatkit.UserSubscriptionEvent)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SubscribeToRoomMemberships(ctx context.Context, roomID string, options chatkit.SubscriptionOptions) (<-chan chatkit.MembershipEvent, error) {
+	if m.SubscribeToRoomMembershipsFunc != nil {
+		m.record("SubscribeToRoomMemberships", roomID, options)
+		return m.SubscribeToRoomMembershipsFunc(ctx, roomID, options)
+	}
+
+	var r0 <-chan chatkit.MembershipEvent
+	var r1 error
+	returns, ok := m.called("SubscribeToRoomMemberships", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("SubscribeToRoomMemberships", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(<-chan chatkit.MembershipEvent)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SubscribeToPresence(ctx context.Context, userIDs []string, options chatkit.SubscriptionOptions) (<-chan chatkit.UserPresence, error) {
+	if m.SubscribeToPresenceFunc != nil {
+		m.record("SubscribeToPresence", userIDs, options)
+		return m.SubscribeToPresenceFunc(ctx, userIDs, options)
+	}
+
+	var r0 <-chan chatkit.UserPresence
+	var r1 error
+	returns, ok := m.called("SubscribeToPresence", 2, userIDs, options)
+	if !ok {
+		r1 = unexpectedCall("SubscribeToPresence", userIDs, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(<-chan chatkit.UserPresence)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) SendSystemMessage(ctx context.Context, options chatkit.SendSystemMessageOptions) (uint, error) {
+	if m.SendSystemMessageFunc != nil {
+		m.record("SendSystemMessage", options)
+		return m.SendSystemMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("SendSystemMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("SendSystemMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) FetchThread(ctx context.Context, roomID string, parentID uint, options chatkit.FetchThreadOptions) ([]chatkit.MultipartMessage, error) {
+	if m.FetchThreadFunc != nil {
+		m.record("FetchThread", roomID, parentID, options)
+		return m.FetchThreadFunc(ctx, roomID, parentID, options)
+	}
+
+	var r0 []chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("FetchThread", 2, roomID, parentID, options)
+	if !ok {
+		r1 = unexpectedCall("FetchThread", roomID, parentID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (m *MockClient) TokenProviderHandler(options chatkit.TokenProviderOptions) http.Handler {
+	if m.TokenProviderHandlerFunc != nil {
+		m.record("TokenProviderHandler", options)
+		return m.TokenProviderHandlerFunc(options)
+	}
+
+	var r0 http.Handler
+	returns, ok := m.called("TokenProviderHandler", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(http.Handler)
+	}
+	return r0
+}
+
+func (m *MockClient) IterateUsers(ctx context.Context, options chatkit.IterateUsersOptions) *chatkit.UsersIterator {
+	if m.IterateUsersFunc != nil {
+		m.record("IterateUsers", options)
+		return m.IterateUsersFunc(ctx, options)
+	}
+
+	var r0 *chatkit.UsersIterator
+	returns, ok := m.called("IterateUsers", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.UsersIterator)
+	}
+	return r0
+}
+
+func (m *MockClient) SearchUsers(ctx context.Context, query string, options chatkit.SearchUsersOptions) *chatkit.UsersIterator {
+	if m.SearchUsersFunc != nil {
+		m.record("SearchUsers", query, options)
+		return m.SearchUsersFunc(ctx, query, options)
+	}
+
+	var r0 *chatkit.UsersIterator
+	returns, ok := m.called("SearchUsers", 1, query, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.UsersIterator)
+	}
+	return r0
+}
+
+func (m *MockClient) UpdateUsers(ctx context.Context, updates map[string]chatkit.UpdateUserOptions, options chatkit.UpdateUsersOptions) *chatkit.BatchResult {
+	if m.UpdateUsersFunc != nil {
+		m.record("UpdateUsers", updates, options)
+		return m.UpdateUsersFunc(ctx, updates, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("UpdateUsers", 1, updates, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (m *MockClient) RenameUser(ctx context.Context, userID string, options chatkit.RenameUserOptions) error {
+	if m.RenameUserFunc != nil {
+		m.record("RenameUser", userID, options)
+		return m.RenameUserFunc(ctx, userID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("RenameUser", 1, userID, options)
+	if !ok {
+		r0 = unexpectedCall("RenameUser", userID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockUsers) GetUser(ctx context.Context, userID string) (chatkit.User, error) {
+	m := sub.m
+	if m.UsersGetUserFunc != nil {
+		m.record("Users.GetUser", userID)
+		return m.UsersGetUserFunc(ctx, userID)
+	}
+
+	var r0 chatkit.User
+	var r1 error
+	returns, ok := m.called("Users.GetUser", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("Users.GetUser", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockUsers) GetUsers(ctx context.Context, options *chatkit.GetUsersOptions) ([]chatkit.User, error) {
+	m := sub.m
+	if m.UsersGetUsersFunc != nil {
+		m.record("Users.GetUsers", options)
+		return m.UsersGetUsersFunc(ctx, options)
+	}
+
+	var r0 []chatkit.User
+	var r1 error
+	returns, ok := m.called("Users.GetUsers", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Users.GetUsers", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockUsers) CreateUser(ctx context.Context, options chatkit.CreateUserOptions) error {
+	m := sub.m
+	if m.UsersCreateUserFunc != nil {
+		m.record("Users.CreateUser", options)
+		return m.UsersCreateUserFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Users.CreateUser", 1, options)
+	if !ok {
+		r0 = unexpectedCall("Users.CreateUser", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockUsers) CreateUserAndGet(ctx context.Context, options chatkit.CreateUserOptions) (chatkit.User, error) {
+	m := sub.m
+	if m.UsersCreateUserAndGetFunc != nil {
+		m.record("Users.CreateUserAndGet", options)
+		return m.UsersCreateUserAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.User
+	var r1 error
+	returns, ok := m.called("Users.CreateUserAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Users.CreateUserAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockUsers) CreateUsers(ctx context.Context, users []chatkit.CreateUserOptions) error {
+	m := sub.m
+	if m.UsersCreateUsersFunc != nil {
+		m.record("Users.CreateUsers", users)
+		return m.UsersCreateUsersFunc(ctx, users)
+	}
+
+	var r0 error
+	returns, ok := m.called("Users.CreateUsers", 1, users)
+	if !ok {
+		r0 = unexpectedCall("Users.CreateUsers", users)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockUsers) CreateUsersInBatches(ctx context.Context, users []chatkit.CreateUserOptions, options chatkit.BatchOptions) *chatkit.BatchResult {
+	m := sub.m
+	if m.UsersCreateUsersInBatchesFunc != nil {
+		m.record("Users.CreateUsersInBatches", users, options)
+		return m.UsersCreateUsersInBatchesFunc(ctx, users, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("Users.CreateUsersInBatches", 1, users, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (sub mockUsers) UpdateUser(ctx context.Context, userID string, options chatkit.UpdateUserOptions) error {
+	m := sub.m
+	if m.UsersUpdateUserFunc != nil {
+		m.record("Users.UpdateUser", userID, options)
+		return m.UsersUpdateUserFunc(ctx, userID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Users.UpdateUser", 1, userID, options)
+	if !ok {
+		r0 = unexpectedCall("Users.UpdateUser", userID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockUsers) DeleteUser(ctx context.Context, userID string) error {
+	m := sub.m
+	if m.UsersDeleteUserFunc != nil {
+		m.record("Users.DeleteUser", userID)
+		return m.UsersDeleteUserFunc(ctx, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Users.DeleteUser", 1, userID)
+	if !ok {
+		r0 = unexpectedCall("Users.DeleteUser", userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockUsers) IterateUsers(ctx context.Context, options chatkit.IterateUsersOptions) *chatkit.UsersIterator {
+	m := sub.m
+	if m.UsersIterateUsersFunc != nil {
+		m.record("Users.IterateUsers", options)
+		return m.UsersIterateUsersFunc(ctx, options)
+	}
+
+	var r0 *chatkit.UsersIterator
+	returns, ok := m.called("Users.IterateUsers", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.UsersIterator)
+	}
+	return r0
+}
+
+func (sub mockUsers) SearchUsers(ctx context.Context, query string, options chatkit.SearchUsersOptions) *chatkit.UsersIterator {
+	m := sub.m
+	if m.UsersSearchUsersFunc != nil {
+		m.record("Users.SearchUsers", query, options)
+		return m.UsersSearchUsersFunc(ctx, query, options)
+	}
+
+	var r0 *chatkit.UsersIterator
+	returns, ok := m.called("Users.SearchUsers", 1, query, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.UsersIterator)
+	}
+	return r0
+}
+
+func (sub mockUsers) GetUsersByID(ctx context.Context, userIDs []string) ([]chatkit.User, error) {
+	m := sub.m
+	if m.UsersGetUsersByIDFunc != nil {
+		m.record("Users.GetUsersByID", userIDs)
+		return m.UsersGetUsersByIDFunc(ctx, userIDs)
+	}
+
+	var r0 []chatkit.User
+	var r1 error
+	returns, ok := m.called("Users.GetUsersByID", 2, userIDs)
+	if !ok {
+		r1 = unexpectedCall("Users.GetUsersByID", userIDs)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.User)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockUsers) UpdateUsers(ctx context.Context, updates map[string]chatkit.UpdateUserOptions, options chatkit.UpdateUsersOptions) *chatkit.BatchResult {
+	m := sub.m
+	if m.UsersUpdateUsersFunc != nil {
+		m.record("Users.UpdateUsers", updates, options)
+		return m.UsersUpdateUsersFunc(ctx, updates, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("Users.UpdateUsers", 1, updates, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (sub mockUsers) RenameUser(ctx context.Context, userID string, options chatkit.RenameUserOptions) error {
+	m := sub.m
+	if m.UsersRenameUserFunc != nil {
+		m.record("Users.RenameUser", userID, options)
+		return m.UsersRenameUserFunc(ctx, userID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Users.RenameUser", 1, userID, options)
+	if !ok {
+		r0 = unexpectedCall("Users.RenameUser", userID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRooms) GetRoom(ctx context.Context, roomID string) (chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsGetRoomFunc != nil {
+		m.record("Rooms.GetRoom", roomID)
+		return m.RoomsGetRoomFunc(ctx, roomID)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.GetRoom", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("Rooms.GetRoom", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) GetRooms(ctx context.Context, options chatkit.GetRoomsOptions) ([]core.RoomWithoutMembers, error) {
+	m := sub.m
+	if m.RoomsGetRoomsFunc != nil {
+		m.record("Rooms.GetRooms", options)
+		return m.RoomsGetRoomsFunc(ctx, options)
+	}
+
+	var r0 []core.RoomWithoutMembers
+	var r1 error
+	returns, ok := m.called("Rooms.GetRooms", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Rooms.GetRooms", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]core.RoomWithoutMembers)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) GetUserRooms(ctx context.Context, userID string) ([]chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsGetUserRoomsFunc != nil {
+		m.record("Rooms.GetUserRooms", userID)
+		return m.RoomsGetUserRoomsFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.GetUserRooms", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("Rooms.GetUserRooms", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) GetUserJoinableRooms(ctx context.Context, userID string) ([]chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsGetUserJoinableRoomsFunc != nil {
+		m.record("Rooms.GetUserJoinableRooms", userID)
+		return m.RoomsGetUserJoinableRoomsFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.GetUserJoinableRooms", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("Rooms.GetUserJoinableRooms", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) CreateRoom(ctx context.Context, options chatkit.CreateRoomOptions) (chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsCreateRoomFunc != nil {
+		m.record("Rooms.CreateRoom", options)
+		return m.RoomsCreateRoomFunc(ctx, options)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.CreateRoom", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Rooms.CreateRoom", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) UpdateRoom(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) error {
+	m := sub.m
+	if m.RoomsUpdateRoomFunc != nil {
+		m.record("Rooms.UpdateRoom", roomID, options)
+		return m.RoomsUpdateRoomFunc(ctx, roomID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Rooms.UpdateRoom", 1, roomID, options)
+	if !ok {
+		r0 = unexpectedCall("Rooms.UpdateRoom", roomID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRooms) UpdateRoomAndGet(ctx context.Context, roomID string, options chatkit.UpdateRoomOptions) (chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsUpdateRoomAndGetFunc != nil {
+		m.record("Rooms.UpdateRoomAndGet", roomID, options)
+		return m.RoomsUpdateRoomAndGetFunc(ctx, roomID, options)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.UpdateRoomAndGet", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("Rooms.UpdateRoomAndGet", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) DeleteRoom(ctx context.Context, roomID string) error {
+	m := sub.m
+	if m.RoomsDeleteRoomFunc != nil {
+		m.record("Rooms.DeleteRoom", roomID)
+		return m.RoomsDeleteRoomFunc(ctx, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Rooms.DeleteRoom", 1, roomID)
+	if !ok {
+		r0 = unexpectedCall("Rooms.DeleteRoom", roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRooms) AsyncDeleteRoom(ctx context.Context, roomID string) (string, error) {
+	m := sub.m
+	if m.RoomsAsyncDeleteRoomFunc != nil {
+		m.record("Rooms.AsyncDeleteRoom", roomID)
+		return m.RoomsAsyncDeleteRoomFunc(ctx, roomID)
+	}
+
+	var r0 string
+	var r1 error
+	returns, ok := m.called("Rooms.AsyncDeleteRoom", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("Rooms.AsyncDeleteRoom", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) GetDeleteStatus(ctx context.Context, jobID string) (chatkit.DeleteStatus, error) {
+	m := sub.m
+	if m.RoomsGetDeleteStatusFunc != nil {
+		m.record("Rooms.GetDeleteStatus", jobID)
+		return m.RoomsGetDeleteStatusFunc(ctx, jobID)
+	}
+
+	var r0 chatkit.DeleteStatus
+	var r1 error
+	returns, ok := m.called("Rooms.GetDeleteStatus", 2, jobID)
+	if !ok {
+		r1 = unexpectedCall("Rooms.GetDeleteStatus", jobID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.DeleteStatus)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
+	m := sub.m
+	if m.RoomsAddUsersToRoomFunc != nil {
+		m.record("Rooms.AddUsersToRoom", roomID, userIDs)
+		return m.RoomsAddUsersToRoomFunc(ctx, roomID, userIDs)
+	}
+
+	var r0 error
+	returns, ok := m.called("Rooms.AddUsersToRoom", 1, roomID, userIDs)
+	if !ok {
+		r0 = unexpectedCall("Rooms.AddUsersToRoom", roomID, userIDs)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRooms) RemoveUsersFromRoom(ctx context.Context, roomID string, userIDs []string) error {
+	m := sub.m
+	if m.RoomsRemoveUsersFromRoomFunc != nil {
+		m.record("Rooms.RemoveUsersFromRoom", roomID, userIDs)
+		return m.RoomsRemoveUsersFromRoomFunc(ctx, roomID, userIDs)
+	}
+
+	var r0 error
+	returns, ok := m.called("Rooms.RemoveUsersFromRoom", 1, roomID, userIDs)
+	if !ok {
+		r0 = unexpectedCall("Rooms.RemoveUsersFromRoom", roomID, userIDs)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRooms) JoinRoom(ctx context.Context, roomID string, userID string) (chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsJoinRoomFunc != nil {
+		m.record("Rooms.JoinRoom", roomID, userID)
+		return m.RoomsJoinRoomFunc(ctx, roomID, userID)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.JoinRoom", 2, roomID, userID)
+	if !ok {
+		r1 = unexpectedCall("Rooms.JoinRoom", roomID, userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) LeaveRoom(ctx context.Context, roomID string, userID string) error {
+	m := sub.m
+	if m.RoomsLeaveRoomFunc != nil {
+		m.record("Rooms.LeaveRoom", roomID, userID)
+		return m.RoomsLeaveRoomFunc(ctx, roomID, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Rooms.LeaveRoom", 1, roomID, userID)
+	if !ok {
+		r0 = unexpectedCall("Rooms.LeaveRoom", roomID, userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRooms) TransferRoomOwnership(ctx context.Context, roomID string, newOwnerID string, options chatkit.TransferRoomOwnershipOptions) error {
+	m := sub.m
+	if m.RoomsTransferRoomOwnershipFunc != nil {
+		m.record("Rooms.TransferRoomOwnership", roomID, newOwnerID, options)
+		return m.RoomsTransferRoomOwnershipFunc(ctx, roomID, newOwnerID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Rooms.TransferRoomOwnership", 1, roomID, newOwnerID, options)
+	if !ok {
+		r0 = unexpectedCall("Rooms.TransferRoomOwnership", roomID, newOwnerID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRooms) IterateRooms(ctx context.Context, options chatkit.IterateRoomsOptions) *chatkit.RoomsIterator {
+	m := sub.m
+	if m.RoomsIterateRoomsFunc != nil {
+		m.record("Rooms.IterateRooms", options)
+		return m.RoomsIterateRoomsFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RoomsIterator
+	returns, ok := m.called("Rooms.IterateRooms", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RoomsIterator)
+	}
+	return r0
+}
+
+func (sub mockRooms) CreateDirectRoom(ctx context.Context, userA string, userB string, options chatkit.CreateDirectRoomOptions) (chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsCreateDirectRoomFunc != nil {
+		m.record("Rooms.CreateDirectRoom", userA, userB, options)
+		return m.RoomsCreateDirectRoomFunc(ctx, userA, userB, options)
+	}
+
+	var r0 chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.CreateDirectRoom", 2, userA, userB, options)
+	if !ok {
+		r1 = unexpectedCall("Rooms.CreateDirectRoom", userA, userB, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) GetRoomsByID(ctx context.Context, roomIDs []string) ([]chatkit.Room, error) {
+	m := sub.m
+	if m.RoomsGetRoomsByIDFunc != nil {
+		m.record("Rooms.GetRoomsByID", roomIDs)
+		return m.RoomsGetRoomsByIDFunc(ctx, roomIDs)
+	}
+
+	var r0 []chatkit.Room
+	var r1 error
+	returns, ok := m.called("Rooms.GetRoomsByID", 2, roomIDs)
+	if !ok {
+		r1 = unexpectedCall("Rooms.GetRoomsByID", roomIDs)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Room)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) GetRoomCounts(ctx context.Context, roomID string) (chatkit.RoomCounts, error) {
+	m := sub.m
+	if m.RoomsGetRoomCountsFunc != nil {
+		m.record("Rooms.GetRoomCounts", roomID)
+		return m.RoomsGetRoomCountsFunc(ctx, roomID)
+	}
+
+	var r0 chatkit.RoomCounts
+	var r1 error
+	returns, ok := m.called("Rooms.GetRoomCounts", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("Rooms.GetRoomCounts", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.RoomCounts)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRooms) WaitForDelete(ctx context.Context, jobID string, interval time.Duration) (chatkit.DeleteStatus, error) {
+	m := sub.m
+	if m.RoomsWaitForDeleteFunc != nil {
+		m.record("Rooms.WaitForDelete", jobID, interval)
+		return m.RoomsWaitForDeleteFunc(ctx, jobID, interval)
+	}
+
+	var r0 chatkit.DeleteStatus
+	var r1 error
+	returns, ok := m.called("Rooms.WaitForDelete", 2, jobID, interval)
+	if !ok {
+		r1 = unexpectedCall("Rooms.WaitForDelete", jobID, interval)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.DeleteStatus)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendMessage(ctx context.Context, options chatkit.SendMessageOptions) (uint, error) {
+	m := sub.m
+	if m.MessagesSendMessageFunc != nil {
+		m.record("Messages.SendMessage", options)
+		return m.MessagesSendMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("Messages.SendMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendMultipartMessage(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error) {
+	m := sub.m
+	if m.MessagesSendMultipartMessageFunc != nil {
+		m.record("Messages.SendMultipartMessage", options)
+		return m.MessagesSendMultipartMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("Messages.SendMultipartMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendMultipartMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendMessageAsService(ctx context.Context, options chatkit.SendMultipartMessageOptions) (uint, error) {
+	m := sub.m
+	if m.MessagesSendMessageAsServiceFunc != nil {
+		m.record("Messages.SendMessageAsService", options)
+		return m.MessagesSendMessageAsServiceFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("Messages.SendMessageAsService", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendMessageAsService", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendSimpleMessage(ctx context.Context, options chatkit.SendSimpleMessageOptions) (uint, error) {
+	m := sub.m
+	if m.MessagesSendSimpleMessageFunc != nil {
+		m.record("Messages.SendSimpleMessage", options)
+		return m.MessagesSendSimpleMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("Messages.SendSimpleMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendSimpleMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) GetRoomMessages(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.Message, error) {
+	m := sub.m
+	if m.MessagesGetRoomMessagesFunc != nil {
+		m.record("Messages.GetRoomMessages", roomID, options)
+		return m.MessagesGetRoomMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.Message
+	var r1 error
+	returns, ok := m.called("Messages.GetRoomMessages", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.GetRoomMessages", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Message)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) FetchMultipartMessage(ctx context.Context, options chatkit.FetchMultipartMessageOptions) (chatkit.MultipartMessage, error) {
+	m := sub.m
+	if m.MessagesFetchMultipartMessageFunc != nil {
+		m.record("Messages.FetchMultipartMessage", options)
+		return m.MessagesFetchMultipartMessageFunc(ctx, options)
+	}
+
+	var r0 chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("Messages.FetchMultipartMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.FetchMultipartMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) FetchMultipartMessages(ctx context.Context, roomID string, options chatkit.GetRoomMessagesOptions) ([]chatkit.MultipartMessage, error) {
+	m := sub.m
+	if m.MessagesFetchMultipartMessagesFunc != nil {
+		m.record("Messages.FetchMultipartMessages", roomID, options)
+		return m.MessagesFetchMultipartMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("Messages.FetchMultipartMessages", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.FetchMultipartMessages", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) DeleteMessage(ctx context.Context, options chatkit.DeleteMessageOptions) error {
+	m := sub.m
+	if m.MessagesDeleteMessageFunc != nil {
+		m.record("Messages.DeleteMessage", options)
+		return m.MessagesDeleteMessageFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.DeleteMessage", 1, options)
+	if !ok {
+		r0 = unexpectedCall("Messages.DeleteMessage", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) EditMessage(ctx context.Context, roomID string, messageID uint, options chatkit.EditMessageOptions) error {
+	m := sub.m
+	if m.MessagesEditMessageFunc != nil {
+		m.record("Messages.EditMessage", roomID, messageID, options)
+		return m.MessagesEditMessageFunc(ctx, roomID, messageID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.EditMessage", 1, roomID, messageID, options)
+	if !ok {
+		r0 = unexpectedCall("Messages.EditMessage", roomID, messageID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options chatkit.EditMultipartMessageOptions) error {
+	m := sub.m
+	if m.MessagesEditMultipartMessageFunc != nil {
+		m.record("Messages.EditMultipartMessage", roomID, messageID, options)
+		return m.MessagesEditMultipartMessageFunc(ctx, roomID, messageID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.EditMultipartMessage", 1, roomID, messageID, options)
+	if !ok {
+		r0 = unexpectedCall("Messages.EditMultipartMessage", roomID, messageID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) EditSimpleMessage(ctx context.Context, roomID string, messageID uint, options chatkit.EditSimpleMessageOptions) error {
+	m := sub.m
+	if m.MessagesEditSimpleMessageFunc != nil {
+		m.record("Messages.EditSimpleMessage", roomID, messageID, options)
+		return m.MessagesEditSimpleMessageFunc(ctx, roomID, messageID, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.EditSimpleMessage", 1, roomID, messageID, options)
+	if !ok {
+		r0 = unexpectedCall("Messages.EditSimpleMessage", roomID, messageID, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) IterateRoomMessages(ctx context.Context, roomID string, options chatkit.IterateRoomMessagesOptions) *chatkit.MessageIterator {
+	m := sub.m
+	if m.MessagesIterateRoomMessagesFunc != nil {
+		m.record("Messages.IterateRoomMessages", roomID, options)
+		return m.MessagesIterateRoomMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 *chatkit.MessageIterator
+	returns, ok := m.called("Messages.IterateRoomMessages", 1, roomID, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.MessageIterator)
+	}
+	return r0
+}
+
+func (sub mockMessages) GetMessages(ctx context.Context, roomID string, options chatkit.GetMessagesOptions) ([]chatkit.Message, error) {
+	m := sub.m
+	if m.MessagesGetMessagesFunc != nil {
+		m.record("Messages.GetMessages", roomID, options)
+		return m.MessagesGetMessagesFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.Message
+	var r1 error
+	returns, ok := m.called("Messages.GetMessages", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.GetMessages", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Message)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) FetchLatestMessagesForRooms(ctx context.Context, roomIDs []string, limit uint) (map[string][]chatkit.MultipartMessage, error) {
+	m := sub.m
+	if m.MessagesFetchLatestMessagesForRoomsFunc != nil {
+		m.record("Messages.FetchLatestMessagesForRooms", roomIDs, limit)
+		return m.MessagesFetchLatestMessagesForRoomsFunc(ctx, roomIDs, limit)
+	}
+
+	var r0 map[string][]chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("Messages.FetchLatestMessagesForRooms", 2, roomIDs, limit)
+	if !ok {
+		r1 = unexpectedCall("Messages.FetchLatestMessagesForRooms", roomIDs, limit)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(map[string][]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendMessageAndGet(ctx context.Context, options chatkit.SendMessageOptions) (chatkit.Message, error) {
+	m := sub.m
+	if m.MessagesSendMessageAndGetFunc != nil {
+		m.record("Messages.SendMessageAndGet", options)
+		return m.MessagesSendMessageAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.Message
+	var r1 error
+	returns, ok := m.called("Messages.SendMessageAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendMessageAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Message)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendMultipartMessageAndGet(ctx context.Context, options chatkit.SendMultipartMessageOptions) (chatkit.MultipartMessage, error) {
+	m := sub.m
+	if m.MessagesSendMultipartMessageAndGetFunc != nil {
+		m.record("Messages.SendMultipartMessageAndGet", options)
+		return m.MessagesSendMultipartMessageAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("Messages.SendMultipartMessageAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendMultipartMessageAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendSimpleMessageAndGet(ctx context.Context, options chatkit.SendSimpleMessageOptions) (chatkit.MultipartMessage, error) {
+	m := sub.m
+	if m.MessagesSendSimpleMessageAndGetFunc != nil {
+		m.record("Messages.SendSimpleMessageAndGet", options)
+		return m.MessagesSendSimpleMessageAndGetFunc(ctx, options)
+	}
+
+	var r0 chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("Messages.SendSimpleMessageAndGet", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendSimpleMessageAndGet", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) DeleteMessages(ctx context.Context, roomID string, messageIDs []uint, options chatkit.BatchOptions) *chatkit.BatchResult {
+	m := sub.m
+	if m.MessagesDeleteMessagesFunc != nil {
+		m.record("Messages.DeleteMessages", roomID, messageIDs, options)
+		return m.MessagesDeleteMessagesFunc(ctx, roomID, messageIDs, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("Messages.DeleteMessages", 1, roomID, messageIDs, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (sub mockMessages) PinMessage(ctx context.Context, roomID string, messageID uint) error {
+	m := sub.m
+	if m.MessagesPinMessageFunc != nil {
+		m.record("Messages.PinMessage", roomID, messageID)
+		return m.MessagesPinMessageFunc(ctx, roomID, messageID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.PinMessage", 1, roomID, messageID)
+	if !ok {
+		r0 = unexpectedCall("Messages.PinMessage", roomID, messageID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) UnpinMessage(ctx context.Context, roomID string, messageID uint) error {
+	m := sub.m
+	if m.MessagesUnpinMessageFunc != nil {
+		m.record("Messages.UnpinMessage", roomID, messageID)
+		return m.MessagesUnpinMessageFunc(ctx, roomID, messageID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.UnpinMessage", 1, roomID, messageID)
+	if !ok {
+		r0 = unexpectedCall("Messages.UnpinMessage", roomID, messageID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) GetPinnedMessages(ctx context.Context, roomID string) ([]chatkit.MultipartMessage, error) {
+	m := sub.m
+	if m.MessagesGetPinnedMessagesFunc != nil {
+		m.record("Messages.GetPinnedMessages", roomID)
+		return m.MessagesGetPinnedMessagesFunc(ctx, roomID)
+	}
+
+	var r0 []chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("Messages.GetPinnedMessages", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("Messages.GetPinnedMessages", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) AddReaction(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error {
+	m := sub.m
+	if m.MessagesAddReactionFunc != nil {
+		m.record("Messages.AddReaction", roomID, messageID, userID, reaction)
+		return m.MessagesAddReactionFunc(ctx, roomID, messageID, userID, reaction)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.AddReaction", 1, roomID, messageID, userID, reaction)
+	if !ok {
+		r0 = unexpectedCall("Messages.AddReaction", roomID, messageID, userID, reaction)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) RemoveReaction(ctx context.Context, roomID string, messageID uint, userID string, reaction string) error {
+	m := sub.m
+	if m.MessagesRemoveReactionFunc != nil {
+		m.record("Messages.RemoveReaction", roomID, messageID, userID, reaction)
+		return m.MessagesRemoveReactionFunc(ctx, roomID, messageID, userID, reaction)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.RemoveReaction", 1, roomID, messageID, userID, reaction)
+	if !ok {
+		r0 = unexpectedCall("Messages.RemoveReaction", roomID, messageID, userID, reaction)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) RedactMessage(ctx context.Context, roomID string, messageID uint, replacementText string) error {
+	m := sub.m
+	if m.MessagesRedactMessageFunc != nil {
+		m.record("Messages.RedactMessage", roomID, messageID, replacementText)
+		return m.MessagesRedactMessageFunc(ctx, roomID, messageID, replacementText)
+	}
+
+	var r0 error
+	returns, ok := m.called("Messages.RedactMessage", 1, roomID, messageID, replacementText)
+	if !ok {
+		r0 = unexpectedCall("Messages.RedactMessage", roomID, messageID, replacementText)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockMessages) SearchRoomMessages(ctx context.Context, roomID string, query string, options chatkit.SearchRoomMessagesOptions) ([]chatkit.MessageSearchResult, error) {
+	m := sub.m
+	if m.MessagesSearchRoomMessagesFunc != nil {
+		m.record("Messages.SearchRoomMessages", roomID, query, options)
+		return m.MessagesSearchRoomMessagesFunc(ctx, roomID, query, options)
+	}
+
+	var r0 []chatkit.MessageSearchResult
+	var r1 error
+	returns, ok := m.called("Messages.SearchRoomMessages", 2, roomID, query, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SearchRoomMessages", roomID, query, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MessageSearchResult)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) SendSystemMessage(ctx context.Context, options chatkit.SendSystemMessageOptions) (uint, error) {
+	m := sub.m
+	if m.MessagesSendSystemMessageFunc != nil {
+		m.record("Messages.SendSystemMessage", options)
+		return m.MessagesSendSystemMessageFunc(ctx, options)
+	}
+
+	var r0 uint
+	var r1 error
+	returns, ok := m.called("Messages.SendSystemMessage", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.SendSystemMessage", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(uint)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockMessages) FetchThread(ctx context.Context, roomID string, parentID uint, options chatkit.FetchThreadOptions) ([]chatkit.MultipartMessage, error) {
+	m := sub.m
+	if m.MessagesFetchThreadFunc != nil {
+		m.record("Messages.FetchThread", roomID, parentID, options)
+		return m.MessagesFetchThreadFunc(ctx, roomID, parentID, options)
+	}
+
+	var r0 []chatkit.MultipartMessage
+	var r1 error
+	returns, ok := m.called("Messages.FetchThread", 2, roomID, parentID, options)
+	if !ok {
+		r1 = unexpectedCall("Messages.FetchThread", roomID, parentID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.MultipartMessage)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) GetUserReadCursors(ctx context.Context, userID string) ([]chatkit.Cursor, error) {
+	m := sub.m
+	if m.CursorsGetUserReadCursorsFunc != nil {
+		m.record("Cursors.GetUserReadCursors", userID)
+		return m.CursorsGetUserReadCursorsFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("Cursors.GetUserReadCursors", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetUserReadCursors", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error {
+	m := sub.m
+	if m.CursorsSetReadCursorFunc != nil {
+		m.record("Cursors.SetReadCursor", userID, roomID, position)
+		return m.CursorsSetReadCursorFunc(ctx, userID, roomID, position)
+	}
+
+	var r0 error
+	returns, ok := m.called("Cursors.SetReadCursor", 1, userID, roomID, position)
+	if !ok {
+		r0 = unexpectedCall("Cursors.SetReadCursor", userID, roomID, position)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockCursors) GetReadCursorsForRoom(ctx context.Context, roomID string) ([]chatkit.Cursor, error) {
+	m := sub.m
+	if m.CursorsGetReadCursorsForRoomFunc != nil {
+		m.record("Cursors.GetReadCursorsForRoom", roomID)
+		return m.CursorsGetReadCursorsForRoomFunc(ctx, roomID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("Cursors.GetReadCursorsForRoom", 2, roomID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetReadCursorsForRoom", roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) GetReadCursorsForRoomPage(ctx context.Context, roomID string, options chatkit.GetReadCursorsForRoomOptions) ([]chatkit.Cursor, error) {
+	m := sub.m
+	if m.CursorsGetReadCursorsForRoomPageFunc != nil {
+		m.record("Cursors.GetReadCursorsForRoomPage", roomID, options)
+		return m.CursorsGetReadCursorsForRoomPageFunc(ctx, roomID, options)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("Cursors.GetReadCursorsForRoomPage", 2, roomID, options)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetReadCursorsForRoomPage", roomID, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) GetReadCursor(ctx context.Context, userID string, roomID string) (chatkit.Cursor, error) {
+	m := sub.m
+	if m.CursorsGetReadCursorFunc != nil {
+		m.record("Cursors.GetReadCursor", userID, roomID)
+		return m.CursorsGetReadCursorFunc(ctx, userID, roomID)
+	}
+
+	var r0 chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("Cursors.GetReadCursor", 2, userID, roomID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetReadCursor", userID, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) DeleteReadCursor(ctx context.Context, userID string, roomID string) error {
+	m := sub.m
+	if m.CursorsDeleteReadCursorFunc != nil {
+		m.record("Cursors.DeleteReadCursor", userID, roomID)
+		return m.CursorsDeleteReadCursorFunc(ctx, userID, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Cursors.DeleteReadCursor", 1, userID, roomID)
+	if !ok {
+		r0 = unexpectedCall("Cursors.DeleteReadCursor", userID, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockCursors) GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]chatkit.Cursor, error) {
+	m := sub.m
+	if m.CursorsGetUserCursorsFunc != nil {
+		m.record("Cursors.GetUserCursors", cursorType, userID)
+		return m.CursorsGetUserCursorsFunc(ctx, cursorType, userID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("Cursors.GetUserCursors", 2, cursorType, userID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetUserCursors", cursorType, userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) SetCursor(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error {
+	m := sub.m
+	if m.CursorsSetCursorFunc != nil {
+		m.record("Cursors.SetCursor", cursorType, userID, roomID, position)
+		return m.CursorsSetCursorFunc(ctx, cursorType, userID, roomID, position)
+	}
+
+	var r0 error
+	returns, ok := m.called("Cursors.SetCursor", 1, cursorType, userID, roomID, position)
+	if !ok {
+		r0 = unexpectedCall("Cursors.SetCursor", cursorType, userID, roomID, position)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockCursors) GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]chatkit.Cursor, error) {
+	m := sub.m
+	if m.CursorsGetCursorsForRoomFunc != nil {
+		m.record("Cursors.GetCursorsForRoom", cursorType, roomID)
+		return m.CursorsGetCursorsForRoomFunc(ctx, cursorType, roomID)
+	}
+
+	var r0 []chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("Cursors.GetCursorsForRoom", 2, cursorType, roomID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetCursorsForRoom", cursorType, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) GetCursor(ctx context.Context, cursorType uint, userID string, roomID string) (chatkit.Cursor, error) {
+	m := sub.m
+	if m.CursorsGetCursorFunc != nil {
+		m.record("Cursors.GetCursor", cursorType, userID, roomID)
+		return m.CursorsGetCursorFunc(ctx, cursorType, userID, roomID)
+	}
+
+	var r0 chatkit.Cursor
+	var r1 error
+	returns, ok := m.called("Cursors.GetCursor", 2, cursorType, userID, roomID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetCursor", cursorType, userID, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Cursor)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error {
+	m := sub.m
+	if m.CursorsDeleteCursorFunc != nil {
+		m.record("Cursors.DeleteCursor", cursorType, userID, roomID)
+		return m.CursorsDeleteCursorFunc(ctx, cursorType, userID, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Cursors.DeleteCursor", 1, cursorType, userID, roomID)
+	if !ok {
+		r0 = unexpectedCall("Cursors.DeleteCursor", cursorType, userID, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockCursors) GetUnreadCounts(ctx context.Context, userID string) (map[string]chatkit.UnreadCount, error) {
+	m := sub.m
+	if m.CursorsGetUnreadCountsFunc != nil {
+		m.record("Cursors.GetUnreadCounts", userID)
+		return m.CursorsGetUnreadCountsFunc(ctx, userID)
+	}
+
+	var r0 map[string]chatkit.UnreadCount
+	var r1 error
+	returns, ok := m.called("Cursors.GetUnreadCounts", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetUnreadCounts", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(map[string]chatkit.UnreadCount)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockCursors) IterateRoomReadCursors(ctx context.Context, roomID string, options chatkit.IterateRoomReadCursorsOptions) *chatkit.CursorsIterator {
+	m := sub.m
+	if m.CursorsIterateRoomReadCursorsFunc != nil {
+		m.record("Cursors.IterateRoomReadCursors", roomID, options)
+		return m.CursorsIterateRoomReadCursorsFunc(ctx, roomID, options)
+	}
+
+	var r0 *chatkit.CursorsIterator
+	returns, ok := m.called("Cursors.IterateRoomReadCursors", 1, roomID, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.CursorsIterator)
+	}
+	return r0
+}
+
+func (sub mockCursors) GetMessageReadBy(ctx context.Context, roomID string, messageID uint) (chatkit.MessageReadBy, error) {
+	m := sub.m
+	if m.CursorsGetMessageReadByFunc != nil {
+		m.record("Cursors.GetMessageReadBy", roomID, messageID)
+		return m.CursorsGetMessageReadByFunc(ctx, roomID, messageID)
+	}
+
+	var r0 chatkit.MessageReadBy
+	var r1 error
+	returns, ok := m.called("Cursors.GetMessageReadBy", 2, roomID, messageID)
+	if !ok {
+		r1 = unexpectedCall("Cursors.GetMessageReadBy", roomID, messageID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.MessageReadBy)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) GetRoles(ctx context.Context) ([]chatkit.Role, error) {
+	m := sub.m
+	if m.RolesGetRolesFunc != nil {
+		m.record("Roles.GetRoles")
+		return m.RolesGetRolesFunc(ctx)
+	}
+
+	var r0 []chatkit.Role
+	var r1 error
+	returns, ok := m.called("Roles.GetRoles", 2)
+	if !ok {
+		r1 = unexpectedCall("Roles.GetRoles")
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Role)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) CreateGlobalRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	m := sub.m
+	if m.RolesCreateGlobalRoleFunc != nil {
+		m.record("Roles.CreateGlobalRole", options)
+		return m.RolesCreateGlobalRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.CreateGlobalRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("Roles.CreateGlobalRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) CreateRoomRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	m := sub.m
+	if m.RolesCreateRoomRoleFunc != nil {
+		m.record("Roles.CreateRoomRole", options)
+		return m.RolesCreateRoomRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.CreateRoomRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("Roles.CreateRoomRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) DeleteGlobalRole(ctx context.Context, roleName string) error {
+	m := sub.m
+	if m.RolesDeleteGlobalRoleFunc != nil {
+		m.record("Roles.DeleteGlobalRole", roleName)
+		return m.RolesDeleteGlobalRoleFunc(ctx, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.DeleteGlobalRole", 1, roleName)
+	if !ok {
+		r0 = unexpectedCall("Roles.DeleteGlobalRole", roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) DeleteRoomRole(ctx context.Context, roleName string) error {
+	m := sub.m
+	if m.RolesDeleteRoomRoleFunc != nil {
+		m.record("Roles.DeleteRoomRole", roleName)
+		return m.RolesDeleteRoomRoleFunc(ctx, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.DeleteRoomRole", 1, roleName)
+	if !ok {
+		r0 = unexpectedCall("Roles.DeleteRoomRole", roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) GetPermissionsForGlobalRole(ctx context.Context, roleName string) ([]string, error) {
+	m := sub.m
+	if m.RolesGetPermissionsForGlobalRoleFunc != nil {
+		m.record("Roles.GetPermissionsForGlobalRole", roleName)
+		return m.RolesGetPermissionsForGlobalRoleFunc(ctx, roleName)
+	}
+
+	var r0 []string
+	var r1 error
+	returns, ok := m.called("Roles.GetPermissionsForGlobalRole", 2, roleName)
+	if !ok {
+		r1 = unexpectedCall("Roles.GetPermissionsForGlobalRole", roleName)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) GetPermissionsForRoomRole(ctx context.Context, roleName string) ([]string, error) {
+	m := sub.m
+	if m.RolesGetPermissionsForRoomRoleFunc != nil {
+		m.record("Roles.GetPermissionsForRoomRole", roleName)
+		return m.RolesGetPermissionsForRoomRoleFunc(ctx, roleName)
+	}
+
+	var r0 []string
+	var r1 error
+	returns, ok := m.called("Roles.GetPermissionsForRoomRole", 2, roleName)
+	if !ok {
+		r1 = unexpectedCall("Roles.GetPermissionsForRoomRole", roleName)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) UpdatePermissionsForGlobalRole(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error {
+	m := sub.m
+	if m.RolesUpdatePermissionsForGlobalRoleFunc != nil {
+		m.record("Roles.UpdatePermissionsForGlobalRole", roleName, options)
+		return m.RolesUpdatePermissionsForGlobalRoleFunc(ctx, roleName, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.UpdatePermissionsForGlobalRole", 1, roleName, options)
+	if !ok {
+		r0 = unexpectedCall("Roles.UpdatePermissionsForGlobalRole", roleName, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) UpdatePermissionsForRoomRole(ctx context.Context, roleName string, options chatkit.UpdateRolePermissionsOptions) error {
+	m := sub.m
+	if m.RolesUpdatePermissionsForRoomRoleFunc != nil {
+		m.record("Roles.UpdatePermissionsForRoomRole", roleName, options)
+		return m.RolesUpdatePermissionsForRoomRoleFunc(ctx, roleName, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.UpdatePermissionsForRoomRole", 1, roleName, options)
+	if !ok {
+		r0 = unexpectedCall("Roles.UpdatePermissionsForRoomRole", roleName, options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) GetUserRoles(ctx context.Context, userID string) ([]chatkit.Role, error) {
+	m := sub.m
+	if m.RolesGetUserRolesFunc != nil {
+		m.record("Roles.GetUserRoles", userID)
+		return m.RolesGetUserRolesFunc(ctx, userID)
+	}
+
+	var r0 []chatkit.Role
+	var r1 error
+	returns, ok := m.called("Roles.GetUserRoles", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("Roles.GetUserRoles", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.Role)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) AssignGlobalRoleToUser(ctx context.Context, userID string, roleName string) error {
+	m := sub.m
+	if m.RolesAssignGlobalRoleToUserFunc != nil {
+		m.record("Roles.AssignGlobalRoleToUser", userID, roleName)
+		return m.RolesAssignGlobalRoleToUserFunc(ctx, userID, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.AssignGlobalRoleToUser", 1, userID, roleName)
+	if !ok {
+		r0 = unexpectedCall("Roles.AssignGlobalRoleToUser", userID, roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) AssignRoomRoleToUser(ctx context.Context, userID string, roomID string, roleName string) error {
+	m := sub.m
+	if m.RolesAssignRoomRoleToUserFunc != nil {
+		m.record("Roles.AssignRoomRoleToUser", userID, roomID, roleName)
+		return m.RolesAssignRoomRoleToUserFunc(ctx, userID, roomID, roleName)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.AssignRoomRoleToUser", 1, userID, roomID, roleName)
+	if !ok {
+		r0 = unexpectedCall("Roles.AssignRoomRoleToUser", userID, roomID, roleName)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) RemoveGlobalRoleForUser(ctx context.Context, userID string) error {
+	m := sub.m
+	if m.RolesRemoveGlobalRoleForUserFunc != nil {
+		m.record("Roles.RemoveGlobalRoleForUser", userID)
+		return m.RolesRemoveGlobalRoleForUserFunc(ctx, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.RemoveGlobalRoleForUser", 1, userID)
+	if !ok {
+		r0 = unexpectedCall("Roles.RemoveGlobalRoleForUser", userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) RemoveRoomRoleForUser(ctx context.Context, userID string, roomID string) error {
+	m := sub.m
+	if m.RolesRemoveRoomRoleForUserFunc != nil {
+		m.record("Roles.RemoveRoomRoleForUser", userID, roomID)
+		return m.RolesRemoveRoomRoleForUserFunc(ctx, userID, roomID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.RemoveRoomRoleForUser", 1, userID, roomID)
+	if !ok {
+		r0 = unexpectedCall("Roles.RemoveRoomRoleForUser", userID, roomID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) CreateDefaultRoles(ctx context.Context) error {
+	m := sub.m
+	if m.RolesCreateDefaultRolesFunc != nil {
+		m.record("Roles.CreateDefaultRoles")
+		return m.RolesCreateDefaultRolesFunc(ctx)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.CreateDefaultRoles", 1)
+	if !ok {
+		r0 = unexpectedCall("Roles.CreateDefaultRoles")
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) GetRole(ctx context.Context, name string, scope string) (chatkit.Role, error) {
+	m := sub.m
+	if m.RolesGetRoleFunc != nil {
+		m.record("Roles.GetRole", name, scope)
+		return m.RolesGetRoleFunc(ctx, name, scope)
+	}
+
+	var r0 chatkit.Role
+	var r1 error
+	returns, ok := m.called("Roles.GetRole", 2, name, scope)
+	if !ok {
+		r1 = unexpectedCall("Roles.GetRole", name, scope)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Role)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) UpsertGlobalRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	m := sub.m
+	if m.RolesUpsertGlobalRoleFunc != nil {
+		m.record("Roles.UpsertGlobalRole", options)
+		return m.RolesUpsertGlobalRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.UpsertGlobalRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("Roles.UpsertGlobalRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) UpsertRoomRole(ctx context.Context, options chatkit.CreateRoleOptions) error {
+	m := sub.m
+	if m.RolesUpsertRoomRoleFunc != nil {
+		m.record("Roles.UpsertRoomRole", options)
+		return m.RolesUpsertRoomRoleFunc(ctx, options)
+	}
+
+	var r0 error
+	returns, ok := m.called("Roles.UpsertRoomRole", 1, options)
+	if !ok {
+		r0 = unexpectedCall("Roles.UpsertRoomRole", options)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockRoles) GetEffectivePermissions(ctx context.Context, userID string, roomID string) ([]string, error) {
+	m := sub.m
+	if m.RolesGetEffectivePermissionsFunc != nil {
+		m.record("Roles.GetEffectivePermissions", userID, roomID)
+		return m.RolesGetEffectivePermissionsFunc(ctx, userID, roomID)
+	}
+
+	var r0 []string
+	var r1 error
+	returns, ok := m.called("Roles.GetEffectivePermissions", 2, userID, roomID)
+	if !ok {
+		r1 = unexpectedCall("Roles.GetEffectivePermissions", userID, roomID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) AssignGlobalRoleToUsers(ctx context.Context, userIDs []string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult {
+	m := sub.m
+	if m.RolesAssignGlobalRoleToUsersFunc != nil {
+		m.record("Roles.AssignGlobalRoleToUsers", userIDs, roleName, options)
+		return m.RolesAssignGlobalRoleToUsersFunc(ctx, userIDs, roleName, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("Roles.AssignGlobalRoleToUsers", 1, userIDs, roleName, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (sub mockRoles) AssignRoomRoleToUsers(ctx context.Context, userIDs []string, roomID string, roleName string, options chatkit.BatchOptions) *chatkit.BatchResult {
+	m := sub.m
+	if m.RolesAssignRoomRoleToUsersFunc != nil {
+		m.record("Roles.AssignRoomRoleToUsers", userIDs, roomID, roleName, options)
+		return m.RolesAssignRoomRoleToUsersFunc(ctx, userIDs, roomID, roleName, options)
+	}
+
+	var r0 *chatkit.BatchResult
+	returns, ok := m.called("Roles.AssignRoomRoleToUsers", 1, userIDs, roomID, roleName, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.BatchResult)
+	}
+	return r0
+}
+
+func (sub mockRoles) GetUsersWithRole(ctx context.Context, roleName string, scope string, options chatkit.GetUsersWithRoleOptions) ([]chatkit.RoleAssignment, error) {
+	m := sub.m
+	if m.RolesGetUsersWithRoleFunc != nil {
+		m.record("Roles.GetUsersWithRole", roleName, scope, options)
+		return m.RolesGetUsersWithRoleFunc(ctx, roleName, scope, options)
+	}
+
+	var r0 []chatkit.RoleAssignment
+	var r1 error
+	returns, ok := m.called("Roles.GetUsersWithRole", 2, roleName, scope, options)
+	if !ok {
+		r1 = unexpectedCall("Roles.GetUsersWithRole", roleName, scope, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.RoleAssignment)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) ListRoleAssignments(ctx context.Context, options chatkit.ListRoleAssignmentsOptions) *chatkit.RoleAssignmentsIterator {
+	m := sub.m
+	if m.RolesListRoleAssignmentsFunc != nil {
+		m.record("Roles.ListRoleAssignments", options)
+		return m.RolesListRoleAssignmentsFunc(ctx, options)
+	}
+
+	var r0 *chatkit.RoleAssignmentsIterator
+	returns, ok := m.called("Roles.ListRoleAssignments", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*chatkit.RoleAssignmentsIterator)
+	}
+	return r0
+}
+
+func (sub mockRoles) ApplyRolesConfig(ctx context.Context, config chatkit.RolesConfig, options chatkit.ApplyRolesConfigOptions) ([]chatkit.RoleChange, error) {
+	m := sub.m
+	if m.RolesApplyRolesConfigFunc != nil {
+		m.record("Roles.ApplyRolesConfig", config, options)
+		return m.RolesApplyRolesConfigFunc(ctx, config, options)
+	}
+
+	var r0 []chatkit.RoleChange
+	var r1 error
+	returns, ok := m.called("Roles.ApplyRolesConfig", 2, config, options)
+	if !ok {
+		r1 = unexpectedCall("Roles.ApplyRolesConfig", config, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.RoleChange)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) ExportRoles(ctx context.Context) (chatkit.RolesConfig, error) {
+	m := sub.m
+	if m.RolesExportRolesFunc != nil {
+		m.record("Roles.ExportRoles")
+		return m.RolesExportRolesFunc(ctx)
+	}
+
+	var r0 chatkit.RolesConfig
+	var r1 error
+	returns, ok := m.called("Roles.ExportRoles", 2)
+	if !ok {
+		r1 = unexpectedCall("Roles.ExportRoles")
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.RolesConfig)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockRoles) ImportRoles(ctx context.Context, config chatkit.RolesConfig, options chatkit.ImportRolesOptions) ([]chatkit.RoleChange, error) {
+	m := sub.m
+	if m.RolesImportRolesFunc != nil {
+		m.record("Roles.ImportRoles", config, options)
+		return m.RolesImportRolesFunc(ctx, config, options)
+	}
+
+	var r0 []chatkit.RoleChange
+	var r1 error
+	returns, ok := m.called("Roles.ImportRoles", 2, config, options)
+	if !ok {
+		r1 = unexpectedCall("Roles.ImportRoles", config, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].([]chatkit.RoleChange)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error) {
+	m := sub.m
+	if m.AuthAuthenticateFunc != nil {
+		m.record("Auth.Authenticate", payload, options)
+		return m.AuthAuthenticateFunc(payload, options)
+	}
+
+	var r0 *auth.Response
+	var r1 error
+	returns, ok := m.called("Auth.Authenticate", 2, payload, options)
+	if !ok {
+		r1 = unexpectedCall("Auth.Authenticate", payload, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(*auth.Response)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	m := sub.m
+	if m.AuthGenerateAccessTokenFunc != nil {
+		m.record("Auth.GenerateAccessToken", options)
+		return m.AuthGenerateAccessTokenFunc(options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("Auth.GenerateAccessToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Auth.GenerateAccessToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	m := sub.m
+	if m.AuthGenerateSUTokenFunc != nil {
+		m.record("Auth.GenerateSUToken", options)
+		return m.AuthGenerateSUTokenFunc(options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("Auth.GenerateSUToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Auth.GenerateSUToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) VerifyToken(ctx context.Context, tokenString string) (chatkit.Claims, error) {
+	m := sub.m
+	if m.AuthVerifyTokenFunc != nil {
+		m.record("Auth.VerifyToken", tokenString)
+		return m.AuthVerifyTokenFunc(ctx, tokenString)
+	}
+
+	var r0 chatkit.Claims
+	var r1 error
+	returns, ok := m.called("Auth.VerifyToken", 2, tokenString)
+	if !ok {
+		r1 = unexpectedCall("Auth.VerifyToken", tokenString)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.Claims)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) AuthMiddleware(next http.Handler, options chatkit.VerifierOptions) http.Handler {
+	m := sub.m
+	if m.AuthAuthMiddlewareFunc != nil {
+		m.record("Auth.AuthMiddleware", next, options)
+		return m.AuthAuthMiddlewareFunc(next, options)
+	}
+
+	var r0 http.Handler
+	returns, ok := m.called("Auth.AuthMiddleware", 1, next, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(http.Handler)
+	}
+	return r0
+}
+
+func (sub mockAuth) IssueRefreshToken(ctx context.Context, userID string) (string, error) {
+	m := sub.m
+	if m.AuthIssueRefreshTokenFunc != nil {
+		m.record("Auth.IssueRefreshToken", userID)
+		return m.AuthIssueRefreshTokenFunc(ctx, userID)
+	}
+
+	var r0 string
+	var r1 error
+	returns, ok := m.called("Auth.IssueRefreshToken", 2, userID)
+	if !ok {
+		r1 = unexpectedCall("Auth.IssueRefreshToken", userID)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(string)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) RefreshAccessToken(ctx context.Context, refreshToken string, options auth.Options) (chatkit.TokenResponse, error) {
+	m := sub.m
+	if m.AuthRefreshAccessTokenFunc != nil {
+		m.record("Auth.RefreshAccessToken", refreshToken, options)
+		return m.AuthRefreshAccessTokenFunc(ctx, refreshToken, options)
+	}
+
+	var r0 chatkit.TokenResponse
+	var r1 error
+	returns, ok := m.called("Auth.RefreshAccessToken", 2, refreshToken, options)
+	if !ok {
+		r1 = unexpectedCall("Auth.RefreshAccessToken", refreshToken, options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(chatkit.TokenResponse)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	m := sub.m
+	if m.AuthRevokeRefreshTokenFunc != nil {
+		m.record("Auth.RevokeRefreshToken", refreshToken)
+		return m.AuthRevokeRefreshTokenFunc(ctx, refreshToken)
+	}
+
+	var r0 error
+	returns, ok := m.called("Auth.RevokeRefreshToken", 1, refreshToken)
+	if !ok {
+		r0 = unexpectedCall("Auth.RevokeRefreshToken", refreshToken)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockAuth) RevokeToken(ctx context.Context, tokenString string) error {
+	m := sub.m
+	if m.AuthRevokeTokenFunc != nil {
+		m.record("Auth.RevokeToken", tokenString)
+		return m.AuthRevokeTokenFunc(ctx, tokenString)
+	}
+
+	var r0 error
+	returns, ok := m.called("Auth.RevokeToken", 1, tokenString)
+	if !ok {
+		r0 = unexpectedCall("Auth.RevokeToken", tokenString)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockAuth) RevokeTokensForUser(ctx context.Context, userID string) error {
+	m := sub.m
+	if m.AuthRevokeTokensForUserFunc != nil {
+		m.record("Auth.RevokeTokensForUser", userID)
+		return m.AuthRevokeTokensForUserFunc(ctx, userID)
+	}
+
+	var r0 error
+	returns, ok := m.called("Auth.RevokeTokensForUser", 1, userID)
+	if !ok {
+		r0 = unexpectedCall("Auth.RevokeTokensForUser", userID)
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(error)
+	}
+	return r0
+}
+
+func (sub mockAuth) GenerateScopedToken(ctx context.Context, options chatkit.ScopedTokenOptions) (auth.TokenWithExpiry, error) {
+	m := sub.m
+	if m.AuthGenerateScopedTokenFunc != nil {
+		m.record("Auth.GenerateScopedToken", options)
+		return m.AuthGenerateScopedTokenFunc(ctx, options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("Auth.GenerateScopedToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Auth.GenerateScopedToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) GenerateReadOnlyToken(ctx context.Context, options chatkit.ReadOnlyTokenOptions) (auth.TokenWithExpiry, error) {
+	m := sub.m
+	if m.AuthGenerateReadOnlyTokenFunc != nil {
+		m.record("Auth.GenerateReadOnlyToken", options)
+		return m.AuthGenerateReadOnlyTokenFunc(ctx, options)
+	}
+
+	var r0 auth.TokenWithExpiry
+	var r1 error
+	returns, ok := m.called("Auth.GenerateReadOnlyToken", 2, options)
+	if !ok {
+		r1 = unexpectedCall("Auth.GenerateReadOnlyToken", options)
+		return r0, r1
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(auth.TokenWithExpiry)
+	}
+	if returns[1] != nil {
+		r1 = returns[1].(error)
+	}
+	return r0, r1
+}
+
+func (sub mockAuth) TokenProviderHandler(options chatkit.TokenProviderOptions) http.Handler {
+	m := sub.m
+	if m.AuthTokenProviderHandlerFunc != nil {
+		m.record("Auth.TokenProviderHandler", options)
+		return m.AuthTokenProviderHandlerFunc(options)
+	}
+
+	var r0 http.Handler
+	returns, ok := m.called("Auth.TokenProviderHandler", 1, options)
+	if !ok {
+		return r0
+	}
+	if returns[0] != nil {
+		r0 = returns[0].(http.Handler)
+	}
+	return r0
+}
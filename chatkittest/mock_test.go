@@ -0,0 +1,160 @@
+package chatkittest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+func TestMockClientMocksSubClients(t *testing.T) {
+	mock := &MockClient{}
+	mock.On("Rooms.GetRoom", "general").Return(chatkit.Room{ID: "general", Name: "General"}, nil).Once()
+
+	var api chatkit.API = mock
+	room, err := api.Rooms().GetRoom(context.Background(), "general")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if room.Name != "General" {
+		t.Fatalf("Expected the canned room, got %+v", room)
+	}
+
+	mock.AssertExpectations(t)
+	mock.AssertCalled(t, "Rooms.GetRoom", "general")
+}
+
+func TestMockClientCallsSubClientFuncs(t *testing.T) {
+	mock := &MockClient{
+		UsersGetUserFunc: func(ctx context.Context, userID string) (chatkit.User, error) {
+			return chatkit.User{ID: userID, Name: "Alice"}, nil
+		},
+	}
+
+	user, err := mock.Users().GetUser(context.Background(), "alice")
+	if err != nil || user.Name != "Alice" {
+		t.Fatalf("Expected alice, got %+v and %v", user, err)
+	}
+
+	calls := mock.Calls()
+	if len(calls) != 1 || calls[0].Method != "Users.GetUser" {
+		t.Fatalf("Expected the call to be recorded as Users.GetUser, got %v", calls)
+	}
+}
+
+// recordingT is a TestingT recording the failures reported to it.
+type recordingT struct {
+	failures []string
+}
+
+func (t *recordingT) Helper() {}
+
+func (t *recordingT) Errorf(format string, args ...interface{}) {
+	t.failures = append(t.failures, fmt.Sprintf(format, args...))
+}
+
+func TestMockClientReturnsCannedResponses(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockClient{}
+	mock.On("GetUser", "alice").Return(chatkit.User{ID: "alice", Name: "Alice"}, nil)
+	mock.On("GetUser", "bob").Return(nil, errors.New("not found"))
+	mock.On("SendSimpleMessage", Anything).Return(uint(42), nil)
+
+	user, err := mock.GetUser(ctx, "alice")
+	if err != nil || user.Name != "Alice" {
+		t.Errorf("Expected alice, got %+v and %v", user, err)
+	}
+
+	user, err = mock.GetUser(ctx, "bob")
+	if err == nil || err.Error() != "not found" || user.ID != "" {
+		t.Errorf("Expected the zero user and the canned error, got %+v and %v", user, err)
+	}
+
+	messageID, err := mock.Messages().SendSimpleMessage(ctx, chatkit.SendSimpleMessageOptions{RoomID: "general"})
+	if err == nil || messageID != 0 {
+		t.Errorf("Expected Messages.SendSimpleMessage not to match SendSimpleMessage, got %d and %v", messageID, err)
+	}
+
+	messageID, err = mock.SendSimpleMessage(ctx, chatkit.SendSimpleMessageOptions{RoomID: "general"})
+	if err != nil || messageID != 42 {
+		t.Errorf("Expected message 42, got %d and %v", messageID, err)
+	}
+}
+
+func TestMockClientMatchesArguments(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockClient{}
+	mock.On("Rooms.AddUsersToRoom", "general", MatchedBy(func(arg interface{}) bool {
+		return len(arg.([]string)) == 1
+	})).Return(nil)
+	mock.On("Rooms.AddUsersToRoom", Anything, Anything).Return(errors.New("too many users"))
+
+	if err := mock.Rooms().AddUsersToRoom(ctx, "general", []string{"alice"}); err != nil {
+		t.Errorf("Expected the first expectation to match, got %v", err)
+	}
+	if err := mock.Rooms().AddUsersToRoom(ctx, "general", []string{"alice", "bob"}); err == nil {
+		t.Error("Expected the second expectation to match")
+	}
+
+	if !mock.AssertExpectations(t) {
+		t.Error("Expected the expectations to be met")
+	}
+}
+
+func TestMockClientExpectationTimes(t *testing.T) {
+	ctx := context.Background()
+	mock := &MockClient{}
+	mock.On("DeleteUser", "alice").Return(nil).Times(2)
+
+	for i := 0; i < 3; i++ {
+		err := mock.DeleteUser(ctx, "alice")
+		if i < 2 && err != nil {
+			t.Errorf("Expected call %d to match, got %v", i+1, err)
+		}
+		if i == 2 && (err == nil || !strings.Contains(err.Error(), `Unexpected call to DeleteUser("alice")`)) {
+			t.Errorf("Expected the third call to be unexpected, got %v", err)
+		}
+	}
+
+	recorder := &recordingT{}
+	if mock.AssertExpectations(recorder) {
+		t.Error("Expected the unexpected call to fail the assertion")
+	}
+	if len(recorder.failures) != 1 || !strings.Contains(recorder.failures[0], "Unexpected call") {
+		t.Errorf("Expected the unexpected call to be reported, got %v", recorder.failures)
+	}
+}
+
+func TestMockClientReportsUnmetExpectations(t *testing.T) {
+	mock := &MockClient{}
+	mock.On("GetRoom", "general").Return(chatkit.Room{}, nil)
+	mock.On("DeleteRoom", "general").Return(nil).Once()
+
+	recorder := &recordingT{}
+	if mock.AssertExpectations(recorder) {
+		t.Error("Expected the assertion to fail")
+	}
+	if len(recorder.failures) != 2 {
+		t.Errorf("Expected both expectations to be reported, got %v", recorder.failures)
+	}
+
+	recorder = &recordingT{}
+	if mock.AssertCalled(recorder, "GetRoom", "general") || len(recorder.failures) != 1 {
+		t.Errorf("Expected AssertCalled to fail, got %v", recorder.failures)
+	}
+}
+
+func TestMockClientPanicsOnWrongNumberOfReturns(t *testing.T) {
+	mock := &MockClient{}
+	mock.On("GetUser", "alice").Return(chatkit.User{})
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an expectation returning too few values")
+		}
+	}()
+	mock.GetUser(context.Background(), "alice")
+}
@@ -0,0 +1,351 @@
+package chatkittest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	redacted            = "[REDACTED]"
+	instanceIDRedaction = "{instance_id}"
+)
+
+// Query parameters and headers that may carry tokens, which are scrubbed from cassettes.
+var (
+	scrubbedQueryParams = map[string]bool{"jwt": true, "token": true, "access_token": true}
+	scrubbedHeaders     = []string{"Authorization", "Cookie", "Set-Cookie"}
+)
+
+// RecorderMode is whether a Recorder records interactions or replays them.
+type RecorderMode int
+
+const (
+	// ModeReplay replays the interactions of a cassette, and fails the requests it has none for.
+	ModeReplay RecorderMode = iota
+	// ModeRecord makes requests, and records them to the cassette when the Recorder is closed.
+	ModeRecord
+)
+
+// RecorderOptions contains parameters to pass when creating a Recorder.
+type RecorderOptions struct {
+	Mode RecorderMode
+	// Transport requests are made with when recording. Defaults to http.DefaultTransport.
+	Transport http.RoundTripper
+	// Strings to scrub from cassettes, e.g. personal data in custom data. They are replaced
+	// with "[REDACTED]", in requests as well before they are matched with recorded ones.
+	Secrets []string
+	// Optional function reporting whether the body of a request, scrubbed, matches the body of a
+	// recorded one, e.g. to ignore timestamps or random IDs. Defaults to comparing them byte for
+	// byte.
+	MatchBody func(recorded []byte, actual []byte) bool
+}
+
+// Recorder is an http.RoundTripper that records the interactions of a Client with Chatkit to a
+// cassette file, or replays them from it, so that tests exercise the payloads of Chatkit
+// without depending on an instance:
+//
+//	mode := chatkittest.ModeReplay
+//	locator, key := chatkittest.FakeInstanceLocator, chatkittest.FakeKey
+//	if os.Getenv("CHATKIT_RECORD") != "" {
+//		mode = chatkittest.ModeRecord
+//		locator, key = os.Getenv("CHATKIT_INSTANCE_LOCATOR"), os.Getenv("CHATKIT_INSTANCE_KEY")
+//	}
+//
+//	recorder, err := chatkittest.NewRecorder("testdata/rooms.json", chatkittest.RecorderOptions{
+//		Mode: mode,
+//	})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer recorder.Close()
+//
+//	client, err := chatkit.NewClient(locator, key, chatkit.WithHTTPClient(&http.Client{
+//		Transport: recorder,
+//	}))
+//
+// Tokens and cookies are scrubbed from cassettes, as are the host and instance ID of requests to
+// Chatkit, so that they are replayed whatever the instance. Requests are matched with the first
+// recorded interaction with the same method, URL and body, as compared by MatchBody, that hasn't
+// been replayed yet.
+type Recorder struct {
+	path    string
+	options RecorderOptions
+
+	mu           sync.Mutex
+	interactions []*interaction
+	replayed     []bool
+}
+
+// cassette is the format of the files interactions are recorded to.
+type cassette struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+
+	responseBody *bytes.Buffer // Body of the response as it is read, while recording
+}
+
+type recordedRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers http.Header `json:"headers,omitempty"`
+	recordedBody
+}
+
+type recordedResponse struct {
+	Status  int         `json:"status"`
+	Headers http.Header `json:"headers,omitempty"`
+	recordedBody
+}
+
+// recordedBody is a body recorded as text, or in base64 if it isn't valid UTF-8.
+type recordedBody struct {
+	Body       string `json:"body,omitempty"`
+	BodyBase64 string `json:"body_base64,omitempty"`
+}
+
+func newRecordedBody(body []byte) recordedBody {
+	if utf8.Valid(body) {
+		return recordedBody{Body: string(body)}
+	}
+
+	return recordedBody{BodyBase64: base64.StdEncoding.EncodeToString(body)}
+}
+
+func (b recordedBody) bytes() []byte {
+	if b.BodyBase64 != "" {
+		body, _ := base64.StdEncoding.DecodeString(b.BodyBase64)
+		return body
+	}
+
+	return []byte(b.Body)
+}
+
+// NewRecorder returns a Recorder of the cassette at path, which is read if replaying.
+func NewRecorder(path string, options RecorderOptions) (*Recorder, error) {
+	if options.Transport == nil {
+		options.Transport = http.DefaultTransport
+	}
+
+	if options.MatchBody == nil {
+		options.MatchBody = bytes.Equal
+	}
+
+	r := &Recorder{path: path, options: options}
+	if options.Mode == ModeRecord {
+		return r, nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read cassette: %v", err)
+	}
+
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("Failed to decode cassette %s: %v", path, err)
+	}
+
+	r.interactions = c.Interactions
+	r.replayed = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// RoundTrip records or replays a request.
+func (r *Recorder) RoundTrip(request *http.Request) (*http.Response, error) {
+	var body []byte
+	if request.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read request body: %v", err)
+		}
+	}
+
+	recorded := r.recordRequest(request, body)
+	if r.options.Mode == ModeRecord {
+		return r.record(request, body, recorded)
+	}
+
+	return r.replay(request, recorded)
+}
+
+func (r *Recorder) record(
+	request *http.Request,
+	body []byte,
+	recorded recordedRequest,
+) (*http.Response, error) {
+	outgoing := *request
+	outgoing.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	response, err := r.options.Transport.RoundTrip(&outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	i := &interaction{
+		Request: recorded,
+		Response: recordedResponse{
+			Status:  response.StatusCode,
+			Headers: r.scrubHeaders(response.Header),
+		},
+		responseBody: &bytes.Buffer{},
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, i)
+	r.mu.Unlock()
+
+	// The body is recorded as it is read, as subscriptions stream theirs.
+	response.Body = &recordingBody{ReadCloser: response.Body, recorder: r, buffer: i.responseBody}
+	return response, nil
+}
+
+func (r *Recorder) replay(request *http.Request, recorded recordedRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for n, i := range r.interactions {
+		if r.replayed[n] ||
+			i.Request.Method != recorded.Method ||
+			i.Request.URL != recorded.URL ||
+			!r.options.MatchBody(i.Request.bytes(), recorded.bytes()) {
+			continue
+		}
+
+		r.replayed[n] = true
+
+		body := i.Response.bytes()
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", i.Response.Status, http.StatusText(i.Response.Status)),
+			StatusCode:    i.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        i.Response.Headers,
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       request,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("No recorded interaction for %s %s in %s", recorded.Method, recorded.URL, r.path)
+}
+
+// Close writes the interactions recorded to the cassette, if recording.
+func (r *Recorder) Close() error {
+	if r.options.Mode != ModeRecord {
+		return nil
+	}
+
+	r.mu.Lock()
+	for _, i := range r.interactions {
+		i.Response.recordedBody = newRecordedBody([]byte(r.scrub(i.responseBody.String())))
+	}
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("Failed to encode cassette: %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("Failed to create cassette directory: %v", err)
+	}
+
+	if err := ioutil.WriteFile(r.path, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("Failed to write cassette: %v", err)
+	}
+
+	return nil
+}
+
+// recordRequest returns a request as it is recorded, scrubbed.
+func (r *Recorder) recordRequest(request *http.Request, body []byte) recordedRequest {
+	u := *request.URL
+	if query := u.Query(); len(query) > 0 {
+		for name := range query {
+			if scrubbedQueryParams[strings.ToLower(name)] {
+				query.Set(name, redacted)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+
+	// Requests to Chatkit are recorded without their host, and their paths, of the form
+	// /services/{service}/{version}/{instance_id}/..., without the instance ID.
+	target := u.String()
+	if segments := strings.SplitN(u.EscapedPath(), "/", 6); len(segments) >= 5 && segments[1] == "services" {
+		segments[4] = instanceIDRedaction
+		target = strings.Join(segments, "/")
+		if u.RawQuery != "" {
+			target += "?" + u.RawQuery
+		}
+	}
+
+	return recordedRequest{
+		Method:       request.Method,
+		URL:          r.scrub(target),
+		Headers:      r.scrubHeaders(request.Header),
+		recordedBody: newRecordedBody([]byte(r.scrub(string(body)))),
+	}
+}
+
+func (r *Recorder) scrubHeaders(headers http.Header) http.Header {
+	scrubbed := http.Header{}
+	for name, values := range headers {
+		for _, value := range values {
+			scrubbed.Add(name, r.scrub(value))
+		}
+	}
+
+	for _, name := range scrubbedHeaders {
+		scrubbed.Del(name)
+	}
+
+	if len(scrubbed) == 0 {
+		return nil
+	}
+
+	return scrubbed
+}
+
+func (r *Recorder) scrub(s string) string {
+	for _, secret := range r.options.Secrets {
+		if secret != "" {
+			s = strings.Replace(s, secret, redacted, -1)
+		}
+	}
+
+	return s
+}
+
+// recordingBody records a response body to a buffer as it is read.
+type recordingBody struct {
+	io.ReadCloser
+	recorder *Recorder
+	buffer   *bytes.Buffer
+}
+
+func (b *recordingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+
+	b.recorder.mu.Lock()
+	b.buffer.Write(p[:n])
+	b.recorder.mu.Unlock()
+
+	return n, err
+}
@@ -0,0 +1,137 @@
+package chatkittest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+// attachmentsHandler serves the endpoints sending a message with an attachment calls, with the
+// attachment uploaded to a host other than Chatkit's.
+func attachmentsHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/attachments"):
+		json.NewEncoder(w).Encode(map[string]string{
+			"upload_url":    "https://uploads.example.com/att-1",
+			"attachment_id": "att-1",
+		})
+	case r.URL.Path == "/att-1" && r.Method == http.MethodPut:
+		w.WriteHeader(http.StatusOK)
+	case strings.HasSuffix(r.URL.Path, "/messages"):
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]uint{"message_id": 1})
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// sendWithAttachment sends a message with an attachment and text including the current time,
+// through recorder.
+func sendWithAttachment(t *testing.T, recorder *Recorder) error {
+	client, err := chatkit.NewClient(FakeInstanceLocator, FakeKey, chatkit.WithHTTPClient(&http.Client{
+		Transport: recorder,
+	}))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	_, err = client.Messages().SendMultipartMessage(context.Background(), chatkit.SendMultipartMessageOptions{
+		RoomID:   "general",
+		SenderID: "alice",
+		Parts: []chatkit.NewPart{
+			chatkit.NewInlinePart{
+				Type:    "text/plain",
+				Content: "Call me on 555-0100, sent at " + time.Now().UTC().Format(time.RFC3339Nano),
+			},
+			chatkit.NewAttachmentPart{Type: "text/plain", File: strings.NewReader("notes")},
+		},
+	})
+	return err
+}
+
+var timestamp = regexp.MustCompile(`\d{4}-\d\d-\d\dT[\d:.]+Z`)
+
+// matchIgnoringTimestamps matches bodies that only differ in their timestamps.
+func matchIgnoringTimestamps(recorded []byte, actual []byte) bool {
+	return bytes.Equal(timestamp.ReplaceAll(recorded, nil), timestamp.ReplaceAll(actual, nil))
+}
+
+func TestRecorderRecordsAndReplaysAttachmentUploads(t *testing.T) {
+	dir, err := ioutil.TempDir("", "chatkittest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	cassettePath := filepath.Join(dir, "cassette.json")
+
+	server := httptest.NewServer(http.HandlerFunc(attachmentsHandler))
+	target, _ := url.Parse(server.URL)
+
+	recorder, err := NewRecorder(cassettePath, RecorderOptions{
+		Mode:      ModeRecord,
+		Transport: redirectTransport{target: target, transport: http.DefaultTransport},
+		Secrets:   []string{"555-0100"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sendWithAttachment(t, recorder); err != nil {
+		t.Fatalf("Failed to send while recording: %v", err)
+	}
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("Failed to write cassette: %v", err)
+	}
+	server.Close()
+
+	data, err := ioutil.ReadFile(cassettePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cassette := string(data)
+
+	for _, leaked := range []string{"555-0100", "Authorization", "fake.pusherplatform.io", "/instance/"} {
+		if strings.Contains(cassette, leaked) {
+			t.Errorf("Expected %q to be scrubbed from the cassette", leaked)
+		}
+	}
+	for _, recorded := range []string{redacted, instanceIDRedaction, "https://uploads.example.com/att-1"} {
+		if !strings.Contains(cassette, recorded) {
+			t.Errorf("Expected the cassette to contain %q", recorded)
+		}
+	}
+
+	// The server is closed, so the attachment can only be uploaded if its upload is replayed.
+	replayer, err := NewRecorder(cassettePath, RecorderOptions{
+		Secrets:   []string{"555-0100"},
+		MatchBody: matchIgnoringTimestamps,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sendWithAttachment(t, replayer); err != nil {
+		t.Fatalf("Failed to send while replaying: %v", err)
+	}
+
+	// Without ignoring timestamps, the message sent later doesn't match the one recorded.
+	strict, err := NewRecorder(cassettePath, RecorderOptions{Secrets: []string{"555-0100"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sendWithAttachment(t, strict); err == nil {
+		t.Fatal("Expected a message sent at another time not to match the recorded one")
+	}
+}
@@ -8,12 +8,18 @@ package chatkit
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/pusher/chatkit-server-go/internal/authenticator"
 	"github.com/pusher/chatkit-server-go/internal/authorizer"
+	"github.com/pusher/chatkit-server-go/internal/common"
 	"github.com/pusher/chatkit-server-go/internal/core"
 	"github.com/pusher/chatkit-server-go/internal/cursors"
+	"github.com/pusher/chatkit-server-go/internal/presence"
 
 	"github.com/pusher/pusher-platform-go/auth"
 	platformclient "github.com/pusher/pusher-platform-go/client"
@@ -25,13 +31,77 @@ import (
 type Client struct {
 	coreServiceV2        core.Service
 	coreServiceV6        core.Service
+	coreServiceV7        core.Service
 	authorizerService    authorizer.Service
 	cursorsService       cursors.Service
+	presenceService      presence.Service
 	authenticatorService authenticator.Service
+
+	batchConcurrency       int
+	lookupConcurrency      int
+	store                  Store
+	storeConfigured        bool
+	idempotencyStore       Store
+	cache                  *responseCache
+	refreshTokenLifetime   time.Duration
+	clockSkew              time.Duration
+	revocationStore        RevocationStore
+	partTypeWarningHandler func(PartTypeWarning)
+	httpDumper             *httpDumper
+	stats                  *clientStats
+	httpClient             *http.Client
 }
 
 // NewClient returns an instantiated instance that fulfils the Client interface.
-func NewClient(instanceLocator string, key string) (*Client, error) {
+// Optional behaviour can be configured by passing ClientOptions.
+func NewClient(instanceLocator string, key string, options ...ClientOption) (*Client, error) {
+	var clientOpts clientOptions
+	for _, option := range options {
+		option(&clientOpts)
+	}
+
+	storeConfigured := clientOpts.store != nil
+	if !storeConfigured {
+		clientOpts.store = NewMemoryStore()
+	}
+
+	// Without a configured store, idempotency keys are kept in memory, and bounded so that
+	// a key sent with every message doesn't grow the store for the day each is remembered.
+	idempotencyStore := clientOpts.store
+	if !storeConfigured {
+		idempotencyStore = newLRUStore(maxIdempotencyKeys)
+	}
+
+	var cache *responseCache
+	if clientOpts.responseCache != nil {
+		cache = newResponseCache(*clientOpts.responseCache)
+	}
+
+	if clientOpts.refreshTokenLifetime <= 0 {
+		clientOpts.refreshTokenLifetime = defaultRefreshTokenLifetime
+	}
+
+	if clientOpts.revocationStore == nil {
+		clientOpts.revocationStore = NewRevocationStore(clientOpts.store)
+	}
+
+	if clientOpts.userTokenCacheSize == 0 {
+		clientOpts.userTokenCacheSize = defaultUserTokenCacheSize
+	}
+
+	stats := newClientStats()
+
+	// newInstance returns a platform instance for a Chatkit service, which caches the tokens its
+	// requests are made with.
+	newInstance := func(options instance.Options) (instance.Instance, error) {
+		inst, err := instance.New(options)
+		if err != nil {
+			return nil, err
+		}
+
+		return common.NewTokenCachingInstance(inst, clientOpts.userTokenCacheSize, &stats.tokens), nil
+	}
+
 	locatorComponents, err := instance.ParseInstanceLocator(instanceLocator)
 	if err != nil {
 		return nil, err
@@ -42,11 +112,42 @@ func NewClient(instanceLocator string, key string) (*Client, error) {
 		return nil, err
 	}
 
-	baseClient := platformclient.New(platformclient.Options{
+	dumper := &httpDumper{w: clientOpts.debugHTTP}
+
+	var interceptors []common.Interceptor
+	if clientOpts.tracerProvider != nil {
+		interceptors = append(interceptors, traceRequest(clientOpts.tracerProvider.Tracer(tracerName)))
+	}
+	if clientOpts.onSlowRequest != nil {
+		interceptors = append(
+			interceptors,
+			reportSlowRequests(clientOpts.slowRequestThreshold, clientOpts.onSlowRequest),
+		)
+	}
+	if clientOpts.audit != nil {
+		interceptors = append(interceptors, auditSURequests(clientOpts.audit))
+	}
+	interceptors = append(interceptors, clientOpts.interceptors...)
+	if clientOpts.compression {
+		if clientOpts.compressionMinSize <= 0 {
+			clientOpts.compressionMinSize = defaultCompressionMinSize
+		}
+		interceptors = append(interceptors, compressRequests(clientOpts.compressionMinSize))
+	}
+	interceptors = append(interceptors, stats.intercept, dumper.intercept)
+
+	platformClient := platformclient.New(platformclient.Options{
 		Host: locatorComponents.Host(),
 	})
+	if clientOpts.httpClient != nil {
+		platformClient = common.NewHTTPClient(locatorComponents.Host(), clientOpts.httpClient)
+	} else {
+		clientOpts.httpClient = http.DefaultClient
+	}
 
-	coreInstanceV2, err := instance.New(instance.Options{
+	baseClient := common.NewInterceptingClient(platformClient, interceptors...)
+
+	coreInstanceV2, err := newInstance(instance.Options{
 		Locator:        instanceLocator,
 		Key:            key,
 		ServiceName:    "chatkit",
@@ -57,7 +158,7 @@ func NewClient(instanceLocator string, key string) (*Client, error) {
 		return nil, err
 	}
 
-	coreInstanceV6, err := instance.New(instance.Options{
+	coreInstanceV6, err := newInstance(instance.Options{
 		Locator:        instanceLocator,
 		Key:            key,
 		ServiceName:    "chatkit",
@@ -68,7 +169,18 @@ func NewClient(instanceLocator string, key string) (*Client, error) {
 		return nil, err
 	}
 
-	authorizerInstance, err := instance.New(instance.Options{
+	coreInstanceV7, err := newInstance(instance.Options{
+		Locator:        instanceLocator,
+		Key:            key,
+		ServiceName:    "chatkit",
+		ServiceVersion: "v7",
+		Client:         baseClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	authorizerInstance, err := newInstance(instance.Options{
 		Locator:        instanceLocator,
 		Key:            key,
 		ServiceName:    "chatkit_authorizer",
@@ -79,7 +191,7 @@ func NewClient(instanceLocator string, key string) (*Client, error) {
 		return nil, err
 	}
 
-	cursorsInstance, err := instance.New(instance.Options{
+	cursorsInstance, err := newInstance(instance.Options{
 		Locator:        instanceLocator,
 		Key:            key,
 		ServiceName:    "chatkit_cursors",
@@ -90,40 +202,138 @@ func NewClient(instanceLocator string, key string) (*Client, error) {
 		return nil, err
 	}
 
+	presenceInstance, err := newInstance(instance.Options{
+		Locator:        instanceLocator,
+		Key:            key,
+		ServiceName:    "chatkit_presence",
+		ServiceVersion: "v2",
+		Client:         baseClient,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	coreServiceV6 := core.NewService(coreInstanceV6, clientOpts.decoder, clientOpts.httpClient)
+	if clientOpts.coreRolloutVersion != "" {
+		candidateInstance, err := newInstance(instance.Options{
+			Locator:        instanceLocator,
+			Key:            key,
+			ServiceName:    "chatkit",
+			ServiceVersion: clientOpts.coreRolloutVersion,
+			Client:         baseClient,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		coreServiceV6 = core.NewRolloutService(
+			coreServiceV6,
+			core.NewService(candidateInstance, clientOpts.decoder, clientOpts.httpClient),
+			clientOpts.coreRollout,
+		)
+	}
+
 	return &Client{
-		coreServiceV2:     core.NewService(coreInstanceV2),
-		coreServiceV6:     core.NewService(coreInstanceV6),
-		authorizerService: authorizer.NewService(authorizerInstance),
-		cursorsService:    cursors.NewService(cursorsInstance),
+		coreServiceV2:     core.NewService(coreInstanceV2, clientOpts.decoder, clientOpts.httpClient),
+		coreServiceV6:     coreServiceV6,
+		coreServiceV7:     core.NewService(coreInstanceV7, clientOpts.decoder, clientOpts.httpClient),
+		authorizerService: authorizer.NewService(authorizerInstance, clientOpts.decoder),
+		cursorsService:    cursors.NewService(cursorsInstance, clientOpts.decoder),
+		presenceService:   presence.NewService(presenceInstance, clientOpts.decoder),
 		authenticatorService: authenticator.NewService(
 			locatorComponents.InstanceID,
 			keyComponents.Key,
 			keyComponents.Secret,
+			clientOpts.tokenOptions,
 		),
+		batchConcurrency:     clientOpts.batchConcurrency,
+		lookupConcurrency:    clientOpts.lookupConcurrency,
+		store:                clientOpts.store,
+		storeConfigured:      storeConfigured,
+		idempotencyStore:     idempotencyStore,
+		cache:                cache,
+		refreshTokenLifetime: clientOpts.refreshTokenLifetime,
+		clockSkew:            clientOpts.tokenOptions.ClockSkew,
+		revocationStore:      clientOpts.revocationStore,
+
+		partTypeWarningHandler: clientOpts.partTypeWarningHandler,
+		httpDumper:             dumper,
+		stats:                  stats,
+		httpClient:             clientOpts.httpClient,
 	}, nil
 }
 
 // GetUserReadCursors returns a list of cursors that have been set across different rooms
 // for the user.
-func (c *Client) GetUserReadCursors(ctx context.Context, userID string) ([]Cursor, error) {
-	return c.cursorsService.GetUserReadCursors(ctx, userID)
+func (cc CursorsClient) GetUserReadCursors(ctx context.Context, userID string) ([]Cursor, error) {
+	return cc.client.cursorsService.GetUserReadCursors(ctx, userID)
 }
 
 // SetReadCursor sets the cursor position for a room for a user.
 // The position points to the message ID of a message that was sent to that room.
-func (c *Client) SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error {
-	return c.cursorsService.SetReadCursor(ctx, userID, roomID, position)
+func (cc CursorsClient) SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error {
+	return cc.client.cursorsService.SetReadCursor(ctx, userID, roomID, position)
 }
 
 // GetReadCursorsForRoom returns a list of cursors that have been set for a room.
 // This returns cursors irrespective of the user that set them.
-func (c *Client) GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error) {
-	return c.cursorsService.GetReadCursorsForRoom(ctx, roomID)
+func (cc CursorsClient) GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error) {
+	return cc.client.cursorsService.GetReadCursorsForRoom(ctx, roomID)
+}
+
+// GetReadCursorsForRoomPage returns a page of the cursors that have been set for a room, ordered by
+// user ID. Use IterateRoomReadCursors to page through all of them.
+func (cc CursorsClient) GetReadCursorsForRoomPage(
+	ctx context.Context,
+	roomID string,
+	options GetReadCursorsForRoomOptions,
+) ([]Cursor, error) {
+	return cc.client.cursorsService.GetReadCursorsForRoomPage(ctx, roomID, options)
 }
 
 // GetReadCursor returns a single cursor that was set by a user in a room.
-func (c *Client) GetReadCursor(ctx context.Context, userID string, roomID string) (Cursor, error) {
-	return c.cursorsService.GetReadCursor(ctx, userID, roomID)
+func (cc CursorsClient) GetReadCursor(ctx context.Context, userID string, roomID string) (Cursor, error) {
+	return cc.client.cursorsService.GetReadCursor(ctx, userID, roomID)
+}
+
+// DeleteReadCursor deletes the read cursor of a user in a room, e.g. after they have been removed
+// from it. Where the cursors service doesn't support deleting cursors, the cursor is reset to
+// position 0 instead, which GetUnreadCounts treats as no cursor.
+func (cc CursorsClient) DeleteReadCursor(ctx context.Context, userID string, roomID string) error {
+	return cc.DeleteCursor(ctx, CursorTypeRead, userID, roomID)
+}
+
+// GetUserCursors returns the cursors of a type that a user has set across different rooms.
+// Use the *ReadCursor* methods for read cursors.
+func (cc CursorsClient) GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]Cursor, error) {
+	return cc.client.cursorsService.GetUserCursors(ctx, cursorType, userID)
+}
+
+// SetCursor sets the position of a cursor of a type for a room for a user.
+func (cc CursorsClient) SetCursor(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error {
+	return cc.client.cursorsService.SetCursor(ctx, cursorType, userID, roomID, position)
+}
+
+// GetCursorsForRoom returns the cursors of a type that have been set for a room.
+func (cc CursorsClient) GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]Cursor, error) {
+	return cc.client.cursorsService.GetCursorsForRoom(ctx, cursorType, roomID)
+}
+
+// GetCursor returns a single cursor of a type that was set by a user in a room.
+func (cc CursorsClient) GetCursor(ctx context.Context, cursorType uint, userID string, roomID string) (Cursor, error) {
+	return cc.client.cursorsService.GetCursor(ctx, cursorType, userID, roomID)
+}
+
+// DeleteCursor deletes a cursor of a type of a user in a room. Where the cursors service doesn't
+// support deleting cursors, the cursor is reset to position 0 instead.
+func (cc CursorsClient) DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error {
+	c := cc.client
+	err := c.cursorsService.DeleteCursor(ctx, cursorType, userID, roomID)
+	if hasStatus(err, http.StatusMethodNotAllowed) {
+		return c.cursorsService.SetCursor(ctx, cursorType, userID, roomID, 0)
+	}
+
+	return err
 }
 
 // CursorsRequest allows performing a request to the cursors service that returns a raw HTTP
@@ -135,97 +345,199 @@ func (c *Client) CursorsRequest(
 	return c.cursorsService.Request(ctx, options)
 }
 
+// CursorsSubscribe opens a subscription to the cursors service, as CoreSubscribe does for the core
+// service.
+func (c *Client) CursorsSubscribe(
+	ctx context.Context,
+	options platformclient.RequestOptions,
+) (*RawEventStream, error) {
+	stream, err := c.cursorsService.Subscribe(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRawEventStream(ctx, stream), nil
+}
+
+// GetUserPresence returns whether a user is currently online or offline.
+func (c *Client) GetUserPresence(ctx context.Context, userID string) (UserPresence, error) {
+	return c.presenceService.GetUserPresence(ctx, userID)
+}
+
+// GetUsersPresence returns whether each of the given users is currently online or offline.
+// The results are in the order the user IDs were given, without duplicates. The users are looked
+// up concurrently, with the concurrency of WithLookupConcurrency. If some of the lookups fail a
+// *BatchError is returned, keyed by user ID, along with the presence of the other users.
+func (c *Client) GetUsersPresence(ctx context.Context, userIDs []string) ([]UserPresence, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.New("You must provide the IDs of the users whose presence you want to fetch")
+	}
+
+	userIDs = uniqueStrings(userIDs)
+
+	var mu sync.Mutex
+	byID := make(map[string]UserPresence, len(userIDs))
+	err := forEachConcurrently(ctx, userIDs, c.concurrencyForLookups(), func(ctx context.Context, userID string) error {
+		presence, err := c.presenceService.GetUserPresence(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		byID[userID] = presence
+		mu.Unlock()
+		return nil
+	})
+
+	presences := make([]UserPresence, 0, len(byID))
+	for _, userID := range userIDs {
+		if presence, ok := byID[userID]; ok {
+			presences = append(presences, presence)
+		}
+	}
+
+	return presences, err
+}
+
+// PresenceRequest allows performing a request to the presence service that returns a raw HTTP
+// response.
+func (c *Client) PresenceRequest(
+	ctx context.Context,
+	options platformclient.RequestOptions,
+) (*http.Response, error) {
+	return c.presenceService.Request(ctx, options)
+}
+
+// PresenceSubscribe opens a subscription to the presence service, as CoreSubscribe does for the core
+// service.
+func (c *Client) PresenceSubscribe(
+	ctx context.Context,
+	options platformclient.RequestOptions,
+) (*RawEventStream, error) {
+	stream, err := c.presenceService.Subscribe(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRawEventStream(ctx, stream), nil
+}
+
 // GetRoles retrieves all roles associated with an instance.
-func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
-	return c.authorizerService.GetRoles(ctx)
+func (rc RolesClient) GetRoles(ctx context.Context) ([]Role, error) {
+	c := rc.client
+	generation := c.cache.generation(rolesCacheKey)
+	var roles []Role
+	if c.cache.get(ctx, rolesCacheKey, &roles) {
+		return roles, nil
+	}
+
+	roles, err := c.authorizerService.GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.put(ctx, rolesCacheKey, roles, generation)
+	return roles, nil
 }
 
 // CreateGlobalRole allows creating a globally scoped role.
-func (c *Client) CreateGlobalRole(ctx context.Context, options CreateRoleOptions) error {
+func (rc RolesClient) CreateGlobalRole(ctx context.Context, options CreateRoleOptions) error {
+	c := rc.client
+	defer c.cache.invalidate(ctx, rolesCacheKey)
 	return c.authorizerService.CreateGlobalRole(ctx, options)
 }
 
 // CreateRoomRole allows creating a room scoped role.
-func (c *Client) CreateRoomRole(ctx context.Context, options CreateRoleOptions) error {
+func (rc RolesClient) CreateRoomRole(ctx context.Context, options CreateRoleOptions) error {
+	c := rc.client
+	defer c.cache.invalidate(ctx, rolesCacheKey)
 	return c.authorizerService.CreateRoomRole(ctx, options)
 }
 
 // DeleteGlobalRole deletes a previously created globally scoped role.
-func (c *Client) DeleteGlobalRole(ctx context.Context, roleName string) error {
+func (rc RolesClient) DeleteGlobalRole(ctx context.Context, roleName string) error {
+	c := rc.client
+	defer c.cache.invalidate(ctx, rolesCacheKey)
 	return c.authorizerService.DeleteGlobalRole(ctx, roleName)
 }
 
 // DeleteRoomRole deletes a previously created room scoped role.
-func (c *Client) DeleteRoomRole(ctx context.Context, roleName string) error {
+func (rc RolesClient) DeleteRoomRole(ctx context.Context, roleName string) error {
+	c := rc.client
+	defer c.cache.invalidate(ctx, rolesCacheKey)
 	return c.authorizerService.DeleteRoomRole(ctx, roleName)
 }
 
 // GetPermissionsForGlobalRole returns permissions associated with a previously created global role.
-func (c *Client) GetPermissionsForGlobalRole(
+func (rc RolesClient) GetPermissionsForGlobalRole(
 	ctx context.Context,
 	roleName string,
 ) ([]string, error) {
-	return c.authorizerService.GetPermissionsForGlobalRole(ctx, roleName)
+	return rc.client.authorizerService.GetPermissionsForGlobalRole(ctx, roleName)
 }
 
 // GetPermissionsForRoomRole returns permissions associated with a previously created room role.
-func (c *Client) GetPermissionsForRoomRole(
+func (rc RolesClient) GetPermissionsForRoomRole(
 	ctx context.Context,
 	roleName string,
 ) ([]string, error) {
-	return c.authorizerService.GetPermissionsForRoomRole(ctx, roleName)
+	return rc.client.authorizerService.GetPermissionsForRoomRole(ctx, roleName)
 }
 
 // UpdatePermissionsForGlobalRole allows adding or removing permissions from a previously created
 // globally scoped role.
-func (c *Client) UpdatePermissionsForGlobalRole(
+func (rc RolesClient) UpdatePermissionsForGlobalRole(
 	ctx context.Context,
 	roleName string,
 	options UpdateRolePermissionsOptions,
 ) error {
+	c := rc.client
+	defer c.cache.invalidate(ctx, rolesCacheKey)
 	return c.authorizerService.UpdatePermissionsForGlobalRole(ctx, roleName, options)
 }
 
 // UpdatePermissionsForRoomROle allows adding or removing permissions from a previously created
 // room scoped role.
-func (c *Client) UpdatePermissionsForRoomRole(
+func (rc RolesClient) UpdatePermissionsForRoomRole(
 	ctx context.Context,
 	roleName string,
 	options UpdateRolePermissionsOptions,
 ) error {
+	c := rc.client
+	defer c.cache.invalidate(ctx, rolesCacheKey)
 	return c.authorizerService.UpdatePermissionsForRoomRole(ctx, roleName, options)
 }
 
 // GetUserRoles returns roles assosciated with a user.
-func (c *Client) GetUserRoles(ctx context.Context, userID string) ([]Role, error) {
-	return c.authorizerService.GetUserRoles(ctx, userID)
+func (rc RolesClient) GetUserRoles(ctx context.Context, userID string) ([]Role, error) {
+	return rc.client.authorizerService.GetUserRoles(ctx, userID)
 }
 
 // AssignGlobalRoleToUser assigns a previously created globally scoped role to a user.
-func (c *Client) AssignGlobalRoleToUser(ctx context.Context, userID string, roleName string) error {
-	return c.authorizerService.AssignGlobalRoleToUser(ctx, userID, roleName)
+func (rc RolesClient) AssignGlobalRoleToUser(ctx context.Context, userID string, roleName string) error {
+	return rc.client.authorizerService.AssignGlobalRoleToUser(ctx, userID, roleName)
 }
 
 // AssignRoomRoleToUser assigns a previously created room scoped role to a user.
-func (c *Client) AssignRoomRoleToUser(
+func (rc RolesClient) AssignRoomRoleToUser(
 	ctx context.Context,
 	userID string,
 	roomID string,
 	roleName string,
 ) error {
-	return c.authorizerService.AssignRoomRoleToUser(ctx, userID, roomID, roleName)
+	return rc.client.authorizerService.AssignRoomRoleToUser(ctx, userID, roomID, roleName)
 }
 
 // RemoveGlobalRoleForUser removes a previously assigned globally scoped role from a user.
 // Users can only have one globall scoped role associated at any point.
-func (c *Client) RemoveGlobalRoleForUser(ctx context.Context, userID string) error {
-	return c.authorizerService.RemoveGlobalRoleForUser(ctx, userID)
+func (rc RolesClient) RemoveGlobalRoleForUser(ctx context.Context, userID string) error {
+	return rc.client.authorizerService.RemoveGlobalRoleForUser(ctx, userID)
 }
 
 // RemoveRoomRoleForUser removes a previously assigned room scoped role from a user.
 // Users can have multiple room roles associated with them, but only one role per room.
-func (c *Client) RemoveRoomRoleForUser(ctx context.Context, userID string, roomID string) error {
-	return c.authorizerService.RemoveRoomRoleForUser(ctx, userID, roomID)
+func (rc RolesClient) RemoveRoomRoleForUser(ctx context.Context, userID string, roomID string) error {
+	return rc.client.authorizerService.RemoveRoomRoleForUser(ctx, userID, roomID)
 }
 
 // AuthorizerRequest allows performing requests to the authorizer service
@@ -237,156 +549,412 @@ func (c *Client) AuthorizerRequest(
 	return c.authorizerService.Request(ctx, options)
 }
 
+// AuthorizerSubscribe opens a subscription to the authorizer service, as CoreSubscribe does for the core
+// service.
+func (c *Client) AuthorizerSubscribe(
+	ctx context.Context,
+	options platformclient.RequestOptions,
+) (*RawEventStream, error) {
+	stream, err := c.authorizerService.Subscribe(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRawEventStream(ctx, stream), nil
+}
+
 // GetUser retrieves a previously created Chatkit user.
-func (c *Client) GetUser(ctx context.Context, userID string) (User, error) {
-	return c.coreServiceV6.GetUser(ctx, userID)
+func (u UsersClient) GetUser(ctx context.Context, userID string) (User, error) {
+	c := u.client
+	generation := c.cache.generation(userCacheKey(userID))
+	var user User
+	if c.cache.get(ctx, userCacheKey(userID), &user) {
+		return user, nil
+	}
+
+	user, err := c.coreServiceV6.GetUser(ctx, userID)
+	if err != nil {
+		return User{}, err
+	}
+
+	c.cache.put(ctx, userCacheKey(userID), user, generation)
+	return user, nil
 }
 
 // GetUsers retrieves a list of users based on the options provided.
-func (c *Client) GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error) {
-	return c.coreServiceV6.GetUsers(ctx, options)
+func (u UsersClient) GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error) {
+	return u.client.coreServiceV6.GetUsers(ctx, options)
 }
 
-// GetUsersByID retrieves a list of users for the given id's.
-func (c *Client) GetUsersByID(ctx context.Context, userIDs []string) ([]User, error) {
-	return c.coreServiceV6.GetUsersByID(ctx, userIDs)
+// CreateUser creates a new chatkit user.
+func (u UsersClient) CreateUser(ctx context.Context, options CreateUserOptions) error {
+	_, err := u.CreateUserAndGet(ctx, options)
+	return err
 }
 
-// CreateUser creates a new chatkit user.
-func (c *Client) CreateUser(ctx context.Context, options CreateUserOptions) error {
+// CreateUserAndGet creates a new chatkit user and returns it, including its server side CreatedAt
+// and UpdatedAt, saving a GetUser to read them.
+func (u UsersClient) CreateUserAndGet(ctx context.Context, options CreateUserOptions) (User, error) {
+	c := u.client
+	defer c.cache.invalidate(ctx, userCacheKey(options.ID))
 	return c.coreServiceV6.CreateUser(ctx, options)
 }
 
 // CreateUsers creates a batch of users.
-func (c *Client) CreateUsers(ctx context.Context, users []CreateUserOptions) error {
-	return c.coreServiceV6.CreateUsers(ctx, users)
+// Batches of more than 100 users are created in chunks, as by CreateUsersInBatches, and if some
+// of the chunks fail a *BatchError is returned, keyed by user ID.
+func (u UsersClient) CreateUsers(ctx context.Context, users []CreateUserOptions) error {
+	result := u.CreateUsersInBatches(ctx, users, BatchOptions{})
+	if len(users) <= maxUsersPerCreateRequest {
+		// A single request was made, so its error is returned as it is.
+		for _, err := range result.Failed {
+			return err
+		}
+	}
+
+	return result.Err()
+}
+
+// maxUsersPerCreateRequest is the maximum number of users created in a single request.
+const maxUsersPerCreateRequest = 100
+
+// CreateUsersInBatches creates many users, in chunks of at most 100 per request with up to
+// options.Concurrency requests in flight at once. The result reports the outcome for each user,
+// keyed by user ID. Creating users isn't idempotent, so chunks are never retried, whatever
+// options.MaxAttempts is.
+func (u UsersClient) CreateUsersInBatches(
+	ctx context.Context,
+	users []CreateUserOptions,
+	options BatchOptions,
+) *BatchResult {
+	c := u.client
+	if c.cache != nil {
+		defer func() {
+			for _, user := range users {
+				c.cache.invalidate(ctx, userCacheKey(user.ID))
+			}
+		}()
+	}
+
+	chunks := [][]CreateUserOptions{}
+	for start := 0; start < len(users); start += maxUsersPerCreateRequest {
+		end := start + maxUsersPerCreateRequest
+		if end > len(users) {
+			end = len(users)
+		}
+		chunks = append(chunks, users[start:end])
+	}
+
+	keys := make([]string, len(chunks))
+	for i := range chunks {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	options.MaxAttempts = 1
+	chunkResult := c.newBatchExecutor(options).Execute(ctx, keys, func(ctx context.Context, key string) error {
+		i, _ := strconv.Atoi(key)
+		return c.coreServiceV6.CreateUsers(ctx, chunks[i])
+	})
+
+	result := &BatchResult{Succeeded: []string{}, Failed: map[string]error{}}
+	for i, chunk := range chunks {
+		err, failed := chunkResult.Failed[keys[i]]
+		for _, user := range chunk {
+			if failed {
+				result.Failed[user.ID] = err
+			} else {
+				result.Succeeded = append(result.Succeeded, user.ID)
+			}
+		}
+	}
+
+	return result
 }
 
 // UpdateUser allows updating a previously created user.
-func (c *Client) UpdateUser(ctx context.Context, userID string, options UpdateUserOptions) error {
+func (u UsersClient) UpdateUser(ctx context.Context, userID string, options UpdateUserOptions) error {
+	c := u.client
+	defer c.cache.invalidate(ctx, userCacheKey(userID))
 	return c.coreServiceV6.UpdateUser(ctx, userID, options)
 }
 
 // DeleteUser deletes a previously created user.
-func (c *Client) DeleteUser(ctx context.Context, userID string) error {
+func (u UsersClient) DeleteUser(ctx context.Context, userID string) error {
+	c := u.client
+	defer c.cache.invalidate(ctx, userCacheKey(userID))
 	return c.coreServiceV6.DeleteUser(ctx, userID)
 }
 
 // GetRoom retrieves an existing room.
-func (c *Client) GetRoom(ctx context.Context, roomID string) (Room, error) {
-	return c.coreServiceV6.GetRoom(ctx, roomID)
+func (r RoomsClient) GetRoom(ctx context.Context, roomID string) (Room, error) {
+	c := r.client
+	generation := c.cache.generation(roomCacheKey(roomID))
+	var room Room
+	if c.cache.get(ctx, roomCacheKey(roomID), &room) {
+		return room, nil
+	}
+
+	room, err := c.coreServiceV6.GetRoom(ctx, roomID)
+	if err != nil {
+		return Room{}, err
+	}
+
+	c.cache.put(ctx, roomCacheKey(roomID), room, generation)
+	return room, nil
 }
 
 // GetRooms retrieves a list of rooms based on the options provided.
-func (c *Client) GetRooms(ctx context.Context, options GetRoomsOptions) ([]core.RoomWithoutMembers, error) {
-	return c.coreServiceV6.GetRooms(ctx, options)
+func (r RoomsClient) GetRooms(ctx context.Context, options GetRoomsOptions) ([]core.RoomWithoutMembers, error) {
+	return r.client.coreServiceV6.GetRooms(ctx, options)
 }
 
 // GetUserRooms retrieves a list of rooms the user is an existing member of.
-func (c *Client) GetUserRooms(ctx context.Context, userID string) ([]Room, error) {
-	return c.coreServiceV6.GetUserRooms(ctx, userID)
+func (r RoomsClient) GetUserRooms(ctx context.Context, userID string) ([]Room, error) {
+	return r.client.coreServiceV6.GetUserRooms(ctx, userID)
 }
 
 // GetUserJoinableRooms retrieves a list of rooms the use can join (not an existing member of)
 // Private rooms are not returned as part of the response.
-func (c *Client) GetUserJoinableRooms(ctx context.Context, userID string) ([]Room, error) {
-	return c.coreServiceV6.GetUserJoinableRooms(ctx, userID)
+func (r RoomsClient) GetUserJoinableRooms(ctx context.Context, userID string) ([]Room, error) {
+	return r.client.coreServiceV6.GetUserJoinableRooms(ctx, userID)
 }
 
 // CreateRoom creates a new room.
-func (c *Client) CreateRoom(ctx context.Context, options CreateRoomOptions) (Room, error) {
-	return c.coreServiceV6.CreateRoom(ctx, options)
+func (r RoomsClient) CreateRoom(ctx context.Context, options CreateRoomOptions) (Room, error) {
+	return r.client.coreServiceV6.CreateRoom(ctx, options)
 }
 
 // UpdateRoom allows updating an existing room.
-func (c *Client) UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) error {
+// If options.ExpectedUpdatedAt is set and the room has been updated since then, ErrConflict is
+// returned instead. Chatkit cannot check this atomically, so an update made in the short window
+// between the check and the update may still be overwritten. UpdatedAt has a resolution of a
+// second, so updates made within the same second as the read are not detected either.
+func (r RoomsClient) UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) error {
+	_, err := r.UpdateRoomAndGet(ctx, roomID, options)
+	return err
+}
+
+// UpdateRoomAndGet updates an existing room as UpdateRoom does, and returns the room as it is
+// after the update, including its new UpdatedAt, saving a GetRoom to read it.
+func (r RoomsClient) UpdateRoomAndGet(
+	ctx context.Context,
+	roomID string,
+	options UpdateRoomOptions,
+) (Room, error) {
+	c := r.client
+	// Invalidated even on conflict, so that callers retrying a read-modify-write of a cached room
+	// read it afresh.
+	defer c.cache.invalidate(ctx, roomCacheKey(roomID))
+
+	if options.ExpectedUpdatedAt != nil {
+		room, err := c.coreServiceV6.GetRoom(ctx, roomID)
+		if err != nil {
+			return Room{}, err
+		}
+
+		if !room.UpdatedAt.Equal(*options.ExpectedUpdatedAt) {
+			return Room{}, ErrConflict
+		}
+	}
+
 	return c.coreServiceV6.UpdateRoom(ctx, roomID, options)
 }
 
 // DeleteRoom deletes an existing room.
-func (c *Client) DeleteRoom(ctx context.Context, roomID string) error {
+func (r RoomsClient) DeleteRoom(ctx context.Context, roomID string) error {
+	c := r.client
+	defer c.cache.invalidate(ctx, roomCacheKey(roomID))
 	return c.coreServiceV6.DeleteRoom(ctx, roomID)
 }
 
+// AsyncDeleteRoom starts deleting a room in the background and returns the ID of the deletion
+// job. Unlike DeleteRoom it returns immediately, however many messages the room has.
+// The progress of the job can be checked with GetDeleteStatus or waited for with WaitForDelete.
+func (r RoomsClient) AsyncDeleteRoom(ctx context.Context, roomID string) (string, error) {
+	c := r.client
+	defer c.cache.invalidate(ctx, roomCacheKey(roomID))
+	return c.coreServiceV7.AsyncDeleteRoom(ctx, roomID)
+}
+
+// GetDeleteStatus returns the status of an asynchronous deletion job.
+func (r RoomsClient) GetDeleteStatus(ctx context.Context, jobID string) (DeleteStatus, error) {
+	return r.client.coreServiceV7.GetDeleteStatus(ctx, jobID)
+}
+
 // AddUsersToRoom adds new users to an existing room.
-func (c *Client) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
-	return c.coreServiceV6.AddUsersToRoom(ctx, roomID, userIDs)
+// Any number of users can be added; they are sent in chunks of at most 10 per request. If some
+// of the chunks fail a *BatchError is returned, reporting the error for each user that was not added.
+func (r RoomsClient) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
+	c := r.client
+	defer c.cache.invalidate(ctx, roomCacheKey(roomID))
+	return c.inMembershipChunks(ctx, userIDs, func(ctx context.Context, chunk []string) error {
+		return c.coreServiceV6.AddUsersToRoom(ctx, roomID, chunk)
+	})
 }
 
 // RemoveUsersFromRoom removes existing members from a room.
-func (c *Client) RemoveUsersFromRoom(ctx context.Context, roomID string, userIDs []string) error {
-	return c.coreServiceV6.RemoveUsersFromRoom(ctx, roomID, userIDs)
+// Any number of users can be removed; they are sent in chunks of at most 10 per request. If some
+// of the chunks fail a *BatchError is returned, reporting the error for each user that was not removed.
+func (r RoomsClient) RemoveUsersFromRoom(ctx context.Context, roomID string, userIDs []string) error {
+	c := r.client
+	defer c.cache.invalidate(ctx, roomCacheKey(roomID))
+	return c.inMembershipChunks(ctx, userIDs, func(ctx context.Context, chunk []string) error {
+		return c.coreServiceV6.RemoveUsersFromRoom(ctx, roomID, chunk)
+	})
 }
 
-// SendMessage publishes a new message to a room.
-func (c *Client) SendMessage(ctx context.Context, options SendMessageOptions) (uint, error) {
-	return c.coreServiceV2.SendMessage(ctx, options)
+// JoinRoom makes a user join a room, acting on their behalf rather than as a super user.
+// The user's permissions are enforced, so for example they cannot join a private room.
+func (r RoomsClient) JoinRoom(ctx context.Context, roomID string, userID string) (Room, error) {
+	c := r.client
+	defer c.cache.invalidate(ctx, roomCacheKey(roomID))
+	return c.coreServiceV6.JoinRoom(ctx, roomID, userID)
+}
+
+// LeaveRoom makes a user leave a room, acting on their behalf rather than as a super user.
+func (r RoomsClient) LeaveRoom(ctx context.Context, roomID string, userID string) error {
+	c := r.client
+	defer c.cache.invalidate(ctx, roomCacheKey(roomID))
+	return c.coreServiceV6.LeaveRoom(ctx, roomID, userID)
+}
+
+// SendMessage publishes a new message to a room. If the message was sent but its ExpiresAfter or
+// IdempotencyKey could not be recorded, its ID is returned with an *ExpiryError or
+// *IdempotencyKeyError.
+func (m MessagesClient) SendMessage(ctx context.Context, options SendMessageOptions) (uint, error) {
+	c := m.client
+	return c.sendMessage(ctx, options.RoomID, options.IdempotencyKey, options.ExpiresAfter, func() (uint, error) {
+		return c.coreServiceV2.SendMessage(ctx, options)
+	})
+}
+
+// SendMultipartMessage publishes a new multipart message to a room. If the message was sent but
+// its ExpiresAfter or IdempotencyKey could not be recorded, its ID is returned with an
+// *ExpiryError or *IdempotencyKeyError.
+func (m MessagesClient) SendMultipartMessage(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (uint, error) {
+	c := m.client
+	if err := c.validateParts(options.Parts); err != nil {
+		return 0, err
+	}
+
+	return c.sendMessage(ctx, options.RoomID, options.IdempotencyKey, options.ExpiresAfter, func() (uint, error) {
+		return c.coreServiceV6.SendMultipartMessage(ctx, options)
+	})
 }
 
-// SendMultipartMessage publishes a new multipart message to a room.
-func (c *Client) SendMultipartMessage(
+// SendMessageAsService publishes a new multipart message to a room on behalf of its sender using
+// a super user token, for messages sent by the service itself such as announcements. If the
+// message was sent but its ExpiresAfter or IdempotencyKey could not be recorded, its ID is
+// returned with an *ExpiryError or *IdempotencyKeyError.
+func (m MessagesClient) SendMessageAsService(
 	ctx context.Context,
 	options SendMultipartMessageOptions,
 ) (uint, error) {
-	return c.coreServiceV6.SendMultipartMessage(ctx, options)
+	c := m.client
+	if err := c.validateParts(options.Parts); err != nil {
+		return 0, err
+	}
+
+	return c.sendMessage(ctx, options.RoomID, options.IdempotencyKey, options.ExpiresAfter, func() (uint, error) {
+		return c.coreServiceV6.SendMultipartMessageAsService(ctx, options)
+	})
 }
 
-// SendSimpleMessage publishes a new simple multipart message to a room.
-func (c *Client) SendSimpleMessage(
+// SendSimpleMessage publishes a new simple multipart message to a room. If the message was sent
+// but its ExpiresAfter or IdempotencyKey could not be recorded, its ID is returned with an
+// *ExpiryError or *IdempotencyKeyError.
+func (m MessagesClient) SendSimpleMessage(
 	ctx context.Context,
 	options SendSimpleMessageOptions,
 ) (uint, error) {
-	return c.coreServiceV6.SendSimpleMessage(ctx, options)
+	c := m.client
+	return c.sendMessage(ctx, options.RoomID, options.IdempotencyKey, options.ExpiresAfter, func() (uint, error) {
+		return c.coreServiceV6.SendSimpleMessage(ctx, options)
+	})
 }
 
 // GetRoomMessages retrieves messages previously sent to a room based on the options provided.
-func (c *Client) GetRoomMessages(
+func (m MessagesClient) GetRoomMessages(
 	ctx context.Context,
 	roomID string,
 	options GetRoomMessagesOptions,
 ) ([]Message, error) {
-	return c.coreServiceV2.GetRoomMessages(ctx, roomID, options)
+	return m.client.coreServiceV2.GetRoomMessages(ctx, roomID, options)
 }
 
 // FetchMultipartMessage retrieves a single message previously sent to a room based on the options provided.
-func (c *Client) FetchMultipartMessage(
+// With IncludeReactions, the message includes the reactions to it, see AddReaction.
+func (m MessagesClient) FetchMultipartMessage(
 	ctx context.Context,
 	options FetchMultipartMessageOptions,
 ) (MultipartMessage, error) {
-	return c.coreServiceV6.FetchMultipartMessage(ctx, options)
+	message, err := m.client.coreServiceV6.FetchMultipartMessage(ctx, options)
+	if err != nil || !options.IncludeReactions {
+		return message, err
+	}
+
+	messages := []MultipartMessage{message}
+	if err := m.client.addReactions(ctx, options.RoomID, messages); err != nil {
+		return MultipartMessage{}, err
+	}
+
+	return messages[0], nil
 }
 
 // FetchMultipartMessages retrieves messages previously sent to a room based on
-// the options provided.
-func (c *Client) FetchMultipartMessages(
+// the options provided. With IncludeReactions, the messages include the reactions to them, see
+// AddReaction.
+func (m MessagesClient) FetchMultipartMessages(
 	ctx context.Context,
 	roomID string,
 	options GetRoomMessagesOptions,
 ) ([]MultipartMessage, error) {
-	return c.coreServiceV6.FetchMultipartMessages(ctx, roomID, options)
+	messages, err := m.client.coreServiceV6.FetchMultipartMessages(ctx, roomID, options)
+	if err != nil || !options.IncludeReactions {
+		return messages, err
+	}
+
+	if err := m.client.addReactions(ctx, roomID, messages); err != nil {
+		return nil, err
+	}
+
+	return messages, nil
 }
 
 // DeleteMessage allows a previously sent message to be deleted.
-func (c *Client) DeleteMessage(ctx context.Context, options DeleteMessageOptions) error {
-	return c.coreServiceV6.DeleteMessage(ctx, options)
+// The reactions to it are deleted as well.
+func (m MessagesClient) DeleteMessage(ctx context.Context, options DeleteMessageOptions) error {
+	if err := m.client.coreServiceV6.DeleteMessage(ctx, options); err != nil {
+		return err
+	}
+
+	return m.client.deleteReactions(ctx, options.RoomID, options.MessageID)
 }
 
 // EditMessage identifies an existing message by both its room and message id
 // in order to replace it's content and sender id with updated values.
-func (c *Client) EditMessage(ctx context.Context, roomID string, messageID uint, options EditMessageOptions) error {
-	return c.coreServiceV2.EditMessage(ctx, roomID, messageID, options)
+func (m MessagesClient) EditMessage(ctx context.Context, roomID string, messageID uint, options EditMessageOptions) error {
+	return m.client.coreServiceV2.EditMessage(ctx, roomID, messageID, options)
 }
 
 // EditMultipartMessage identifies an existing message by both its room and message id
 // in order to replace it's content and sender id with updated values.
-func (c *Client) EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options EditMultipartMessageOptions) error {
+func (m MessagesClient) EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options EditMultipartMessageOptions) error {
+	c := m.client
+	if err := c.validateParts(options.Parts); err != nil {
+		return err
+	}
+
 	return c.coreServiceV6.EditMultipartMessage(ctx, roomID, messageID, options)
 }
 
 // EditSimpleMessage identifies an existing message by both its room and message id
 // in order to replace it's content and sender id with updated values.
-func (c *Client) EditSimpleMessage(ctx context.Context, roomID string, messageID uint, options EditSimpleMessageOptions) error {
-	return c.coreServiceV6.EditSimpleMessage(ctx, roomID, messageID, options)
+func (m MessagesClient) EditSimpleMessage(ctx context.Context, roomID string, messageID uint, options EditSimpleMessageOptions) error {
+	return m.client.coreServiceV6.EditSimpleMessage(ctx, roomID, messageID, options)
 }
 
 // CoreRequest allows making requests to the core chatkit service and returns a raw HTTP response.
@@ -397,19 +965,60 @@ func (c *Client) CoreRequest(
 	return c.coreServiceV6.Request(ctx, options)
 }
 
+// CoreSubscribe opens a subscription to the core service, e.g. for subscription endpoints the SDK
+// doesn't wrap, and returns its raw event stream. The method of options is ignored, and a token
+// with the `su` claim is used unless options has one.
+func (c *Client) CoreSubscribe(
+	ctx context.Context,
+	options platformclient.RequestOptions,
+) (*RawEventStream, error) {
+	stream, err := c.coreServiceV6.Subscribe(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+
+	return newRawEventStream(ctx, stream), nil
+}
+
 // Authenticate returns a token response along with headers and status code to be used within
 // the context of a token provider.
 // Currently, the only supported GrantType is GrantTypeClientCredentials.
-func (c *Client) Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error) {
-	return c.authenticatorService.Authenticate(payload, options)
+// Any ServiceClaims in options, e.g. a tenant ID or feature flags, are added to the token as
+// additional claims, which VerifyToken returns. They must not replace the claims set by Chatkit,
+// such as `sub` or `exp`.
+func (a AuthClient) Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error) {
+	return a.client.authenticatorService.Authenticate(payload, options)
 }
 
 // GenerateAccessToken generates a JWT token based on the options provided.
-func (c *Client) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
-	return c.authenticatorService.GenerateAccessToken(options)
+// ServiceClaims are added to the token as for Authenticate.
+func (a AuthClient) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	return a.client.authenticatorService.GenerateAccessToken(options)
 }
 
 // GenerateSuToken generates a JWT token with the `su` claim.
-func (c *Client) GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error) {
-	return c.authenticatorService.GenerateSUToken(options)
+func (a AuthClient) GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	return a.client.authenticatorService.GenerateSUToken(options)
+}
+
+// VerifyToken verifies a token generated for this instance, e.g. one presented back to your own
+// API by a client, and returns its claims. It returns ErrInvalidToken if the token was not signed
+// with the instance's key or is malformed, ErrTokenExpired if it has expired, and ErrTokenRevoked
+// if it has been revoked.
+func (a AuthClient) VerifyToken(ctx context.Context, tokenString string) (Claims, error) {
+	c := a.client
+	claims, err := c.authenticatorService.VerifyToken(tokenString)
+	if err != nil {
+		return Claims{}, err
+	}
+
+	revoked, err := c.revocationStore.IsRevoked(ctx, claims)
+	if err != nil {
+		return Claims{}, err
+	}
+	if revoked {
+		return Claims{}, ErrTokenRevoked
+	}
+
+	return claims, nil
 }
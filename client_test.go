@@ -1,20 +1,31 @@
 package chatkit
 
 import (
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"math/rand"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/pusher/chatkit-server-go/internal/common"
 	"github.com/pusher/pusher-platform-go/auth"
 	platformclient "github.com/pusher/pusher-platform-go/client"
 	. "github.com/smartystreets/goconvey/convey"
@@ -224,6 +235,58 @@ func TestCursors(t *testing.T) {
 				So(userCursors[0].Position, ShouldEqual, messageID)
 			})
 
+			Convey("it should be possible to see who has read the message", func() {
+				readBy, err := client.GetMessageReadBy(context.Background(), room.ID, messageID)
+				So(err, ShouldBeNil)
+				So(readBy.ReadBy, ShouldResemble, []string{userID})
+				So(readBy.UnreadBy, ShouldResemble, []string{})
+
+				latestMessageID, err := client.SendMessage(context.Background(), SendMessageOptions{
+					RoomID:   room.ID,
+					Text:     "Hello again!",
+					SenderID: userID,
+				})
+				So(err, ShouldBeNil)
+
+				readBy, err = client.GetMessageReadBy(context.Background(), room.ID, latestMessageID)
+				So(err, ShouldBeNil)
+				So(readBy.ReadBy, ShouldResemble, []string{})
+				So(readBy.UnreadBy, ShouldResemble, []string{userID})
+			})
+
+			Convey("it should be possible to iterate over cursors for a room", func() {
+				it := client.IterateRoomReadCursors(context.Background(), room.ID, IterateRoomReadCursorsOptions{
+					PageSize: 1,
+				})
+
+				So(it.Next(), ShouldBeTrue)
+				So(it.Cursor().UserID, ShouldEqual, userID)
+				So(it.Cursor().Position, ShouldEqual, messageID)
+				So(it.Next(), ShouldBeFalse)
+				So(it.Err(), ShouldBeNil)
+			})
+
+			Convey("it should be possible to get back the cursor by type", func() {
+				cursor, err := client.GetCursor(context.Background(), CursorTypeRead, userID, room.ID)
+				So(err, ShouldBeNil)
+				So(cursor.Position, ShouldEqual, messageID)
+
+				roomCursors, err := client.GetCursorsForRoom(context.Background(), CursorTypeRead, room.ID)
+				So(err, ShouldBeNil)
+				So(len(roomCursors), ShouldEqual, 1)
+			})
+
+			Convey("it should be possible to delete the cursor", func() {
+				err := client.DeleteReadCursor(context.Background(), userID, room.ID)
+				So(err, ShouldBeNil)
+
+				userCursors, err := client.GetUserReadCursors(context.Background(), userID)
+				So(err, ShouldBeNil)
+				for _, cursor := range userCursors {
+					So(cursor.Position, ShouldEqual, 0)
+				}
+			})
+
 			Convey("it should be possible to get back cursors for a room", func() {
 				roomCursors, err := client.GetReadCursorsForRoom(context.Background(), room.ID)
 				So(err, ShouldBeNil)
@@ -235,6 +298,38 @@ func TestCursors(t *testing.T) {
 			})
 		})
 
+		Convey("it should be possible to count their unread messages", func() {
+			counts, err := client.GetUnreadCounts(context.Background(), userID)
+			So(err, ShouldBeNil)
+			So(counts[room.ID].Count, ShouldEqual, 1)
+			So(counts[room.ID].LatestMessageID, ShouldEqual, messageID)
+
+			latestMessageID, err := client.SendMessage(context.Background(), SendMessageOptions{
+				RoomID:   room.ID,
+				Text:     "Hello again!",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			err = client.SetReadCursor(context.Background(), userID, room.ID, messageID)
+			So(err, ShouldBeNil)
+
+			counts, err = client.GetUnreadCounts(context.Background(), userID)
+			So(err, ShouldBeNil)
+			So(counts[room.ID], ShouldResemble, UnreadCount{
+				Count:           1,
+				LatestMessageID: latestMessageID,
+				CursorPosition:  messageID,
+			})
+
+			err = client.SetReadCursor(context.Background(), userID, room.ID, latestMessageID)
+			So(err, ShouldBeNil)
+
+			counts, err = client.GetUnreadCounts(context.Background(), userID)
+			So(err, ShouldBeNil)
+			So(counts[room.ID].Count, ShouldEqual, 0)
+		})
+
 		Convey("On sending a new message and setting the read cursor", func() {
 			latestMessageID, err := client.SendMessage(context.Background(), SendMessageOptions{
 				RoomID:   room.ID,
@@ -323,6 +418,141 @@ func TestAuthorizer(t *testing.T) {
 			})
 		})
 
+		Convey("it should not be possible to create a role with an unknown permission", func() {
+			err := client.CreateGlobalRole(context.Background(), CreateRoleOptions{
+				Name:        randomString(),
+				Permissions: []string{PermissionMessageCreate, "mesage:create"},
+			})
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldContainSubstring, "mesage:create")
+		})
+
+		Convey("it should be possible to create the default roles", func() {
+			So(client.CreateDefaultRoles(context.Background()), ShouldBeNil)
+			// Existing roles are skipped.
+			So(client.CreateDefaultRoles(context.Background()), ShouldBeNil)
+
+			roles, err := client.GetRoles(context.Background())
+			So(err, ShouldBeNil)
+			So(roles, ShouldHaveLength, 2+len(DefaultRoles))
+		})
+
+		Convey("it should be possible to get one of them by name and scope", func() {
+			role, err := client.GetRole(context.Background(), roomRoleName, RoleScopeRoom)
+			So(err, ShouldBeNil)
+			So(role, ShouldResemble, Role{
+				Name:        roomRoleName,
+				Permissions: roomPermissions,
+				Scope:       RoleScopeRoom,
+			})
+
+			_, err = client.GetRole(context.Background(), roomRoleName, RoleScopeGlobal)
+			So(err, ShouldEqual, ErrRoleNotFound)
+		})
+
+		Convey("it should be possible to upsert roles", func() {
+			err := client.UpsertGlobalRole(context.Background(), CreateRoleOptions{
+				Name:        globalRoleName,
+				Permissions: []string{PermissionMessageCreate, PermissionCursorsReadGet},
+			})
+			So(err, ShouldBeNil)
+
+			permissions, err := client.GetPermissionsForGlobalRole(context.Background(), globalRoleName)
+			So(err, ShouldBeNil)
+			So(permissions, shouldResembleUpToReordering, []string{PermissionMessageCreate, PermissionCursorsReadGet})
+
+			newRoleName := randomString()
+			err = client.UpsertRoomRole(context.Background(), CreateRoleOptions{
+				Name:        newRoleName,
+				Permissions: []string{PermissionMessageCreate},
+			})
+			So(err, ShouldBeNil)
+
+			permissions, err = client.GetPermissionsForRoomRole(context.Background(), newRoleName)
+			So(err, ShouldBeNil)
+			So(permissions, ShouldResemble, []string{PermissionMessageCreate})
+		})
+
+		Convey("it should be possible to apply a roles config", func() {
+			config, err := LoadRolesConfig(strings.NewReader(fmt.Sprintf(`{
+				"roles": [
+					{"name": %q, "scope": "global", "permissions": ["message:create"]},
+					{"name": "new", "scope": "room", "permissions": ["room:get"]}
+				]
+			}`, globalRoleName)))
+			So(err, ShouldBeNil)
+
+			changes, err := client.ApplyRolesConfig(context.Background(), config, ApplyRolesConfigOptions{
+				DryRun:             true,
+				DeleteUnconfigured: true,
+			})
+			So(err, ShouldBeNil)
+			So(changes, ShouldResemble, []RoleChange{
+				{
+					Action: RoleChangeCreate,
+					Role:   Role{Name: "new", Scope: RoleScopeRoom, Permissions: []string{PermissionRoomGet}},
+				},
+				{
+					Action:              RoleChangeUpdate,
+					Role:                Role{Name: globalRoleName, Scope: RoleScopeGlobal, Permissions: []string{PermissionMessageCreate}},
+					PermissionsToAdd:    []string{},
+					PermissionsToRemove: []string{PermissionRoomCreate},
+				},
+				{
+					Action: RoleChangeDelete,
+					Role:   Role{Name: roomRoleName, Scope: RoleScopeRoom, Permissions: roomPermissions},
+				},
+			})
+
+			roles, err := client.GetRoles(context.Background())
+			So(err, ShouldBeNil)
+			So(roles, ShouldHaveLength, 2)
+
+			_, err = client.ApplyRolesConfig(context.Background(), config, ApplyRolesConfigOptions{
+				DeleteUnconfigured: true,
+			})
+			So(err, ShouldBeNil)
+
+			changes, err = client.ApplyRolesConfig(context.Background(), config, ApplyRolesConfigOptions{
+				DryRun:             true,
+				DeleteUnconfigured: true,
+			})
+			So(err, ShouldBeNil)
+			So(changes, ShouldBeEmpty)
+		})
+
+		Convey("it should be possible to export and import roles", func() {
+			config, err := client.ExportRoles(context.Background())
+			So(err, ShouldBeNil)
+			So(config.Roles, ShouldHaveLength, 2)
+			So(config.Roles[0].Name, ShouldEqual, globalRoleName)
+			So(config.Roles[1].Name, ShouldEqual, roomRoleName)
+
+			config.Roles[0].Permissions = []string{PermissionMessageCreate, PermissionRoomGet}
+			config.Roles = append(config.Roles, Role{
+				Name:        "new",
+				Scope:       RoleScopeGlobal,
+				Permissions: []string{PermissionRoomGet},
+			})
+
+			changes, err := client.ImportRoles(context.Background(), config, ImportRolesOptions{
+				Conflict: ImportConflictMerge,
+			})
+			So(err, ShouldBeNil)
+			So(changes, ShouldHaveLength, 2)
+
+			permissions, err := client.GetPermissionsForGlobalRole(context.Background(), globalRoleName)
+			So(err, ShouldBeNil)
+			So(permissions, shouldResembleUpToReordering, []string{
+				PermissionMessageCreate,
+				PermissionRoomCreate,
+				PermissionRoomGet,
+			})
+
+			_, err = client.GetRole(context.Background(), "new", RoleScopeGlobal)
+			So(err, ShouldBeNil)
+		})
+
 		Convey("it should be possible to delete a global scoped role", func() {
 			err := client.DeleteGlobalRole(context.Background(), globalRoleName)
 			So(err, ShouldBeNil)
@@ -431,6 +661,31 @@ func TestAuthorizer(t *testing.T) {
 				})
 			})
 
+			Convey("it should be possible to assign a room scoped role in a room with a custom ID", func() {
+				roomID := "general-" + randomString()
+				_, err := client.CreateRoom(context.Background(), CreateRoomOptions{
+					ID:        &roomID,
+					Name:      randomString(),
+					CreatorID: userID,
+				})
+				So(err, ShouldBeNil)
+
+				err = client.AssignRoomRoleToUser(context.Background(), userID, roomID, roomRoleName)
+				So(err, ShouldBeNil)
+
+				roles, err := client.GetUserRoles(context.Background(), userID)
+				So(err, ShouldBeNil)
+				So(roles, ShouldContain, Role{
+					Name:        roomRoleName,
+					Permissions: roomPermissions,
+					Scope:       "room",
+					RoomID:      roomID,
+				})
+
+				err = client.RemoveRoomRoleForUser(context.Background(), userID, roomID)
+				So(err, ShouldBeNil)
+			})
+
 			Convey("it should be possible to assign a room scoped role to a user", func() {
 				err := client.AssignRoomRoleToUser(context.Background(), userID, room.ID, roomRoleName)
 				So(err, ShouldBeNil)
@@ -442,7 +697,55 @@ func TestAuthorizer(t *testing.T) {
 						Name:        roomRoleName,
 						Permissions: roomPermissions,
 						Scope:       "room",
+						RoomID:      room.ID,
+					})
+				})
+
+				Convey("and to get their effective permissions in the room", func() {
+					err := client.AssignGlobalRoleToUser(context.Background(), userID, globalRoleName)
+					So(err, ShouldBeNil)
+
+					permissions, err := client.GetEffectivePermissions(context.Background(), userID, room.ID)
+					So(err, ShouldBeNil)
+					So(permissions, ShouldResemble, []string{PermissionMessageCreate, PermissionRoomCreate})
+
+					permissions, err = client.GetEffectivePermissions(context.Background(), userID, "")
+					So(err, ShouldBeNil)
+					So(permissions, ShouldResemble, []string{PermissionMessageCreate, PermissionRoomCreate})
+				})
+
+				Convey("and to list the users holding that role", func() {
+					assignments, err := client.GetUsersWithRole(
+						context.Background(),
+						roomRoleName,
+						RoleScopeRoom,
+						GetUsersWithRoleOptions{},
+					)
+					So(err, ShouldBeNil)
+					So(assignments, ShouldResemble, []RoleAssignment{{
+						UserID:   userID,
+						RoleName: roomRoleName,
+						Scope:    RoleScopeRoom,
+						RoomID:   room.ID,
+					}})
+				})
+
+				Convey("and list it among the instance's role assignments", func() {
+					it := client.ListRoleAssignments(context.Background(), ListRoleAssignmentsOptions{
+						RoomID: room.ID,
 					})
+
+					assignments := []RoleAssignment{}
+					for it.Next() {
+						assignments = append(assignments, it.Assignment())
+					}
+					So(it.Err(), ShouldBeNil)
+					So(assignments, ShouldResemble, []RoleAssignment{{
+						UserID:   userID,
+						RoleName: roomRoleName,
+						Scope:    RoleScopeRoom,
+						RoomID:   room.ID,
+					}})
 				})
 
 				Convey("and remove it again", func() {
@@ -476,13 +779,18 @@ func TestUsers(t *testing.T) {
 	Convey("We can create a user", t, func() {
 		userID := randomString()
 		avatarURL := "https://" + randomString()
-		err := client.CreateUser(ctx, CreateUserOptions{
+		createdUser, err := client.CreateUserAndGet(ctx, CreateUserOptions{
 			ID:         userID,
 			Name:       "integration-test-user",
 			AvatarURL:  &avatarURL,
 			CustomData: json.RawMessage([]byte(`{"foo":"hello","bar":42}`)),
 		})
 		So(err, ShouldBeNil)
+		So(createdUser.ID, ShouldEqual, userID)
+		So(createdUser.Name, ShouldEqual, "integration-test-user")
+		So(createdUser.AvatarURL, ShouldEqual, avatarURL)
+		So(createdUser.CreatedAt, ShouldNotEqual, time.Time{})
+		So(createdUser.UpdatedAt, ShouldNotEqual, time.Time{})
 
 		Convey("and we can can get them", func() {
 			user, err := client.GetUser(ctx, userID)
@@ -495,6 +803,31 @@ func TestUsers(t *testing.T) {
 			So(user.CustomData["bar"], ShouldEqual, 42)
 		})
 
+		Convey("and we can get their presence", func() {
+			presence, err := client.GetUserPresence(ctx, userID)
+			So(err, ShouldBeNil)
+			So(presence, ShouldResemble, UserPresence{UserID: userID, State: PresenceStateOffline})
+		})
+
+		Convey("and we can subscribe to their presence", func() {
+			subscriptionCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			presences, err := client.SubscribeToPresence(subscriptionCtx, []string{userID}, SubscriptionOptions{})
+			So(err, ShouldBeNil)
+
+			select {
+			case presence := <-presences:
+				So(presence, ShouldResemble, UserPresence{UserID: userID, State: PresenceStateOffline})
+			case <-time.After(10 * time.Second):
+				t.Fatal("Timed out waiting for their presence")
+			}
+
+			cancel()
+			for range presences {
+			}
+		})
+
 		Convey("and we can update them", func() {
 			newName := randomString()
 			newAvatarURL := "https://" + randomString()
@@ -605,6 +938,56 @@ func TestUsers(t *testing.T) {
 			So(users[3].CustomData["d"], ShouldEqual, "ddd")
 		})
 
+		Convey("and update them in bulk", func() {
+			updates := map[string]UpdateUserOptions{}
+			for _, id := range ids {
+				name := "renamed-" + id
+				updates[id] = UpdateUserOptions{Name: &name}
+			}
+
+			result := client.UpdateUsers(ctx, updates, UpdateUsersOptions{Concurrency: 2})
+			So(result.Err(), ShouldBeNil)
+			So(result.Succeeded, ShouldResemble, ids)
+
+			users, err := client.GetUsersByID(ctx, ids)
+			So(err, ShouldBeNil)
+			So(len(users), ShouldEqual, 4)
+			for _, user := range users {
+				So(user.Name, ShouldEqual, "renamed-"+user.ID)
+			}
+		})
+
+		Convey("and export them as CSV", func() {
+			var buf bytes.Buffer
+			err := client.ExportUsers(ctx, &buf, ExportFormatCSV)
+			So(err, ShouldBeNil)
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			So(len(lines), ShouldEqual, 5)
+			So(lines[0], ShouldEqual, "id,name,avatar_url,custom_data,created_at,updated_at")
+		})
+
+		Convey("and iterate over them a page at a time", func() {
+			it := client.IterateUsers(ctx, IterateUsersOptions{PageSize: 3})
+
+			iteratedIDs := []string{}
+			for it.Next() {
+				iteratedIDs = append(iteratedIDs, it.User().ID)
+			}
+			So(it.Err(), ShouldBeNil)
+			So(iteratedIDs, shouldResembleUpToReordering, ids)
+		})
+
+		Convey("and search for them by name prefix", func() {
+			it := client.SearchUsers(ctx, "ca", SearchUsersOptions{})
+
+			So(it.Next(), ShouldBeTrue)
+			So(it.User().ID, ShouldEqual, ids[2])
+			So(it.User().Name, ShouldEqual, "Carol")
+			So(it.Next(), ShouldBeFalse)
+			So(it.Err(), ShouldBeNil)
+		})
+
 		Convey("and get them all (paginated)", func() {
 			users, err := client.GetUsers(ctx, nil)
 			So(err, ShouldBeNil)
@@ -701,12 +1084,16 @@ func TestRooms(t *testing.T) {
 				newRoomName := randomString()
 				newRoomPNTitleOverride := randomString()
 
-				err := client.UpdateRoom(ctx, room.ID, UpdateRoomOptions{
+				updatedRoom, err := client.UpdateRoomAndGet(ctx, room.ID, UpdateRoomOptions{
 					Name:                          &newRoomName,
 					PushNotificationTitleOverride: &newRoomPNTitleOverride,
 					CustomData:                    map[string]interface{}{"foo": "baz"},
 				})
 				So(err, ShouldBeNil)
+				So(updatedRoom.ID, ShouldEqual, room.ID)
+				So(updatedRoom.Name, ShouldEqual, newRoomName)
+				So(updatedRoom.CustomData, ShouldResemble, map[string]interface{}{"foo": "baz"})
+				So(updatedRoom.UpdatedAt, ShouldHappenOnOrAfter, room.UpdatedAt)
 
 				Convey("and get it again", func() {
 					r, err := client.GetRoom(ctx, room.ID)
@@ -720,6 +1107,30 @@ func TestRooms(t *testing.T) {
 				})
 			})
 
+			Convey("and not update it if it changed since it was read", func() {
+				newRoomName := randomString()
+
+				// UpdatedAt has a resolution of a second.
+				time.Sleep(time.Second)
+
+				err := client.UpdateRoom(ctx, room.ID, UpdateRoomOptions{
+					Name:              &newRoomName,
+					ExpectedUpdatedAt: &room.UpdatedAt,
+				})
+				So(err, ShouldBeNil)
+
+				err = client.UpdateRoom(ctx, room.ID, UpdateRoomOptions{
+					CustomData:        map[string]interface{}{"foo": "baz"},
+					ExpectedUpdatedAt: &room.UpdatedAt,
+				})
+				So(err, ShouldEqual, ErrConflict)
+
+				r, err := client.GetRoom(ctx, room.ID)
+				So(err, ShouldBeNil)
+				So(r.Name, ShouldEqual, newRoomName)
+				So(r.CustomData, ShouldResemble, map[string]interface{}{"foo": "bar"})
+			})
+
 			Convey("and explicitly remove push notifications override", func() {
 				newRoomName := randomString()
 
@@ -742,6 +1153,19 @@ func TestRooms(t *testing.T) {
 				})
 			})
 
+			Convey("and delete it asynchronously", func() {
+				jobID, err := client.AsyncDeleteRoom(ctx, room.ID)
+				So(err, ShouldBeNil)
+				So(jobID, ShouldNotBeEmpty)
+
+				status, err := client.WaitForDelete(ctx, jobID, 100*time.Millisecond)
+				So(err, ShouldBeNil)
+				So(status.Status, ShouldEqual, DeleteStatusCompleted)
+
+				_, err = client.GetRoom(ctx, room.ID)
+				So(err.(*ErrorResponse).Status, ShouldEqual, 404)
+			})
+
 			Convey("and delete it", func() {
 				err := client.DeleteRoom(ctx, room.ID)
 				So(err, ShouldBeNil)
@@ -770,6 +1194,42 @@ func TestRooms(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(r.MemberUserIDs, shouldResembleUpToReordering, []string{aliceID})
 			})
+
+			Convey("and transfer its ownership to another member", func() {
+				ownerRoleName := randomString()
+				err := client.CreateRoomRole(ctx, CreateRoleOptions{
+					Name:        ownerRoleName,
+					Permissions: []string{"room:update", "room:delete"},
+				})
+				So(err, ShouldBeNil)
+
+				err = client.TransferRoomOwnership(ctx, room.ID, bobID, TransferRoomOwnershipOptions{
+					OwnerRoleName: ownerRoleName,
+				})
+				So(err, ShouldBeNil)
+
+				r, err := client.GetRoom(ctx, room.ID)
+				So(err, ShouldBeNil)
+				So(r.CustomData, ShouldResemble, map[string]interface{}{
+					"foo":                  "bar",
+					RoomOwnerCustomDataKey: bobID,
+				})
+
+				roles, err := client.GetUserRoles(ctx, bobID)
+				So(err, ShouldBeNil)
+				So(roles, ShouldContain, Role{
+					Name:        ownerRoleName,
+					Permissions: []string{"room:update", "room:delete"},
+					Scope:       "room",
+				})
+
+				Convey("but not to a user who isn't a member", func() {
+					err := client.TransferRoomOwnership(ctx, room.ID, carolID, TransferRoomOwnershipOptions{
+						OwnerRoleName: ownerRoleName,
+					})
+					So(err, ShouldNotBeNil)
+				})
+			})
 		})
 
 		Convey("we can create a room providing an ID", func() {
@@ -801,6 +1261,20 @@ func TestRooms(t *testing.T) {
 			})
 		})
 
+		Convey("we can create a direct room between two users", func() {
+			room, err := client.CreateDirectRoom(ctx, aliceID, bobID, CreateDirectRoomOptions{})
+			So(err, ShouldBeNil)
+			So(room.ID, ShouldEqual, DirectRoomID(bobID, aliceID))
+			So(room.Private, ShouldEqual, true)
+			So(room.MemberUserIDs, shouldResembleUpToReordering, []string{aliceID, bobID})
+
+			Convey("and get the same room back when creating it again", func() {
+				r, err := client.CreateDirectRoom(ctx, bobID, aliceID, CreateDirectRoomOptions{})
+				So(err, ShouldBeNil)
+				So(r.ID, ShouldEqual, room.ID)
+			})
+		})
+
 		Convey("we can create a couple of rooms", func() {
 			room1, err := client.CreateRoom(ctx, CreateRoomOptions{
 				Name:      randomString(),
@@ -827,6 +1301,28 @@ func TestRooms(t *testing.T) {
 				)
 			})
 
+			Convey("and iterate over them", func() {
+				it := client.IterateRooms(ctx, IterateRoomsOptions{IncludePrivate: true})
+
+				roomIDs := []string{}
+				for it.Next() {
+					roomIDs = append(roomIDs, it.Room().ID)
+				}
+				So(it.Err(), ShouldBeNil)
+				So(roomIDs, shouldResembleUpToReordering, []string{room1.ID, room2.ID})
+			})
+
+			Convey("and get them by ID", func() {
+				rooms, err := client.GetRoomsByID(ctx, []string{room2.ID, "missing", room1.ID})
+				So(err, ShouldHaveSameTypeAs, &BatchError{})
+				So(err.(*BatchError).Errors, ShouldContainKey, "missing")
+				So(len(err.(*BatchError).Errors), ShouldEqual, 1)
+				So(len(rooms), ShouldEqual, 3)
+				So(rooms[0].ID, ShouldEqual, room2.ID)
+				So(rooms[1].ID, ShouldEqual, "")
+				So(rooms[2].ID, ShouldEqual, room1.ID)
+			})
+
 			Convey("and get a user's rooms", func() {
 				rooms, err := client.GetUserRooms(ctx, bobID)
 				So(err, ShouldBeNil)
@@ -840,6 +1336,91 @@ func TestRooms(t *testing.T) {
 				So(len(rooms), ShouldEqual, 1)
 				So(rooms[0].ID, ShouldEqual, room2.ID)
 			})
+
+			Convey("and have a user with permission join and leave one", func() {
+				_, err := assignGlobalPermissionsToUser(client, carolID, []string{"room:join", "room:leave"})
+				So(err, ShouldBeNil)
+
+				room, err := client.JoinRoom(ctx, room2.ID, carolID)
+				So(err, ShouldBeNil)
+				So(room.MemberUserIDs, shouldResembleUpToReordering, []string{aliceID, carolID})
+
+				err = client.LeaveRoom(ctx, room2.ID, carolID)
+				So(err, ShouldBeNil)
+
+				r, err := client.GetRoom(ctx, room2.ID)
+				So(err, ShouldBeNil)
+				So(r.MemberUserIDs, ShouldResemble, []string{aliceID})
+			})
+
+			Convey("but not have a user without permission join one", func() {
+				_, err := client.JoinRoom(ctx, room2.ID, bobID)
+				So(err, ShouldNotBeNil)
+			})
+		})
+
+		Convey("we can subscribe to the rooms of a user and the members of a room", func() {
+			subscriptionCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			userEvents, err := client.SubscribeToUserEvents(subscriptionCtx, bobID, SubscriptionOptions{})
+			So(err, ShouldBeNil)
+
+			room, err := client.CreateRoom(ctx, CreateRoomOptions{
+				Name:      randomString(),
+				CreatorID: aliceID,
+			})
+			So(err, ShouldBeNil)
+
+			memberships, err := client.SubscribeToRoomMemberships(subscriptionCtx, room.ID, SubscriptionOptions{})
+			So(err, ShouldBeNil)
+
+			receiveMembership := func() MembershipEvent {
+				select {
+				case event := <-memberships:
+					return event
+				case <-time.After(10 * time.Second):
+					t.Fatal("Timed out waiting for a membership event")
+					return MembershipEvent{}
+				}
+			}
+
+			receiveUserEvent := func() UserSubscriptionEvent {
+				select {
+				case event := <-userEvents:
+					return event
+				case <-time.After(10 * time.Second):
+					t.Fatal("Timed out waiting for a user event")
+					return UserSubscriptionEvent{}
+				}
+			}
+
+			event := receiveMembership()
+			So(event.Type, ShouldEqual, MembershipEventUserJoined)
+			So(event.UserID, ShouldEqual, aliceID)
+
+			err = client.AddUsersToRoom(ctx, room.ID, []string{bobID})
+			So(err, ShouldBeNil)
+
+			event = receiveMembership()
+			So(event.Type, ShouldEqual, MembershipEventUserJoined)
+			So(event.UserID, ShouldEqual, bobID)
+
+			userEvent := receiveUserEvent()
+			So(userEvent.Type, ShouldEqual, UserSubscriptionEventAddedToRoom)
+			So(userEvent.RoomID, ShouldEqual, room.ID)
+			So(userEvent.Room.Name, ShouldEqual, room.Name)
+
+			err = client.RemoveUsersFromRoom(ctx, room.ID, []string{bobID})
+			So(err, ShouldBeNil)
+
+			event = receiveMembership()
+			So(event.Type, ShouldEqual, MembershipEventUserLeft)
+			So(event.UserID, ShouldEqual, bobID)
+
+			userEvent = receiveUserEvent()
+			So(userEvent.Type, ShouldEqual, UserSubscriptionEventRemovedFromRoom)
+			So(userEvent.RoomID, ShouldEqual, room.ID)
 		})
 
 		Reset(func() {
@@ -925,6 +1506,34 @@ func TestMessages(t *testing.T) {
 				So(messagesPage2[1].Text, ShouldEqual, "one")
 			})
 
+			Convey("and iterate over them", func() {
+				newestFirst := []uint{}
+				it := client.IterateRoomMessages(ctx, room.ID, IterateRoomMessagesOptions{PageSize: 3})
+				for it.Next() {
+					newestFirst = append(newestFirst, it.Message().ID)
+				}
+				So(it.Err(), ShouldBeNil)
+				So(newestFirst, ShouldResemble, []uint{messageID4, messageID3, messageID2, messageID1})
+
+				oldestFirst := []uint{}
+				it = client.IterateRoomMessages(ctx, room.ID, IterateRoomMessagesOptions{
+					Direction: "newer",
+					InitialID: &messageID1,
+				})
+				for it.Next() {
+					oldestFirst = append(oldestFirst, it.Message().ID)
+				}
+				So(it.Err(), ShouldBeNil)
+				So(oldestFirst, ShouldResemble, []uint{messageID2, messageID3, messageID4})
+			})
+
+			Convey("and count them", func() {
+				counts, err := client.GetRoomCounts(ctx, room.ID)
+				So(err, ShouldBeNil)
+				So(counts.Members, ShouldEqual, 1)
+				So(counts.Messages, ShouldEqual, 4)
+			})
+
 			Convey("and fetch one of them", func() {
 				message, err := client.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
 					MessageID: messageID3,
@@ -978,11 +1587,426 @@ func TestMessages(t *testing.T) {
 			})
 		})
 
-		Convey("we can publish a multipart messages", func() {
-			fileName := "cat.jpg"
-			file, err := os.Open(fileName)
-			So(err, ShouldBeNil)
-			defer file.Close()
+		Convey("we can publish messages and get them back", func() {
+			message, err := client.SendMessageAndGet(ctx, SendMessageOptions{
+				RoomID:   room.ID,
+				Text:     "one",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+			So(message.ID, ShouldNotEqual, 0)
+			So(message.Text, ShouldEqual, "one")
+			So(message.UserID, ShouldEqual, userID)
+			So(message.CreatedAt.IsZero(), ShouldBeFalse)
+
+			multipartMessage, err := client.SendSimpleMessageAndGet(ctx, SendSimpleMessageOptions{
+				RoomID:   room.ID,
+				Text:     "two",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+			So(multipartMessage.ID, ShouldBeGreaterThan, message.ID)
+			So(*multipartMessage.Parts[0].Content, ShouldEqual, "two")
+			So(multipartMessage.CreatedAt.IsZero(), ShouldBeFalse)
+		})
+
+		Convey("we can publish an attachment of known size from a stream", func() {
+			reader, writer := io.Pipe()
+			go func() {
+				writer.Write([]byte(`{"hello":"world"}`))
+				writer.Close()
+			}()
+
+			var bytesSent, totalBytes int64
+			message, err := client.SendMultipartMessageAndGet(ctx, SendMultipartMessageOptions{
+				RoomID:   room.ID,
+				SenderID: userID,
+				Parts: []NewPart{
+					NewAttachmentPart{
+						Type: "application/json",
+						File: reader,
+						Size: 17,
+						Progress: func(sent int64, total int64) {
+							bytesSent, totalBytes = sent, total
+						},
+					},
+				},
+			})
+			So(err, ShouldBeNil)
+			So(message.Parts[0].Attachment.Size, ShouldEqual, 17)
+			So(bytesSent, ShouldEqual, 17)
+			So(totalBytes, ShouldEqual, 17)
+		})
+
+		Convey("we can publish JSON and binary inline parts", func() {
+			jsonPart, err := NewJSONPart(map[string]interface{}{"hello": "world"})
+			So(err, ShouldBeNil)
+
+			message, err := client.SendMultipartMessageAndGet(ctx, SendMultipartMessageOptions{
+				RoomID:   room.ID,
+				SenderID: userID,
+				Parts: []NewPart{
+					jsonPart,
+					NewBinaryInlinePart("application/octet-stream", []byte{0, 1, 2}),
+				},
+			})
+			So(err, ShouldBeNil)
+
+			var content map[string]interface{}
+			So(DecodeJSONPart(message.Parts[0], &content), ShouldBeNil)
+			So(content, ShouldResemble, map[string]interface{}{"hello": "world"})
+
+			contentType, data, err := DecodeBinaryInlinePart(message.Parts[1])
+			So(err, ShouldBeNil)
+			So(contentType, ShouldEqual, "application/octet-stream")
+			So(data, ShouldResemble, []byte{0, 1, 2})
+		})
+
+		Convey("we can't publish a message with an invalid part type", func() {
+			_, err := client.SendMultipartMessage(ctx, SendMultipartMessageOptions{
+				RoomID:   room.ID,
+				SenderID: userID,
+				Parts: []NewPart{
+					NewInlinePart{Type: "text/plain", Content: "valid"},
+					NewURLPart{Type: "not a type", URL: "https://example.com"},
+				},
+			})
+			So(err, ShouldHaveSameTypeAs, &PartValidationError{})
+			So(err.(*PartValidationError).Index, ShouldEqual, 1)
+		})
+
+		Convey("we can react to a message", func() {
+			otherUserID, err := createUser(client)
+			So(err, ShouldBeNil)
+
+			messageID, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+				RoomID:   room.ID,
+				Text:     "react to me",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			So(client.AddReaction(ctx, room.ID, messageID, userID, "👍"), ShouldBeNil)
+			So(client.AddReaction(ctx, room.ID, messageID, otherUserID, "👍"), ShouldBeNil)
+			So(client.AddReaction(ctx, room.ID, messageID, otherUserID, "🎉"), ShouldBeNil)
+			So(client.RemoveReaction(ctx, room.ID, messageID, otherUserID, "🎉"), ShouldBeNil)
+
+			message, err := client.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+				RoomID:           room.ID,
+				MessageID:        messageID,
+				IncludeReactions: true,
+			})
+			So(err, ShouldBeNil)
+			So(len(message.Parts), ShouldEqual, 1)
+			So(message.Reactions, ShouldResemble, []ReactionSummary{
+				{Reaction: "👍", Count: 2, UserIDs: []string{userID, otherUserID}},
+			})
+		})
+
+		Convey("we can reply to a message in a thread", func() {
+			parentID, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+				RoomID:   room.ID,
+				Text:     "parent",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			replyIDs := []uint{}
+			for _, text := range []string{"reply one", "reply two"} {
+				replyID, err := client.SendMultipartMessage(ctx, SendMultipartMessageOptions{
+					RoomID:          room.ID,
+					SenderID:        userID,
+					Parts:           []NewPart{NewInlinePart{Type: "text/plain", Content: text}},
+					ParentMessageID: &parentID,
+				})
+				So(err, ShouldBeNil)
+				replyIDs = append(replyIDs, replyID)
+
+				_, err = client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+					RoomID:   room.ID,
+					Text:     "not a reply",
+					SenderID: userID,
+				})
+				So(err, ShouldBeNil)
+			}
+
+			replies, err := client.FetchThread(ctx, room.ID, parentID, FetchThreadOptions{})
+			So(err, ShouldBeNil)
+			So(len(replies), ShouldEqual, 2)
+			So(replies[0].ID, ShouldEqual, replyIDs[0])
+			So(*replies[0].ParentMessageID, ShouldEqual, parentID)
+			So(replies[1].ID, ShouldEqual, replyIDs[1])
+
+			nextPage, err := client.FetchThread(ctx, room.ID, parentID, FetchThreadOptions{
+				InitialID: &replyIDs[0],
+			})
+			So(err, ShouldBeNil)
+			So(len(nextPage), ShouldEqual, 1)
+			So(nextPage[0].ID, ShouldEqual, replyIDs[1])
+		})
+
+		Convey("we can fetch the latest messages of several rooms", func() {
+			otherRoom, err := client.CreateRoom(ctx, CreateRoomOptions{
+				Name:      randomString(),
+				CreatorID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			for _, text := range []string{"one", "two"} {
+				_, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+					RoomID:   room.ID,
+					Text:     text,
+					SenderID: userID,
+				})
+				So(err, ShouldBeNil)
+			}
+
+			latest, err := client.FetchLatestMessagesForRooms(ctx, []string{room.ID, otherRoom.ID}, 1)
+			So(err, ShouldBeNil)
+			So(len(latest), ShouldEqual, 2)
+			So(len(latest[room.ID]), ShouldEqual, 1)
+			So(*latest[room.ID][0].Parts[0].Content, ShouldEqual, "two")
+			So(len(latest[otherRoom.ID]), ShouldEqual, 0)
+		})
+
+		Convey("we can search messages", func() {
+			messageIDs := []uint{}
+			for _, text := range []string{"Hello world", "nothing to see", "HELLO again", "bye"} {
+				messageID, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+					RoomID:   room.ID,
+					Text:     text,
+					SenderID: userID,
+				})
+				So(err, ShouldBeNil)
+				messageIDs = append(messageIDs, messageID)
+			}
+
+			results, err := client.SearchRoomMessages(ctx, room.ID, "hello", SearchRoomMessagesOptions{
+				ContextSize: 1,
+			})
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 2)
+			So(results[0].Message.ID, ShouldEqual, messageIDs[0])
+			So(len(results[0].Before), ShouldEqual, 0)
+			So(len(results[0].After), ShouldEqual, 1)
+			So(results[0].After[0].ID, ShouldEqual, messageIDs[1])
+			So(results[1].Message.ID, ShouldEqual, messageIDs[2])
+			So(results[1].Before[0].ID, ShouldEqual, messageIDs[1])
+			So(results[1].After[0].ID, ShouldEqual, messageIDs[3])
+
+			results, err = client.SearchRoomMessages(ctx, room.ID, "^Hello", SearchRoomMessagesOptions{
+				Regexp:        true,
+				CaseSensitive: true,
+			})
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 1)
+			So(results[0].Message.ID, ShouldEqual, messageIDs[0])
+
+			results, err = client.SearchRoomMessages(ctx, room.ID, "hello", SearchRoomMessagesOptions{
+				Limit: 1,
+			})
+			So(err, ShouldBeNil)
+			So(len(results), ShouldEqual, 1)
+		})
+
+		Convey("we can schedule messages", func() {
+			scheduler := NewScheduler(client, SchedulerOptions{PollInterval: 100 * time.Millisecond})
+
+			_, err := scheduler.ScheduleMessage(ctx, SendMessageOptions{
+				RoomID:   room.ID,
+				Text:     "sent",
+				SenderID: userID,
+			}, time.Now().Add(500*time.Millisecond))
+			So(err, ShouldBeNil)
+
+			cancelledID, err := scheduler.ScheduleMessage(ctx, SendMessageOptions{
+				RoomID:   room.ID,
+				Text:     "cancelled",
+				SenderID: userID,
+			}, time.Now().Add(500*time.Millisecond))
+			So(err, ShouldBeNil)
+			So(scheduler.CancelScheduledMessage(ctx, cancelledID), ShouldBeNil)
+			So(scheduler.CancelScheduledMessage(ctx, cancelledID), ShouldEqual, ErrScheduledMessageNotFound)
+
+			runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			defer cancel()
+			So(scheduler.Run(runCtx), ShouldEqual, context.DeadlineExceeded)
+
+			messages, err := client.GetRoomMessages(ctx, room.ID, GetRoomMessagesOptions{})
+			So(err, ShouldBeNil)
+			So(len(messages), ShouldEqual, 1)
+			So(messages[0].Text, ShouldEqual, "sent")
+		})
+
+		Convey("we can publish messages that expire", func() {
+			_, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+				RoomID:       room.ID,
+				Text:         "secret",
+				SenderID:     userID,
+				ExpiresAfter: 500 * time.Millisecond,
+			})
+			So(err, ShouldBeNil)
+
+			_, err = client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+				RoomID:   room.ID,
+				Text:     "not secret",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			reaper := NewMessageReaper(client, MessageReaperOptions{PollInterval: 100 * time.Millisecond})
+			runCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+			defer cancel()
+			So(reaper.Run(runCtx), ShouldEqual, context.DeadlineExceeded)
+
+			messages, err := client.FetchMultipartMessages(ctx, room.ID, FetchMultipartMessagesOptions{})
+			So(err, ShouldBeNil)
+			So(len(messages), ShouldEqual, 1)
+			So(*messages[0].Parts[0].Content, ShouldEqual, "not secret")
+		})
+
+		Convey("we can redact a reply", func() {
+			parentID, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+				RoomID:   room.ID,
+				Text:     "question",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			replyID, err := client.SendMultipartMessage(ctx, SendMultipartMessageOptions{
+				RoomID:          room.ID,
+				SenderID:        userID,
+				Parts:           []NewPart{NewInlinePart{Type: "text/plain", Content: "rude answer"}},
+				ParentMessageID: &parentID,
+			})
+			So(err, ShouldBeNil)
+
+			So(client.RedactMessage(ctx, room.ID, replyID, ""), ShouldBeNil)
+
+			reply, err := client.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+				RoomID:    room.ID,
+				MessageID: replyID,
+			})
+			So(err, ShouldBeNil)
+			So(IsRedacted(reply), ShouldBeTrue)
+			So(reply.PlainText(), ShouldEqual, "This message has been removed.")
+			So(reply.UserID, ShouldEqual, userID)
+			So(reply.ParentMessageID, ShouldNotBeNil)
+			So(*reply.ParentMessageID, ShouldEqual, parentID)
+		})
+
+		Convey("we can pin messages", func() {
+			messageIDs := []uint{}
+			for _, text := range []string{"one", "two", "three"} {
+				messageID, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+					RoomID:   room.ID,
+					Text:     text,
+					SenderID: userID,
+				})
+				So(err, ShouldBeNil)
+				messageIDs = append(messageIDs, messageID)
+			}
+
+			So(client.PinMessage(ctx, room.ID, messageIDs[2]), ShouldBeNil)
+			So(client.PinMessage(ctx, room.ID, messageIDs[0]), ShouldBeNil)
+			So(client.PinMessage(ctx, room.ID, messageIDs[1]), ShouldBeNil)
+			So(client.UnpinMessage(ctx, room.ID, messageIDs[1]), ShouldBeNil)
+
+			pinned, err := client.GetPinnedMessages(ctx, room.ID)
+			So(err, ShouldBeNil)
+			So(len(pinned), ShouldEqual, 2)
+			So(pinned[0].ID, ShouldEqual, messageIDs[2])
+			So(pinned[1].ID, ShouldEqual, messageIDs[0])
+		})
+
+		Convey("we can publish a message as the service", func() {
+			messageID, err := client.SendMessageAsService(ctx, SendMultipartMessageOptions{
+				RoomID:   room.ID,
+				SenderID: userID,
+				Parts:    []NewPart{NewInlinePart{Type: "text/plain", Content: "announcement"}},
+			})
+			So(err, ShouldBeNil)
+
+			message, err := client.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+				RoomID:    room.ID,
+				MessageID: messageID,
+			})
+			So(err, ShouldBeNil)
+			So(message.UserID, ShouldEqual, userID)
+			So(*message.Parts[0].Content, ShouldEqual, "announcement")
+		})
+
+		Convey("we can publish a message only once using an idempotency key", func() {
+			options := SendSimpleMessageOptions{
+				RoomID:         room.ID,
+				Text:           "once",
+				SenderID:       userID,
+				IdempotencyKey: randomString(),
+			}
+
+			messageID, err := client.SendSimpleMessage(ctx, options)
+			So(err, ShouldBeNil)
+
+			retriedMessageID, err := client.SendSimpleMessage(ctx, options)
+			So(err, ShouldBeNil)
+			So(retriedMessageID, ShouldEqual, messageID)
+
+			messages, err := client.FetchMultipartMessages(ctx, room.ID, FetchMultipartMessagesOptions{})
+			So(err, ShouldBeNil)
+			So(len(messages), ShouldEqual, 1)
+		})
+
+		Convey("we can rename the user and announce it in the room", func() {
+			newName := randomString()
+			err := client.RenameUser(ctx, userID, RenameUserOptions{
+				Name:     &newName,
+				Announce: true,
+			})
+			So(err, ShouldBeNil)
+
+			messages, err := client.FetchMultipartMessages(ctx, room.ID, FetchMultipartMessagesOptions{})
+			So(err, ShouldBeNil)
+			So(len(messages), ShouldEqual, 1)
+			So(IsSystemPart(messages[0].Parts[0]), ShouldBeTrue)
+
+			event, err := ParseSystemPart(messages[0].Parts[0])
+			So(err, ShouldBeNil)
+			So(event, ShouldResemble, SystemEvent{
+				Type:         SystemEventUserRenamed,
+				UserID:       userID,
+				Name:         newName,
+				PreviousName: "integration-test-user",
+			})
+		})
+
+		Convey("we can publish a system message", func() {
+			event := SystemEvent{Type: SystemEventUserJoined, UserID: userID}
+			messageID, err := client.SendSystemMessage(ctx, SendSystemMessageOptions{
+				RoomID:   room.ID,
+				SenderID: userID,
+				Event:    event,
+			})
+			So(err, ShouldBeNil)
+
+			message, err := client.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+				RoomID:    room.ID,
+				MessageID: messageID,
+			})
+			So(err, ShouldBeNil)
+			So(len(message.Parts), ShouldEqual, 2)
+			So(*message.Parts[1].Content, ShouldEqual, userID+" joined the room")
+
+			parsedEvent, isSystemMessage, err := ParseSystemMessage(message)
+			So(err, ShouldBeNil)
+			So(isSystemMessage, ShouldBeTrue)
+			So(parsedEvent, ShouldResemble, event)
+		})
+
+		Convey("we can publish a multipart messages", func() {
+			fileName := "cat.jpg"
+			file, err := os.Open(fileName)
+			So(err, ShouldBeNil)
+			defer file.Close()
 
 			messageID, err := client.SendMultipartMessage(ctx, SendMultipartMessageOptions{
 				RoomID:   room.ID,
@@ -1020,6 +2044,16 @@ func TestMessages(t *testing.T) {
 				)
 			})
 
+			Convey("and fetch it with either API", func() {
+				limit := uint(1)
+				messages, err := client.GetMessages(ctx, room.ID, GetMessagesOptions{Limit: &limit})
+				So(err, ShouldBeNil)
+				So(len(messages), ShouldEqual, 1)
+				So(messages[0].ID, ShouldEqual, messageID)
+				So(messages[0].Text, ShouldEqual, "see attached")
+				So(len(messages[0].Parts), ShouldEqual, 5)
+			})
+
 			Convey("and fetch it (v6)", func() {
 				limit := uint(1)
 				messages, err := client.FetchMultipartMessages(
@@ -1066,6 +2100,17 @@ func TestMessages(t *testing.T) {
 				So(err, ShouldBeNil)
 				So(string(body), ShouldEqual, `{"hello":"world"}`)
 
+				var downloaded bytes.Buffer
+				err = client.DownloadAttachment(ctx, *messages[0].Parts[3].Attachment, &downloaded)
+				So(err, ShouldBeNil)
+				So(downloaded.String(), ShouldEqual, `{"hello":"world"}`)
+
+				refreshed, err := client.RefreshAttachment(ctx, *messages[0].Parts[3].Attachment)
+				So(err, ShouldBeNil)
+				So(refreshed.ID, ShouldEqual, messages[0].Parts[3].Attachment.ID)
+				So(refreshed.DownloadURL, ShouldNotEqual, "")
+				So(refreshed.Expiration, ShouldHappenOnOrAfter, messages[0].Parts[3].Attachment.Expiration)
+
 				So(messages[0].Parts[4].Type, ShouldEqual, "image/png")
 				So(messages[0].Parts[4].Content, ShouldBeNil)
 				So(messages[0].Parts[4].URL, ShouldBeNil)
@@ -1077,6 +2122,107 @@ func TestMessages(t *testing.T) {
 				So(messages[0].Parts[4].Attachment.CustomData, ShouldBeNil)
 				So(messages[0].Parts[4].Attachment.DownloadURL, ShouldNotEqual, "")
 			})
+
+			Convey("and export it", func() {
+				var buf bytes.Buffer
+				err := client.ExportRoomMessages(ctx, room.ID, &buf, ExportFormatNDJSON, ExportRoomMessagesOptions{})
+				So(err, ShouldBeNil)
+
+				var exported MultipartMessage
+				So(json.Unmarshal(buf.Bytes(), &exported), ShouldBeNil)
+				So(exported.ID, ShouldEqual, messageID)
+				So(len(exported.Parts), ShouldEqual, 5)
+				So(exported.Parts[4].Attachment, ShouldNotBeNil)
+				So(exported.Parts[4].Attachment.Name, ShouldEqual, fileName)
+
+				buf.Reset()
+				err = client.ExportRoomMessages(ctx, room.ID, &buf, ExportFormatCSV, ExportRoomMessagesOptions{})
+				So(err, ShouldBeNil)
+
+				records, err := csv.NewReader(&buf).ReadAll()
+				So(err, ShouldBeNil)
+				So(len(records), ShouldEqual, 2)
+				So(records[0], ShouldResemble, []string{
+					"id", "user_id", "room_id", "parent_message_id", "text", "parts", "created_at", "updated_at",
+				})
+				So(records[1][4], ShouldEqual, "see attached")
+			})
+		})
+
+		Convey("we can subscribe to the messages sent to the room", func() {
+			subscriptionCtx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			messages, err := client.SubscribeToRoomMessages(subscriptionCtx, room.ID, SubscribeToRoomMessagesOptions{})
+			So(err, ShouldBeNil)
+
+			messageID, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+				RoomID:   room.ID,
+				Text:     "hello",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			select {
+			case message := <-messages:
+				So(message.ID, ShouldEqual, messageID)
+				So(message.UserID, ShouldEqual, userID)
+			case <-time.After(10 * time.Second):
+				t.Fatal("Timed out waiting for the message")
+			}
+
+			Convey("and resume after a message", func() {
+				resumed, err := client.SubscribeToRoomMessages(
+					subscriptionCtx,
+					room.ID,
+					SubscribeToRoomMessagesOptions{AfterMessageID: messageID - 1},
+				)
+				So(err, ShouldBeNil)
+
+				select {
+				case message := <-resumed:
+					So(message.ID, ShouldEqual, messageID)
+				case <-time.After(10 * time.Second):
+					t.Fatal("Timed out waiting for the message")
+				}
+			})
+		})
+
+		Convey("we can share a subscription between consumers with a subscription manager", func() {
+			var connected []string
+			manager := client.NewSubscriptionManager(SubscriptionManagerOptions{
+				OnConnected: func(subscription string) {
+					connected = append(connected, subscription)
+				},
+			})
+			defer manager.Close()
+
+			first, err := manager.SubscribeToRoomMessages(ctx, room.ID)
+			So(err, ShouldBeNil)
+
+			second, err := manager.SubscribeToRoomMessages(ctx, room.ID)
+			So(err, ShouldBeNil)
+			So(connected, ShouldResemble, []string{"rooms/" + room.ID + "/messages"})
+
+			messageID, err := client.SendSimpleMessage(ctx, SendSimpleMessageOptions{
+				RoomID:   room.ID,
+				Text:     "hello",
+				SenderID: userID,
+			})
+			So(err, ShouldBeNil)
+
+			for _, messages := range []<-chan MultipartMessage{first, second} {
+				select {
+				case message := <-messages:
+					So(message.ID, ShouldEqual, messageID)
+				case <-time.After(10 * time.Second):
+					t.Fatal("Timed out waiting for the message")
+				}
+			}
+
+			So(manager.Close(), ShouldBeNil)
+			_, open := <-first
+			So(open, ShouldBeFalse)
 		})
 
 		Reset(func() {
@@ -1154,6 +2300,38 @@ func TestEditMessages(t *testing.T) {
 					}})
 				So(err, ShouldBeNil)
 			})
+			Convey("Messages with attachments can be edited keeping the attachment", func() {
+				messageID, err := client.SendMultipartMessage(ctx, SendMultipartMessageOptions{
+					RoomID:   room.ID,
+					SenderID: userID,
+					Parts: []NewPart{
+						NewInlinePart{Type: "text/plain", Content: "four"},
+						NewAttachmentPart{Type: "application/json", File: strings.NewReader(`{"hello":"world"}`)},
+					}})
+				So(err, ShouldBeNil)
+
+				message, err := client.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+					RoomID:    room.ID,
+					MessageID: messageID,
+				})
+				So(err, ShouldBeNil)
+
+				err = client.EditMultipartMessage(ctx, room.ID, messageID, EditMultipartMessageOptions{
+					SenderID: userID,
+					Parts: []NewPart{
+						NewInlinePart{Type: "text/plain", Content: "four-edited"},
+						message.Parts[1].AsNewPart(),
+					}})
+				So(err, ShouldBeNil)
+
+				edited, err := client.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+					RoomID:    room.ID,
+					MessageID: messageID,
+				})
+				So(err, ShouldBeNil)
+				So(*edited.Parts[0].Content, ShouldEqual, "four-edited")
+				So(edited.Parts[1].Attachment.ID, ShouldEqual, message.Parts[1].Attachment.ID)
+			})
 
 		})
 
@@ -1164,3 +2342,622 @@ func TestEditMessages(t *testing.T) {
 	})
 
 }
+
+func TestTokens(t *testing.T) {
+	config, err := getConfig()
+	if err != nil {
+		t.Fatalf("Failed to get test config: %s", err.Error())
+	}
+
+	client, err := NewClient(config.instanceLocator, config.key, WithStore(NewMemoryStore()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %s", err.Error())
+	}
+
+	Convey("Given a token generated for a user", t, func() {
+		userID := randomString()
+		tokenWithExpiry, err := client.GenerateAccessToken(AuthenticateOptions{
+			UserID:        &userID,
+			ServiceClaims: map[string]interface{}{"tenant": "acme"},
+		})
+		So(err, ShouldBeNil)
+
+		Convey("it can be verified", func() {
+			claims, err := client.VerifyToken(context.Background(), tokenWithExpiry.Token)
+			So(err, ShouldBeNil)
+			So(claims.UserID, ShouldEqual, userID)
+			So(claims.SU, ShouldBeFalse)
+			So(claims.Expiry, ShouldHappenAfter, time.Now())
+			So(claims.ServiceClaims["tenant"], ShouldEqual, "acme")
+		})
+
+		Convey("it can't be verified once tampered with", func() {
+			_, err := client.VerifyToken(context.Background(), tokenWithExpiry.Token+"x")
+			So(err, ShouldEqual, ErrInvalidToken)
+		})
+
+		Convey("a refresh token can be exchanged for a new token once", func() {
+			refreshToken, err := client.IssueRefreshToken(context.Background(), userID)
+			So(err, ShouldBeNil)
+
+			response, err := client.RefreshAccessToken(context.Background(), refreshToken, AuthenticateOptions{})
+			So(err, ShouldBeNil)
+			So(response.RefreshToken, ShouldNotEqual, refreshToken)
+
+			claims, err := client.VerifyToken(context.Background(), response.AccessToken)
+			So(err, ShouldBeNil)
+			So(claims.UserID, ShouldEqual, userID)
+
+			_, err = client.RefreshAccessToken(context.Background(), refreshToken, AuthenticateOptions{})
+			So(err, ShouldEqual, ErrInvalidRefreshToken)
+
+			err = client.RevokeRefreshToken(context.Background(), response.RefreshToken)
+			So(err, ShouldBeNil)
+
+			_, err = client.RefreshAccessToken(context.Background(), response.RefreshToken, AuthenticateOptions{})
+			So(err, ShouldEqual, ErrInvalidRefreshToken)
+		})
+
+		Convey("service claims can't replace the claims set by Chatkit", func() {
+			_, err := client.GenerateAccessToken(AuthenticateOptions{
+				UserID:        &userID,
+				ServiceClaims: map[string]interface{}{"sub": "someone-else"},
+			})
+			So(err, ShouldNotBeNil)
+		})
+
+		Convey("the default lifetime of tokens can be configured", func() {
+			shortLivedClient, err := NewClient(
+				config.instanceLocator,
+				config.key,
+				WithTokenOptions(TokenOptions{AccessTokenExpiry: 15 * time.Minute}),
+			)
+			So(err, ShouldBeNil)
+
+			tokenWithExpiry, err := shortLivedClient.GenerateAccessToken(AuthenticateOptions{UserID: &userID})
+			So(err, ShouldBeNil)
+			So(tokenWithExpiry.ExpiresIn, ShouldEqual, (15 * time.Minute).Seconds())
+
+			claims, err := shortLivedClient.VerifyToken(context.Background(), tokenWithExpiry.Token)
+			So(err, ShouldBeNil)
+			So(claims.Expiry, ShouldHappenBefore, time.Now().Add(16*time.Minute))
+		})
+
+		Convey("it can be revoked", func() {
+			err := client.RevokeToken(context.Background(), tokenWithExpiry.Token)
+			So(err, ShouldBeNil)
+
+			_, err = client.VerifyToken(context.Background(), tokenWithExpiry.Token)
+			So(err, ShouldEqual, ErrTokenRevoked)
+		})
+
+		Convey("it can be revoked along with the user's other tokens", func() {
+			err := client.RevokeTokensForUser(context.Background(), userID)
+			So(err, ShouldBeNil)
+
+			_, err = client.VerifyToken(context.Background(), tokenWithExpiry.Token)
+			So(err, ShouldEqual, ErrTokenRevoked)
+		})
+
+		Convey("a read only token can be generated for a service user", func() {
+			serviceUserID := randomString()
+			tokenWithExpiry, err := client.GenerateReadOnlyToken(context.Background(), ReadOnlyTokenOptions{
+				UserID: serviceUserID,
+			})
+			So(err, ShouldBeNil)
+
+			claims, err := client.VerifyToken(context.Background(), tokenWithExpiry.Token)
+			So(err, ShouldBeNil)
+			So(claims.UserID, ShouldEqual, serviceUserID)
+			So(claims.SU, ShouldBeFalse)
+			So(claims.Permissions, shouldResembleUpToReordering, ReadOnlyPermissions)
+		})
+
+		Convey("it is accepted by the auth middleware", func() {
+			handler := client.AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				userID, _ := UserIDFromContext(r.Context())
+				fmt.Fprint(w, userID)
+			}), VerifierOptions{})
+
+			request := httptest.NewRequest(http.MethodGet, "/", nil)
+			request.Header.Set("Authorization", "Bearer "+tokenWithExpiry.Token)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			So(recorder.Code, ShouldEqual, http.StatusOK)
+			So(recorder.Body.String(), ShouldEqual, userID)
+
+			request = httptest.NewRequest(http.MethodGet, "/", nil)
+			recorder = httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			So(recorder.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Convey("tokens for other instances can't be verified", func() {
+			otherClient, err := NewClient(config.instanceLocator, "other:c2VjcmV0")
+			So(err, ShouldBeNil)
+
+			_, err = otherClient.VerifyToken(context.Background(), tokenWithExpiry.Token)
+			So(err, ShouldEqual, ErrInvalidToken)
+		})
+	})
+}
+
+func TestTokenProvider(t *testing.T) {
+	config, err := getConfig()
+	if err != nil {
+		t.Fatalf("Failed to get test config: %s", err.Error())
+	}
+
+	client, err := NewClient(config.instanceLocator, config.key)
+	if err != nil {
+		t.Fatalf("Failed to create client: %s", err.Error())
+	}
+
+	Convey("Given a token provider endpoint", t, func() {
+		userID := randomString()
+		server := httptest.NewServer(client.TokenProviderHandler(TokenProviderOptions{
+			Authorizer: func(r *http.Request) (string, error) {
+				if r.Header.Get("Authorization") != "Bearer session" {
+					return "", errors.New("no session")
+				}
+				return userID, nil
+			},
+		}))
+
+		requestToken := func(authorization string) (*http.Response, error) {
+			request, err := http.NewRequest(
+				http.MethodPost,
+				server.URL,
+				strings.NewReader(url.Values{"grant_type": {GrantTypeClientCredentials}}.Encode()),
+			)
+			if err != nil {
+				return nil, err
+			}
+			request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			request.Header.Set("Authorization", authorization)
+
+			return http.DefaultClient.Do(request)
+		}
+
+		Convey("it issues tokens to authorized users", func() {
+			response, err := requestToken("Bearer session")
+			So(err, ShouldBeNil)
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+
+			var body auth.TokenResponse
+			err = json.NewDecoder(response.Body).Decode(&body)
+			So(err, ShouldBeNil)
+
+			claims, err := client.VerifyToken(context.Background(), body.AccessToken)
+			So(err, ShouldBeNil)
+			So(claims.UserID, ShouldEqual, userID)
+		})
+
+		Convey("it refuses unauthorized users", func() {
+			response, err := requestToken("")
+			So(err, ShouldBeNil)
+			defer response.Body.Close()
+			So(response.StatusCode, ShouldEqual, http.StatusUnauthorized)
+		})
+
+		Reset(func() {
+			server.Close()
+		})
+	})
+}
+
+func TestWebhookSignatures(t *testing.T) {
+	Convey("Given a webhook request", t, func() {
+		body := []byte(`{"metadata":{"event_type":"v1.users_created"}}`)
+		// HMAC-SHA1 of body keyed with "secret"
+		mac := hmac.New(sha1.New, []byte("secret"))
+		mac.Write(body)
+		signature := hex.EncodeToString(mac.Sum(nil))
+
+		request := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+
+		Convey("it is verified with the right secret", func() {
+			request.Header.Set(WebhookSignatureHeader, signature)
+			verifiedBody, err := VerifyWebhookRequest(request, "secret")
+			So(err, ShouldBeNil)
+			So(verifiedBody, ShouldResemble, body)
+		})
+
+		Convey("it is refused with the wrong secret", func() {
+			request.Header.Set(WebhookSignatureHeader, signature)
+			_, err := VerifyWebhookRequest(request, "other")
+			So(err, ShouldEqual, ErrInvalidWebhookSignature)
+		})
+
+		Convey("it is refused without a signature", func() {
+			_, err := VerifyWebhookRequest(request, "secret")
+			So(err, ShouldEqual, ErrInvalidWebhookSignature)
+		})
+	})
+
+	Convey("Given a webhook handler", t, func() {
+		handler := NewWebhookHandler(WebhookHandlerOptions{Secret: "secret"})
+
+		var createdUserIDs []string
+		handler.OnUserCreated(func(ctx context.Context, event UserEvent) error {
+			createdUserIDs = append(createdUserIDs, event.User.ID)
+			return nil
+		})
+
+		send := func(body []byte, secret string) int {
+			mac := hmac.New(sha1.New, []byte(secret))
+			mac.Write(body)
+
+			request := httptest.NewRequest(http.MethodPost, "/webhooks", bytes.NewReader(body))
+			request.Header.Set(WebhookSignatureHeader, hex.EncodeToString(mac.Sum(nil)))
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			return recorder.Code
+		}
+
+		Convey("it calls the callbacks for each event", func() {
+			status := send([]byte(`{
+				"metadata": {"event_type": "v1.users_created", "attempt": 1},
+				"payload": {"users": [{"id": "alice"}, {"id": "bob"}]}
+			}`), "secret")
+			So(status, ShouldEqual, http.StatusOK)
+			So(createdUserIDs, ShouldResemble, []string{"alice", "bob"})
+		})
+
+		Convey("it processes webhooks delivered to AWS Lambda", func() {
+			body := `{
+				"metadata": {"event_type": "v1.users_created", "attempt": 1},
+				"payload": {"users": [{"id": "alice"}]}
+			}`
+			mac := hmac.New(sha1.New, []byte("secret"))
+			mac.Write([]byte(body))
+
+			response, err := handler.HandleAPIGatewayProxyRequest(context.Background(), APIGatewayProxyRequest{
+				HTTPMethod: http.MethodPost,
+				Path:       "/webhooks",
+				Headers:    map[string]string{WebhookSignatureHeader: hex.EncodeToString(mac.Sum(nil))},
+				Body:       body,
+			})
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(createdUserIDs, ShouldResemble, []string{"alice"})
+		})
+
+		Convey("it refuses requests that aren't signed with the secret", func() {
+			status := send([]byte(`{"metadata": {"event_type": "v1.users_created"}}`), "other")
+			So(status, ShouldEqual, http.StatusUnauthorized)
+			So(createdUserIDs, ShouldBeEmpty)
+		})
+
+		Reset(func() {
+			handler.Close(context.Background())
+		})
+	})
+}
+
+func TestWebhookBridge(t *testing.T) {
+	Convey("Given a webhook bridge", t, func() {
+		var (
+			mu      sync.Mutex
+			batches [][]WebhookEvent
+			fail    bool
+		)
+		bridge := NewWebhookBridge(PublisherFunc(func(ctx context.Context, events []WebhookEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if fail {
+				return errors.New("unavailable")
+			}
+			batches = append(batches, events)
+			return nil
+		}), WebhookBridgeOptions{BatchSize: 2, MaxAttempts: 2, RetryBackoff: time.Millisecond})
+
+		publish := func(eventTypes ...string) []error {
+			errs := make([]error, len(eventTypes))
+			var wg sync.WaitGroup
+			for i, eventType := range eventTypes {
+				wg.Add(1)
+				go func(i int, eventType string) {
+					defer wg.Done()
+					errs[i] = bridge.Publish(context.Background(), WebhookEvent{
+						Metadata: WebhookMetadata{EventType: eventType},
+					})
+				}(i, eventType)
+			}
+			wg.Wait()
+			return errs
+		}
+
+		Convey("it publishes events in batches", func() {
+			errs := publish(WebhookEventUsersCreated, WebhookEventUsersDeleted, WebhookEventMessagesCreated)
+			So(errs, ShouldResemble, []error{nil, nil, nil})
+			So(batches, ShouldHaveLength, 2)
+			So(len(batches[0])+len(batches[1]), ShouldEqual, 3)
+		})
+
+		Convey("it returns the error of batches that fail", func() {
+			fail = true
+			errs := publish(WebhookEventUsersCreated, WebhookEventUsersDeleted)
+			So(errs[0], ShouldNotBeNil)
+			So(errs[1], ShouldNotBeNil)
+		})
+
+		Reset(func() {
+			err := bridge.Close(context.Background())
+			So(err, ShouldBeNil)
+		})
+	})
+}
+
+func TestRoomIDDecoding(t *testing.T) {
+	Convey("Room IDs are decoded whether they are strings or numbers", t, func() {
+		var cursors []Cursor
+		err := json.Unmarshal([]byte(`[
+			{"cursor_type": 0, "room_id": "general", "user_id": "alice", "position": 1},
+			{"cursor_type": 0, "room_id": 42, "user_id": "alice", "position": 2}
+		]`), &cursors)
+		So(err, ShouldBeNil)
+		So(cursors[0].RoomID, ShouldEqual, "general")
+		So(cursors[0].Position, ShouldEqual, 1)
+		So(cursors[1].RoomID, ShouldEqual, "42")
+		So(cursors[1].UserID, ShouldEqual, "alice")
+	})
+}
+
+func TestDebugHTTP(t *testing.T) {
+	Convey("Given an HTTP dumper", t, func() {
+		var buf bytes.Buffer
+		dumper := &httpDumper{w: &buf}
+
+		jwt := "secret-token"
+		next := func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+			body, err := ioutil.ReadAll(options.Body)
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, `{"name":"general"}`)
+
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				ProtoMajor: 1,
+				ProtoMinor: 1,
+				Header:     http.Header{"Set-Cookie": []string{"session=secret"}},
+				Body:       ioutil.NopCloser(strings.NewReader(`{"id":"1"}`)),
+			}, nil
+		}
+
+		Convey("it dumps requests and responses with their tokens redacted", func() {
+			response, err := dumper.intercept(context.Background(), platformclient.RequestOptions{
+				Method:      http.MethodPost,
+				Path:        "/rooms?jwt=secret-token",
+				Jwt:         &jwt,
+				QueryParams: &url.Values{"limit": []string{"1"}},
+				Body:        strings.NewReader(`{"name":"general"}`),
+			}, next)
+			So(err, ShouldBeNil)
+
+			body, err := ioutil.ReadAll(response.Body)
+			So(err, ShouldBeNil)
+			So(string(body), ShouldEqual, `{"id":"1"}`)
+
+			dump := buf.String()
+			So(dump, ShouldContainSubstring, "--> POST /rooms?jwt=%5BREDACTED%5D&limit=1")
+			So(dump, ShouldContainSubstring, "Authorization: [REDACTED]")
+			So(dump, ShouldContainSubstring, `{"name":"general"}`)
+			So(dump, ShouldContainSubstring, "HTTP/1.1 201 Created")
+			So(dump, ShouldContainSubstring, "Set-Cookie: [REDACTED]")
+			So(dump, ShouldContainSubstring, `{"id":"1"}`)
+			So(dump, ShouldNotContainSubstring, "secret")
+		})
+
+		Convey("it dumps nothing once disabled", func() {
+			dumper.setWriter(nil)
+			_, err := dumper.intercept(context.Background(), platformclient.RequestOptions{
+				Method: http.MethodPost,
+				Path:   "/rooms",
+				Body:   strings.NewReader(`{"name":"general"}`),
+			}, next)
+			So(err, ShouldBeNil)
+			So(buf.Len(), ShouldEqual, 0)
+		})
+	})
+}
+
+type testSpan struct {
+	name       string
+	attributes map[string]interface{}
+	err        error
+	ended      bool
+}
+
+func (s *testSpan) SetAttribute(key string, value interface{}) { s.attributes[key] = value }
+func (s *testSpan) RecordError(err error)                      { s.err = err }
+func (s *testSpan) End()                                       { s.ended = true }
+
+type testTracer struct {
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attributes: map[string]interface{}{}}
+	t.spans = append(t.spans, span)
+	return ctx, span
+}
+
+func TestTracing(t *testing.T) {
+	Convey("Given a traced request", t, func() {
+		tracer := &testTracer{}
+		intercept := traceRequest(tracer)
+
+		_, err := intercept(context.Background(), platformclient.RequestOptions{
+			Method: http.MethodPost,
+			Path:   "/services/chatkit/v6/instance/rooms/general%2Fnews/messages",
+		}, func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+			return nil, &platformclient.ErrorResponse{Status: http.StatusForbidden}
+		})
+		So(err, ShouldNotBeNil)
+
+		Convey("it is traced with a span named after its route", func() {
+			So(len(tracer.spans), ShouldEqual, 1)
+
+			span := tracer.spans[0]
+			So(span.name, ShouldEqual, "POST /rooms/{room_id}/messages")
+			So(span.attributes["chatkit.service"], ShouldEqual, "chatkit")
+			So(span.attributes["chatkit.room_id"], ShouldEqual, "general/news")
+			So(span.attributes["http.status_code"], ShouldEqual, http.StatusForbidden)
+			So(span.err, ShouldEqual, err)
+			So(span.ended, ShouldBeTrue)
+		})
+	})
+
+	Convey("Routes are parsed from request paths", t, func() {
+		r := parseRoute("/services/chatkit_cursors/v2/instance/cursors/0/rooms/general?limit=1")
+		So(r.Service, ShouldEqual, "chatkit_cursors")
+		So(r.Template, ShouldEqual, "/cursors/{cursor_type}/rooms/{room_id}")
+		So(r.Params, ShouldResemble, map[string]string{"cursor_type": "0", "room_id": "general"})
+
+		r = parseRoute("/services/chatkit/v6/instance/rooms/general/users/add")
+		So(r.Template, ShouldEqual, "/rooms/{room_id}/users/add")
+	})
+}
+
+type testPlatformClient func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error)
+
+func (c testPlatformClient) Request(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+	return c(ctx, options)
+}
+
+func TestInterceptors(t *testing.T) {
+	Convey("Given a client with interceptors", t, func() {
+		var calls []string
+		interceptor := func(name string) Interceptor {
+			return func(ctx context.Context, options RequestOptions, next Invoker) (*http.Response, error) {
+				calls = append(calls, name)
+				options.Headers = http.Header{"X-Interceptor": []string{name}}
+				return next(ctx, options)
+			}
+		}
+
+		client := common.NewInterceptingClient(
+			testPlatformClient(func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+				calls = append(calls, options.Headers.Get("X-Interceptor"))
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			}),
+			interceptor("first"),
+			interceptor("second"),
+		)
+
+		Convey("requests go through them in order", func() {
+			response, err := client.Request(context.Background(), RequestOptions{Method: http.MethodGet, Path: "/users"})
+			So(err, ShouldBeNil)
+			So(response.StatusCode, ShouldEqual, http.StatusOK)
+			So(calls, ShouldResemble, []string{"first", "second", "second"})
+		})
+	})
+}
+
+func TestStats(t *testing.T) {
+	Convey("Given client stats", t, func() {
+		stats := newClientStats()
+		next := func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+			if _, err := ioutil.ReadAll(options.Body); err != nil {
+				return nil, err
+			}
+
+			return &http.Response{
+				StatusCode: http.StatusCreated,
+				Body:       ioutil.NopCloser(strings.NewReader(`{"id":"1"}`)),
+			}, nil
+		}
+
+		Convey("requests are counted with the bytes they send and receive", func() {
+			for i := 0; i < 2; i++ {
+				response, err := stats.intercept(context.Background(), platformclient.RequestOptions{
+					Method: http.MethodPost,
+					Path:   "/services/chatkit/v6/instance/rooms/general/messages",
+					Body:   strings.NewReader(`{"text":"hi"}`),
+				}, next)
+				So(err, ShouldBeNil)
+
+				_, err = ioutil.ReadAll(response.Body)
+				So(err, ShouldBeNil)
+				So(response.Body.Close(), ShouldBeNil)
+			}
+
+			snapshot := stats.snapshot()
+			So(snapshot.Requests, ShouldResemble, map[RequestStatsKey]uint64{
+				{Method: http.MethodPost, Route: "/rooms/{room_id}/messages", Status: http.StatusCreated}: 2,
+			})
+			So(snapshot.BytesSent, ShouldEqual, 26)
+			So(snapshot.BytesReceived, ShouldEqual, 20)
+		})
+
+		Convey("the token cache hit rate is computed from its hits and misses", func() {
+			So(Stats{}.TokenCacheHitRate(), ShouldEqual, 0)
+			So(Stats{TokenCacheHits: 3, TokenCacheMisses: 1}.TokenCacheHitRate(), ShouldEqual, 0.75)
+		})
+	})
+}
+
+func TestSlowRequests(t *testing.T) {
+	Convey("Given a slow request threshold", t, func() {
+		var slow []CallInfo
+		intercept := reportSlowRequests(10*time.Millisecond, func(info CallInfo) {
+			slow = append(slow, info)
+		})
+
+		request := func(delay time.Duration) {
+			_, err := intercept(context.Background(), platformclient.RequestOptions{
+				Method: http.MethodGet,
+				Path:   "/services/chatkit/v6/instance/users/alice",
+			}, func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+				time.Sleep(delay)
+				return &http.Response{StatusCode: http.StatusOK}, nil
+			})
+			So(err, ShouldBeNil)
+		}
+
+		Convey("only requests taking longer are reported", func() {
+			request(0)
+			request(20 * time.Millisecond)
+
+			So(len(slow), ShouldEqual, 1)
+			So(slow[0].Method, ShouldEqual, http.MethodGet)
+			So(slow[0].Route, ShouldEqual, "/users/{user_id}")
+			So(slow[0].Status, ShouldEqual, http.StatusOK)
+			So(slow[0].Duration, ShouldBeGreaterThan, 10*time.Millisecond)
+		})
+	})
+}
+
+func TestAuditLog(t *testing.T) {
+	Convey("Given an audit log", t, func() {
+		var records []AuditRecord
+		intercept := auditSURequests(func(ctx context.Context, record AuditRecord) {
+			records = append(records, record)
+		})
+
+		request := func(ctx context.Context, claims string) {
+			token := "header." + base64.RawURLEncoding.EncodeToString([]byte(claims)) + ".signature"
+			_, err := intercept(ctx, platformclient.RequestOptions{
+				Method: http.MethodDelete,
+				Path:   "/services/chatkit/v6/instance/rooms/general",
+				Jwt:    &token,
+			}, func(ctx context.Context, options platformclient.RequestOptions) (*http.Response, error) {
+				return &http.Response{StatusCode: http.StatusNoContent}, nil
+			})
+			So(err, ShouldBeNil)
+		}
+
+		Convey("requests made with an SU token are recorded with their actor", func() {
+			request(WithActor(context.Background(), "admin"), `{"su":true,"sub":"alice"}`)
+			request(context.Background(), `{"sub":"bob"}`)
+
+			So(len(records), ShouldEqual, 1)
+			So(records[0].Actor, ShouldEqual, "admin")
+			So(records[0].Operation, ShouldEqual, "DELETE /rooms/{room_id}")
+			So(records[0].Resources, ShouldResemble, map[string]string{"room_id": "general"})
+			So(records[0].UserID, ShouldEqual, "alice")
+			So(records[0].Status, ShouldEqual, http.StatusNoContent)
+		})
+	})
+}
@@ -0,0 +1,61 @@
+package chatkit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// defaultCompressionMinSize is the size from which request bodies are gzipped when no minimum is
+// given. Smaller bodies gain little from compression.
+const defaultCompressionMinSize = 1 << 10
+
+// compressRequests is an interceptor that gzips request bodies of at least minSize bytes.
+// Responses are left to the HTTP transport, which asks for them to be gzipped and decompresses
+// them before anything reads them, including raw requests and error bodies. Subscriptions are
+// left alone, since their events are streamed.
+func compressRequests(minSize int) common.Interceptor {
+	return func(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+		next common.Invoker,
+	) (*http.Response, error) {
+		if options.Method == common.MethodSubscribe {
+			return next(ctx, options)
+		}
+
+		headers := make(http.Header, len(options.Headers)+1)
+		for name, values := range options.Headers {
+			headers[name] = values
+		}
+
+		if options.Body != nil {
+			body, err := ioutil.ReadAll(options.Body)
+			if closer, ok := options.Body.(io.Closer); ok {
+				closer.Close()
+			}
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read request body: %v", err)
+			}
+
+			if len(body) >= minSize {
+				body, err = common.Compress(body)
+				if err != nil {
+					return nil, err
+				}
+				headers.Set("Content-Encoding", "gzip")
+			}
+
+			options.Body = bytes.NewReader(body)
+		}
+
+		options.Headers = headers
+		return next(ctx, options)
+	}
+}
@@ -0,0 +1,123 @@
+package chatkit
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// gzipTestResponse writes body gzipped when the request accepts it, like Chatkit does.
+func gzipTestResponse(w http.ResponseWriter, r *http.Request, status int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	if body == "" || !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+		return
+	}
+
+	w.Header().Set("Content-Encoding", "gzip")
+	w.WriteHeader(status)
+	writer := gzip.NewWriter(w)
+	writer.Write([]byte(body))
+	writer.Close()
+}
+
+func TestCompressionRawRequestsAreDecompressed(t *testing.T) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gzipTestResponse(w, r, http.StatusOK, `{"id":"alice"}`)
+	}, WithCompression(0))
+	defer server.Close()
+
+	response, err := client.CoreRequest(context.Background(), platformclient.RequestOptions{
+		Method: http.MethodGet,
+		Path:   "/users/alice",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer response.Body.Close()
+
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("Expected no error reading the body, got %v", err)
+	}
+	if string(body) != `{"id":"alice"}` {
+		t.Fatalf("Expected a decompressed body, got %q", body)
+	}
+}
+
+func TestCompressionErrorsKeepTheirInfo(t *testing.T) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gzipTestResponse(w, r, http.StatusNotFound, `{"error":"services/chatkit/not_found/user_not_found"}`)
+	}, WithCompression(0))
+	defer server.Close()
+
+	_, err := client.Users().GetUser(context.Background(), "alice")
+	errorResponse, ok := err.(*platformclient.ErrorResponse)
+	if !ok {
+		t.Fatalf("Expected an ErrorResponse, got %#v", err)
+	}
+
+	info, ok := errorResponse.Info.(map[string]interface{})
+	if !ok || info["error"] != "services/chatkit/not_found/user_not_found" {
+		t.Fatalf("Expected the error info to be decoded, got %#v", errorResponse.Info)
+	}
+}
+
+func TestCompressionDecodesGzippedResponses(t *testing.T) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		gzipTestResponse(w, r, http.StatusOK, `{"id":"alice","name":"Alice"}`)
+	}, WithCompression(0))
+	defer server.Close()
+
+	user, err := client.Users().GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if user.ID != "alice" || user.Name != "Alice" {
+		t.Fatalf("Expected the user to be decoded, got %+v", user)
+	}
+}
+
+func TestCompressionGzipsLargeRequestBodies(t *testing.T) {
+	var encodings []string
+	var bodies [][]byte
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		encoding := r.Header.Get("Content-Encoding")
+		body, _ := ioutil.ReadAll(r.Body)
+		if encoding == "gzip" {
+			reader, err := gzip.NewReader(bytes.NewReader(body))
+			if err != nil {
+				t.Errorf("Expected a gzipped body, got %v", err)
+				return
+			}
+			body, _ = ioutil.ReadAll(reader)
+		}
+		encodings = append(encodings, encoding)
+		bodies = append(bodies, body)
+		gzipTestResponse(w, r, http.StatusNoContent, ``)
+	}, WithCompression(64))
+	defer server.Close()
+
+	ctx := context.Background()
+	short, long := "A", strings.Repeat("Alice", 20)
+	if err := client.Users().UpdateUser(ctx, "alice", UpdateUserOptions{Name: &short}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := client.Users().UpdateUser(ctx, "alice", UpdateUserOptions{Name: &long}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(encodings) != 2 || encodings[0] != "" || encodings[1] != "gzip" {
+		t.Fatalf("Expected only the large body to be gzipped, got %q", encodings)
+	}
+	if !bytes.Contains(bodies[1], []byte(strings.Repeat("Alice", 20))) {
+		t.Fatalf("Expected the large body to arrive intact, got %q", bodies[1])
+	}
+}
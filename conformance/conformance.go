@@ -0,0 +1,482 @@
+// Package conformance exports scenarios of the integration tests of the SDK as a suite that any
+// implementation of chatkit.API can be run against, so that alternative backends, such as
+// chatkittest.FakeServer or proxies in front of Chatkit, can check that they behave like it:
+//
+//	func TestConformance(t *testing.T) {
+//		server := chatkittest.NewFakeServer()
+//		defer server.Close()
+//
+//		client, err := server.NewClient()
+//		if err != nil {
+//			t.Fatal(err)
+//		}
+//
+//		conformance.Run(t, client)
+//	}
+package conformance
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"testing"
+	"time"
+
+	chatkit "github.com/pusher/chatkit-server-go"
+)
+
+var random = rand.New(rand.NewSource(time.Now().UnixNano()))
+
+var letters = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789")
+
+// Run runs the suite against client, as subtests of t. Scenarios create the resources they need
+// with random IDs and delete them afterwards, so the suite can be run against an instance that is
+// in use.
+func Run(t *testing.T, client chatkit.API) {
+	t.Run("Users", func(t *testing.T) { testUsers(t, client) })
+	t.Run("Rooms", func(t *testing.T) { testRooms(t, client) })
+	t.Run("Messages", func(t *testing.T) { testMessages(t, client) })
+	t.Run("Cursors", func(t *testing.T) { testCursors(t, client) })
+	t.Run("Roles", func(t *testing.T) { testRoles(t, client) })
+}
+
+func testUsers(t *testing.T, client chatkit.API) {
+	ctx := context.Background()
+
+	userID := randomString()
+	avatarURL := "https://" + randomString()
+	created, err := client.CreateUserAndGet(ctx, chatkit.CreateUserOptions{
+		ID:         userID,
+		Name:       "conformance-test-user",
+		AvatarURL:  &avatarURL,
+		CustomData: map[string]interface{}{"foo": "hello"},
+	})
+	check(t, err, "create user")
+	deleted := false
+	defer func() {
+		if !deleted {
+			client.DeleteUser(ctx, userID)
+		}
+	}()
+
+	expect(t, created.ID == userID, "created user has ID %q, expected %q", created.ID, userID)
+	expect(t, !created.CreatedAt.IsZero(), "created user has no creation time")
+
+	user, err := client.GetUser(ctx, userID)
+	check(t, err, "get user")
+	expect(t, user.Name == "conformance-test-user", "user has name %q", user.Name)
+	expect(t, user.AvatarURL == avatarURL, "user has avatar URL %q, expected %q", user.AvatarURL, avatarURL)
+	expect(t, user.CustomData["foo"] == "hello", "user has custom data %v", user.CustomData)
+
+	err = client.CreateUser(ctx, chatkit.CreateUserOptions{ID: userID, Name: "duplicate"})
+	expectStatus(t, err, http.StatusConflict, "create existing user")
+
+	newName := randomString()
+	err = client.UpdateUser(ctx, userID, chatkit.UpdateUserOptions{Name: &newName})
+	check(t, err, "update user")
+
+	user, err = client.GetUser(ctx, userID)
+	check(t, err, "get updated user")
+	expect(t, user.Name == newName, "updated user has name %q, expected %q", user.Name, newName)
+	expect(t, user.AvatarURL == avatarURL, "updated user has avatar URL %q, expected %q", user.AvatarURL, avatarURL)
+
+	batchIDs := []string{randomString(), randomString(), randomString()}
+	sort.Strings(batchIDs)
+	batch := make([]chatkit.CreateUserOptions, len(batchIDs))
+	for i, id := range batchIDs {
+		batch[i] = chatkit.CreateUserOptions{ID: id, Name: "conformance-test-user-" + id}
+	}
+	check(t, client.CreateUsers(ctx, batch), "create batch of users")
+	defer deleteUsers(client, batchIDs...)
+
+	users, err := client.GetUsersByID(ctx, batchIDs)
+	check(t, err, "get users by ID")
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	expect(t, len(users) == len(batchIDs), "got %d users by ID, expected %d", len(users), len(batchIDs))
+	for i := range users {
+		expect(t, users[i].ID == batchIDs[i], "got user %q by ID, expected %q", users[i].ID, batchIDs[i])
+		expect(t, users[i].Name == batch[i].Name, "user has name %q, expected %q", users[i].Name, batch[i].Name)
+	}
+
+	check(t, client.DeleteUser(ctx, userID), "delete user")
+	deleted = true
+
+	_, err = client.GetUser(ctx, userID)
+	expectErrorType(t, err, http.StatusNotFound, "services/chatkit/not_found/user_not_found", "get deleted user")
+}
+
+func testRooms(t *testing.T, client chatkit.API) {
+	ctx := context.Background()
+
+	aliceID, bobID, carolID := createUser(t, client), createUser(t, client), createUser(t, client)
+	defer deleteUsers(client, aliceID, bobID, carolID)
+
+	roomID := randomString()
+	roomName := randomString()
+	created, err := client.CreateRoom(ctx, chatkit.CreateRoomOptions{
+		ID:         &roomID,
+		Name:       roomName,
+		Private:    true,
+		UserIDs:    []string{bobID},
+		CreatorID:  aliceID,
+		CustomData: map[string]interface{}{"foo": "bar"},
+	})
+	check(t, err, "create room")
+	deleted := false
+	defer func() {
+		if !deleted {
+			client.DeleteRoom(ctx, roomID)
+		}
+	}()
+
+	expect(t, created.ID == roomID, "created room has ID %q, expected %q", created.ID, roomID)
+	expect(t, created.CreatedByID == aliceID, "created room was created by %q, expected %q", created.CreatedByID, aliceID)
+	expectMembers(t, created, aliceID, bobID)
+
+	_, err = client.CreateRoom(ctx, chatkit.CreateRoomOptions{ID: &roomID, Name: "duplicate", CreatorID: aliceID})
+	expectStatus(t, err, http.StatusConflict, "create existing room")
+
+	room, err := client.GetRoom(ctx, roomID)
+	check(t, err, "get room")
+	expect(t, room.Name == roomName, "room has name %q, expected %q", room.Name, roomName)
+	expect(t, room.Private, "room isn't private")
+	expectMembers(t, room, aliceID, bobID)
+
+	newName := randomString()
+	public := false
+	err = client.UpdateRoom(ctx, roomID, chatkit.UpdateRoomOptions{Name: &newName, Private: &public})
+	check(t, err, "update room")
+
+	room, err = client.GetRoom(ctx, roomID)
+	check(t, err, "get updated room")
+	expect(t, room.Name == newName, "updated room has name %q, expected %q", room.Name, newName)
+	expect(t, !room.Private, "updated room is private")
+
+	check(t, client.AddUsersToRoom(ctx, roomID, []string{carolID}), "add users to room")
+	check(t, client.RemoveUsersFromRoom(ctx, roomID, []string{bobID}), "remove users from room")
+
+	room, err = client.GetRoom(ctx, roomID)
+	check(t, err, "get room after changing its members")
+	expectMembers(t, room, aliceID, carolID)
+
+	rooms, err := client.GetUserRooms(ctx, carolID)
+	check(t, err, "get user rooms")
+	expect(t, len(rooms) == 1 && rooms[0].ID == roomID, "user is in rooms %v, expected [%s]", roomIDs(rooms), roomID)
+
+	rooms, err = client.GetUserRooms(ctx, bobID)
+	check(t, err, "get rooms of removed user")
+	expect(t, len(rooms) == 0, "removed user is in rooms %v", roomIDs(rooms))
+
+	check(t, client.DeleteRoom(ctx, roomID), "delete room")
+	deleted = true
+
+	_, err = client.GetRoom(ctx, roomID)
+	expectStatus(t, err, http.StatusNotFound, "get deleted room")
+}
+
+func testMessages(t *testing.T, client chatkit.API) {
+	ctx := context.Background()
+
+	userID := createUser(t, client)
+	defer deleteUsers(client, userID)
+
+	roomID := createRoom(t, client, userID)
+	defer client.DeleteRoom(ctx, roomID)
+
+	messageIDs := make([]uint, 4)
+	for i, text := range []string{"one", "two"} {
+		id, err := client.SendMessage(ctx, chatkit.SendMessageOptions{RoomID: roomID, Text: text, SenderID: userID})
+		check(t, err, "send message")
+		messageIDs[i] = id
+	}
+	for i, text := range []string{"three", "four"} {
+		id, err := client.SendMultipartMessage(ctx, chatkit.SendMultipartMessageOptions{
+			RoomID:   roomID,
+			SenderID: userID,
+			Parts:    []chatkit.NewPart{chatkit.NewInlinePart{Type: "text/plain", Content: text}},
+		})
+		check(t, err, "send multipart message")
+		messageIDs[i+2] = id
+	}
+
+	for i := 1; i < len(messageIDs); i++ {
+		expect(t, messageIDs[i] > messageIDs[i-1], "message IDs %v aren't increasing", messageIDs)
+	}
+
+	limit := uint(2)
+	page, err := client.GetRoomMessages(ctx, roomID, chatkit.GetRoomMessagesOptions{Limit: &limit})
+	check(t, err, "get first page of messages")
+	expectMessages(t, page, []uint{messageIDs[3], messageIDs[2]}, []string{"four", "three"})
+
+	page, err = client.GetRoomMessages(ctx, roomID, chatkit.GetRoomMessagesOptions{InitialID: &messageIDs[2]})
+	check(t, err, "get second page of messages")
+	expectMessages(t, page, []uint{messageIDs[1], messageIDs[0]}, []string{"two", "one"})
+
+	message, err := client.FetchMultipartMessage(ctx, chatkit.FetchMultipartMessageOptions{
+		RoomID:    roomID,
+		MessageID: messageIDs[2],
+	})
+	check(t, err, "fetch multipart message")
+	expect(t, message.ID == messageIDs[2], "fetched message %d, expected %d", message.ID, messageIDs[2])
+	expect(t, message.UserID == userID, "message was sent by %q, expected %q", message.UserID, userID)
+	expect(
+		t,
+		len(message.Parts) == 1 && message.Parts[0].Type == "text/plain" &&
+			message.Parts[0].Content != nil && *message.Parts[0].Content == "three",
+		"message has parts %v, expected a single text/plain part \"three\"",
+		message.Parts,
+	)
+
+	check(t, client.DeleteMessage(ctx, chatkit.DeleteMessageOptions{
+		RoomID:    roomID,
+		MessageID: messageIDs[2],
+	}), "delete message")
+
+	_, err = client.FetchMultipartMessage(ctx, chatkit.FetchMultipartMessageOptions{
+		RoomID:    roomID,
+		MessageID: messageIDs[2],
+	})
+	expectStatus(t, err, http.StatusNotFound, "fetch deleted message")
+
+	page, err = client.GetRoomMessages(ctx, roomID, chatkit.GetRoomMessagesOptions{})
+	check(t, err, "get messages after deleting one")
+	expectMessages(
+		t,
+		page,
+		[]uint{messageIDs[3], messageIDs[1], messageIDs[0]},
+		[]string{"four", "two", "one"},
+	)
+}
+
+func testCursors(t *testing.T, client chatkit.API) {
+	ctx := context.Background()
+
+	userID := createUser(t, client)
+	defer deleteUsers(client, userID)
+
+	roomID := createRoom(t, client, userID)
+	defer client.DeleteRoom(ctx, roomID)
+
+	messageID, err := client.SendMessage(ctx, chatkit.SendMessageOptions{
+		RoomID:   roomID,
+		Text:     "Hello!",
+		SenderID: userID,
+	})
+	check(t, err, "send message")
+
+	check(t, client.SetReadCursor(ctx, userID, roomID, messageID), "set read cursor")
+
+	cursor, err := client.GetReadCursor(ctx, userID, roomID)
+	check(t, err, "get read cursor")
+	expectCursor(t, cursor, userID, roomID, messageID)
+
+	cursors, err := client.GetUserReadCursors(ctx, userID)
+	check(t, err, "get user read cursors")
+	expect(t, len(cursors) == 1, "user has %d read cursors, expected 1", len(cursors))
+	if len(cursors) == 1 {
+		expectCursor(t, cursors[0], userID, roomID, messageID)
+	}
+
+	cursors, err = client.GetReadCursorsForRoom(ctx, roomID)
+	check(t, err, "get room read cursors")
+	expect(t, len(cursors) == 1, "room has %d read cursors, expected 1", len(cursors))
+	if len(cursors) == 1 {
+		expectCursor(t, cursors[0], userID, roomID, messageID)
+	}
+}
+
+func testRoles(t *testing.T, client chatkit.API) {
+	ctx := context.Background()
+
+	userID := createUser(t, client)
+	defer deleteUsers(client, userID)
+
+	roomID := createRoom(t, client, userID)
+	defer client.DeleteRoom(ctx, roomID)
+
+	globalRoleName, roomRoleName := randomString(), randomString()
+	check(t, client.CreateGlobalRole(ctx, chatkit.CreateRoleOptions{
+		Name:        globalRoleName,
+		Permissions: []string{"room:create"},
+	}), "create global role")
+	check(t, client.CreateRoomRole(ctx, chatkit.CreateRoleOptions{
+		Name:        roomRoleName,
+		Permissions: []string{"message:create"},
+	}), "create room role")
+
+	err := client.CreateGlobalRole(ctx, chatkit.CreateRoleOptions{
+		Name:        globalRoleName,
+		Permissions: []string{"room:create"},
+	})
+	expectStatus(t, err, http.StatusConflict, "create existing role")
+
+	check(t, client.UpdatePermissionsForGlobalRole(ctx, globalRoleName, chatkit.UpdateRolePermissionsOptions{
+		PermissionsToAdd: []string{"room:join"},
+	}), "update permissions of global role")
+
+	permissions, err := client.GetPermissionsForGlobalRole(ctx, globalRoleName)
+	check(t, err, "get permissions of global role")
+	sort.Strings(permissions)
+	expect(
+		t,
+		len(permissions) == 2 && permissions[0] == "room:create" && permissions[1] == "room:join",
+		"global role has permissions %v, expected [room:create room:join]",
+		permissions,
+	)
+
+	check(t, client.AssignGlobalRoleToUser(ctx, userID, globalRoleName), "assign global role")
+	check(t, client.AssignRoomRoleToUser(ctx, userID, roomID, roomRoleName), "assign room role")
+
+	roles, err := client.GetUserRoles(ctx, userID)
+	check(t, err, "get user roles")
+	expect(t, len(roles) == 2, "user has %d roles, expected 2", len(roles))
+	for _, role := range roles {
+		switch role.Name {
+		case globalRoleName:
+			expect(t, role.Scope == chatkit.RoleScopeGlobal, "global role has scope %q", role.Scope)
+		case roomRoleName:
+			expect(t, role.Scope == chatkit.RoleScopeRoom, "room role has scope %q", role.Scope)
+			expect(t, role.RoomID == roomID, "room role is assigned in room %q, expected %q", role.RoomID, roomID)
+		default:
+			t.Errorf("user has unexpected role %q", role.Name)
+		}
+	}
+
+	check(t, client.RemoveGlobalRoleForUser(ctx, userID), "remove global role")
+	check(t, client.RemoveRoomRoleForUser(ctx, userID, roomID), "remove room role")
+
+	roles, err = client.GetUserRoles(ctx, userID)
+	check(t, err, "get user roles after removing them")
+	expect(t, len(roles) == 0, "user has %d roles after removing them", len(roles))
+
+	check(t, client.DeleteGlobalRole(ctx, globalRoleName), "delete global role")
+	check(t, client.DeleteRoomRole(ctx, roomRoleName), "delete room role")
+
+	_, err = client.GetPermissionsForGlobalRole(ctx, globalRoleName)
+	expectStatus(t, err, http.StatusNotFound, "get permissions of deleted role")
+}
+
+// Helpers
+
+// randomString generates a random string of length 10.
+func randomString() string {
+	b := make([]rune, 10)
+	for i := range b {
+		b[i] = letters[random.Intn(len(letters))]
+	}
+
+	return string(b)
+}
+
+func createUser(t *testing.T, client chatkit.API) string {
+	userID := randomString()
+	err := client.CreateUser(context.Background(), chatkit.CreateUserOptions{
+		ID:   userID,
+		Name: "conformance-test-user",
+	})
+	check(t, err, "create user")
+
+	return userID
+}
+
+func deleteUsers(client chatkit.API, userIDs ...string) {
+	for _, userID := range userIDs {
+		client.DeleteUser(context.Background(), userID)
+	}
+}
+
+func createRoom(t *testing.T, client chatkit.API, creatorID string) string {
+	room, err := client.CreateRoom(context.Background(), chatkit.CreateRoomOptions{
+		Name:      randomString(),
+		CreatorID: creatorID,
+	})
+	check(t, err, "create room")
+
+	return room.ID
+}
+
+func roomIDs(rooms []chatkit.Room) []string {
+	ids := make([]string, len(rooms))
+	for i, room := range rooms {
+		ids[i] = room.ID
+	}
+
+	return ids
+}
+
+// check fails the scenario if err isn't nil, as the steps after action depend on it.
+func check(t *testing.T, err error, action string) {
+	if err != nil {
+		t.Fatalf("Failed to %s: %v", action, err)
+	}
+}
+
+func expect(t *testing.T, ok bool, format string, args ...interface{}) {
+	if !ok {
+		t.Errorf(format, args...)
+	}
+}
+
+// expectStatus checks that err is an error response from Chatkit with the given status.
+func expectStatus(t *testing.T, err error, status int, action string) {
+	errorResponse, ok := err.(*chatkit.ErrorResponse)
+	if !ok || errorResponse.Status != status {
+		t.Errorf("Expected %s to fail with status %d, got %v", action, status, err)
+	}
+}
+
+// expectErrorType checks that err is an error response from Chatkit with the given status and
+// error type.
+func expectErrorType(t *testing.T, err error, status int, errorType string, action string) {
+	expectStatus(t, err, status, action)
+
+	errorResponse, ok := err.(*chatkit.ErrorResponse)
+	if !ok {
+		return
+	}
+
+	info, _ := errorResponse.Info.(map[string]interface{})
+	if info["error"] != errorType {
+		t.Errorf("Expected %s to fail with error %s, got %v", action, errorType, info["error"])
+	}
+}
+
+func expectMembers(t *testing.T, room chatkit.Room, userIDs ...string) {
+	members := append([]string(nil), room.MemberUserIDs...)
+	sort.Strings(members)
+	expected := append([]string(nil), userIDs...)
+	sort.Strings(expected)
+
+	if fmt.Sprint(members) != fmt.Sprint(expected) {
+		t.Errorf("Room %s has members %v, expected %v", room.ID, members, expected)
+	}
+}
+
+func expectMessages(t *testing.T, messages []chatkit.Message, ids []uint, texts []string) {
+	gotIDs := make([]uint, len(messages))
+	gotTexts := make([]string, len(messages))
+	for i, message := range messages {
+		gotIDs[i], gotTexts[i] = message.ID, message.Text
+	}
+
+	if fmt.Sprint(gotIDs, gotTexts) != fmt.Sprint(ids, texts) {
+		t.Errorf("Got messages %v %q, expected %v %q", gotIDs, gotTexts, ids, texts)
+	}
+}
+
+func expectCursor(t *testing.T, cursor chatkit.Cursor, userID string, roomID string, position uint) {
+	if cursor.CursorType != chatkit.CursorTypeRead ||
+		cursor.UserID != userID ||
+		cursor.RoomID != roomID ||
+		cursor.Position != position {
+		t.Errorf(
+			"Got cursor %+v, expected a read cursor of user %s in room %s at %d",
+			cursor,
+			userID,
+			roomID,
+			position,
+		)
+	}
+}
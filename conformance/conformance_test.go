@@ -0,0 +1,20 @@
+package conformance_test
+
+import (
+	"testing"
+
+	"github.com/pusher/chatkit-server-go/chatkittest"
+	"github.com/pusher/chatkit-server-go/conformance"
+)
+
+func TestFakeServerConformance(t *testing.T) {
+	server := chatkittest.NewFakeServer()
+	defer server.Close()
+
+	client, err := server.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conformance.Run(t, client)
+}
@@ -0,0 +1,272 @@
+package chatkit
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// maxUnreadCount is the number of unread messages above which GetUnreadCounts stops counting.
+const maxUnreadCount = 99
+
+// UnreadCount describes the messages in a room a user has not read yet.
+type UnreadCount struct {
+	// Number of messages after the user's read cursor, up to 99.
+	Count uint
+	// Whether there are more unread messages than Count, e.g. to display "99+".
+	Capped bool
+	// ID of the newest message in the room, or 0 if the room has no messages.
+	LatestMessageID uint
+	// Position of the user's read cursor, or 0 if they have not set one, in which case every
+	// message in the room is unread.
+	CursorPosition uint
+}
+
+// GetUnreadCounts returns the number of unread messages in each of the rooms a user is a member
+// of, keyed by room ID, computed from the user's read cursors.
+// Rooms whose newest message has been read cost one request; the others are counted by fetching
+// the messages after the cursor, up to 99 of them. Rooms are counted concurrently. If some rooms
+// could not be counted the error is a *BatchError, keyed by room ID, and those rooms are missing
+// from the map.
+func (cc CursorsClient) GetUnreadCounts(ctx context.Context, userID string) (map[string]UnreadCount, error) {
+	c := cc.client
+	rooms, err := c.Rooms().GetUserRooms(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursors, err := cc.GetUserReadCursors(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[string]uint, len(cursors))
+	for _, cursor := range cursors {
+		positions[cursor.RoomID] = cursor.Position
+	}
+
+	roomIDs := make([]string, len(rooms))
+	for i, room := range rooms {
+		roomIDs[i] = room.ID
+	}
+
+	latest, err := c.Messages().FetchLatestMessagesForRooms(ctx, roomIDs, 1)
+	batchErr, _ := err.(*BatchError)
+	if err != nil && batchErr == nil {
+		return nil, err
+	}
+	if batchErr == nil {
+		batchErr = &BatchError{Errors: map[string]error{}}
+	}
+
+	var (
+		mu     sync.Mutex
+		counts = make(map[string]UnreadCount, len(rooms))
+	)
+
+	unreadRoomIDs := []string{}
+	for roomID, messages := range latest {
+		count := UnreadCount{CursorPosition: positions[roomID]}
+		if len(messages) > 0 {
+			count.LatestMessageID = messages[0].ID
+		}
+
+		if count.LatestMessageID > count.CursorPosition {
+			unreadRoomIDs = append(unreadRoomIDs, roomID)
+		}
+		counts[roomID] = count
+	}
+
+	err = forEachConcurrently(ctx, unreadRoomIDs, c.concurrencyFor(0), func(ctx context.Context, roomID string) error {
+		mu.Lock()
+		count := counts[roomID]
+		mu.Unlock()
+
+		direction := "newer"
+		limit := uint(maxUnreadCount + 1)
+		messages, err := c.Messages().FetchMultipartMessages(ctx, roomID, FetchMultipartMessagesOptions{
+			Direction: &direction,
+			InitialID: &count.CursorPosition,
+			Limit:     &limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		count.Count = uint(len(messages))
+		if count.Count > maxUnreadCount {
+			count.Count, count.Capped = maxUnreadCount, true
+		}
+
+		mu.Lock()
+		counts[roomID] = count
+		mu.Unlock()
+		return nil
+	})
+	if countErr, ok := err.(*BatchError); ok {
+		for roomID, err := range countErr.Errors {
+			batchErr.Errors[roomID] = err
+			delete(counts, roomID)
+		}
+	}
+
+	if len(batchErr.Errors) > 0 {
+		return counts, batchErr
+	}
+
+	return counts, nil
+}
+
+// defaultCursorsPageSize is the number of cursors requested per page when iterating over a room's
+// read cursors.
+const defaultCursorsPageSize = 100
+
+// IterateRoomReadCursorsOptions contains parameters to pass when iterating over a room's read
+// cursors.
+type IterateRoomReadCursorsOptions struct {
+	PageSize uint // Number of cursors fetched per request. Defaults to 100
+}
+
+// CursorsIterator pages through the read cursors of a room, ordered by user ID.
+// Pages are only requested once the previous one has been consumed, so rooms with very many
+// members can be processed without holding all of their cursors in memory.
+//
+//	it := client.IterateRoomReadCursors(ctx, roomID, IterateRoomReadCursorsOptions{})
+//	for it.Next() {
+//		cursor := it.Cursor()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type CursorsIterator struct {
+	ctx      context.Context
+	client   *Client
+	roomID   string
+	pageSize uint
+
+	fromUserID string
+	page       []Cursor
+	current    Cursor
+	lastPage   bool
+	err        error
+}
+
+// IterateRoomReadCursors returns an iterator over the read cursors of a room.
+func (cc CursorsClient) IterateRoomReadCursors(
+	ctx context.Context,
+	roomID string,
+	options IterateRoomReadCursorsOptions,
+) *CursorsIterator {
+	pageSize := options.PageSize
+	if pageSize == 0 {
+		pageSize = defaultCursorsPageSize
+	}
+
+	return &CursorsIterator{
+		ctx:      ctx,
+		client:   cc.client,
+		roomID:   roomID,
+		pageSize: pageSize,
+	}
+}
+
+// Next advances the iterator to the next cursor.
+// It returns false when there are no more cursors or an error occurred.
+func (it *CursorsIterator) Next() bool {
+	if len(it.page) == 0 && !it.lastPage && it.err == nil {
+		it.fetchPage()
+	}
+
+	if len(it.page) == 0 {
+		return false
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+
+	return true
+}
+
+// Cursor returns the cursor the iterator currently points at.
+func (it *CursorsIterator) Cursor() Cursor {
+	return it.current
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *CursorsIterator) Err() error {
+	return it.err
+}
+
+// fetchPage requests the page of cursors following the last cursor returned.
+// A cursors service that doesn't paginate returns every cursor at once, which is then taken to
+// be the last page.
+func (it *CursorsIterator) fetchPage() {
+	cursors, err := it.client.Cursors().GetReadCursorsForRoomPage(it.ctx, it.roomID, GetReadCursorsForRoomOptions{
+		FromUserID: it.fromUserID,
+		Limit:      it.pageSize,
+	})
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	if uint(len(cursors)) != it.pageSize {
+		it.lastPage = true
+	}
+
+	sort.Slice(cursors, func(i, j int) bool {
+		return cursors[i].UserID < cursors[j].UserID
+	})
+
+	for _, cursor := range cursors {
+		if it.fromUserID == "" || cursor.UserID > it.fromUserID {
+			it.page = append(it.page, cursor)
+		}
+	}
+
+	if len(it.page) == 0 {
+		it.lastPage = true
+		return
+	}
+
+	it.fromUserID = it.page[len(it.page)-1].UserID
+}
+
+// MessageReadBy describes which members of a room have read a message.
+type MessageReadBy struct {
+	ReadBy   []string // Members whose read cursor is at or after the message, sorted by ID
+	UnreadBy []string // Members who have not read the message yet, sorted by ID
+}
+
+// GetMessageReadBy returns which members of a room have read a message, according to their read
+// cursors, e.g. to display "Seen by N". The sender of the message is included like any other
+// member. Cursors of users who are no longer members of the room are ignored.
+func (cc CursorsClient) GetMessageReadBy(ctx context.Context, roomID string, messageID uint) (MessageReadBy, error) {
+	room, err := cc.client.Rooms().GetRoom(ctx, roomID)
+	if err != nil {
+		return MessageReadBy{}, err
+	}
+
+	positions := make(map[string]uint, len(room.MemberUserIDs))
+	it := cc.IterateRoomReadCursors(ctx, roomID, IterateRoomReadCursorsOptions{})
+	for it.Next() {
+		positions[it.Cursor().UserID] = it.Cursor().Position
+	}
+	if err := it.Err(); err != nil {
+		return MessageReadBy{}, err
+	}
+
+	readBy := MessageReadBy{ReadBy: []string{}, UnreadBy: []string{}}
+	for _, userID := range uniqueStrings(room.MemberUserIDs) {
+		if position, ok := positions[userID]; ok && position >= messageID {
+			readBy.ReadBy = append(readBy.ReadBy, userID)
+		} else {
+			readBy.UnreadBy = append(readBy.UnreadBy, userID)
+		}
+	}
+
+	sort.Strings(readBy.ReadBy)
+	sort.Strings(readBy.UnreadBy)
+
+	return readBy, nil
+}
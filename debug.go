@@ -0,0 +1,199 @@
+package chatkit
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// maxDumpedBodySize is the size above which request bodies are truncated in dumps.
+const maxDumpedBodySize = 64 << 10
+
+const redacted = "[REDACTED]"
+
+// Query parameters that may carry tokens, which are redacted in dumps.
+var redactedQueryParams = map[string]bool{"jwt": true, "token": true, "access_token": true}
+
+// httpDumper writes the requests made to Chatkit and their responses to a writer, if any.
+type httpDumper struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (d *httpDumper) writer() io.Writer {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.w
+}
+
+func (d *httpDumper) setWriter(w io.Writer) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.w = w
+}
+
+// write writes a dump at once, so that the dumps of concurrent requests aren't interleaved.
+func (d *httpDumper) write(dump []byte) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.w != nil {
+		d.w.Write(dump)
+	}
+}
+
+// intercept dumps a request and its response, unless dumping is disabled.
+func (d *httpDumper) intercept(
+	ctx context.Context,
+	options platformclient.RequestOptions,
+	next common.Invoker,
+) (*http.Response, error) {
+	if d.writer() == nil {
+		return next(ctx, options)
+	}
+
+	var dump bytes.Buffer
+	fmt.Fprintf(&dump, "--> %s %s\n", options.Method, redactedPath(options))
+
+	headers := http.Header{}
+	for name, values := range options.Headers {
+		headers[name] = values
+	}
+	if options.Jwt != nil {
+		headers.Set("Authorization", "Bearer")
+	}
+	writeRedactedHeaders(&dump, headers)
+
+	if options.Body != nil {
+		body, err := ioutil.ReadAll(options.Body)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to read request body: %v", err)
+		}
+		options.Body = bytes.NewReader(body)
+
+		dump.WriteString("\n")
+		if len(body) > maxDumpedBodySize {
+			dump.Write(body[:maxDumpedBodySize])
+			fmt.Fprintf(&dump, "\n[%d more bytes]", len(body)-maxDumpedBodySize)
+		} else {
+			dump.Write(body)
+		}
+		dump.WriteString("\n")
+	}
+	d.write(dump.Bytes())
+
+	start := time.Now()
+	response, err := next(ctx, options)
+	elapsed := time.Since(start)
+
+	dump.Reset()
+	if response != nil {
+		// Subscriptions stream their bodies for as long as they are open.
+		withBody := options.Method != common.MethodSubscribe
+
+		responseDump, dumpErr := httputil.DumpResponse(response, withBody)
+		if dumpErr != nil {
+			fmt.Fprintf(&dump, "<-- %s %s: failed to dump response: %v\n", options.Method, redactedPath(options), dumpErr)
+		} else {
+			fmt.Fprintf(&dump, "<-- %s %s (%s)\n", options.Method, redactedPath(options), elapsed)
+			dump.Write(redactResponseDump(responseDump))
+			dump.WriteString("\n")
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(&dump, "<-- %s %s: %v (%s)\n", options.Method, redactedPath(options), err, elapsed)
+	}
+	d.write(dump.Bytes())
+
+	return response, err
+}
+
+// redactedPath returns the path and query of a request, with the values of the query
+// parameters that may carry tokens redacted.
+func redactedPath(options platformclient.RequestOptions) string {
+	path, query := options.Path, url.Values{}
+	if i := strings.Index(path, "?"); i >= 0 {
+		query, _ = url.ParseQuery(path[i+1:])
+		path = path[:i]
+	}
+
+	if options.QueryParams != nil {
+		for name, values := range *options.QueryParams {
+			query[name] = append(query[name], values...)
+		}
+	}
+
+	if len(query) == 0 {
+		return path
+	}
+
+	for name := range query {
+		if redactedQueryParams[strings.ToLower(name)] {
+			query.Set(name, redacted)
+		}
+	}
+
+	return path + "?" + query.Encode()
+}
+
+func writeRedactedHeaders(w io.Writer, headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range headers[name] {
+			if isRedactedHeader(name) {
+				value = redacted
+			}
+			fmt.Fprintf(w, "%s: %s\n", name, value)
+		}
+	}
+}
+
+// redactResponseDump redacts the values of headers that may carry tokens in a response dump.
+func redactResponseDump(dump []byte) []byte {
+	lines := bytes.Split(dump, []byte("\r\n"))
+	for i, line := range lines {
+		if len(line) == 0 {
+			break
+		}
+
+		if colon := bytes.IndexByte(line, ':'); colon > 0 && isRedactedHeader(string(line[:colon])) {
+			lines[i] = append(line[:colon:colon], ": "+redacted...)
+		}
+	}
+
+	return bytes.Join(lines, []byte("\n"))
+}
+
+func isRedactedHeader(name string) bool {
+	switch http.CanonicalHeaderKey(name) {
+	case "Authorization", "Cookie", "Set-Cookie":
+		return true
+	default:
+		return false
+	}
+}
+
+// SetDebugHTTP starts writing the requests made to Chatkit and their responses to w, with their
+// tokens redacted, or stops if w is nil. See WithDebugHTTP.
+func (c *Client) SetDebugHTTP(w io.Writer) {
+	c.httpDumper.setWriter(w)
+}
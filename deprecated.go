@@ -0,0 +1,936 @@
+package chatkit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/core"
+	"github.com/pusher/pusher-platform-go/auth"
+)
+
+// GetUser calls Users().GetUser.
+//
+// Deprecated: use c.Users().GetUser instead.
+func (c *Client) GetUser(ctx context.Context, userID string) (User, error) {
+	return c.Users().GetUser(ctx, userID)
+}
+
+// GetUsers calls Users().GetUsers.
+//
+// Deprecated: use c.Users().GetUsers instead.
+func (c *Client) GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error) {
+	return c.Users().GetUsers(ctx, options)
+}
+
+// GetUsersByID calls Users().GetUsersByID.
+//
+// Deprecated: use c.Users().GetUsersByID instead.
+func (c *Client) GetUsersByID(ctx context.Context, userIDs []string) ([]User, error) {
+	return c.Users().GetUsersByID(ctx, userIDs)
+}
+
+// CreateUser calls Users().CreateUser.
+//
+// Deprecated: use c.Users().CreateUser instead.
+func (c *Client) CreateUser(ctx context.Context, options CreateUserOptions) error {
+	return c.Users().CreateUser(ctx, options)
+}
+
+// CreateUserAndGet calls Users().CreateUserAndGet.
+//
+// Deprecated: use c.Users().CreateUserAndGet instead.
+func (c *Client) CreateUserAndGet(ctx context.Context, options CreateUserOptions) (User, error) {
+	return c.Users().CreateUserAndGet(ctx, options)
+}
+
+// CreateUsers calls Users().CreateUsers.
+//
+// Deprecated: use c.Users().CreateUsers instead.
+func (c *Client) CreateUsers(ctx context.Context, users []CreateUserOptions) error {
+	return c.Users().CreateUsers(ctx, users)
+}
+
+// UpdateUser calls Users().UpdateUser.
+//
+// Deprecated: use c.Users().UpdateUser instead.
+func (c *Client) UpdateUser(ctx context.Context, userID string, options UpdateUserOptions) error {
+	return c.Users().UpdateUser(ctx, userID, options)
+}
+
+// DeleteUser calls Users().DeleteUser.
+//
+// Deprecated: use c.Users().DeleteUser instead.
+func (c *Client) DeleteUser(ctx context.Context, userID string) error {
+	return c.Users().DeleteUser(ctx, userID)
+}
+
+// IterateUsers calls Users().IterateUsers.
+//
+// Deprecated: use c.Users().IterateUsers instead.
+func (c *Client) IterateUsers(ctx context.Context, options IterateUsersOptions) *UsersIterator {
+	return c.Users().IterateUsers(ctx, options)
+}
+
+// SearchUsers calls Users().SearchUsers.
+//
+// Deprecated: use c.Users().SearchUsers instead.
+func (c *Client) SearchUsers(
+	ctx context.Context,
+	query string,
+	options SearchUsersOptions,
+) *UsersIterator {
+	return c.Users().SearchUsers(ctx, query, options)
+}
+
+// UpdateUsers calls Users().UpdateUsers.
+//
+// Deprecated: use c.Users().UpdateUsers instead.
+func (c *Client) UpdateUsers(
+	ctx context.Context,
+	updates map[string]UpdateUserOptions,
+	options UpdateUsersOptions,
+) *BatchResult {
+	return c.Users().UpdateUsers(ctx, updates, options)
+}
+
+// RenameUser calls Users().RenameUser.
+//
+// Deprecated: use c.Users().RenameUser instead.
+func (c *Client) RenameUser(ctx context.Context, userID string, options RenameUserOptions) error {
+	return c.Users().RenameUser(ctx, userID, options)
+}
+
+// GetRoom calls Rooms().GetRoom.
+//
+// Deprecated: use c.Rooms().GetRoom instead.
+func (c *Client) GetRoom(ctx context.Context, roomID string) (Room, error) {
+	return c.Rooms().GetRoom(ctx, roomID)
+}
+
+// GetRooms calls Rooms().GetRooms.
+//
+// Deprecated: use c.Rooms().GetRooms instead.
+func (c *Client) GetRooms(ctx context.Context, options GetRoomsOptions) ([]core.RoomWithoutMembers, error) {
+	return c.Rooms().GetRooms(ctx, options)
+}
+
+// GetUserRooms calls Rooms().GetUserRooms.
+//
+// Deprecated: use c.Rooms().GetUserRooms instead.
+func (c *Client) GetUserRooms(ctx context.Context, userID string) ([]Room, error) {
+	return c.Rooms().GetUserRooms(ctx, userID)
+}
+
+// GetUserJoinableRooms calls Rooms().GetUserJoinableRooms.
+//
+// Deprecated: use c.Rooms().GetUserJoinableRooms instead.
+func (c *Client) GetUserJoinableRooms(ctx context.Context, userID string) ([]Room, error) {
+	return c.Rooms().GetUserJoinableRooms(ctx, userID)
+}
+
+// CreateRoom calls Rooms().CreateRoom.
+//
+// Deprecated: use c.Rooms().CreateRoom instead.
+func (c *Client) CreateRoom(ctx context.Context, options CreateRoomOptions) (Room, error) {
+	return c.Rooms().CreateRoom(ctx, options)
+}
+
+// UpdateRoom calls Rooms().UpdateRoom.
+//
+// Deprecated: use c.Rooms().UpdateRoom instead.
+func (c *Client) UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) error {
+	return c.Rooms().UpdateRoom(ctx, roomID, options)
+}
+
+// UpdateRoomAndGet calls Rooms().UpdateRoomAndGet.
+//
+// Deprecated: use c.Rooms().UpdateRoomAndGet instead.
+func (c *Client) UpdateRoomAndGet(ctx context.Context, roomID string, options UpdateRoomOptions) (Room, error) {
+	return c.Rooms().UpdateRoomAndGet(ctx, roomID, options)
+}
+
+// DeleteRoom calls Rooms().DeleteRoom.
+//
+// Deprecated: use c.Rooms().DeleteRoom instead.
+func (c *Client) DeleteRoom(ctx context.Context, roomID string) error {
+	return c.Rooms().DeleteRoom(ctx, roomID)
+}
+
+// AsyncDeleteRoom calls Rooms().AsyncDeleteRoom.
+//
+// Deprecated: use c.Rooms().AsyncDeleteRoom instead.
+func (c *Client) AsyncDeleteRoom(ctx context.Context, roomID string) (string, error) {
+	return c.Rooms().AsyncDeleteRoom(ctx, roomID)
+}
+
+// GetDeleteStatus calls Rooms().GetDeleteStatus.
+//
+// Deprecated: use c.Rooms().GetDeleteStatus instead.
+func (c *Client) GetDeleteStatus(ctx context.Context, jobID string) (DeleteStatus, error) {
+	return c.Rooms().GetDeleteStatus(ctx, jobID)
+}
+
+// AddUsersToRoom calls Rooms().AddUsersToRoom.
+//
+// Deprecated: use c.Rooms().AddUsersToRoom instead.
+func (c *Client) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
+	return c.Rooms().AddUsersToRoom(ctx, roomID, userIDs)
+}
+
+// RemoveUsersFromRoom calls Rooms().RemoveUsersFromRoom.
+//
+// Deprecated: use c.Rooms().RemoveUsersFromRoom instead.
+func (c *Client) RemoveUsersFromRoom(ctx context.Context, roomID string, userIDs []string) error {
+	return c.Rooms().RemoveUsersFromRoom(ctx, roomID, userIDs)
+}
+
+// JoinRoom calls Rooms().JoinRoom.
+//
+// Deprecated: use c.Rooms().JoinRoom instead.
+func (c *Client) JoinRoom(ctx context.Context, roomID string, userID string) (Room, error) {
+	return c.Rooms().JoinRoom(ctx, roomID, userID)
+}
+
+// LeaveRoom calls Rooms().LeaveRoom.
+//
+// Deprecated: use c.Rooms().LeaveRoom instead.
+func (c *Client) LeaveRoom(ctx context.Context, roomID string, userID string) error {
+	return c.Rooms().LeaveRoom(ctx, roomID, userID)
+}
+
+// TransferRoomOwnership calls Rooms().TransferRoomOwnership.
+//
+// Deprecated: use c.Rooms().TransferRoomOwnership instead.
+func (c *Client) TransferRoomOwnership(
+	ctx context.Context,
+	roomID string,
+	newOwnerID string,
+	options TransferRoomOwnershipOptions,
+) error {
+	return c.Rooms().TransferRoomOwnership(ctx, roomID, newOwnerID, options)
+}
+
+// IterateRooms calls Rooms().IterateRooms.
+//
+// Deprecated: use c.Rooms().IterateRooms instead.
+func (c *Client) IterateRooms(ctx context.Context, options IterateRoomsOptions) *RoomsIterator {
+	return c.Rooms().IterateRooms(ctx, options)
+}
+
+// CreateDirectRoom calls Rooms().CreateDirectRoom.
+//
+// Deprecated: use c.Rooms().CreateDirectRoom instead.
+func (c *Client) CreateDirectRoom(
+	ctx context.Context,
+	userA string,
+	userB string,
+	options CreateDirectRoomOptions,
+) (Room, error) {
+	return c.Rooms().CreateDirectRoom(ctx, userA, userB, options)
+}
+
+// GetRoomsByID calls Rooms().GetRoomsByID.
+//
+// Deprecated: use c.Rooms().GetRoomsByID instead.
+func (c *Client) GetRoomsByID(ctx context.Context, roomIDs []string) ([]Room, error) {
+	return c.Rooms().GetRoomsByID(ctx, roomIDs)
+}
+
+// GetRoomCounts calls Rooms().GetRoomCounts.
+//
+// Deprecated: use c.Rooms().GetRoomCounts instead.
+func (c *Client) GetRoomCounts(ctx context.Context, roomID string) (RoomCounts, error) {
+	return c.Rooms().GetRoomCounts(ctx, roomID)
+}
+
+// WaitForDelete calls Rooms().WaitForDelete.
+//
+// Deprecated: use c.Rooms().WaitForDelete instead.
+func (c *Client) WaitForDelete(ctx context.Context, jobID string, interval time.Duration) (DeleteStatus, error) {
+	return c.Rooms().WaitForDelete(ctx, jobID, interval)
+}
+
+// SendMessage calls Messages().SendMessage.
+//
+// Deprecated: use c.Messages().SendMessage instead.
+func (c *Client) SendMessage(ctx context.Context, options SendMessageOptions) (uint, error) {
+	return c.Messages().SendMessage(ctx, options)
+}
+
+// SendMultipartMessage calls Messages().SendMultipartMessage.
+//
+// Deprecated: use c.Messages().SendMultipartMessage instead.
+func (c *Client) SendMultipartMessage(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (uint, error) {
+	return c.Messages().SendMultipartMessage(ctx, options)
+}
+
+// SendMessageAsService calls Messages().SendMessageAsService.
+//
+// Deprecated: use c.Messages().SendMessageAsService instead.
+func (c *Client) SendMessageAsService(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (uint, error) {
+	return c.Messages().SendMessageAsService(ctx, options)
+}
+
+// SendSimpleMessage calls Messages().SendSimpleMessage.
+//
+// Deprecated: use c.Messages().SendSimpleMessage instead.
+func (c *Client) SendSimpleMessage(
+	ctx context.Context,
+	options SendSimpleMessageOptions,
+) (uint, error) {
+	return c.Messages().SendSimpleMessage(ctx, options)
+}
+
+// GetRoomMessages calls Messages().GetRoomMessages.
+//
+// Deprecated: use c.Messages().GetRoomMessages instead.
+func (c *Client) GetRoomMessages(
+	ctx context.Context,
+	roomID string,
+	options GetRoomMessagesOptions,
+) ([]Message, error) {
+	return c.Messages().GetRoomMessages(ctx, roomID, options)
+}
+
+// FetchMultipartMessage calls Messages().FetchMultipartMessage.
+//
+// Deprecated: use c.Messages().FetchMultipartMessage instead.
+func (c *Client) FetchMultipartMessage(
+	ctx context.Context,
+	options FetchMultipartMessageOptions,
+) (MultipartMessage, error) {
+	return c.Messages().FetchMultipartMessage(ctx, options)
+}
+
+// FetchMultipartMessages calls Messages().FetchMultipartMessages.
+//
+// Deprecated: use c.Messages().FetchMultipartMessages instead.
+func (c *Client) FetchMultipartMessages(
+	ctx context.Context,
+	roomID string,
+	options GetRoomMessagesOptions,
+) ([]MultipartMessage, error) {
+	return c.Messages().FetchMultipartMessages(ctx, roomID, options)
+}
+
+// DeleteMessage calls Messages().DeleteMessage.
+//
+// Deprecated: use c.Messages().DeleteMessage instead.
+func (c *Client) DeleteMessage(ctx context.Context, options DeleteMessageOptions) error {
+	return c.Messages().DeleteMessage(ctx, options)
+}
+
+// EditMessage calls Messages().EditMessage.
+//
+// Deprecated: use c.Messages().EditMessage instead.
+func (c *Client) EditMessage(ctx context.Context, roomID string, messageID uint, options EditMessageOptions) error {
+	return c.Messages().EditMessage(ctx, roomID, messageID, options)
+}
+
+// EditMultipartMessage calls Messages().EditMultipartMessage.
+//
+// Deprecated: use c.Messages().EditMultipartMessage instead.
+func (c *Client) EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options EditMultipartMessageOptions) error {
+	return c.Messages().EditMultipartMessage(ctx, roomID, messageID, options)
+}
+
+// EditSimpleMessage calls Messages().EditSimpleMessage.
+//
+// Deprecated: use c.Messages().EditSimpleMessage instead.
+func (c *Client) EditSimpleMessage(ctx context.Context, roomID string, messageID uint, options EditSimpleMessageOptions) error {
+	return c.Messages().EditSimpleMessage(ctx, roomID, messageID, options)
+}
+
+// IterateRoomMessages calls Messages().IterateRoomMessages.
+//
+// Deprecated: use c.Messages().IterateRoomMessages instead.
+func (c *Client) IterateRoomMessages(
+	ctx context.Context,
+	roomID string,
+	options IterateRoomMessagesOptions,
+) *MessageIterator {
+	return c.Messages().IterateRoomMessages(ctx, roomID, options)
+}
+
+// GetMessages calls Messages().GetMessages.
+//
+// Deprecated: use c.Messages().GetMessages instead.
+func (c *Client) GetMessages(ctx context.Context, roomID string, options GetMessagesOptions) ([]Message, error) {
+	return c.Messages().GetMessages(ctx, roomID, options)
+}
+
+// FetchLatestMessagesForRooms calls Messages().FetchLatestMessagesForRooms.
+//
+// Deprecated: use c.Messages().FetchLatestMessagesForRooms instead.
+func (c *Client) FetchLatestMessagesForRooms(
+	ctx context.Context,
+	roomIDs []string,
+	limit uint,
+) (map[string][]MultipartMessage, error) {
+	return c.Messages().FetchLatestMessagesForRooms(ctx, roomIDs, limit)
+}
+
+// DeleteMessages calls Messages().DeleteMessages.
+//
+// Deprecated: use c.Messages().DeleteMessages instead.
+func (c *Client) DeleteMessages(
+	ctx context.Context,
+	roomID string,
+	messageIDs []uint,
+	options BatchOptions,
+) *BatchResult {
+	return c.Messages().DeleteMessages(ctx, roomID, messageIDs, options)
+}
+
+// SendMessageAndGet calls Messages().SendMessageAndGet.
+//
+// Deprecated: use c.Messages().SendMessageAndGet instead.
+func (c *Client) SendMessageAndGet(ctx context.Context, options SendMessageOptions) (Message, error) {
+	return c.Messages().SendMessageAndGet(ctx, options)
+}
+
+// SendMultipartMessageAndGet calls Messages().SendMultipartMessageAndGet.
+//
+// Deprecated: use c.Messages().SendMultipartMessageAndGet instead.
+func (c *Client) SendMultipartMessageAndGet(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (MultipartMessage, error) {
+	return c.Messages().SendMultipartMessageAndGet(ctx, options)
+}
+
+// SendSimpleMessageAndGet calls Messages().SendSimpleMessageAndGet.
+//
+// Deprecated: use c.Messages().SendSimpleMessageAndGet instead.
+func (c *Client) SendSimpleMessageAndGet(
+	ctx context.Context,
+	options SendSimpleMessageOptions,
+) (MultipartMessage, error) {
+	return c.Messages().SendSimpleMessageAndGet(ctx, options)
+}
+
+// PinMessage calls Messages().PinMessage.
+//
+// Deprecated: use c.Messages().PinMessage instead.
+func (c *Client) PinMessage(ctx context.Context, roomID string, messageID uint) error {
+	return c.Messages().PinMessage(ctx, roomID, messageID)
+}
+
+// UnpinMessage calls Messages().UnpinMessage.
+//
+// Deprecated: use c.Messages().UnpinMessage instead.
+func (c *Client) UnpinMessage(ctx context.Context, roomID string, messageID uint) error {
+	return c.Messages().UnpinMessage(ctx, roomID, messageID)
+}
+
+// GetPinnedMessages calls Messages().GetPinnedMessages.
+//
+// Deprecated: use c.Messages().GetPinnedMessages instead.
+func (c *Client) GetPinnedMessages(ctx context.Context, roomID string) ([]MultipartMessage, error) {
+	return c.Messages().GetPinnedMessages(ctx, roomID)
+}
+
+// AddReaction calls Messages().AddReaction.
+//
+// Deprecated: use c.Messages().AddReaction instead.
+func (c *Client) AddReaction(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	userID string,
+	reaction string,
+) error {
+	return c.Messages().AddReaction(ctx, roomID, messageID, userID, reaction)
+}
+
+// RemoveReaction calls Messages().RemoveReaction.
+//
+// Deprecated: use c.Messages().RemoveReaction instead.
+func (c *Client) RemoveReaction(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	userID string,
+	reaction string,
+) error {
+	return c.Messages().RemoveReaction(ctx, roomID, messageID, userID, reaction)
+}
+
+// RedactMessage calls Messages().RedactMessage.
+//
+// Deprecated: use c.Messages().RedactMessage instead.
+func (c *Client) RedactMessage(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	replacementText string,
+) error {
+	return c.Messages().RedactMessage(ctx, roomID, messageID, replacementText)
+}
+
+// SearchRoomMessages calls Messages().SearchRoomMessages.
+//
+// Deprecated: use c.Messages().SearchRoomMessages instead.
+func (c *Client) SearchRoomMessages(
+	ctx context.Context,
+	roomID string,
+	query string,
+	options SearchRoomMessagesOptions,
+) ([]MessageSearchResult, error) {
+	return c.Messages().SearchRoomMessages(ctx, roomID, query, options)
+}
+
+// SendSystemMessage calls Messages().SendSystemMessage.
+//
+// Deprecated: use c.Messages().SendSystemMessage instead.
+func (c *Client) SendSystemMessage(ctx context.Context, options SendSystemMessageOptions) (uint, error) {
+	return c.Messages().SendSystemMessage(ctx, options)
+}
+
+// FetchThread calls Messages().FetchThread.
+//
+// Deprecated: use c.Messages().FetchThread instead.
+func (c *Client) FetchThread(
+	ctx context.Context,
+	roomID string,
+	parentID uint,
+	options FetchThreadOptions,
+) ([]MultipartMessage, error) {
+	return c.Messages().FetchThread(ctx, roomID, parentID, options)
+}
+
+// GetUserReadCursors calls Cursors().GetUserReadCursors.
+//
+// Deprecated: use c.Cursors().GetUserReadCursors instead.
+func (c *Client) GetUserReadCursors(ctx context.Context, userID string) ([]Cursor, error) {
+	return c.Cursors().GetUserReadCursors(ctx, userID)
+}
+
+// SetReadCursor calls Cursors().SetReadCursor.
+//
+// Deprecated: use c.Cursors().SetReadCursor instead.
+func (c *Client) SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error {
+	return c.Cursors().SetReadCursor(ctx, userID, roomID, position)
+}
+
+// GetReadCursorsForRoom calls Cursors().GetReadCursorsForRoom.
+//
+// Deprecated: use c.Cursors().GetReadCursorsForRoom instead.
+func (c *Client) GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error) {
+	return c.Cursors().GetReadCursorsForRoom(ctx, roomID)
+}
+
+// GetReadCursorsForRoomPage calls Cursors().GetReadCursorsForRoomPage.
+//
+// Deprecated: use c.Cursors().GetReadCursorsForRoomPage instead.
+func (c *Client) GetReadCursorsForRoomPage(
+	ctx context.Context,
+	roomID string,
+	options GetReadCursorsForRoomOptions,
+) ([]Cursor, error) {
+	return c.Cursors().GetReadCursorsForRoomPage(ctx, roomID, options)
+}
+
+// GetReadCursor calls Cursors().GetReadCursor.
+//
+// Deprecated: use c.Cursors().GetReadCursor instead.
+func (c *Client) GetReadCursor(ctx context.Context, userID string, roomID string) (Cursor, error) {
+	return c.Cursors().GetReadCursor(ctx, userID, roomID)
+}
+
+// DeleteReadCursor calls Cursors().DeleteReadCursor.
+//
+// Deprecated: use c.Cursors().DeleteReadCursor instead.
+func (c *Client) DeleteReadCursor(ctx context.Context, userID string, roomID string) error {
+	return c.Cursors().DeleteReadCursor(ctx, userID, roomID)
+}
+
+// GetUserCursors calls Cursors().GetUserCursors.
+//
+// Deprecated: use c.Cursors().GetUserCursors instead.
+func (c *Client) GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]Cursor, error) {
+	return c.Cursors().GetUserCursors(ctx, cursorType, userID)
+}
+
+// SetCursor calls Cursors().SetCursor.
+//
+// Deprecated: use c.Cursors().SetCursor instead.
+func (c *Client) SetCursor(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error {
+	return c.Cursors().SetCursor(ctx, cursorType, userID, roomID, position)
+}
+
+// GetCursorsForRoom calls Cursors().GetCursorsForRoom.
+//
+// Deprecated: use c.Cursors().GetCursorsForRoom instead.
+func (c *Client) GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]Cursor, error) {
+	return c.Cursors().GetCursorsForRoom(ctx, cursorType, roomID)
+}
+
+// GetCursor calls Cursors().GetCursor.
+//
+// Deprecated: use c.Cursors().GetCursor instead.
+func (c *Client) GetCursor(ctx context.Context, cursorType uint, userID string, roomID string) (Cursor, error) {
+	return c.Cursors().GetCursor(ctx, cursorType, userID, roomID)
+}
+
+// DeleteCursor calls Cursors().DeleteCursor.
+//
+// Deprecated: use c.Cursors().DeleteCursor instead.
+func (c *Client) DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error {
+	return c.Cursors().DeleteCursor(ctx, cursorType, userID, roomID)
+}
+
+// GetUnreadCounts calls Cursors().GetUnreadCounts.
+//
+// Deprecated: use c.Cursors().GetUnreadCounts instead.
+func (c *Client) GetUnreadCounts(ctx context.Context, userID string) (map[string]UnreadCount, error) {
+	return c.Cursors().GetUnreadCounts(ctx, userID)
+}
+
+// IterateRoomReadCursors calls Cursors().IterateRoomReadCursors.
+//
+// Deprecated: use c.Cursors().IterateRoomReadCursors instead.
+func (c *Client) IterateRoomReadCursors(
+	ctx context.Context,
+	roomID string,
+	options IterateRoomReadCursorsOptions,
+) *CursorsIterator {
+	return c.Cursors().IterateRoomReadCursors(ctx, roomID, options)
+}
+
+// GetMessageReadBy calls Cursors().GetMessageReadBy.
+//
+// Deprecated: use c.Cursors().GetMessageReadBy instead.
+func (c *Client) GetMessageReadBy(ctx context.Context, roomID string, messageID uint) (MessageReadBy, error) {
+	return c.Cursors().GetMessageReadBy(ctx, roomID, messageID)
+}
+
+// GetRoles calls Roles().GetRoles.
+//
+// Deprecated: use c.Roles().GetRoles instead.
+func (c *Client) GetRoles(ctx context.Context) ([]Role, error) {
+	return c.Roles().GetRoles(ctx)
+}
+
+// CreateGlobalRole calls Roles().CreateGlobalRole.
+//
+// Deprecated: use c.Roles().CreateGlobalRole instead.
+func (c *Client) CreateGlobalRole(ctx context.Context, options CreateRoleOptions) error {
+	return c.Roles().CreateGlobalRole(ctx, options)
+}
+
+// CreateRoomRole calls Roles().CreateRoomRole.
+//
+// Deprecated: use c.Roles().CreateRoomRole instead.
+func (c *Client) CreateRoomRole(ctx context.Context, options CreateRoleOptions) error {
+	return c.Roles().CreateRoomRole(ctx, options)
+}
+
+// DeleteGlobalRole calls Roles().DeleteGlobalRole.
+//
+// Deprecated: use c.Roles().DeleteGlobalRole instead.
+func (c *Client) DeleteGlobalRole(ctx context.Context, roleName string) error {
+	return c.Roles().DeleteGlobalRole(ctx, roleName)
+}
+
+// DeleteRoomRole calls Roles().DeleteRoomRole.
+//
+// Deprecated: use c.Roles().DeleteRoomRole instead.
+func (c *Client) DeleteRoomRole(ctx context.Context, roleName string) error {
+	return c.Roles().DeleteRoomRole(ctx, roleName)
+}
+
+// GetPermissionsForGlobalRole calls Roles().GetPermissionsForGlobalRole.
+//
+// Deprecated: use c.Roles().GetPermissionsForGlobalRole instead.
+func (c *Client) GetPermissionsForGlobalRole(
+	ctx context.Context,
+	roleName string,
+) ([]string, error) {
+	return c.Roles().GetPermissionsForGlobalRole(ctx, roleName)
+}
+
+// GetPermissionsForRoomRole calls Roles().GetPermissionsForRoomRole.
+//
+// Deprecated: use c.Roles().GetPermissionsForRoomRole instead.
+func (c *Client) GetPermissionsForRoomRole(
+	ctx context.Context,
+	roleName string,
+) ([]string, error) {
+	return c.Roles().GetPermissionsForRoomRole(ctx, roleName)
+}
+
+// UpdatePermissionsForGlobalRole calls Roles().UpdatePermissionsForGlobalRole.
+//
+// Deprecated: use c.Roles().UpdatePermissionsForGlobalRole instead.
+func (c *Client) UpdatePermissionsForGlobalRole(
+	ctx context.Context,
+	roleName string,
+	options UpdateRolePermissionsOptions,
+) error {
+	return c.Roles().UpdatePermissionsForGlobalRole(ctx, roleName, options)
+}
+
+// UpdatePermissionsForRoomRole calls Roles().UpdatePermissionsForRoomRole.
+//
+// Deprecated: use c.Roles().UpdatePermissionsForRoomRole instead.
+func (c *Client) UpdatePermissionsForRoomRole(
+	ctx context.Context,
+	roleName string,
+	options UpdateRolePermissionsOptions,
+) error {
+	return c.Roles().UpdatePermissionsForRoomRole(ctx, roleName, options)
+}
+
+// GetUserRoles calls Roles().GetUserRoles.
+//
+// Deprecated: use c.Roles().GetUserRoles instead.
+func (c *Client) GetUserRoles(ctx context.Context, userID string) ([]Role, error) {
+	return c.Roles().GetUserRoles(ctx, userID)
+}
+
+// AssignGlobalRoleToUser calls Roles().AssignGlobalRoleToUser.
+//
+// Deprecated: use c.Roles().AssignGlobalRoleToUser instead.
+func (c *Client) AssignGlobalRoleToUser(ctx context.Context, userID string, roleName string) error {
+	return c.Roles().AssignGlobalRoleToUser(ctx, userID, roleName)
+}
+
+// AssignRoomRoleToUser calls Roles().AssignRoomRoleToUser.
+//
+// Deprecated: use c.Roles().AssignRoomRoleToUser instead.
+func (c *Client) AssignRoomRoleToUser(
+	ctx context.Context,
+	userID string,
+	roomID string,
+	roleName string,
+) error {
+	return c.Roles().AssignRoomRoleToUser(ctx, userID, roomID, roleName)
+}
+
+// RemoveGlobalRoleForUser calls Roles().RemoveGlobalRoleForUser.
+//
+// Deprecated: use c.Roles().RemoveGlobalRoleForUser instead.
+func (c *Client) RemoveGlobalRoleForUser(ctx context.Context, userID string) error {
+	return c.Roles().RemoveGlobalRoleForUser(ctx, userID)
+}
+
+// RemoveRoomRoleForUser calls Roles().RemoveRoomRoleForUser.
+//
+// Deprecated: use c.Roles().RemoveRoomRoleForUser instead.
+func (c *Client) RemoveRoomRoleForUser(ctx context.Context, userID string, roomID string) error {
+	return c.Roles().RemoveRoomRoleForUser(ctx, userID, roomID)
+}
+
+// CreateDefaultRoles calls Roles().CreateDefaultRoles.
+//
+// Deprecated: use c.Roles().CreateDefaultRoles instead.
+func (c *Client) CreateDefaultRoles(ctx context.Context) error {
+	return c.Roles().CreateDefaultRoles(ctx)
+}
+
+// GetRole calls Roles().GetRole.
+//
+// Deprecated: use c.Roles().GetRole instead.
+func (c *Client) GetRole(ctx context.Context, name string, scope string) (Role, error) {
+	return c.Roles().GetRole(ctx, name, scope)
+}
+
+// UpsertGlobalRole calls Roles().UpsertGlobalRole.
+//
+// Deprecated: use c.Roles().UpsertGlobalRole instead.
+func (c *Client) UpsertGlobalRole(ctx context.Context, options CreateRoleOptions) error {
+	return c.Roles().UpsertGlobalRole(ctx, options)
+}
+
+// UpsertRoomRole calls Roles().UpsertRoomRole.
+//
+// Deprecated: use c.Roles().UpsertRoomRole instead.
+func (c *Client) UpsertRoomRole(ctx context.Context, options CreateRoleOptions) error {
+	return c.Roles().UpsertRoomRole(ctx, options)
+}
+
+// GetEffectivePermissions calls Roles().GetEffectivePermissions.
+//
+// Deprecated: use c.Roles().GetEffectivePermissions instead.
+func (c *Client) GetEffectivePermissions(ctx context.Context, userID string, roomID string) ([]string, error) {
+	return c.Roles().GetEffectivePermissions(ctx, userID, roomID)
+}
+
+// AssignGlobalRoleToUsers calls Roles().AssignGlobalRoleToUsers.
+//
+// Deprecated: use c.Roles().AssignGlobalRoleToUsers instead.
+func (c *Client) AssignGlobalRoleToUsers(
+	ctx context.Context,
+	userIDs []string,
+	roleName string,
+	options BatchOptions,
+) *BatchResult {
+	return c.Roles().AssignGlobalRoleToUsers(ctx, userIDs, roleName, options)
+}
+
+// AssignRoomRoleToUsers calls Roles().AssignRoomRoleToUsers.
+//
+// Deprecated: use c.Roles().AssignRoomRoleToUsers instead.
+func (c *Client) AssignRoomRoleToUsers(
+	ctx context.Context,
+	userIDs []string,
+	roomID string,
+	roleName string,
+	options BatchOptions,
+) *BatchResult {
+	return c.Roles().AssignRoomRoleToUsers(ctx, userIDs, roomID, roleName, options)
+}
+
+// GetUsersWithRole calls Roles().GetUsersWithRole.
+//
+// Deprecated: use c.Roles().GetUsersWithRole instead.
+func (c *Client) GetUsersWithRole(
+	ctx context.Context,
+	roleName string,
+	scope string,
+	options GetUsersWithRoleOptions,
+) ([]RoleAssignment, error) {
+	return c.Roles().GetUsersWithRole(ctx, roleName, scope, options)
+}
+
+// ListRoleAssignments calls Roles().ListRoleAssignments.
+//
+// Deprecated: use c.Roles().ListRoleAssignments instead.
+func (c *Client) ListRoleAssignments(
+	ctx context.Context,
+	options ListRoleAssignmentsOptions,
+) *RoleAssignmentsIterator {
+	return c.Roles().ListRoleAssignments(ctx, options)
+}
+
+// ApplyRolesConfig calls Roles().ApplyRolesConfig.
+//
+// Deprecated: use c.Roles().ApplyRolesConfig instead.
+func (c *Client) ApplyRolesConfig(
+	ctx context.Context,
+	config RolesConfig,
+	options ApplyRolesConfigOptions,
+) ([]RoleChange, error) {
+	return c.Roles().ApplyRolesConfig(ctx, config, options)
+}
+
+// ExportRoles calls Roles().ExportRoles.
+//
+// Deprecated: use c.Roles().ExportRoles instead.
+func (c *Client) ExportRoles(ctx context.Context) (RolesConfig, error) {
+	return c.Roles().ExportRoles(ctx)
+}
+
+// ImportRoles calls Roles().ImportRoles.
+//
+// Deprecated: use c.Roles().ImportRoles instead.
+func (c *Client) ImportRoles(
+	ctx context.Context,
+	config RolesConfig,
+	options ImportRolesOptions,
+) ([]RoleChange, error) {
+	return c.Roles().ImportRoles(ctx, config, options)
+}
+
+// Authenticate calls Auth().Authenticate.
+//
+// Deprecated: use c.Auth().Authenticate instead.
+func (c *Client) Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error) {
+	return c.Auth().Authenticate(payload, options)
+}
+
+// GenerateAccessToken calls Auth().GenerateAccessToken.
+//
+// Deprecated: use c.Auth().GenerateAccessToken instead.
+func (c *Client) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	return c.Auth().GenerateAccessToken(options)
+}
+
+// GenerateSUToken calls Auth().GenerateSUToken.
+//
+// Deprecated: use c.Auth().GenerateSUToken instead.
+func (c *Client) GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	return c.Auth().GenerateSUToken(options)
+}
+
+// VerifyToken calls Auth().VerifyToken.
+//
+// Deprecated: use c.Auth().VerifyToken instead.
+func (c *Client) VerifyToken(ctx context.Context, tokenString string) (Claims, error) {
+	return c.Auth().VerifyToken(ctx, tokenString)
+}
+
+// IssueRefreshToken calls Auth().IssueRefreshToken.
+//
+// Deprecated: use c.Auth().IssueRefreshToken instead.
+func (c *Client) IssueRefreshToken(ctx context.Context, userID string) (string, error) {
+	return c.Auth().IssueRefreshToken(ctx, userID)
+}
+
+// RefreshAccessToken calls Auth().RefreshAccessToken.
+//
+// Deprecated: use c.Auth().RefreshAccessToken instead.
+func (c *Client) RefreshAccessToken(
+	ctx context.Context,
+	refreshToken string,
+	options auth.Options,
+) (TokenResponse, error) {
+	return c.Auth().RefreshAccessToken(ctx, refreshToken, options)
+}
+
+// RevokeRefreshToken calls Auth().RevokeRefreshToken.
+//
+// Deprecated: use c.Auth().RevokeRefreshToken instead.
+func (c *Client) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	return c.Auth().RevokeRefreshToken(ctx, refreshToken)
+}
+
+// RevokeToken calls Auth().RevokeToken.
+//
+// Deprecated: use c.Auth().RevokeToken instead.
+func (c *Client) RevokeToken(ctx context.Context, tokenString string) error {
+	return c.Auth().RevokeToken(ctx, tokenString)
+}
+
+// RevokeTokensForUser calls Auth().RevokeTokensForUser.
+//
+// Deprecated: use c.Auth().RevokeTokensForUser instead.
+func (c *Client) RevokeTokensForUser(ctx context.Context, userID string) error {
+	return c.Auth().RevokeTokensForUser(ctx, userID)
+}
+
+// GenerateScopedToken calls Auth().GenerateScopedToken.
+//
+// Deprecated: use c.Auth().GenerateScopedToken instead.
+func (c *Client) GenerateScopedToken(ctx context.Context, options ScopedTokenOptions) (auth.TokenWithExpiry, error) {
+	return c.Auth().GenerateScopedToken(ctx, options)
+}
+
+// GenerateReadOnlyToken calls Auth().GenerateReadOnlyToken.
+//
+// Deprecated: use c.Auth().GenerateReadOnlyToken instead.
+func (c *Client) GenerateReadOnlyToken(ctx context.Context, options ReadOnlyTokenOptions) (auth.TokenWithExpiry, error) {
+	return c.Auth().GenerateReadOnlyToken(ctx, options)
+}
+
+// AuthMiddleware calls Auth().AuthMiddleware.
+//
+// Deprecated: use c.Auth().AuthMiddleware instead.
+func (c *Client) AuthMiddleware(next http.Handler, options VerifierOptions) http.Handler {
+	return c.Auth().AuthMiddleware(next, options)
+}
+
+// TokenProviderHandler calls Auth().TokenProviderHandler.
+//
+// Deprecated: use c.Auth().TokenProviderHandler instead.
+func (c *Client) TokenProviderHandler(options TokenProviderOptions) http.Handler {
+	return c.Auth().TokenProviderHandler(options)
+}
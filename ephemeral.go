@@ -0,0 +1,157 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// expiringMessagesStorePrefix is the prefix of the Store keys messages sent with ExpiresAfter are
+// recorded under. Keys continue with the zero padded expiry time in nanoseconds, so that listing
+// them returns the messages that expire soonest first.
+const expiringMessagesStorePrefix = "expiring-messages/"
+
+const defaultReaperPollInterval = time.Second
+
+type expiringMessage struct {
+	RoomID    string `json:"room_id"`
+	MessageID uint   `json:"message_id"`
+}
+
+// expireMessage records that a message must be deleted once expiresAfter has elapsed, if it is
+// positive. Failures are returned as an *ExpiryError.
+func (c *Client) expireMessage(ctx context.Context, roomID string, messageID uint, expiresAfter time.Duration) error {
+	if expiresAfter <= 0 {
+		return nil
+	}
+
+	value, err := json.Marshal(expiringMessage{RoomID: roomID, MessageID: messageID})
+	if err != nil {
+		return &ExpiryError{RoomID: roomID, MessageID: messageID, Err: err}
+	}
+
+	expiresAt := time.Now().Add(expiresAfter)
+	key := fmt.Sprintf("%s%020d/%s/%d", expiringMessagesStorePrefix, expiresAt.UnixNano(), roomID, messageID)
+
+	if err := c.store.Put(ctx, key, value, 0); err != nil {
+		return &ExpiryError{RoomID: roomID, MessageID: messageID, Err: err}
+	}
+
+	return nil
+}
+
+// MessageReaperOptions contains parameters to configure a MessageReaper.
+type MessageReaperOptions struct {
+	// How often the store is checked for messages that have expired. Defaults to 1s.
+	PollInterval time.Duration
+	// Optional function called when an expired message could not be deleted. Deleting it is
+	// retried on the next check.
+	OnError func(roomID string, messageID uint, err error)
+}
+
+// MessageReaper deletes messages sent with ExpiresAfter once they have expired.
+//
+// Expiring messages are recorded in the client's Store (see WithStore), so with a persistent
+// store messages that expire while no reaper is running are deleted when one next runs. Messages
+// are only deleted while Run is running.
+type MessageReaper struct {
+	client  *Client
+	options MessageReaperOptions
+}
+
+// NewMessageReaper returns a MessageReaper that deletes messages with client.
+func NewMessageReaper(client *Client, options MessageReaperOptions) *MessageReaper {
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultReaperPollInterval
+	}
+
+	return &MessageReaper{client: client, options: options}
+}
+
+// Run deletes messages as they expire, until ctx is cancelled.
+// It returns the error that caused it to stop.
+func (r *MessageReaper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.deleteExpired(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// deleteExpired deletes the messages that have expired. Failures to delete a message are
+// reported to OnError; only failures to access the store are returned.
+func (r *MessageReaper) deleteExpired(ctx context.Context) error {
+	keys, err := r.client.store.List(ctx, expiringMessagesStorePrefix)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixNano()
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		expiry := strings.SplitN(key[len(expiringMessagesStorePrefix):], "/", 2)[0]
+		expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+		if err != nil {
+			return fmt.Errorf("Failed to parse expiry of %s: %v", key, err)
+		}
+		if expiresAt > now {
+			// Keys are ordered by expiry, so no later message has expired either.
+			return nil
+		}
+
+		if err := r.deleteMessage(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *MessageReaper) deleteMessage(ctx context.Context, key string) error {
+	defer lockKey(key)()
+
+	value, err := r.client.store.Get(ctx, key)
+	if err == ErrStoreKeyNotFound {
+		// Deleted by another reaper.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var message expiringMessage
+	if err := json.Unmarshal(value, &message); err != nil {
+		return fmt.Errorf("Failed to decode expiring message %s: %v", key, err)
+	}
+
+	err = r.client.Messages().DeleteMessage(ctx, DeleteMessageOptions{
+		RoomID:    message.RoomID,
+		MessageID: message.MessageID,
+	})
+	if err != nil && !isNotFound(err) {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if r.options.OnError != nil {
+			r.options.OnError(message.RoomID, message.MessageID, err)
+		}
+		return nil
+	}
+
+	return r.client.store.Delete(ctx, key)
+}
@@ -0,0 +1,66 @@
+package chatkit
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// ErrConflict is returned by updates that expected a resource to be unchanged since it was last
+// read, when it has in fact been changed.
+var ErrConflict = errors.New("The resource has been modified since it was read")
+
+// ErrRoleNotFound is returned when a role with the given name and scope does not exist.
+var ErrRoleNotFound = errors.New("Role not found")
+
+// ExpiryError is returned by the methods sending messages when a message sent with ExpiresAfter
+// was sent, but its expiry could not be recorded in the client's Store, so the MessageReaper will
+// not delete it. The message must not be sent again; delete it or retry recording its expiry
+// instead.
+type ExpiryError struct {
+	RoomID    string
+	MessageID uint
+	Err       error
+}
+
+func (e *ExpiryError) Error() string {
+	return fmt.Sprintf("Message %d was sent but its expiry could not be stored: %v", e.MessageID, e.Err)
+}
+
+// IdempotencyKeyError is returned by the methods sending messages when a message sent with an
+// IdempotencyKey was sent, but the key could not be recorded, so sending the message again with
+// the same key would send it twice. The message must not be sent again.
+type IdempotencyKeyError struct {
+	RoomID         string
+	MessageID      uint
+	IdempotencyKey string
+	Err            error
+}
+
+func (e *IdempotencyKeyError) Error() string {
+	return fmt.Sprintf("Message %d was sent but its idempotency key could not be stored: %v", e.MessageID, e.Err)
+}
+
+// sendFailed reports whether err means that a message was not sent, as opposed to an
+// *ExpiryError or *IdempotencyKeyError, which are returned for messages that were sent.
+func sendFailed(err error) bool {
+	switch err.(type) {
+	case nil, *ExpiryError, *IdempotencyKeyError:
+		return false
+	default:
+		return true
+	}
+}
+
+// hasStatus reports whether err is an error response from Chatkit with the given status code.
+func hasStatus(err error, status int) bool {
+	errorResponse, ok := err.(*platformclient.ErrorResponse)
+	return ok && errorResponse.Status == status
+}
+
+// isNotFound reports whether err is a 404 error response from Chatkit.
+func isNotFound(err error) bool {
+	return hasStatus(err, http.StatusNotFound)
+}
@@ -0,0 +1,292 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportFormat is the format records are written in by the Export* methods.
+type ExportFormat int
+
+const (
+	// ExportFormatNDJSON writes one JSON object per line.
+	ExportFormatNDJSON ExportFormat = iota
+	// ExportFormatCSV writes a header row followed by one row per record.
+	// Nested values, such as custom data, are written as JSON.
+	ExportFormatCSV
+)
+
+// recordWriter writes records to an io.Writer in an ExportFormat.
+type recordWriter interface {
+	Write(record interface{}, row []string) error
+	Flush() error
+}
+
+func newRecordWriter(w io.Writer, format ExportFormat, header []string) (recordWriter, error) {
+	switch format {
+	case ExportFormatNDJSON:
+		return &ndjsonWriter{encoder: json.NewEncoder(w)}, nil
+	case ExportFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvWriter{writer: writer}, nil
+	default:
+		return nil, fmt.Errorf("Unknown export format: %d", format)
+	}
+}
+
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *ndjsonWriter) Write(record interface{}, row []string) error {
+	return w.encoder.Encode(record)
+}
+
+func (w *ndjsonWriter) Flush() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func (w *csvWriter) Write(record interface{}, row []string) error {
+	return w.writer.Write(row)
+}
+
+func (w *csvWriter) Flush() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// jsonField renders a nested value as a CSV field.
+func jsonField(value interface{}) string {
+	if value == nil {
+		return ""
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+var userExportHeader = []string{"id", "name", "avatar_url", "custom_data", "created_at", "updated_at"}
+
+// ExportUsers writes every user of the instance to w in the given format.
+// Users are fetched a page at a time and written as they arrive, so the whole user base is
+// never held in memory.
+func (c *Client) ExportUsers(ctx context.Context, w io.Writer, format ExportFormat) error {
+	writer, err := newRecordWriter(w, format, userExportHeader)
+	if err != nil {
+		return err
+	}
+
+	it := c.Users().IterateUsers(ctx, IterateUsersOptions{})
+	for it.Next() {
+		user := it.User()
+
+		var customData interface{}
+		if user.CustomData != nil {
+			customData = user.CustomData
+		}
+
+		err := writer.Write(user, []string{
+			user.ID,
+			user.Name,
+			user.AvatarURL,
+			jsonField(customData),
+			user.CreatedAt.Format(time.RFC3339),
+			user.UpdatedAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+// UsersNDJSON writes every user of the instance to w as newline-delimited JSON.
+func (c *Client) UsersNDJSON(ctx context.Context, w io.Writer) error {
+	return c.ExportUsers(ctx, w, ExportFormatNDJSON)
+}
+
+// RoomsNDJSON writes every room of the instance to w as newline-delimited JSON.
+func (c *Client) RoomsNDJSON(ctx context.Context, w io.Writer, options IterateRoomsOptions) error {
+	encoder := json.NewEncoder(w)
+
+	it := c.Rooms().IterateRooms(ctx, options)
+	for it.Next() {
+		if err := encoder.Encode(it.Room()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// RoomMessagesNDJSON writes the whole history of a room to w as newline-delimited JSON, oldest
+// message first.
+func (c *Client) RoomMessagesNDJSON(ctx context.Context, w io.Writer, roomID string) error {
+	encoder := json.NewEncoder(w)
+
+	it := c.Messages().IterateRoomMessages(ctx, roomID, IterateRoomMessagesOptions{Direction: "newer"})
+	for it.Next() {
+		if err := encoder.Encode(it.Message()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// ExportRoomMessagesOptions contains parameters to pass when exporting a room's messages.
+type ExportRoomMessagesOptions struct {
+	// Only export messages newer than the message with this ID.
+	InitialID *uint
+	// Number of messages fetched per request. Defaults to 100.
+	PageSize uint
+}
+
+var messageExportHeader = []string{
+	"id", "user_id", "room_id", "parent_message_id", "text", "parts", "created_at", "updated_at",
+}
+
+// ExportRoomMessages writes the whole multipart history of a room to w in the given format,
+// oldest message first.
+// Messages are fetched a page at a time and written as they arrive, so the history is never held
+// in memory. Attachment download URLs that have expired, or are about to, are refreshed before
+// the message is written, so every attachment in the export can be downloaded. In CSV exports
+// the text column holds the content of the message's text/plain parts, and the parts column all
+// of its parts as JSON.
+func (c *Client) ExportRoomMessages(
+	ctx context.Context,
+	roomID string,
+	w io.Writer,
+	format ExportFormat,
+	options ExportRoomMessagesOptions,
+) error {
+	writer, err := newRecordWriter(w, format, messageExportHeader)
+	if err != nil {
+		return err
+	}
+
+	it := c.Messages().IterateRoomMessages(ctx, roomID, IterateRoomMessagesOptions{
+		Direction: "newer",
+		InitialID: options.InitialID,
+		PageSize:  options.PageSize,
+	})
+	for it.Next() {
+		message := it.Message()
+
+		if err := c.refreshExpiringAttachments(ctx, message.Parts); err != nil {
+			return err
+		}
+
+		parentMessageID := ""
+		if message.ParentMessageID != nil {
+			parentMessageID = strconv.FormatUint(uint64(*message.ParentMessageID), 10)
+		}
+
+		err := writer.Write(message, []string{
+			strconv.FormatUint(uint64(message.ID), 10),
+			message.UserID,
+			message.RoomID,
+			parentMessageID,
+			message.PlainText(),
+			jsonField(message.Parts),
+			message.CreatedAt.Format(time.RFC3339),
+			message.UpdatedAt.Format(time.RFC3339),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if err := it.Err(); err != nil {
+		return err
+	}
+
+	return writer.Flush()
+}
+
+// refreshExpiringAttachments refreshes, in place, the attachments of parts whose download URLs
+// have expired or are about to.
+func (c *Client) refreshExpiringAttachments(ctx context.Context, parts []Part) error {
+	for i, part := range parts {
+		att := part.Attachment
+		if att == nil || att.Expiration.IsZero() || att.RefreshURL == "" {
+			continue
+		}
+		if time.Now().Add(attachmentExpiryMargin).Before(att.Expiration) {
+			continue
+		}
+
+		refreshed, err := c.RefreshAttachment(ctx, *att)
+		if err != nil {
+			return err
+		}
+		parts[i].Attachment = &refreshed
+	}
+
+	return nil
+}
+
+// RolesNDJSON writes every role of the instance to w as newline-delimited JSON.
+func (c *Client) RolesNDJSON(ctx context.Context, w io.Writer) error {
+	roles, err := c.Roles().GetRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	return encodeEach(w, len(roles), func(i int) interface{} { return roles[i] })
+}
+
+// RoomReadCursorsNDJSON writes every read cursor set in a room to w as newline-delimited JSON.
+func (c *Client) RoomReadCursorsNDJSON(ctx context.Context, w io.Writer, roomID string) error {
+	encoder := json.NewEncoder(w)
+
+	it := c.Cursors().IterateRoomReadCursors(ctx, roomID, IterateRoomReadCursorsOptions{})
+	for it.Next() {
+		if err := encoder.Encode(it.Cursor()); err != nil {
+			return err
+		}
+	}
+
+	return it.Err()
+}
+
+// UserReadCursorsNDJSON writes every read cursor set by a user to w as newline-delimited JSON.
+func (c *Client) UserReadCursorsNDJSON(ctx context.Context, w io.Writer, userID string) error {
+	cursors, err := c.Cursors().GetUserReadCursors(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return encodeEach(w, len(cursors), func(i int) interface{} { return cursors[i] })
+}
+
+// encodeEach writes n values to w as newline-delimited JSON.
+func encodeEach(w io.Writer, n int, value func(i int) interface{}) error {
+	encoder := json.NewEncoder(w)
+	for i := 0; i < n; i++ {
+		if err := encoder.Encode(value(i)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Credentials of the clients of unit tests, which don't make requests to Chatkit.
+const (
+	testInstanceLocator = "v1:test:instance"
+	testKey             = "key:secret"
+)
+
+// newTestClient returns a client for unit tests, created with options.
+func newTestClient(t *testing.T, options ...ClientOption) *Client {
+	client, err := NewClient(testInstanceLocator, testKey, options...)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	return client
+}
+
+// newStubServer starts a server that handles the requests of the client it returns, created with
+// options, whatever their host. It must be closed by the test.
+func newStubServer(
+	t *testing.T,
+	handler http.HandlerFunc,
+	options ...ClientOption,
+) (*Client, *httptest.Server) {
+	server := httptest.NewTLSServer(handler)
+	target, _ := url.Parse(server.URL)
+	options = append(options[:len(options):len(options)], WithHTTPClient(&http.Client{
+		Transport: stubTransport{target: target, transport: server.Client().Transport},
+	}))
+
+	return newTestClient(t, options...), server
+}
+
+// stubTransport sends requests to a target server, whatever their host.
+type stubTransport struct {
+	target    *url.URL
+	transport http.RoundTripper
+}
+
+func (t stubTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	redirected := *request
+	target := *request.URL
+	target.Scheme, target.Host = t.target.Scheme, t.target.Host
+	redirected.URL, redirected.Host = &target, ""
+
+	return t.transport.RoundTrip(&redirected)
+}
+
+// writeSubscriptionEvent writes an event to a subscription in the format of the platform, and
+// flushes it.
+func writeSubscriptionEvent(w http.ResponseWriter, id string, name string, data interface{}) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"event_name": name,
+		"data":       data,
+		"timestamp":  time.Now().UTC().Format(time.RFC3339),
+	})
+	fmt.Fprintf(w, "[1,%q,{},%s]\n", id, body)
+	w.(http.Flusher).Flush()
+}
+
+// holdSubscription starts a subscription's response, and holds it open until the request is
+// cancelled.
+func holdSubscription(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	<-r.Context().Done()
+}
+
+// recordingStore is a Store that records the TTLs keys were put with.
+type recordingStore struct {
+	Store
+
+	mu   sync.Mutex
+	ttls map[string]time.Duration
+}
+
+func newRecordingStore() *recordingStore {
+	return &recordingStore{Store: NewMemoryStore(), ttls: map[string]time.Duration{}}
+}
+
+func (s *recordingStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	s.ttls[key] = ttl
+	s.mu.Unlock()
+
+	return s.Store.Put(ctx, key, value, ttl)
+}
+
+func (s *recordingStore) ttl(key string) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.ttls[key]
+}
@@ -3,6 +3,12 @@
 package authenticator
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
 	auth "github.com/pusher/pusher-platform-go/auth"
 )
 
@@ -12,10 +18,29 @@ type Service interface {
 	Authenticate(payload auth.Payload, options auth.Options) (*auth.Response, error)
 	GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error)
 	GenerateSUToken(options auth.Options) (auth.TokenWithExpiry, error)
+	GenerateScopedToken(options auth.Options, permissions []string) (auth.TokenWithExpiry, error)
+	VerifyToken(tokenString string) (Claims, error)
+}
+
+// Options configures the tokens generated and accepted by an authenticator.
+type Options struct {
+	// Lifetime of user tokens that don't set TokenExpiry. Defaults to the platform's default of
+	// 24 hours.
+	AccessTokenExpiry time.Duration
+	// Lifetime of SU tokens that don't set TokenExpiry. Defaults to AccessTokenExpiry.
+	SUTokenExpiry time.Duration
+	// How far the clock of the server that generated a token may be ahead of or behind this one
+	// when verifying it: tokens are accepted for this long after they expire, and may have been
+	// issued up to this long in the future.
+	ClockSkew time.Duration
 }
 
 type authenticator struct {
 	platformAuthenticator auth.Authenticator
+	signer                *signer
+	instanceID            string
+	keyID                 string
+	options               Options
 }
 
 // NewService returns a new instance of an authenticator that conforms to the `Service` interface.
@@ -23,26 +48,142 @@ func NewService(
 	instanceID string,
 	keyID string,
 	keySecret string,
+	options Options,
 ) Service {
+	if options.SUTokenExpiry <= 0 {
+		options.SUTokenExpiry = options.AccessTokenExpiry
+	}
+
 	return &authenticator{
 		platformAuthenticator: auth.New(instanceID, keyID, keySecret),
+		signer:                newSigner(instanceID, keyID, keySecret),
+		instanceID:            instanceID,
+		keyID:                 keyID,
+		options:               options,
 	}
 }
 
 // Authenticate should be used within a token providing endpoint to
 // generate access tokens for a user.
+// The token is signed by the platform authenticator, since only it can build an auth.Response;
+// tokens are otherwise generated by GenerateAccessToken, whose claims match.
 func (a *authenticator) Authenticate(
 	payload auth.Payload,
 	options auth.Options,
 ) (*auth.Response, error) {
+	if err := validateServiceClaims(options.ServiceClaims); err != nil {
+		return nil, err
+	}
+
+	options, err := withTokenID(a.withDefaultExpiry(options))
+	if err != nil {
+		return nil, err
+	}
+
 	return a.platformAuthenticator.Do(payload, options)
 }
 
 // GenerateAccessToken returns a TokenWithExpiry based on the options provided.
+// Tokens are signed by the authenticator itself rather than the platform's, since this is on the
+// path of every request.
 func (a *authenticator) GenerateAccessToken(
 	options auth.Options,
 ) (auth.TokenWithExpiry, error) {
-	return a.platformAuthenticator.GenerateAccessToken(options)
+	if err := validateServiceClaims(options.ServiceClaims); err != nil {
+		return auth.TokenWithExpiry{}, err
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return auth.TokenWithExpiry{}, err
+	}
+
+	return a.signer.generateAccessToken(a.withDefaultExpiry(options), tokenID)
+}
+
+// GenerateScopedToken returns a TokenWithExpiry based on the options provided, restricted to
+// permissions by a `permissions` claim. Chatkit doesn't read the claim: it is enforced by services
+// that verify the token with VerifyToken.
+func (a *authenticator) GenerateScopedToken(
+	options auth.Options,
+	permissions []string,
+) (auth.TokenWithExpiry, error) {
+	if err := validateServiceClaims(options.ServiceClaims); err != nil {
+		return auth.TokenWithExpiry{}, err
+	}
+
+	if options.Su {
+		return auth.TokenWithExpiry{}, errors.New("Scoped tokens must not have the `su` claim")
+	}
+
+	tokenID, err := newTokenID()
+	if err != nil {
+		return auth.TokenWithExpiry{}, err
+	}
+
+	serviceClaims := make(map[string]interface{}, len(options.ServiceClaims)+1)
+	for name, value := range options.ServiceClaims {
+		serviceClaims[name] = value
+	}
+	serviceClaims[permissionsClaim] = append([]string{}, permissions...)
+	options.ServiceClaims = serviceClaims
+
+	return a.signer.generateAccessToken(a.withDefaultExpiry(options), tokenID)
+}
+
+// newTokenID returns a random ID for the `jti` claim of a token, so that the token can be revoked
+// individually.
+func newTokenID() (string, error) {
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", fmt.Errorf("Failed to generate token ID: %v", err)
+	}
+
+	return hex.EncodeToString(idBytes), nil
+}
+
+// withTokenID adds a random `jti` claim to the service claims of options.
+func withTokenID(options auth.Options) (auth.Options, error) {
+	tokenID, err := newTokenID()
+	if err != nil {
+		return auth.Options{}, err
+	}
+
+	serviceClaims := make(map[string]interface{}, len(options.ServiceClaims)+1)
+	for name, value := range options.ServiceClaims {
+		serviceClaims[name] = value
+	}
+	serviceClaims["jti"] = tokenID
+	options.ServiceClaims = serviceClaims
+
+	return options, nil
+}
+
+// withDefaultExpiry sets the TokenExpiry of options to the configured lifetime of tokens of their
+// kind, unless it is set already.
+func (a *authenticator) withDefaultExpiry(options auth.Options) auth.Options {
+	expiry := a.options.AccessTokenExpiry
+	if options.Su {
+		expiry = a.options.SUTokenExpiry
+	}
+
+	if options.TokenExpiry == nil && expiry > 0 {
+		options.TokenExpiry = &expiry
+	}
+
+	return options
+}
+
+// validateServiceClaims checks that service claims, which are added to tokens alongside the
+// claims set by the platform, don't replace any of them.
+func validateServiceClaims(serviceClaims map[string]interface{}) error {
+	for name := range serviceClaims {
+		if registeredClaims[name] {
+			return fmt.Errorf("ServiceClaims must not contain the reserved claim %q", name)
+		}
+	}
+
+	return nil
 }
 
 // GenerateSUToken returns a TokenWithExpiry with the `su` claim set to true.
@@ -0,0 +1,163 @@
+package authenticator
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	auth "github.com/pusher/pusher-platform-go/auth"
+)
+
+func newBenchmarkService() Service {
+	return NewService("instance-id", "key-id", "key-secret", Options{})
+}
+
+// decodeClaims returns the claims of a token without verifying it.
+func decodeClaims(t *testing.T, token string) map[string]interface{} {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		t.Fatalf("Expected a token with 3 segments, got %q", token)
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(segments[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := map[string]interface{}{}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	return claims
+}
+
+func TestAuthenticateAndGenerateAccessTokenClaimsMatch(t *testing.T) {
+	service := NewService("instance-id", "key-id", "key-secret", Options{AccessTokenExpiry: time.Hour})
+	userID := "alice"
+	tokenExpiry := 10 * time.Minute
+
+	for name, options := range map[string]auth.Options{
+		"user":           {UserID: &userID},
+		"su":             {Su: true},
+		"token expiry":   {UserID: &userID, TokenExpiry: &tokenExpiry},
+		"service claims": {UserID: &userID, ServiceClaims: map[string]interface{}{"tenant": "acme", "rooms": []string{"a", "b"}}},
+	} {
+		response, err := service.Authenticate(auth.Payload{GrantType: auth.GrantTypeClientCredentials}, options)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		authenticated := decodeClaims(t, response.TokenResponse().AccessToken)
+
+		generated, err := service.GenerateAccessToken(options)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if generated.ExpiresIn != response.TokenResponse().ExpiresIn {
+			t.Errorf("%s: expected ExpiresIn %v, got %v", name, response.TokenResponse().ExpiresIn, generated.ExpiresIn)
+		}
+		claims := decodeClaims(t, generated.Token)
+
+		// Both tokens carry a random ID, and may have been issued a second apart.
+		if _, ok := authenticated["jti"].(string); !ok {
+			t.Errorf("%s: expected a jti claim from Authenticate, got %v", name, authenticated["jti"])
+		}
+		if _, ok := claims["jti"].(string); !ok {
+			t.Errorf("%s: expected a jti claim from GenerateAccessToken, got %v", name, claims["jti"])
+		}
+		for _, timeClaim := range []string{"iat", "exp"} {
+			if math.Abs(claims[timeClaim].(float64)-authenticated[timeClaim].(float64)) > 1 {
+				t.Errorf("%s: expected %s %v, got %v", name, timeClaim, authenticated[timeClaim], claims[timeClaim])
+			}
+		}
+		for _, claim := range []string{"jti", "iat", "exp"} {
+			delete(claims, claim)
+			delete(authenticated, claim)
+		}
+
+		if !reflect.DeepEqual(claims, authenticated) {
+			t.Errorf("%s: expected claims %v, got %v", name, authenticated, claims)
+		}
+	}
+}
+
+func benchmarkGenerateAccessToken(b *testing.B, options auth.Options) {
+	service := newBenchmarkService()
+
+	// Check that the tokens being benchmarked are valid, so that a broken signer can't look fast.
+	token, err := service.GenerateAccessToken(options)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if _, err := service.VerifyToken(token.Token); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := service.GenerateAccessToken(options); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGenerateAccessToken(b *testing.B) {
+	userID := "alice"
+	benchmarkGenerateAccessToken(b, auth.Options{UserID: &userID})
+}
+
+func BenchmarkGenerateAccessTokenSU(b *testing.B) {
+	benchmarkGenerateAccessToken(b, auth.Options{Su: true})
+}
+
+func BenchmarkGenerateAccessTokenWithServiceClaims(b *testing.B) {
+	userID := "alice"
+	benchmarkGenerateAccessToken(b, auth.Options{
+		UserID: &userID,
+		ServiceClaims: map[string]interface{}{
+			"scope":    "read",
+			"room_ids": []string{"room-1", "room-2"},
+		},
+	})
+}
+
+func BenchmarkGenerateAccessTokenParallel(b *testing.B) {
+	service := newBenchmarkService()
+	userID := "alice"
+	options := auth.Options{UserID: &userID, Su: true}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := service.GenerateAccessToken(options); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkVerifyToken(b *testing.B) {
+	service := newBenchmarkService()
+	userID := "alice"
+	token, err := service.GenerateAccessToken(auth.Options{UserID: &userID})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := service.VerifyToken(token.Token); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
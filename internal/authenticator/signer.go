@@ -0,0 +1,151 @@
+package authenticator
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	auth "github.com/pusher/pusher-platform-go/auth"
+)
+
+// defaultTokenExpiry is the lifetime of tokens generated without a TokenExpiry, the same as the
+// platform's.
+const defaultTokenExpiry = 24 * time.Hour
+
+// tokenHeader is the encoded header segment of every token generated.
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// signer generates HS256 signed tokens for an instance's key, as the platform authenticator does.
+// The claims every token carries, identifying the instance and key, are encoded once, and HMAC
+// state keyed with the secret is pooled rather than set up again for every signature.
+type signer struct {
+	// Opening of the claims object, up to and including the `iss` claim.
+	claimsPrefix []byte
+	macs         sync.Pool
+}
+
+func newSigner(instanceID string, keyID string, keySecret string) *signer {
+	instance, _ := json.Marshal(instanceID)
+	issuer, _ := json.Marshal("api_keys/" + keyID)
+
+	key := []byte(keySecret)
+	return &signer{
+		claimsPrefix: []byte(`{"instance":` + string(instance) + `,"iss":` + string(issuer)),
+		macs: sync.Pool{
+			New: func() interface{} {
+				return hmac.New(sha256.New, key)
+			},
+		},
+	}
+}
+
+// generateAccessToken returns a token with the claims described by options and the `jti` claim
+// tokenID.
+func (s *signer) generateAccessToken(options auth.Options, tokenID string) (auth.TokenWithExpiry, error) {
+	expiry := defaultTokenExpiry
+	if options.TokenExpiry != nil {
+		expiry = *options.TokenExpiry
+	}
+
+	payload, err := s.encodeClaims(options, tokenID, time.Now(), expiry)
+	if err != nil {
+		return auth.TokenWithExpiry{}, err
+	}
+
+	encoding := base64.RawURLEncoding
+	token := make([]byte, 0, len(tokenHeader)+encoding.EncodedLen(len(payload))+encoding.EncodedLen(sha256.Size)+2)
+	token = append(token, tokenHeader...)
+	token = append(token, '.')
+	token = appendBase64(token, payload)
+
+	signature := s.sign(token)
+	token = append(token, '.')
+	token = appendBase64(token, signature)
+
+	return auth.TokenWithExpiry{
+		Token:     string(token),
+		ExpiresIn: expiry.Seconds(),
+	}, nil
+}
+
+// encodeClaims encodes the claims of a token as JSON. Service claims are encoded in order of
+// their names, so that the same options always give the same claims.
+func (s *signer) encodeClaims(
+	options auth.Options,
+	tokenID string,
+	now time.Time,
+	expiry time.Duration,
+) ([]byte, error) {
+	claims := make([]byte, 0, 256)
+	claims = append(claims, s.claimsPrefix...)
+	claims = append(claims, `,"iat":`...)
+	claims = strconv.AppendInt(claims, now.Unix(), 10)
+	claims = append(claims, `,"exp":`...)
+	claims = strconv.AppendInt(claims, now.Add(expiry).Unix(), 10)
+	claims = append(claims, `,"jti":`...)
+	claims = strconv.AppendQuote(claims, tokenID)
+
+	if options.UserID != nil {
+		sub, err := json.Marshal(*options.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to encode user ID: %v", err)
+		}
+		claims = append(claims, `,"sub":`...)
+		claims = append(claims, sub...)
+	}
+
+	if options.Su {
+		claims = append(claims, `,"su":true`...)
+	}
+
+	names := make([]string, 0, len(options.ServiceClaims))
+	for name := range options.ServiceClaims {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		encodedName, err := json.Marshal(name)
+		if err != nil {
+			return nil, fmt.Errorf("Failed to encode claim %q: %v", name, err)
+		}
+
+		value, err := json.Marshal(options.ServiceClaims[name])
+		if err != nil {
+			return nil, fmt.Errorf("Failed to encode claim %q: %v", name, err)
+		}
+
+		claims = append(claims, ',')
+		claims = append(claims, encodedName...)
+		claims = append(claims, ':')
+		claims = append(claims, value...)
+	}
+
+	return append(claims, '}'), nil
+}
+
+// sign returns the HS256 signature of data.
+func (s *signer) sign(data []byte) []byte {
+	mac := s.macs.Get().(hash.Hash)
+	mac.Reset()
+	mac.Write(data)
+	signature := mac.Sum(nil)
+	s.macs.Put(mac)
+
+	return signature
+}
+
+// appendBase64 appends the unpadded base64url encoding of src to dst.
+func appendBase64(dst []byte, src []byte) []byte {
+	n := len(dst)
+	dst = append(dst, make([]byte, base64.RawURLEncoding.EncodedLen(len(src)))...)
+	base64.RawURLEncoding.Encode(dst[n:], src)
+	return dst
+}
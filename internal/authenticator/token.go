@@ -0,0 +1,161 @@
+package authenticator
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrInvalidToken is returned when verifying a token that is malformed, was not signed with the
+// instance's key, or was issued for another instance.
+var ErrInvalidToken = errors.New("Invalid token")
+
+// ErrTokenExpired is returned when verifying a token that was valid but has expired.
+var ErrTokenExpired = errors.New("Token has expired")
+
+// Claims are the claims of a verified token.
+type Claims struct {
+	ID       string    // The `jti` claim, which identifies the token. Empty for tokens without one
+	UserID   string    // The `sub` claim. Empty for tokens not issued to a user
+	SU       bool      // Whether the token grants superuser access
+	IssuedAt time.Time // When the token was issued. Zero for tokens without an `iat` claim
+	Expiry   time.Time // When the token expires
+	// Permissions the token is restricted to, for tokens generated by GenerateScopedToken. Nil
+	// for tokens that aren't restricted, see Allows.
+	Permissions []string
+	// Any other claims the token carries, e.g. the ServiceClaims it was generated with.
+	ServiceClaims map[string]interface{}
+}
+
+// Allows reports whether the token grants permission. Tokens without a permissions claim aren't
+// restricted by it, while scoped tokens only grant their Permissions.
+func (c Claims) Allows(permission string) bool {
+	if c.Permissions == nil {
+		return true
+	}
+
+	for _, granted := range c.Permissions {
+		if granted == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+// permissionsClaim is the claim the permissions of a scoped token are carried in.
+const permissionsClaim = "permissions"
+
+// registeredClaims are the claims set by the platform. They can't be set with ServiceClaims, and
+// aren't returned as ServiceClaims.
+var registeredClaims = map[string]bool{
+	"instance": true,
+	"iss":      true,
+	"sub":      true,
+	"su":       true,
+	"iat":      true,
+	"exp":      true,
+	"nbf":      true,
+	"jti":      true,
+
+	permissionsClaim: true,
+}
+
+// VerifyToken checks that a token was signed with the instance's key secret (HS256), was issued
+// for the instance with its key and has not expired, allowing for the configured clock skew, and
+// returns its claims.
+func (a *authenticator) VerifyToken(tokenString string) (Claims, error) {
+	segments := strings.Split(tokenString, ".")
+	if len(segments) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(segments[0], &header); err != nil || header.Alg != "HS256" {
+		return Claims{}, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(segments[2])
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	signed := tokenString[:len(segments[0])+1+len(segments[1])]
+	if !hmac.Equal(signature, a.signer.sign([]byte(signed))) {
+		return Claims{}, ErrInvalidToken
+	}
+
+	var payload map[string]interface{}
+	if err := decodeSegment(segments[1], &payload); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if instance, _ := payload["instance"].(string); instance != a.instanceID {
+		return Claims{}, ErrInvalidToken
+	}
+
+	if issuer, _ := payload["iss"].(string); issuer != "api_keys/"+a.keyID {
+		return Claims{}, ErrInvalidToken
+	}
+
+	exp, ok := payload["exp"].(json.Number)
+	if !ok {
+		return Claims{}, ErrInvalidToken
+	}
+	expiry, err := exp.Int64()
+	if err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+
+	now := time.Now()
+	claims := Claims{Expiry: time.Unix(expiry, 0), ServiceClaims: map[string]interface{}{}}
+	if !now.Before(claims.Expiry.Add(a.options.ClockSkew)) {
+		return Claims{}, ErrTokenExpired
+	}
+
+	if iat, ok := payload["iat"].(json.Number); ok {
+		issuedAt, err := iat.Int64()
+		if err != nil || time.Unix(issuedAt, 0).After(now.Add(a.options.ClockSkew)) {
+			return Claims{}, ErrInvalidToken
+		}
+		claims.IssuedAt = time.Unix(issuedAt, 0)
+	}
+
+	claims.ID, _ = payload["jti"].(string)
+	claims.UserID, _ = payload["sub"].(string)
+	claims.SU, _ = payload["su"].(bool)
+	if permissions, ok := payload[permissionsClaim].([]interface{}); ok {
+		claims.Permissions = make([]string, 0, len(permissions))
+		for _, permission := range permissions {
+			if permission, ok := permission.(string); ok {
+				claims.Permissions = append(claims.Permissions, permission)
+			}
+		}
+	}
+	for name, value := range payload {
+		if !registeredClaims[name] {
+			claims.ServiceClaims[name] = value
+		}
+	}
+
+	return claims, nil
+}
+
+// decodeSegment decodes a base64url encoded JSON segment of a token. Numbers are decoded as
+// json.Number so that timestamps keep their precision.
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
@@ -15,12 +15,12 @@ import (
 	"github.com/pusher/chatkit-server-go/internal/common"
 
 	"github.com/pusher/pusher-platform-go/client"
-	"github.com/pusher/pusher-platform-go/instance"
 )
 
+// Scopes of roles. Global roles apply to every room; room roles to the rooms they are assigned in.
 const (
-	scopeGlobal = "global"
-	scopeRoom   = "room"
+	ScopeGlobal = "global"
+	ScopeRoom   = "room"
 )
 
 // Exposes methods to interact with the roles and permissions API.
@@ -55,16 +55,19 @@ type Service interface {
 
 	// Generic requests
 	Request(ctx context.Context, options client.RequestOptions) (*http.Response, error)
+	Subscribe(ctx context.Context, options client.RequestOptions) (*common.SubscriptionStream, error)
 }
 
 type authorizerService struct {
-	underlyingInstance instance.Instance
+	underlyingInstance common.Requester
+	decoder            common.Decoder
 }
 
 // Returns an new authorizerService instance conforming to the Service interface.
-func NewService(platformInstance instance.Instance) Service {
+func NewService(platformInstance common.Requester, decoder common.Decoder) Service {
 	return &authorizerService{
 		underlyingInstance: platformInstance,
+		decoder:            decoder,
 	}
 }
 
@@ -80,7 +83,7 @@ func (as *authorizerService) GetRoles(ctx context.Context) ([]Role, error) {
 	defer response.Body.Close()
 
 	var roles []Role
-	err = common.DecodeResponseBody(response.Body, &roles)
+	err = as.decoder.Decode(response.Body, &roles)
 	if err != nil {
 		return nil, err
 	}
@@ -93,7 +96,7 @@ func (as *authorizerService) CreateGlobalRole(ctx context.Context, options Creat
 	return as.createRole(ctx, Role{
 		Name:        options.Name,
 		Permissions: options.Permissions,
-		Scope:       scopeGlobal,
+		Scope:       ScopeGlobal,
 	})
 }
 
@@ -102,7 +105,7 @@ func (as *authorizerService) CreateRoomRole(ctx context.Context, options CreateR
 	return as.createRole(ctx, Role{
 		Name:        options.Name,
 		Permissions: options.Permissions,
-		Scope:       scopeRoom,
+		Scope:       ScopeRoom,
 	})
 }
 
@@ -116,6 +119,10 @@ func (as *authorizerService) createRole(ctx context.Context, role Role) error {
 		return errors.New("You must provide permissions of the role")
 	}
 
+	if err := ValidatePermissions(role.Permissions); err != nil {
+		return err
+	}
+
 	requestBody, err := common.CreateRequestBody(&role)
 	if err != nil {
 		return err
@@ -136,19 +143,19 @@ func (as *authorizerService) createRole(ctx context.Context, role Role) error {
 
 // DeleteGlobalRole deletes a role with the given name at the global scope.
 func (as *authorizerService) DeleteGlobalRole(ctx context.Context, roleName string) error {
-	return as.deleteRole(ctx, roleName, scopeGlobal)
+	return as.deleteRole(ctx, roleName, ScopeGlobal)
 }
 
 // DeleteRoomRole deletes a role with the given name at the room scope.
 func (as *authorizerService) DeleteRoomRole(ctx context.Context, roleName string) error {
-	return as.deleteRole(ctx, roleName, scopeRoom)
+	return as.deleteRole(ctx, roleName, ScopeRoom)
 }
 
 // deleteRole is used by DeleteGlobalRole and DeleteRoomRole.
 func (as *authorizerService) deleteRole(ctx context.Context, roleName string, scope string) error {
 	response, err := common.RequestWithSuToken(as.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodDelete,
-		Path:   fmt.Sprintf("/roles/%s/scope/%s", roleName, scope),
+		Path:   fmt.Sprintf("/roles/%s/scope/%s", url.PathEscape(roleName), scope),
 	})
 	if err != nil {
 		return err
@@ -163,7 +170,7 @@ func (as *authorizerService) GetPermissionsForGlobalRole(
 	ctx context.Context,
 	roleName string,
 ) ([]string, error) {
-	return as.getPermissions(ctx, roleName, scopeGlobal)
+	return as.getPermissions(ctx, roleName, ScopeGlobal)
 }
 
 // GetPermissionsForRoomRole retrieves a list of permissions associated with the role.
@@ -171,7 +178,7 @@ func (as *authorizerService) GetPermissionsForRoomRole(
 	ctx context.Context,
 	roleName string,
 ) ([]string, error) {
-	return as.getPermissions(ctx, roleName, scopeRoom)
+	return as.getPermissions(ctx, roleName, ScopeRoom)
 }
 
 // getPermissions is used by GetPermissionsForGlobalRole and GetPermissionsForRoomRole.
@@ -182,7 +189,7 @@ func (as *authorizerService) getPermissions(
 ) ([]string, error) {
 	response, err := common.RequestWithSuToken(as.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/roles/%s/scope/%s/permissions", roleName, scope),
+		Path:   fmt.Sprintf("/roles/%s/scope/%s/permissions", url.PathEscape(roleName), scope),
 	})
 	if err != nil {
 		return nil, err
@@ -190,7 +197,7 @@ func (as *authorizerService) getPermissions(
 	defer response.Body.Close()
 
 	var rolePermissions []string
-	err = common.DecodeResponseBody(response.Body, &rolePermissions)
+	err = as.decoder.Decode(response.Body, &rolePermissions)
 	if err != nil {
 		return nil, err
 	}
@@ -204,7 +211,7 @@ func (as *authorizerService) UpdatePermissionsForGlobalRole(
 	roleName string,
 	options UpdateRolePermissionsOptions,
 ) error {
-	return as.updatePermissions(ctx, roleName, options, scopeGlobal)
+	return as.updatePermissions(ctx, roleName, options, ScopeGlobal)
 }
 
 // UpdatePermissionsForRoomRole allows updating permissions associated with a room role.
@@ -213,7 +220,7 @@ func (as *authorizerService) UpdatePermissionsForRoomRole(
 	roleName string,
 	options UpdateRolePermissionsOptions,
 ) error {
-	return as.updatePermissions(ctx, roleName, options, scopeRoom)
+	return as.updatePermissions(ctx, roleName, options, ScopeRoom)
 }
 
 // updatePermissions is used by UpdatePermissionsForGlobalRole and UpdatePermissionsForRoomRole.
@@ -228,6 +235,10 @@ func (as *authorizerService) updatePermissions(
 		return errors.New("PermissionsToAdd and PermissionsToRemove cannot both be empty")
 	}
 
+	if err := ValidatePermissions(options.PermissionsToAdd); err != nil {
+		return err
+	}
+
 	requestBody, err := common.CreateRequestBody(&options)
 	if err != nil {
 		return err
@@ -235,7 +246,7 @@ func (as *authorizerService) updatePermissions(
 
 	response, err := common.RequestWithSuToken(as.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodPut,
-		Path:   fmt.Sprintf("/roles/%s/scope/%s/permissions", roleName, scope),
+		Path:   fmt.Sprintf("/roles/%s/scope/%s/permissions", url.PathEscape(roleName), scope),
 		Body:   requestBody,
 	})
 	if err != nil {
@@ -254,7 +265,7 @@ func (as *authorizerService) GetUserRoles(ctx context.Context, userID string) ([
 
 	response, err := common.RequestWithSuToken(as.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/users/%s/roles", userID),
+		Path:   fmt.Sprintf("/users/%s/roles", url.PathEscape(userID)),
 	})
 	if err != nil {
 		return nil, err
@@ -262,7 +273,7 @@ func (as *authorizerService) GetUserRoles(ctx context.Context, userID string) ([
 	defer response.Body.Close()
 
 	var roles []Role
-	err = common.DecodeResponseBody(response.Body, &roles)
+	err = as.decoder.Decode(response.Body, &roles)
 	if err != nil {
 		return nil, err
 	}
@@ -286,6 +297,10 @@ func (as *authorizerService) AssignRoomRoleToUser(
 	roomID string,
 	roleName string,
 ) error {
+	if roomID == "" {
+		return errors.New("You must provide the ID of the room you want to assign a role in")
+	}
+
 	return as.assignRoleToUser(ctx, userID, roleName, &roomID)
 }
 
@@ -311,7 +326,7 @@ func (as *authorizerService) assignRoleToUser(
 
 	response, err := common.RequestWithSuToken(as.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodPut,
-		Path:   fmt.Sprintf("/users/%s/roles", userID),
+		Path:   fmt.Sprintf("/users/%s/roles", url.PathEscape(userID)),
 		Body:   requestBody,
 	})
 	if err != nil {
@@ -335,6 +350,10 @@ func (as *authorizerService) RemoveRoomRoleForUser(
 	userID string,
 	roomID string,
 ) error {
+	if roomID == "" {
+		return errors.New("You must provide the ID of the room you want to remove a role in")
+	}
+
 	return as.removeRoleForUser(ctx, userID, &roomID)
 }
 
@@ -355,7 +374,7 @@ func (as *authorizerService) removeRoleForUser(
 
 	response, err := common.RequestWithSuToken(as.underlyingInstance, ctx, client.RequestOptions{
 		Method:      http.MethodDelete,
-		Path:        fmt.Sprintf("/users/%s/roles", userID),
+		Path:        fmt.Sprintf("/users/%s/roles", url.PathEscape(userID)),
 		QueryParams: &queryParams,
 	})
 	if err != nil {
@@ -373,3 +392,11 @@ func (as *authorizerService) Request(
 ) (*http.Response, error) {
 	return as.underlyingInstance.Request(ctx, options)
 }
+
+// Subscribe opens a subscription to the authorizer service with a token with the `su` claim.
+func (as *authorizerService) Subscribe(
+	ctx context.Context,
+	options client.RequestOptions,
+) (*common.SubscriptionStream, error) {
+	return common.SubscribeWithSuToken(as.underlyingInstance, ctx, options)
+}
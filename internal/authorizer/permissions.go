@@ -0,0 +1,66 @@
+package authorizer
+
+import "fmt"
+
+// Permissions that can be granted by a role.
+const (
+	PermissionRoomCreate              = "room:create"
+	PermissionRoomDelete              = "room:delete"
+	PermissionRoomUpdate              = "room:update"
+	PermissionRoomGet                 = "room:get"
+	PermissionRoomJoin                = "room:join"
+	PermissionRoomLeave               = "room:leave"
+	PermissionRoomMembersAdd          = "room:members:add"
+	PermissionRoomMembersRemove       = "room:members:remove"
+	PermissionRoomMessagesGet         = "room:messages:get"
+	PermissionRoomTypingIndicatorSend = "room:typing_indicator:create"
+	PermissionMessageCreate           = "message:create"
+	PermissionPresenceSubscribe       = "presence:subscribe"
+	PermissionUserGet                 = "user:get"
+	PermissionUserRoomsGet            = "user:rooms:get"
+	PermissionFileCreate              = "file:create"
+	PermissionFileGet                 = "file:get"
+	PermissionCursorsReadGet          = "cursors:read:get"
+	PermissionCursorsReadSet          = "cursors:read:set"
+)
+
+// Permissions lists every permission that can be granted by a role.
+var Permissions = []string{
+	PermissionRoomCreate,
+	PermissionRoomDelete,
+	PermissionRoomUpdate,
+	PermissionRoomGet,
+	PermissionRoomJoin,
+	PermissionRoomLeave,
+	PermissionRoomMembersAdd,
+	PermissionRoomMembersRemove,
+	PermissionRoomMessagesGet,
+	PermissionRoomTypingIndicatorSend,
+	PermissionMessageCreate,
+	PermissionPresenceSubscribe,
+	PermissionUserGet,
+	PermissionUserRoomsGet,
+	PermissionFileCreate,
+	PermissionFileGet,
+	PermissionCursorsReadGet,
+	PermissionCursorsReadSet,
+}
+
+var knownPermissions = func() map[string]bool {
+	known := make(map[string]bool, len(Permissions))
+	for _, permission := range Permissions {
+		known[permission] = true
+	}
+	return known
+}()
+
+// ValidatePermissions returns an error naming the first of permissions that is not known.
+func ValidatePermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !knownPermissions[permission] {
+			return fmt.Errorf("Unknown permission: %q", permission)
+		}
+	}
+
+	return nil
+}
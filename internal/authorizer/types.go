@@ -1,22 +1,29 @@
 package authorizer
 
-import "encoding/json"
+import (
+	"encoding/json"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+)
 
 // Role represents a chatkit authorizer role.
 type Role struct {
-	Name        string   `json:"name"`        // Name of new role
-	Permissions []string `json:"permissions"` // List of permissions for role
-	Scope       string   `json:"scope"`       // Scope of the new role (global or room)
+	Name        string   `json:"name" yaml:"name"`               // Name of new role
+	Permissions []string `json:"permissions" yaml:"permissions"` // List of permissions for role
+	Scope       string   `json:"scope" yaml:"scope"`             // Scope of the new role (global or room)
+	// Room a room scoped role is assigned to a user in. Only set on roles returned by GetUserRoles.
+	RoomID string `json:"room_id,omitempty" yaml:"room_id,omitempty"`
 }
 
 func (r *Role) UnmarshalJSON(b []byte) error {
 	// Custom unmarshal logic because some routes give us a "name" and some
 	// give us a "role_name".
 	var raw struct {
-		Name        string   `json:"name"`
-		RoleName    string   `json:"role_name"`
-		Permissions []string `json:"permissions"`
-		Scope       string   `json:"scope"`
+		Name        string          `json:"name"`
+		RoleName    string          `json:"role_name"`
+		Permissions []string        `json:"permissions"`
+		Scope       string          `json:"scope"`
+		RoomID      json.RawMessage `json:"room_id"`
 	}
 
 	if err := json.Unmarshal(b, &raw); err != nil {
@@ -27,10 +34,16 @@ func (r *Role) UnmarshalJSON(b []byte) error {
 		raw.Name = raw.RoleName
 	}
 
+	roomID, err := common.UnmarshalID(raw.RoomID)
+	if err != nil {
+		return err
+	}
+
 	*r = Role{
 		Name:        raw.Name,
 		Permissions: raw.Permissions,
 		Scope:       raw.Scope,
+		RoomID:      roomID,
 	}
 
 	return nil
@@ -53,6 +66,29 @@ type UserRole struct {
 	RoomID *string `json:"room_id,omitempty"` // Optional room id. If empty, the scope is global
 }
 
+func (r *UserRole) UnmarshalJSON(b []byte) error {
+	// Custom unmarshal logic because room IDs may be given to us as numbers.
+	var raw struct {
+		Name   string          `json:"name"`
+		RoomID json.RawMessage `json:"room_id"`
+	}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	*r = UserRole{Name: raw.Name}
+	if len(raw.RoomID) > 0 && string(raw.RoomID) != "null" {
+		roomID, err := common.UnmarshalID(raw.RoomID)
+		if err != nil {
+			return err
+		}
+		r.RoomID = &roomID
+	}
+
+	return nil
+}
+
 // UpdateRolePermissionsOptions contains permissions to add/remove
 // permissions to/ from a role.
 type UpdateRolePermissionsOptions struct {
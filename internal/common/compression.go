@@ -0,0 +1,22 @@
+package common
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+)
+
+// Compress returns body gzipped.
+func Compress(body []byte) ([]byte, error) {
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(body); err != nil {
+		return nil, fmt.Errorf("Failed to compress request body: %v", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("Failed to compress request body: %v", err)
+	}
+
+	return compressed.Bytes(), nil
+}
@@ -7,17 +7,68 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/pusher/pusher-platform-go/auth"
 	"github.com/pusher/pusher-platform-go/client"
-	"github.com/pusher/pusher-platform-go/instance"
 )
 
 // DecodeResponseBody takes an io.Reader and decodes the body into a destination struct
 func DecodeResponseBody(body io.Reader, dest interface{}) error {
-	decoder := json.NewDecoder(body)
-	err := decoder.Decode(dest)
+	return Decoder{}.Decode(body, dest)
+}
+
+// UnknownField describes a field in a response body that the destination has no field for.
+type UnknownField struct {
+	Type  string // Go type the response was decoded into
+	Field string // Name of the unknown JSON field
+}
+
+// Decoder decodes response bodies, optionally checking for fields that would be dropped.
+type Decoder struct {
+	// Strict makes decoding fail when the response contains a field the destination lacks.
+	Strict bool
+	// OnUnknownField is called when a response contains a field the destination lacks and
+	// decoding is not strict. Only the first unknown field of each response is reported.
+	OnUnknownField func(UnknownField)
+}
+
+// Decode takes an io.Reader and decodes the body into a destination struct
+func (d Decoder) Decode(body io.Reader, dest interface{}) error {
+	if !d.Strict && d.OnUnknownField == nil {
+		err := json.NewDecoder(body).Decode(dest)
+		if err != nil {
+			return fmt.Errorf("Failed to decode response body: %s", err.Error())
+		}
+
+		return nil
+	}
+
+	bodyBytes, err := ioutil.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("Failed to read response body: %s", err.Error())
+	}
+
+	err = newStrictDecoder(bytes.NewReader(bodyBytes)).Decode(dest)
+	if err == nil {
+		return nil
+	}
+
+	field, isUnknownField := unknownFieldName(err)
+	if d.Strict || !isUnknownField {
+		return fmt.Errorf("Failed to decode response body: %s", err.Error())
+	}
+
+	d.OnUnknownField(UnknownField{
+		Type:  reflect.TypeOf(dest).Elem().String(),
+		Field: field,
+	})
+
+	err = json.Unmarshal(bodyBytes, dest)
 	if err != nil {
 		return fmt.Errorf("Failed to decode response body: %s", err.Error())
 	}
@@ -25,7 +76,49 @@ func DecodeResponseBody(body io.Reader, dest interface{}) error {
 	return nil
 }
 
-// CreateRequestBody takes a struct/ map and converts it into an io.Reader
+// unknownFieldName extracts the name of the field from the error returned by a json.Decoder
+// that disallows unknown fields.
+func unknownFieldName(err error) (string, bool) {
+	const prefix = "json: unknown field "
+	message := err.Error()
+	if !strings.HasPrefix(message, prefix) {
+		return "", false
+	}
+
+	field, unquoteErr := strconv.Unquote(strings.TrimPrefix(message, prefix))
+	if unquoteErr != nil {
+		return strings.TrimPrefix(message, prefix), true
+	}
+
+	return field, true
+}
+
+// UnmarshalID decodes an ID that may be encoded as either a JSON string or a JSON number, since
+// room IDs were numbers in earlier versions of the API. A null ID decodes to "".
+func UnmarshalID(raw json.RawMessage) (string, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return "", nil
+	}
+
+	if raw[0] == '"' {
+		var id string
+		err := json.Unmarshal(raw, &id)
+		return id, err
+	}
+
+	var id json.Number
+	if err := json.Unmarshal(raw, &id); err != nil {
+		return "", fmt.Errorf("Failed to decode ID %s: %v", raw, err)
+	}
+
+	return id.String(), nil
+}
+
+// CreateRequestBody takes a struct/ map and converts it into an io.Reader.
+// The reader is a *bytes.Reader, so that net/http sets the Content-Length of requests and can
+// send them again on a new connection. It owns its bytes for that reason, so they aren't pooled:
+// encoding/json pools its encoding buffers already.
 func CreateRequestBody(target interface{}) (io.Reader, error) {
 	bodyBytes, err := json.Marshal(target)
 	if err != nil {
@@ -35,8 +128,16 @@ func CreateRequestBody(target interface{}) (io.Reader, error) {
 	return bytes.NewReader(bodyBytes), nil
 }
 
+// Requester makes requests to a Chatkit service, with tokens it generates. It is the part of an
+// instance.Instance that the services and request helpers depend on, so that their tests can
+// stub the transport.
+type Requester interface {
+	Request(ctx context.Context, options client.RequestOptions) (*http.Response, error)
+	GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error)
+}
+
 // generateTokenFromInstance generates a token with the given options.
-func generateTokenFromInstance(inst instance.Instance, options auth.Options) (string, error) {
+func generateTokenFromInstance(inst Requester, options auth.Options) (string, error) {
 	tokenWithExpiry, err := inst.GenerateAccessToken(options)
 	if err != nil {
 		return "", fmt.Errorf("Failed to generate token: %s", err.Error())
@@ -48,7 +149,7 @@ func generateTokenFromInstance(inst instance.Instance, options auth.Options) (st
 // RequestWithToken makes a request and includes token generation as a part of it.
 // It generates a token with the `su` claim.
 func RequestWithSuToken(
-	inst instance.Instance,
+	inst Requester,
 	ctx context.Context,
 	options client.RequestOptions,
 ) (*http.Response, error) {
@@ -69,7 +170,7 @@ func RequestWithSuToken(
 
 // RequestWithUserToken makes a request and includes the user id as part of the `sub` claim.
 func RequestWithUserToken(
-	inst instance.Instance,
+	inst Requester,
 	ctx context.Context,
 	userID string,
 	options client.RequestOptions,
@@ -88,3 +189,27 @@ func RequestWithUserToken(
 		Jwt:         &token,
 	})
 }
+
+// RequestAsUser makes a request on behalf of a user.
+// Unlike RequestWithUserToken the token does not have the `su` claim, so the request is subject
+// to the user's permissions.
+func RequestAsUser(
+	inst Requester,
+	ctx context.Context,
+	userID string,
+	options client.RequestOptions,
+) (*http.Response, error) {
+	token, err := generateTokenFromInstance(inst, auth.Options{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return inst.Request(ctx, client.RequestOptions{
+		Method:      options.Method,
+		Path:        options.Path,
+		Body:        options.Body,
+		Headers:     options.Headers,
+		QueryParams: options.QueryParams,
+		Jwt:         &token,
+	})
+}
@@ -0,0 +1,74 @@
+package common
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+type testMessage struct {
+	SenderID string `json:"sender_id"`
+	Text     string `json:"text"`
+}
+
+func TestCreateRequestBodyIsSizedAndRewindable(t *testing.T) {
+	body, err := CreateRequestBody(testMessage{SenderID: "alice", Text: "hello"})
+	if err != nil {
+		t.Fatalf("Failed to create request body: %v", err)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, "https://example.com/messages", body)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+
+	const expected = `{"sender_id":"alice","text":"hello"}`
+	if request.ContentLength != int64(len(expected)) {
+		t.Errorf("Expected a Content-Length of %d, got %d", len(expected), request.ContentLength)
+	}
+
+	if request.GetBody == nil {
+		t.Fatal("Expected the request body to be rewindable")
+	}
+
+	for i := 0; i < 2; i++ {
+		replay, err := request.GetBody()
+		if err != nil {
+			t.Fatalf("Failed to rewind request body: %v", err)
+		}
+
+		sent, _ := ioutil.ReadAll(replay)
+		if string(sent) != expected {
+			t.Errorf("Expected body %s, got %s", expected, sent)
+		}
+	}
+}
+
+func BenchmarkCreateRequestBody(b *testing.B) {
+	message := testMessage{SenderID: "alice", Text: "hello there, this is a message of a typical length"}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		body, err := CreateRequestBody(message)
+		if err != nil {
+			b.Fatal(err)
+		}
+		ioutil.ReadAll(body)
+	}
+}
+
+func TestDecodeKnownFields(t *testing.T) {
+	body := `{"sender_id":"alice","text":"hello"}`
+	for _, decoder := range []Decoder{{}, {Strict: true}, {OnUnknownField: func(UnknownField) {
+		t.Errorf("Expected no unknown fields to be reported")
+	}}} {
+		var message testMessage
+		if err := decoder.Decode(strings.NewReader(body), &message); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if message.SenderID != "alice" || message.Text != "hello" {
+			t.Fatalf("Expected the message to be decoded, got %+v", message)
+		}
+	}
+}
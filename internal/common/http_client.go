@@ -0,0 +1,69 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pusher/pusher-platform-go/client"
+)
+
+type httpClient struct {
+	host       string
+	underlying *http.Client
+}
+
+// NewHTTPClient returns a client that makes requests to a host with c, for when requests must be
+// made with a particular *http.Client, which the platform client can't be given. Requests are made
+// as the platform client makes them: over HTTPS, with a JSON Content-Type, and with responses with
+// a status other than 2xx returned as ErrorResponses whose Info is their decoded JSON body.
+// TestHTTPClientMatchesThePlatformClient checks that they stay the same.
+func NewHTTPClient(host string, c *http.Client) client.Client {
+	return &httpClient{host: host, underlying: c}
+}
+
+func (c *httpClient) Request(ctx context.Context, options client.RequestOptions) (*http.Response, error) {
+	target := "https://" + c.host + options.Path
+	if options.QueryParams != nil && len(*options.QueryParams) > 0 {
+		separator := "?"
+		if strings.Contains(target, "?") {
+			separator = "&"
+		}
+		target += separator + options.QueryParams.Encode()
+	}
+
+	request, err := http.NewRequest(options.Method, target, options.Body)
+	if err != nil {
+		return nil, err
+	}
+	request = request.WithContext(ctx)
+
+	request.Header.Set("Content-Type", "application/json")
+	for name, values := range options.Headers {
+		request.Header[name] = values
+	}
+	if options.Jwt != nil {
+		request.Header.Set("Authorization", "Bearer "+*options.Jwt)
+	}
+
+	response, err := c.underlying.Do(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return response, nil
+	}
+
+	defer response.Body.Close()
+
+	var info interface{}
+	json.NewDecoder(response.Body).Decode(&info)
+
+	return nil, &client.ErrorResponse{
+		Status:  response.StatusCode,
+		Headers: response.Header,
+		Info:    info,
+	}
+}
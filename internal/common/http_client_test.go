@@ -0,0 +1,112 @@
+package common
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pusher/pusher-platform-go/client"
+)
+
+// sentRequest is what a client sent, as seen by the transport.
+type sentRequest struct {
+	method string
+	url    string
+	header http.Header
+	body   string
+}
+
+// cannedTransport records the requests sent through it and responds to them with status and body.
+type cannedTransport struct {
+	status   int
+	body     string
+	requests []sentRequest
+}
+
+func (t *cannedTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	body := ""
+	if request.Body != nil {
+		bodyBytes, _ := ioutil.ReadAll(request.Body)
+		request.Body.Close()
+		body = string(bodyBytes)
+	}
+
+	t.requests = append(t.requests, sentRequest{
+		method: request.Method,
+		url:    request.URL.String(),
+		header: request.Header,
+		body:   body,
+	})
+
+	return &http.Response{
+		StatusCode: t.status,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(strings.NewReader(t.body)),
+		Request:    request,
+	}, nil
+}
+
+// sendThroughDefaultTransport makes a request with c, with http.DefaultTransport, which the
+// platform client sends its requests through, replaced by transport.
+func sendThroughDefaultTransport(
+	c client.Client,
+	transport *cannedTransport,
+	options client.RequestOptions,
+) (*http.Response, error) {
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = transport
+	defer func() { http.DefaultTransport = defaultTransport }()
+
+	return c.Request(context.Background(), options)
+}
+
+func TestHTTPClientMatchesThePlatformClient(t *testing.T) {
+	const host = "us1.pusherplatform.io"
+	jwt := "token"
+	query := url.Values{"limit": {"10"}}
+	newOptions := func() client.RequestOptions {
+		body, _ := CreateRequestBody(map[string]string{"name": "general"})
+		return client.RequestOptions{
+			Method:      http.MethodPost,
+			Path:        "/services/chatkit/v6/instance/rooms",
+			Jwt:         &jwt,
+			Headers:     http.Header{"X-Custom": {"value"}},
+			Body:        body,
+			QueryParams: &query,
+		}
+	}
+
+	for _, status := range []int{http.StatusCreated, http.StatusBadRequest} {
+		platformTransport := &cannedTransport{status: status, body: `{"error":"services/chatkit/bad_request"}`}
+		platformResponse, platformErr := sendThroughDefaultTransport(
+			client.New(client.Options{Host: host}),
+			platformTransport,
+			newOptions(),
+		)
+
+		httpTransport := &cannedTransport{status: status, body: `{"error":"services/chatkit/bad_request"}`}
+		httpResponse, httpErr := sendThroughDefaultTransport(
+			NewHTTPClient(host, &http.Client{}),
+			httpTransport,
+			newOptions(),
+		)
+
+		if len(platformTransport.requests) != 1 {
+			t.Fatalf("Expected the platform client to send 1 request through http.DefaultTransport, got %d", len(platformTransport.requests))
+		}
+		if !reflect.DeepEqual(httpTransport.requests, platformTransport.requests) {
+			t.Errorf("Expected the request %+v the platform client sends, got %+v", platformTransport.requests, httpTransport.requests)
+		}
+
+		if (platformResponse == nil) != (httpResponse == nil) {
+			t.Errorf("Expected response %v for status %d, got %v", platformResponse, status, httpResponse)
+		}
+		if !reflect.DeepEqual(httpErr, platformErr) {
+			t.Errorf("Expected error %#v for status %d, got %#v", platformErr, status, httpErr)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package common
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pusher/pusher-platform-go/client"
+)
+
+// Invoker performs a request to a Chatkit service.
+type Invoker func(ctx context.Context, options client.RequestOptions) (*http.Response, error)
+
+// Interceptor wraps the requests made to Chatkit services, and calls next to perform them.
+type Interceptor func(ctx context.Context, options client.RequestOptions, next Invoker) (*http.Response, error)
+
+type interceptingClient struct {
+	invoke Invoker
+}
+
+// NewInterceptingClient returns a client that makes requests with c through interceptors, the
+// first of which is the outermost.
+func NewInterceptingClient(c client.Client, interceptors ...Interceptor) client.Client {
+	invoke := Invoker(c.Request)
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, options client.RequestOptions) (*http.Response, error) {
+			return interceptor(ctx, options, next)
+		}
+	}
+
+	return &interceptingClient{invoke: invoke}
+}
+
+func (c *interceptingClient) Request(ctx context.Context, options client.RequestOptions) (*http.Response, error) {
+	return c.invoke(ctx, options)
+}
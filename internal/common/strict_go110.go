@@ -0,0 +1,16 @@
+//go:build go1.10
+// +build go1.10
+
+package common
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// newStrictDecoder returns a json.Decoder that fails on fields the destination lacks.
+func newStrictDecoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	return decoder
+}
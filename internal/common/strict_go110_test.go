@@ -0,0 +1,38 @@
+//go:build go1.10
+// +build go1.10
+
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeStrictRejectsUnknownFields(t *testing.T) {
+	var message testMessage
+	err := Decoder{Strict: true}.Decode(strings.NewReader(`{"sender_id":"alice","colour":"red"}`), &message)
+	if err == nil || !strings.Contains(err.Error(), "colour") {
+		t.Fatalf("Expected an error naming the unknown field, got %v", err)
+	}
+}
+
+func TestDecodeReportsUnknownFields(t *testing.T) {
+	var reported []UnknownField
+	decoder := Decoder{OnUnknownField: func(field UnknownField) {
+		reported = append(reported, field)
+	}}
+
+	var message testMessage
+	err := decoder.Decode(strings.NewReader(`{"sender_id":"alice","colour":"red"}`), &message)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if message.SenderID != "alice" {
+		t.Fatalf("Expected the known fields to be decoded, got %+v", message)
+	}
+
+	expected := UnknownField{Type: "common.testMessage", Field: "colour"}
+	if len(reported) != 1 || reported[0] != expected {
+		t.Fatalf("Expected %+v to be reported, got %+v", expected, reported)
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !go1.10
+// +build !go1.10
+
+package common
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// newStrictDecoder returns a json.Decoder for decoding strictly. Detecting unknown fields relies
+// on json.Decoder.DisallowUnknownFields, added in Go 1.10, so before then unknown fields are
+// dropped as they are when decoding isn't strict.
+func newStrictDecoder(r io.Reader) *json.Decoder {
+	return json.NewDecoder(r)
+}
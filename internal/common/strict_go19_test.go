@@ -0,0 +1,29 @@
+//go:build !go1.10
+// +build !go1.10
+
+package common
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDecodeStrictDropsUnknownFieldsBeforeGo110(t *testing.T) {
+	reported := false
+	for _, decoder := range []Decoder{{Strict: true}, {OnUnknownField: func(UnknownField) {
+		reported = true
+	}}} {
+		var message testMessage
+		err := decoder.Decode(strings.NewReader(`{"sender_id":"alice","colour":"red"}`), &message)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if message.SenderID != "alice" {
+			t.Fatalf("Expected the known fields to be decoded, got %+v", message)
+		}
+	}
+
+	if reported {
+		t.Fatalf("Expected no unknown fields to be reported")
+	}
+}
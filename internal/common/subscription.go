@@ -0,0 +1,205 @@
+package common
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pusher/pusher-platform-go/auth"
+	"github.com/pusher/pusher-platform-go/client"
+)
+
+// MethodSubscribe is the HTTP method platform subscriptions are requested with.
+const MethodSubscribe = "SUBSCRIBE"
+
+// Types of the messages of a subscription, which are newline delimited JSON arrays starting with
+// their type.
+const (
+	messageTypeKeepAlive         = 0
+	messageTypeEvent             = 1
+	messageTypeEndOfSubscription = 255
+)
+
+// SubscriptionEvent is an event received on a subscription.
+type SubscriptionEvent struct {
+	ID      string            // Used to resume the subscription after this event
+	Headers map[string]string // Headers of the event
+	Body    json.RawMessage   // Body of the event, as sent by the service
+}
+
+// EndOfSubscriptionError is returned when a service ends a subscription.
+type EndOfSubscriptionError struct {
+	Status  int               // Status code, as for an HTTP response
+	Headers map[string]string // Headers of the end of subscription message
+	Info    json.RawMessage   // Description of why the subscription ended
+}
+
+func (e *EndOfSubscriptionError) Error() string {
+	return fmt.Sprintf("Subscription ended with status %d: %s", e.Status, e.Info)
+}
+
+// SubscriptionStream reads the events of a platform subscription.
+type SubscriptionStream struct {
+	body   io.ReadCloser
+	reader *bufio.Reader
+}
+
+// NewSubscriptionStream returns a stream reading the events of a subscription from its response
+// body.
+func NewSubscriptionStream(body io.ReadCloser) *SubscriptionStream {
+	return &SubscriptionStream{body: body, reader: bufio.NewReader(body)}
+}
+
+// Next blocks until the next event is received and returns it. Keep-alive messages are skipped.
+// It returns an *EndOfSubscriptionError when the service ends the subscription, and io.EOF if
+// the connection was closed without one.
+func (s *SubscriptionStream) Next() (SubscriptionEvent, error) {
+	for {
+		line, err := s.reader.ReadBytes('\n')
+		if err == io.EOF && len(bytes.TrimSpace(line)) > 0 {
+			err = nil
+		}
+		if err != nil {
+			return SubscriptionEvent{}, err
+		}
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var message []json.RawMessage
+		if err := json.Unmarshal(line, &message); err != nil || len(message) == 0 {
+			return SubscriptionEvent{}, fmt.Errorf("Failed to decode subscription message: %s", line)
+		}
+
+		var messageType int
+		if err := json.Unmarshal(message[0], &messageType); err != nil {
+			return SubscriptionEvent{}, fmt.Errorf("Failed to decode subscription message: %s", line)
+		}
+
+		switch messageType {
+		case messageTypeKeepAlive:
+			continue
+		case messageTypeEvent:
+			var event SubscriptionEvent
+			if len(message) != 4 ||
+				json.Unmarshal(message[1], &event.ID) != nil ||
+				json.Unmarshal(message[2], &event.Headers) != nil {
+				return SubscriptionEvent{}, fmt.Errorf("Failed to decode subscription event: %s", line)
+			}
+			event.Body = message[3]
+			return event, nil
+		case messageTypeEndOfSubscription:
+			eos := &EndOfSubscriptionError{}
+			if len(message) != 4 ||
+				json.Unmarshal(message[1], &eos.Status) != nil ||
+				json.Unmarshal(message[2], &eos.Headers) != nil {
+				return SubscriptionEvent{}, fmt.Errorf("Failed to decode end of subscription: %s", line)
+			}
+			eos.Info = message[3]
+			return SubscriptionEvent{}, eos
+		default:
+			return SubscriptionEvent{}, fmt.Errorf("Unknown subscription message type %d", messageType)
+		}
+	}
+}
+
+// Close closes the subscription.
+func (s *SubscriptionStream) Close() error {
+	return s.body.Close()
+}
+
+// SubscribeWithSuToken opens a subscription with a token with the `su` claim.
+// The subscription is closed when ctx is done.
+func SubscribeWithSuToken(
+	inst Requester,
+	ctx context.Context,
+	options client.RequestOptions,
+) (*SubscriptionStream, error) {
+	return subscribe(inst, ctx, auth.Options{Su: true}, options)
+}
+
+// SubscribeWithUserToken opens a subscription and includes the user id as part of the `sub` claim.
+// The subscription is closed when ctx is done.
+func SubscribeWithUserToken(
+	inst Requester,
+	ctx context.Context,
+	userID string,
+	options client.RequestOptions,
+) (*SubscriptionStream, error) {
+	return subscribe(inst, ctx, auth.Options{UserID: &userID, Su: true}, options)
+}
+
+// tokenInvalidator is implemented by instances that cache tokens, to discard them.
+type tokenInvalidator interface {
+	invalidateTokens()
+}
+
+// subscribe opens a subscription with a token generated with tokenOptions, unless options
+// already has one. If the token is refused and the instance caches tokens, the subscription is
+// attempted again with a new token.
+func subscribe(
+	inst Requester,
+	ctx context.Context,
+	tokenOptions auth.Options,
+	options client.RequestOptions,
+) (*SubscriptionStream, error) {
+	if options.Jwt != nil {
+		return subscribeWithToken(inst, ctx, *options.Jwt, options)
+	}
+
+	token, err := generateTokenFromInstance(inst, tokenOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := subscribeWithToken(inst, ctx, token, options)
+	if errorResponse, ok := err.(*client.ErrorResponse); ok && errorResponse.Status == http.StatusUnauthorized {
+		if invalidator, ok := inst.(tokenInvalidator); ok {
+			invalidator.invalidateTokens()
+
+			token, err := generateTokenFromInstance(inst, tokenOptions)
+			if err != nil {
+				return nil, err
+			}
+
+			return subscribeWithToken(inst, ctx, token, options)
+		}
+	}
+
+	return stream, err
+}
+
+func subscribeWithToken(
+	inst Requester,
+	ctx context.Context,
+	token string,
+	options client.RequestOptions,
+) (*SubscriptionStream, error) {
+	response, err := inst.Request(ctx, client.RequestOptions{
+		Method:      MethodSubscribe,
+		Path:        options.Path,
+		Body:        options.Body,
+		Headers:     options.Headers,
+		QueryParams: options.QueryParams,
+		Jwt:         &token,
+	})
+	if err != nil {
+		if response != nil {
+			response.Body.Close()
+		}
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, &client.ErrorResponse{Status: response.StatusCode, Headers: response.Header}
+	}
+
+	return NewSubscriptionStream(response.Body), nil
+}
@@ -0,0 +1,75 @@
+package common
+
+import (
+	"io"
+	"io/ioutil"
+	"strings"
+	"testing"
+)
+
+func newTestStream(lines ...string) *SubscriptionStream {
+	return NewSubscriptionStream(ioutil.NopCloser(strings.NewReader(strings.Join(lines, "\n"))))
+}
+
+func TestSubscriptionStreamSkipsKeepAlives(t *testing.T) {
+	stream := newTestStream(
+		`[0,""]`,
+		``,
+		`[1,"event-1",{"content-type":"application/json"},{"event_name":"new_message"}]`,
+		`[0,""]`,
+		`[1,"event-2",{},{"event_name":"user_joined"}]`,
+	)
+
+	event, err := stream.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != "event-1" ||
+		event.Headers["content-type"] != "application/json" ||
+		string(event.Body) != `{"event_name":"new_message"}` {
+		t.Errorf("Expected the first event, got %+v", event)
+	}
+
+	event, err = stream.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.ID != "event-2" || string(event.Body) != `{"event_name":"user_joined"}` {
+		t.Errorf("Expected the second event, got %+v", event)
+	}
+
+	if _, err := stream.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF once the connection is closed, got %v", err)
+	}
+}
+
+func TestSubscriptionStreamEndOfSubscription(t *testing.T) {
+	stream := newTestStream(
+		`[0,""]`,
+		`[255,404,{"x-request-id":"abc"},{"error":"not_found"}]`,
+	)
+
+	_, err := stream.Next()
+	eos, ok := err.(*EndOfSubscriptionError)
+	if !ok {
+		t.Fatalf("Expected an *EndOfSubscriptionError, got %v", err)
+	}
+	if eos.Status != 404 || eos.Headers["x-request-id"] != "abc" || string(eos.Info) != `{"error":"not_found"}` {
+		t.Errorf("Expected the end of subscription to be decoded, got %+v", eos)
+	}
+}
+
+func TestSubscriptionStreamMalformedMessages(t *testing.T) {
+	for _, line := range []string{
+		`not json`,
+		`[]`,
+		`["one"]`,
+		`[1,"event-1",{}]`,
+		`[255,"404",{},{}]`,
+		`[7,"",{},{}]`,
+	} {
+		if _, err := newTestStream(line).Next(); err == nil || err == io.EOF {
+			t.Errorf("Expected an error decoding %s, got %v", line, err)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package common
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pusher/pusher-platform-go/auth"
+	"github.com/pusher/pusher-platform-go/instance"
+)
+
+// tokenRefreshMargin is how long before a cached token expires that a new one is generated
+// instead, so that tokens don't expire while a request is in flight.
+const tokenRefreshMargin = time.Minute
+
+// TokenStats counts the tokens generated for requests, and how often they are reused.
+// Its fields are updated atomically.
+type TokenStats struct {
+	Minted      uint64 // Tokens generated
+	CacheHits   uint64 // Requests made with a cached token
+	CacheMisses uint64 // Requests whose token could have been cached, but wasn't
+}
+
+type cachedToken struct {
+	token     auth.TokenWithExpiry
+	expiresAt time.Time
+}
+
+// usable reports whether the token can still be used at now.
+func (t cachedToken) usable(now time.Time) bool {
+	return now.Before(t.expiresAt.Add(-tokenRefreshMargin))
+}
+
+// userTokenKey identifies the cached token of a user.
+type userTokenKey struct {
+	userID string
+	su     bool
+}
+
+type userTokenEntry struct {
+	key   userTokenKey
+	token cachedToken
+}
+
+// tokenCachingInstance is an instance.Instance that reuses the tokens it generates for requests
+// until they are about to expire, rather than signing a new one for every request.
+// Besides the SU token it keeps the tokens of up to userTokenCacheSize users, evicting those of
+// the least recently used.
+type tokenCachingInstance struct {
+	instance.Instance
+
+	mu      sync.Mutex
+	suToken cachedToken
+
+	stats *TokenStats
+
+	userTokenCacheSize int
+	userTokens         map[userTokenKey]*list.Element
+	// Entries of userTokens, most recently used first.
+	userTokensLRU *list.List
+}
+
+// NewTokenCachingInstance returns an instance that makes requests with inst, and caches the
+// tokens generated by RequestWithSuToken, RequestWithUserToken and RequestAsUser, keeping the
+// tokens of up to userTokenCacheSize users. The tokens it generates and reuses are counted in
+// stats. It is safe for concurrent use.
+func NewTokenCachingInstance(inst instance.Instance, userTokenCacheSize int, stats *TokenStats) instance.Instance {
+	return &tokenCachingInstance{
+		Instance:           inst,
+		stats:              stats,
+		userTokenCacheSize: userTokenCacheSize,
+		userTokens:         map[userTokenKey]*list.Element{},
+		userTokensLRU:      list.New(),
+	}
+}
+
+// GenerateAccessToken returns a cached token if options are those the request helpers use and it
+// has not expired, and otherwise generates a token with the underlying instance.
+func (i *tokenCachingInstance) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	if options.TokenExpiry != nil || len(options.ServiceClaims) > 0 {
+		return i.mint(options)
+	}
+
+	if options.UserID == nil && !options.Su {
+		return i.mint(options)
+	}
+
+	if options.UserID != nil && i.userTokenCacheSize <= 0 {
+		return i.mint(options)
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	now := time.Now()
+	if options.UserID == nil {
+		if i.suToken.usable(now) {
+			atomic.AddUint64(&i.stats.CacheHits, 1)
+			return i.suToken.token, nil
+		}
+
+		token, err := i.generate(options, now)
+		if err != nil {
+			return auth.TokenWithExpiry{}, err
+		}
+		i.suToken = token
+
+		return token.token, nil
+	}
+
+	key := userTokenKey{userID: *options.UserID, su: options.Su}
+	if element, ok := i.userTokens[key]; ok {
+		entry := element.Value.(*userTokenEntry)
+		if entry.token.usable(now) {
+			i.userTokensLRU.MoveToFront(element)
+			atomic.AddUint64(&i.stats.CacheHits, 1)
+			return entry.token.token, nil
+		}
+
+		i.userTokensLRU.Remove(element)
+		delete(i.userTokens, key)
+	}
+
+	token, err := i.generate(options, now)
+	if err != nil {
+		return auth.TokenWithExpiry{}, err
+	}
+
+	i.userTokens[key] = i.userTokensLRU.PushFront(&userTokenEntry{key: key, token: token})
+	for i.userTokensLRU.Len() > i.userTokenCacheSize {
+		oldest := i.userTokensLRU.Back()
+		i.userTokensLRU.Remove(oldest)
+		delete(i.userTokens, oldest.Value.(*userTokenEntry).key)
+	}
+
+	return token.token, nil
+}
+
+// invalidateTokens discards the cached tokens, e.g. after the service has refused one, so that
+// new ones are generated.
+func (i *tokenCachingInstance) invalidateTokens() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.suToken = cachedToken{}
+	i.userTokens = map[userTokenKey]*list.Element{}
+	i.userTokensLRU.Init()
+}
+
+// generate generates a token to cache with the underlying instance, and records when it expires.
+func (i *tokenCachingInstance) generate(options auth.Options, now time.Time) (cachedToken, error) {
+	atomic.AddUint64(&i.stats.CacheMisses, 1)
+
+	token, err := i.mint(options)
+	if err != nil {
+		return cachedToken{}, err
+	}
+
+	return cachedToken{
+		token:     token,
+		expiresAt: now.Add(time.Duration(token.ExpiresIn * float64(time.Second))),
+	}, nil
+}
+
+// mint generates a token with the underlying instance.
+func (i *tokenCachingInstance) mint(options auth.Options) (auth.TokenWithExpiry, error) {
+	atomic.AddUint64(&i.stats.Minted, 1)
+	return i.Instance.GenerateAccessToken(options)
+}
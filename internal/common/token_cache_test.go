@@ -0,0 +1,200 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pusher/pusher-platform-go/auth"
+	"github.com/pusher/pusher-platform-go/instance"
+)
+
+// mintingInstance is an instance.Instance generating distinct tokens that expire after
+// expiresIn, counting them.
+type mintingInstance struct {
+	instance.Instance
+	expiresIn time.Duration
+	minted    int64
+}
+
+func (i *mintingInstance) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	n := atomic.AddInt64(&i.minted, 1)
+	return auth.TokenWithExpiry{
+		Token:     fmt.Sprintf("token-%d", n),
+		ExpiresIn: i.expiresIn.Seconds(),
+	}, nil
+}
+
+func newTestTokenCache(expiresIn time.Duration, userTokenCacheSize int) (*tokenCachingInstance, *mintingInstance, *TokenStats) {
+	minting := &mintingInstance{expiresIn: expiresIn}
+	stats := &TokenStats{}
+	return NewTokenCachingInstance(minting, userTokenCacheSize, stats).(*tokenCachingInstance), minting, stats
+}
+
+func TestSUTokenReused(t *testing.T) {
+	cache, minting, stats := newTestTokenCache(time.Hour, 0)
+
+	first, err := generateTokenFromInstance(cache, auth.Options{Su: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := generateTokenFromInstance(cache, auth.Options{Su: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != second || minting.minted != 1 {
+		t.Errorf("Expected the SU token to be reused, got %s and %s with %d minted", first, second, minting.minted)
+	}
+	if stats.Minted != 1 || stats.CacheHits != 1 || stats.CacheMisses != 1 {
+		t.Errorf("Expected 1 token minted, 1 hit and 1 miss, got %+v", *stats)
+	}
+}
+
+func TestSUTokenRegeneratedNearExpiry(t *testing.T) {
+	// Tokens expiring within the refresh margin are never reused.
+	cache, minting, _ := newTestTokenCache(tokenRefreshMargin/2, 0)
+
+	for i := 0; i < 3; i++ {
+		if _, err := cache.GenerateAccessToken(auth.Options{Su: true}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if minting.minted != 3 {
+		t.Errorf("Expected a token to be minted for every request, got %d", minting.minted)
+	}
+}
+
+func TestTokensWithCustomOptionsNotCached(t *testing.T) {
+	cache, minting, stats := newTestTokenCache(time.Hour, 10)
+	expiry := time.Minute
+
+	for _, options := range []auth.Options{
+		{Su: true, TokenExpiry: &expiry},
+		{Su: true, TokenExpiry: &expiry},
+		{Su: true, ServiceClaims: map[string]interface{}{"tenant": "acme"}},
+		{Su: true, ServiceClaims: map[string]interface{}{"tenant": "acme"}},
+		{},
+		{},
+	} {
+		if _, err := cache.GenerateAccessToken(options); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if minting.minted != 6 || stats.CacheHits != 0 {
+		t.Errorf("Expected every token to be minted, got %d minted and %d hits", minting.minted, stats.CacheHits)
+	}
+}
+
+func TestSUTokenCacheConcurrentUse(t *testing.T) {
+	cache, minting, _ := newTestTokenCache(time.Hour, 0)
+
+	var wg sync.WaitGroup
+	tokens := make([]string, 50)
+	for i := range tokens {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			token, err := cache.GenerateAccessToken(auth.Options{Su: true})
+			if err != nil {
+				t.Error(err)
+			}
+			tokens[i] = token.Token
+		}(i)
+	}
+	wg.Wait()
+
+	for _, token := range tokens {
+		if token != tokens[0] {
+			t.Fatalf("Expected every request to share a token, got %s and %s", tokens[0], token)
+		}
+	}
+	if minting.minted != 1 {
+		t.Errorf("Expected a single token to be minted, got %d", minting.minted)
+	}
+}
+
+func TestInvalidateTokens(t *testing.T) {
+	cache, minting, _ := newTestTokenCache(time.Hour, 0)
+
+	first, _ := cache.GenerateAccessToken(auth.Options{Su: true})
+	cache.invalidateTokens()
+	second, _ := cache.GenerateAccessToken(auth.Options{Su: true})
+
+	if first.Token == second.Token || minting.minted != 2 {
+		t.Errorf("Expected a new token after invalidating, got %s and %s", first.Token, second.Token)
+	}
+}
+
+func userToken(t *testing.T, cache *tokenCachingInstance, userID string, su bool) string {
+	token, err := cache.GenerateAccessToken(auth.Options{UserID: &userID, Su: su})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token.Token
+}
+
+func TestUserTokensReusedPerUser(t *testing.T) {
+	cache, minting, stats := newTestTokenCache(time.Hour, 10)
+
+	alice := userToken(t, cache, "alice", false)
+	bob := userToken(t, cache, "bob", false)
+	aliceSU := userToken(t, cache, "alice", true)
+
+	if alice == bob || alice == aliceSU {
+		t.Errorf("Expected distinct tokens per user and su claim, got %s, %s and %s", alice, bob, aliceSU)
+	}
+	if userToken(t, cache, "alice", false) != alice || userToken(t, cache, "alice", true) != aliceSU {
+		t.Error("Expected alice's tokens to be reused")
+	}
+	if minting.minted != 3 || stats.CacheHits != 2 {
+		t.Errorf("Expected 3 tokens minted and 2 hits, got %d and %d", minting.minted, stats.CacheHits)
+	}
+}
+
+func TestUserTokenCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache, _, _ := newTestTokenCache(time.Hour, 2)
+
+	alice := userToken(t, cache, "alice", false)
+	bob := userToken(t, cache, "bob", false)
+	userToken(t, cache, "alice", false)
+	// Bob is the least recently used, so makes way for carol.
+	userToken(t, cache, "carol", false)
+
+	if len(cache.userTokens) != 2 || cache.userTokensLRU.Len() != 2 {
+		t.Errorf("Expected the cache to hold 2 users' tokens, got %d", len(cache.userTokens))
+	}
+	if userToken(t, cache, "alice", false) != alice {
+		t.Error("Expected alice's token to be kept")
+	}
+	if userToken(t, cache, "bob", false) == bob {
+		t.Error("Expected bob's token to have been evicted")
+	}
+}
+
+func TestUserTokensRegeneratedNearExpiry(t *testing.T) {
+	cache, minting, _ := newTestTokenCache(tokenRefreshMargin/2, 10)
+
+	first := userToken(t, cache, "alice", false)
+	second := userToken(t, cache, "alice", false)
+
+	if first == second || minting.minted != 2 || len(cache.userTokens) != 1 {
+		t.Errorf("Expected the expiring token to be replaced, got %s and %s", first, second)
+	}
+}
+
+func TestUserTokenCacheDisabled(t *testing.T) {
+	cache, minting, _ := newTestTokenCache(time.Hour, 0)
+
+	userToken(t, cache, "alice", false)
+	userToken(t, cache, "alice", false)
+
+	if minting.minted != 2 || len(cache.userTokens) != 0 {
+		t.Errorf("Expected user tokens not to be cached, got %d minted", minting.minted)
+	}
+}
@@ -16,7 +16,6 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/pusher/pusher-platform-go/client"
-	"github.com/pusher/pusher-platform-go/instance"
 
 	"github.com/pusher/chatkit-server-go/internal/common"
 )
@@ -28,7 +27,7 @@ type Service interface {
 	GetUser(ctx context.Context, userID string) (User, error)
 	GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error)
 	GetUsersByID(ctx context.Context, userIDs []string) ([]User, error)
-	CreateUser(ctx context.Context, options CreateUserOptions) error
+	CreateUser(ctx context.Context, options CreateUserOptions) (User, error)
 	CreateUsers(ctx context.Context, users []CreateUserOptions) error
 	UpdateUser(ctx context.Context, userID string, options UpdateUserOptions) error
 	DeleteUser(ctx context.Context, userID string) error
@@ -39,14 +38,19 @@ type Service interface {
 	GetUserRooms(ctx context.Context, userID string) ([]Room, error)
 	GetUserJoinableRooms(ctx context.Context, userID string) ([]Room, error)
 	CreateRoom(ctx context.Context, options CreateRoomOptions) (Room, error)
-	UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) error
+	UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) (Room, error)
 	DeleteRoom(ctx context.Context, roomID string) error
 	AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error
 	RemoveUsersFromRoom(ctx context.Context, roomID string, userIds []string) error
+	AsyncDeleteRoom(ctx context.Context, roomID string) (string, error)
+	GetDeleteStatus(ctx context.Context, jobID string) (DeleteStatus, error)
+	JoinRoom(ctx context.Context, roomID string, userID string) (Room, error)
+	LeaveRoom(ctx context.Context, roomID string, userID string) error
 
 	// Messages
 	SendMessage(ctx context.Context, options SendMessageOptions) (uint, error)
 	SendMultipartMessage(ctx context.Context, options SendMultipartMessageOptions) (uint, error)
+	SendMultipartMessageAsService(ctx context.Context, options SendMultipartMessageOptions) (uint, error)
 	SendSimpleMessage(ctx context.Context, options SendSimpleMessageOptions) (uint, error)
 	GetRoomMessages(
 		ctx context.Context,
@@ -62,6 +66,7 @@ type Service interface {
 		roomID string,
 		options FetchMultipartMessagesOptions,
 	) ([]MultipartMessage, error)
+	CountRoomMessages(ctx context.Context, roomID string, max uint) (uint, error)
 	DeleteMessage(ctx context.Context, options DeleteMessageOptions) error
 	EditMessage(ctx context.Context, roomID string, messageID uint, options EditMessageOptions) error
 	EditMultipartMessage(ctx context.Context, roomID string, messageID uint, options EditMultipartMessageOptions) error
@@ -69,16 +74,27 @@ type Service interface {
 
 	// Generic requests
 	Request(ctx context.Context, options client.RequestOptions) (*http.Response, error)
+	Subscribe(ctx context.Context, options client.RequestOptions) (*common.SubscriptionStream, error)
+	SubscribeAsUser(
+		ctx context.Context,
+		userID string,
+		options client.RequestOptions,
+	) (*common.SubscriptionStream, error)
 }
 
 type coreService struct {
-	underlyingInstance instance.Instance
+	underlyingInstance common.Requester
+	decoder            common.Decoder
+	uploadClient       *http.Client // Client attachments are uploaded to their upload URL with
 }
 
-// Returns a new coreService instance that conforms to the Service interface.
-func NewService(platformInstance instance.Instance) Service {
+// Returns a new coreService instance that conforms to the Service interface, uploading
+// attachments with uploadClient.
+func NewService(platformInstance common.Requester, decoder common.Decoder, uploadClient *http.Client) Service {
 	return &coreService{
 		underlyingInstance: platformInstance,
+		decoder:            decoder,
+		uploadClient:       uploadClient,
 	}
 }
 
@@ -100,7 +116,7 @@ func (cs *coreService) GetUser(ctx context.Context, userID string) (User, error)
 	}
 
 	var user User
-	err = common.DecodeResponseBody(response.Body, &user)
+	err = cs.decoder.Decode(response.Body, &user)
 	if err != nil {
 		return User{}, err
 	}
@@ -113,8 +129,13 @@ func (cs *coreService) GetUser(ctx context.Context, userID string) (User, error)
 func (cs *coreService) GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error) {
 	queryParams := url.Values{}
 	if options != nil {
-		queryParams.Add("from_ts", options.FromTimestamp)
-		queryParams.Add("limit", strconv.Itoa(int(options.Limit)))
+		if options.FromTimestamp != "" {
+			queryParams.Add("from_ts", options.FromTimestamp)
+		}
+
+		if options.Limit != 0 {
+			queryParams.Add("limit", strconv.Itoa(int(options.Limit)))
+		}
 	}
 
 	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
@@ -128,7 +149,7 @@ func (cs *coreService) GetUsers(ctx context.Context, options *GetUsersOptions) (
 	defer response.Body.Close()
 
 	var users []User
-	err = common.DecodeResponseBody(response.Body, &users)
+	err = cs.decoder.Decode(response.Body, &users)
 	if err != nil {
 		return nil, err
 	}
@@ -153,7 +174,7 @@ func (cs *coreService) GetUsersByID(ctx context.Context, userIDs []string) ([]Us
 	}
 
 	var users []User
-	err = common.DecodeResponseBody(response.Body, &users)
+	err = cs.decoder.Decode(response.Body, &users)
 	if err != nil {
 		return nil, err
 	}
@@ -162,18 +183,19 @@ func (cs *coreService) GetUsersByID(ctx context.Context, userIDs []string) ([]Us
 }
 
 // CreateUser creates a new chatkit user based on the provided options.
-func (cs *coreService) CreateUser(ctx context.Context, options CreateUserOptions) error {
+// The user is returned as it was stored, including its creation timestamps.
+func (cs *coreService) CreateUser(ctx context.Context, options CreateUserOptions) (User, error) {
 	if options.ID == "" {
-		return errors.New("You must provide the ID of the user to create")
+		return User{}, errors.New("You must provide the ID of the user to create")
 	}
 
 	if options.Name == "" {
-		return errors.New("You must provide the name of the user to create")
+		return User{}, errors.New("You must provide the name of the user to create")
 	}
 
 	requestBody, err := common.CreateRequestBody(&options)
 	if err != nil {
-		return err
+		return User{}, err
 	}
 
 	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
@@ -185,10 +207,16 @@ func (cs *coreService) CreateUser(ctx context.Context, options CreateUserOptions
 		defer response.Body.Close()
 	}
 	if err != nil {
-		return err
+		return User{}, err
 	}
 
-	return nil
+	var user User
+	err = cs.decoder.Decode(response.Body, &user)
+	if err != nil {
+		return User{}, err
+	}
+
+	return user, nil
 }
 
 // CreateUsers creates users in a batch.
@@ -283,7 +311,7 @@ func (cs *coreService) GetRoom(ctx context.Context, roomID string) (Room, error)
 	}
 
 	var room Room
-	err = common.DecodeResponseBody(response.Body, &room)
+	err = cs.decoder.Decode(response.Body, &room)
 	if err != nil {
 		return Room{}, err
 	}
@@ -317,7 +345,7 @@ func (cs *coreService) GetRooms(ctx context.Context, options GetRoomsOptions) ([
 	}
 
 	var rooms []RoomWithoutMembers
-	err = common.DecodeResponseBody(response.Body, &rooms)
+	err = cs.decoder.Decode(response.Body, &rooms)
 	if err != nil {
 		return nil, err
 	}
@@ -364,7 +392,7 @@ func (cs *coreService) getRoomsForUser(
 	}
 
 	var rooms []Room
-	err = common.DecodeResponseBody(response.Body, &rooms)
+	err = cs.decoder.Decode(response.Body, &rooms)
 	if err != nil {
 		return nil, err
 	}
@@ -404,7 +432,7 @@ func (cs *coreService) CreateRoom(ctx context.Context, options CreateRoomOptions
 	}
 
 	var room Room
-	err = common.DecodeResponseBody(response.Body, &room)
+	err = cs.decoder.Decode(response.Body, &room)
 	if err != nil {
 		return Room{}, err
 	}
@@ -413,7 +441,7 @@ func (cs *coreService) CreateRoom(ctx context.Context, options CreateRoomOptions
 }
 
 // UpdateRoom updates an existing room based on the options provided.
-func (cs *coreService) UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) error {
+func (cs *coreService) UpdateRoom(ctx context.Context, roomID string, options UpdateRoomOptions) (Room, error) {
 	var formattedOptions interface{} = options
 	if options.PushNotificationTitleOverride == &ExplicitlyResetPushNotificationTitleOverride {
 		type updateRoomOptionsWithExplicitPNTitleOverride struct {
@@ -429,7 +457,7 @@ func (cs *coreService) UpdateRoom(ctx context.Context, roomID string, options Up
 
 	requestBody, err := common.CreateRequestBody(&formattedOptions)
 	if err != nil {
-		return err
+		return Room{}, err
 	}
 
 	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
@@ -441,10 +469,21 @@ func (cs *coreService) UpdateRoom(ctx context.Context, roomID string, options Up
 		defer response.Body.Close()
 	}
 	if err != nil {
-		return err
+		return Room{}, err
 	}
 
-	return nil
+	// Older versions of the API respond without a body, in which case the room is fetched.
+	if response.StatusCode == http.StatusNoContent {
+		return cs.GetRoom(ctx, roomID)
+	}
+
+	var room Room
+	err = cs.decoder.Decode(response.Body, &room)
+	if err != nil {
+		return Room{}, err
+	}
+
+	return room, nil
 }
 
 // DeleteRoom deletes an existing room.
@@ -463,6 +502,60 @@ func (cs *coreService) DeleteRoom(ctx context.Context, roomID string) error {
 	return nil
 }
 
+// AsyncDeleteRoom starts deleting a room in the background and returns the ID of the job.
+// Requires version v7 or later of the API.
+func (cs *coreService) AsyncDeleteRoom(ctx context.Context, roomID string) (string, error) {
+	if roomID == "" {
+		return "", errors.New("You must provide the ID of the room to delete")
+	}
+
+	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
+		Method: http.MethodPut,
+		Path:   fmt.Sprintf("/rooms/%s/delete", url.PathEscape(roomID)),
+	})
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var job DeleteStatus
+	err = cs.decoder.Decode(response.Body, &job)
+	if err != nil {
+		return "", err
+	}
+
+	return job.JobID, nil
+}
+
+// GetDeleteStatus returns the status of an asynchronous deletion job.
+// Requires version v7 or later of the API.
+func (cs *coreService) GetDeleteStatus(ctx context.Context, jobID string) (DeleteStatus, error) {
+	if jobID == "" {
+		return DeleteStatus{}, errors.New("You must provide the ID of the deletion job")
+	}
+
+	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/deletes/%s", url.PathEscape(jobID)),
+	})
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return DeleteStatus{}, err
+	}
+
+	var status DeleteStatus
+	err = cs.decoder.Decode(response.Body, &status)
+	if err != nil {
+		return DeleteStatus{}, err
+	}
+
+	return status, nil
+}
+
 // AddUsersToRoom adds users to an existing room.
 // The maximum number of users that can be added in a single request is 10.
 func (cs *coreService) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
@@ -517,6 +610,60 @@ func (cs *coreService) RemoveUsersFromRoom(ctx context.Context, roomID string, u
 	return nil
 }
 
+// JoinRoom adds a user to a room on their behalf, subject to their permissions.
+func (cs *coreService) JoinRoom(ctx context.Context, roomID string, userID string) (Room, error) {
+	if userID == "" {
+		return Room{}, errors.New("You must provide the ID of the user joining the room")
+	}
+
+	response, err := common.RequestAsUser(cs.underlyingInstance, ctx, userID, client.RequestOptions{
+		Method: http.MethodPost,
+		Path: fmt.Sprintf(
+			"/users/%s/rooms/%s/join",
+			url.PathEscape(userID),
+			url.PathEscape(roomID),
+		),
+	})
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return Room{}, err
+	}
+
+	var room Room
+	err = cs.decoder.Decode(response.Body, &room)
+	if err != nil {
+		return Room{}, err
+	}
+
+	return room, nil
+}
+
+// LeaveRoom removes a user from a room on their behalf, subject to their permissions.
+func (cs *coreService) LeaveRoom(ctx context.Context, roomID string, userID string) error {
+	if userID == "" {
+		return errors.New("You must provide the ID of the user leaving the room")
+	}
+
+	response, err := common.RequestAsUser(cs.underlyingInstance, ctx, userID, client.RequestOptions{
+		Method: http.MethodPost,
+		Path: fmt.Sprintf(
+			"/users/%s/rooms/%s/leave",
+			url.PathEscape(userID),
+			url.PathEscape(roomID),
+		),
+	})
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
 // SendMessage publishes a message to a room.
 func (cs *coreService) SendMessage(ctx context.Context, options SendMessageOptions) (uint, error) {
 	if options.Text == "" {
@@ -549,7 +696,7 @@ func (cs *coreService) SendMessage(ctx context.Context, options SendMessageOptio
 	}
 
 	var messageResponse map[string]uint
-	err = common.DecodeResponseBody(response.Body, &messageResponse)
+	err = cs.decoder.Decode(response.Body, &messageResponse)
 	if err != nil {
 		return 0, err
 	}
@@ -561,6 +708,24 @@ func (cs *coreService) SendMessage(ctx context.Context, options SendMessageOptio
 func (cs *coreService) SendMultipartMessage(
 	ctx context.Context,
 	options SendMultipartMessageOptions,
+) (uint, error) {
+	return cs.sendMultipartMessage(ctx, options, false)
+}
+
+// SendMultipartMessageAsService publishes a multipart message to a room with a super user token,
+// naming the sender in the body of the request rather than authenticating as them.
+// Any attachments are still uploaded on behalf of the sender.
+func (cs *coreService) SendMultipartMessageAsService(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (uint, error) {
+	return cs.sendMultipartMessage(ctx, options, true)
+}
+
+func (cs *coreService) sendMultipartMessage(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+	asService bool,
 ) (uint, error) {
 	if len(options.Parts) == 0 {
 		return 0, errors.New("You must provide at least one message part")
@@ -570,10 +735,15 @@ func (cs *coreService) SendMultipartMessage(
 		return 0, errors.New("You must provide the ID of the user sending the message")
 	}
 
-	requestParts := make([]interface{}, len(options.Parts))
+	parts := options.Parts
+	if options.ParentMessageID != nil {
+		parts = append(parts[:len(parts):len(parts)], newThreadPart(*options.ParentMessageID))
+	}
+
+	requestParts := make([]interface{}, len(parts))
 	g, gCtx := errgroup.WithContext(ctx)
 
-	for i, part := range options.Parts {
+	for i, part := range parts {
 		switch p := part.(type) {
 		case NewAttachmentPart:
 			i := i
@@ -582,6 +752,11 @@ func (cs *coreService) SendMultipartMessage(
 				requestParts[i] = uploadedPart
 				return err
 			})
+		case NewExistingAttachmentPart:
+			requestParts[i] = newAttachmentPartUploaded{
+				Type:       p.Type,
+				Attachment: uploadedAttachment{ID: p.AttachmentID},
+			}
 		default:
 			requestParts[i] = part
 		}
@@ -591,23 +766,28 @@ func (cs *coreService) SendMultipartMessage(
 		return 0, fmt.Errorf("Failed to upload attachment: %v", err)
 	}
 
-	requestBody, err := common.CreateRequestBody(
-		map[string]interface{}{"parts": requestParts},
-	)
+	requestFields := map[string]interface{}{"parts": requestParts}
+	if asService {
+		requestFields["sender_id"] = options.SenderID
+	}
+
+	requestBody, err := common.CreateRequestBody(requestFields)
 	if err != nil {
 		return 0, err
 	}
 
-	response, err := common.RequestWithUserToken(
-		cs.underlyingInstance,
-		ctx,
-		options.SenderID,
-		client.RequestOptions{
-			Method: http.MethodPost,
-			Path:   fmt.Sprintf("/rooms/%s/messages", url.PathEscape(options.RoomID)),
-			Body:   requestBody,
-		},
-	)
+	requestOptions := client.RequestOptions{
+		Method: http.MethodPost,
+		Path:   fmt.Sprintf("/rooms/%s/messages", url.PathEscape(options.RoomID)),
+		Body:   requestBody,
+	}
+
+	var response *http.Response
+	if asService {
+		response, err = common.RequestWithSuToken(cs.underlyingInstance, ctx, requestOptions)
+	} else {
+		response, err = common.RequestWithUserToken(cs.underlyingInstance, ctx, options.SenderID, requestOptions)
+	}
 	if response != nil {
 		defer response.Body.Close()
 	}
@@ -616,7 +796,7 @@ func (cs *coreService) SendMultipartMessage(
 	}
 
 	var messageResponse map[string]uint
-	err = common.DecodeResponseBody(response.Body, &messageResponse)
+	err = cs.decoder.Decode(response.Body, &messageResponse)
 	if err != nil {
 		return 0, err
 	}
@@ -630,19 +810,28 @@ func (cs *coreService) uploadAttachment(
 	roomID string,
 	part NewAttachmentPart,
 ) (newAttachmentPartUploaded, error) {
-	// Unfortunately since we need to provide the content length up front, we
-	// have to read the whole file in to memory.
-	b, err := ioutil.ReadAll(part.File)
+	// The content length must be provided up front. When the size of the file can't be
+	// determined without reading it, the whole file has to be read in to memory.
+	size, known, err := attachmentSize(part)
 	if err != nil {
 		return newAttachmentPartUploaded{}, err
 	}
 
+	body := io.Reader(io.LimitReader(part.File, size))
+	if !known {
+		b, err := ioutil.ReadAll(part.File)
+		if err != nil {
+			return newAttachmentPartUploaded{}, err
+		}
+		size, body = int64(len(b)), bytes.NewReader(b)
+	}
+
 	url, attachmentID, err := cs.requestPresignedURL(
 		ctx,
 		senderID,
 		roomID,
 		part.Type,
-		len(b),
+		size,
 		part.Name,
 		part.CustomData,
 	)
@@ -650,7 +839,7 @@ func (cs *coreService) uploadAttachment(
 		return newAttachmentPartUploaded{}, err
 	}
 
-	if err := cs.uploadToURL(ctx, url, part.Type, len(b), bytes.NewReader(b)); err != nil {
+	if err := cs.uploadToURL(ctx, url, part.Type, size, body, part.Progress); err != nil {
 		return newAttachmentPartUploaded{}, err
 	}
 
@@ -660,12 +849,59 @@ func (cs *coreService) uploadAttachment(
 	}, nil
 }
 
+// progressReader reports the number of bytes read through it to a progress callback.
+type progressReader struct {
+	reader   io.Reader
+	read     int64
+	total    int64
+	progress func(bytesSent int64, totalBytes int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.progress(r.read, r.total)
+	}
+
+	return n, err
+}
+
+// attachmentSize returns the number of bytes that will be uploaded for an attachment, if it can be
+// determined without reading the file: either from the part's Size, or by seeking the file.
+func attachmentSize(part NewAttachmentPart) (int64, bool, error) {
+	if part.Size > 0 {
+		return part.Size, true, nil
+	}
+
+	seeker, ok := part.File.(io.Seeker)
+	if !ok {
+		return 0, false, nil
+	}
+
+	current, err := seeker.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return 0, false, nil
+	}
+
+	end, err := seeker.Seek(0, io.SeekEnd)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if _, err := seeker.Seek(current, io.SeekStart); err != nil {
+		return 0, false, err
+	}
+
+	return end - current, true, nil
+}
+
 func (cs *coreService) requestPresignedURL(
 	ctx context.Context,
 	senderID string,
 	roomID string,
 	contentType string,
-	contentLength int,
+	contentLength int64,
 	name *string,
 	customData interface{},
 ) (string, string, error) {
@@ -697,7 +933,7 @@ func (cs *coreService) requestPresignedURL(
 	}
 
 	var resBody map[string]string
-	if err := common.DecodeResponseBody(res.Body, &resBody); err != nil {
+	if err := cs.decoder.Decode(res.Body, &resBody); err != nil {
 		return "", "", err
 	}
 
@@ -708,19 +944,23 @@ func (cs *coreService) uploadToURL(
 	ctx context.Context,
 	url string,
 	contentType string,
-	contentLength int,
+	contentLength int64,
 	body io.Reader,
+	progress func(bytesSent int64, totalBytes int64),
 ) error {
-	client := &http.Client{}
+	if progress != nil {
+		body = &progressReader{reader: body, total: contentLength, progress: progress}
+	}
 
 	req, err := http.NewRequest("PUT", url, body)
 	if err != nil {
 		return err
 	}
+	req.ContentLength = contentLength
 	req.Header.Add("content-type", contentType)
-	req.Header.Add("content-length", strconv.Itoa(contentLength))
+	req.Header.Add("content-length", strconv.FormatInt(contentLength, 10))
 
-	res, err := client.Do(req.WithContext(ctx))
+	res, err := cs.uploadClient.Do(req.WithContext(ctx))
 	if res != nil {
 		defer res.Body.Close()
 	}
@@ -756,7 +996,7 @@ func (cs *coreService) DeleteMessage(ctx context.Context, options DeleteMessageO
 		defer response.Body.Close()
 	}
 	if err != nil {
-		return nil
+		return err
 	}
 
 	return nil
@@ -834,6 +1074,11 @@ func (cs *coreService) EditMultipartMessage(ctx context.Context, roomID string,
 				requestParts[i] = uploadedPart
 				return err
 			})
+		case NewExistingAttachmentPart:
+			requestParts[i] = newAttachmentPartUploaded{
+				Type:       p.Type,
+				Attachment: uploadedAttachment{ID: p.AttachmentID},
+			}
 		default:
 			requestParts[i] = part
 		}
@@ -900,11 +1145,12 @@ func (cs *coreService) FetchMultipartMessage(
 	defer response.Body.Close()
 
 	var message MultipartMessage
-	err = common.DecodeResponseBody(response.Body, &message)
+	err = cs.decoder.Decode(response.Body, &message)
 	if err != nil {
 		return MultipartMessage{}, err
 	}
 
+	message.setParentMessageID()
 	return message, nil
 }
 
@@ -916,9 +1162,55 @@ func (cs *coreService) FetchMultipartMessages(
 ) ([]MultipartMessage, error) {
 	messages := []MultipartMessage{}
 	err := cs.fetchMessages(ctx, roomID, options, &messages)
+	for i := range messages {
+		messages[i].setParentMessageID()
+	}
 	return messages, err
 }
 
+// maxMessagesPageSize is the largest number of messages that can be fetched in a single request.
+const maxMessagesPageSize = 100
+
+// CountRoomMessages counts the messages in a room by paging through its history, stopping once
+// it has counted more than max, so that a count of max+1 means there are more than max.
+// Only message IDs are decoded, but every message is still transferred, so this is slow for
+// large values of max.
+func (cs *coreService) CountRoomMessages(ctx context.Context, roomID string, max uint) (uint, error) {
+	direction := "newer"
+	initialID := uint(0)
+	count := uint(0)
+
+	for {
+		limit := uint(maxMessagesPageSize)
+		if remaining := max + 1 - count; remaining < limit {
+			limit = remaining
+		}
+
+		var messages []struct {
+			ID uint `json:"id"`
+		}
+		err := cs.fetchMessages(ctx, roomID, fetchMessagesOptions{
+			Direction: &direction,
+			InitialID: &initialID,
+			Limit:     &limit,
+		}, &messages)
+		if err != nil {
+			return 0, err
+		}
+
+		count += uint(len(messages))
+		for _, message := range messages {
+			if message.ID > initialID {
+				initialID = message.ID
+			}
+		}
+
+		if uint(len(messages)) < limit || count > max {
+			return count, nil
+		}
+	}
+}
+
 func (cs *coreService) fetchMessages(
 	ctx context.Context,
 	roomID string,
@@ -950,7 +1242,7 @@ func (cs *coreService) fetchMessages(
 		return err
 	}
 
-	err = common.DecodeResponseBody(response.Body, target)
+	err = cs.decoder.Decode(response.Body, target)
 	if err != nil {
 		return err
 	}
@@ -965,3 +1257,20 @@ func (cs *coreService) Request(
 ) (*http.Response, error) {
 	return cs.underlyingInstance.Request(ctx, options)
 }
+
+// Subscribe opens a subscription to the core service with a token with the `su` claim.
+func (cs *coreService) Subscribe(
+	ctx context.Context,
+	options client.RequestOptions,
+) (*common.SubscriptionStream, error) {
+	return common.SubscribeWithSuToken(cs.underlyingInstance, ctx, options)
+}
+
+// SubscribeAsUser opens a subscription to the core service on behalf of a user.
+func (cs *coreService) SubscribeAsUser(
+	ctx context.Context,
+	userID string,
+	options client.RequestOptions,
+) (*common.SubscriptionStream, error) {
+	return common.SubscribeWithUserToken(cs.underlyingInstance, ctx, userID, options)
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	"github.com/pusher/pusher-platform-go/auth"
+	"github.com/pusher/pusher-platform-go/client"
+)
+
+// stubRequester is a common.Requester recording the requests made through it and responding to
+// them with status and body.
+type stubRequester struct {
+	status   int
+	body     string
+	err      error
+	requests []client.RequestOptions
+	bodies   []string
+	tokens   []auth.Options
+}
+
+func (r *stubRequester) Request(ctx context.Context, options client.RequestOptions) (*http.Response, error) {
+	body := ""
+	if options.Body != nil {
+		bodyBytes, _ := ioutil.ReadAll(options.Body)
+		body = string(bodyBytes)
+	}
+	r.requests = append(r.requests, options)
+	r.bodies = append(r.bodies, body)
+
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return &http.Response{
+		StatusCode: r.status,
+		Body:       ioutil.NopCloser(strings.NewReader(r.body)),
+	}, nil
+}
+
+func (r *stubRequester) GenerateAccessToken(options auth.Options) (auth.TokenWithExpiry, error) {
+	r.tokens = append(r.tokens, options)
+	return auth.TokenWithExpiry{Token: "token"}, nil
+}
+
+func newStubService(requester *stubRequester) Service {
+	return NewService(requester, common.Decoder{}, http.DefaultClient)
+}
+
+func TestGetUser(t *testing.T) {
+	requester := &stubRequester{
+		status: http.StatusOK,
+		body:   `{"id":"alice","name":"Alice","created_at":"2020-01-01T00:00:00Z"}`,
+	}
+
+	user, err := newStubService(requester).GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "alice" || user.Name != "Alice" || user.CreatedAt.Year() != 2020 {
+		t.Errorf("Expected the user to be decoded, got %+v", user)
+	}
+
+	if len(requester.requests) != 1 {
+		t.Fatalf("Expected one request, got %d", len(requester.requests))
+	}
+	request := requester.requests[0]
+	if request.Method != http.MethodGet || request.Path != "/users/alice" {
+		t.Errorf("Expected GET /users/alice, got %s %s", request.Method, request.Path)
+	}
+	if request.Jwt == nil || *request.Jwt != "token" || !requester.tokens[0].Su {
+		t.Errorf("Expected the request to be made with an SU token, got %v", requester.tokens)
+	}
+}
+
+func TestGetUserRequiresID(t *testing.T) {
+	requester := &stubRequester{}
+
+	if _, err := newStubService(requester).GetUser(context.Background(), ""); err == nil {
+		t.Error("Expected an error for a user without an ID")
+	}
+	if len(requester.requests) != 0 {
+		t.Errorf("Expected no request to be made, got %v", requester.requests)
+	}
+}
+
+func TestCreateUser(t *testing.T) {
+	requester := &stubRequester{
+		status: http.StatusCreated,
+		body:   `{"id":"alice","name":"Alice","created_at":"2020-01-01T00:00:00Z","updated_at":"2020-01-01T00:00:00Z"}`,
+	}
+
+	user, err := newStubService(requester).CreateUser(context.Background(), CreateUserOptions{
+		ID:   "alice",
+		Name: "Alice",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ID != "alice" || user.UpdatedAt.IsZero() {
+		t.Errorf("Expected the created user to be decoded, got %+v", user)
+	}
+
+	request := requester.requests[0]
+	if request.Method != http.MethodPost || request.Path != "/users" {
+		t.Errorf("Expected POST /users, got %s %s", request.Method, request.Path)
+	}
+	if !strings.Contains(requester.bodies[0], `"id":"alice"`) || !strings.Contains(requester.bodies[0], `"name":"Alice"`) {
+		t.Errorf("Expected the user to be sent, got %s", requester.bodies[0])
+	}
+}
+
+func TestJoinRoomActsAsUser(t *testing.T) {
+	requester := &stubRequester{status: http.StatusOK, body: `{"id":"room-1","member_user_ids":["alice"]}`}
+
+	room, err := newStubService(requester).JoinRoom(context.Background(), "room-1", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if room.ID != "room-1" {
+		t.Errorf("Expected the room to be decoded, got %+v", room)
+	}
+
+	request := requester.requests[0]
+	if request.Method != http.MethodPost || request.Path != "/users/alice/rooms/room-1/join" {
+		t.Errorf("Expected POST /users/alice/rooms/room-1/join, got %s %s", request.Method, request.Path)
+	}
+	token := requester.tokens[0]
+	if token.UserID == nil || *token.UserID != "alice" || token.Su {
+		t.Errorf("Expected the request to be made with a token for alice without su, got %+v", token)
+	}
+}
+
+func TestRequestErrorsAreReturned(t *testing.T) {
+	requestErr := errors.New("connection refused")
+	requester := &stubRequester{err: requestErr}
+
+	if _, err := newStubService(requester).GetUser(context.Background(), "alice"); err != requestErr {
+		t.Errorf("Expected the request's error, got %v", err)
+	}
+}
+
+func TestDeleteMessageErrorsAreReturned(t *testing.T) {
+	requestErr := errors.New("connection refused")
+	requester := &stubRequester{err: requestErr}
+
+	err := newStubService(requester).DeleteMessage(context.Background(), DeleteMessageOptions{
+		RoomID:    "general",
+		MessageID: 1,
+	})
+	if err != requestErr {
+		t.Errorf("Expected the request's error, got %v", err)
+	}
+}
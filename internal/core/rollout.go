@@ -0,0 +1,413 @@
+package core
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"reflect"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	"github.com/pusher/pusher-platform-go/client"
+)
+
+// RolloutOptions configures a service that gradually moves calls over to a candidate version
+// of the core service.
+type RolloutOptions struct {
+	// Percentage of calls, between 0 and 100, routed through the candidate service.
+	Percentage float64
+	// Called whenever a read routed through the candidate returns a different result to the
+	// stable service.
+	OnDivergence func(RolloutDivergence)
+}
+
+// RolloutDivergence describes a read for which the candidate and stable services disagreed.
+type RolloutDivergence struct {
+	Method          string      // Name of the Service method that was called
+	StableResult    interface{} // Result returned by the stable service
+	StableError     error       // Error returned by the stable service
+	CandidateResult interface{} // Result returned by the candidate service
+	CandidateError  error       // Error returned by the candidate service
+}
+
+type rolloutService struct {
+	stable    Service
+	candidate Service
+	options   RolloutOptions
+}
+
+// NewRolloutService returns a Service that routes a percentage of calls through candidate and
+// the remainder through stable.
+// Reads routed through the candidate are also performed against the stable service, at the same
+// time, and any difference between the two is reported, while the candidate's result is returned.
+// Reads are only mirrored while OnDivergence is set. Writes are
+// only ever performed once, against whichever service they were routed to.
+func NewRolloutService(stable Service, candidate Service, options RolloutOptions) Service {
+	return &rolloutService{
+		stable:    stable,
+		candidate: candidate,
+		options:   options,
+	}
+}
+
+// route picks the service the next call is performed against.
+func (rs *rolloutService) route() (Service, bool) {
+	if rand.Float64()*100 < rs.options.Percentage {
+		return rs.candidate, true
+	}
+
+	return rs.stable, false
+}
+
+// mirroredRead is the result of a read mirrored to the stable service.
+type mirroredRead struct {
+	result interface{}
+	err    error
+}
+
+// mirror starts performing a read routed through the candidate against the stable service too,
+// concurrently with the candidate's, so that mirroring doesn't add the stable service's latency
+// to the candidate's. It returns nil, reading nothing, if the read wasn't routed through the
+// candidate or divergences aren't reported.
+func (rs *rolloutService) mirror(isCandidate bool, read func() (interface{}, error)) <-chan mirroredRead {
+	if !isCandidate || rs.options.OnDivergence == nil {
+		return nil
+	}
+
+	stable := make(chan mirroredRead, 1)
+	go func() {
+		result, err := read()
+		stable <- mirroredRead{result: result, err: err}
+	}()
+
+	return stable
+}
+
+// compare waits for the mirrored read of the stable service, if there is one, and reports a
+// divergence between its result and the candidate's.
+func (rs *rolloutService) compare(
+	method string,
+	candidateResult interface{},
+	candidateErr error,
+	stable <-chan mirroredRead,
+) {
+	if stable == nil {
+		return
+	}
+
+	mirrored := <-stable
+	stableResult, stableErr := mirrored.result, mirrored.err
+	if reflect.DeepEqual(candidateResult, stableResult) && (candidateErr == nil) == (stableErr == nil) {
+		return
+	}
+
+	rs.options.OnDivergence(RolloutDivergence{
+		Method:          method,
+		StableResult:    stableResult,
+		StableError:     stableErr,
+		CandidateResult: candidateResult,
+		CandidateError:  candidateErr,
+	})
+}
+
+func (rs *rolloutService) GetUser(ctx context.Context, userID string) (User, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetUser(ctx, userID)
+	})
+	user, err := service.GetUser(ctx, userID)
+	rs.compare("GetUser", user, err, stable)
+
+	return user, err
+}
+
+func (rs *rolloutService) GetUsers(ctx context.Context, options *GetUsersOptions) ([]User, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetUsers(ctx, options)
+	})
+	users, err := service.GetUsers(ctx, options)
+	rs.compare("GetUsers", users, err, stable)
+
+	return users, err
+}
+
+func (rs *rolloutService) GetUsersByID(ctx context.Context, userIDs []string) ([]User, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetUsersByID(ctx, userIDs)
+	})
+	users, err := service.GetUsersByID(ctx, userIDs)
+	rs.compare("GetUsersByID", users, err, stable)
+
+	return users, err
+}
+
+func (rs *rolloutService) CreateUser(ctx context.Context, options CreateUserOptions) (User, error) {
+	service, _ := rs.route()
+	return service.CreateUser(ctx, options)
+}
+
+func (rs *rolloutService) CreateUsers(ctx context.Context, users []CreateUserOptions) error {
+	service, _ := rs.route()
+	return service.CreateUsers(ctx, users)
+}
+
+func (rs *rolloutService) UpdateUser(
+	ctx context.Context,
+	userID string,
+	options UpdateUserOptions,
+) error {
+	service, _ := rs.route()
+	return service.UpdateUser(ctx, userID, options)
+}
+
+func (rs *rolloutService) DeleteUser(ctx context.Context, userID string) error {
+	service, _ := rs.route()
+	return service.DeleteUser(ctx, userID)
+}
+
+func (rs *rolloutService) GetRoom(ctx context.Context, roomID string) (Room, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetRoom(ctx, roomID)
+	})
+	room, err := service.GetRoom(ctx, roomID)
+	rs.compare("GetRoom", room, err, stable)
+
+	return room, err
+}
+
+func (rs *rolloutService) GetRooms(
+	ctx context.Context,
+	options GetRoomsOptions,
+) ([]RoomWithoutMembers, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetRooms(ctx, options)
+	})
+	rooms, err := service.GetRooms(ctx, options)
+	rs.compare("GetRooms", rooms, err, stable)
+
+	return rooms, err
+}
+
+func (rs *rolloutService) GetUserRooms(ctx context.Context, userID string) ([]Room, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetUserRooms(ctx, userID)
+	})
+	rooms, err := service.GetUserRooms(ctx, userID)
+	rs.compare("GetUserRooms", rooms, err, stable)
+
+	return rooms, err
+}
+
+func (rs *rolloutService) GetUserJoinableRooms(ctx context.Context, userID string) ([]Room, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetUserJoinableRooms(ctx, userID)
+	})
+	rooms, err := service.GetUserJoinableRooms(ctx, userID)
+	rs.compare("GetUserJoinableRooms", rooms, err, stable)
+
+	return rooms, err
+}
+
+func (rs *rolloutService) CreateRoom(ctx context.Context, options CreateRoomOptions) (Room, error) {
+	service, _ := rs.route()
+	return service.CreateRoom(ctx, options)
+}
+
+func (rs *rolloutService) UpdateRoom(
+	ctx context.Context,
+	roomID string,
+	options UpdateRoomOptions,
+) (Room, error) {
+	service, _ := rs.route()
+	return service.UpdateRoom(ctx, roomID, options)
+}
+
+func (rs *rolloutService) DeleteRoom(ctx context.Context, roomID string) error {
+	service, _ := rs.route()
+	return service.DeleteRoom(ctx, roomID)
+}
+
+func (rs *rolloutService) AsyncDeleteRoom(ctx context.Context, roomID string) (string, error) {
+	service, _ := rs.route()
+	return service.AsyncDeleteRoom(ctx, roomID)
+}
+
+// GetDeleteStatus is not compared, as the status of a job may change between the two reads.
+func (rs *rolloutService) GetDeleteStatus(ctx context.Context, jobID string) (DeleteStatus, error) {
+	service, _ := rs.route()
+	return service.GetDeleteStatus(ctx, jobID)
+}
+
+func (rs *rolloutService) AddUsersToRoom(ctx context.Context, roomID string, userIDs []string) error {
+	service, _ := rs.route()
+	return service.AddUsersToRoom(ctx, roomID, userIDs)
+}
+
+func (rs *rolloutService) RemoveUsersFromRoom(
+	ctx context.Context,
+	roomID string,
+	userIDs []string,
+) error {
+	service, _ := rs.route()
+	return service.RemoveUsersFromRoom(ctx, roomID, userIDs)
+}
+
+func (rs *rolloutService) JoinRoom(ctx context.Context, roomID string, userID string) (Room, error) {
+	service, _ := rs.route()
+	return service.JoinRoom(ctx, roomID, userID)
+}
+
+func (rs *rolloutService) LeaveRoom(ctx context.Context, roomID string, userID string) error {
+	service, _ := rs.route()
+	return service.LeaveRoom(ctx, roomID, userID)
+}
+
+func (rs *rolloutService) SendMessage(ctx context.Context, options SendMessageOptions) (uint, error) {
+	service, _ := rs.route()
+	return service.SendMessage(ctx, options)
+}
+
+func (rs *rolloutService) SendMultipartMessage(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (uint, error) {
+	service, _ := rs.route()
+	return service.SendMultipartMessage(ctx, options)
+}
+
+func (rs *rolloutService) SendMultipartMessageAsService(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (uint, error) {
+	service, _ := rs.route()
+	return service.SendMultipartMessageAsService(ctx, options)
+}
+
+func (rs *rolloutService) SendSimpleMessage(
+	ctx context.Context,
+	options SendSimpleMessageOptions,
+) (uint, error) {
+	service, _ := rs.route()
+	return service.SendSimpleMessage(ctx, options)
+}
+
+func (rs *rolloutService) GetRoomMessages(
+	ctx context.Context,
+	roomID string,
+	options GetRoomMessagesOptions,
+) ([]Message, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.GetRoomMessages(ctx, roomID, options)
+	})
+	messages, err := service.GetRoomMessages(ctx, roomID, options)
+	rs.compare("GetRoomMessages", messages, err, stable)
+
+	return messages, err
+}
+
+func (rs *rolloutService) FetchMultipartMessage(
+	ctx context.Context,
+	options FetchMultipartMessageOptions,
+) (MultipartMessage, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.FetchMultipartMessage(ctx, options)
+	})
+	message, err := service.FetchMultipartMessage(ctx, options)
+	rs.compare("FetchMultipartMessage", message, err, stable)
+
+	return message, err
+}
+
+func (rs *rolloutService) FetchMultipartMessages(
+	ctx context.Context,
+	roomID string,
+	options FetchMultipartMessagesOptions,
+) ([]MultipartMessage, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.FetchMultipartMessages(ctx, roomID, options)
+	})
+	messages, err := service.FetchMultipartMessages(ctx, roomID, options)
+	rs.compare("FetchMultipartMessages", messages, err, stable)
+
+	return messages, err
+}
+
+func (rs *rolloutService) CountRoomMessages(ctx context.Context, roomID string, max uint) (uint, error) {
+	service, isCandidate := rs.route()
+	stable := rs.mirror(isCandidate, func() (interface{}, error) {
+		return rs.stable.CountRoomMessages(ctx, roomID, max)
+	})
+	count, err := service.CountRoomMessages(ctx, roomID, max)
+	rs.compare("CountRoomMessages", count, err, stable)
+
+	return count, err
+}
+
+func (rs *rolloutService) DeleteMessage(ctx context.Context, options DeleteMessageOptions) error {
+	service, _ := rs.route()
+	return service.DeleteMessage(ctx, options)
+}
+
+func (rs *rolloutService) EditMessage(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	options EditMessageOptions,
+) error {
+	service, _ := rs.route()
+	return service.EditMessage(ctx, roomID, messageID, options)
+}
+
+func (rs *rolloutService) EditMultipartMessage(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	options EditMultipartMessageOptions,
+) error {
+	service, _ := rs.route()
+	return service.EditMultipartMessage(ctx, roomID, messageID, options)
+}
+
+func (rs *rolloutService) EditSimpleMessage(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	options EditSimpleMessageOptions,
+) error {
+	service, _ := rs.route()
+	return service.EditSimpleMessage(ctx, roomID, messageID, options)
+}
+
+// Request is always performed against the stable service, since raw requests are built for
+// a specific version of the API.
+func (rs *rolloutService) Request(
+	ctx context.Context,
+	options client.RequestOptions,
+) (*http.Response, error) {
+	return rs.stable.Request(ctx, options)
+}
+
+// Subscribe is always performed against the stable service, like Request.
+func (rs *rolloutService) Subscribe(
+	ctx context.Context,
+	options client.RequestOptions,
+) (*common.SubscriptionStream, error) {
+	return rs.stable.Subscribe(ctx, options)
+}
+
+// SubscribeAsUser is always performed against the stable service, like Request.
+func (rs *rolloutService) SubscribeAsUser(
+	ctx context.Context,
+	userID string,
+	options client.RequestOptions,
+) (*common.SubscriptionStream, error) {
+	return rs.stable.SubscribeAsUser(ctx, userID, options)
+}
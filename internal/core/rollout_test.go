@@ -0,0 +1,153 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// stubService is a Service whose GetUser and CreateUser call the functions it is given. Calls
+// to any other method panic.
+type stubService struct {
+	Service
+	getUser    func(userID string) (User, error)
+	createUser func(options CreateUserOptions) (User, error)
+}
+
+func (s stubService) GetUser(ctx context.Context, userID string) (User, error) {
+	return s.getUser(userID)
+}
+
+func (s stubService) CreateUser(ctx context.Context, options CreateUserOptions) (User, error) {
+	return s.createUser(options)
+}
+
+func userNamed(name string) func(string) (User, error) {
+	return func(userID string) (User, error) {
+		return User{ID: userID, Name: name}, nil
+	}
+}
+
+func TestRolloutMirrorsReadsConcurrently(t *testing.T) {
+	// Each read waits for the other to start, so they only both complete if they run at the
+	// same time.
+	candidateStarted, stableStarted := make(chan struct{}), make(chan struct{})
+	waitFor := func(started chan struct{}, other chan struct{}) func(string) (User, error) {
+		return func(userID string) (User, error) {
+			close(started)
+			select {
+			case <-other:
+				return User{ID: userID}, nil
+			case <-time.After(5 * time.Second):
+				return User{}, errors.New("Timed out waiting for the other read")
+			}
+		}
+	}
+
+	service := NewRolloutService(
+		stubService{getUser: waitFor(stableStarted, candidateStarted)},
+		stubService{getUser: waitFor(candidateStarted, stableStarted)},
+		RolloutOptions{Percentage: 100, OnDivergence: func(RolloutDivergence) {}},
+	)
+
+	if _, err := service.GetUser(context.Background(), "alice"); err != nil {
+		t.Fatalf("Expected the reads to run concurrently, got %v", err)
+	}
+}
+
+func TestRolloutReportsDivergence(t *testing.T) {
+	var divergences []RolloutDivergence
+	service := NewRolloutService(
+		stubService{getUser: userNamed("Stable")},
+		stubService{getUser: userNamed("Candidate")},
+		RolloutOptions{Percentage: 100, OnDivergence: func(divergence RolloutDivergence) {
+			divergences = append(divergences, divergence)
+		}},
+	)
+
+	user, err := service.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Candidate" {
+		t.Errorf("Expected the candidate's result, got %+v", user)
+	}
+
+	if len(divergences) != 1 {
+		t.Fatalf("Expected one divergence, got %v", divergences)
+	}
+	divergence := divergences[0]
+	if divergence.Method != "GetUser" ||
+		divergence.StableResult.(User).Name != "Stable" ||
+		divergence.CandidateResult.(User).Name != "Candidate" {
+		t.Errorf("Expected the divergence of GetUser to be reported, got %+v", divergence)
+	}
+}
+
+func TestRolloutDoesNotReportMatchingReads(t *testing.T) {
+	service := NewRolloutService(
+		stubService{getUser: userNamed("Alice")},
+		stubService{getUser: userNamed("Alice")},
+		RolloutOptions{Percentage: 100, OnDivergence: func(divergence RolloutDivergence) {
+			t.Errorf("Expected no divergence, got %+v", divergence)
+		}},
+	)
+
+	if _, err := service.GetUser(context.Background(), "alice"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRolloutOnlyMirrorsCandidateReads(t *testing.T) {
+	service := NewRolloutService(
+		stubService{getUser: userNamed("Stable")},
+		stubService{getUser: func(string) (User, error) {
+			t.Error("Expected no read to be routed through the candidate")
+			return User{}, nil
+		}},
+		RolloutOptions{Percentage: 0, OnDivergence: func(divergence RolloutDivergence) {
+			t.Errorf("Expected no divergence, got %+v", divergence)
+		}},
+	)
+
+	user, err := service.GetUser(context.Background(), "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Name != "Stable" {
+		t.Errorf("Expected the stable service's result, got %+v", user)
+	}
+}
+
+func TestRolloutDoesNotMirrorWithoutOnDivergence(t *testing.T) {
+	service := NewRolloutService(
+		stubService{getUser: func(string) (User, error) {
+			t.Error("Expected the read not to be mirrored")
+			return User{}, nil
+		}},
+		stubService{getUser: userNamed("Candidate")},
+		RolloutOptions{Percentage: 100},
+	)
+
+	if _, err := service.GetUser(context.Background(), "alice"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRolloutPerformsWritesOnce(t *testing.T) {
+	service := NewRolloutService(
+		stubService{createUser: func(CreateUserOptions) (User, error) {
+			t.Error("Expected the write not to be performed against the stable service")
+			return User{}, nil
+		}},
+		stubService{createUser: func(options CreateUserOptions) (User, error) {
+			return User{ID: options.ID}, nil
+		}},
+		RolloutOptions{Percentage: 100, OnDivergence: func(RolloutDivergence) {}},
+	)
+
+	if _, err := service.CreateUser(context.Background(), CreateUserOptions{ID: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"encoding/json"
 	"io"
+	"mime"
+	"strings"
 	"time"
 )
 
@@ -45,6 +48,8 @@ type Message struct {
 	Text      string    `json:"text"`       // Content of the message
 	CreatedAt time.Time `json:"created_at"` // Creation timestamp
 	UpdatedAt time.Time `json:"updated_at"` // Updation timestamp
+	// Parts composing the message. Only set on messages fetched with GetMessages.
+	Parts []Part `json:"parts,omitempty"`
 }
 
 func (Message) isMessageIsh() {}
@@ -57,10 +62,91 @@ type MultipartMessage struct {
 	Parts     []Part    `json:"parts"`      // Parts composing the message
 	CreatedAt time.Time `json:"created_at"` // Creation timestamp
 	UpdatedAt time.Time `json:"updated_at"` // Updation timestamp
+	// ID of the message this message is a reply to, read from its thread part.
+	ParentMessageID *uint `json:"parent_message_id,omitempty"`
+	// Reactions to the message, most popular first. Set by the client when fetching messages
+	// with IncludeReactions.
+	Reactions []ReactionSummary `json:"reactions,omitempty"`
+}
+
+// ReactionSummary describes the users that reacted to a message with a reaction.
+type ReactionSummary struct {
+	Reaction string   `json:"reaction"` // The reaction, e.g. an emoji
+	Count    int      `json:"count"`    // Number of users that reacted
+	UserIDs  []string `json:"user_ids"` // Users that reacted, in the order they reacted
+}
+
+// ThreadPartType is the content type of the inline part that marks a message as a reply in the
+// thread of another message. Its content is a JSON object with the ID of the parent message,
+// e.g. {"parent_message_id":42}.
+const ThreadPartType = "application/x.thread+json"
+
+type threadPartContent struct {
+	ParentMessageID uint `json:"parent_message_id"`
+}
+
+func newThreadPart(parentMessageID uint) NewInlinePart {
+	content, _ := json.Marshal(threadPartContent{ParentMessageID: parentMessageID})
+	return NewInlinePart{Type: ThreadPartType, Content: string(content)}
+}
+
+// setParentMessageID sets ParentMessageID from the message's thread part, if it has one.
+func (m *MultipartMessage) setParentMessageID() {
+	for _, part := range m.Parts {
+		if part.Type != ThreadPartType || part.Content == nil {
+			continue
+		}
+
+		var content threadPartContent
+		if err := json.Unmarshal([]byte(*part.Content), &content); err == nil {
+			m.ParentMessageID = &content.ParentMessageID
+		}
+		return
+	}
+}
+
+// DecodeMultipartMessage decodes a message as sent by Chatkit, e.g. in the events of a room
+// subscription.
+func DecodeMultipartMessage(data []byte) (MultipartMessage, error) {
+	var message MultipartMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		return MultipartMessage{}, err
+	}
+
+	message.setParentMessageID()
+	return message, nil
 }
 
 func (MultipartMessage) isMessageIsh() {}
 
+// PlainText returns the content of the message's text/plain parts, separated by newlines.
+func (m MultipartMessage) PlainText() string {
+	texts := []string{}
+	for _, part := range m.Parts {
+		if part.Content == nil {
+			continue
+		}
+		if mediaType, _, err := mime.ParseMediaType(part.Type); err == nil && mediaType == "text/plain" {
+			texts = append(texts, *part.Content)
+		}
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+// AsMessage returns m as a Message, with the plain text rendering of its parts as its Text.
+func (m MultipartMessage) AsMessage() Message {
+	return Message{
+		ID:        m.ID,
+		UserID:    m.UserID,
+		RoomID:    m.RoomID,
+		Text:      m.PlainText(),
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+		Parts:     m.Parts,
+	}
+}
+
 type Part struct {
 	Type       string      `json:"type"`
 	Content    *string     `json:"content,omitempty"`
@@ -122,6 +208,9 @@ type UpdateRoomOptions struct {
 	PushNotificationTitleOverride *string     `json:"push_notification_title_override,omitempty"`
 	Private                       *bool       `json:"private,omitempty"`
 	CustomData                    interface{} `json:"custom_data,omitempty"`
+	// Optional UpdatedAt of the room as it was read. If the room has been updated since, the
+	// update is not performed and ErrConflict is returned.
+	ExpectedUpdatedAt *time.Time `json:"-"`
 }
 
 // ExplicitlyResetPushNotificationTitleOverride when used in the UpdateRoomOptions
@@ -136,6 +225,14 @@ type SendMultipartMessageOptions struct {
 	RoomID   string
 	SenderID string
 	Parts    []NewPart
+	// Optional ID of the message this message replies to in a thread. It is recorded in a part
+	// of type ThreadPartType appended to the message.
+	ParentMessageID *uint
+	// Optional key identifying the message. Sending again with the same key, for example when
+	// retrying after a timeout, returns the ID of the message already sent instead.
+	IdempotencyKey string
+	// Optional time after which the message is deleted by the SDK's message reaper.
+	ExpiresAfter time.Duration
 }
 
 // SendSimpleMessageOptions contains parameters to pass when sending a new message.
@@ -143,6 +240,11 @@ type SendSimpleMessageOptions struct {
 	RoomID   string
 	Text     string
 	SenderID string
+	// Optional key identifying the message. Sending again with the same key, for example when
+	// retrying after a timeout, returns the ID of the message already sent instead.
+	IdempotencyKey string
+	// Optional time after which the message is deleted by the SDK's message reaper.
+	ExpiresAfter time.Duration
 }
 
 type EditMessageOptions = EditSimpleMessageOptions
@@ -179,15 +281,46 @@ func (p NewURLPart) isNewPart() {}
 // NewAttachmentPart has no JSON annotations because it cannot be sent directly
 // to the backend. The attachment must first be uploaded and a
 // newAttachmentPartUploaded sent instead.
+//
+// The file is streamed to Chatkit when its size is known, either from Size or because File is
+// an io.Seeker (such as an *os.File). Otherwise it is read in to memory first.
 type NewAttachmentPart struct {
 	Type       string
 	Name       *string
 	CustomData interface{}
 	File       io.Reader
+	Size       int64 // Optional number of bytes to upload from File
+	// Optional function called as the file is uploaded with the number of bytes sent so far.
+	// It may be called from a different goroutine to the one sending the message.
+	Progress func(bytesSent int64, totalBytes int64)
 }
 
 func (p NewAttachmentPart) isNewPart() {}
 
+// NewExistingAttachmentPart refers to an attachment that has already been uploaded, such as one
+// belonging to a message that is being edited, so that it is kept without uploading it again.
+type NewExistingAttachmentPart struct {
+	Type         string
+	AttachmentID string
+}
+
+func (p NewExistingAttachmentPart) isNewPart() {}
+
+// AsNewPart returns a NewPart with the same content as p, which can be used to send or edit a
+// message with the same part. Attachments are referred to rather than uploaded again.
+func (p Part) AsNewPart() NewPart {
+	switch {
+	case p.Attachment != nil:
+		return NewExistingAttachmentPart{Type: p.Type, AttachmentID: p.Attachment.ID}
+	case p.URL != nil:
+		return NewURLPart{Type: p.Type, URL: *p.URL}
+	case p.Content != nil:
+		return NewInlinePart{Type: p.Type, Content: *p.Content}
+	default:
+		return NewInlinePart{Type: p.Type}
+	}
+}
+
 type newAttachmentPartUploaded struct {
 	Type       string             `json:"type"`
 	Attachment uploadedAttachment `json:"attachment"`
@@ -201,12 +334,17 @@ type uploadedAttachment struct {
 type FetchMultipartMessageOptions struct {
 	RoomID    string
 	MessageID uint
+	// Whether to include the reactions to the message, read from the client's Store.
+	IncludeReactions bool
 }
 
 type fetchMessagesOptions struct {
 	InitialID *uint   // Starting ID of messages to retrieve
 	Direction *string // One of older or newer
 	Limit     *uint   // Number of messages to retrieve
+	// Whether FetchMultipartMessages includes the reactions to the messages, read from the
+	// client's Store. Ignored by GetRoomMessages.
+	IncludeReactions bool
 }
 
 // FetchMultipartMessagesOptions contains parameters to pass when fetching messages from a room.
@@ -215,6 +353,22 @@ type FetchMultipartMessagesOptions = fetchMessagesOptions
 // GetRoomMessagesOptions contains parameters to pass when fetching messages from a room.
 type GetRoomMessagesOptions = fetchMessagesOptions
 
+// GetMessagesOptions contains parameters to pass when fetching messages from a room.
+type GetMessagesOptions = fetchMessagesOptions
+
+// Statuses of an asynchronous deletion job.
+const (
+	DeleteStatusPending   = "pending"
+	DeleteStatusCompleted = "completed"
+	DeleteStatusFailed    = "failed"
+)
+
+// DeleteStatus describes the progress of an asynchronous deletion job.
+type DeleteStatus struct {
+	JobID  string `json:"job_id"` // ID of the deletion job
+	Status string `json:"status"` // One of DeleteStatusPending, DeleteStatusCompleted or DeleteStatusFailed
+}
+
 type DeleteMessageOptions struct {
 	RoomID    string
 	MessageID uint
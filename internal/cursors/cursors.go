@@ -6,47 +6,137 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/pusher/chatkit-server-go/internal/common"
 
 	"github.com/pusher/pusher-platform-go/client"
-	"github.com/pusher/pusher-platform-go/instance"
 )
 
-const readCursorType = 0
+// CursorTypeRead is the type of read cursors, which record the last message a user has read in a
+// room.
+const CursorTypeRead uint = 0
 
 // Exposes methods to interact with the cursors API.
 type Service interface {
 	GetUserReadCursors(ctx context.Context, userID string) ([]Cursor, error)
 	SetReadCursor(ctx context.Context, userID string, roomID string, position uint) error
 	GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error)
+	GetReadCursorsForRoomPage(
+		ctx context.Context,
+		roomID string,
+		options GetReadCursorsForRoomOptions,
+	) ([]Cursor, error)
 	GetReadCursor(ctx context.Context, userID string, roomID string) (Cursor, error)
+	DeleteReadCursor(ctx context.Context, userID string, roomID string) error
+
+	// Cursors of any type
+	GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]Cursor, error)
+	SetCursor(ctx context.Context, cursorType uint, userID string, roomID string, position uint) error
+	GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]Cursor, error)
+	GetCursor(ctx context.Context, cursorType uint, userID string, roomID string) (Cursor, error)
+	DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error
 
 	// Generic requests
 	Request(ctx context.Context, options client.RequestOptions) (*http.Response, error)
+	Subscribe(ctx context.Context, options client.RequestOptions) (*common.SubscriptionStream, error)
 }
 
 type cursorsService struct {
-	underlyingInstance instance.Instance
+	underlyingInstance common.Requester
+	decoder            common.Decoder
 }
 
 // Returns a new cursorsService instance conforming to
 // the Service interface
-func NewService(platformInstance instance.Instance) Service {
+func NewService(platformInstance common.Requester, decoder common.Decoder) Service {
 	return &cursorsService{
 		underlyingInstance: platformInstance,
+		decoder:            decoder,
 	}
 }
 
 // GetUserReadCursors retrieves cursors for a user.
 func (cs *cursorsService) GetUserReadCursors(ctx context.Context, userID string) ([]Cursor, error) {
+	return cs.GetUserCursors(ctx, CursorTypeRead, userID)
+}
+
+// SetReadCursor sets a read cursor for a given room and user.
+func (cs *cursorsService) SetReadCursor(
+	ctx context.Context,
+	userID string,
+	roomID string,
+	position uint,
+) error {
+	return cs.SetCursor(ctx, CursorTypeRead, userID, roomID, position)
+}
+
+// GetReadCursorsForRoom retrieves read cursors for a given room.
+func (cs *cursorsService) GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error) {
+	return cs.GetCursorsForRoom(ctx, CursorTypeRead, roomID)
+}
+
+// GetReadCursorsForRoomPage retrieves a page of the read cursors for a given room, ordered by
+// user ID.
+func (cs *cursorsService) GetReadCursorsForRoomPage(
+	ctx context.Context,
+	roomID string,
+	options GetReadCursorsForRoomOptions,
+) ([]Cursor, error) {
+	queryParams := url.Values{}
+	if options.FromUserID != "" {
+		queryParams.Add("from_user_id", options.FromUserID)
+	}
+
+	if options.Limit != 0 {
+		queryParams.Add("limit", strconv.Itoa(int(options.Limit)))
+	}
+
+	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
+		Method:      http.MethodGet,
+		Path:        fmt.Sprintf("/cursors/%d/rooms/%s", CursorTypeRead, url.PathEscape(roomID)),
+		QueryParams: &queryParams,
+	})
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cursors []Cursor
+	err = cs.decoder.Decode(response.Body, &cursors)
+	if err != nil {
+		return nil, err
+	}
+
+	return cursors, nil
+}
+
+// GetReadCursor fetches a single cursor for a given user and room.
+func (cs *cursorsService) GetReadCursor(
+	ctx context.Context,
+	userID string,
+	roomID string,
+) (Cursor, error) {
+	return cs.GetCursor(ctx, CursorTypeRead, userID, roomID)
+}
+
+// DeleteReadCursor deletes the read cursor of a user in a room.
+func (cs *cursorsService) DeleteReadCursor(ctx context.Context, userID string, roomID string) error {
+	return cs.DeleteCursor(ctx, CursorTypeRead, userID, roomID)
+}
+
+// GetUserCursors retrieves the cursors of a type for a user.
+func (cs *cursorsService) GetUserCursors(ctx context.Context, cursorType uint, userID string) ([]Cursor, error) {
 	if userID == "" {
-		return nil, errors.New("You must provide the ID of the user whos read cursors you want to fetch")
+		return nil, errors.New("You must provide the ID of the user whos cursors you want to fetch")
 	}
 
 	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/cursors/%d/users/%s", readCursorType, userID),
+		Path:   fmt.Sprintf("/cursors/%d/users/%s", cursorType, url.PathEscape(userID)),
 	})
 	if err != nil {
 		return nil, err
@@ -54,7 +144,7 @@ func (cs *cursorsService) GetUserReadCursors(ctx context.Context, userID string)
 	defer response.Body.Close()
 
 	var cursors []Cursor
-	err = common.DecodeResponseBody(response.Body, &cursors)
+	err = cs.decoder.Decode(response.Body, &cursors)
 	if err != nil {
 		return nil, err
 	}
@@ -62,15 +152,16 @@ func (cs *cursorsService) GetUserReadCursors(ctx context.Context, userID string)
 	return cursors, nil
 }
 
-// SetReadCursor sets a read cursor for a given room and user.
-func (cs *cursorsService) SetReadCursor(
+// SetCursor sets a cursor of a type for a given room and user.
+func (cs *cursorsService) SetCursor(
 	ctx context.Context,
+	cursorType uint,
 	userID string,
 	roomID string,
 	position uint,
 ) error {
 	if userID == "" {
-		return errors.New("You must provide the ID of the user whose read cursor you want to set")
+		return errors.New("You must provide the ID of the user whose cursor you want to set")
 	}
 
 	requestBody, err := common.CreateRequestBody(map[string]uint{"position": position})
@@ -82,9 +173,9 @@ func (cs *cursorsService) SetReadCursor(
 		Method: http.MethodPut,
 		Path: fmt.Sprintf(
 			"/cursors/%d/rooms/%s/users/%s",
-			readCursorType,
-			roomID,
-			userID,
+			cursorType,
+			url.PathEscape(roomID),
+			url.PathEscape(userID),
 		),
 		Body: requestBody,
 	})
@@ -96,11 +187,11 @@ func (cs *cursorsService) SetReadCursor(
 	return nil
 }
 
-// GetReadCursorsForRoom retrieves read cursors for a given room.
-func (cs *cursorsService) GetReadCursorsForRoom(ctx context.Context, roomID string) ([]Cursor, error) {
+// GetCursorsForRoom retrieves the cursors of a type for a given room.
+func (cs *cursorsService) GetCursorsForRoom(ctx context.Context, cursorType uint, roomID string) ([]Cursor, error) {
 	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/cursors/%d/rooms/%s", readCursorType, roomID),
+		Path:   fmt.Sprintf("/cursors/%d/rooms/%s", cursorType, url.PathEscape(roomID)),
 	})
 	if err != nil {
 		return nil, err
@@ -108,7 +199,7 @@ func (cs *cursorsService) GetReadCursorsForRoom(ctx context.Context, roomID stri
 	defer response.Body.Close()
 
 	var cursors []Cursor
-	err = common.DecodeResponseBody(response.Body, &cursors)
+	err = cs.decoder.Decode(response.Body, &cursors)
 	if err != nil {
 		return nil, err
 	}
@@ -116,30 +207,62 @@ func (cs *cursorsService) GetReadCursorsForRoom(ctx context.Context, roomID stri
 	return cursors, nil
 }
 
-// GetReadCursor fetches a single cursor for a given user and room.
-func (cs *cursorsService) GetReadCursor(
+// GetCursor fetches a single cursor of a type for a given user and room.
+func (cs *cursorsService) GetCursor(
 	ctx context.Context,
+	cursorType uint,
 	userID string,
 	roomID string,
 ) (Cursor, error) {
 	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
 		Method: http.MethodGet,
-		Path:   fmt.Sprintf("/cursors/%d/rooms/%s/users/%s", readCursorType, roomID, userID),
+		Path: fmt.Sprintf(
+			"/cursors/%d/rooms/%s/users/%s",
+			cursorType,
+			url.PathEscape(roomID),
+			url.PathEscape(userID),
+		),
 	})
 	if err != nil {
-		return Cursor{}, nil
+		return Cursor{}, err
 	}
 	defer response.Body.Close()
 
 	var cursor Cursor
-	err = common.DecodeResponseBody(response.Body, &cursor)
+	err = cs.decoder.Decode(response.Body, &cursor)
 	if err != nil {
-		return Cursor{}, nil
+		return Cursor{}, err
 	}
 
 	return cursor, nil
 }
 
+// DeleteCursor deletes the cursor of a type of a user in a room.
+func (cs *cursorsService) DeleteCursor(ctx context.Context, cursorType uint, userID string, roomID string) error {
+	if userID == "" {
+		return errors.New("You must provide the ID of the user whose cursor you want to delete")
+	}
+
+	if roomID == "" {
+		return errors.New("You must provide the ID of the room whose cursor you want to delete")
+	}
+
+	response, err := common.RequestWithSuToken(cs.underlyingInstance, ctx, client.RequestOptions{
+		Method: http.MethodDelete,
+		Path: fmt.Sprintf(
+			"/cursors/%d/rooms/%s/users/%s",
+			cursorType,
+			url.PathEscape(roomID),
+			url.PathEscape(userID),
+		),
+	})
+	if response != nil {
+		defer response.Body.Close()
+	}
+
+	return err
+}
+
 // Request allows performing requests to the cursors service and returns the raw http response.
 func (cs *cursorsService) Request(
 	ctx context.Context,
@@ -147,3 +270,11 @@ func (cs *cursorsService) Request(
 ) (*http.Response, error) {
 	return cs.underlyingInstance.Request(ctx, options)
 }
+
+// Subscribe opens a subscription to the cursors service with a token with the `su` claim.
+func (cs *cursorsService) Subscribe(
+	ctx context.Context,
+	options client.RequestOptions,
+) (*common.SubscriptionStream, error) {
+	return common.SubscribeWithSuToken(cs.underlyingInstance, ctx, options)
+}
@@ -1,7 +1,10 @@
 package cursors
 
 import (
+	"encoding/json"
 	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
 )
 
 // Cursor represents a read cursor.
@@ -12,3 +15,34 @@ type Cursor struct {
 	Position   uint      `json:"position"`
 	UpdatedAt  time.Time `json:"updated_at"`
 }
+
+func (c *Cursor) UnmarshalJSON(b []byte) error {
+	// Custom unmarshal logic because older versions of the cursors service give us numeric room
+	// IDs.
+	type cursor Cursor
+	var raw struct {
+		cursor
+		RoomID json.RawMessage `json:"room_id"`
+	}
+
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+
+	roomID, err := common.UnmarshalID(raw.RoomID)
+	if err != nil {
+		return err
+	}
+
+	*c = Cursor(raw.cursor)
+	c.RoomID = roomID
+
+	return nil
+}
+
+// GetReadCursorsForRoomOptions contains parameters to pass when fetching a page of a room's read
+// cursors.
+type GetReadCursorsForRoomOptions struct {
+	FromUserID string // Only return cursors of users whose ID sorts after this one
+	Limit      uint   // Maximum number of cursors to return
+}
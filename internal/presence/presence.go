@@ -0,0 +1,80 @@
+// Package presence exposes an interface that allows making requests to the Chatkit presence service.
+package presence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+
+	"github.com/pusher/pusher-platform-go/client"
+)
+
+// Exposes methods to interact with the presence API.
+type Service interface {
+	GetUserPresence(ctx context.Context, userID string) (UserPresence, error)
+
+	// Generic requests
+	Request(ctx context.Context, options client.RequestOptions) (*http.Response, error)
+	Subscribe(ctx context.Context, options client.RequestOptions) (*common.SubscriptionStream, error)
+}
+
+type presenceService struct {
+	underlyingInstance common.Requester
+	decoder            common.Decoder
+}
+
+// Returns a new presenceService instance conforming to
+// the Service interface
+func NewService(platformInstance common.Requester, decoder common.Decoder) Service {
+	return &presenceService{
+		underlyingInstance: platformInstance,
+		decoder:            decoder,
+	}
+}
+
+// GetUserPresence retrieves the presence state of a user.
+func (ps *presenceService) GetUserPresence(ctx context.Context, userID string) (UserPresence, error) {
+	if userID == "" {
+		return UserPresence{}, errors.New("You must provide the ID of the user whose presence you want to fetch")
+	}
+
+	response, err := common.RequestWithSuToken(ps.underlyingInstance, ctx, client.RequestOptions{
+		Method: http.MethodGet,
+		Path:   fmt.Sprintf("/users/%s", url.PathEscape(userID)),
+	})
+	if response != nil {
+		defer response.Body.Close()
+	}
+	if err != nil {
+		return UserPresence{}, err
+	}
+
+	var presence UserPresence
+	err = ps.decoder.Decode(response.Body, &presence)
+	if err != nil {
+		return UserPresence{}, err
+	}
+	presence.UserID = userID
+
+	return presence, nil
+}
+
+// Request allows performing requests to the presence service and returns the raw http response.
+func (ps *presenceService) Request(
+	ctx context.Context,
+	options client.RequestOptions,
+) (*http.Response, error) {
+	return ps.underlyingInstance.Request(ctx, options)
+}
+
+// Subscribe opens a subscription to the presence service with a token with the `su` claim.
+func (ps *presenceService) Subscribe(
+	ctx context.Context,
+	options client.RequestOptions,
+) (*common.SubscriptionStream, error) {
+	return common.SubscribeWithSuToken(ps.underlyingInstance, ctx, options)
+}
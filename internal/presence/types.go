@@ -0,0 +1,13 @@
+package presence
+
+// Presence states.
+const (
+	StateOnline  = "online"
+	StateOffline = "offline"
+)
+
+// UserPresence represents the presence state of a user.
+type UserPresence struct {
+	UserID string `json:"user_id"`
+	State  string `json:"state"` // One of online or offline
+}
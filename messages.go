@@ -0,0 +1,423 @@
+package chatkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// defaultMessagesPageSize is the number of messages requested per page when paging through a
+// room's history.
+const defaultMessagesPageSize = 100
+
+// IterateRoomMessagesOptions contains parameters to pass when iterating over a room's messages.
+type IterateRoomMessagesOptions struct {
+	// Either "older", the default, to start from the newest message and go back in time, or
+	// "newer" to start from the oldest message.
+	Direction string
+	// Only return messages older (or newer) than the message with this ID.
+	InitialID *uint
+	// Number of messages fetched per request. Defaults to 100.
+	PageSize uint
+}
+
+// MessageIterator pages through the history of a room.
+// Pages are only requested once the previous one has been consumed.
+//
+//	it := client.IterateRoomMessages(ctx, roomID, IterateRoomMessagesOptions{})
+//	for it.Next() {
+//		message := it.Message()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type MessageIterator struct {
+	ctx       context.Context
+	client    *Client
+	roomID    string
+	direction string
+	pageSize  uint
+
+	initialID *uint
+	page      []MultipartMessage
+	current   MultipartMessage
+	lastPage  bool
+	err       error
+}
+
+// IterateRoomMessages returns an iterator over the messages of a room.
+func (m MessagesClient) IterateRoomMessages(
+	ctx context.Context,
+	roomID string,
+	options IterateRoomMessagesOptions,
+) *MessageIterator {
+	direction := options.Direction
+	if direction == "" {
+		direction = "older"
+	}
+
+	pageSize := options.PageSize
+	if pageSize == 0 {
+		pageSize = defaultMessagesPageSize
+	}
+
+	initialID := options.InitialID
+	if initialID == nil && direction == "newer" {
+		initialID = new(uint)
+	}
+
+	return &MessageIterator{
+		ctx:       ctx,
+		client:    m.client,
+		roomID:    roomID,
+		direction: direction,
+		pageSize:  pageSize,
+		initialID: initialID,
+	}
+}
+
+// Next advances the iterator to the next message.
+// It returns false when there are no more messages or an error occurred.
+func (it *MessageIterator) Next() bool {
+	if len(it.page) == 0 && !it.lastPage && it.err == nil {
+		it.fetchPage()
+	}
+
+	if len(it.page) == 0 {
+		return false
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+
+	return true
+}
+
+// Message returns the message the iterator currently points at.
+func (it *MessageIterator) Message() MultipartMessage {
+	return it.current
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *MessageIterator) Err() error {
+	return it.err
+}
+
+// fetchPage requests the page of messages following the last message returned.
+func (it *MessageIterator) fetchPage() {
+	messages, err := it.client.Messages().FetchMultipartMessages(it.ctx, it.roomID, FetchMultipartMessagesOptions{
+		Direction: &it.direction,
+		InitialID: it.initialID,
+		Limit:     &it.pageSize,
+	})
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	if uint(len(messages)) < it.pageSize {
+		it.lastPage = true
+	}
+
+	if len(messages) == 0 {
+		return
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		if it.direction == "newer" {
+			return messages[i].ID < messages[j].ID
+		}
+		return messages[i].ID > messages[j].ID
+	})
+
+	lastID := messages[len(messages)-1].ID
+	it.initialID = &lastID
+	it.page = messages
+}
+
+// GetMessages retrieves messages previously sent to a room based on the options provided.
+// Unlike GetRoomMessages it returns the content of multipart messages, in Parts, and unlike
+// FetchMultipartMessages it also renders their text/plain parts as Text, so the same code can
+// handle messages sent with either API. Messages are ordered in the direction requested: newest
+// first by default, or oldest first when Direction is "newer".
+func (m MessagesClient) GetMessages(ctx context.Context, roomID string, options GetMessagesOptions) ([]Message, error) {
+	multipartMessages, err := m.FetchMultipartMessages(ctx, roomID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	newer := options.Direction != nil && *options.Direction == "newer"
+	sort.Slice(multipartMessages, func(i, j int) bool {
+		if newer {
+			return multipartMessages[i].ID < multipartMessages[j].ID
+		}
+		return multipartMessages[i].ID > multipartMessages[j].ID
+	})
+
+	messages := make([]Message, len(multipartMessages))
+	for i, message := range multipartMessages {
+		messages[i] = message.AsMessage()
+	}
+
+	return messages, nil
+}
+
+// FetchLatestMessagesForRooms fetches up to limit of the newest messages of each room
+// concurrently, e.g. to show a preview of the last message of every room in a user's room list.
+// Messages are returned newest first, keyed by room ID. If some rooms could not be fetched the
+// error is a *BatchError, keyed by room ID, and those rooms are missing from the map.
+func (m MessagesClient) FetchLatestMessagesForRooms(
+	ctx context.Context,
+	roomIDs []string,
+	limit uint,
+) (map[string][]MultipartMessage, error) {
+	if limit == 0 {
+		limit = 1
+	}
+
+	var (
+		mu       sync.Mutex
+		messages = make(map[string][]MultipartMessage, len(roomIDs))
+	)
+
+	err := forEachConcurrently(ctx, uniqueStrings(roomIDs), m.client.concurrencyFor(0), func(ctx context.Context, roomID string) error {
+		roomMessages, err := m.FetchMultipartMessages(ctx, roomID, FetchMultipartMessagesOptions{
+			Limit: &limit,
+		})
+		if err != nil {
+			return err
+		}
+
+		sort.Slice(roomMessages, func(i, j int) bool {
+			return roomMessages[i].ID > roomMessages[j].ID
+		})
+
+		mu.Lock()
+		messages[roomID] = roomMessages
+		mu.Unlock()
+		return nil
+	})
+
+	return messages, err
+}
+
+// SendMessageAndGet publishes a new message to a room like SendMessage, but returns the whole
+// message as stored by Chatkit, including its CreatedAt timestamp. If the message was sent but its
+// ExpiresAfter or IdempotencyKey could not be recorded, it is returned with an *ExpiryError or
+// *IdempotencyKeyError.
+func (m MessagesClient) SendMessageAndGet(ctx context.Context, options SendMessageOptions) (Message, error) {
+	messageID, sendErr := m.SendMessage(ctx, options)
+	if sendFailed(sendErr) {
+		return Message{}, sendErr
+	}
+
+	// Messages are fetched from before an ID, so ask for the one message before the next ID.
+	initialID := messageID + 1
+	limit := uint(1)
+	messages, err := m.GetRoomMessages(ctx, options.RoomID, GetRoomMessagesOptions{
+		InitialID: &initialID,
+		Limit:     &limit,
+	})
+	if err != nil {
+		return Message{}, err
+	}
+
+	if len(messages) == 0 || messages[0].ID != messageID {
+		return Message{}, fmt.Errorf("Failed to fetch sent message %d", messageID)
+	}
+
+	return messages[0], sendErr
+}
+
+// SendMultipartMessageAndGet publishes a new multipart message to a room like
+// SendMultipartMessage, but returns the whole message as stored by Chatkit, including its
+// CreatedAt timestamp and the details of any uploaded attachments. If the message was sent but its
+// ExpiresAfter or IdempotencyKey could not be recorded, it is returned with an *ExpiryError or
+// *IdempotencyKeyError.
+func (m MessagesClient) SendMultipartMessageAndGet(
+	ctx context.Context,
+	options SendMultipartMessageOptions,
+) (MultipartMessage, error) {
+	messageID, sendErr := m.SendMultipartMessage(ctx, options)
+	if sendFailed(sendErr) {
+		return MultipartMessage{}, sendErr
+	}
+
+	return m.fetchSentMessage(ctx, options.RoomID, messageID, sendErr)
+}
+
+// SendSimpleMessageAndGet publishes a new simple multipart message to a room like
+// SendSimpleMessage, but returns the whole message as stored by Chatkit. If the message was sent
+// but its ExpiresAfter or IdempotencyKey could not be recorded, it is returned with an
+// *ExpiryError or *IdempotencyKeyError.
+func (m MessagesClient) SendSimpleMessageAndGet(
+	ctx context.Context,
+	options SendSimpleMessageOptions,
+) (MultipartMessage, error) {
+	messageID, sendErr := m.SendSimpleMessage(ctx, options)
+	if sendFailed(sendErr) {
+		return MultipartMessage{}, sendErr
+	}
+
+	return m.fetchSentMessage(ctx, options.RoomID, messageID, sendErr)
+}
+
+// fetchSentMessage fetches a message that was just sent, returning it with sendErr, the
+// *ExpiryError or *IdempotencyKeyError the send returned if any.
+func (m MessagesClient) fetchSentMessage(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	sendErr error,
+) (MultipartMessage, error) {
+	message, err := m.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+		RoomID:    roomID,
+		MessageID: messageID,
+	})
+	if err != nil {
+		return MultipartMessage{}, err
+	}
+
+	return message, sendErr
+}
+
+// idempotencyKeyTTL is how long the ID of a message sent with an idempotency key is remembered.
+const idempotencyKeyTTL = 24 * time.Hour
+
+// maxIdempotencyKeys is how many idempotency keys are remembered when no Store is configured.
+// The least recently used keys are forgotten first.
+const maxIdempotencyKeys = 10000
+
+// keyLocks holds the locks used to serialise operations on the same key within this process, such
+// as sends with the same idempotency key, so that concurrent retries don't both miss the store.
+var keyLocks = struct {
+	sync.Mutex
+	keys map[string]*keyLock
+}{keys: map[string]*keyLock{}}
+
+type keyLock struct {
+	sync.Mutex
+	waiters int
+}
+
+// lockKey locks key and returns the function that unlocks it.
+func lockKey(key string) func() {
+	keyLocks.Lock()
+	lock, ok := keyLocks.keys[key]
+	if !ok {
+		lock = &keyLock{}
+		keyLocks.keys[key] = lock
+	}
+	lock.waiters++
+	keyLocks.Unlock()
+
+	lock.Lock()
+	return func() {
+		keyLocks.Lock()
+		lock.waiters--
+		if lock.waiters == 0 {
+			delete(keyLocks.keys, key)
+		}
+		keyLocks.Unlock()
+		lock.Unlock()
+	}
+}
+
+// lockMessage locks a message against concurrent read-modify-write edits within this process, and
+// returns the function that unlocks it.
+func lockMessage(roomID string, messageID uint) func() {
+	return lockKey(fmt.Sprintf("messages/%s/%d", roomID, messageID))
+}
+
+// sendIdempotently calls send unless a message has already been sent to the room with the same
+// idempotency key, in which case the ID of that message is returned.
+// Sent message IDs are remembered in the client's Store for a day. Without one, the most recent
+// keys are remembered in memory. Use a shared Store (see WithStore) for keys to be honoured
+// across processes.
+func (c *Client) sendIdempotently(
+	ctx context.Context,
+	roomID string,
+	idempotencyKey string,
+	send func() (uint, error),
+) (uint, error) {
+	if idempotencyKey == "" {
+		return send()
+	}
+
+	storeKey := fmt.Sprintf("idempotency/%s/%s", roomID, idempotencyKey)
+	defer lockKey(storeKey)()
+
+	value, err := c.idempotencyStore.Get(ctx, storeKey)
+	if err == nil {
+		messageID, err := strconv.ParseUint(string(value), 10, 0)
+		if err != nil {
+			return 0, fmt.Errorf("Failed to read message ID for idempotency key %s: %v", idempotencyKey, err)
+		}
+		return uint(messageID), nil
+	}
+	if err != ErrStoreKeyNotFound {
+		return 0, err
+	}
+
+	messageID, err := send()
+	if err != nil {
+		return 0, err
+	}
+
+	err = c.idempotencyStore.Put(ctx, storeKey, []byte(strconv.FormatUint(uint64(messageID), 10)), idempotencyKeyTTL)
+	if err != nil {
+		return messageID, &IdempotencyKeyError{
+			RoomID:         roomID,
+			MessageID:      messageID,
+			IdempotencyKey: idempotencyKey,
+			Err:            err,
+		}
+	}
+
+	return messageID, nil
+}
+
+// sendMessage sends a message with send, idempotently, and records when it expires. If the
+// message was sent but its idempotency key or expiry could not be stored, its ID is returned with
+// an *IdempotencyKeyError or *ExpiryError.
+func (c *Client) sendMessage(
+	ctx context.Context,
+	roomID string,
+	idempotencyKey string,
+	expiresAfter time.Duration,
+	send func() (uint, error),
+) (uint, error) {
+	messageID, err := c.sendIdempotently(ctx, roomID, idempotencyKey, send)
+	if sendFailed(err) {
+		return 0, err
+	}
+
+	if expiryErr := c.expireMessage(ctx, roomID, messageID, expiresAfter); expiryErr != nil {
+		return messageID, expiryErr
+	}
+
+	return messageID, err
+}
+
+// DeleteMessages deletes many messages of a room.
+// Chatkit has no batch deletion endpoint, so the messages are deleted concurrently. The result
+// reports the outcome for each message, keyed by message ID.
+func (m MessagesClient) DeleteMessages(
+	ctx context.Context,
+	roomID string,
+	messageIDs []uint,
+	options BatchOptions,
+) *BatchResult {
+	ids := make([]string, len(messageIDs))
+	for i, messageID := range messageIDs {
+		ids[i] = strconv.FormatUint(uint64(messageID), 10)
+	}
+
+	return m.client.newBatchExecutor(options).Execute(ctx, ids, func(ctx context.Context, id string) error {
+		messageID, _ := strconv.ParseUint(id, 10, 0)
+		return m.DeleteMessage(ctx, DeleteMessageOptions{RoomID: roomID, MessageID: uint(messageID)})
+	})
+}
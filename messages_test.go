@@ -0,0 +1,341 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSendStub returns a client whose sends succeed with increasing message IDs, and the number
+// of messages sent.
+func newSendStub(t *testing.T, options ...ClientOption) (*Client, func(), *int32) {
+	var sent int32
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		id := atomic.AddInt32(&sent, 1)
+		w.WriteHeader(http.StatusCreated)
+		writeTestJSON(w, map[string]interface{}{"message_id": id})
+	}, options...)
+
+	return client, server.Close, &sent
+}
+
+func TestSendIdempotentlyReturnsTheMessageAlreadySent(t *testing.T) {
+	client, closeServer, sent := newSendStub(t)
+	defer closeServer()
+
+	ctx := context.Background()
+	options := SendSimpleMessageOptions{RoomID: "general", Text: "hi", SenderID: "alice", IdempotencyKey: "key"}
+
+	first, err := client.Messages().SendSimpleMessage(ctx, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	retried, err := client.Messages().SendSimpleMessage(ctx, options)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count := atomic.LoadInt32(sent); retried != first || count != 1 {
+		t.Fatalf("Expected message %d to be sent once, got %d after %d sends", first, retried, count)
+	}
+}
+
+func TestIdempotencyKeysAreBoundedWithoutAStore(t *testing.T) {
+	client := newTestClient(t)
+	store, ok := client.idempotencyStore.(*lruStore)
+	if !ok {
+		t.Fatalf("Expected idempotency keys to be kept in an LRU store, got %T", client.idempotencyStore)
+	}
+	if store.maxEntries != maxIdempotencyKeys {
+		t.Fatalf("Expected at most %d keys, got %d", maxIdempotencyKeys, store.maxEntries)
+	}
+
+	ctx := context.Background()
+	for i := 0; i < maxIdempotencyKeys+10; i++ {
+		store.Put(ctx, fmt.Sprintf("idempotency/general/%d", i), []byte("1"), idempotencyKeyTTL)
+	}
+	if keys, _ := store.List(ctx, ""); len(keys) != maxIdempotencyKeys {
+		t.Fatalf("Expected %d keys to be kept, got %d", maxIdempotencyKeys, len(keys))
+	}
+}
+
+func TestIdempotencyKeysUseTheConfiguredStore(t *testing.T) {
+	store := NewMemoryStore()
+	client, closeServer, _ := newSendStub(t, WithStore(store))
+	defer closeServer()
+
+	ctx := context.Background()
+	_, err := client.Messages().SendSimpleMessage(ctx, SendSimpleMessageOptions{
+		RoomID:         "general",
+		Text:           "hi",
+		SenderID:       "alice",
+		IdempotencyKey: "key",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := store.Get(ctx, "idempotency/general/key"); err != nil {
+		t.Fatalf("Expected the key to be kept in the configured store, got %v", err)
+	}
+}
+
+// expiryFailingStore is a Store that fails to record the expiry of messages.
+type expiryFailingStore struct {
+	Store
+}
+
+func (s expiryFailingStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if strings.HasPrefix(key, expiringMessagesStorePrefix) {
+		return errors.New("disk full")
+	}
+	return s.Store.Put(ctx, key, value, ttl)
+}
+
+func TestSendReturnsTheMessageIDWhenItsExpiryCantBeStored(t *testing.T) {
+	client, closeServer, sent := newSendStub(t, WithStore(expiryFailingStore{NewMemoryStore()}))
+	defer closeServer()
+
+	messageID, err := client.Messages().SendSimpleMessage(context.Background(), SendSimpleMessageOptions{
+		RoomID:       "general",
+		Text:         "hi",
+		SenderID:     "alice",
+		ExpiresAfter: time.Minute,
+	})
+
+	expiryErr, ok := err.(*ExpiryError)
+	if !ok {
+		t.Fatalf("Expected an *ExpiryError, got %v", err)
+	}
+	if messageID != 1 || expiryErr.MessageID != 1 || expiryErr.RoomID != "general" {
+		t.Fatalf("Expected message 1 in general to be reported, got %d and %+v", messageID, expiryErr)
+	}
+	if sendFailed(err) {
+		t.Fatal("Expected the message to be reported as sent")
+	}
+	if count := atomic.LoadInt32(sent); count != 1 {
+		t.Fatalf("Expected the message to be sent once, got %d", count)
+	}
+}
+
+func TestSchedulerDoesNotResendMessagesWhoseExpiryCantBeStored(t *testing.T) {
+	client, closeServer, sent := newSendStub(t, WithStore(expiryFailingStore{NewMemoryStore()}))
+	defer closeServer()
+
+	var reported []error
+	scheduler := NewScheduler(client, SchedulerOptions{
+		OnError: func(id ScheduleID, err error, givenUp bool) {
+			if !givenUp {
+				t.Errorf("Expected %s to be given up on", id)
+			}
+			reported = append(reported, err)
+		},
+	})
+
+	ctx := context.Background()
+	id, err := scheduler.ScheduleMessage(ctx, SendMessageOptions{
+		RoomID:       "general",
+		Text:         "hi",
+		SenderID:     "alice",
+		ExpiresAfter: time.Minute,
+	}, time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := scheduler.sendIfDue(ctx, id); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	if count := atomic.LoadInt32(sent); count != 1 {
+		t.Fatalf("Expected the message to be sent once, got %d", count)
+	}
+	if len(reported) != 1 {
+		t.Fatalf("Expected the expiry failure to be reported once, got %v", reported)
+	}
+	if _, ok := reported[0].(*ExpiryError); !ok {
+		t.Fatalf("Expected an *ExpiryError, got %v", reported[0])
+	}
+}
+
+// newDeleteStub returns a client whose message deletions respond with status, and the number of
+// deletions requested.
+func newDeleteStub(t *testing.T, status int, options ...ClientOption) (*Client, func(), *int32) {
+	var deletes int32
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			atomic.AddInt32(&deletes, 1)
+		}
+		w.WriteHeader(status)
+	}, options...)
+
+	return client, server.Close, &deletes
+}
+
+func TestMessageReaperKeepsMessagesThatFailToDelete(t *testing.T) {
+	store := NewMemoryStore()
+	client, closeServer, deletes := newDeleteStub(t, http.StatusInternalServerError, WithStore(store))
+	defer closeServer()
+
+	ctx := context.Background()
+	if err := client.expireMessage(ctx, "general", 1, time.Nanosecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	var reported []uint
+	reaper := NewMessageReaper(client, MessageReaperOptions{
+		OnError: func(roomID string, messageID uint, err error) {
+			reported = append(reported, messageID)
+		},
+	})
+	if err := reaper.deleteExpired(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count := atomic.LoadInt32(deletes); count != 1 {
+		t.Fatalf("Expected the message to be deleted once, got %d", count)
+	}
+	if len(reported) != 1 || reported[0] != 1 {
+		t.Errorf("Expected the failure to delete message 1 to be reported, got %v", reported)
+	}
+	if keys, _ := store.List(ctx, expiringMessagesStorePrefix); len(keys) != 1 {
+		t.Errorf("Expected the expiry to be kept to retry the deletion, got %v", keys)
+	}
+}
+
+func TestMessageReaperForgetsDeletedMessages(t *testing.T) {
+	store := NewMemoryStore()
+	client, closeServer, _ := newDeleteStub(t, http.StatusNoContent, WithStore(store))
+	defer closeServer()
+
+	ctx := context.Background()
+	if err := client.expireMessage(ctx, "general", 1, time.Nanosecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	time.Sleep(time.Millisecond)
+
+	if err := NewMessageReaper(client, MessageReaperOptions{}).deleteExpired(ctx); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if keys, _ := store.List(ctx, expiringMessagesStorePrefix); len(keys) != 0 {
+		t.Errorf("Expected the expiry to be removed, got %v", keys)
+	}
+}
+
+func TestDeleteMessagesReportsFailedDeletions(t *testing.T) {
+	client, closeServer, _ := newDeleteStub(t, http.StatusForbidden)
+	defer closeServer()
+
+	result := client.Messages().DeleteMessages(context.Background(), "general", []uint{1, 2}, BatchOptions{})
+	if len(result.Succeeded) != 0 || result.Failed["1"] == nil || result.Failed["2"] == nil {
+		t.Errorf("Expected both deletions to be reported as failed, got %+v", result)
+	}
+}
+
+// idempotencyFailingStore is a Store that fails to record idempotency keys.
+type idempotencyFailingStore struct {
+	Store
+}
+
+func (s idempotencyFailingStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	if strings.HasPrefix(key, "idempotency/") {
+		return errors.New("disk full")
+	}
+	return s.Store.Put(ctx, key, value, ttl)
+}
+
+func TestSendReturnsTheMessageIDWhenItsIdempotencyKeyCantBeStored(t *testing.T) {
+	client, closeServer, _ := newSendStub(t, WithStore(idempotencyFailingStore{NewMemoryStore()}))
+	defer closeServer()
+
+	messageID, err := client.Messages().SendSimpleMessage(context.Background(), SendSimpleMessageOptions{
+		RoomID:         "general",
+		Text:           "hi",
+		SenderID:       "alice",
+		IdempotencyKey: "key",
+	})
+
+	keyErr, ok := err.(*IdempotencyKeyError)
+	if !ok {
+		t.Fatalf("Expected an *IdempotencyKeyError, got %v", err)
+	}
+	if messageID != 1 || keyErr.MessageID != 1 || keyErr.RoomID != "general" || keyErr.IdempotencyKey != "key" {
+		t.Fatalf("Expected message 1 in general to be reported, got %d and %+v", messageID, keyErr)
+	}
+	if sendFailed(err) {
+		t.Fatal("Expected the message to be reported as sent")
+	}
+}
+
+func TestSendAndGetReturnsTheMessageWhenItsIdempotencyKeyCantBeStored(t *testing.T) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+			writeTestJSON(w, map[string]interface{}{"message_id": 1})
+			return
+		}
+
+		writeTestJSON(w, map[string]interface{}{
+			"id":      1,
+			"user_id": "alice",
+			"room_id": "general",
+			"parts":   []map[string]interface{}{{"type": "text/plain", "content": "hi"}},
+		})
+	}, WithStore(idempotencyFailingStore{NewMemoryStore()}))
+	defer server.Close()
+
+	message, err := client.Messages().SendSimpleMessageAndGet(context.Background(), SendSimpleMessageOptions{
+		RoomID:         "general",
+		Text:           "hi",
+		SenderID:       "alice",
+		IdempotencyKey: "key",
+	})
+
+	if _, ok := err.(*IdempotencyKeyError); !ok {
+		t.Fatalf("Expected an *IdempotencyKeyError, got %v", err)
+	}
+	if message.ID != 1 {
+		t.Fatalf("Expected message 1 to be returned, got %+v", message)
+	}
+}
+
+func TestGetPinnedMessagesReturnsPartialResults(t *testing.T) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		id := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		switch id {
+		case "general":
+			writeTestJSON(w, map[string]interface{}{
+				"id":          "general",
+				"custom_data": map[string]interface{}{RoomPinnedMessagesCustomDataKey: []uint{3, 1, 2, 4}},
+			})
+		case "1":
+			w.WriteHeader(http.StatusForbidden)
+		case "4":
+			w.WriteHeader(http.StatusNotFound)
+		default:
+			messageID, _ := strconv.Atoi(id)
+			writeTestJSON(w, map[string]interface{}{"id": messageID, "room_id": "general"})
+		}
+	})
+	defer server.Close()
+
+	messages, err := client.Messages().GetPinnedMessages(context.Background(), "general")
+
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Errors) != 1 || batchErr.Errors["1"] == nil {
+		t.Fatalf("Expected a *BatchError for message 1, got %v", err)
+	}
+	if len(messages) != 2 || messages[0].ID != 3 || messages[1].ID != 2 {
+		t.Errorf("Expected messages 3 and 2, got %+v", messages)
+	}
+}
@@ -0,0 +1,114 @@
+package chatkit
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// claimsContextKey is the key the claims of a verified token are stored under in a request's
+// context.
+type claimsContextKey struct{}
+
+// VerifierOptions contains parameters to configure AuthMiddleware.
+type VerifierOptions struct {
+	// Only accept SU tokens, e.g. for internal endpoints called by other backend services.
+	RequireSU bool
+	// Only accept tokens that grant all of these permissions. Scoped tokens, see
+	// GenerateScopedToken, must carry them, while other tokens aren't restricted by permissions.
+	RequirePermissions []string
+	// Accept requests without a token, which reach the next handler with no claims in their
+	// context. Requests with a token that can't be verified are still refused.
+	Optional bool
+	// Optional function writing the response to requests that are refused, with the error
+	// returned by VerifyToken. Defaults to a JSON error body with a 401 for tokens that are
+	// invalid, expired or revoked, and a 500 when the token couldn't be checked, e.g. because the
+	// revocation store is unavailable. The error itself isn't exposed.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// AuthMiddleware returns an http.Handler that verifies the Chatkit token in the Authorization
+// header of requests (`Authorization: Bearer <token>`) with VerifyToken, and passes those with a
+// valid token on to next. The token's claims can be retrieved from the request's context with
+// ClaimsFromContext, and the ID of its user with UserIDFromContext.
+func (a AuthClient) AuthMiddleware(next http.Handler, options VerifierOptions) http.Handler {
+	onError := options.OnError
+	if onError == nil {
+		onError = writeVerificationError
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenString, ok := bearerToken(r)
+		if !ok {
+			if options.Optional {
+				next.ServeHTTP(w, r)
+				return
+			}
+			onError(w, r, ErrInvalidToken)
+			return
+		}
+
+		claims, err := a.VerifyToken(r.Context(), tokenString)
+		if err != nil {
+			onError(w, r, err)
+			return
+		}
+
+		if options.RequireSU && !claims.SU {
+			onError(w, r, ErrInvalidToken)
+			return
+		}
+
+		for _, permission := range options.RequirePermissions {
+			if !claims.Allows(permission) {
+				onError(w, r, ErrInvalidToken)
+				return
+			}
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), claimsContextKey{}, claims)))
+	})
+}
+
+// writeVerificationError refuses a request whose token failed verification with err.
+func writeVerificationError(w http.ResponseWriter, r *http.Request, err error) {
+	var description string
+	switch err {
+	case ErrInvalidToken:
+		description = "The token is invalid"
+	case ErrTokenExpired:
+		description = "The token has expired"
+	case ErrTokenRevoked:
+		description = "The token has been revoked"
+	default:
+		writeTokenError(w, http.StatusInternalServerError, "server_error", "The token could not be verified")
+		return
+	}
+
+	w.Header().Set("WWW-Authenticate", "Bearer")
+	writeTokenError(w, http.StatusUnauthorized, "invalid_token", description)
+}
+
+// ClaimsFromContext returns the claims of the token verified by AuthMiddleware.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	claims, ok := ctx.Value(claimsContextKey{}).(Claims)
+	return claims, ok
+}
+
+// UserIDFromContext returns the ID of the user of the token verified by AuthMiddleware. It
+// returns false if there is no verified token, or it was not issued to a user.
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	claims, ok := ClaimsFromContext(ctx)
+	return claims.UserID, ok && claims.UserID != ""
+}
+
+// bearerToken returns the token in the Authorization header of a request.
+func bearerToken(r *http.Request) (string, bool) {
+	const prefix = "bearer "
+	header := r.Header.Get("Authorization")
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+
+	return strings.TrimSpace(header[len(prefix):]), true
+}
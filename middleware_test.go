@@ -0,0 +1,78 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// failingRevocationStore is a RevocationStore that can't be reached.
+type failingRevocationStore struct{}
+
+func (failingRevocationStore) RevokeToken(context.Context, string, time.Time) error {
+	return errors.New("connection refused to 10.0.0.1:6379")
+}
+
+func (failingRevocationStore) RevokeTokensForUser(context.Context, string, time.Time) error {
+	return errors.New("connection refused to 10.0.0.1:6379")
+}
+
+func (failingRevocationStore) IsRevoked(context.Context, Claims) (bool, error) {
+	return false, errors.New("connection refused to 10.0.0.1:6379")
+}
+
+// serveWithAuthMiddleware sends a request with token through AuthMiddleware and returns the
+// response.
+func serveWithAuthMiddleware(client *Client, token string) *httptest.ResponseRecorder {
+	handler := client.Auth().AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}), VerifierOptions{})
+
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+
+	return recorder
+}
+
+func TestAuthMiddlewareRefusesInvalidTokens(t *testing.T) {
+	client := newTestClient(t)
+
+	recorder := serveWithAuthMiddleware(client, "not-a-token")
+	if recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("Expected a 401, got %d", recorder.Code)
+	}
+	if recorder.Header().Get("WWW-Authenticate") != "Bearer" {
+		t.Fatalf("Expected a WWW-Authenticate header, got %q", recorder.Header().Get("WWW-Authenticate"))
+	}
+
+	var body map[string]string
+	json.NewDecoder(recorder.Body).Decode(&body)
+	if body["error"] != "invalid_token" {
+		t.Fatalf("Expected an invalid_token error, got %v", body)
+	}
+}
+
+func TestAuthMiddlewareFailsWhenTokensCantBeChecked(t *testing.T) {
+	client := newTestClient(t, WithRevocationStore(failingRevocationStore{}))
+
+	userID := "alice"
+	token, err := client.Auth().GenerateAccessToken(AuthenticateOptions{UserID: &userID})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	recorder := serveWithAuthMiddleware(client, token.Token)
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected a 500, got %d", recorder.Code)
+	}
+	if strings.Contains(recorder.Body.String(), "10.0.0.1") {
+		t.Fatalf("Expected the error not to be exposed, got %s", recorder.Body.String())
+	}
+}
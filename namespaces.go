@@ -0,0 +1,66 @@
+package chatkit
+
+// UsersClient makes the calls that manage users. It is obtained with Client.Users.
+type UsersClient struct {
+	client *Client
+}
+
+// Users returns the calls that manage users.
+func (c *Client) Users() UsersAPI {
+	return UsersClient{client: c}
+}
+
+// RoomsClient makes the calls that manage rooms and their members. It is obtained with
+// Client.Rooms.
+type RoomsClient struct {
+	client *Client
+}
+
+// Rooms returns the calls that manage rooms and their members.
+func (c *Client) Rooms() RoomsAPI {
+	return RoomsClient{client: c}
+}
+
+// MessagesClient makes the calls that send, fetch and manage messages, including their pins,
+// reactions and threads. It is obtained with Client.Messages.
+type MessagesClient struct {
+	client *Client
+}
+
+// Messages returns the calls that send, fetch and manage messages.
+func (c *Client) Messages() MessagesAPI {
+	return MessagesClient{client: c}
+}
+
+// CursorsClient makes the calls that manage read and other cursors, and the unread counts
+// derived from them. It is obtained with Client.Cursors.
+type CursorsClient struct {
+	client *Client
+}
+
+// Cursors returns the calls that manage cursors.
+func (c *Client) Cursors() CursorsAPI {
+	return CursorsClient{client: c}
+}
+
+// RolesClient makes the calls that manage roles, their permissions and their assignment to
+// users. It is obtained with Client.Roles.
+type RolesClient struct {
+	client *Client
+}
+
+// Roles returns the calls that manage roles and permissions.
+func (c *Client) Roles() RolesAPI {
+	return RolesClient{client: c}
+}
+
+// AuthClient makes the calls that generate, verify and revoke tokens, and serves them to
+// clients. It is obtained with Client.Auth.
+type AuthClient struct {
+	client *Client
+}
+
+// Auth returns the calls that generate, verify and revoke tokens.
+func (c *Client) Auth() AuthAPI {
+	return AuthClient{client: c}
+}
@@ -0,0 +1,238 @@
+package chatkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/authenticator"
+	"github.com/pusher/chatkit-server-go/internal/common"
+	"github.com/pusher/chatkit-server-go/internal/core"
+)
+
+// defaultUserTokenCacheSize is the number of users whose tokens are cached by default.
+const defaultUserTokenCacheSize = 1000
+
+// ClientOption configures optional behaviour of a Client. Options are passed to NewClient.
+type ClientOption func(*clientOptions)
+
+// clientOptions holds the configuration assembled from the ClientOptions passed to NewClient.
+type clientOptions struct {
+	batchConcurrency   int
+	lookupConcurrency  int
+	coreRolloutVersion string
+	coreRollout        core.RolloutOptions
+	decoder            common.Decoder
+	store              Store
+	responseCache      *ResponseCacheOptions
+
+	refreshTokenLifetime time.Duration
+	tokenOptions         authenticator.Options
+	userTokenCacheSize   int
+	revocationStore      RevocationStore
+
+	partTypeWarningHandler func(PartTypeWarning)
+	debugHTTP              io.Writer
+	tracerProvider         TracerProvider
+	interceptors           []Interceptor
+
+	slowRequestThreshold time.Duration
+	onSlowRequest        func(CallInfo)
+	audit                func(context.Context, AuditRecord)
+	httpClient           *http.Client
+	compression          bool
+	compressionMinSize   int
+}
+
+// WithBatchConcurrency sets the maximum number of requests bulk operations, such as
+// UpdateUsers or adding many users to a room, have in flight at once. Defaults to 10.
+// Options passed to an individual call take precedence.
+func WithBatchConcurrency(concurrency int) ClientOption {
+	return func(o *clientOptions) {
+		o.batchConcurrency = concurrency
+	}
+}
+
+// WithLookupConcurrency sets the maximum number of requests lookups of many resources by ID,
+// GetUsersByID, GetRoomsByID and GetUsersPresence, have in flight at once, so that they can be
+// tuned against rate limits separately from other bulk operations. Defaults to the batch
+// concurrency.
+func WithLookupConcurrency(concurrency int) ClientOption {
+	return func(o *clientOptions) {
+		o.lookupConcurrency = concurrency
+	}
+}
+
+// WithResponseCache caches the responses of GetUser, GetRoom and GetRoles, to avoid repeating
+// lookups of the same resources in hot paths. Cached responses are invalidated by the calls of
+// this client that change them, but changes made elsewhere are only seen once they expire.
+func WithResponseCache(options ResponseCacheOptions) ClientOption {
+	return func(o *clientOptions) {
+		o.responseCache = &options
+	}
+}
+
+// WithCoreRollout routes a percentage of calls to the core service through another version of
+// its API (e.g. "v7"), to de-risk migrating between versions.
+// Reads routed through the new version are also performed against the current one, at the same
+// time, and any difference between their results is reported to options.OnDivergence.
+func WithCoreRollout(version string, options RolloutOptions) ClientOption {
+	return func(o *clientOptions) {
+		o.coreRolloutVersion = version
+		o.coreRollout = options
+	}
+}
+
+// WithStrictDecoding makes calls fail when Chatkit responds with fields that the SDK's types
+// have no place for, rather than silently dropping them. Before Go 1.10, which can't detect such
+// fields, they are dropped as usual.
+func WithStrictDecoding() ClientOption {
+	return func(o *clientOptions) {
+		o.decoder.Strict = true
+	}
+}
+
+// WithUnknownFieldHandler registers a function that is called when Chatkit responds with a
+// field that the SDK's types have no place for, and decoding is not strict.
+// Only the first unknown field of each response is reported. Before Go 1.10, which can't detect
+// such fields, the handler is never called.
+func WithUnknownFieldHandler(handler func(UnknownField)) ClientOption {
+	return func(o *clientOptions) {
+		o.decoder.OnUnknownField = handler
+	}
+}
+
+// WithStore sets the Store that the client's stateful helpers, such as send deduplication and
+// message scheduling, persist their state in. Defaults to an in-memory store, whose state is
+// lost on restart and not shared between processes; see NewFileStore and NewRedisStore.
+// Refresh tokens require a store to be set explicitly.
+func WithStore(store Store) ClientOption {
+	return func(o *clientOptions) {
+		o.store = store
+	}
+}
+
+// WithRefreshTokenLifetime sets how long refresh tokens issued by IssueRefreshToken remain valid
+// for. Defaults to 30 days.
+func WithRefreshTokenLifetime(lifetime time.Duration) ClientOption {
+	return func(o *clientOptions) {
+		o.refreshTokenLifetime = lifetime
+	}
+}
+
+// WithTokenOptions sets the lifetime of the tokens generated by Authenticate, GenerateAccessToken
+// and GenerateSUToken when their options don't set TokenExpiry, and the clock skew allowed by
+// VerifyToken. By default tokens last 24 hours and no clock skew is allowed.
+func WithTokenOptions(options TokenOptions) ClientOption {
+	return func(o *clientOptions) {
+		o.tokenOptions = options
+	}
+}
+
+// WithRevocationStore sets the RevocationStore that RevokeToken and RevokeTokensForUser record
+// revocations in, and that VerifyToken consults. Defaults to one backed by the client's Store.
+func WithRevocationStore(store RevocationStore) ClientOption {
+	return func(o *clientOptions) {
+		o.revocationStore = store
+	}
+}
+
+// WithUserTokenCacheSize sets the number of users whose tokens are reused for requests made on
+// their behalf, e.g. when sending messages, until shortly before they expire. The tokens of the
+// least recently used users are evicted first. Defaults to 1000; a negative size disables the
+// cache.
+func WithUserTokenCacheSize(size int) ClientOption {
+	return func(o *clientOptions) {
+		o.userTokenCacheSize = size
+	}
+}
+
+// WithPartTypeWarningHandler registers a function that is called when a message is sent with a
+// part whose type is valid but cannot be rendered by the Chatkit mobile SDKs.
+func WithPartTypeWarningHandler(handler func(PartTypeWarning)) ClientOption {
+	return func(o *clientOptions) {
+		o.partTypeWarningHandler = handler
+	}
+}
+
+// WithDebugHTTP writes the requests made to Chatkit and their responses to w, for
+// troubleshooting. Authorization headers and query parameters carrying tokens are redacted.
+// Dumping can be started and stopped later with Client.SetDebugHTTP.
+func WithDebugHTTP(w io.Writer) ClientOption {
+	return func(o *clientOptions) {
+		o.debugHTTP = w
+	}
+}
+
+// WithTracerProvider traces every call made to Chatkit with a span, started from the context
+// passed to the call, with a tracer obtained from provider. See TracerProvider for adapting
+// OpenTelemetry.
+func WithTracerProvider(provider TracerProvider) ClientOption {
+	return func(o *clientOptions) {
+		o.tracerProvider = provider
+	}
+}
+
+// WithInterceptor makes every request to Chatkit, whichever service it is made to, go through
+// interceptor, which calls next to perform it, e.g. to add headers, log, inject failures or serve
+// responses from a cache:
+//
+//	chatkit.WithInterceptor(func(
+//		ctx context.Context,
+//		options chatkit.RequestOptions,
+//		next chatkit.Invoker,
+//	) (*http.Response, error) {
+//		if options.Headers == nil {
+//			options.Headers = http.Header{}
+//		}
+//		options.Headers.Set("X-Request-ID", requestID(ctx))
+//		return next(ctx, options)
+//	})
+//
+// Interceptors are called in the order they are passed, within the span of the request when it
+// is traced.
+func WithInterceptor(interceptor Interceptor) ClientOption {
+	return func(o *clientOptions) {
+		o.interceptors = append(o.interceptors, interceptor)
+	}
+}
+
+// WithSlowRequestThreshold calls onSlowRequest, synchronously, with every call made to Chatkit
+// that takes longer than threshold, e.g. to report service degradation. The duration includes
+// the interceptors passed with WithInterceptor.
+func WithSlowRequestThreshold(threshold time.Duration, onSlowRequest func(info CallInfo)) ClientOption {
+	return func(o *clientOptions) {
+		o.slowRequestThreshold = threshold
+		o.onSlowRequest = onSlowRequest
+	}
+}
+
+// WithAuditLog calls audit, synchronously, once each request made to Chatkit with a token with the
+// `su` claim has completed, so that privileged operations can be audited. Calls are attributed to
+// the actor set in their context with WithActor.
+func WithAuditLog(audit func(ctx context.Context, record AuditRecord)) ClientOption {
+	return func(o *clientOptions) {
+		o.audit = audit
+	}
+}
+
+// WithHTTPClient sets the HTTP client that requests to Chatkit, and attachment uploads and
+// downloads, are made with, e.g. to set its transport. Defaults to the platform client, and to
+// http.DefaultClient for attachments.
+func WithHTTPClient(client *http.Client) ClientOption {
+	return func(o *clientOptions) {
+		o.httpClient = client
+	}
+}
+
+// WithCompression gzips the bodies of requests to Chatkit of at least minSize bytes, such as large
+// batches of users, to save bandwidth. minSize defaults to 1KB. Responses are asked for gzipped
+// and decompressed by the HTTP transport whether or not this is set. Stats count the bytes sent
+// compressed.
+func WithCompression(minSize int) ClientOption {
+	return func(o *clientOptions) {
+		o.compression = true
+		o.compressionMinSize = minSize
+	}
+}
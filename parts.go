@@ -0,0 +1,178 @@
+package chatkit
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// JSONPartType is the content type of parts created by NewJSONPart.
+const JSONPartType = "application/json"
+
+// base64EncodingParameter is the content type parameter marking inline parts whose content is
+// base64 encoded binary data.
+const base64EncodingParameter = "encoding"
+
+// NewJSONPart returns an inline part whose content is v encoded as JSON.
+func NewJSONPart(v interface{}) (NewInlinePart, error) {
+	content, err := json.Marshal(v)
+	if err != nil {
+		return NewInlinePart{}, fmt.Errorf("Failed to marshal part content: %v", err)
+	}
+
+	return NewInlinePart{Type: JSONPartType, Content: string(content)}, nil
+}
+
+// DecodeJSONPart decodes the JSON content of an inline part into v.
+func DecodeJSONPart(part Part, v interface{}) error {
+	if part.Content == nil {
+		return fmt.Errorf("Part of type %s is not an inline part", part.Type)
+	}
+
+	if err := json.Unmarshal([]byte(*part.Content), v); err != nil {
+		return fmt.Errorf("Failed to decode part content: %v", err)
+	}
+
+	return nil
+}
+
+// NewBinaryInlinePart returns an inline part carrying binary data, which is base64 encoded.
+// If contentType is empty it is detected from the data. The part's type records the encoding as
+// a parameter, e.g. "image/png; encoding=base64", so that DecodeBinaryInlinePart can reverse it.
+// Inline parts are limited in size; larger data should be sent as an attachment.
+func NewBinaryInlinePart(contentType string, data []byte) NewInlinePart {
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType, params = contentType, map[string]string{}
+	}
+	params[base64EncodingParameter] = "base64"
+
+	return NewInlinePart{
+		Type:    mime.FormatMediaType(mediaType, params),
+		Content: base64.StdEncoding.EncodeToString(data),
+	}
+}
+
+// DecodeBinaryInlinePart returns the data carried by a part created by NewBinaryInlinePart and
+// its content type without the encoding parameter.
+func DecodeBinaryInlinePart(part Part) (string, []byte, error) {
+	if part.Content == nil {
+		return "", nil, fmt.Errorf("Part of type %s is not an inline part", part.Type)
+	}
+
+	mediaType, params, err := mime.ParseMediaType(part.Type)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to parse part type: %v", err)
+	}
+
+	if params[base64EncodingParameter] != "base64" {
+		return "", nil, fmt.Errorf("Part of type %s is not base64 encoded", part.Type)
+	}
+	delete(params, base64EncodingParameter)
+
+	data, err := base64.StdEncoding.DecodeString(*part.Content)
+	if err != nil {
+		return "", nil, fmt.Errorf("Failed to decode part content: %v", err)
+	}
+
+	return mime.FormatMediaType(mediaType, params), data, nil
+}
+
+// PartValidationError is returned when sending or editing a message with an invalid part.
+// Parts are validated before anything is uploaded or sent.
+type PartValidationError struct {
+	Index  int    // Position of the invalid part in the message
+	Type   string // Type of the invalid part
+	Reason string // Why the part is invalid
+}
+
+func (e *PartValidationError) Error() string {
+	return fmt.Sprintf("Invalid message part %d of type %q: %s", e.Index, e.Type, e.Reason)
+}
+
+// PartTypeWarning describes a valid part that the Chatkit mobile SDKs cannot render, reported to
+// the handler registered with WithPartTypeWarningHandler.
+type PartTypeWarning struct {
+	Index int    // Position of the part in the message
+	Type  string // Type of the part
+}
+
+// validateParts checks that the type of every part is a valid MIME type, reporting parts that
+// the mobile SDKs can't render to the client's warning handler.
+func (c *Client) validateParts(parts []NewPart) error {
+	for i, part := range parts {
+		var partType string
+		inline := false
+		switch p := part.(type) {
+		case NewInlinePart:
+			partType, inline = p.Type, true
+		case NewURLPart:
+			partType = p.Type
+		case NewAttachmentPart:
+			partType = p.Type
+		case NewExistingAttachmentPart:
+			partType = p.Type
+		default:
+			continue
+		}
+
+		mediaType, reason := parsePartType(partType)
+		if reason != "" {
+			return &PartValidationError{Index: i, Type: partType, Reason: reason}
+		}
+
+		if c.partTypeWarningHandler != nil && !isRenderablePartType(mediaType, inline) {
+			c.partTypeWarningHandler(PartTypeWarning{Index: i, Type: partType})
+		}
+	}
+
+	return nil
+}
+
+// parsePartType returns the media type of a part type without parameters, or the reason it is
+// not a valid MIME type.
+func parsePartType(partType string) (string, string) {
+	if partType == "" {
+		return "", "type must not be empty"
+	}
+
+	mediaType, _, err := mime.ParseMediaType(partType)
+	if err != nil {
+		return "", err.Error()
+	}
+
+	slash := strings.Index(mediaType, "/")
+	if slash <= 0 || slash == len(mediaType)-1 || strings.Count(mediaType, "/") != 1 {
+		return "", "type must be of the form type/subtype"
+	}
+
+	return mediaType, ""
+}
+
+// isRenderablePartType reports whether the mobile SDKs can display parts of a media type.
+// Inline parts are displayed as text; URL and attachment parts as media. System, thread and
+// redacted parts are treated as renderable, as they are interpreted by the SDK's own conventions.
+func isRenderablePartType(mediaType string, inline bool) bool {
+	if inline {
+		switch mediaType {
+		case "text/plain", SystemPartType, ThreadPartType, RedactedPartType:
+			return true
+		}
+		return false
+	}
+
+	for _, prefix := range []string{"image/", "video/", "audio/"} {
+		if strings.HasPrefix(mediaType, prefix) {
+			return true
+		}
+	}
+
+	return mediaType == "application/pdf"
+}
@@ -0,0 +1,156 @@
+package chatkit
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// RoomPinnedMessagesCustomDataKey is the key in a room's custom data under which the IDs of the
+// room's pinned messages are stored, in the order they were pinned.
+const RoomPinnedMessagesCustomDataKey = "pinned_message_ids"
+
+// maxCustomDataUpdateAttempts is the number of times a read-modify-write of a room's custom data
+// is attempted when the room keeps being updated concurrently.
+const maxCustomDataUpdateAttempts = 5
+
+// PinMessage pins a message in a room. Pinning a message that is already pinned has no effect.
+func (m MessagesClient) PinMessage(ctx context.Context, roomID string, messageID uint) error {
+	return m.client.updatePinnedMessageIDs(ctx, roomID, func(ids []uint) []uint {
+		for _, id := range ids {
+			if id == messageID {
+				return ids
+			}
+		}
+		return append(ids, messageID)
+	})
+}
+
+// UnpinMessage unpins a message in a room, if it is pinned.
+func (m MessagesClient) UnpinMessage(ctx context.Context, roomID string, messageID uint) error {
+	return m.client.updatePinnedMessageIDs(ctx, roomID, func(ids []uint) []uint {
+		remaining := []uint{}
+		for _, id := range ids {
+			if id != messageID {
+				remaining = append(remaining, id)
+			}
+		}
+		return remaining
+	})
+}
+
+// GetPinnedMessages returns the messages pinned in a room, in the order they were pinned.
+// Pinned messages that have since been deleted are left out. If some of the messages can't be
+// fetched a *BatchError is returned, keyed by message ID, along with the other messages.
+func (m MessagesClient) GetPinnedMessages(ctx context.Context, roomID string) ([]MultipartMessage, error) {
+	c := m.client
+	room, err := c.Rooms().GetRoom(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	pinnedIDs := pinnedMessageIDs(room.CustomData)
+	ids := make([]string, len(pinnedIDs))
+	for i, messageID := range pinnedIDs {
+		ids[i] = strconv.FormatUint(uint64(messageID), 10)
+	}
+	ids = uniqueStrings(ids)
+
+	var mu sync.Mutex
+	byID := make(map[string]MultipartMessage, len(ids))
+	err = forEachConcurrently(ctx, ids, c.concurrencyFor(0), func(ctx context.Context, id string) error {
+		messageID, _ := strconv.ParseUint(id, 10, 0)
+		message, err := m.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+			RoomID:    roomID,
+			MessageID: uint(messageID),
+		})
+		if isNotFound(err) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		byID[id] = message
+		mu.Unlock()
+		return nil
+	})
+
+	pinned := []MultipartMessage{}
+	for _, id := range ids {
+		if message, ok := byID[id]; ok {
+			pinned = append(pinned, message)
+		}
+	}
+
+	return pinned, err
+}
+
+// updatePinnedMessageIDs applies update to the IDs of the messages pinned in a room.
+func (c *Client) updatePinnedMessageIDs(ctx context.Context, roomID string, update func([]uint) []uint) error {
+	return c.updateRoomCustomData(ctx, roomID, func(customData map[string]interface{}) {
+		ids := update(pinnedMessageIDs(customData))
+		if len(ids) == 0 {
+			delete(customData, RoomPinnedMessagesCustomDataKey)
+			return
+		}
+		customData[RoomPinnedMessagesCustomDataKey] = ids
+	})
+}
+
+// updateRoomCustomData applies update to a copy of a room's custom data and saves the result,
+// without overwriting changes made to the room concurrently. Updates within this process are
+// serialised, and the update is retried if the room is changed elsewhere in the meantime.
+func (c *Client) updateRoomCustomData(
+	ctx context.Context,
+	roomID string,
+	update func(customData map[string]interface{}),
+) error {
+	defer lockKey("rooms/" + roomID)()
+
+	for attempt := 1; ; attempt++ {
+		room, err := c.Rooms().GetRoom(ctx, roomID)
+		if err != nil {
+			return err
+		}
+
+		customData, err := customDataAsMap(room.CustomData)
+		if err != nil {
+			return err
+		}
+
+		update(customData)
+
+		err = c.Rooms().UpdateRoom(ctx, roomID, UpdateRoomOptions{
+			CustomData:        customData,
+			ExpectedUpdatedAt: &room.UpdatedAt,
+		})
+		if err != ErrConflict || attempt == maxCustomDataUpdateAttempts {
+			return err
+		}
+	}
+}
+
+// pinnedMessageIDs reads the IDs of the pinned messages from a room's custom data.
+func pinnedMessageIDs(customData interface{}) []uint {
+	data, ok := customData.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+
+	values, ok := data[RoomPinnedMessagesCustomDataKey].([]interface{})
+	if !ok {
+		return nil
+	}
+
+	ids := []uint{}
+	for _, value := range values {
+		// Custom data is decoded generically, so numbers are float64s.
+		if id, ok := value.(float64); ok && id > 0 {
+			ids = append(ids, uint(id))
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,220 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// reactionsStorePrefix is the prefix of the Store keys reactions are kept under.
+//
+// Chatkit has no native support for reactions, so they are kept in the client's Store rather than
+// in the messages themselves, with a key for each user's reaction to a message:
+// reactions/{roomID}/{messageID}/{reaction}/{userID}, whose value is when they reacted. Adding
+// and removing a reaction is a single write that can't undo another made concurrently.
+const reactionsStorePrefix = "reactions/"
+
+// reactionTimeFormat is the format reaction times are stored in, fixed width so that they sort
+// lexically.
+const reactionTimeFormat = "2006-01-02T15:04:05.000000000Z"
+
+// roomReactionsKey returns the prefix of the keys of the reactions to the messages of a room.
+func roomReactionsKey(roomID string) string {
+	return reactionsStorePrefix + url.PathEscape(roomID) + "/"
+}
+
+// messageReactionsKey returns the prefix of the keys of the reactions to a message.
+func messageReactionsKey(roomID string, messageID uint) string {
+	return roomReactionsKey(roomID) + strconv.FormatUint(uint64(messageID), 10) + "/"
+}
+
+// reactionKey returns the key of a user's reaction to a message.
+func reactionKey(roomID string, messageID uint, reaction string, userID string) string {
+	return messageReactionsKey(roomID, messageID) + url.PathEscape(reaction) + "/" + url.PathEscape(userID)
+}
+
+// AddReaction records that a user reacted to a message. Reacting twice with the same reaction
+// has no effect. Reactions are kept in the client's Store (see WithStore), which must be shared
+// for them to be seen by other processes, and are included in the messages fetched with
+// FetchMultipartMessage and FetchMultipartMessages when their IncludeReactions option is set.
+func (m MessagesClient) AddReaction(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	userID string,
+	reaction string,
+) error {
+	if err := validateReaction(roomID, userID, reaction); err != nil {
+		return err
+	}
+
+	key := reactionKey(roomID, messageID, reaction, userID)
+	if _, err := m.client.store.Get(ctx, key); err != ErrStoreKeyNotFound {
+		return err
+	}
+
+	reactedAt := time.Now().UTC().Format(reactionTimeFormat)
+	return m.client.store.Put(ctx, key, []byte(reactedAt), 0)
+}
+
+// RemoveReaction removes a user's reaction to a message, if they reacted with it.
+func (m MessagesClient) RemoveReaction(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	userID string,
+	reaction string,
+) error {
+	if err := validateReaction(roomID, userID, reaction); err != nil {
+		return err
+	}
+
+	return m.client.store.Delete(ctx, reactionKey(roomID, messageID, reaction, userID))
+}
+
+func validateReaction(roomID string, userID string, reaction string) error {
+	if roomID == "" {
+		return errors.New("You must provide the ID of the room the message was sent to")
+	}
+
+	if userID == "" {
+		return errors.New("You must provide the ID of the user reacting")
+	}
+
+	if reaction == "" {
+		return errors.New("You must provide the reaction")
+	}
+
+	return nil
+}
+
+// addReactions sets the reactions of messages sent to a room, with one listing of the store.
+func (c *Client) addReactions(ctx context.Context, roomID string, messages []MultipartMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	prefix := roomReactionsKey(roomID)
+	if len(messages) == 1 {
+		prefix = messageReactionsKey(roomID, messages[0].ID)
+	}
+
+	keys, err := c.store.List(ctx, prefix)
+	if err != nil {
+		return fmt.Errorf("Failed to list reactions: %v", err)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	wanted := make(map[uint]bool, len(messages))
+	for _, message := range messages {
+		wanted[message.ID] = true
+	}
+
+	type userReaction struct {
+		userID    string
+		reactedAt string
+	}
+	reactions := map[uint]map[string][]userReaction{}
+
+	for _, key := range keys {
+		messageID, reaction, userID, ok := parseReactionKey(strings.TrimPrefix(key, roomReactionsKey(roomID)))
+		if !ok || !wanted[messageID] {
+			continue
+		}
+
+		value, err := c.store.Get(ctx, key)
+		if err == ErrStoreKeyNotFound {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("Failed to read reaction: %v", err)
+		}
+
+		if reactions[messageID] == nil {
+			reactions[messageID] = map[string][]userReaction{}
+		}
+		reactions[messageID][reaction] = append(reactions[messageID][reaction], userReaction{userID, string(value)})
+	}
+
+	for i, message := range messages {
+		byReaction := reactions[message.ID]
+		if len(byReaction) == 0 {
+			continue
+		}
+
+		summaries := make([]ReactionSummary, 0, len(byReaction))
+		for reaction, users := range byReaction {
+			sort.Slice(users, func(i, j int) bool {
+				if users[i].reactedAt != users[j].reactedAt {
+					return users[i].reactedAt < users[j].reactedAt
+				}
+				return users[i].userID < users[j].userID
+			})
+
+			userIDs := make([]string, len(users))
+			for j, user := range users {
+				userIDs[j] = user.userID
+			}
+			summaries = append(summaries, ReactionSummary{Reaction: reaction, Count: len(userIDs), UserIDs: userIDs})
+		}
+
+		sort.Slice(summaries, func(i, j int) bool {
+			if summaries[i].Count != summaries[j].Count {
+				return summaries[i].Count > summaries[j].Count
+			}
+			return summaries[i].Reaction < summaries[j].Reaction
+		})
+
+		messages[i].Reactions = summaries
+	}
+
+	return nil
+}
+
+// parseReactionKey parses the part of the key of a reaction following its room's prefix.
+func parseReactionKey(key string) (uint, string, string, bool) {
+	segments := strings.Split(key, "/")
+	if len(segments) != 3 {
+		return 0, "", "", false
+	}
+
+	messageID, err := strconv.ParseUint(segments[0], 10, 0)
+	if err != nil {
+		return 0, "", "", false
+	}
+
+	reaction, err := url.PathUnescape(segments[1])
+	if err != nil {
+		return 0, "", "", false
+	}
+
+	userID, err := url.PathUnescape(segments[2])
+	if err != nil {
+		return 0, "", "", false
+	}
+
+	return uint(messageID), reaction, userID, true
+}
+
+// deleteReactions deletes the reactions to a message.
+func (c *Client) deleteReactions(ctx context.Context, roomID string, messageID uint) error {
+	keys, err := c.store.List(ctx, messageReactionsKey(roomID, messageID))
+	if err != nil {
+		return fmt.Errorf("Failed to list reactions: %v", err)
+	}
+
+	for _, key := range keys {
+		if err := c.store.Delete(ctx, key); err != nil {
+			return fmt.Errorf("Failed to delete reaction: %v", err)
+		}
+	}
+
+	return nil
+}
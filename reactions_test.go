@@ -0,0 +1,224 @@
+package chatkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// newReactionsStub returns a client, created with options, whose rooms have messages 1 to 3, and
+// which fails the test if a message is edited.
+func newReactionsStub(t *testing.T, options ...ClientOption) (*Client, func()) {
+	message := func(id int) map[string]interface{} {
+		return map[string]interface{}{
+			"id":      id,
+			"user_id": "alice",
+			"room_id": "general",
+			"parts":   []map[string]interface{}{{"type": "text/plain", "content": "hello"}},
+		}
+	}
+
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("Expected no message to be edited, got %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		if strings.HasSuffix(r.URL.Path, "/messages") {
+			writeTestJSON(w, []map[string]interface{}{message(3), message(2), message(1)})
+			return
+		}
+
+		var id int
+		fmt.Sscanf(r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:], "%d", &id)
+		writeTestJSON(w, message(id))
+	}, options...)
+
+	return client, server.Close
+}
+
+func TestReactionsAreSummarisedInFetchedMessages(t *testing.T) {
+	client, closeServer := newReactionsStub(t)
+	defer closeServer()
+
+	ctx := context.Background()
+	messages := client.Messages()
+	for _, reaction := range []struct {
+		messageID uint
+		userID    string
+		reaction  string
+	}{
+		{1, "alice", "👍"},
+		{1, "bob", "👍"},
+		{1, "bob", "👍"},
+		{1, "bob", "🎉"},
+		{1, "carol", "a/b"},
+		{2, "carol", "👍"},
+	} {
+		if err := messages.AddReaction(ctx, "general", reaction.messageID, reaction.userID, reaction.reaction); err != nil {
+			t.Fatalf("Failed to add reaction: %v", err)
+		}
+	}
+	if err := messages.RemoveReaction(ctx, "general", 1, "bob", "🎉"); err != nil {
+		t.Fatalf("Failed to remove reaction: %v", err)
+	}
+
+	message, err := messages.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+		RoomID:           "general",
+		MessageID:        1,
+		IncludeReactions: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to fetch message: %v", err)
+	}
+
+	expected := []ReactionSummary{
+		{Reaction: "👍", Count: 2, UserIDs: []string{"alice", "bob"}},
+		{Reaction: "a/b", Count: 1, UserIDs: []string{"carol"}},
+	}
+	if !reflect.DeepEqual(message.Reactions, expected) {
+		t.Fatalf("Expected %+v, got %+v", expected, message.Reactions)
+	}
+	if len(message.Parts) != 1 {
+		t.Fatalf("Expected the message's parts to be untouched, got %+v", message.Parts)
+	}
+
+	fetched, err := messages.FetchMultipartMessages(ctx, "general", FetchMultipartMessagesOptions{IncludeReactions: true})
+	if err != nil {
+		t.Fatalf("Failed to fetch messages: %v", err)
+	}
+
+	counts := map[uint]int{}
+	for _, message := range fetched {
+		for _, summary := range message.Reactions {
+			counts[message.ID] += summary.Count
+		}
+	}
+	if expected := map[uint]int{1: 3, 2: 1}; !reflect.DeepEqual(counts, expected) {
+		t.Fatalf("Expected reaction counts %v, got %v", expected, counts)
+	}
+}
+
+func TestConcurrentReactionsAreAllKept(t *testing.T) {
+	client, closeServer := newReactionsStub(t)
+	defer closeServer()
+
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			client.Messages().AddReaction(ctx, "general", 1, fmt.Sprintf("user-%d", i), "👍")
+		}(i)
+	}
+	wg.Wait()
+
+	message, err := client.Messages().FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+		RoomID:           "general",
+		MessageID:        1,
+		IncludeReactions: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to fetch message: %v", err)
+	}
+	if len(message.Reactions) != 1 || message.Reactions[0].Count != 20 {
+		t.Fatalf("Expected 20 reactions, got %+v", message.Reactions)
+	}
+}
+
+func TestDeleteMessageDeletesReactions(t *testing.T) {
+	store := NewMemoryStore()
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}, WithStore(store))
+	defer server.Close()
+
+	ctx := context.Background()
+	client.Messages().AddReaction(ctx, "general", 1, "alice", "👍")
+	client.Messages().AddReaction(ctx, "general", 2, "alice", "👍")
+
+	if err := client.Messages().DeleteMessage(ctx, DeleteMessageOptions{RoomID: "general", MessageID: 1}); err != nil {
+		t.Fatalf("Failed to delete message: %v", err)
+	}
+
+	keys, _ := store.List(ctx, reactionsStorePrefix)
+	if len(keys) != 1 || !strings.HasPrefix(keys[0], messageReactionsKey("general", 2)) {
+		t.Fatalf("Expected only the reactions to the other message to remain, got %v", keys)
+	}
+}
+
+// listCountingStore is a Store that counts the listings of its keys.
+type listCountingStore struct {
+	Store
+	lists int32
+}
+
+func (s *listCountingStore) List(ctx context.Context, prefix string) ([]string, error) {
+	atomic.AddInt32(&s.lists, 1)
+	return s.Store.List(ctx, prefix)
+}
+
+func TestReactionsAreOnlyReadWhenIncluded(t *testing.T) {
+	store := &listCountingStore{Store: NewMemoryStore()}
+	client, closeServer := newReactionsStub(t, WithStore(store))
+	defer closeServer()
+
+	ctx := context.Background()
+	if err := client.Messages().AddReaction(ctx, "general", 1, "alice", "👍"); err != nil {
+		t.Fatalf("Failed to add reaction: %v", err)
+	}
+
+	message, err := client.Messages().FetchMultipartMessage(ctx, FetchMultipartMessageOptions{RoomID: "general", MessageID: 1})
+	if err != nil {
+		t.Fatalf("Failed to fetch message: %v", err)
+	}
+	fetched, err := client.Messages().FetchMultipartMessages(ctx, "general", FetchMultipartMessagesOptions{})
+	if err != nil {
+		t.Fatalf("Failed to fetch messages: %v", err)
+	}
+
+	if message.Reactions != nil || fetched[2].Reactions != nil {
+		t.Errorf("Expected no reactions, got %+v and %+v", message.Reactions, fetched[2].Reactions)
+	}
+	if lists := atomic.LoadInt32(&store.lists); lists != 0 {
+		t.Errorf("Expected the store not to be read, got %d listings", lists)
+	}
+}
+
+func TestRedactMessageDeletesReactions(t *testing.T) {
+	store := NewMemoryStore()
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		writeTestJSON(w, map[string]interface{}{
+			"id":      1,
+			"user_id": "alice",
+			"room_id": "general",
+			"parts":   []map[string]interface{}{{"type": "text/plain", "content": "hello"}},
+		})
+	}, WithStore(store))
+	defer server.Close()
+
+	ctx := context.Background()
+	client.Messages().AddReaction(ctx, "general", 1, "bob", "👍")
+	client.Messages().AddReaction(ctx, "general", 2, "bob", "👍")
+
+	if err := client.Messages().RedactMessage(ctx, "general", 1, ""); err != nil {
+		t.Fatalf("Failed to redact message: %v", err)
+	}
+
+	keys, _ := store.List(ctx, reactionsStorePrefix)
+	if len(keys) != 1 || !strings.HasPrefix(keys[0], messageReactionsKey("general", 2)) {
+		t.Fatalf("Expected only the reactions to the other message to remain, got %v", keys)
+	}
+}
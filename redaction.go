@@ -0,0 +1,81 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// RedactedPartType is the content type of the inline part that marks a message as redacted.
+// Its content is a JSON object recording when the message was redacted, e.g.
+// {"redacted_at":"2019-01-01T00:00:00Z"}.
+const RedactedPartType = "application/x.redacted+json"
+
+// defaultRedactionText is the text a redacted message is replaced with when none is given.
+const defaultRedactionText = "This message has been removed."
+
+type redactedPartContent struct {
+	RedactedAt time.Time `json:"redacted_at"`
+}
+
+// RedactMessage replaces the content of a message with replacementText, or a default
+// placeholder if it is empty, rather than deleting it.
+// The message keeps its ID, sender and position in its thread, so the history around it stays
+// intact, and is marked with a part of type RedactedPartType. Everything else, including its
+// attachments and reactions, is removed.
+func (m MessagesClient) RedactMessage(
+	ctx context.Context,
+	roomID string,
+	messageID uint,
+	replacementText string,
+) error {
+	if replacementText == "" {
+		replacementText = defaultRedactionText
+	}
+
+	defer lockMessage(roomID, messageID)()
+
+	message, err := m.FetchMultipartMessage(ctx, FetchMultipartMessageOptions{
+		RoomID:    roomID,
+		MessageID: messageID,
+	})
+	if err != nil {
+		return err
+	}
+
+	content, err := json.Marshal(redactedPartContent{RedactedAt: time.Now().UTC()})
+	if err != nil {
+		return err
+	}
+
+	parts := []NewPart{
+		NewInlinePart{Type: "text/plain", Content: replacementText},
+		NewInlinePart{Type: RedactedPartType, Content: string(content)},
+	}
+	for _, part := range message.Parts {
+		if part.Type == ThreadPartType {
+			parts = append(parts, part.AsNewPart())
+		}
+	}
+
+	err = m.client.coreServiceV6.EditMultipartMessage(ctx, roomID, messageID, EditMultipartMessageOptions{
+		SenderID: message.UserID,
+		Parts:    parts,
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.client.deleteReactions(ctx, roomID, messageID)
+}
+
+// IsRedacted reports whether a message has been redacted with RedactMessage.
+func IsRedacted(message MultipartMessage) bool {
+	for _, part := range message.Parts {
+		if part.Type == RedactedPartType {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,173 @@
+package chatkit
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pusher/pusher-platform-go/auth"
+)
+
+// GrantTypeRefreshToken is the grant type of token requests that redeem a refresh token.
+const GrantTypeRefreshToken = "refresh_token"
+
+// refreshTokensStorePrefix is the prefix of the Store keys refresh tokens are kept under. Keys
+// continue with the SHA-256 hash of the token, so that the tokens can't be read from the store.
+const refreshTokensStorePrefix = "refresh-tokens/"
+
+const defaultRefreshTokenLifetime = 30 * 24 * time.Hour
+
+// ErrRefreshTokensNeedStore is returned when issuing or redeeming refresh tokens with a client
+// that has no Store configured with WithStore. Refresh tokens outlive access tokens, so they must
+// be kept in a store that survives restarts and is shared by every process redeeming them.
+var ErrRefreshTokensNeedStore = errors.New("You must configure a persistent Store with WithStore to use refresh tokens")
+
+// ErrInvalidRefreshToken is returned when redeeming a refresh token that was never issued, has
+// expired, or has been revoked or already redeemed.
+var ErrInvalidRefreshToken = errors.New("Invalid refresh token")
+
+// TokenResponse is the response to a token request that may include a refresh token.
+type TokenResponse struct {
+	AccessToken  string  `json:"access_token"`
+	TokenType    string  `json:"token_type"`
+	ExpiresIn    float64 `json:"expires_in"`
+	RefreshToken string  `json:"refresh_token,omitempty"`
+}
+
+// storedRefreshToken is what is kept in the store for a refresh token.
+type storedRefreshToken struct {
+	UserID string `json:"user_id"`
+	// When the token was issued, so that it can be revoked by RevokeTokensForUser.
+	IssuedAt int64 `json:"issued_at"`
+}
+
+// IssueRefreshToken issues a refresh token for a user, which RefreshAccessToken exchanges for an
+// access token without the user having to be authorized again.
+// Refresh tokens are kept in the client's Store until they expire (see
+// WithRefreshTokenLifetime), are redeemed, or are revoked with RevokeRefreshToken. The Store must
+// be configured explicitly with WithStore, otherwise ErrRefreshTokensNeedStore is returned.
+func (a AuthClient) IssueRefreshToken(ctx context.Context, userID string) (string, error) {
+	c := a.client
+	if userID == "" {
+		return "", errors.New("You must provide the ID of the user to issue a refresh token to")
+	}
+
+	if !c.storeConfigured {
+		return "", ErrRefreshTokensNeedStore
+	}
+
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		return "", fmt.Errorf("Failed to generate refresh token: %v", err)
+	}
+	refreshToken := base64.RawURLEncoding.EncodeToString(tokenBytes)
+
+	stored, err := json.Marshal(storedRefreshToken{UserID: userID, IssuedAt: time.Now().Unix()})
+	if err != nil {
+		return "", err
+	}
+
+	err = c.store.Put(ctx, refreshTokenKey(refreshToken), stored, c.refreshTokenLifetime)
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+// RefreshAccessToken exchanges a refresh token for an access token for the user it was issued
+// to, generated with options, and a new refresh token. Each refresh token can only be redeemed
+// once. It returns ErrInvalidRefreshToken if the refresh token can't be redeemed.
+func (a AuthClient) RefreshAccessToken(
+	ctx context.Context,
+	refreshToken string,
+	options auth.Options,
+) (TokenResponse, error) {
+	return a.client.refreshAccessToken(ctx, refreshToken, func(userID string) auth.Options {
+		options.UserID = &userID
+		return options
+	})
+}
+
+// refreshAccessToken implements RefreshAccessToken, generating the access token with the options
+// returned by optionsFor for the user the refresh token was issued to.
+func (c *Client) refreshAccessToken(
+	ctx context.Context,
+	refreshToken string,
+	optionsFor func(userID string) auth.Options,
+) (TokenResponse, error) {
+	userID, err := c.redeemRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	token, err := c.Auth().GenerateAccessToken(optionsFor(userID))
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	newRefreshToken, err := c.Auth().IssueRefreshToken(ctx, userID)
+	if err != nil {
+		return TokenResponse{}, err
+	}
+
+	return TokenResponse{
+		AccessToken:  token.Token,
+		TokenType:    "bearer",
+		ExpiresIn:    token.ExpiresIn,
+		RefreshToken: newRefreshToken,
+	}, nil
+}
+
+// RevokeRefreshToken revokes a refresh token, e.g. when its user logs out. Revoking a token that
+// has expired or was already revoked is not an error.
+func (a AuthClient) RevokeRefreshToken(ctx context.Context, refreshToken string) error {
+	return a.client.store.Delete(ctx, refreshTokenKey(refreshToken))
+}
+
+// redeemRefreshToken removes a refresh token from the store, and returns the ID of the user it
+// was issued to. Tokens issued before the tokens of their user were revoked can't be redeemed.
+// The token is taken from the store atomically, so that it can't be redeemed twice even by
+// different processes.
+func (c *Client) redeemRefreshToken(ctx context.Context, refreshToken string) (string, error) {
+	if !c.storeConfigured {
+		return "", ErrRefreshTokensNeedStore
+	}
+
+	value, err := c.store.Take(ctx, refreshTokenKey(refreshToken))
+	if err == ErrStoreKeyNotFound {
+		return "", ErrInvalidRefreshToken
+	}
+	if err != nil {
+		return "", err
+	}
+
+	var stored storedRefreshToken
+	if err := json.Unmarshal(value, &stored); err != nil {
+		return "", fmt.Errorf("Failed to read stored refresh token: %v", err)
+	}
+
+	revoked, err := c.revocationStore.IsRevoked(ctx, Claims{
+		UserID:   stored.UserID,
+		IssuedAt: time.Unix(stored.IssuedAt, 0),
+	})
+	if err != nil {
+		return "", err
+	}
+	if revoked {
+		return "", ErrInvalidRefreshToken
+	}
+
+	return stored.UserID, nil
+}
+
+func refreshTokenKey(refreshToken string) string {
+	hash := sha256.Sum256([]byte(refreshToken))
+	return refreshTokensStorePrefix + hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,68 @@
+package chatkit
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestRefreshTokensNeedStore(t *testing.T) {
+	client := newTestClient(t)
+
+	if _, err := client.Auth().IssueRefreshToken(context.Background(), "alice"); err != ErrRefreshTokensNeedStore {
+		t.Errorf("Expected ErrRefreshTokensNeedStore, got %v", err)
+	}
+
+	_, err := client.Auth().RefreshAccessToken(context.Background(), "token", AuthenticateOptions{})
+	if err != ErrRefreshTokensNeedStore {
+		t.Errorf("Expected ErrRefreshTokensNeedStore, got %v", err)
+	}
+}
+
+func TestRefreshTokenRedeemedOnce(t *testing.T) {
+	ctx := context.Background()
+	// Clients sharing a store stand in for processes redeeming the same token concurrently.
+	store := NewMemoryStore()
+	clients := []*Client{
+		newTestClient(t, WithStore(store)),
+		newTestClient(t, WithStore(store)),
+	}
+
+	refreshToken, err := clients[0].Auth().IssueRefreshToken(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Failed to issue refresh token: %v", err)
+	}
+
+	var (
+		wg         sync.WaitGroup
+		mu         sync.Mutex
+		redeemed   int
+		rejected   int
+		attempts   = 20
+		unexpected []error
+	)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(client *Client) {
+			defer wg.Done()
+
+			_, err := client.Auth().RefreshAccessToken(ctx, refreshToken, AuthenticateOptions{})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch err {
+			case nil:
+				redeemed++
+			case ErrInvalidRefreshToken:
+				rejected++
+			default:
+				unexpected = append(unexpected, err)
+			}
+		}(clients[i%len(clients)])
+	}
+	wg.Wait()
+
+	if redeemed != 1 || rejected != attempts-1 || len(unexpected) > 0 {
+		t.Errorf("Expected the token to be redeemed once, got %d redeemed, %d rejected, errors %v", redeemed, rejected, unexpected)
+	}
+}
@@ -0,0 +1,145 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+)
+
+// ErrTokenRevoked is returned when verifying a token that has been revoked.
+var ErrTokenRevoked = errors.New("Token has been revoked")
+
+const (
+	// revokedTokensStorePrefix is the prefix of the Store keys recording revoked tokens, which
+	// continue with the token's ID.
+	revokedTokensStorePrefix = "revoked-tokens/"
+	// revokedUsersStorePrefix is the prefix of the Store keys recording when the tokens of a user
+	// were revoked, which continue with the user's ID.
+	revokedUsersStorePrefix = "revoked-users/"
+)
+
+// RevocationStore records revoked tokens, and is consulted by VerifyToken.
+// Implementations must be safe for concurrent use.
+type RevocationStore interface {
+	// RevokeToken revokes the token with the given ID, which is no longer accepted after expiry,
+	// including the clock skew VerifyToken allows, and needn't be remembered after.
+	RevokeToken(ctx context.Context, tokenID string, expiry time.Time) error
+	// RevokeTokensForUser revokes the tokens of a user issued at or before the given time.
+	RevokeTokensForUser(ctx context.Context, userID string, issuedBefore time.Time) error
+	// IsRevoked reports whether a token with the given claims has been revoked.
+	IsRevoked(ctx context.Context, claims Claims) (bool, error)
+}
+
+type storeRevocationStore struct {
+	store Store
+}
+
+// NewRevocationStore returns a RevocationStore that keeps revocations in store. It is the
+// default, using the client's Store (see WithStore).
+func NewRevocationStore(store Store) RevocationStore {
+	return &storeRevocationStore{store: store}
+}
+
+func (s *storeRevocationStore) RevokeToken(ctx context.Context, tokenID string, expiry time.Time) error {
+	ttl := time.Until(expiry)
+	if ttl <= 0 {
+		return nil
+	}
+
+	return s.store.Put(ctx, revokedTokensStorePrefix+tokenID, []byte{}, ttl)
+}
+
+func (s *storeRevocationStore) RevokeTokensForUser(ctx context.Context, userID string, issuedBefore time.Time) error {
+	key := revokedUsersStorePrefix + userID
+	defer lockKey(key)()
+
+	// Keep the latest revocation, which covers the earlier ones.
+	revokedAt, err := s.revokedAt(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if revokedAt.After(issuedBefore) {
+		return nil
+	}
+
+	return s.store.Put(ctx, key, []byte(strconv.FormatInt(issuedBefore.Unix(), 10)), 0)
+}
+
+func (s *storeRevocationStore) IsRevoked(ctx context.Context, claims Claims) (bool, error) {
+	if claims.ID != "" {
+		_, err := s.store.Get(ctx, revokedTokensStorePrefix+claims.ID)
+		if err == nil {
+			return true, nil
+		}
+		if err != ErrStoreKeyNotFound {
+			return false, err
+		}
+	}
+
+	if claims.UserID == "" {
+		return false, nil
+	}
+
+	revokedAt, err := s.revokedAt(ctx, claims.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	return !revokedAt.IsZero() && !claims.IssuedAt.After(revokedAt), nil
+}
+
+// revokedAt returns when the tokens of a user were last revoked, or the zero time if they never
+// have been.
+func (s *storeRevocationStore) revokedAt(ctx context.Context, userID string) (time.Time, error) {
+	value, err := s.store.Get(ctx, revokedUsersStorePrefix+userID)
+	if err == ErrStoreKeyNotFound {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	seconds, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return time.Unix(seconds, 0), nil
+}
+
+// RevokeToken revokes a token generated for this instance, so that VerifyToken rejects it with
+// ErrTokenRevoked before it expires. Only tokens with an ID, which those generated by this SDK
+// have, can be revoked individually.
+// Chatkit itself doesn't consult the RevocationStore, so the token remains usable against the
+// Chatkit API until it expires.
+func (a AuthClient) RevokeToken(ctx context.Context, tokenString string) error {
+	c := a.client
+	claims, err := c.authenticatorService.VerifyToken(tokenString)
+	if err == ErrTokenExpired {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if claims.ID == "" {
+		return errors.New("The token has no ID, so can't be revoked individually")
+	}
+
+	// VerifyToken accepts tokens for the clock skew after they expire, so they must stay revoked
+	// for as long.
+	return c.revocationStore.RevokeToken(ctx, claims.ID, claims.Expiry.Add(c.clockSkew))
+}
+
+// RevokeTokensForUser revokes every token issued to a user so far, e.g. when their session has
+// been compromised, so that VerifyToken rejects them with ErrTokenRevoked and their refresh
+// tokens can no longer be redeemed. Tokens issued from the next second on, when `iat` is later
+// than the revocation, are not affected.
+func (a AuthClient) RevokeTokensForUser(ctx context.Context, userID string) error {
+	if userID == "" {
+		return errors.New("You must provide the ID of the user whose tokens you want to revoke")
+	}
+
+	return a.client.revocationStore.RevokeTokensForUser(ctx, userID, time.Now())
+}
@@ -0,0 +1,73 @@
+package chatkit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRevokeTokenCoversClockSkew(t *testing.T) {
+	ctx := context.Background()
+	store := newRecordingStore()
+	client := newTestClient(t, WithStore(store), WithTokenOptions(TokenOptions{ClockSkew: time.Hour}))
+
+	expiry := time.Minute
+	userID := "alice"
+	token, err := client.Auth().GenerateAccessToken(AuthenticateOptions{UserID: &userID, TokenExpiry: &expiry})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	claims, err := client.Auth().VerifyToken(ctx, token.Token)
+	if err != nil {
+		t.Fatalf("Failed to verify token: %v", err)
+	}
+
+	if err := client.Auth().RevokeToken(ctx, token.Token); err != nil {
+		t.Fatalf("Failed to revoke token: %v", err)
+	}
+
+	// The token is accepted for the clock skew after it expires, so must stay revoked as long.
+	ttl := store.ttl(revokedTokensStorePrefix + claims.ID)
+	if ttl < time.Hour+expiry-time.Second {
+		t.Errorf("Expected the revocation to be kept for the expiry and clock skew, got %v", ttl)
+	}
+
+	if _, err := client.Auth().VerifyToken(ctx, token.Token); err != ErrTokenRevoked {
+		t.Errorf("Expected ErrTokenRevoked, got %v", err)
+	}
+}
+
+func TestRevokeTokensForUserRevokesRefreshTokens(t *testing.T) {
+	ctx := context.Background()
+	client := newTestClient(t, WithStore(NewMemoryStore()))
+
+	refreshToken, err := client.Auth().IssueRefreshToken(ctx, "alice")
+	if err != nil {
+		t.Fatalf("Failed to issue refresh token: %v", err)
+	}
+
+	otherRefreshToken, err := client.Auth().IssueRefreshToken(ctx, "bob")
+	if err != nil {
+		t.Fatalf("Failed to issue refresh token: %v", err)
+	}
+
+	if err := client.Auth().RevokeTokensForUser(ctx, "alice"); err != nil {
+		t.Fatalf("Failed to revoke tokens: %v", err)
+	}
+
+	_, err = client.Auth().RefreshAccessToken(ctx, refreshToken, AuthenticateOptions{})
+	if err != ErrInvalidRefreshToken {
+		t.Errorf("Expected ErrInvalidRefreshToken for a revoked user's refresh token, got %v", err)
+	}
+
+	response, err := client.Auth().RefreshAccessToken(ctx, otherRefreshToken, AuthenticateOptions{})
+	if err != nil {
+		t.Fatalf("Expected another user's refresh token to be redeemed, got %v", err)
+	}
+
+	claims, err := client.Auth().VerifyToken(ctx, response.AccessToken)
+	if err != nil || claims.UserID != "bob" {
+		t.Errorf("Expected a valid token for bob, got %+v, %v", claims, err)
+	}
+}
@@ -0,0 +1,442 @@
+package chatkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// DefaultRoles are the roles created by CreateDefaultRoles:
+//
+//	"admin", global: every permission.
+//	"moderator", room: manage the room and its members, as well as take part in it.
+//	"member", global: see users and rooms, create rooms, and join and take part in public rooms.
+//	"member", room: take part in the room.
+var DefaultRoles = []Role{
+	{
+		Name:        "admin",
+		Scope:       RoleScopeGlobal,
+		Permissions: Permissions,
+	},
+	{
+		Name:  "moderator",
+		Scope: RoleScopeRoom,
+		Permissions: []string{
+			PermissionRoomUpdate,
+			PermissionRoomDelete,
+			PermissionRoomGet,
+			PermissionRoomLeave,
+			PermissionRoomMembersAdd,
+			PermissionRoomMembersRemove,
+			PermissionRoomMessagesGet,
+			PermissionRoomTypingIndicatorSend,
+			PermissionMessageCreate,
+			PermissionFileCreate,
+			PermissionFileGet,
+			PermissionCursorsReadGet,
+			PermissionCursorsReadSet,
+		},
+	},
+	{
+		Name:  "member",
+		Scope: RoleScopeGlobal,
+		Permissions: []string{
+			PermissionRoomCreate,
+			PermissionRoomGet,
+			PermissionRoomJoin,
+			PermissionRoomLeave,
+			PermissionRoomMessagesGet,
+			PermissionRoomTypingIndicatorSend,
+			PermissionMessageCreate,
+			PermissionPresenceSubscribe,
+			PermissionUserGet,
+			PermissionUserRoomsGet,
+			PermissionFileCreate,
+			PermissionFileGet,
+			PermissionCursorsReadGet,
+			PermissionCursorsReadSet,
+		},
+	},
+	{
+		Name:  "member",
+		Scope: RoleScopeRoom,
+		Permissions: []string{
+			PermissionRoomGet,
+			PermissionRoomLeave,
+			PermissionRoomMessagesGet,
+			PermissionRoomTypingIndicatorSend,
+			PermissionMessageCreate,
+			PermissionFileCreate,
+			PermissionFileGet,
+			PermissionCursorsReadGet,
+			PermissionCursorsReadSet,
+		},
+	},
+}
+
+// CreateDefaultRoles creates the DefaultRoles, to bootstrap a new instance in one call.
+// Roles that already exist with the same name and scope are left as they are.
+func (rc RolesClient) CreateDefaultRoles(ctx context.Context) error {
+	roles, err := rc.GetRoles(ctx)
+	if err != nil {
+		return err
+	}
+
+	existing := make(map[string]bool, len(roles))
+	for _, role := range roles {
+		existing[role.Scope+"/"+role.Name] = true
+	}
+
+	for _, role := range DefaultRoles {
+		if existing[role.Scope+"/"+role.Name] {
+			continue
+		}
+
+		options := CreateRoleOptions{Name: role.Name, Permissions: role.Permissions}
+		if role.Scope == RoleScopeGlobal {
+			err = rc.CreateGlobalRole(ctx, options)
+		} else {
+			err = rc.CreateRoomRole(ctx, options)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// GetRole returns the role with the given name and scope, either RoleScopeGlobal or
+// RoleScopeRoom, or ErrRoleNotFound if there is none.
+func (rc RolesClient) GetRole(ctx context.Context, name string, scope string) (Role, error) {
+	var (
+		permissions []string
+		err         error
+	)
+	switch scope {
+	case RoleScopeGlobal:
+		permissions, err = rc.GetPermissionsForGlobalRole(ctx, name)
+	case RoleScopeRoom:
+		permissions, err = rc.GetPermissionsForRoomRole(ctx, name)
+	default:
+		return Role{}, fmt.Errorf("Unknown role scope: %q", scope)
+	}
+	if isNotFound(err) {
+		return Role{}, ErrRoleNotFound
+	}
+	if err != nil {
+		return Role{}, err
+	}
+
+	return Role{Name: name, Permissions: permissions, Scope: scope}, nil
+}
+
+// UpsertGlobalRole creates a global role, or if it already exists updates its permissions to match
+// the ones given, so that it can be called repeatedly, e.g. from deployment scripts.
+func (rc RolesClient) UpsertGlobalRole(ctx context.Context, options CreateRoleOptions) error {
+	return rc.client.upsertRole(ctx, options, RoleScopeGlobal)
+}
+
+// UpsertRoomRole creates a room role, or if it already exists updates its permissions to match the
+// ones given, so that it can be called repeatedly, e.g. from deployment scripts.
+func (rc RolesClient) UpsertRoomRole(ctx context.Context, options CreateRoleOptions) error {
+	return rc.client.upsertRole(ctx, options, RoleScopeRoom)
+}
+
+// upsertRole is used by UpsertGlobalRole and UpsertRoomRole.
+func (c *Client) upsertRole(ctx context.Context, options CreateRoleOptions, scope string) error {
+	role, err := c.Roles().GetRole(ctx, options.Name, scope)
+	if err == ErrRoleNotFound {
+		if scope == RoleScopeGlobal {
+			return c.Roles().CreateGlobalRole(ctx, options)
+		}
+		return c.Roles().CreateRoomRole(ctx, options)
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.updateRolePermissions(ctx, role, options.Permissions)
+}
+
+// updateRolePermissions adds and removes permissions from role so that it has the desired ones.
+// No request is made if it has them already.
+func (c *Client) updateRolePermissions(ctx context.Context, role Role, desired []string) error {
+	add, remove := diffPermissions(role.Permissions, desired)
+	if len(add) == 0 && len(remove) == 0 {
+		return nil
+	}
+
+	options := UpdateRolePermissionsOptions{PermissionsToAdd: add, PermissionsToRemove: remove}
+	if role.Scope == RoleScopeGlobal {
+		return c.Roles().UpdatePermissionsForGlobalRole(ctx, role.Name, options)
+	}
+	return c.Roles().UpdatePermissionsForRoomRole(ctx, role.Name, options)
+}
+
+// diffPermissions returns the permissions that must be added to and removed from current to get
+// desired, each sorted.
+func diffPermissions(current []string, desired []string) ([]string, []string) {
+	currentSet := make(map[string]bool, len(current))
+	for _, permission := range current {
+		currentSet[permission] = true
+	}
+
+	desiredSet := make(map[string]bool, len(desired))
+	for _, permission := range desired {
+		desiredSet[permission] = true
+	}
+
+	add := []string{}
+	for permission := range desiredSet {
+		if !currentSet[permission] {
+			add = append(add, permission)
+		}
+	}
+
+	remove := []string{}
+	for permission := range currentSet {
+		if !desiredSet[permission] {
+			remove = append(remove, permission)
+		}
+	}
+
+	sort.Strings(add)
+	sort.Strings(remove)
+
+	return add, remove
+}
+
+// GetEffectivePermissions returns the permissions a user has in a room, sorted: those of their
+// global role combined with those of their role in the room, as Chatkit does when authorizing
+// their requests. With an empty roomID only the permissions of their global role are returned.
+func (rc RolesClient) GetEffectivePermissions(ctx context.Context, userID string, roomID string) ([]string, error) {
+	roles, err := rc.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	granted := map[string]bool{}
+	for _, role := range roles {
+		if role.Scope == RoleScopeRoom && (roomID == "" || role.RoomID != roomID) {
+			continue
+		}
+
+		for _, permission := range role.Permissions {
+			granted[permission] = true
+		}
+	}
+
+	permissions := make([]string, 0, len(granted))
+	for permission := range granted {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+
+	return permissions, nil
+}
+
+// AssignGlobalRoleToUsers assigns a global role to many users.
+// Chatkit has no batch assignment endpoint, so the role is assigned to each user concurrently.
+// The result reports the outcome for each user.
+func (rc RolesClient) AssignGlobalRoleToUsers(
+	ctx context.Context,
+	userIDs []string,
+	roleName string,
+	options BatchOptions,
+) *BatchResult {
+	return rc.client.newBatchExecutor(options).Execute(ctx, userIDs, func(ctx context.Context, userID string) error {
+		return rc.AssignGlobalRoleToUser(ctx, userID, roleName)
+	})
+}
+
+// AssignRoomRoleToUsers assigns a room role to many users in a room.
+// Chatkit has no batch assignment endpoint, so the role is assigned to each user concurrently.
+// The result reports the outcome for each user.
+func (rc RolesClient) AssignRoomRoleToUsers(
+	ctx context.Context,
+	userIDs []string,
+	roomID string,
+	roleName string,
+	options BatchOptions,
+) *BatchResult {
+	return rc.client.newBatchExecutor(options).Execute(ctx, userIDs, func(ctx context.Context, userID string) error {
+		return rc.AssignRoomRoleToUser(ctx, userID, roomID, roleName)
+	})
+}
+
+// RoleAssignment records that a user holds a role.
+type RoleAssignment struct {
+	UserID   string // User holding the role
+	RoleName string // Name of the role
+	Scope    string // Scope of the role, either RoleScopeGlobal or RoleScopeRoom
+	RoomID   string // Room the role is held in, for room scoped roles
+}
+
+// GetUsersWithRoleOptions contains parameters to pass when listing the users holding a role.
+type GetUsersWithRoleOptions struct {
+	RoomID      string // Only list room scoped roles held in this room
+	Concurrency int    // Maximum number of requests in flight at once. Defaults to the client's batch concurrency
+}
+
+// GetUsersWithRole lists the users holding a role, e.g. for access reviews. A user holding a room
+// scoped role in several rooms is listed once per room.
+// Chatkit can't look up users by role, so the roles of every user of the instance are fetched,
+// concurrently, which for large instances takes a while.
+func (rc RolesClient) GetUsersWithRole(
+	ctx context.Context,
+	roleName string,
+	scope string,
+	options GetUsersWithRoleOptions,
+) ([]RoleAssignment, error) {
+	assignments := []RoleAssignment{}
+	it := rc.ListRoleAssignments(ctx, ListRoleAssignmentsOptions{
+		RoleName:    roleName,
+		Scope:       scope,
+		RoomID:      options.RoomID,
+		Concurrency: options.Concurrency,
+	})
+	for it.Next() {
+		assignments = append(assignments, it.Assignment())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return assignments, nil
+}
+
+// ListRoleAssignmentsOptions contains parameters to pass when listing role assignments. Empty
+// fields don't filter.
+type ListRoleAssignmentsOptions struct {
+	RoleName    string // Only list assignments of roles with this name
+	Scope       string // Only list assignments of roles with this scope
+	RoomID      string // Only list room scoped roles held in this room
+	Concurrency int    // Maximum number of requests in flight at once. Defaults to the client's batch concurrency
+}
+
+// RoleAssignmentsIterator pages through the role assignments of an instance, in the order the
+// users were created.
+//
+//	it := client.ListRoleAssignments(ctx, ListRoleAssignmentsOptions{Scope: RoleScopeGlobal})
+//	for it.Next() {
+//		assignment := it.Assignment()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type RoleAssignmentsIterator struct {
+	ctx         context.Context
+	client      *Client
+	options     ListRoleAssignmentsOptions
+	concurrency int
+
+	users   *UsersIterator
+	page    []RoleAssignment
+	current RoleAssignment
+	done    bool
+	err     error
+}
+
+// ListRoleAssignments returns an iterator over every role held by every user of the instance,
+// e.g. for compliance audits of who holds admin rights.
+// Chatkit can't list role assignments directly, so the roles of each user are fetched, a page of
+// users at a time with up to Concurrency requests in flight. Only one page of assignments is held
+// in memory at once.
+func (rc RolesClient) ListRoleAssignments(
+	ctx context.Context,
+	options ListRoleAssignmentsOptions,
+) *RoleAssignmentsIterator {
+	c := rc.client
+	return &RoleAssignmentsIterator{
+		ctx:         ctx,
+		client:      c,
+		options:     options,
+		concurrency: c.concurrencyFor(options.Concurrency),
+		users:       c.Users().IterateUsers(ctx, IterateUsersOptions{}),
+	}
+}
+
+// Next advances the iterator to the next role assignment.
+// It returns false when there are no more assignments or an error occurred.
+func (it *RoleAssignmentsIterator) Next() bool {
+	for len(it.page) == 0 && !it.done && it.err == nil {
+		it.fetchPage()
+	}
+
+	if len(it.page) == 0 {
+		return false
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+
+	return true
+}
+
+// Assignment returns the role assignment the iterator currently points at.
+func (it *RoleAssignmentsIterator) Assignment() RoleAssignment {
+	return it.current
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *RoleAssignmentsIterator) Err() error {
+	return it.err
+}
+
+// fetchPage fetches the roles of the next page of users. Pages may contain no assignments
+// matching the filters.
+func (it *RoleAssignmentsIterator) fetchPage() {
+	userIDs := make([]string, 0, defaultUsersPageSize)
+	for len(userIDs) < defaultUsersPageSize && it.users.Next() {
+		userIDs = append(userIDs, it.users.User().ID)
+	}
+	if err := it.users.Err(); err != nil {
+		it.err = err
+		return
+	}
+	if len(userIDs) < defaultUsersPageSize {
+		it.done = true
+	}
+
+	var (
+		mu    sync.Mutex
+		roles = make(map[string][]Role, len(userIDs))
+	)
+
+	err := forEachConcurrently(it.ctx, userIDs, it.concurrency, func(ctx context.Context, userID string) error {
+		userRoles, err := it.client.Roles().GetUserRoles(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		roles[userID] = userRoles
+		mu.Unlock()
+		return nil
+	})
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	for _, userID := range userIDs {
+		for _, role := range roles[userID] {
+			if it.matches(role) {
+				it.page = append(it.page, RoleAssignment{
+					UserID:   userID,
+					RoleName: role.Name,
+					Scope:    role.Scope,
+					RoomID:   role.RoomID,
+				})
+			}
+		}
+	}
+}
+
+func (it *RoleAssignmentsIterator) matches(role Role) bool {
+	return (it.options.RoleName == "" || role.Name == it.options.RoleName) &&
+		(it.options.Scope == "" || role.Scope == it.options.Scope) &&
+		(it.options.RoomID == "" || role.RoomID == it.options.RoomID)
+}
@@ -0,0 +1,324 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/pusher/chatkit-server-go/internal/authorizer"
+)
+
+// RolesConfig describes every role an instance should have.
+// It can be decoded from JSON with LoadRolesConfig, or from YAML with LoadRolesConfigYAML using
+// the same field names, e.g.
+//
+//	roles:
+//	  - name: admin
+//	    scope: global
+//	    permissions: [room:create, room:delete]
+type RolesConfig struct {
+	Roles []Role `json:"roles" yaml:"roles"`
+}
+
+// LoadRolesConfig decodes a RolesConfig from JSON.
+func LoadRolesConfig(r io.Reader) (RolesConfig, error) {
+	var config RolesConfig
+	if err := json.NewDecoder(r).Decode(&config); err != nil {
+		return RolesConfig{}, fmt.Errorf("Failed to decode roles config: %v", err)
+	}
+
+	return config, nil
+}
+
+// LoadRolesConfigYAML decodes a RolesConfig from YAML with unmarshal, the Unmarshal function of a
+// YAML package, so that the SDK doesn't depend on one. For example, with gopkg.in/yaml.v2:
+//
+//	config, err := chatkit.LoadRolesConfigYAML(file, yaml.Unmarshal)
+func LoadRolesConfigYAML(r io.Reader, unmarshal func(in []byte, out interface{}) error) (RolesConfig, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return RolesConfig{}, fmt.Errorf("Failed to read roles config: %v", err)
+	}
+
+	var config RolesConfig
+	if err := unmarshal(data, &config); err != nil {
+		return RolesConfig{}, fmt.Errorf("Failed to decode roles config: %v", err)
+	}
+
+	return config, nil
+}
+
+// Actions a RoleChange can make.
+const (
+	RoleChangeCreate = "create"
+	RoleChangeUpdate = "update"
+	RoleChangeDelete = "delete"
+)
+
+// RoleChange is a change made, or planned, by ApplyRolesConfig.
+type RoleChange struct {
+	Action              string   // One of RoleChangeCreate, RoleChangeUpdate or RoleChangeDelete
+	Role                Role     // The role as configured, or as it exists for deletions
+	PermissionsToAdd    []string // Permissions added by an update
+	PermissionsToRemove []string // Permissions removed by an update
+}
+
+// ApplyRolesConfigOptions contains parameters to pass when applying a RolesConfig.
+type ApplyRolesConfigOptions struct {
+	DryRun bool // Only plan the changes, without making them
+	// Whether roles that are not in the config are deleted. Defaults to leaving them alone, as
+	// the instance may have roles managed by other means, such as its default roles.
+	DeleteUnconfigured bool
+	// Roles whose name starts with one of these prefixes are never deleted, even with
+	// DeleteUnconfigured.
+	ProtectedPrefixes []string
+}
+
+// ApplyRolesConfig converges the roles of the instance on config: roles that are missing are
+// created, roles whose permissions differ are updated, and with DeleteUnconfigured roles that are
+// not in config are deleted, except for those with a protected prefix. It returns the changes
+// made, or with DryRun the changes that would be made, creations first and deletions last.
+// If a change fails the error is returned along with the changes made before it.
+func (rc RolesClient) ApplyRolesConfig(
+	ctx context.Context,
+	config RolesConfig,
+	options ApplyRolesConfigOptions,
+) ([]RoleChange, error) {
+	c := rc.client
+	plan, err := c.planRoleChanges(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []RoleChange{}
+	for _, change := range plan {
+		if change.Action != RoleChangeDelete || options.deletes(change.Role) {
+			changes = append(changes, change)
+		}
+	}
+
+	if options.DryRun {
+		return changes, nil
+	}
+
+	for i, change := range changes {
+		if err := c.applyRoleChange(ctx, change); err != nil {
+			return changes[:i], fmt.Errorf("Failed to %s %s role %s: %v", change.Action, change.Role.Scope, change.Role.Name, err)
+		}
+	}
+
+	return changes, nil
+}
+
+// deletes reports whether a role that is not in the config is deleted.
+func (options ApplyRolesConfigOptions) deletes(role Role) bool {
+	if !options.DeleteUnconfigured {
+		return false
+	}
+
+	for _, prefix := range options.ProtectedPrefixes {
+		if strings.HasPrefix(role.Name, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// planRoleChanges returns the changes needed to converge the instance's roles on config.
+func (c *Client) planRoleChanges(ctx context.Context, config RolesConfig) ([]RoleChange, error) {
+	desired := make(map[string]Role, len(config.Roles))
+	for _, role := range config.Roles {
+		if role.Name == "" {
+			return nil, errors.New("You must provide a name for every role")
+		}
+
+		if role.Scope != RoleScopeGlobal && role.Scope != RoleScopeRoom {
+			return nil, fmt.Errorf("Role %s has unknown scope: %q", role.Name, role.Scope)
+		}
+
+		if err := authorizer.ValidatePermissions(role.Permissions); err != nil {
+			return nil, fmt.Errorf("Role %s: %v", role.Name, err)
+		}
+
+		key := role.Scope + "/" + role.Name
+		if _, ok := desired[key]; ok {
+			return nil, fmt.Errorf("%s role %s is configured more than once", role.Scope, role.Name)
+		}
+		desired[key] = role
+	}
+
+	roles, err := c.Roles().GetRoles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]Role, len(roles))
+	for _, role := range roles {
+		existing[role.Scope+"/"+role.Name] = role
+	}
+
+	var creates, updates, deletes []RoleChange
+	for key, role := range desired {
+		current, ok := existing[key]
+		if !ok {
+			creates = append(creates, RoleChange{Action: RoleChangeCreate, Role: role})
+			continue
+		}
+
+		add, remove := diffPermissions(current.Permissions, role.Permissions)
+		if len(add) > 0 || len(remove) > 0 {
+			updates = append(updates, RoleChange{
+				Action:              RoleChangeUpdate,
+				Role:                role,
+				PermissionsToAdd:    add,
+				PermissionsToRemove: remove,
+			})
+		}
+	}
+
+	for key, role := range existing {
+		if _, ok := desired[key]; !ok {
+			deletes = append(deletes, RoleChange{Action: RoleChangeDelete, Role: role})
+		}
+	}
+
+	changes := []RoleChange{}
+	for _, group := range [][]RoleChange{creates, updates, deletes} {
+		sort.Slice(group, func(i, j int) bool {
+			if group[i].Role.Scope != group[j].Role.Scope {
+				return group[i].Role.Scope < group[j].Role.Scope
+			}
+			return group[i].Role.Name < group[j].Role.Name
+		})
+		changes = append(changes, group...)
+	}
+
+	return changes, nil
+}
+
+func (c *Client) applyRoleChange(ctx context.Context, change RoleChange) error {
+	role := change.Role
+	global := role.Scope == RoleScopeGlobal
+
+	switch change.Action {
+	case RoleChangeCreate:
+		options := CreateRoleOptions{Name: role.Name, Permissions: role.Permissions}
+		if global {
+			return c.Roles().CreateGlobalRole(ctx, options)
+		}
+		return c.Roles().CreateRoomRole(ctx, options)
+
+	case RoleChangeUpdate:
+		options := UpdateRolePermissionsOptions{
+			PermissionsToAdd:    change.PermissionsToAdd,
+			PermissionsToRemove: change.PermissionsToRemove,
+		}
+		if global {
+			return c.Roles().UpdatePermissionsForGlobalRole(ctx, role.Name, options)
+		}
+		return c.Roles().UpdatePermissionsForRoomRole(ctx, role.Name, options)
+
+	case RoleChangeDelete:
+		if global {
+			return c.Roles().DeleteGlobalRole(ctx, role.Name)
+		}
+		return c.Roles().DeleteRoomRole(ctx, role.Name)
+
+	default:
+		return fmt.Errorf("Unknown action %q", change.Action)
+	}
+}
+
+// ExportRoles returns the roles of the instance as a RolesConfig, sorted by scope and name, e.g. to
+// copy them to another instance with ImportRoles or ApplyRolesConfig.
+func (rc RolesClient) ExportRoles(ctx context.Context) (RolesConfig, error) {
+	roles, err := rc.GetRoles(ctx)
+	if err != nil {
+		return RolesConfig{}, err
+	}
+
+	for i := range roles {
+		sort.Strings(roles[i].Permissions)
+	}
+
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].Scope != roles[j].Scope {
+			return roles[i].Scope < roles[j].Scope
+		}
+		return roles[i].Name < roles[j].Name
+	})
+
+	return RolesConfig{Roles: roles}, nil
+}
+
+// Strategies for importing a role that already exists.
+const (
+	// ImportConflictSkip leaves the existing role as it is.
+	ImportConflictSkip = "skip"
+	// ImportConflictOverwrite replaces the permissions of the existing role with the imported ones.
+	ImportConflictOverwrite = "overwrite"
+	// ImportConflictMerge adds the imported permissions the existing role lacks.
+	ImportConflictMerge = "merge"
+)
+
+// ImportRolesOptions contains parameters to pass when importing roles.
+type ImportRolesOptions struct {
+	// How to handle roles that already exist. Defaults to ImportConflictSkip.
+	Conflict string
+}
+
+// ImportRoles creates the roles in config that don't exist yet, and handles those that do
+// according to the conflict strategy. Unlike ApplyRolesConfig, roles that are not in config are
+// left alone. It returns the changes made; if a change fails the error is returned along with the
+// changes made before it.
+func (rc RolesClient) ImportRoles(
+	ctx context.Context,
+	config RolesConfig,
+	options ImportRolesOptions,
+) ([]RoleChange, error) {
+	c := rc.client
+	conflict := options.Conflict
+	switch conflict {
+	case "":
+		conflict = ImportConflictSkip
+	case ImportConflictSkip, ImportConflictOverwrite, ImportConflictMerge:
+	default:
+		return nil, fmt.Errorf("Unknown conflict strategy: %q", conflict)
+	}
+
+	plan, err := c.planRoleChanges(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []RoleChange{}
+	for _, change := range plan {
+		switch change.Action {
+		case RoleChangeDelete:
+			continue
+		case RoleChangeUpdate:
+			if conflict == ImportConflictSkip {
+				continue
+			}
+			if conflict == ImportConflictMerge {
+				if len(change.PermissionsToAdd) == 0 {
+					continue
+				}
+				change.PermissionsToRemove = []string{}
+			}
+		}
+
+		if err := c.applyRoleChange(ctx, change); err != nil {
+			return changes, fmt.Errorf("Failed to %s %s role %s: %v", change.Action, change.Role.Scope, change.Role.Name, err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
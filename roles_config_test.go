@@ -0,0 +1,109 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// newRolesStub returns a client whose instance has the given roles, and the paths of the roles
+// deleted through it.
+func newRolesStub(t *testing.T, roles []Role) (*Client, func(), func() []string) {
+	var (
+		mu      sync.Mutex
+		deleted []string
+	)
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/roles"):
+			writeTestJSON(w, roles)
+		case r.Method == http.MethodDelete:
+			mu.Lock()
+			deleted = append(deleted, r.URL.Path[strings.Index(r.URL.Path, "/roles/"):])
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+
+	return client, server.Close, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return deleted
+	}
+}
+
+func TestApplyRolesConfigLeavesUnconfiguredRolesByDefault(t *testing.T) {
+	client, closeServer, deleted := newRolesStub(t, []Role{
+		{Name: "admin", Scope: RoleScopeGlobal, Permissions: []string{PermissionRoomCreate}},
+		{Name: "default", Scope: RoleScopeGlobal, Permissions: []string{PermissionRoomGet}},
+	})
+	defer closeServer()
+
+	config := RolesConfig{Roles: []Role{
+		{Name: "admin", Scope: RoleScopeGlobal, Permissions: []string{PermissionRoomCreate}},
+	}}
+
+	changes, err := client.Roles().ApplyRolesConfig(context.Background(), config, ApplyRolesConfigOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(changes) != 0 || len(deleted()) != 0 {
+		t.Fatalf("Expected no changes, got %+v and deletions %v", changes, deleted())
+	}
+}
+
+func TestApplyRolesConfigDeletesUnprotectedRoles(t *testing.T) {
+	client, closeServer, deleted := newRolesStub(t, []Role{
+		{Name: "admin", Scope: RoleScopeGlobal, Permissions: []string{PermissionRoomCreate}},
+		{Name: "legacy", Scope: RoleScopeGlobal, Permissions: []string{PermissionRoomGet}},
+		{Name: "managed-moderator", Scope: RoleScopeRoom, Permissions: []string{PermissionRoomGet}},
+	})
+	defer closeServer()
+
+	config := RolesConfig{Roles: []Role{
+		{Name: "admin", Scope: RoleScopeGlobal, Permissions: []string{PermissionRoomCreate}},
+	}}
+
+	changes, err := client.Roles().ApplyRolesConfig(context.Background(), config, ApplyRolesConfigOptions{
+		DeleteUnconfigured: true,
+		ProtectedPrefixes:  []string{"managed-"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(changes) != 1 || changes[0].Action != RoleChangeDelete || changes[0].Role.Name != "legacy" {
+		t.Fatalf("Expected only legacy to be deleted, got %+v", changes)
+	}
+	if paths := deleted(); len(paths) != 1 || paths[0] != "/roles/legacy/scope/global" {
+		t.Fatalf("Expected legacy to be deleted, got %v", paths)
+	}
+}
+
+func TestLoadRolesConfigYAML(t *testing.T) {
+	var unmarshalled string
+	// Stands in for a YAML package's Unmarshal, decoding the JSON subset of YAML.
+	unmarshal := func(in []byte, out interface{}) error {
+		unmarshalled = string(in)
+		return json.Unmarshal(in, out)
+	}
+
+	input := `{"roles": [{"name": "admin", "scope": "global", "permissions": ["room:create"]}]}`
+	config, err := LoadRolesConfigYAML(strings.NewReader(input), unmarshal)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if unmarshalled != input {
+		t.Fatalf("Expected the whole config to be unmarshalled, got %q", unmarshalled)
+	}
+	if len(config.Roles) != 1 || config.Roles[0].Name != "admin" || config.Roles[0].Permissions[0] != PermissionRoomCreate {
+		t.Fatalf("Expected the admin role, got %+v", config)
+	}
+}
@@ -0,0 +1,455 @@
+package chatkit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// RoomOwnerCustomDataKey is the key in a room's custom data under which the ID of the
+// current owner of the room is stored.
+// When it is not set, the user that created the room is considered its owner.
+const RoomOwnerCustomDataKey = "owner_id"
+
+// TransferRoomOwnershipOptions contains parameters to pass when transferring the ownership of a room.
+type TransferRoomOwnershipOptions struct {
+	// Room scoped role assigned to the new owner. Defaults to "admin".
+	OwnerRoleName string
+	// Optional room scoped role assigned to the previous owner.
+	// If not provided, the previous owner's room role is removed.
+	PreviousOwnerRoleName *string
+}
+
+// TransferRoomOwnership makes newOwnerID the owner of a room.
+// The new owner must already be a member of the room. The owner recorded in the room's custom
+// data is updated, the new owner is assigned the owner role for the room and the previous owner is
+// demoted. If the room is updated by someone else during the transfer ErrConflict is returned,
+// before any of them are changed. If the new owner can't be assigned the owner role, the owner
+// recorded in the room is restored.
+func (r RoomsClient) TransferRoomOwnership(
+	ctx context.Context,
+	roomID string,
+	newOwnerID string,
+	options TransferRoomOwnershipOptions,
+) error {
+	c := r.client
+	if roomID == "" {
+		return errors.New("You must provide the ID of the room to transfer")
+	}
+
+	if newOwnerID == "" {
+		return errors.New("You must provide the ID of the user to transfer the room to")
+	}
+
+	ownerRoleName := options.OwnerRoleName
+	if ownerRoleName == "" {
+		ownerRoleName = "admin"
+	}
+
+	room, err := r.GetRoom(ctx, roomID)
+	if err != nil {
+		return err
+	}
+
+	if !containsString(room.MemberUserIDs, newOwnerID) {
+		return fmt.Errorf("User %s is not a member of room %s", newOwnerID, roomID)
+	}
+
+	customData, err := customDataAsMap(room.CustomData)
+	if err != nil {
+		return err
+	}
+
+	previousOwnerID := room.CreatedByID
+	if ownerID, ok := customData[RoomOwnerCustomDataKey].(string); ok && ownerID != "" {
+		previousOwnerID = ownerID
+	}
+
+	if previousOwnerID == newOwnerID {
+		return nil
+	}
+
+	// The owner is recorded first, since that is where a concurrent update is detected.
+	previousCustomData, _ := customDataAsMap(room.CustomData)
+	customData[RoomOwnerCustomDataKey] = newOwnerID
+	updated, err := r.UpdateRoomAndGet(ctx, roomID, UpdateRoomOptions{
+		CustomData:        customData,
+		ExpectedUpdatedAt: &room.UpdatedAt,
+	})
+	if err != nil {
+		return err
+	}
+
+	err = c.Roles().AssignRoomRoleToUser(ctx, newOwnerID, roomID, ownerRoleName)
+	if err != nil {
+		rollbackErr := r.UpdateRoom(ctx, roomID, UpdateRoomOptions{
+			CustomData:        previousCustomData,
+			ExpectedUpdatedAt: &updated.UpdatedAt,
+		})
+		if rollbackErr != nil {
+			return fmt.Errorf(
+				"Failed to assign the owner role (%v), and to restore the previous owner of the room: %v",
+				err,
+				rollbackErr,
+			)
+		}
+		return err
+	}
+
+	if previousOwnerID != "" {
+		if options.PreviousOwnerRoleName != nil {
+			err = c.Roles().AssignRoomRoleToUser(ctx, previousOwnerID, roomID, *options.PreviousOwnerRoleName)
+		} else {
+			err = c.Roles().RemoveRoomRoleForUser(ctx, previousOwnerID, roomID)
+		}
+		if err != nil {
+			return fmt.Errorf("Room ownership was transferred, but the previous owner's role could not be changed: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// maxUsersPerMembershipRequest is the maximum number of users that can be added to or removed
+// from a room in a single request.
+const maxUsersPerMembershipRequest = 10
+
+// inMembershipChunks calls fn concurrently for chunks of userIDs small enough to be added to or
+// removed from a room in a single request.
+func (c *Client) inMembershipChunks(
+	ctx context.Context,
+	userIDs []string,
+	fn func(ctx context.Context, chunk []string) error,
+) error {
+	if len(userIDs) <= maxUsersPerMembershipRequest {
+		return fn(ctx, userIDs)
+	}
+
+	return forEachChunkConcurrently(ctx, userIDs, maxUsersPerMembershipRequest, c.concurrencyFor(0), fn)
+}
+
+// customDataAsMap returns a copy of a room's custom data that can be safely modified.
+// Custom data that is not a JSON object cannot be merged into and results in an error.
+func customDataAsMap(customData interface{}) (map[string]interface{}, error) {
+	merged := map[string]interface{}{}
+	if customData == nil {
+		return merged, nil
+	}
+
+	existing, ok := customData.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("Room custom data must be a JSON object")
+	}
+
+	for key, value := range existing {
+		merged[key] = value
+	}
+
+	return merged, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IterateRoomsOptions contains parameters to pass when iterating over rooms.
+type IterateRoomsOptions struct {
+	FromID         *string // Only return rooms after the room with this ID
+	IncludePrivate bool    // Include private rooms
+}
+
+// RoomsIterator pages through the rooms of an instance.
+// Pages are only requested once the previous one has been consumed.
+//
+//	it := client.IterateRooms(ctx, IterateRoomsOptions{IncludePrivate: true})
+//	for it.Next() {
+//		room := it.Room()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type RoomsIterator struct {
+	ctx            context.Context
+	client         *Client
+	includePrivate bool
+
+	lastID  *string
+	page    []RoomWithoutMembers
+	current RoomWithoutMembers
+	done    bool
+	err     error
+}
+
+// IterateRooms returns an iterator over all rooms of the instance.
+func (r RoomsClient) IterateRooms(ctx context.Context, options IterateRoomsOptions) *RoomsIterator {
+	return &RoomsIterator{
+		ctx:            ctx,
+		client:         r.client,
+		includePrivate: options.IncludePrivate,
+		lastID:         options.FromID,
+	}
+}
+
+// Next advances the iterator to the next room.
+// It returns false when there are no more rooms or an error occurred.
+func (it *RoomsIterator) Next() bool {
+	if len(it.page) == 0 && !it.done && it.err == nil {
+		it.fetchPage()
+	}
+
+	if len(it.page) == 0 {
+		return false
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+
+	return true
+}
+
+// Room returns the room the iterator currently points at.
+func (it *RoomsIterator) Room() RoomWithoutMembers {
+	return it.current
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *RoomsIterator) Err() error {
+	return it.err
+}
+
+// fetchPage requests the page of rooms following the last room returned.
+func (it *RoomsIterator) fetchPage() {
+	rooms, err := it.client.Rooms().GetRooms(it.ctx, GetRoomsOptions{
+		FromID:         it.lastID,
+		IncludePrivate: it.includePrivate,
+	})
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	if len(rooms) > 0 && it.lastID != nil && rooms[0].ID == *it.lastID {
+		rooms = rooms[1:]
+	}
+
+	if len(rooms) == 0 {
+		it.done = true
+		return
+	}
+
+	lastID := rooms[len(rooms)-1].ID
+	it.lastID = &lastID
+	it.page = rooms
+}
+
+// ErrNotDirectRoom is returned by CreateDirectRoom when a room with the ID of the direct room
+// between two users exists, but isn't a private room of just those two users.
+var ErrNotDirectRoom = errors.New("The room is not the direct room between the two users")
+
+// DirectRoomID returns the ID of the direct message room between two users.
+// The ID is the same regardless of the order the users are given in. It is a hash of the two user
+// IDs separated by a NUL byte, so no two pairs of users share an ID, whatever their IDs contain.
+func DirectRoomID(userA string, userB string) string {
+	if userB < userA {
+		userA, userB = userB, userA
+	}
+
+	hash := sha256.Sum256([]byte(userA + "\x00" + userB))
+	return "dm-" + hex.EncodeToString(hash[:16])
+}
+
+// CreateDirectRoomOptions contains parameters to pass when creating a direct message room.
+type CreateDirectRoomOptions struct {
+	Name                          string // Defaults to the IDs of the two users
+	PushNotificationTitleOverride *string
+	CustomData                    interface{}
+}
+
+// CreateDirectRoom returns the private room containing just userA and userB, creating it if it
+// doesn't exist yet. The room's ID is derived from the two user IDs (see DirectRoomID), so
+// calling this repeatedly for the same pair of users always returns the same room. If a room with
+// that ID exists but isn't a private room of just the two users, ErrNotDirectRoom is returned.
+func (r RoomsClient) CreateDirectRoom(
+	ctx context.Context,
+	userA string,
+	userB string,
+	options CreateDirectRoomOptions,
+) (Room, error) {
+	if userA == "" || userB == "" {
+		return Room{}, errors.New("You must provide the IDs of both users in the direct room")
+	}
+
+	if userA == userB {
+		return Room{}, errors.New("A direct room must be between two different users")
+	}
+
+	roomID := DirectRoomID(userA, userB)
+
+	room, err := r.GetRoom(ctx, roomID)
+	if err == nil {
+		return checkDirectRoom(room, userA, userB)
+	}
+	if !isNotFound(err) {
+		return Room{}, err
+	}
+
+	name := options.Name
+	if name == "" {
+		name = fmt.Sprintf("%s, %s", userA, userB)
+	}
+
+	room, err = r.CreateRoom(ctx, CreateRoomOptions{
+		ID:                            &roomID,
+		Name:                          name,
+		PushNotificationTitleOverride: options.PushNotificationTitleOverride,
+		Private:                       true,
+		UserIDs:                       []string{userA, userB},
+		CustomData:                    options.CustomData,
+		CreatorID:                     userA,
+	})
+	if err != nil {
+		// The room may have been created concurrently since we last checked.
+		if existingRoom, getErr := r.GetRoom(ctx, roomID); getErr == nil {
+			return checkDirectRoom(existingRoom, userA, userB)
+		}
+		return Room{}, err
+	}
+
+	return room, nil
+}
+
+// checkDirectRoom returns room if it is a private room of just userA and userB, and
+// ErrNotDirectRoom otherwise.
+func checkDirectRoom(room Room, userA string, userB string) (Room, error) {
+	if !room.Private || len(room.MemberUserIDs) != 2 ||
+		!containsString(room.MemberUserIDs, userA) || !containsString(room.MemberUserIDs, userB) {
+		return Room{}, ErrNotDirectRoom
+	}
+
+	return room, nil
+}
+
+// GetRoomsByID fetches many rooms concurrently, with up to the client's lookup concurrency in
+// flight at once, and returns them in the order their IDs were given.
+// If some rooms could not be fetched the error is a *BatchError, keyed by room ID, and the
+// corresponding entries in the returned slice are left empty.
+func (r RoomsClient) GetRoomsByID(ctx context.Context, roomIDs []string) ([]Room, error) {
+	var (
+		mu    sync.Mutex
+		rooms = make(map[string]Room, len(roomIDs))
+	)
+
+	err := forEachConcurrently(ctx, uniqueStrings(roomIDs), r.client.concurrencyForLookups(), func(ctx context.Context, roomID string) error {
+		room, err := r.GetRoom(ctx, roomID)
+		if err != nil {
+			return err
+		}
+
+		mu.Lock()
+		rooms[roomID] = room
+		mu.Unlock()
+		return nil
+	})
+
+	ordered := make([]Room, len(roomIDs))
+	for i, roomID := range roomIDs {
+		ordered[i] = rooms[roomID]
+	}
+
+	return ordered, err
+}
+
+// uniqueStrings returns values without duplicates, keeping the first occurrence of each.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
+
+// MaxCountedRoomMessages is the most messages GetRoomCounts counts in the history of a room.
+const MaxCountedRoomMessages = 10000
+
+// RoomCounts contains totals describing a room.
+type RoomCounts struct {
+	Members  int  // Number of users that are members of the room
+	Messages uint // Number of messages in the room's history, at most MaxCountedRoomMessages
+	// Whether the room has more than MaxCountedRoomMessages messages, which weren't counted.
+	MoreMessages bool
+}
+
+// GetRoomCounts returns the number of members of a room and the number of messages in it.
+// Chatkit does not report message totals, so they are counted by paging through the room's
+// history, which takes one request per 100 messages. Counting stops after
+// MaxCountedRoomMessages, so that rooms with long histories take at most 101 requests, and
+// MoreMessages is set if there are more.
+func (r RoomsClient) GetRoomCounts(ctx context.Context, roomID string) (RoomCounts, error) {
+	room, err := r.GetRoom(ctx, roomID)
+	if err != nil {
+		return RoomCounts{}, err
+	}
+
+	messages, err := r.client.coreServiceV6.CountRoomMessages(ctx, roomID, MaxCountedRoomMessages)
+	if err != nil {
+		return RoomCounts{}, err
+	}
+
+	counts := RoomCounts{Members: len(room.MemberUserIDs), Messages: messages}
+	if messages > MaxCountedRoomMessages {
+		counts.Messages, counts.MoreMessages = MaxCountedRoomMessages, true
+	}
+
+	return counts, nil
+}
+
+// defaultDeletePollInterval is how often WaitForDelete checks the status of a deletion job when
+// no interval is given.
+const defaultDeletePollInterval = time.Second
+
+// WaitForDelete polls the status of an asynchronous deletion job, such as one started by
+// AsyncDeleteRoom, every interval until it has completed. An error is returned if the job
+// failed or ctx is done first.
+func (r RoomsClient) WaitForDelete(ctx context.Context, jobID string, interval time.Duration) (DeleteStatus, error) {
+	if interval <= 0 {
+		interval = defaultDeletePollInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		status, err := r.GetDeleteStatus(ctx, jobID)
+		if err != nil {
+			return DeleteStatus{}, err
+		}
+
+		switch status.Status {
+		case DeleteStatusCompleted:
+			return status, nil
+		case DeleteStatusFailed:
+			return status, fmt.Errorf("Deletion job %s failed", jobID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return status, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,242 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// transferStub is a stub of the endpoints TransferRoomOwnership calls, recording the changes made.
+type transferStub struct {
+	mu          sync.Mutex
+	room        map[string]interface{}
+	staleReads  int  // Number of reads after the first that see the room updated by someone else
+	failRoles   bool // Whether assigning roles fails
+	roleChanges []string
+	roomUpdates []map[string]interface{}
+	reads       int
+}
+
+func newTransferStub() *transferStub {
+	return &transferStub{room: map[string]interface{}{
+		"id":              "room-1",
+		"name":            "Room",
+		"created_by_id":   "alice",
+		"member_user_ids": []string{"alice", "bob"},
+		"updated_at":      "2020-01-01T00:00:00Z",
+	}}
+}
+
+func (s *transferStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/rooms/room-1") && r.Method == http.MethodGet:
+		s.reads++
+		if s.reads > 1 && s.staleReads > 0 {
+			s.staleReads--
+			s.room["updated_at"] = "2020-01-01T00:00:05Z"
+		}
+		writeTestJSON(w, s.room)
+	case strings.HasSuffix(r.URL.Path, "/rooms/room-1") && r.Method == http.MethodPut:
+		var update map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&update)
+		s.roomUpdates = append(s.roomUpdates, update)
+		s.room["custom_data"] = update["custom_data"]
+		s.room["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+		writeTestJSON(w, s.room)
+	case strings.HasSuffix(r.URL.Path, "/roles"):
+		if s.failRoles {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		s.roleChanges = append(s.roleChanges, r.Method+" "+r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestTransferRoomOwnership(t *testing.T) {
+	stub := newTransferStub()
+	client, server := newStubServer(t, stub.ServeHTTP)
+	defer server.Close()
+
+	err := client.Rooms().TransferRoomOwnership(context.Background(), "room-1", "bob", TransferRoomOwnershipOptions{})
+	if err != nil {
+		t.Fatalf("Failed to transfer room: %v", err)
+	}
+
+	customData, _ := stub.room["custom_data"].(map[string]interface{})
+	if customData[RoomOwnerCustomDataKey] != "bob" {
+		t.Errorf("Expected bob to be recorded as the owner, got %v", stub.room["custom_data"])
+	}
+	if len(stub.roleChanges) != 2 {
+		t.Errorf("Expected the roles of both owners to change, got %v", stub.roleChanges)
+	}
+}
+
+func TestTransferRoomOwnershipConflict(t *testing.T) {
+	stub := newTransferStub()
+	stub.staleReads = 1
+	client, server := newStubServer(t, stub.ServeHTTP)
+	defer server.Close()
+
+	err := client.Rooms().TransferRoomOwnership(context.Background(), "room-1", "bob", TransferRoomOwnershipOptions{})
+	if err != ErrConflict {
+		t.Fatalf("Expected ErrConflict, got %v", err)
+	}
+
+	if len(stub.roleChanges) != 0 || len(stub.roomUpdates) != 0 {
+		t.Errorf("Expected nothing to change, got roles %v and updates %v", stub.roleChanges, stub.roomUpdates)
+	}
+}
+
+func TestTransferRoomOwnershipRollsBack(t *testing.T) {
+	stub := newTransferStub()
+	stub.failRoles = true
+	client, server := newStubServer(t, stub.ServeHTTP)
+	defer server.Close()
+
+	err := client.Rooms().TransferRoomOwnership(context.Background(), "room-1", "bob", TransferRoomOwnershipOptions{})
+	if err == nil {
+		t.Fatal("Expected the transfer to fail")
+	}
+
+	if len(stub.roomUpdates) != 2 {
+		t.Fatalf("Expected the owner to be recorded and then restored, got %v", stub.roomUpdates)
+	}
+
+	customData, _ := stub.room["custom_data"].(map[string]interface{})
+	if _, ok := customData[RoomOwnerCustomDataKey]; ok {
+		t.Errorf("Expected the recorded owner to be restored, got %v", stub.room["custom_data"])
+	}
+}
+
+func TestDirectRoomID(t *testing.T) {
+	if DirectRoomID("alice", "bob") != DirectRoomID("bob", "alice") {
+		t.Error("Expected the ID not to depend on the order of the users")
+	}
+
+	if DirectRoomID("a-b", "c") == DirectRoomID("a", "b-c") {
+		t.Error("Expected different pairs of users to have different IDs")
+	}
+}
+
+// directRoomStub serves an existing room with the ID of the direct room between alice and bob.
+func directRoomStub(private bool, memberIDs ...string) http.HandlerFunc {
+	roomID := DirectRoomID("alice", "bob")
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || !strings.HasSuffix(r.URL.Path, "/rooms/"+roomID) {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		writeTestJSON(w, map[string]interface{}{
+			"id":              roomID,
+			"name":            "Room",
+			"private":         private,
+			"created_by_id":   memberIDs[0],
+			"member_user_ids": memberIDs,
+		})
+	}
+}
+
+func TestCreateDirectRoomReturnsExistingRoom(t *testing.T) {
+	client, server := newStubServer(t, directRoomStub(true, "bob", "alice"))
+	defer server.Close()
+
+	room, err := client.Rooms().CreateDirectRoom(context.Background(), "alice", "bob", CreateDirectRoomOptions{})
+	if err != nil {
+		t.Fatalf("Failed to get direct room: %v", err)
+	}
+	if room.ID != DirectRoomID("alice", "bob") {
+		t.Errorf("Expected the existing direct room, got %+v", room)
+	}
+}
+
+func TestCreateDirectRoomRejectsOtherRooms(t *testing.T) {
+	for name, handler := range map[string]http.HandlerFunc{
+		"public":        directRoomStub(false, "alice", "bob"),
+		"other members": directRoomStub(true, "alice", "carol"),
+		"extra members": directRoomStub(true, "alice", "bob", "carol"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			client, server := newStubServer(t, handler)
+			defer server.Close()
+
+			room, err := client.Rooms().CreateDirectRoom(context.Background(), "alice", "bob", CreateDirectRoomOptions{})
+			if err != ErrNotDirectRoom {
+				t.Errorf("Expected ErrNotDirectRoom, got %+v and %v", room, err)
+			}
+		})
+	}
+}
+
+// countStub serves a room with a history of messages messages, counting the pages fetched.
+func countStub(messages int, pages *int) http.HandlerFunc {
+	var mu sync.Mutex
+	return func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/messages") {
+			mu.Lock()
+			*pages++
+			mu.Unlock()
+
+			initialID, _ := strconv.Atoi(r.URL.Query().Get("initial_id"))
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			page := []map[string]interface{}{}
+			for id := initialID + 1; id <= messages && len(page) < limit; id++ {
+				page = append(page, map[string]interface{}{"id": id})
+			}
+			writeTestJSON(w, page)
+			return
+		}
+
+		writeTestJSON(w, map[string]interface{}{
+			"id":              "room-1",
+			"member_user_ids": []string{"alice", "bob"},
+		})
+	}
+}
+
+func TestGetRoomCounts(t *testing.T) {
+	pages := 0
+	client, server := newStubServer(t, countStub(250, &pages))
+	defer server.Close()
+
+	counts, err := client.Rooms().GetRoomCounts(context.Background(), "room-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if counts != (RoomCounts{Members: 2, Messages: 250}) {
+		t.Errorf("Expected 2 members and 250 messages, got %+v", counts)
+	}
+	if pages != 3 {
+		t.Errorf("Expected 3 pages to be fetched, got %d", pages)
+	}
+}
+
+func TestGetRoomCountsStopsAtMaxCountedRoomMessages(t *testing.T) {
+	pages := 0
+	client, server := newStubServer(t, countStub(MaxCountedRoomMessages+500, &pages))
+	defer server.Close()
+
+	counts, err := client.Rooms().GetRoomCounts(context.Background(), "room-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if counts != (RoomCounts{Members: 2, Messages: MaxCountedRoomMessages, MoreMessages: true}) {
+		t.Errorf("Expected the count to stop at %d messages, got %+v", MaxCountedRoomMessages, counts)
+	}
+	if pages != MaxCountedRoomMessages/100+1 {
+		t.Errorf("Expected %d pages to be fetched, got %d", MaxCountedRoomMessages/100+1, pages)
+	}
+}
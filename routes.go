@@ -0,0 +1,69 @@
+package chatkit
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Parameters of routes, by the name of the path segment preceding them.
+var routeParams = map[string]string{
+	"rooms":    "room_id",
+	"users":    "user_id",
+	"messages": "message_id",
+	"roles":    "role_name",
+	"scope":    "scope",
+	"cursors":  "cursor_type",
+	"deletes":  "job_id",
+}
+
+// Path segments that are actions rather than parameters, e.g. in /rooms/{room_id}/users/add.
+var routeActions = map[string]bool{"add": true, "remove": true}
+
+// route describes the path of a request to a Chatkit service.
+type route struct {
+	Service  string            // Name of the service, e.g. chatkit_cursors
+	Version  string            // Version of the service, e.g. v6
+	Template string            // Path with its parameters replaced by their names, e.g. /rooms/{room_id}
+	Params   map[string]string // Values of the parameters, by name
+}
+
+// parseRoute parses the path of a request made by the platform client, which is prefixed with
+// /services/{service}/{version}/{instance_id}.
+func parseRoute(path string) route {
+	if i := strings.Index(path, "?"); i >= 0 {
+		path = path[:i]
+	}
+
+	var r route
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) >= 4 && segments[0] == "services" {
+		r.Service, r.Version = segments[1], segments[2]
+		segments = segments[4:]
+	}
+
+	template := make([]string, len(segments))
+	for i, segment := range segments {
+		template[i] = segment
+
+		if i == 0 {
+			continue
+		}
+
+		name, ok := routeParams[segments[i-1]]
+		if !ok || routeActions[segment] || template[i-1] != segments[i-1] {
+			continue
+		}
+
+		if r.Params == nil {
+			r.Params = map[string]string{}
+		}
+		if value, err := url.PathUnescape(segment); err == nil {
+			segment = value
+		}
+		r.Params[name] = segment
+		template[i] = "{" + name + "}"
+	}
+
+	r.Template = "/" + strings.Join(template, "/")
+	return r
+}
@@ -0,0 +1,273 @@
+package chatkit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrScheduledMessageNotFound is returned when cancelling a scheduled message that does not
+// exist, or has already been sent.
+var ErrScheduledMessageNotFound = errors.New("Scheduled message not found")
+
+// scheduledMessagesStorePrefix is the prefix of the Store keys scheduled messages are kept under.
+const scheduledMessagesStorePrefix = "scheduled-messages/"
+
+const (
+	defaultSchedulerPollInterval = time.Second
+	defaultSchedulerMaxAttempts  = 5
+	defaultSchedulerRetryBackoff = 10 * time.Second
+
+	// How long a scheduler has to send a message it claimed before other schedulers may.
+	schedulerClaimTimeout = time.Minute
+)
+
+// ScheduleID identifies a scheduled message.
+type ScheduleID string
+
+// SchedulerOptions contains parameters to configure a Scheduler.
+type SchedulerOptions struct {
+	// How often the store is checked for messages that are due. Defaults to 1s.
+	PollInterval time.Duration
+	// Number of times sending a message is attempted before it is given up on. Defaults to 5.
+	MaxAttempts int
+	// Delay before the first retry of a failed send, doubled for each subsequent retry.
+	// Defaults to 10s.
+	RetryBackoff time.Duration
+	// Optional function called when a send fails, with whether the message has been given up on.
+	// Messages sent without their expiry or idempotency key being recorded are reported with an
+	// *ExpiryError or *IdempotencyKeyError, and are not sent again.
+	OnError func(id ScheduleID, err error, givenUp bool)
+}
+
+// Scheduler sends messages at a later time.
+//
+// Scheduled messages are kept in the client's Store (see WithStore), so they survive a restart
+// when a persistent store is used, and are sent by Run. When several processes share a store
+// each of them may run a Scheduler: a message that is due is claimed with Store.Take before it
+// is sent, so only one of them sends it. If the process sending a message stops before it is
+// removed from the store, it is sent again by a Scheduler once a minute has passed. It is sent
+// with the same idempotency key, derived from its ScheduleID, so it is only sent twice if the
+// process stopped after sending it but before recording its idempotency key.
+type Scheduler struct {
+	client  *Client
+	options SchedulerOptions
+}
+
+type scheduledMessage struct {
+	Options       SendMessageOptions `json:"options"`
+	At            time.Time          `json:"at"`
+	Attempts      int                `json:"attempts"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+}
+
+// NewScheduler returns a Scheduler that sends messages with client.
+func NewScheduler(client *Client, options SchedulerOptions) *Scheduler {
+	if options.PollInterval <= 0 {
+		options.PollInterval = defaultSchedulerPollInterval
+	}
+
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultSchedulerMaxAttempts
+	}
+
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaultSchedulerRetryBackoff
+	}
+
+	return &Scheduler{client: client, options: options}
+}
+
+// ScheduleMessage schedules a message to be sent to a room at the given time.
+// Messages scheduled in the past are sent the next time the store is checked.
+func (s *Scheduler) ScheduleMessage(
+	ctx context.Context,
+	options SendMessageOptions,
+	at time.Time,
+) (ScheduleID, error) {
+	if options.RoomID == "" {
+		return "", errors.New("You must provide the ID of the room to send the message to")
+	}
+
+	if options.SenderID == "" {
+		return "", errors.New("You must provide the ID of the user sending the message")
+	}
+
+	idBytes := make([]byte, 16)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", fmt.Errorf("Failed to generate schedule ID: %v", err)
+	}
+	id := ScheduleID(hex.EncodeToString(idBytes))
+
+	if options.IdempotencyKey == "" {
+		options.IdempotencyKey = "scheduled-" + string(id)
+	}
+
+	err := s.put(ctx, id, scheduledMessage{Options: options, At: at, NextAttemptAt: at})
+	if err != nil {
+		return "", err
+	}
+
+	return id, nil
+}
+
+// CancelScheduledMessage cancels a scheduled message, so that it is not sent.
+// It returns ErrScheduledMessageNotFound if the message does not exist or has been sent.
+func (s *Scheduler) CancelScheduledMessage(ctx context.Context, id ScheduleID) error {
+	key := scheduledMessagesStorePrefix + string(id)
+	defer lockKey(key)()
+
+	if _, err := s.client.store.Get(ctx, key); err != nil {
+		if err == ErrStoreKeyNotFound {
+			return ErrScheduledMessageNotFound
+		}
+		return err
+	}
+
+	return s.client.store.Delete(ctx, key)
+}
+
+// Run sends scheduled messages as they become due, until ctx is cancelled.
+// It returns the error that caused it to stop.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.options.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.sendDue(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendDue sends the scheduled messages whose next attempt is due.
+func (s *Scheduler) sendDue(ctx context.Context) error {
+	keys, err := s.client.store.List(ctx, scheduledMessagesStorePrefix)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		id := ScheduleID(key[len(scheduledMessagesStorePrefix):])
+		if err := s.sendIfDue(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendIfDue attempts to send a scheduled message if it is due. Failures to send are reported to
+// OnError; only failures to access the store are returned.
+func (s *Scheduler) sendIfDue(ctx context.Context, id ScheduleID) error {
+	key := scheduledMessagesStorePrefix + string(id)
+	defer lockKey(key)()
+
+	value, err := s.client.store.Get(ctx, key)
+	if err == ErrStoreKeyNotFound {
+		// Cancelled, or sent by another scheduler.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var message scheduledMessage
+	if err := json.Unmarshal(value, &message); err != nil {
+		return fmt.Errorf("Failed to decode scheduled message %s: %v", id, err)
+	}
+
+	if time.Now().Before(message.NextAttemptAt) {
+		return nil
+	}
+
+	message, claimed, err := s.claim(ctx, id)
+	if err != nil || !claimed {
+		return err
+	}
+
+	_, sendErr := s.client.Messages().SendMessage(ctx, message.Options)
+	if !sendFailed(sendErr) {
+		if sendErr != nil && s.options.OnError != nil {
+			// Sent, but its expiry or idempotency key wasn't recorded; sending it again would
+			// duplicate it.
+			s.options.OnError(id, sendErr, true)
+		}
+		return s.client.store.Delete(ctx, key)
+	}
+
+	if ctx.Err() != nil {
+		// Stopped while sending; the attempt doesn't count, and the message is sent again once
+		// its claim expires.
+		return ctx.Err()
+	}
+
+	message.Attempts++
+	givenUp := message.Attempts >= s.options.MaxAttempts
+	if s.options.OnError != nil {
+		s.options.OnError(id, sendErr, givenUp)
+	}
+
+	if givenUp {
+		return s.client.store.Delete(ctx, key)
+	}
+
+	message.NextAttemptAt = time.Now().Add(s.options.RetryBackoff << uint(message.Attempts-1))
+	return s.put(ctx, id, message)
+}
+
+// claim takes a scheduled message that is due from the store, and puts it back with its next
+// attempt delayed by schedulerClaimTimeout, so that other schedulers leave it alone while it is
+// sent. It returns the message as it was before being claimed, and false if the message was
+// cancelled, claimed by another scheduler or isn't due anymore.
+func (s *Scheduler) claim(ctx context.Context, id ScheduleID) (scheduledMessage, bool, error) {
+	value, err := s.client.store.Take(ctx, scheduledMessagesStorePrefix+string(id))
+	if err == ErrStoreKeyNotFound {
+		return scheduledMessage{}, false, nil
+	}
+	if err != nil {
+		return scheduledMessage{}, false, err
+	}
+
+	var message scheduledMessage
+	if err := json.Unmarshal(value, &message); err != nil {
+		return scheduledMessage{}, false, fmt.Errorf("Failed to decode scheduled message %s: %v", id, err)
+	}
+
+	// Another scheduler claimed it, or rescheduled it after failing to send it, since it was read.
+	now := time.Now()
+	if now.Before(message.NextAttemptAt) {
+		return scheduledMessage{}, false, s.put(ctx, id, message)
+	}
+
+	claimed := message
+	claimed.NextAttemptAt = now.Add(schedulerClaimTimeout)
+	if err := s.put(ctx, id, claimed); err != nil {
+		return scheduledMessage{}, false, err
+	}
+
+	return message, true, nil
+}
+
+func (s *Scheduler) put(ctx context.Context, id ScheduleID, message scheduledMessage) error {
+	value, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("Failed to encode scheduled message %s: %v", id, err)
+	}
+
+	return s.client.store.Put(ctx, scheduledMessagesStorePrefix+string(id), value, 0)
+}
@@ -0,0 +1,113 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// claimedElsewhereStore is a Store whose keys are taken by another process as they are read.
+type claimedElsewhereStore struct {
+	Store
+}
+
+func (claimedElsewhereStore) Take(context.Context, string) ([]byte, error) {
+	return nil, ErrStoreKeyNotFound
+}
+
+func scheduleTestMessage(t *testing.T, scheduler *Scheduler) ScheduleID {
+	id, err := scheduler.ScheduleMessage(context.Background(), SendMessageOptions{
+		RoomID:   "general",
+		Text:     "later",
+		SenderID: "alice",
+	}, time.Now().Add(-time.Second))
+	if err != nil {
+		t.Fatalf("Failed to schedule message: %v", err)
+	}
+
+	return id
+}
+
+func TestSchedulerClaimsMessagesWhileSending(t *testing.T) {
+	store := NewMemoryStore()
+
+	var claimedUntil time.Time
+	var sent int32
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		keys, _ := store.List(r.Context(), scheduledMessagesStorePrefix)
+		if len(keys) == 1 {
+			value, _ := store.Get(r.Context(), keys[0])
+			var message scheduledMessage
+			json.Unmarshal(value, &message)
+			claimedUntil = message.NextAttemptAt
+		}
+
+		atomic.AddInt32(&sent, 1)
+		w.WriteHeader(http.StatusCreated)
+		writeTestJSON(w, map[string]interface{}{"message_id": 1})
+	}, WithStore(store))
+	defer server.Close()
+
+	scheduler := NewScheduler(client, SchedulerOptions{})
+	scheduleTestMessage(t, scheduler)
+
+	if err := scheduler.sendDue(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count := atomic.LoadInt32(&sent); count != 1 {
+		t.Fatalf("Expected the message to be sent once, got %d", count)
+	}
+	if !claimedUntil.After(time.Now()) {
+		t.Fatalf("Expected the message to be claimed while it was sent, got %v", claimedUntil)
+	}
+	if keys, _ := store.List(context.Background(), scheduledMessagesStorePrefix); len(keys) != 0 {
+		t.Fatalf("Expected the sent message to be removed, got %v", keys)
+	}
+}
+
+func TestSchedulerLeavesMessagesClaimedElsewhere(t *testing.T) {
+	var sent int32
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&sent, 1)
+		w.WriteHeader(http.StatusCreated)
+		writeTestJSON(w, map[string]interface{}{"message_id": 1})
+	}, WithStore(claimedElsewhereStore{NewMemoryStore()}))
+	defer server.Close()
+
+	scheduler := NewScheduler(client, SchedulerOptions{})
+	scheduleTestMessage(t, scheduler)
+
+	if err := scheduler.sendDue(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if count := atomic.LoadInt32(&sent); count != 0 {
+		t.Fatalf("Expected a message claimed by another scheduler not to be sent, got %d sends", count)
+	}
+}
+
+func TestSchedulerLeavesMessagesThatAreNoLongerDue(t *testing.T) {
+	store := NewMemoryStore()
+	client := newTestClient(t, WithStore(store))
+	scheduler := NewScheduler(client, SchedulerOptions{})
+	id := scheduleTestMessage(t, scheduler)
+
+	// Claimed by another scheduler since it was read.
+	key := scheduledMessagesStorePrefix + string(id)
+	value, _ := store.Get(context.Background(), key)
+	var message scheduledMessage
+	json.Unmarshal(value, &message)
+	message.NextAttemptAt = time.Now().Add(time.Minute)
+	scheduler.put(context.Background(), id, message)
+
+	_, claimed, err := scheduler.claim(context.Background(), id)
+	if err != nil || claimed {
+		t.Fatalf("Expected a message claimed elsewhere not to be claimed, got %v, %v", claimed, err)
+	}
+	if _, err := store.Get(context.Background(), key); err != nil {
+		t.Fatalf("Expected the message to be put back, got %v", err)
+	}
+}
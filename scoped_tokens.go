@@ -0,0 +1,78 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/pusher/pusher-platform-go/auth"
+)
+
+// ReadOnlyPermissions are the permissions of tokens generated by GenerateReadOnlyToken: they can
+// read users, rooms, messages, files, cursors and presence, but not change anything.
+var ReadOnlyPermissions = []string{
+	PermissionRoomGet,
+	PermissionRoomMessagesGet,
+	PermissionUserGet,
+	PermissionUserRoomsGet,
+	PermissionFileGet,
+	PermissionCursorsReadGet,
+	PermissionPresenceSubscribe,
+}
+
+// ScopedTokenOptions contains parameters to pass when generating a scoped token.
+type ScopedTokenOptions struct {
+	// ID of the user the token is issued to, e.g. a service user "analytics". Optional.
+	UserID string
+	// Permissions the token grants, e.g. ReadOnlyPermissions.
+	Permissions []string
+	// Lifetime of the token. Defaults to that of other tokens, see WithTokenOptions.
+	TokenExpiry time.Duration
+	// Additional claims to add to the token, see Authenticate.
+	ServiceClaims map[string]interface{}
+}
+
+// GenerateScopedToken generates a token that only grants the given permissions, for jobs that
+// shouldn't hold the `su` claim, such as analytics.
+// The permissions are carried in the token's `permissions` claim, so each token has its own
+// scope and no roles are changed. Chatkit doesn't read the claim, so the scope is enforced by the
+// services the token is presented to: VerifyToken returns it as Claims.Permissions, checked by
+// Claims.Allows and the RequirePermissions option of AuthMiddleware. Towards Chatkit the token
+// has the permissions of its user's roles.
+func (a AuthClient) GenerateScopedToken(ctx context.Context, options ScopedTokenOptions) (auth.TokenWithExpiry, error) {
+	if len(options.Permissions) == 0 {
+		return auth.TokenWithExpiry{}, errors.New("You must provide the permissions the token grants")
+	}
+
+	permissions := uniqueStrings(options.Permissions)
+	sort.Strings(permissions)
+
+	authOptions := auth.Options{ServiceClaims: options.ServiceClaims}
+	if options.UserID != "" {
+		authOptions.UserID = &options.UserID
+	}
+	if options.TokenExpiry > 0 {
+		authOptions.TokenExpiry = &options.TokenExpiry
+	}
+
+	return a.client.authenticatorService.GenerateScopedToken(authOptions, permissions)
+}
+
+// ReadOnlyTokenOptions contains parameters to pass when generating a read only token.
+type ReadOnlyTokenOptions struct {
+	UserID        string                 // ID of the user the token is issued to, see ScopedTokenOptions
+	TokenExpiry   time.Duration          // Lifetime of the token. Defaults to that of other tokens
+	ServiceClaims map[string]interface{} // Additional claims to add to the token
+}
+
+// GenerateReadOnlyToken generates a token with the ReadOnlyPermissions, e.g. for analytics jobs.
+// See GenerateScopedToken.
+func (a AuthClient) GenerateReadOnlyToken(ctx context.Context, options ReadOnlyTokenOptions) (auth.TokenWithExpiry, error) {
+	return a.GenerateScopedToken(ctx, ScopedTokenOptions{
+		UserID:        options.UserID,
+		Permissions:   ReadOnlyPermissions,
+		TokenExpiry:   options.TokenExpiry,
+		ServiceClaims: options.ServiceClaims,
+	})
+}
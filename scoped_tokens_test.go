@@ -0,0 +1,103 @@
+package chatkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGenerateScopedToken(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	tokenWithExpiry, err := client.Auth().GenerateScopedToken(ctx, ScopedTokenOptions{
+		UserID:      "analytics",
+		Permissions: []string{PermissionRoomGet, PermissionUserGet, PermissionRoomGet},
+	})
+	if err != nil {
+		t.Fatalf("Failed to generate scoped token: %v", err)
+	}
+
+	claims, err := client.Auth().VerifyToken(ctx, tokenWithExpiry.Token)
+	if err != nil {
+		t.Fatalf("Failed to verify scoped token: %v", err)
+	}
+
+	if claims.UserID != "analytics" || claims.SU {
+		t.Errorf("Expected a token for analytics without su, got %+v", claims)
+	}
+	if len(claims.Permissions) != 2 || !claims.Allows(PermissionRoomGet) || !claims.Allows(PermissionUserGet) {
+		t.Errorf("Expected the token to grant room:get and user:get, got %v", claims.Permissions)
+	}
+	if claims.Allows(PermissionRoomDelete) {
+		t.Error("Expected the token not to grant room:delete")
+	}
+	if _, ok := claims.ServiceClaims["permissions"]; ok {
+		t.Error("Expected the permissions not to be returned as a service claim")
+	}
+}
+
+func TestGenerateScopedTokenValidation(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Auth().GenerateScopedToken(ctx, ScopedTokenOptions{UserID: "analytics"}); err == nil {
+		t.Error("Expected an error for a token without permissions")
+	}
+
+	_, err := client.Auth().GenerateScopedToken(ctx, ScopedTokenOptions{
+		Permissions:   ReadOnlyPermissions,
+		ServiceClaims: map[string]interface{}{"permissions": []string{PermissionRoomDelete}},
+	})
+	if err == nil {
+		t.Error("Expected an error for service claims replacing the permissions")
+	}
+
+	_, err = client.Auth().GenerateAccessToken(AuthenticateOptions{
+		ServiceClaims: map[string]interface{}{"permissions": ReadOnlyPermissions},
+	})
+	if err == nil {
+		t.Error("Expected an error for service claims setting permissions")
+	}
+}
+
+func TestAuthMiddlewareRequirePermissions(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	readOnly, err := client.Auth().GenerateReadOnlyToken(ctx, ReadOnlyTokenOptions{UserID: "analytics"})
+	if err != nil {
+		t.Fatalf("Failed to generate read only token: %v", err)
+	}
+
+	unrestricted, err := client.Auth().GenerateAccessToken(AuthenticateOptions{UserID: &[]string{"alice"}[0]})
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	tests := []struct {
+		token       string
+		permissions []string
+		status      int
+	}{
+		{readOnly.Token, []string{PermissionRoomGet}, http.StatusOK},
+		{readOnly.Token, []string{PermissionRoomGet, PermissionRoomDelete}, http.StatusUnauthorized},
+		{unrestricted.Token, []string{PermissionRoomDelete}, http.StatusOK},
+	}
+
+	for _, test := range tests {
+		handler := client.Auth().AuthMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}), VerifierOptions{RequirePermissions: test.permissions})
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		request.Header.Set("Authorization", "Bearer "+test.token)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		if recorder.Code != test.status {
+			t.Errorf("Expected %d for permissions %v, got %d", test.status, test.permissions, recorder.Code)
+		}
+	}
+}
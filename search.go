@@ -0,0 +1,118 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// SearchRoomMessagesOptions contains parameters to pass when searching a room's messages.
+type SearchRoomMessagesOptions struct {
+	// Treat the query as a regular expression (RE2 syntax) rather than plain text.
+	Regexp bool
+	// Match case exactly. By default the query matches regardless of case.
+	CaseSensitive bool
+	// Number of messages before and after each match to return with it.
+	ContextSize int
+	// Maximum number of matches to return. Defaults to no limit.
+	Limit int
+	// Only search messages newer than the message with this ID.
+	InitialID *uint
+}
+
+// MessageSearchResult is a message that matched a search, along with the messages around it.
+type MessageSearchResult struct {
+	Message MultipartMessage   // The matching message
+	Before  []MultipartMessage // Messages preceding the match, oldest first
+	After   []MultipartMessage // Messages following the match, oldest first
+}
+
+// SearchRoomMessages searches the text/plain parts of a room's messages for query, and returns
+// the matching messages oldest first.
+// Chatkit has no search index, so the room's history is paged through from the oldest message
+// (or InitialID) until Limit matches have been found; searching a long history is slow and
+// should be bounded with Limit or InitialID where possible.
+func (m MessagesClient) SearchRoomMessages(
+	ctx context.Context,
+	roomID string,
+	query string,
+	options SearchRoomMessagesOptions,
+) ([]MessageSearchResult, error) {
+	if query == "" {
+		return nil, errors.New("You must provide a query")
+	}
+
+	if options.ContextSize < 0 {
+		return nil, errors.New("ContextSize must not be negative")
+	}
+
+	pattern := query
+	if !options.Regexp {
+		pattern = regexp.QuoteMeta(query)
+	}
+	if !options.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to parse query: %v", err)
+	}
+
+	var (
+		results []MessageSearchResult
+		// Matches still collecting the messages that follow them.
+		pending []MessageSearchResult
+		// The messages preceding the current one.
+		window  []MultipartMessage
+		matches int
+	)
+
+	it := m.IterateRoomMessages(ctx, roomID, IterateRoomMessagesOptions{
+		Direction: "newer",
+		InitialID: options.InitialID,
+	})
+	for it.Next() {
+		message := it.Message()
+
+		for i := range pending {
+			pending[i].After = append(pending[i].After, message)
+		}
+		for len(pending) > 0 && len(pending[0].After) == options.ContextSize {
+			results, pending = append(results, pending[0]), pending[1:]
+		}
+
+		limitReached := options.Limit > 0 && matches >= options.Limit
+		if limitReached && len(pending) == 0 {
+			break
+		}
+
+		if !limitReached && re.MatchString(message.PlainText()) {
+			matches++
+
+			result := MessageSearchResult{
+				Message: message,
+				Before:  append([]MultipartMessage{}, window...),
+			}
+			if options.ContextSize == 0 {
+				results = append(results, result)
+			} else {
+				pending = append(pending, result)
+			}
+		}
+
+		if options.ContextSize > 0 {
+			if len(window) == options.ContextSize {
+				window = window[1:]
+			}
+			window = append(window, message)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	// The last matches are followed by fewer messages than the context size.
+	return append(results, pending...), nil
+}
@@ -0,0 +1,46 @@
+package chatkit
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// CallInfo describes a call made to Chatkit.
+type CallInfo struct {
+	Method   string
+	Path     string // Path of the request, with tokens redacted
+	Route    string // Path of the request, with its parameters replaced by their names
+	Status   int    // Status of the response, or 0 if the call failed without one
+	Duration time.Duration
+	Err      error
+}
+
+// reportSlowRequests is an interceptor that calls onSlowRequest for the requests taking longer
+// than threshold. For subscriptions, only the time taken to open them is measured.
+func reportSlowRequests(threshold time.Duration, onSlowRequest func(CallInfo)) common.Interceptor {
+	return func(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+		next common.Invoker,
+	) (*http.Response, error) {
+		start := time.Now()
+		response, err := next(ctx, options)
+
+		if duration := time.Since(start); duration > threshold {
+			onSlowRequest(CallInfo{
+				Method:   options.Method,
+				Path:     redactedPath(options),
+				Route:    parseRoute(options.Path).Template,
+				Status:   responseStatus(response, err),
+				Duration: duration,
+				Err:      err,
+			})
+		}
+
+		return response, err
+	}
+}
@@ -0,0 +1,134 @@
+package chatkit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// RequestStatsKey identifies the requests counted together in Stats.
+type RequestStatsKey struct {
+	Method string
+	Route  string // Path of the request, with its parameters replaced by their names
+	Status int    // Status of the response, or 0 if the request failed without one
+}
+
+// Stats is a snapshot of the counters of a Client, since it was created.
+type Stats struct {
+	Requests      map[RequestStatsKey]uint64
+	BytesSent     uint64 // Size of the request bodies sent
+	BytesReceived uint64 // Size of the response bodies read
+	TokensMinted  uint64 // Tokens generated to make requests with
+	// Number of requests made with a cached token, and that could have been but weren't.
+	TokenCacheHits   uint64
+	TokenCacheMisses uint64
+	// Number of subscription events dropped, or subscriptions ended with OverflowError, because
+	// their consumer fell behind.
+	EventsDropped uint64
+}
+
+// TokenCacheHitRate returns the proportion of the requests whose token could be cached that
+// were made with a cached token, or 0 if there were none.
+func (s Stats) TokenCacheHitRate() float64 {
+	total := s.TokenCacheHits + s.TokenCacheMisses
+	if total == 0 {
+		return 0
+	}
+
+	return float64(s.TokenCacheHits) / float64(total)
+}
+
+// clientStats accumulates the counters of a Client.
+type clientStats struct {
+	// Updated atomically, and first to be 64-bit aligned.
+	bytesSent     uint64
+	bytesReceived uint64
+	eventsDropped uint64
+	tokens        common.TokenStats
+
+	mu       sync.Mutex
+	requests map[RequestStatsKey]uint64
+}
+
+func newClientStats() *clientStats {
+	return &clientStats{requests: map[RequestStatsKey]uint64{}}
+}
+
+// intercept counts a request, and the bytes sent and received with it.
+func (s *clientStats) intercept(
+	ctx context.Context,
+	options platformclient.RequestOptions,
+	next common.Invoker,
+) (*http.Response, error) {
+	if options.Body != nil {
+		options.Body = &countingReader{Reader: options.Body, count: &s.bytesSent}
+	}
+
+	response, err := next(ctx, options)
+	if response != nil && response.Body != nil {
+		response.Body = &countingReadCloser{
+			countingReader: countingReader{Reader: response.Body, count: &s.bytesReceived},
+			Closer:         response.Body,
+		}
+	}
+
+	key := RequestStatsKey{
+		Method: options.Method,
+		Route:  parseRoute(options.Path).Template,
+		Status: responseStatus(response, err),
+	}
+
+	s.mu.Lock()
+	s.requests[key]++
+	s.mu.Unlock()
+
+	return response, err
+}
+
+func (s *clientStats) snapshot() Stats {
+	stats := Stats{
+		Requests:         map[RequestStatsKey]uint64{},
+		BytesSent:        atomic.LoadUint64(&s.bytesSent),
+		BytesReceived:    atomic.LoadUint64(&s.bytesReceived),
+		TokensMinted:     atomic.LoadUint64(&s.tokens.Minted),
+		TokenCacheHits:   atomic.LoadUint64(&s.tokens.CacheHits),
+		TokenCacheMisses: atomic.LoadUint64(&s.tokens.CacheMisses),
+		EventsDropped:    atomic.LoadUint64(&s.eventsDropped),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, count := range s.requests {
+		stats.Requests[key] = count
+	}
+
+	return stats
+}
+
+type countingReader struct {
+	io.Reader
+	count *uint64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	atomic.AddUint64(r.count, uint64(n))
+	return n, err
+}
+
+type countingReadCloser struct {
+	countingReader
+	io.Closer
+}
+
+// Stats returns a snapshot of the counters of the client: the requests it has made, by method,
+// route and status, the bytes they sent and received, and the tokens they were made with.
+func (c *Client) Stats() Stats {
+	return c.stats.snapshot()
+}
@@ -0,0 +1,630 @@
+package chatkit
+
+import (
+	"container/list"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrStoreKeyNotFound is returned by Store.Get when the key does not exist or has expired.
+var ErrStoreKeyNotFound = errors.New("Key not found in store")
+
+// Store is the persistence interface shared by the SDK's stateful helpers, such as message
+// scheduling, send deduplication and response caching.
+// Implementing it once lets every helper persist its state in the same place.
+// Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the value stored for key, or ErrStoreKeyNotFound.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Put stores value for key. A positive ttl makes the key expire after that duration.
+	Put(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes key. Deleting a key that does not exist is not an error.
+	Delete(ctx context.Context, key string) error
+	// Take removes key and returns the value it had, or ErrStoreKeyNotFound, atomically: of
+	// concurrent calls for the same key, from any process sharing the store, only one gets the
+	// value.
+	Take(ctx context.Context, key string) ([]byte, error)
+	// List returns the keys, in lexical order, that start with prefix and have not expired.
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+type memoryStoreEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+func (e memoryStoreEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+// memoryStoreSweepInterval is how often a memory store removes expired entries that nothing has
+// read since they expired.
+const memoryStoreSweepInterval = time.Minute
+
+type memoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryStoreEntry
+	// When expired entries were last removed.
+	sweptAt time.Time
+}
+
+// NewMemoryStore returns a Store that keeps its data in memory.
+// Its contents are lost when the process exits. Expired entries are removed as values are put,
+// at most once a minute, so keys that are never read again don't accumulate.
+func NewMemoryStore() Store {
+	return &memoryStore{entries: map[string]memoryStoreEntry{}, sweptAt: time.Now()}
+}
+
+// sweep removes the expired entries if it hasn't done so recently. s.mu must be held.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.sweptAt) < memoryStoreSweepInterval {
+		return
+	}
+
+	for key, entry := range s.entries {
+		if entry.expired(now) {
+			delete(s.entries, key)
+		}
+	}
+	s.sweptAt = now
+}
+
+func (s *memoryStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || entry.expired(time.Now()) {
+		delete(s.entries, key)
+		return nil, ErrStoreKeyNotFound
+	}
+
+	return append([]byte(nil), entry.value...), nil
+}
+
+func (s *memoryStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	now := time.Now()
+	entry := memoryStoreEntry{value: append([]byte(nil), value...)}
+	if ttl > 0 {
+		entry.expiresAt = now.Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+	s.entries[key] = entry
+	return nil
+}
+
+func (s *memoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+func (s *memoryStore) Take(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	delete(s.entries, key)
+	if !ok || entry.expired(time.Now()) {
+		return nil, ErrStoreKeyNotFound
+	}
+
+	return entry.value, nil
+}
+
+func (s *memoryStore) List(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	keys := []string{}
+	for key, entry := range s.entries {
+		if entry.expired(now) {
+			delete(s.entries, key)
+			continue
+		}
+
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+type lruStoreEntry struct {
+	key string
+	memoryStoreEntry
+}
+
+// lruStore is an in-memory Store of up to maxEntries keys, evicting the least recently used.
+type lruStore struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// Entries of entries, most recently used first.
+	lru *list.List
+}
+
+func newLRUStore(maxEntries int) *lruStore {
+	return &lruStore{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+	}
+}
+
+func (s *lruStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, ErrStoreKeyNotFound
+	}
+
+	entry := element.Value.(*lruStoreEntry)
+	if entry.expired(time.Now()) {
+		s.remove(element)
+		return nil, ErrStoreKeyNotFound
+	}
+
+	s.lru.MoveToFront(element)
+	return append([]byte(nil), entry.value...), nil
+}
+
+func (s *lruStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := &lruStoreEntry{key: key, memoryStoreEntry: memoryStoreEntry{value: append([]byte(nil), value...)}}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		element.Value = entry
+		s.lru.MoveToFront(element)
+		return nil
+	}
+
+	s.entries[key] = s.lru.PushFront(entry)
+	for s.lru.Len() > s.maxEntries {
+		s.remove(s.lru.Back())
+	}
+
+	return nil
+}
+
+func (s *lruStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+
+	return nil
+}
+
+func (s *lruStore) Take(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, ErrStoreKeyNotFound
+	}
+
+	s.remove(element)
+	entry := element.Value.(*lruStoreEntry)
+	if entry.expired(time.Now()) {
+		return nil, ErrStoreKeyNotFound
+	}
+
+	return entry.value, nil
+}
+
+func (s *lruStore) List(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	keys := []string{}
+	for key, element := range s.entries {
+		if element.Value.(*lruStoreEntry).expired(now) {
+			s.remove(element)
+			continue
+		}
+
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+func (s *lruStore) remove(element *list.Element) {
+	s.lru.Remove(element)
+	delete(s.entries, element.Value.(*lruStoreEntry).key)
+}
+
+type fileStoreEntry struct {
+	Key       string    `json:"key,omitempty"`
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// maxFileStoreKeyLength is the length of the longest key stored in a file named after the key.
+// Hex encoding doubles it, and file names are limited to 255 bytes on most file systems.
+const maxFileStoreKeyLength = 100
+
+// fileStoreHashedPrefix starts the names of the files of keys too long to name them after.
+// It is not valid hex, so it can't be mistaken for an encoded key.
+const fileStoreHashedPrefix = "h-"
+
+type fileStore struct {
+	dir string
+	mu  sync.RWMutex
+}
+
+// NewFileStore returns a Store that keeps each key in a file in dir, creating the directory if
+// necessary. It survives restarts but is only suitable for a single process.
+func NewFileStore(dir string) (Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("Failed to create store directory: %v", err)
+	}
+
+	return &fileStore{dir: dir}, nil
+}
+
+// path returns the file a key is stored in. Keys are hex encoded so that any key is a valid
+// file name and listing by prefix can mostly be done from the file names. Longer keys are
+// hashed instead, to keep the name short enough, and are read from the file when listing.
+func (s *fileStore) path(key string) string {
+	if len(key) > maxFileStoreKeyLength {
+		hash := sha256.Sum256([]byte(key))
+		return filepath.Join(s.dir, fileStoreHashedPrefix+hex.EncodeToString(hash[:]))
+	}
+
+	return filepath.Join(s.dir, hex.EncodeToString([]byte(key)))
+}
+
+func (s *fileStore) read(key string) (fileStoreEntry, error) {
+	return readFileStoreEntry(s.path(key), key)
+}
+
+// readFileStoreEntry reads the entry of key from the file at path.
+func readFileStoreEntry(path string, key string) (fileStoreEntry, error) {
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return fileStoreEntry{}, ErrStoreKeyNotFound
+	}
+	if err != nil {
+		return fileStoreEntry{}, err
+	}
+
+	var entry fileStoreEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return fileStoreEntry{}, fmt.Errorf("Failed to read stored value for %s: %v", key, err)
+	}
+
+	if !entry.ExpiresAt.IsZero() && !time.Now().Before(entry.ExpiresAt) {
+		return fileStoreEntry{}, ErrStoreKeyNotFound
+	}
+
+	return entry, nil
+}
+
+func (s *fileStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, err := s.read(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.Value, nil
+}
+
+func (s *fileStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := fileStoreEntry{Key: key, Value: value}
+	if ttl > 0 {
+		entry.ExpiresAt = time.Now().Add(ttl)
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Write to a temporary file first so a crash never leaves a partially written value.
+	tmp, err := ioutil.TempFile(s.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.path(key))
+}
+
+func (s *fileStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	err := os.Remove(s.path(key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Take moves the file of key aside before reading it, so that only one of the calls racing for
+// it, even from other processes, succeeds.
+func (s *fileStore) Take(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return nil, err
+	}
+
+	taken := filepath.Join(s.dir, ".take-"+hex.EncodeToString(suffix))
+	if err := os.Rename(s.path(key), taken); os.IsNotExist(err) {
+		return nil, ErrStoreKeyNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	defer os.Remove(taken)
+
+	entry, err := readFileStoreEntry(taken, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return entry.Value, nil
+}
+
+func (s *fileStore) List(ctx context.Context, prefix string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	files, err := ioutil.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, file := range files {
+		name := file.Name()
+		if strings.HasPrefix(name, fileStoreHashedPrefix) {
+			entry, err := readFileStoreEntry(filepath.Join(s.dir, name), name)
+			if err == ErrStoreKeyNotFound || os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+
+			if strings.HasPrefix(entry.Key, prefix) {
+				keys = append(keys, entry.Key)
+			}
+			continue
+		}
+
+		decoded, err := hex.DecodeString(name)
+		if err != nil || !strings.HasPrefix(string(decoded), prefix) {
+			continue
+		}
+
+		key := string(decoded)
+		if _, err := s.read(key); err == ErrStoreKeyNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// RedisDoer executes a single Redis command, such as Do("GET", "key"), and returns its reply.
+// Replies must be returned as the client library decodes them: bulk strings as []byte or
+// string, integers as int64 and arrays as []interface{}. A missing key must be returned as a
+// nil reply rather than an error.
+//
+// Most Redis client libraries can be adapted in a line, for example with redigo:
+//
+//	chatkit.RedisDoerFunc(func(ctx context.Context, args ...interface{}) (interface{}, error) {
+//		conn := pool.Get()
+//		defer conn.Close()
+//		return conn.Do(args[0].(string), args[1:]...)
+//	})
+type RedisDoer interface {
+	Do(ctx context.Context, args ...interface{}) (interface{}, error)
+}
+
+// RedisDoerFunc adapts a function to the RedisDoer interface.
+type RedisDoerFunc func(ctx context.Context, args ...interface{}) (interface{}, error)
+
+// Do calls f.
+func (f RedisDoerFunc) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	return f(ctx, args...)
+}
+
+type redisStore struct {
+	redis     RedisDoer
+	keyPrefix string
+}
+
+// NewRedisStore returns a Store backed by Redis, suitable for sharing state between processes.
+// keyPrefix is prepended to every key, namespacing the SDK's keys within the Redis database.
+func NewRedisStore(redis RedisDoer, keyPrefix string) Store {
+	return &redisStore{redis: redis, keyPrefix: keyPrefix}
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) ([]byte, error) {
+	reply, err := s.redis.Do(ctx, "GET", s.keyPrefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply == nil {
+		return nil, ErrStoreKeyNotFound
+	}
+
+	value, err := redisString(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+func (s *redisStore) Put(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	args := []interface{}{"SET", s.keyPrefix + key, value}
+	if ttl > 0 {
+		milliseconds := int64(ttl / time.Millisecond)
+		if milliseconds == 0 {
+			milliseconds = 1
+		}
+		args = append(args, "PX", milliseconds)
+	}
+
+	_, err := s.redis.Do(ctx, args...)
+	return err
+}
+
+func (s *redisStore) Delete(ctx context.Context, key string) error {
+	_, err := s.redis.Do(ctx, "DEL", s.keyPrefix+key)
+	return err
+}
+
+// redisTakeScript gets and deletes a key in one step. GETDEL would do, but needs Redis 6.2.
+const redisTakeScript = `local value = redis.call("GET", KEYS[1])
+if value then
+	redis.call("DEL", KEYS[1])
+end
+return value`
+
+func (s *redisStore) Take(ctx context.Context, key string) ([]byte, error) {
+	reply, err := s.redis.Do(ctx, "EVAL", redisTakeScript, 1, s.keyPrefix+key)
+	if err != nil {
+		return nil, err
+	}
+
+	if reply == nil {
+		return nil, ErrStoreKeyNotFound
+	}
+
+	value, err := redisString(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(value), nil
+}
+
+func (s *redisStore) List(ctx context.Context, prefix string) ([]string, error) {
+	match := redisGlobEscaper.Replace(s.keyPrefix+prefix) + "*"
+
+	// SCAN may return a key more than once, such as when the keyspace is resized during the scan.
+	seen := map[string]bool{}
+	keys := []string{}
+	cursor := "0"
+	for {
+		reply, err := s.redis.Do(ctx, "SCAN", cursor, "MATCH", match, "COUNT", 100)
+		if err != nil {
+			return nil, err
+		}
+
+		values, ok := reply.([]interface{})
+		if !ok || len(values) != 2 {
+			return nil, fmt.Errorf("Unexpected reply to SCAN: %v", reply)
+		}
+
+		cursor, err = redisString(values[0])
+		if err != nil {
+			return nil, err
+		}
+
+		batch, ok := values[1].([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("Unexpected reply to SCAN: %v", reply)
+		}
+
+		for _, value := range batch {
+			key, err := redisString(value)
+			if err != nil {
+				return nil, err
+			}
+			key = strings.TrimPrefix(key, s.keyPrefix)
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+
+		if cursor == "0" {
+			break
+		}
+	}
+
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// redisGlobEscaper escapes the characters that are special in Redis MATCH patterns.
+var redisGlobEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+func redisString(reply interface{}) (string, error) {
+	switch r := reply.(type) {
+	case []byte:
+		return string(r), nil
+	case string:
+		return r, nil
+	case int64:
+		return strconv.FormatInt(r, 10), nil
+	default:
+		return "", fmt.Errorf("Unexpected Redis reply of type %T", reply)
+	}
+}
@@ -0,0 +1,216 @@
+package chatkit
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestStores returns a store of each kind that keeps its data locally.
+func newTestStores(t *testing.T) (map[string]Store, func()) {
+	dir, err := ioutil.TempDir("", "chatkit-store")
+	if err != nil {
+		t.Fatalf("Failed to create temporary directory: %v", err)
+	}
+
+	fileStore, err := NewFileStore(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatalf("Failed to create file store: %v", err)
+	}
+
+	stores := map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   fileStore,
+		"lru":    newLRUStore(100),
+	}
+
+	return stores, func() { os.RemoveAll(dir) }
+}
+
+func TestStorePutGetDelete(t *testing.T) {
+	ctx := context.Background()
+	stores, cleanup := newTestStores(t)
+	defer cleanup()
+
+	for name, store := range stores {
+		if err := store.Put(ctx, "a", []byte("hello"), 0); err != nil {
+			t.Fatalf("%s: Expected no error putting, got %v", name, err)
+		}
+
+		value, err := store.Get(ctx, "a")
+		if err != nil || string(value) != "hello" {
+			t.Fatalf("%s: Expected to get hello, got %q, %v", name, value, err)
+		}
+
+		if err := store.Delete(ctx, "a"); err != nil {
+			t.Fatalf("%s: Expected no error deleting, got %v", name, err)
+		}
+		if _, err := store.Get(ctx, "a"); err != ErrStoreKeyNotFound {
+			t.Fatalf("%s: Expected ErrStoreKeyNotFound after deleting, got %v", name, err)
+		}
+		if err := store.Delete(ctx, "a"); err != nil {
+			t.Fatalf("%s: Expected deleting a missing key not to fail, got %v", name, err)
+		}
+	}
+}
+
+func TestStoreListsByPrefix(t *testing.T) {
+	ctx := context.Background()
+	stores, cleanup := newTestStores(t)
+	defer cleanup()
+
+	for name, store := range stores {
+		store.Put(ctx, "prefix/b", []byte("2"), 0)
+		store.Put(ctx, "prefix/a", []byte("1"), 0)
+		store.Put(ctx, "other", []byte("3"), 0)
+
+		keys, err := store.List(ctx, "prefix/")
+		if err != nil {
+			t.Fatalf("%s: Expected no error, got %v", name, err)
+		}
+		if expected := []string{"prefix/a", "prefix/b"}; !reflect.DeepEqual(keys, expected) {
+			t.Fatalf("%s: Expected %v, got %v", name, expected, keys)
+		}
+	}
+}
+
+func TestStoreExpiresValues(t *testing.T) {
+	ctx := context.Background()
+	stores, cleanup := newTestStores(t)
+	defer cleanup()
+
+	for _, store := range stores {
+		store.Put(ctx, "a", []byte("1"), 10*time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+
+	for name, store := range stores {
+		if _, err := store.Get(ctx, "a"); err != ErrStoreKeyNotFound {
+			t.Fatalf("%s: Expected ErrStoreKeyNotFound once expired, got %v", name, err)
+		}
+
+		keys, err := store.List(ctx, "")
+		if err != nil || len(keys) != 0 {
+			t.Fatalf("%s: Expected no keys once expired, got %v, %v", name, keys, err)
+		}
+	}
+}
+
+func TestStoreTake(t *testing.T) {
+	ctx := context.Background()
+	stores, cleanup := newTestStores(t)
+	defer cleanup()
+
+	for name, store := range stores {
+		store.Put(ctx, "a", []byte("1"), 0)
+
+		value, err := store.Take(ctx, "a")
+		if err != nil || string(value) != "1" {
+			t.Fatalf("%s: Expected to take 1, got %q, %v", name, value, err)
+		}
+		if _, err := store.Take(ctx, "a"); err != ErrStoreKeyNotFound {
+			t.Fatalf("%s: Expected ErrStoreKeyNotFound taking twice, got %v", name, err)
+		}
+	}
+}
+
+func TestMemoryStoreSweepsExpiredEntries(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore().(*memoryStore)
+
+	for i := 0; i < 10; i++ {
+		store.Put(ctx, fmt.Sprintf("expired-%d", i), []byte("1"), time.Millisecond)
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	store.Put(ctx, "recent", []byte("1"), 0)
+	if len(store.entries) != 11 {
+		t.Fatalf("Expected no sweep within the interval, got %d entries", len(store.entries))
+	}
+
+	store.sweptAt = time.Now().Add(-memoryStoreSweepInterval)
+	store.Put(ctx, "later", []byte("1"), 0)
+	if len(store.entries) != 2 {
+		t.Fatalf("Expected the expired entries to be swept, got %d entries", len(store.entries))
+	}
+}
+
+func TestFileStoreLongKeys(t *testing.T) {
+	ctx := context.Background()
+	stores, cleanup := newTestStores(t)
+	defer cleanup()
+	store := stores["file"]
+
+	long := "prefix/" + strings.Repeat("k", 300)
+	if err := store.Put(ctx, long, []byte("1"), 0); err != nil {
+		t.Fatalf("Expected a long key to be stored, got %v", err)
+	}
+	store.Put(ctx, "prefix/short", []byte("2"), 0)
+
+	for _, file := range mustReadDir(t, store.(*fileStore).dir) {
+		if len(file) > 255 {
+			t.Fatalf("Expected file names of at most 255 bytes, got %d", len(file))
+		}
+	}
+
+	value, err := store.Get(ctx, long)
+	if err != nil || string(value) != "1" {
+		t.Fatalf("Expected to get the long key, got %q, %v", value, err)
+	}
+
+	keys, err := store.List(ctx, "prefix/")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := []string{long, "prefix/short"}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+
+	if _, err := store.Take(ctx, long); err != nil {
+		t.Fatalf("Expected to take the long key, got %v", err)
+	}
+	if keys, _ := store.List(ctx, "prefix/"); len(keys) != 1 {
+		t.Fatalf("Expected the long key to be gone, got %v", keys)
+	}
+}
+
+func mustReadDir(t *testing.T, dir string) []string {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("Failed to read directory: %v", err)
+	}
+
+	names := make([]string, len(files))
+	for i, file := range files {
+		names[i] = file.Name()
+	}
+	return names
+}
+
+func TestRedisStoreListDeduplicatesScanResults(t *testing.T) {
+	var calls [][]interface{}
+	store := NewRedisStore(RedisDoerFunc(func(ctx context.Context, args ...interface{}) (interface{}, error) {
+		calls = append(calls, args)
+		if args[1] == "0" {
+			return []interface{}{[]byte("7"), []interface{}{[]byte("app:b"), []byte("app:a")}}, nil
+		}
+		return []interface{}{[]byte("0"), []interface{}{[]byte("app:a"), []byte("app:c")}}, nil
+	}), "app:")
+
+	keys, err := store.List(context.Background(), "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("Expected %v, got %v", expected, keys)
+	}
+	if len(calls) != 2 || calls[0][3] != "app:*" {
+		t.Fatalf("Expected two scans matching the key prefix, got %v", calls)
+	}
+}
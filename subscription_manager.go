@@ -0,0 +1,692 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// ErrSubscriptionManagerClosed is returned when subscribing through a SubscriptionManager that has
+// been closed.
+var ErrSubscriptionManagerClosed = errors.New("Subscription manager is closed")
+
+// SubscriptionManagerOptions contains parameters to configure a SubscriptionManager.
+type SubscriptionManagerOptions struct {
+	// Number of events buffered for each consumer. Defaults to 100.
+	BufferSize int
+	// Maximum number of subscriptions open at once, however many consumers share them.
+	// Subscribing to something else while the limit is reached waits until one of them is closed,
+	// or the consumer's context is done. Defaults to no limit.
+	MaxSubscriptions int
+	// What to do with events received while a consumer's buffer is full. Defaults to
+	// OverflowBlock, in which case a slow consumer holds up those sharing its subscription.
+	Overflow OverflowPolicy
+	// Delay before reconnecting after a subscription fails, doubled for each failed attempt up to
+	// 30s. Defaults to 1s.
+	ReconnectDelay time.Duration
+	// Optional function called when a subscription has been opened.
+	OnConnected func(subscription string)
+	// Optional function called with the errors of a subscription, as for
+	// SubscriptionOptions.OnError.
+	OnError func(subscription string, err error)
+	// Optional function called when a subscription has been resumed after failing.
+	OnResume func(subscription string)
+}
+
+// SubscriptionManager manages the subscriptions of an application. Consumers of the same room or
+// user share a single subscription, which is kept open while any of them is subscribed, and the
+// number of subscriptions open at once can be bounded with MaxSubscriptions. Failed
+// subscriptions are resumed with exponential backoff, and those refused as unauthorized, e.g.
+// because their token expired, are reopened once with a new token.
+//
+// Subscriptions are named in hooks after what they are subscribed to, e.g.
+// "rooms/{roomID}/messages", "rooms/{roomID}/memberships", "users/{userID}",
+// "users/{userID}/presence" or "users/{userID}/rooms/{roomID}". Consumers receive
+// the events received after they subscribed, until their context is done or the subscription
+// fails with an error that reconnecting can't recover from, when their channel is closed.
+type SubscriptionManager struct {
+	client  *Client
+	options SubscriptionManagerOptions
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	// Holds a value for each open subscription, if their number is bounded.
+	slots chan struct{}
+
+	mu            sync.Mutex
+	subscriptions map[string]*managedSubscription
+	closed        bool
+	running       sync.WaitGroup
+}
+
+type managedSubscription struct {
+	cancel context.CancelFunc
+	opened chan struct{} // Closed once the subscription has been opened, or failed to
+	err    error         // Error opening the subscription, set before opened is closed
+	done   chan struct{} // Closed once the subscription has ended
+
+	mu        sync.Mutex
+	consumers map[*subscriptionConsumer]bool
+}
+
+// subscriptionSource receives the next event of a subscription, and reports false once it has
+// ended.
+type subscriptionSource func() (interface{}, bool)
+
+type subscriptionConsumer struct {
+	ctx context.Context
+	out eventChannel // Channel the consumer receives events on
+
+	mu     sync.Mutex // Held while an event is delivered, so that out isn't closed meanwhile
+	closed bool
+}
+
+// send delivers an event to the consumer, unless its channel has been closed. It returns false
+// if the consumer must be unsubscribed, as its context is done or it fell behind.
+func (c *subscriptionConsumer) send(event interface{}, options SubscriptionOptions) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.closed || deliver(c.ctx, c.out, event, options)
+}
+
+// close closes the consumer's channel, once the event being delivered to it, if any, has been.
+func (c *subscriptionConsumer) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.closed {
+		c.closed = true
+		c.out.close()
+	}
+}
+
+// NewSubscriptionManager returns a SubscriptionManager opening subscriptions with the client.
+func (c *Client) NewSubscriptionManager(options SubscriptionManagerOptions) *SubscriptionManager {
+	if options.BufferSize <= 0 {
+		options.BufferSize = defaultSubscriptionBufferSize
+	}
+
+	if options.ReconnectDelay <= 0 {
+		options.ReconnectDelay = defaultSubscriptionReconnectDelay
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	manager := &SubscriptionManager{
+		client:        c,
+		options:       options,
+		ctx:           ctx,
+		cancel:        cancel,
+		subscriptions: map[string]*managedSubscription{},
+	}
+	if options.MaxSubscriptions > 0 {
+		manager.slots = make(chan struct{}, options.MaxSubscriptions)
+	}
+
+	return manager
+}
+
+// SubscribeToRoomMessages subscribes to the messages sent to a room, as Client.SubscribeToRoomMessages
+// does, until ctx is done.
+func (m *SubscriptionManager) SubscribeToRoomMessages(ctx context.Context, roomID string) (<-chan MultipartMessage, error) {
+	messages := make(chan MultipartMessage, m.options.BufferSize)
+
+	err := m.subscribe(
+		ctx,
+		fmt.Sprintf("rooms/%s/messages", roomID),
+		multipartMessageChannel(messages),
+		func(ctx context.Context, options SubscriptionOptions) (subscriptionSource, error) {
+			source, err := m.client.SubscribeToRoomMessages(ctx, roomID, SubscribeToRoomMessagesOptions{
+				SubscriptionOptions: options,
+			})
+			return func() (interface{}, bool) {
+				message, ok := <-source
+				return message, ok
+			}, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// SubscribeToRoomMemberships subscribes to the users joining and leaving a room, as
+// Client.SubscribeToRoomMemberships does, until ctx is done.
+func (m *SubscriptionManager) SubscribeToRoomMemberships(ctx context.Context, roomID string) (<-chan MembershipEvent, error) {
+	events := make(chan MembershipEvent, m.options.BufferSize)
+
+	err := m.subscribe(
+		ctx,
+		fmt.Sprintf("rooms/%s/memberships", roomID),
+		membershipEventChannel(events),
+		func(ctx context.Context, options SubscriptionOptions) (subscriptionSource, error) {
+			source, err := m.client.SubscribeToRoomMemberships(ctx, roomID, options)
+			return func() (interface{}, bool) {
+				event, ok := <-source
+				return event, ok
+			}, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// SubscribeToUserEvents subscribes to the changes to the rooms a user is a member of, as
+// Client.SubscribeToUserEvents does, until ctx is done.
+func (m *SubscriptionManager) SubscribeToUserEvents(ctx context.Context, userID string) (<-chan UserSubscriptionEvent, error) {
+	events := make(chan UserSubscriptionEvent, m.options.BufferSize)
+
+	err := m.subscribe(
+		ctx,
+		fmt.Sprintf("users/%s", userID),
+		userSubscriptionEventChannel(events),
+		func(ctx context.Context, options SubscriptionOptions) (subscriptionSource, error) {
+			source, err := m.client.SubscribeToUserEvents(ctx, userID, options)
+			return func() (interface{}, bool) {
+				event, ok := <-source
+				return event, ok
+			}, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// SubscribeToUserPresence subscribes to the presence of a user, as Client.SubscribeToPresence
+// does, until ctx is done. Consumers subscribing to a user whose presence is already subscribed
+// to receive their next transition between online and offline.
+func (m *SubscriptionManager) SubscribeToUserPresence(ctx context.Context, userID string) (<-chan UserPresence, error) {
+	presences := make(chan UserPresence, m.options.BufferSize)
+
+	if err := m.subscribeToUserPresence(ctx, userID, userPresenceChannel(presences)); err != nil {
+		return nil, err
+	}
+
+	return presences, nil
+}
+
+// SubscribeToPresence subscribes to the presence of several users, as Client.SubscribeToPresence
+// does, until ctx is done. Each user's subscription is shared with the other consumers of their
+// presence, as for SubscribeToUserPresence, and those that aren't open yet are opened
+// concurrently, with the client's batch concurrency. The returned channel is closed once the
+// subscriptions of every user have ended.
+func (m *SubscriptionManager) SubscribeToPresence(ctx context.Context, userIDs []string) (<-chan UserPresence, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.New("You must provide the IDs of the users to subscribe to")
+	}
+
+	userIDs = uniqueStrings(userIDs)
+
+	consumersCtx, cancel := context.WithCancel(ctx)
+	presences := make(chan UserPresence, m.options.BufferSize)
+
+	// The channel is shared by the consumers of every user, and closed with the last of them.
+	out := userPresenceChannel(presences)
+	remaining := int32(len(userIDs))
+	shared := out
+	shared.close = func() {
+		if atomic.AddInt32(&remaining, -1) == 0 {
+			cancel()
+			out.close()
+		}
+	}
+
+	err := forEachConcurrentlyOnce(ctx, userIDs, m.client.concurrencyFor(0), func(_ context.Context, userID string) error {
+		return m.subscribeToUserPresence(consumersCtx, userID, shared)
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return presences, nil
+}
+
+func (m *SubscriptionManager) subscribeToUserPresence(ctx context.Context, userID string, out eventChannel) error {
+	return m.subscribe(
+		ctx,
+		fmt.Sprintf("users/%s/presence", userID),
+		out,
+		func(ctx context.Context, options SubscriptionOptions) (subscriptionSource, error) {
+			source, err := m.client.SubscribeToPresence(ctx, []string{userID}, options)
+			return func() (interface{}, bool) {
+				presence, ok := <-source
+				return presence, ok
+			}, err
+		},
+	)
+}
+
+// States of a user's subscription to a room, reported by RoomEvents.
+const (
+	// The user is a member of the room, and its messages are received.
+	RoomSubscriptionJoined = "joined"
+	// The user was removed from the room, or the room was deleted, so its messages aren't
+	// received until the user is added to it again.
+	RoomSubscriptionRemoved = "removed"
+)
+
+// RoomEvent is an event of a user's subscription to a room, see SubscribeToRoomAsUser: either a
+// message sent to the room, or a change of the state of the subscription.
+type RoomEvent struct {
+	RoomID  string
+	Message *MultipartMessage // The message sent, for messages
+	State   string            // The new state of the subscription, for state changes
+}
+
+// SubscribeToRoomAsUser subscribes to the messages sent to a room on behalf of a member of it,
+// until ctx is done. The subscription follows the user's membership of the room: it receives a
+// RoomSubscriptionJoined event once the user is a member and the room is subscribed to, and a
+// RoomSubscriptionRemoved event when the user is removed from the room, the room is deleted, or
+// its subscription is ended as the user no longer has access to it. When the user is added to the
+// room again, it is subscribed to again, after the last message received, and another
+// RoomSubscriptionJoined event is received. Membership changes are detected from the user's
+// events, see Client.SubscribeToUserEvents, which are subscribed to alongside the room.
+func (m *SubscriptionManager) SubscribeToRoomAsUser(ctx context.Context, userID string, roomID string) (<-chan RoomEvent, error) {
+	if userID == "" {
+		return nil, errors.New("You must provide the ID of the user to subscribe as")
+	}
+
+	if roomID == "" {
+		return nil, errors.New("You must provide the ID of the room to subscribe to")
+	}
+
+	events := make(chan RoomEvent, m.options.BufferSize)
+
+	err := m.subscribe(
+		ctx,
+		fmt.Sprintf("users/%s/rooms/%s", userID, roomID),
+		roomEventChannel(events),
+		func(ctx context.Context, options SubscriptionOptions) (subscriptionSource, error) {
+			source, err := m.client.followRoomMembership(ctx, userID, roomID, options)
+			return func() (interface{}, bool) {
+				event, ok := <-source
+				return event, ok
+			}, err
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// followRoomMembership subscribes to the messages of a room as a user while they are a member of
+// it, re-subscribing when they are added to it again, as SubscribeToRoomAsUser describes.
+func (c *Client) followRoomMembership(
+	ctx context.Context,
+	userID string,
+	roomID string,
+	options SubscriptionOptions,
+) (<-chan RoomEvent, error) {
+	options = c.countingDrops(options.withDefaults())
+
+	userEvents, err := c.SubscribeToUserEvents(ctx, userID, options)
+	if err != nil {
+		return nil, err
+	}
+
+	subscribeAsUser := func(ctx context.Context, requestOptions platformclient.RequestOptions) (*common.SubscriptionStream, error) {
+		return c.coreServiceV6.SubscribeAsUser(ctx, userID, requestOptions)
+	}
+
+	events := make(chan RoomEvent, options.BufferSize)
+	out := roomEventChannel(events)
+
+	go func() {
+		defer close(events)
+
+		var (
+			messages      <-chan MultipartMessage // Nil while the room isn't subscribed to
+			cancelRoom    = func() {}
+			lastMessageID uint
+		)
+		defer func() { cancelRoom() }()
+
+		send := func(event RoomEvent) bool {
+			return deliver(ctx, out, event, options)
+		}
+
+		join := func() bool {
+			roomCtx, cancel := context.WithCancel(ctx)
+			roomMessages, err := subscribeToRoomMessages(roomCtx, subscribeAsUser, roomID, SubscribeToRoomMessagesOptions{
+				AfterMessageID:      lastMessageID,
+				SubscriptionOptions: options,
+			})
+			if err != nil {
+				// The room is subscribed to again if the user is added to it again.
+				cancel()
+				options.reportError(err)
+				return true
+			}
+
+			messages, cancelRoom = roomMessages, cancel
+			return send(RoomEvent{RoomID: roomID, State: RoomSubscriptionJoined})
+		}
+
+		leave := func() bool {
+			cancelRoom()
+			messages, cancelRoom = nil, func() {}
+			return send(RoomEvent{RoomID: roomID, State: RoomSubscriptionRemoved})
+		}
+
+		for {
+			select {
+			case event, ok := <-userEvents:
+				if !ok {
+					return
+				}
+				if event.RoomID != roomID {
+					continue
+				}
+
+				switch event.Type {
+				case UserSubscriptionEventAddedToRoom, UserSubscriptionEventRoomUpdated:
+					if messages == nil && !join() {
+						return
+					}
+				case UserSubscriptionEventRemovedFromRoom, UserSubscriptionEventRoomDeleted:
+					if messages != nil && !leave() {
+						return
+					}
+				}
+			case message, ok := <-messages:
+				if !ok {
+					// The subscription ended with an error reconnecting can't recover from, such
+					// as the user no longer having access to the room.
+					if ctx.Err() != nil || !leave() {
+						return
+					}
+					continue
+				}
+
+				lastMessageID = message.ID
+				if !send(RoomEvent{RoomID: roomID, Message: &message}) {
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+// Close closes every subscription, and waits for their channels to be closed.
+// Subscribing afterwards fails with ErrSubscriptionManagerClosed.
+func (m *SubscriptionManager) Close() error {
+	m.mu.Lock()
+	m.closed = true
+	m.cancel()
+	m.mu.Unlock()
+
+	m.running.Wait()
+	return nil
+}
+
+// subscribe adds a consumer receiving events on out to the subscription of the given name,
+// opening it with open if it isn't already. The subscription is opened without holding the
+// manager's lock, and consumers subscribing meanwhile wait for it to be opened.
+func (m *SubscriptionManager) subscribe(
+	ctx context.Context,
+	name string,
+	out eventChannel,
+	open func(ctx context.Context, options SubscriptionOptions) (subscriptionSource, error),
+) error {
+	for {
+		subscription, err := m.subscription(ctx, name, open)
+		if err != nil {
+			return err
+		}
+
+		consumer := &subscriptionConsumer{ctx: ctx, out: out}
+
+		subscription.mu.Lock()
+		ended := subscription.consumers == nil
+		if !ended {
+			subscription.consumers[consumer] = true
+		}
+		subscription.mu.Unlock()
+
+		// The subscription ended before the consumer could be added, so it is opened again.
+		if ended {
+			continue
+		}
+
+		m.running.Add(1)
+		go func() {
+			defer m.running.Done()
+
+			select {
+			case <-ctx.Done():
+				m.unsubscribe(name, subscription, consumer)
+			case <-subscription.done:
+			}
+		}()
+
+		return nil
+	}
+}
+
+// subscription returns the subscription of the given name, once it has been opened, opening it
+// with open if it isn't already. If the number of subscriptions is bounded it waits for a slot
+// to open it in first.
+func (m *SubscriptionManager) subscription(
+	ctx context.Context,
+	name string,
+	open func(ctx context.Context, options SubscriptionOptions) (subscriptionSource, error),
+) (*managedSubscription, error) {
+	for {
+		m.mu.Lock()
+		if m.closed {
+			m.mu.Unlock()
+			return nil, ErrSubscriptionManagerClosed
+		}
+
+		subscription, ok := m.subscriptions[name]
+		m.mu.Unlock()
+
+		if ok {
+			select {
+			case <-subscription.opened:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+
+			if subscription.err != nil {
+				return nil, subscription.err
+			}
+			return subscription, nil
+		}
+
+		if err := m.acquireSlot(ctx); err != nil {
+			return nil, err
+		}
+
+		m.mu.Lock()
+		if m.closed {
+			m.mu.Unlock()
+			m.releaseSlot()
+			return nil, ErrSubscriptionManagerClosed
+		}
+
+		// Another consumer may have started opening it while waiting for the slot.
+		if _, ok := m.subscriptions[name]; ok {
+			m.mu.Unlock()
+			m.releaseSlot()
+			continue
+		}
+
+		subscriptionCtx, cancel := context.WithCancel(m.ctx)
+		subscription = &managedSubscription{
+			cancel:    cancel,
+			opened:    make(chan struct{}),
+			done:      make(chan struct{}),
+			consumers: map[*subscriptionConsumer]bool{},
+		}
+		m.subscriptions[name] = subscription
+		m.running.Add(1)
+		m.mu.Unlock()
+
+		source, err := open(subscriptionCtx, m.subscriptionOptions(name))
+		if err != nil {
+			cancel()
+			m.releaseSlot()
+			m.running.Done()
+
+			m.mu.Lock()
+			if m.subscriptions[name] == subscription {
+				delete(m.subscriptions, name)
+			}
+			m.mu.Unlock()
+
+			subscription.err = err
+			close(subscription.opened)
+			return nil, err
+		}
+
+		if m.options.OnConnected != nil {
+			m.options.OnConnected(name)
+		}
+
+		go m.forward(name, subscription, source)
+		close(subscription.opened)
+		return subscription, nil
+	}
+}
+
+// acquireSlot waits for a subscription to be allowed to open, if their number is bounded.
+func (m *SubscriptionManager) acquireSlot(ctx context.Context) error {
+	if m.slots == nil {
+		return nil
+	}
+
+	select {
+	case m.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-m.ctx.Done():
+		return ErrSubscriptionManagerClosed
+	}
+}
+
+// releaseSlot makes room for another subscription to open, once one has ended.
+func (m *SubscriptionManager) releaseSlot() {
+	if m.slots != nil {
+		<-m.slots
+	}
+}
+
+// subscriptionOptions returns the options a subscription is opened with. Its events are buffered
+// without dropping any, as the overflow policy is applied to each consumer instead.
+func (m *SubscriptionManager) subscriptionOptions(name string) SubscriptionOptions {
+	return SubscriptionOptions{
+		BufferSize:     m.options.BufferSize,
+		Overflow:       OverflowBlock,
+		ReconnectDelay: m.options.ReconnectDelay,
+		OnError: func(err error) {
+			if m.options.OnError != nil {
+				m.options.OnError(name, err)
+			}
+		},
+		onResume: func() {
+			if m.options.OnResume != nil {
+				m.options.OnResume(name)
+			}
+		},
+		recoverUnauthorized: true,
+	}
+}
+
+// forward sends the events received from source to the consumers of a subscription, and closes
+// their channels once it has ended. Events are delivered without holding the subscription's
+// lock, so that consumers can come and go while one of them is slow to receive. Consumers that
+// fall behind with OverflowError are unsubscribed.
+func (m *SubscriptionManager) forward(name string, subscription *managedSubscription, source subscriptionSource) {
+	defer m.running.Done()
+	defer m.releaseSlot()
+
+	consumerOptions := m.client.countingDrops(m.subscriptionOptions(name))
+	consumerOptions.Overflow = m.options.Overflow
+
+	for {
+		event, ok := source()
+		if !ok {
+			break
+		}
+
+		subscription.mu.Lock()
+		consumers := make([]*subscriptionConsumer, 0, len(subscription.consumers))
+		for consumer := range subscription.consumers {
+			consumers = append(consumers, consumer)
+		}
+		subscription.mu.Unlock()
+
+		for _, consumer := range consumers {
+			if !consumer.send(event, consumerOptions) {
+				m.unsubscribe(name, subscription, consumer)
+			}
+		}
+	}
+
+	m.mu.Lock()
+	if m.subscriptions[name] == subscription {
+		delete(m.subscriptions, name)
+	}
+	m.mu.Unlock()
+
+	subscription.mu.Lock()
+	consumers := subscription.consumers
+	subscription.consumers = nil
+	close(subscription.done)
+	subscription.mu.Unlock()
+
+	for consumer := range consumers {
+		consumer.close()
+	}
+}
+
+// unsubscribe removes a consumer from a subscription, which is closed if it was its last one.
+func (m *SubscriptionManager) unsubscribe(
+	name string,
+	subscription *managedSubscription,
+	consumer *subscriptionConsumer,
+) {
+	m.mu.Lock()
+	subscription.mu.Lock()
+
+	removed := subscription.consumers[consumer]
+	delete(subscription.consumers, consumer)
+
+	if removed && len(subscription.consumers) == 0 {
+		subscription.cancel()
+		if m.subscriptions[name] == subscription {
+			delete(m.subscriptions, name)
+		}
+	}
+
+	subscription.mu.Unlock()
+	m.mu.Unlock()
+
+	if removed {
+		consumer.close()
+	}
+}
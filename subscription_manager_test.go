@@ -0,0 +1,395 @@
+package chatkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSubscriptionManagerMaxSubscriptions(t *testing.T) {
+	client, server := newStubServer(t, holdSubscription)
+	defer server.Close()
+
+	manager := client.NewSubscriptionManager(SubscriptionManagerOptions{MaxSubscriptions: 1})
+	defer manager.Close()
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	first, err := manager.SubscribeToRoomMessages(firstCtx, "room-1")
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// Consumers of the same room share its subscription, so aren't held up by the limit.
+	if _, err := manager.SubscribeToRoomMessages(firstCtx, "room-1"); err != nil {
+		t.Fatalf("Failed to subscribe to a room already subscribed to: %v", err)
+	}
+
+	waitCtx, cancelWait := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelWait()
+	if _, err := manager.SubscribeToRoomMessages(waitCtx, "room-2"); err != context.DeadlineExceeded {
+		t.Fatalf("Expected subscribing beyond the limit to wait, got %v", err)
+	}
+
+	cancelFirst()
+	for range first {
+	}
+
+	secondCtx, cancelSecond := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelSecond()
+	if _, err := manager.SubscribeToRoomMessages(secondCtx, "room-2"); err != nil {
+		t.Fatalf("Expected to subscribe once the first subscription closed, got %v", err)
+	}
+}
+
+func TestSubscriptionManagerOpensWithoutLocking(t *testing.T) {
+	release := make(chan struct{})
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/rooms/slow") {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		holdSubscription(w, r)
+	})
+	defer server.Close()
+	defer close(release)
+
+	manager := client.NewSubscriptionManager(SubscriptionManagerOptions{})
+	defer manager.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	slow := make(chan error, 1)
+	go func() {
+		_, err := manager.SubscribeToRoomMessages(ctx, "slow")
+		slow <- err
+	}()
+
+	fast := make(chan error, 1)
+	go func() {
+		_, err := manager.SubscribeToRoomMessages(ctx, "fast")
+		fast <- err
+	}()
+
+	select {
+	case err := <-fast:
+		if err != nil {
+			t.Fatalf("Failed to subscribe: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribing was held up by another subscription being opened")
+	}
+
+	select {
+	case err := <-slow:
+		t.Fatalf("Expected the slow subscription to still be opening, got %v", err)
+	default:
+	}
+}
+
+func TestSubscriptionManagerFollowsRoomMembership(t *testing.T) {
+	userEvents := make(chan func(w http.ResponseWriter), 10)
+	roomSubscriptions := make(chan *http.Request, 10)
+	roomEvents := make(chan func(w http.ResponseWriter), 10)
+
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+
+		events := userEvents
+		if strings.HasSuffix(r.URL.Path, "/rooms/room-1") {
+			roomSubscriptions <- r
+			events = roomEvents
+		}
+
+		for {
+			select {
+			case write := <-events:
+				write(w)
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+	defer server.Close()
+
+	manager := client.NewSubscriptionManager(SubscriptionManagerOptions{})
+	defer manager.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := manager.SubscribeToRoomAsUser(ctx, "alice", "room-1")
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	next := func() RoomEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+			return RoomEvent{}
+		}
+	}
+
+	room := map[string]interface{}{"id": "room-1", "name": "Room"}
+	userEvents <- func(w http.ResponseWriter) {
+		writeSubscriptionEvent(w, "1", "initial_state", map[string]interface{}{
+			"rooms": []interface{}{room},
+		})
+	}
+	if event := next(); event.State != RoomSubscriptionJoined {
+		t.Fatalf("Expected the subscription to join the room, got %+v", event)
+	}
+
+	message := map[string]interface{}{
+		"id":      7,
+		"user_id": "bob",
+		"room_id": "room-1",
+		"parts":   []interface{}{map[string]interface{}{"type": "text/plain", "content": "hi"}},
+	}
+	roomEvents <- func(w http.ResponseWriter) {
+		writeSubscriptionEvent(w, "1", "new_multipart_message", message)
+	}
+	if event := next(); event.Message == nil || event.Message.ID != 7 {
+		t.Fatalf("Expected message 7, got %+v", event)
+	}
+
+	userEvents <- func(w http.ResponseWriter) {
+		writeSubscriptionEvent(w, "2", "removed_from_room", map[string]interface{}{"room_id": "room-1"})
+	}
+	if event := next(); event.State != RoomSubscriptionRemoved {
+		t.Fatalf("Expected the user to be removed from the room, got %+v", event)
+	}
+
+	userEvents <- func(w http.ResponseWriter) {
+		writeSubscriptionEvent(w, "3", "added_to_room", map[string]interface{}{"room": room})
+	}
+	if event := next(); event.State != RoomSubscriptionJoined {
+		t.Fatalf("Expected the subscription to join the room again, got %+v", event)
+	}
+
+	<-roomSubscriptions
+	resubscription := <-roomSubscriptions
+	if limit := resubscription.URL.Query().Get("message_limit"); limit == "0" {
+		t.Error("Expected the room to be subscribed to again after the last message received")
+	}
+}
+
+func TestSubscriptionManagerSlowConsumer(t *testing.T) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		if !strings.HasSuffix(r.URL.Path, "/rooms/room-1") {
+			<-r.Context().Done()
+			return
+		}
+
+		for id := 1; ; id++ {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(time.Millisecond):
+				writeSubscriptionEvent(w, fmt.Sprint(id), "new_multipart_message", map[string]interface{}{
+					"id":      id,
+					"user_id": "bob",
+					"room_id": "room-1",
+				})
+			}
+		}
+	})
+	defer server.Close()
+
+	manager := client.NewSubscriptionManager(SubscriptionManagerOptions{BufferSize: 1})
+
+	// The slow consumer never receives, so delivering to it blocks once its buffer is full.
+	slowCtx, cancelSlow := context.WithCancel(context.Background())
+	defer cancelSlow()
+	if _, err := manager.SubscribeToRoomMessages(slowCtx, "room-1"); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	otherCtx, cancelOther := context.WithCancel(context.Background())
+	other, err := manager.SubscribeToRoomMessages(otherCtx, "room-1")
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	<-other
+	cancelOther()
+
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		_, err := manager.SubscribeToRoomMessages(ctx, "room-2")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Failed to subscribe to another room: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Subscribing to another room was held up by a slow consumer")
+	}
+
+	for range other {
+	}
+
+	cancelSlow()
+	manager.Close()
+}
+
+// presenceStub serves presence subscriptions, holding each open once it has sent the user's
+// state, and counts the subscriptions opened for each user.
+type presenceStub struct {
+	mu      sync.Mutex
+	opened  map[string]int
+	arrived chan struct{} // Receives a value for each subscription request
+	release chan struct{} // Closed to let the subscriptions respond
+}
+
+func newPresenceStub() *presenceStub {
+	return &presenceStub{
+		opened:  map[string]int{},
+		arrived: make(chan struct{}, 100),
+		release: make(chan struct{}),
+	}
+}
+
+func (s *presenceStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	s.mu.Lock()
+	s.opened[userID]++
+	s.mu.Unlock()
+
+	s.arrived <- struct{}{}
+	select {
+	case <-s.release:
+	case <-r.Context().Done():
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	writeSubscriptionEvent(w, "1", eventNamePresenceState, map[string]interface{}{"state": "online"})
+	<-r.Context().Done()
+}
+
+func (s *presenceStub) openedFor(userID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.opened[userID]
+}
+
+// waitForRequests waits until n subscription requests are being served at once.
+func (s *presenceStub) waitForRequests(t *testing.T, n int) {
+	for i := 0; i < n; i++ {
+		select {
+		case <-s.arrived:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d subscriptions to be opened at once, got %d", n, i)
+		}
+	}
+	close(s.release)
+}
+
+// receivePresences receives n presences from presences, keyed by user ID.
+func receivePresences(t *testing.T, presences <-chan UserPresence, n int) map[string]string {
+	states := map[string]string{}
+	for len(states) < n {
+		select {
+		case presence := <-presences:
+			states[presence.UserID] = presence.State
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Expected %d presences, got %v", n, states)
+		}
+	}
+	return states
+}
+
+func TestSubscribeToPresenceOpensSubscriptionsConcurrently(t *testing.T) {
+	stub := newPresenceStub()
+	client, server := newStubServer(t, stub.ServeHTTP)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscribed := make(chan error, 1)
+	var presences <-chan UserPresence
+	go func() {
+		var err error
+		presences, err = client.SubscribeToPresence(ctx, []string{"alice", "bob", "carol"}, SubscriptionOptions{})
+		subscribed <- err
+	}()
+
+	stub.waitForRequests(t, 3)
+	if err := <-subscribed; err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	states := receivePresences(t, presences, 3)
+	for _, userID := range []string{"alice", "bob", "carol"} {
+		if states[userID] != "online" {
+			t.Errorf("Expected %s to be online, got %q", userID, states[userID])
+		}
+	}
+}
+
+func TestSubscriptionManagerSharesPresenceSubscriptions(t *testing.T) {
+	stub := newPresenceStub()
+	client, server := newStubServer(t, stub.ServeHTTP)
+	defer server.Close()
+
+	manager := client.NewSubscriptionManager(SubscriptionManagerOptions{})
+	defer manager.Close()
+
+	firstCtx, cancelFirst := context.WithCancel(context.Background())
+	defer cancelFirst()
+
+	subscribed := make(chan error, 1)
+	var first <-chan UserPresence
+	go func() {
+		var err error
+		first, err = manager.SubscribeToPresence(firstCtx, []string{"alice", "bob"})
+		subscribed <- err
+	}()
+
+	stub.waitForRequests(t, 2)
+	if err := <-subscribed; err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	receivePresences(t, first, 2)
+
+	secondCtx, cancelSecond := context.WithCancel(context.Background())
+	if _, err := manager.SubscribeToUserPresence(secondCtx, "alice"); err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	if opened := stub.openedFor("alice"); opened != 1 {
+		t.Fatalf("Expected the subscription to alice to be shared, got %d subscriptions", opened)
+	}
+
+	cancelFirst()
+	select {
+	case _, ok := <-first:
+		if ok {
+			t.Fatal("Expected no more presences")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected the channel to be closed once every subscription ended")
+	}
+	cancelSecond()
+}
@@ -0,0 +1,992 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	"github.com/pusher/chatkit-server-go/internal/core"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// lastEventIDHeader is the header a subscription is resumed from an event with.
+const lastEventIDHeader = "Last-Event-ID"
+
+const (
+	defaultSubscriptionBufferSize     = 100
+	defaultSubscriptionReconnectDelay = time.Second
+	maxSubscriptionReconnectDelay     = 30 * time.Second
+
+	// Most recent messages of a room sent when subscribing after a message.
+	maxRoomSubscriptionMessageLimit = 100
+)
+
+// Names of the events of subscriptions.
+const (
+	eventNameInitialState        = "initial_state"
+	eventNameNewMultipartMessage = "new_multipart_message"
+	eventNameAddedToRoom         = "added_to_room"
+	eventNameRemovedFromRoom     = "removed_from_room"
+	eventNameRoomUpdated         = "room_updated"
+	eventNameRoomDeleted         = "room_deleted"
+	eventNameUserJoined          = "user_joined"
+	eventNameUserLeft            = "user_left"
+	eventNamePresenceState       = "presence_state"
+)
+
+// Types of the events of user subscriptions.
+const (
+	UserSubscriptionEventAddedToRoom     = "added_to_room"
+	UserSubscriptionEventRemovedFromRoom = "removed_from_room"
+	UserSubscriptionEventRoomUpdated     = "room_updated"
+	UserSubscriptionEventRoomDeleted     = "room_deleted"
+)
+
+// Types of the events of room membership subscriptions.
+const (
+	MembershipEventUserJoined = "user_joined"
+	MembershipEventUserLeft   = "user_left"
+)
+
+// ErrSubscriptionOverflow is reported to OnError when events are dropped, or the subscription is
+// ended, because the consumer of a subscription has fallen behind.
+var ErrSubscriptionOverflow = errors.New("Subscription buffer is full")
+
+// OverflowPolicy decides what a subscription does with new events when its buffer is full.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock stops reading events until the consumer catches up, so that none are lost.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest buffered event to make room for the new one.
+	OverflowDropOldest
+	// OverflowDropNewest discards the events received while the buffer is full.
+	OverflowDropNewest
+	// OverflowError ends the subscription, closing its channel, for consumers that can't miss
+	// events but shouldn't hold up the subscription either. Such consumers can subscribe again,
+	// e.g. after the last message they received.
+	OverflowError
+)
+
+// SubscriptionOptions contains parameters to configure how a subscription buffers events and
+// reconnects.
+type SubscriptionOptions struct {
+	// Number of events buffered for the consumer. Defaults to 100.
+	BufferSize int
+	// What to do with events received while the buffer is full. Defaults to OverflowBlock.
+	Overflow OverflowPolicy
+	// Delay before reconnecting after the subscription fails, doubled for each failed attempt up
+	// to 30s. Defaults to 1s.
+	ReconnectDelay time.Duration
+	// Optional function called with the errors the subscription recovers from by reconnecting,
+	// with ErrSubscriptionOverflow when events are dropped, and with the error that ends the
+	// subscription, if any.
+	OnError func(err error)
+
+	// Set by SubscriptionManager: called when the subscription is resumed, and whether to
+	// reconnect once, with a new token, when the subscription is refused as unauthorized.
+	onResume            func()
+	recoverUnauthorized bool
+	// Counter of the events dropped, updated atomically. Set by the client to its stats.
+	dropped *uint64
+}
+
+func (o SubscriptionOptions) withDefaults() SubscriptionOptions {
+	if o.BufferSize <= 0 {
+		o.BufferSize = defaultSubscriptionBufferSize
+	}
+
+	if o.ReconnectDelay <= 0 {
+		o.ReconnectDelay = defaultSubscriptionReconnectDelay
+	}
+
+	return o
+}
+
+func (o SubscriptionOptions) reportError(err error) {
+	if o.OnError != nil {
+		o.OnError(err)
+	}
+}
+
+// reportOverflow counts an event dropped, or a subscription ended, because the consumer fell
+// behind, and reports it to OnError.
+func (o SubscriptionOptions) reportOverflow() {
+	if o.dropped != nil {
+		atomic.AddUint64(o.dropped, 1)
+	}
+	o.reportError(ErrSubscriptionOverflow)
+}
+
+// countingDrops returns options counting the events they drop in the client's stats.
+func (c *Client) countingDrops(options SubscriptionOptions) SubscriptionOptions {
+	options.dropped = &c.stats.eventsDropped
+	return options
+}
+
+// Event is an event received on a subscription, with its data left undecoded.
+type Event struct {
+	ID        string          `json:"-"`          // Used to resume a subscription after this event
+	Name      string          `json:"event_name"` // Type of the event, e.g. "new_message"
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// Decode decodes the data of the event into v, e.g. a MultipartMessage for new messages.
+func (e Event) Decode(v interface{}) error {
+	if err := json.Unmarshal(e.Data, v); err != nil {
+		return fmt.Errorf("Failed to decode %s event: %v", e.Name, err)
+	}
+
+	return nil
+}
+
+// RawEventStream iterates over the events of a subscription as they are received, with their
+// bodies left undecoded.
+//
+//	for stream.Next() {
+//		handle(stream.Event())
+//	}
+//	if err := stream.Err(); err != nil {
+//		return err
+//	}
+//
+// The subscription is closed once Next returns false, when its context is done, or by Close.
+// A subscription ended by the service stops with an *EndOfSubscriptionError.
+type RawEventStream struct {
+	ctx     context.Context
+	stream  *common.SubscriptionStream
+	current RawEvent
+	err     error
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newRawEventStream(ctx context.Context, stream *common.SubscriptionStream) *RawEventStream {
+	return &RawEventStream{ctx: ctx, stream: stream}
+}
+
+// Next blocks until the next event is received.
+// It returns false when the subscription has ended or an error occurred.
+func (s *RawEventStream) Next() bool {
+	if s.isClosed() || s.err != nil {
+		return false
+	}
+
+	event, err := s.stream.Next()
+	if err != nil {
+		if s.ctx.Err() != nil {
+			err = s.ctx.Err()
+		}
+		s.fail(err)
+		return false
+	}
+
+	s.current = event
+	return true
+}
+
+// Event returns the event most recently received.
+func (s *RawEventStream) Event() RawEvent {
+	return s.current
+}
+
+// Err returns the error, if any, that ended the subscription. A subscription ended by Close has
+// no error.
+func (s *RawEventStream) Err() error {
+	return s.err
+}
+
+// Close closes the subscription. It may be called while Next is blocked.
+func (s *RawEventStream) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	return s.stream.Close()
+}
+
+func (s *RawEventStream) isClosed() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.closed
+}
+
+// fail ends the subscription with err, unless it was ended by Close.
+func (s *RawEventStream) fail(err error) {
+	if !s.isClosed() {
+		s.err = err
+		s.Close()
+	}
+}
+
+// eventStream iterates over the events of a subscription as they are received, as
+// RawEventStream does, with their bodies decoded as Chatkit events.
+type eventStream struct {
+	raw     *RawEventStream
+	current Event
+}
+
+func newEventStream(ctx context.Context, stream *common.SubscriptionStream) *eventStream {
+	return &eventStream{raw: newRawEventStream(ctx, stream)}
+}
+
+// Next blocks until the next event is received.
+// It returns false when the subscription has ended or an error occurred.
+func (s *eventStream) Next() bool {
+	if !s.raw.Next() {
+		return false
+	}
+
+	var event Event
+	if err := json.Unmarshal(s.raw.Event().Body, &event); err != nil {
+		s.raw.fail(fmt.Errorf("Failed to decode event: %v", err))
+		return false
+	}
+	event.ID = s.raw.Event().ID
+
+	s.current = event
+	return true
+}
+
+// Event returns the event most recently received.
+func (s *eventStream) Event() Event {
+	return s.current
+}
+
+// Err returns the error, if any, that ended the subscription. A subscription ended by Close has
+// no error.
+func (s *eventStream) Err() error {
+	return s.raw.Err()
+}
+
+// Close closes the subscription. It may be called while Next is blocked.
+func (s *eventStream) Close() error {
+	return s.raw.Close()
+}
+
+// SubscribeToRoomMessagesOptions contains parameters to pass when subscribing to the messages of a
+// room.
+type SubscribeToRoomMessagesOptions struct {
+	// Optional ID of a message to start after. Only messages among the 100 most recent ones are
+	// sent again. By default only messages sent after subscribing are received.
+	AfterMessageID uint
+	SubscriptionOptions
+}
+
+// SubscribeToRoomMessages subscribes to the messages sent to a room, e.g. for a bot to respond to
+// them. If the subscription fails it is resumed after the last message received, so messages are
+// received once and in order. The returned channel is closed when ctx is done, or when the
+// subscription fails with an error that reconnecting can't recover from, which is reported to
+// OnError.
+func (c *Client) SubscribeToRoomMessages(
+	ctx context.Context,
+	roomID string,
+	options SubscribeToRoomMessagesOptions,
+) (<-chan MultipartMessage, error) {
+	if roomID == "" {
+		return nil, errors.New("You must provide the ID of the room to subscribe to")
+	}
+
+	options.SubscriptionOptions = c.countingDrops(options.SubscriptionOptions)
+	return subscribeToRoomMessages(ctx, c.coreServiceV6.Subscribe, roomID, options)
+}
+
+// subscribeToRoomMessages subscribes to the messages sent to a room, as SubscribeToRoomMessages
+// does, with subscribe.
+func subscribeToRoomMessages(
+	ctx context.Context,
+	subscribe func(context.Context, platformclient.RequestOptions) (*common.SubscriptionStream, error),
+	roomID string,
+	options SubscribeToRoomMessagesOptions,
+) (<-chan MultipartMessage, error) {
+	subscriptionOptions := options.SubscriptionOptions.withDefaults()
+
+	messageLimit := 0
+	if options.AfterMessageID != 0 {
+		messageLimit = maxRoomSubscriptionMessageLimit
+	}
+
+	open := resumableSubscription(ctx, subscribe, platformclient.RequestOptions{
+		Path:        fmt.Sprintf("/rooms/%s", url.PathEscape(roomID)),
+		QueryParams: &url.Values{"message_limit": []string{strconv.Itoa(messageLimit)}},
+	})
+
+	messages := make(chan MultipartMessage, subscriptionOptions.BufferSize)
+	out := multipartMessageChannel(messages)
+	lastMessageID := options.AfterMessageID
+
+	err := startSubscription(ctx, open, subscriptionOptions, func(event Event) bool {
+		if event.Name != eventNameNewMultipartMessage {
+			return true
+		}
+
+		message, err := core.DecodeMultipartMessage(event.Data)
+		if err != nil {
+			subscriptionOptions.reportError(fmt.Errorf("Failed to decode %s event: %v", event.Name, err))
+			return true
+		}
+
+		// Messages sent again when reconnecting have already been received.
+		if message.ID <= lastMessageID {
+			return true
+		}
+		lastMessageID = message.ID
+
+		return deliver(ctx, out, message, subscriptionOptions)
+	}, func() { close(messages) })
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+// UserSubscriptionEvent is a change to the rooms a user is a member of.
+type UserSubscriptionEvent struct {
+	Type      string // One of the UserSubscriptionEvent constants
+	RoomID    string
+	Timestamp time.Time
+	// The room as it is after the change. Only set for rooms added or updated.
+	Room *RoomWithoutMembers
+}
+
+// SubscribeToUserEvents subscribes to the changes to the rooms a user is a member of: rooms
+// they're added to or removed from, and rooms updated or deleted. The rooms the user is a member
+// of when subscribing are received first, as added. If the subscription fails, it is resumed and
+// the changes missed meanwhile are received. The returned channel is closed when ctx is done, or
+// when the subscription fails with an error that reconnecting can't recover from, which is
+// reported to OnError.
+func (c *Client) SubscribeToUserEvents(
+	ctx context.Context,
+	userID string,
+	options SubscriptionOptions,
+) (<-chan UserSubscriptionEvent, error) {
+	if userID == "" {
+		return nil, errors.New("You must provide the ID of the user to subscribe to")
+	}
+
+	options = c.countingDrops(options.withDefaults())
+
+	open := resumableSubscription(
+		ctx,
+		func(ctx context.Context, requestOptions platformclient.RequestOptions) (*common.SubscriptionStream, error) {
+			return c.coreServiceV6.SubscribeAsUser(ctx, userID, requestOptions)
+		},
+		platformclient.RequestOptions{Path: "/users"},
+	)
+
+	events := make(chan UserSubscriptionEvent, options.BufferSize)
+	out := userSubscriptionEventChannel(events)
+	rooms := map[string]RoomWithoutMembers{}
+
+	send := func(event UserSubscriptionEvent) bool {
+		return deliver(ctx, out, event, options)
+	}
+
+	err := startSubscription(ctx, open, options, func(event Event) bool {
+		var data struct {
+			Rooms  []RoomWithoutMembers `json:"rooms"`
+			Room   RoomWithoutMembers   `json:"room"`
+			RoomID string               `json:"room_id"`
+		}
+		if err := event.Decode(&data); err != nil {
+			options.reportError(err)
+			return true
+		}
+
+		switch event.Name {
+		case eventNameInitialState:
+			// The initial state is sent again when reconnecting, so only the changes since the
+			// previous one are received.
+			current := map[string]bool{}
+			for _, room := range data.Rooms {
+				current[room.ID] = true
+
+				eventType := UserSubscriptionEventAddedToRoom
+				if previous, ok := rooms[room.ID]; ok {
+					if previous.UpdatedAt.Equal(room.UpdatedAt) {
+						continue
+					}
+					eventType = UserSubscriptionEventRoomUpdated
+				}
+
+				room := room
+				rooms[room.ID] = room
+				if !send(UserSubscriptionEvent{
+					Type:      eventType,
+					RoomID:    room.ID,
+					Timestamp: event.Timestamp,
+					Room:      &room,
+				}) {
+					return false
+				}
+			}
+
+			for roomID := range rooms {
+				if current[roomID] {
+					continue
+				}
+
+				delete(rooms, roomID)
+				if !send(UserSubscriptionEvent{
+					Type:      UserSubscriptionEventRemovedFromRoom,
+					RoomID:    roomID,
+					Timestamp: event.Timestamp,
+				}) {
+					return false
+				}
+			}
+
+			return true
+		case eventNameAddedToRoom, eventNameRoomUpdated:
+			rooms[data.Room.ID] = data.Room
+			return send(UserSubscriptionEvent{
+				Type:      event.Name,
+				RoomID:    data.Room.ID,
+				Timestamp: event.Timestamp,
+				Room:      &data.Room,
+			})
+		case eventNameRemovedFromRoom, eventNameRoomDeleted:
+			delete(rooms, data.RoomID)
+			return send(UserSubscriptionEvent{
+				Type:      event.Name,
+				RoomID:    data.RoomID,
+				Timestamp: event.Timestamp,
+			})
+		default:
+			return true
+		}
+	}, func() { close(events) })
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// MembershipEvent is a user joining or leaving a room.
+type MembershipEvent struct {
+	Type      string // One of the MembershipEvent constants
+	RoomID    string
+	UserID    string
+	Timestamp time.Time
+}
+
+// SubscribeToRoomMemberships subscribes to the users joining and leaving a room. The members of
+// the room when subscribing are received first, as joining. If the subscription fails, it is
+// resumed and the changes missed meanwhile are received. The returned channel is closed when ctx
+// is done, or when the subscription fails with an error that reconnecting can't recover from,
+// which is reported to OnError.
+func (c *Client) SubscribeToRoomMemberships(
+	ctx context.Context,
+	roomID string,
+	options SubscriptionOptions,
+) (<-chan MembershipEvent, error) {
+	if roomID == "" {
+		return nil, errors.New("You must provide the ID of the room to subscribe to")
+	}
+
+	options = c.countingDrops(options.withDefaults())
+
+	open := resumableSubscription(ctx, c.coreServiceV6.Subscribe, platformclient.RequestOptions{
+		Path: fmt.Sprintf("/rooms/%s/memberships", url.PathEscape(roomID)),
+	})
+
+	events := make(chan MembershipEvent, options.BufferSize)
+	out := membershipEventChannel(events)
+	members := map[string]bool{}
+
+	send := func(eventType string, userID string, timestamp time.Time) bool {
+		event := MembershipEvent{Type: eventType, RoomID: roomID, UserID: userID, Timestamp: timestamp}
+		return deliver(ctx, out, event, options)
+	}
+
+	err := startSubscription(ctx, open, options, func(event Event) bool {
+		var data struct {
+			UserIDs []string `json:"user_ids"`
+			UserID  string   `json:"user_id"`
+		}
+		if err := event.Decode(&data); err != nil {
+			options.reportError(err)
+			return true
+		}
+
+		switch event.Name {
+		case eventNameInitialState:
+			// The initial state is sent again when reconnecting, so only the changes since the
+			// previous one are received.
+			current := map[string]bool{}
+			for _, userID := range data.UserIDs {
+				current[userID] = true
+				if members[userID] {
+					continue
+				}
+
+				members[userID] = true
+				if !send(MembershipEventUserJoined, userID, event.Timestamp) {
+					return false
+				}
+			}
+
+			for userID := range members {
+				if current[userID] {
+					continue
+				}
+
+				delete(members, userID)
+				if !send(MembershipEventUserLeft, userID, event.Timestamp) {
+					return false
+				}
+			}
+
+			return true
+		case eventNameUserJoined:
+			if members[data.UserID] {
+				return true
+			}
+			members[data.UserID] = true
+			return send(MembershipEventUserJoined, data.UserID, event.Timestamp)
+		case eventNameUserLeft:
+			if !members[data.UserID] {
+				return true
+			}
+			delete(members, data.UserID)
+			return send(MembershipEventUserLeft, data.UserID, event.Timestamp)
+		default:
+			return true
+		}
+	}, func() { close(events) })
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// SubscribeToPresence subscribes to the presence of users, e.g. to keep a cache of which users
+// are online. The current state of each user is received first, followed by their transitions
+// between online and offline. The subscriptions of the users are opened concurrently, with the
+// client's batch concurrency; to share them with other consumers of the same users, use a
+// SubscriptionManager. If the subscription of a user fails it is reopened, and their state is
+// received again only if it changed meanwhile. The returned channel is closed when ctx is done,
+// or when the subscriptions of every user have failed with an error that reconnecting can't
+// recover from, which is reported to OnError.
+func (c *Client) SubscribeToPresence(
+	ctx context.Context,
+	userIDs []string,
+	options SubscriptionOptions,
+) (<-chan UserPresence, error) {
+	if len(userIDs) == 0 {
+		return nil, errors.New("You must provide the IDs of the users to subscribe to")
+	}
+
+	options = c.countingDrops(options.withDefaults())
+	userIDs = uniqueStrings(userIDs)
+
+	subscriptionsCtx, cancel := context.WithCancel(ctx)
+	presences := make(chan UserPresence, options.BufferSize)
+	out := userPresenceChannel(presences)
+
+	var subscriptions sync.WaitGroup
+	subscriptions.Add(len(userIDs))
+	err := forEachConcurrentlyOnce(ctx, userIDs, c.concurrencyFor(0), func(_ context.Context, userID string) error {
+		err := c.startPresenceSubscription(subscriptionsCtx, userID, out, options, subscriptions.Done)
+		if err != nil {
+			subscriptions.Done()
+		}
+		return err
+	})
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	go func() {
+		subscriptions.Wait()
+		cancel()
+		close(presences)
+	}()
+
+	return presences, nil
+}
+
+// startPresenceSubscription opens the subscription to the presence of a user, delivering their
+// states on out until ctx is done or it fails with an error reconnecting can't recover from, when
+// done is called. It returns the error opening the subscription, if any, in which case done isn't
+// called.
+func (c *Client) startPresenceSubscription(
+	ctx context.Context,
+	userID string,
+	out eventChannel,
+	options SubscriptionOptions,
+	done func(),
+) error {
+	open := resumableSubscription(ctx, c.presenceService.Subscribe, platformclient.RequestOptions{
+		Path: fmt.Sprintf("/users/%s", url.PathEscape(userID)),
+	})
+
+	var lastState string
+	return startSubscription(ctx, open, options, func(event Event) bool {
+		if event.Name != eventNamePresenceState {
+			return true
+		}
+
+		var presence UserPresence
+		if err := event.Decode(&presence); err != nil {
+			options.reportError(err)
+			return true
+		}
+		presence.UserID = userID
+
+		// The state is sent again when reconnecting, but only transitions are received.
+		if presence.State == lastState {
+			return true
+		}
+		lastState = presence.State
+
+		return deliver(ctx, out, presence, options)
+	}, done)
+}
+
+// resumableSubscription returns a function opening a subscription with subscribe, which resumes
+// it after the event with the given ID, if any.
+func resumableSubscription(
+	ctx context.Context,
+	subscribe func(context.Context, platformclient.RequestOptions) (*common.SubscriptionStream, error),
+	options platformclient.RequestOptions,
+) func(lastEventID string) (*eventStream, error) {
+	return func(lastEventID string) (*eventStream, error) {
+		headers := http.Header{}
+		if lastEventID != "" {
+			headers.Set(lastEventIDHeader, lastEventID)
+		}
+
+		requestOptions := options
+		requestOptions.Headers = headers
+
+		stream, err := subscribe(ctx, requestOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		return newEventStream(ctx, stream), nil
+	}
+}
+
+// startSubscription opens a subscription, and keeps it open in the background, calling handle
+// with its events and then done once it has ended. It returns the error opening the
+// subscription, if any, in which case neither are called.
+func startSubscription(
+	ctx context.Context,
+	open func(lastEventID string) (*eventStream, error),
+	options SubscriptionOptions,
+	handle func(event Event) bool,
+	done func(),
+) error {
+	stream, err := open("")
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		defer done()
+		keepSubscribed(ctx, stream, open, options, handle)
+	}()
+
+	return nil
+}
+
+// keepSubscribed calls handle with the events of stream until ctx is done or handle returns
+// false. When the subscription fails it is opened again, after a delay, to resume after the last
+// event received, until it fails with an error that reconnecting can't recover from.
+func keepSubscribed(
+	ctx context.Context,
+	stream *eventStream,
+	open func(lastEventID string) (*eventStream, error),
+	options SubscriptionOptions,
+	handle func(event Event) bool,
+) {
+	var lastEventID string
+	delay := options.ReconnectDelay
+	unauthorized := false
+
+	for {
+		for stream.Next() {
+			lastEventID = stream.Event().ID
+			delay = options.ReconnectDelay
+			unauthorized = false
+
+			if !handle(stream.Event()) {
+				stream.Close()
+				return
+			}
+		}
+
+		err := stream.Err()
+		stream.Close()
+
+		for {
+			if ctx.Err() != nil {
+				return
+			}
+
+			options.reportError(err)
+			if subscriptionErrorStatus(err) == http.StatusUnauthorized && options.recoverUnauthorized {
+				// The token may have expired, but a new one being refused as well won't be.
+				if unauthorized {
+					return
+				}
+				unauthorized = true
+			} else if !isRecoverableSubscriptionError(err) {
+				return
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return
+			}
+
+			delay *= 2
+			if delay > maxSubscriptionReconnectDelay {
+				delay = maxSubscriptionReconnectDelay
+			}
+
+			stream, err = open(lastEventID)
+			if err == nil {
+				if options.onResume != nil {
+					options.onResume()
+				}
+				break
+			}
+		}
+	}
+}
+
+// isRecoverableSubscriptionError reports whether reconnecting may recover a subscription from
+// err: connection failures and server errors are, but client errors, such as the subscription
+// not being authorized or the room not existing, aren't.
+func isRecoverableSubscriptionError(err error) bool {
+	status := subscriptionErrorStatus(err)
+	return status == 0 || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+}
+
+// subscriptionErrorStatus returns the status of the response refusing a subscription, or ending
+// it, if err is one, and otherwise 0.
+func subscriptionErrorStatus(err error) int {
+	switch err := err.(type) {
+	case *platformclient.ErrorResponse:
+		return err.Status
+	case *common.EndOfSubscriptionError:
+		return err.Status
+	default:
+		return 0
+	}
+}
+
+// eventChannel gives access to a channel of events of any type, so that deliver can be shared by
+// subscriptions of every type of event. Each is built for a typed channel, e.g. by
+// multipartMessageChannel.
+type eventChannel struct {
+	// send sends event, which must be of the channel's type, waiting until done is closed if the
+	// channel is full. It reports whether the event was sent.
+	send func(event interface{}, done <-chan struct{}) bool
+	// trySend sends event if the channel has room, and reports whether it was sent.
+	trySend func(event interface{}) bool
+	// drop discards the oldest event buffered in the channel, and reports whether there was one.
+	drop func() bool
+	// close closes the channel.
+	close func()
+}
+
+// deliver sends event on out, applying the overflow policy if its buffer is full.
+// It returns false if ctx is done before the event could be sent, or the subscription must end
+// as the consumer fell behind.
+func deliver(ctx context.Context, out eventChannel, event interface{}, options SubscriptionOptions) bool {
+	switch options.Overflow {
+	case OverflowDropOldest:
+		for !out.trySend(event) {
+			if out.drop() {
+				options.reportOverflow()
+			}
+		}
+	case OverflowDropNewest:
+		if !out.trySend(event) {
+			options.reportOverflow()
+		}
+	case OverflowError:
+		if !out.trySend(event) {
+			options.reportOverflow()
+			return false
+		}
+	default:
+		return out.send(event, ctx.Done())
+	}
+
+	return true
+}
+
+func multipartMessageChannel(c chan MultipartMessage) eventChannel {
+	return eventChannel{
+		send: func(event interface{}, done <-chan struct{}) bool {
+			select {
+			case c <- event.(MultipartMessage):
+				return true
+			case <-done:
+				return false
+			}
+		},
+		trySend: func(event interface{}) bool {
+			select {
+			case c <- event.(MultipartMessage):
+				return true
+			default:
+				return false
+			}
+		},
+		drop: func() bool {
+			select {
+			case <-c:
+				return true
+			default:
+				return false
+			}
+		},
+		close: func() { close(c) },
+	}
+}
+
+func userSubscriptionEventChannel(c chan UserSubscriptionEvent) eventChannel {
+	return eventChannel{
+		send: func(event interface{}, done <-chan struct{}) bool {
+			select {
+			case c <- event.(UserSubscriptionEvent):
+				return true
+			case <-done:
+				return false
+			}
+		},
+		trySend: func(event interface{}) bool {
+			select {
+			case c <- event.(UserSubscriptionEvent):
+				return true
+			default:
+				return false
+			}
+		},
+		drop: func() bool {
+			select {
+			case <-c:
+				return true
+			default:
+				return false
+			}
+		},
+		close: func() { close(c) },
+	}
+}
+
+func membershipEventChannel(c chan MembershipEvent) eventChannel {
+	return eventChannel{
+		send: func(event interface{}, done <-chan struct{}) bool {
+			select {
+			case c <- event.(MembershipEvent):
+				return true
+			case <-done:
+				return false
+			}
+		},
+		trySend: func(event interface{}) bool {
+			select {
+			case c <- event.(MembershipEvent):
+				return true
+			default:
+				return false
+			}
+		},
+		drop: func() bool {
+			select {
+			case <-c:
+				return true
+			default:
+				return false
+			}
+		},
+		close: func() { close(c) },
+	}
+}
+
+func userPresenceChannel(c chan UserPresence) eventChannel {
+	return eventChannel{
+		send: func(event interface{}, done <-chan struct{}) bool {
+			select {
+			case c <- event.(UserPresence):
+				return true
+			case <-done:
+				return false
+			}
+		},
+		trySend: func(event interface{}) bool {
+			select {
+			case c <- event.(UserPresence):
+				return true
+			default:
+				return false
+			}
+		},
+		drop: func() bool {
+			select {
+			case <-c:
+				return true
+			default:
+				return false
+			}
+		},
+		close: func() { close(c) },
+	}
+}
+
+func roomEventChannel(c chan RoomEvent) eventChannel {
+	return eventChannel{
+		send: func(event interface{}, done <-chan struct{}) bool {
+			select {
+			case c <- event.(RoomEvent):
+				return true
+			case <-done:
+				return false
+			}
+		},
+		trySend: func(event interface{}) bool {
+			select {
+			case c <- event.(RoomEvent):
+				return true
+			default:
+				return false
+			}
+		},
+		drop: func() bool {
+			select {
+			case <-c:
+				return true
+			default:
+				return false
+			}
+		},
+		close: func() { close(c) },
+	}
+}
@@ -0,0 +1,224 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDeliverOverflowPolicies(t *testing.T) {
+	tests := []struct {
+		overflow  OverflowPolicy
+		delivered bool
+		received  uint // ID of the message left in the buffer
+	}{
+		{OverflowDropOldest, true, 2},
+		{OverflowDropNewest, true, 1},
+		{OverflowError, false, 1},
+	}
+
+	for _, test := range tests {
+		messages := make(chan MultipartMessage, 1)
+		messages <- MultipartMessage{ID: 1}
+
+		var dropped uint64
+		var errs []error
+		options := SubscriptionOptions{
+			Overflow: test.overflow,
+			OnError:  func(err error) { errs = append(errs, err) },
+			dropped:  &dropped,
+		}
+
+		delivered := deliver(context.Background(), multipartMessageChannel(messages), MultipartMessage{ID: 2}, options)
+		if delivered != test.delivered {
+			t.Errorf("Policy %d: expected delivered to be %v, got %v", test.overflow, test.delivered, delivered)
+		}
+		if received := (<-messages).ID; received != test.received {
+			t.Errorf("Policy %d: expected message %d to be buffered, got %d", test.overflow, test.received, received)
+		}
+		if dropped != 1 || len(errs) != 1 || errs[0] != ErrSubscriptionOverflow {
+			t.Errorf("Policy %d: expected one overflow to be counted and reported, got %d and %v", test.overflow, dropped, errs)
+		}
+	}
+}
+
+func TestDeliverBlocksUntilContextDone(t *testing.T) {
+	messages := make(chan MultipartMessage, 1)
+	messages <- MultipartMessage{ID: 1}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if deliver(ctx, multipartMessageChannel(messages), MultipartMessage{ID: 2}, SubscriptionOptions{}) {
+		t.Fatal("Expected delivering to a full channel to fail once the context is done")
+	}
+}
+
+// streamMessages serves a subscription to room-1 that sends a message every millisecond.
+func streamMessages(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+	if !strings.HasSuffix(r.URL.Path, "/rooms/room-1") {
+		<-r.Context().Done()
+		return
+	}
+
+	for id := 1; ; id++ {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-time.After(time.Millisecond):
+			writeSubscriptionEvent(w, fmt.Sprint(id), "new_multipart_message", map[string]interface{}{
+				"id":      id,
+				"user_id": "bob",
+				"room_id": "room-1",
+			})
+		}
+	}
+}
+
+func TestSubscribeToRoomMessagesOverflowError(t *testing.T) {
+	client, server := newStubServer(t, streamMessages)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var mu sync.Mutex
+	var errs []error
+	messages, err := client.SubscribeToRoomMessages(ctx, "room-1", SubscribeToRoomMessagesOptions{
+		SubscriptionOptions: SubscriptionOptions{
+			BufferSize: 1,
+			Overflow:   OverflowError,
+			OnError: func(err error) {
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// Not receiving lets the buffer fill up, which ends the subscription.
+	time.Sleep(50 * time.Millisecond)
+
+	received := 0
+	for range messages {
+		received++
+	}
+	if ctx.Err() != nil {
+		t.Fatal("Expected the subscription to end before its context")
+	}
+	if received != 1 {
+		t.Fatalf("Expected only the buffered message to be received, got %d", received)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(errs) != 1 || errs[0] != ErrSubscriptionOverflow {
+		t.Fatalf("Expected the overflow to be reported, got %v", errs)
+	}
+	if dropped := client.Stats().EventsDropped; dropped != 1 {
+		t.Fatalf("Expected one overflow to be counted, got %d", dropped)
+	}
+}
+
+func TestSubscriptionManagerOverflowErrorUnsubscribesSlowConsumers(t *testing.T) {
+	client, server := newStubServer(t, streamMessages)
+	defer server.Close()
+
+	manager := client.NewSubscriptionManager(SubscriptionManagerOptions{BufferSize: 10, Overflow: OverflowError})
+	defer manager.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	slow, err := manager.SubscribeToRoomMessages(ctx, "room-1")
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+	fast, err := manager.SubscribeToRoomMessages(ctx, "room-1")
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	// Receiving from the fast consumer while the slow one falls behind and is closed.
+	slowClosed := make(chan struct{})
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		for range slow {
+		}
+		close(slowClosed)
+	}()
+
+	for {
+		select {
+		case _, ok := <-fast:
+			if !ok {
+				t.Fatal("Expected the consumer keeping up to stay subscribed")
+			}
+			continue
+		case <-slowClosed:
+		case <-ctx.Done():
+			t.Fatal("Expected the slow consumer to be unsubscribed")
+		}
+		break
+	}
+
+	if _, ok := <-fast; !ok {
+		t.Fatal("Expected the consumer keeping up to stay subscribed")
+	}
+	if client.Stats().EventsDropped == 0 {
+		t.Fatal("Expected the overflow to be counted")
+	}
+}
+
+func TestRawSubscriptionsParseMessages(t *testing.T) {
+	ctx := context.Background()
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprint(w, "[0,\"\"]\n")
+		writeSubscriptionEvent(w, "event-1", "new_message", map[string]interface{}{"id": 1})
+		fmt.Fprint(w, "[0,\"\"]\n")
+		writeSubscriptionEvent(w, "event-2", "new_message", map[string]interface{}{"id": 2})
+		fmt.Fprint(w, "[255,410,{},{\"error\":\"subscription_ended\"}]\n")
+	})
+	defer server.Close()
+
+	for name, subscribe := range map[string]func(ctx context.Context, options RequestOptions) (*RawEventStream, error){
+		"CoreSubscribe":       client.CoreSubscribe,
+		"CursorsSubscribe":    client.CursorsSubscribe,
+		"AuthorizerSubscribe": client.AuthorizerSubscribe,
+	} {
+		stream, err := subscribe(ctx, RequestOptions{Path: "/events"})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		ids := []string{}
+		for stream.Next() {
+			var event Event
+			if err := json.Unmarshal(stream.Event().Body, &event); err != nil || event.Name != "new_message" {
+				t.Errorf("%s: expected a new_message event, got %s", name, stream.Event().Body)
+			}
+			ids = append(ids, stream.Event().ID)
+		}
+
+		if !reflect.DeepEqual(ids, []string{"event-1", "event-2"}) {
+			t.Errorf("%s: expected events event-1 and event-2, got %v", name, ids)
+		}
+
+		eos, ok := stream.Err().(*EndOfSubscriptionError)
+		if !ok || eos.Status != 410 || string(eos.Info) != `{"error":"subscription_ended"}` {
+			t.Errorf("%s: expected the subscription to end with status 410, got %v", name, stream.Err())
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SystemPartType is the content type of inline parts that describe administrative events
+// generated by the server rather than written by a user.
+//
+// A system message is made up of a system part, whose content is a JSON encoded SystemEvent,
+// followed by a text/plain part with a human readable rendering of the event (see
+// SystemEvent.Text). Client apps that understand the convention can render the event
+// consistently; the others fall back to displaying the text part.
+const SystemPartType = "application/x.system+json"
+
+// System event types.
+const (
+	SystemEventUserJoined      = "user_joined"      // UserID joined the room, optionally added by ActorID
+	SystemEventUserLeft        = "user_left"        // UserID left the room, optionally removed by ActorID
+	SystemEventUserRenamed     = "user_renamed"     // UserID changed their name from PreviousName to Name
+	SystemEventMessagePinned   = "message_pinned"   // ActorID pinned MessageID
+	SystemEventMessageUnpinned = "message_unpinned" // ActorID unpinned MessageID
+)
+
+// SystemEvent is the content of a system part.
+type SystemEvent struct {
+	Type         string `json:"type"`                    // One of the SystemEvent* constants
+	UserID       string `json:"user_id,omitempty"`       // User the event is about
+	ActorID      string `json:"actor_id,omitempty"`      // User that caused the event, if not UserID
+	Name         string `json:"name,omitempty"`          // Name of the user after the event
+	PreviousName string `json:"previous_name,omitempty"` // Name of the user before the event
+	MessageID    uint   `json:"message_id,omitempty"`    // Message the event is about
+}
+
+// Text returns a human readable rendering of the event, as sent alongside system parts.
+func (e SystemEvent) Text() string {
+	user := e.UserID
+	if e.Name != "" {
+		user = e.Name
+	}
+
+	switch e.Type {
+	case SystemEventUserJoined:
+		if e.ActorID != "" && e.ActorID != e.UserID {
+			return fmt.Sprintf("%s was added to the room by %s", user, e.ActorID)
+		}
+		return fmt.Sprintf("%s joined the room", user)
+	case SystemEventUserLeft:
+		if e.ActorID != "" && e.ActorID != e.UserID {
+			return fmt.Sprintf("%s was removed from the room by %s", user, e.ActorID)
+		}
+		return fmt.Sprintf("%s left the room", user)
+	case SystemEventUserRenamed:
+		return fmt.Sprintf("%s is now known as %s", e.PreviousName, e.Name)
+	case SystemEventMessagePinned:
+		return fmt.Sprintf("%s pinned a message", e.ActorID)
+	case SystemEventMessageUnpinned:
+		return fmt.Sprintf("%s unpinned a message", e.ActorID)
+	default:
+		return e.Type
+	}
+}
+
+// NewSystemPart returns an inline part carrying a system event.
+func NewSystemPart(event SystemEvent) (NewInlinePart, error) {
+	if event.Type == "" {
+		return NewInlinePart{}, errors.New("You must provide the type of the system event")
+	}
+
+	return systemPart(event), nil
+}
+
+// NewUserJoinedSystemPart returns a system part announcing that a user joined a room.
+// actorID is the user that added them, or empty if they joined by themselves.
+func NewUserJoinedSystemPart(userID string, actorID string) NewInlinePart {
+	return systemPart(SystemEvent{Type: SystemEventUserJoined, UserID: userID, ActorID: actorID})
+}
+
+// NewUserLeftSystemPart returns a system part announcing that a user left a room.
+// actorID is the user that removed them, or empty if they left by themselves.
+func NewUserLeftSystemPart(userID string, actorID string) NewInlinePart {
+	return systemPart(SystemEvent{Type: SystemEventUserLeft, UserID: userID, ActorID: actorID})
+}
+
+// NewUserRenamedSystemPart returns a system part announcing that a user changed their name.
+func NewUserRenamedSystemPart(userID string, previousName string, name string) NewInlinePart {
+	return systemPart(SystemEvent{
+		Type:         SystemEventUserRenamed,
+		UserID:       userID,
+		Name:         name,
+		PreviousName: previousName,
+	})
+}
+
+// NewMessagePinnedSystemPart returns a system part announcing that a message was pinned.
+func NewMessagePinnedSystemPart(actorID string, messageID uint) NewInlinePart {
+	return systemPart(SystemEvent{Type: SystemEventMessagePinned, ActorID: actorID, MessageID: messageID})
+}
+
+// NewMessageUnpinnedSystemPart returns a system part announcing that a message was unpinned.
+func NewMessageUnpinnedSystemPart(actorID string, messageID uint) NewInlinePart {
+	return systemPart(SystemEvent{Type: SystemEventMessageUnpinned, ActorID: actorID, MessageID: messageID})
+}
+
+// systemPart encodes an event that is known to be valid.
+func systemPart(event SystemEvent) NewInlinePart {
+	// Marshalling a SystemEvent cannot fail.
+	content, _ := json.Marshal(event)
+	return NewInlinePart{Type: SystemPartType, Content: string(content)}
+}
+
+// IsSystemPart reports whether a part of a fetched message carries a system event.
+func IsSystemPart(part Part) bool {
+	return part.Type == SystemPartType && part.Content != nil
+}
+
+// ParseSystemPart returns the system event carried by a part of a fetched message.
+func ParseSystemPart(part Part) (SystemEvent, error) {
+	if !IsSystemPart(part) {
+		return SystemEvent{}, fmt.Errorf("Part of type %s is not a system part", part.Type)
+	}
+
+	var event SystemEvent
+	if err := json.Unmarshal([]byte(*part.Content), &event); err != nil {
+		return SystemEvent{}, fmt.Errorf("Failed to unmarshal system event: %v", err)
+	}
+
+	return event, nil
+}
+
+// ParseSystemMessage returns the system event carried by a fetched message.
+// The boolean result is false if the message is not a system message.
+func ParseSystemMessage(message MultipartMessage) (SystemEvent, bool, error) {
+	for _, part := range message.Parts {
+		if IsSystemPart(part) {
+			event, err := ParseSystemPart(part)
+			return event, true, err
+		}
+	}
+
+	return SystemEvent{}, false, nil
+}
+
+// SendSystemMessageOptions contains parameters to pass when sending a system message.
+type SendSystemMessageOptions struct {
+	RoomID   string
+	SenderID string
+	Event    SystemEvent
+}
+
+// SendSystemMessage publishes a system message, made up of a system part and its text
+// rendering, to a room.
+func (m MessagesClient) SendSystemMessage(ctx context.Context, options SendSystemMessageOptions) (uint, error) {
+	part, err := NewSystemPart(options.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	return m.SendMultipartMessage(ctx, SendMultipartMessageOptions{
+		RoomID:   options.RoomID,
+		SenderID: options.SenderID,
+		Parts: []NewPart{
+			part,
+			NewInlinePart{Type: "text/plain", Content: options.Event.Text()},
+		},
+	})
+}
@@ -0,0 +1,58 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+)
+
+// defaultThreadLimit is the number of replies FetchThread returns when no limit is given.
+const defaultThreadLimit = 20
+
+// FetchThreadOptions contains parameters to pass when fetching the replies to a message.
+type FetchThreadOptions struct {
+	InitialID *uint // Only return replies newer than the message with this ID
+	Limit     uint  // Maximum number of replies to return. Defaults to 20
+}
+
+// FetchThread returns the replies to a message, sent with a ParentMessageID, oldest first.
+// To fetch the next page of replies, pass the ID of the last reply returned as InitialID.
+//
+// Chatkit has no native support for threads, so the room's history following the parent
+// message (or InitialID) is scanned for replies. This can take many requests in busy rooms.
+func (m MessagesClient) FetchThread(
+	ctx context.Context,
+	roomID string,
+	parentID uint,
+	options FetchThreadOptions,
+) ([]MultipartMessage, error) {
+	if parentID == 0 {
+		return nil, errors.New("You must provide the ID of the message the thread belongs to")
+	}
+
+	limit := options.Limit
+	if limit == 0 {
+		limit = defaultThreadLimit
+	}
+
+	initialID := parentID
+	if options.InitialID != nil && *options.InitialID > initialID {
+		initialID = *options.InitialID
+	}
+
+	replies := []MultipartMessage{}
+	it := m.IterateRoomMessages(ctx, roomID, IterateRoomMessagesOptions{
+		Direction: "newer",
+		InitialID: &initialID,
+	})
+	for uint(len(replies)) < limit && it.Next() {
+		message := it.Message()
+		if message.ParentMessageID != nil && *message.ParentMessageID == parentID {
+			replies = append(replies, message)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+
+	return replies, nil
+}
@@ -0,0 +1,143 @@
+package chatkit
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/pusher/pusher-platform-go/auth"
+)
+
+// UserAuthorizer identifies the user a token is being requested for, e.g. from the session
+// cookie of the request. Returning an error refuses the request with a 401; the error is not
+// shown to the client.
+type UserAuthorizer func(r *http.Request) (userID string, err error)
+
+// TokenProviderOptions contains parameters to configure a token provider endpoint.
+type TokenProviderOptions struct {
+	// Required function identifying the user of each request.
+	Authorizer UserAuthorizer
+	// Lifetime of the tokens issued. Defaults to the platform's default of 24 hours.
+	TokenExpiry time.Duration
+	// Optional function returning additional claims to add to the token of a user, see
+	// Authenticate.
+	ServiceClaims func(r *http.Request, userID string) map[string]interface{}
+	// Issue a refresh token along with each access token, and accept the `refresh_token` grant
+	// type, which exchanges a refresh token for a new access token without calling Authorizer.
+	// Requires a persistent Store to be configured with WithStore.
+	RefreshTokens bool
+}
+
+// TokenProviderHandler returns an http.Handler implementing a Chatkit token provider endpoint,
+// which client SDKs request user tokens from:
+//
+//	http.Handle("/token", client.TokenProviderHandler(chatkit.TokenProviderOptions{
+//		Authorizer: func(r *http.Request) (string, error) {
+//			return userIDFromSession(r)
+//		},
+//	}))
+//
+// It accepts POST requests with the form encoded `grant_type` GrantTypeClientCredentials, and
+// responds with a token generated by GenerateAccessToken. With RefreshTokens, requests with the
+// `refresh_token` grant type redeem the form encoded `refresh_token` instead, see
+// RefreshAccessToken.
+func (a AuthClient) TokenProviderHandler(options TokenProviderOptions) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeTokenError(w, http.StatusMethodNotAllowed, "invalid_request", "Tokens must be requested with POST")
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			writeTokenError(w, http.StatusBadRequest, "invalid_request", "The request body could not be parsed")
+			return
+		}
+
+		if options.RefreshTokens && r.PostForm.Get("grant_type") == GrantTypeRefreshToken {
+			a.client.refreshToken(w, r, options)
+			return
+		}
+
+		if r.PostForm.Get("grant_type") != GrantTypeClientCredentials {
+			writeTokenError(w, http.StatusBadRequest, "unsupported_grant_type", "The grant type is not supported")
+			return
+		}
+
+		if options.Authorizer == nil {
+			writeTokenError(w, http.StatusInternalServerError, "server_error", "No authorizer is configured")
+			return
+		}
+
+		userID, err := options.Authorizer(r)
+		if err != nil {
+			writeTokenError(w, http.StatusUnauthorized, "unauthorized", "The user could not be authorized")
+			return
+		}
+
+		token, err := a.GenerateAccessToken(options.authOptions(r, userID))
+		if err != nil {
+			writeTokenError(w, http.StatusInternalServerError, "server_error", "The token could not be generated")
+			return
+		}
+
+		body := TokenResponse{
+			AccessToken: token.Token,
+			TokenType:   "bearer",
+			ExpiresIn:   token.ExpiresIn,
+		}
+		if options.RefreshTokens {
+			body.RefreshToken, err = a.IssueRefreshToken(r.Context(), userID)
+			if err != nil {
+				writeTokenError(w, http.StatusInternalServerError, "server_error", "The token could not be generated")
+				return
+			}
+		}
+
+		writeTokenResponse(w, http.StatusOK, body)
+	})
+}
+
+// refreshToken responds to a request with the `refresh_token` grant type.
+func (c *Client) refreshToken(w http.ResponseWriter, r *http.Request, options TokenProviderOptions) {
+	body, err := c.refreshAccessToken(r.Context(), r.PostForm.Get("refresh_token"), func(userID string) auth.Options {
+		return options.authOptions(r, userID)
+	})
+	if err == ErrInvalidRefreshToken {
+		writeTokenError(w, http.StatusBadRequest, "invalid_grant", "The refresh token is invalid or has expired")
+		return
+	}
+	if err != nil {
+		writeTokenError(w, http.StatusInternalServerError, "server_error", "The token could not be generated")
+		return
+	}
+
+	writeTokenResponse(w, http.StatusOK, body)
+}
+
+// authOptions returns the options to generate the token of a user with.
+func (options TokenProviderOptions) authOptions(r *http.Request, userID string) auth.Options {
+	authOptions := auth.Options{UserID: &userID}
+	if options.TokenExpiry > 0 {
+		authOptions.TokenExpiry = &options.TokenExpiry
+	}
+	if options.ServiceClaims != nil {
+		authOptions.ServiceClaims = options.ServiceClaims(r, userID)
+	}
+
+	return authOptions
+}
+
+func writeTokenError(w http.ResponseWriter, status int, errorType string, description string) {
+	writeTokenResponse(w, status, auth.ErrorBody{
+		ErrorType:        errorType,
+		ErrorDescription: description,
+	})
+}
+
+func writeTokenResponse(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
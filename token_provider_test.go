@@ -0,0 +1,79 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func requestToken(t *testing.T, handler http.Handler, grantType string) *httptest.ResponseRecorder {
+	request := httptest.NewRequest(
+		http.MethodPost,
+		"/token",
+		strings.NewReader(url.Values{"grant_type": {grantType}}.Encode()),
+	)
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, request)
+	return recorder
+}
+
+func TestTokenProviderHandlerIssuesAccessTokens(t *testing.T) {
+	client := newTestClient(t)
+	handler := client.Auth().TokenProviderHandler(TokenProviderOptions{
+		Authorizer: func(r *http.Request) (string, error) {
+			return "alice", nil
+		},
+		ServiceClaims: func(r *http.Request, userID string) map[string]interface{} {
+			return map[string]interface{}{"tenant": "acme"}
+		},
+	})
+
+	recorder := requestToken(t, handler, GrantTypeClientCredentials)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	var body TokenResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.TokenType != "bearer" || body.ExpiresIn <= 0 {
+		t.Errorf("Expected a bearer token with an expiry, got %+v", body)
+	}
+
+	claims, err := client.Auth().VerifyToken(context.Background(), body.AccessToken)
+	if err != nil {
+		t.Fatalf("Expected a valid token, got %v", err)
+	}
+	if claims.UserID != "alice" || claims.ServiceClaims["tenant"] != "acme" {
+		t.Errorf("Expected a token for alice with the tenant claim, got %+v", claims)
+	}
+}
+
+func TestTokenProviderHandlerRejectsUnsupportedGrantTypes(t *testing.T) {
+	client := newTestClient(t)
+	authorized := false
+	handler := client.Auth().TokenProviderHandler(TokenProviderOptions{
+		Authorizer: func(r *http.Request) (string, error) {
+			authorized = true
+			return "alice", nil
+		},
+	})
+
+	recorder := requestToken(t, handler, "password")
+	if recorder.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", recorder.Code)
+	}
+	if !strings.Contains(recorder.Body.String(), "unsupported_grant_type") {
+		t.Errorf("Expected an unsupported_grant_type error, got %s", recorder.Body.String())
+	}
+	if authorized {
+		t.Error("Expected the request to be rejected before authorizing the user")
+	}
+}
@@ -0,0 +1,115 @@
+package chatkit
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/pusher/chatkit-server-go/internal/common"
+	platformclient "github.com/pusher/pusher-platform-go/client"
+)
+
+// tracerName is the instrumentation name the tracer of the SDK is obtained with.
+const tracerName = "github.com/pusher/chatkit-server-go"
+
+// TracerProvider provides the tracer the calls made to Chatkit are traced with, e.g. an
+// OpenTelemetry trace.TracerProvider, which is adapted with:
+//
+//	type tracerProvider struct{ trace.TracerProvider }
+//
+//	func (p tracerProvider) Tracer(name string) chatkit.Tracer {
+//		return tracer{p.TracerProvider.Tracer(name)}
+//	}
+//
+//	type tracer struct{ trace.Tracer }
+//
+//	func (t tracer) Start(ctx context.Context, name string) (context.Context, chatkit.Span) {
+//		ctx, s := t.Tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient))
+//		return ctx, span{s}
+//	}
+//
+//	type span struct{ trace.Span }
+//
+//	func (s span) SetAttribute(key string, value interface{}) {
+//		s.Span.SetAttributes(attribute.String(key, fmt.Sprint(value)))
+//	}
+//
+//	func (s span) RecordError(err error) {
+//		s.Span.RecordError(err)
+//		s.Span.SetStatus(codes.Error, err.Error())
+//	}
+//
+//	func (s span) End() {
+//		s.Span.End()
+//	}
+type TracerProvider interface {
+	Tracer(name string) Tracer
+}
+
+// Tracer starts spans.
+type Tracer interface {
+	// Start starts a span as a child of the span in ctx, if any, and returns a context with it.
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span traces a call made to Chatkit.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	RecordError(err error)
+	End()
+}
+
+// traceRequest is an interceptor that traces every request with a span named after its method
+// and route, e.g. "POST /rooms/{room_id}/messages". Spans have the attributes:
+//
+//	chatkit.service      e.g. chatkit_cursors
+//	chatkit.room_id      and the other parameters of the route, e.g. chatkit.user_id
+//	http.method
+//	http.route           e.g. /rooms/{room_id}/messages
+//	http.target          e.g. /rooms/general/messages, with tokens redacted
+//	http.status_code
+func traceRequest(tracer Tracer) common.Interceptor {
+	return func(
+		ctx context.Context,
+		options platformclient.RequestOptions,
+		next common.Invoker,
+	) (*http.Response, error) {
+		r := parseRoute(options.Path)
+
+		ctx, span := tracer.Start(ctx, options.Method+" "+r.Template)
+		defer span.End()
+
+		if r.Service != "" {
+			span.SetAttribute("chatkit.service", r.Service)
+		}
+		for name, value := range r.Params {
+			span.SetAttribute("chatkit."+name, value)
+		}
+		span.SetAttribute("http.method", options.Method)
+		span.SetAttribute("http.route", r.Template)
+		span.SetAttribute("http.target", redactedPath(options))
+
+		response, err := next(ctx, options)
+		if status := responseStatus(response, err); status != 0 {
+			span.SetAttribute("http.status_code", status)
+		}
+		if err != nil {
+			span.RecordError(err)
+		}
+
+		return response, err
+	}
+}
+
+// responseStatus returns the status of the response to a request, or 0 if it failed without
+// one.
+func responseStatus(response *http.Response, err error) int {
+	if response != nil {
+		return response.StatusCode
+	}
+
+	if errorResponse, ok := err.(*platformclient.ErrorResponse); ok {
+		return errorResponse.Status
+	}
+
+	return 0
+}
@@ -0,0 +1,337 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultUsersPageSize is the number of users requested per page when iterating over users.
+const defaultUsersPageSize = 100
+
+// maxUsersPageSize is the largest page of users requested when more users than fit in a page
+// were created at the same time.
+const maxUsersPageSize = 1000
+
+// IterateUsersOptions contains parameters to pass when iterating over users.
+type IterateUsersOptions struct {
+	FromTimestamp string // Only return users created at or after this timestamp (RFC 3339)
+	PageSize      uint   // Number of users fetched per request. Defaults to 100
+}
+
+// UsersIterator pages through the users of an instance, in the order they were created.
+// Pages are only requested once the previous one has been consumed.
+//
+//	it := client.IterateUsers(ctx, IterateUsersOptions{})
+//	for it.Next() {
+//		user := it.User()
+//	}
+//	if err := it.Err(); err != nil {
+//		return err
+//	}
+type UsersIterator struct {
+	ctx      context.Context
+	client   *Client
+	filter   func(User) bool
+	pageSize uint
+	limit    uint // Size of the next page requested, larger than pageSize to page past ties
+
+	fromTimestamp string
+	boundaryIDs   map[string]bool // IDs already returned that were created at fromTimestamp
+	page          []User
+	current       User
+	lastPage      bool
+	err           error
+}
+
+// IterateUsers returns an iterator over all users of the instance.
+func (u UsersClient) IterateUsers(ctx context.Context, options IterateUsersOptions) *UsersIterator {
+	pageSize := options.PageSize
+	if pageSize == 0 {
+		pageSize = defaultUsersPageSize
+	}
+
+	return &UsersIterator{
+		ctx:           ctx,
+		client:        u.client,
+		pageSize:      pageSize,
+		limit:         pageSize,
+		fromTimestamp: options.FromTimestamp,
+		boundaryIDs:   map[string]bool{},
+	}
+}
+
+// Next advances the iterator to the next user.
+// It returns false when there are no more users or an error occurred.
+func (it *UsersIterator) Next() bool {
+	for {
+		for len(it.page) > 0 {
+			it.current = it.page[0]
+			it.page = it.page[1:]
+
+			if it.filter == nil || it.filter(it.current) {
+				return true
+			}
+		}
+
+		if it.lastPage || it.err != nil {
+			return false
+		}
+
+		it.fetchPage()
+	}
+}
+
+// User returns the user the iterator currently points at.
+func (it *UsersIterator) User() User {
+	return it.current
+}
+
+// Err returns the error, if any, that stopped the iteration.
+func (it *UsersIterator) Err() error {
+	return it.err
+}
+
+// fetchPage requests the next page of users.
+// Pages are requested by creation timestamp, which is inclusive, so users on the boundary of the
+// previous page are skipped. Chatkit has no other way to order users created at the same time,
+// so when a whole page of them has been returned, larger pages are requested until the next
+// page gets past them, failing if more than maxUsersPageSize share a timestamp.
+func (it *UsersIterator) fetchPage() {
+	users, err := it.client.Users().GetUsers(it.ctx, &GetUsersOptions{
+		FromTimestamp: it.fromTimestamp,
+		Limit:         it.limit,
+	})
+	if err != nil {
+		it.err = err
+		return
+	}
+
+	if uint(len(users)) < it.limit {
+		it.lastPage = true
+	}
+
+	for _, user := range users {
+		if it.boundaryIDs[user.ID] {
+			continue
+		}
+
+		timestamp := user.CreatedAt.UTC().Format(time.RFC3339Nano)
+		if timestamp != it.fromTimestamp {
+			it.fromTimestamp = timestamp
+			it.boundaryIDs = map[string]bool{}
+		}
+		it.boundaryIDs[user.ID] = true
+
+		it.page = append(it.page, user)
+	}
+
+	if len(it.page) > 0 || it.lastPage {
+		it.limit = it.pageSize
+		return
+	}
+
+	// A full page made up entirely of users that were already returned, all created at
+	// fromTimestamp, can only be paged past by requesting more of them at once.
+	if it.limit >= maxUsersPageSize {
+		it.err = fmt.Errorf(
+			"Failed to page through users: more than %d users were created at %s",
+			maxUsersPageSize,
+			it.fromTimestamp,
+		)
+		return
+	}
+
+	it.limit *= 2
+	if it.limit > maxUsersPageSize {
+		it.limit = maxUsersPageSize
+	}
+}
+
+// SearchUsersOptions contains parameters to pass when searching for users.
+type SearchUsersOptions struct {
+	CaseSensitive bool // Match the query against names exactly rather than ignoring case
+	MatchID       bool // Also return users whose ID starts with the query
+	PageSize      uint // Number of users fetched per request. Defaults to 100
+}
+
+// SearchUsers returns an iterator over the users whose name starts with the query.
+// Chatkit does not provide a search endpoint, so users are paged through and filtered as
+// the iterator advances rather than being loaded up front.
+func (u UsersClient) SearchUsers(
+	ctx context.Context,
+	query string,
+	options SearchUsersOptions,
+) *UsersIterator {
+	it := u.IterateUsers(ctx, IterateUsersOptions{PageSize: options.PageSize})
+
+	if !options.CaseSensitive {
+		query = strings.ToLower(query)
+	}
+
+	it.filter = func(user User) bool {
+		name := user.Name
+		id := user.ID
+		if !options.CaseSensitive {
+			name = strings.ToLower(name)
+			id = strings.ToLower(id)
+		}
+
+		return strings.HasPrefix(name, query) || (options.MatchID && strings.HasPrefix(id, query))
+	}
+
+	return it
+}
+
+// maxUsersPerLookupRequest is the maximum number of users retrieved by ID in a single request,
+// which keeps the query string well within the length limits of URLs.
+const maxUsersPerLookupRequest = 100
+
+// GetUsersByID retrieves a list of users for the given id's.
+// Any number of users can be retrieved. Up to 100 are requested in a single request, and returned
+// as Chatkit returns them. More are requested in chunks of at most 100 per request, with up to the
+// client's lookup concurrency in flight at once, and returned in the order their IDs were given,
+// without duplicates. Users that don't exist are left out. If some of the chunks fail a
+// *BatchError is returned, keyed by user ID, along with the users of the other chunks.
+func (u UsersClient) GetUsersByID(ctx context.Context, userIDs []string) ([]User, error) {
+	c := u.client
+	if len(userIDs) <= maxUsersPerLookupRequest {
+		return c.coreServiceV6.GetUsersByID(ctx, userIDs)
+	}
+
+	userIDs = uniqueStrings(userIDs)
+
+	var (
+		mu    sync.Mutex
+		users []User
+	)
+
+	err := forEachChunkConcurrently(
+		ctx,
+		userIDs,
+		maxUsersPerLookupRequest,
+		c.concurrencyForLookups(),
+		func(ctx context.Context, chunk []string) error {
+			chunkUsers, err := c.coreServiceV6.GetUsersByID(ctx, chunk)
+			if err != nil {
+				return err
+			}
+
+			mu.Lock()
+			users = append(users, chunkUsers...)
+			mu.Unlock()
+			return nil
+		},
+	)
+
+	return orderUsers(users, userIDs), err
+}
+
+// orderUsers returns users in the order of userIDs. Users whose ID isn't in userIDs are left out.
+func orderUsers(users []User, userIDs []string) []User {
+	byID := make(map[string]User, len(users))
+	for _, user := range users {
+		byID[user.ID] = user
+	}
+
+	ordered := make([]User, 0, len(byID))
+	for _, userID := range userIDs {
+		if user, ok := byID[userID]; ok {
+			ordered = append(ordered, user)
+		}
+	}
+
+	return ordered
+}
+
+// UpdateUsersOptions contains parameters to pass when updating users in bulk.
+type UpdateUsersOptions struct {
+	Concurrency int // Maximum number of updates in flight at once. Defaults to the client's batch concurrency
+}
+
+// UpdateUsers applies updates to many users, keyed by user ID.
+// Chatkit has no batch update endpoint, so the updates are performed concurrently.
+// The result reports the outcome for each user, in the order of their IDs.
+func (u UsersClient) UpdateUsers(
+	ctx context.Context,
+	updates map[string]UpdateUserOptions,
+	options UpdateUsersOptions,
+) *BatchResult {
+	userIDs := make([]string, 0, len(updates))
+	for userID := range updates {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+
+	executor := u.client.newBatchExecutor(BatchOptions{Concurrency: options.Concurrency})
+	return executor.Execute(ctx, userIDs, func(ctx context.Context, userID string) error {
+		return u.UpdateUser(ctx, userID, updates[userID])
+	})
+}
+
+// RenameUserOptions contains parameters to pass when renaming a user.
+type RenameUserOptions struct {
+	Name      *string
+	AvatarURL *string
+	// If set, a message announcing the new name is sent by the user to every room they are a
+	// member of. The message carries a system part along with a plain text rendering.
+	Announce    bool
+	Concurrency int // Maximum number of announcements in flight at once. Defaults to the client's batch concurrency
+}
+
+// RenameUser updates the name and/or avatar of a user and optionally announces the change in
+// the user's rooms.
+// If announcing fails in some rooms a *BatchError is returned, keyed by room ID.
+func (u UsersClient) RenameUser(ctx context.Context, userID string, options RenameUserOptions) error {
+	c := u.client
+	if options.Name == nil && options.AvatarURL == nil {
+		return errors.New("You must provide a new name or avatar for the user")
+	}
+
+	user, err := u.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	err = u.UpdateUser(ctx, userID, UpdateUserOptions{
+		Name:      options.Name,
+		AvatarUrl: options.AvatarURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !options.Announce || options.Name == nil || *options.Name == user.Name {
+		return nil
+	}
+
+	rooms, err := c.Rooms().GetUserRooms(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	roomIDs := make([]string, len(rooms))
+	for i, room := range rooms {
+		roomIDs[i] = room.ID
+	}
+
+	// Sending isn't idempotent, so an announcement that may or may not have been sent isn't
+	// retried, rather than risk announcing the new name twice.
+	return forEachConcurrentlyOnce(ctx, roomIDs, c.concurrencyFor(options.Concurrency), func(ctx context.Context, roomID string) error {
+		_, err := c.Messages().SendSystemMessage(ctx, SendSystemMessageOptions{
+			RoomID:   roomID,
+			SenderID: userID,
+			Event: SystemEvent{
+				Type:         SystemEventUserRenamed,
+				UserID:       userID,
+				Name:         *options.Name,
+				PreviousName: user.Name,
+			},
+		})
+		return err
+	})
+}
@@ -0,0 +1,277 @@
+package chatkit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newUsersStub returns a client whose instance has users, in creation order, served like
+// Chatkit does: from a creation timestamp, inclusive, up to a limit.
+func newUsersStub(t *testing.T, users []User) (*Client, func()) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/users") {
+			http.NotFound(w, r)
+			return
+		}
+
+		from := time.Time{}
+		if ts := r.URL.Query().Get("from_ts"); ts != "" {
+			from, _ = time.Parse(time.RFC3339Nano, ts)
+		}
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		page := []User{}
+		for _, user := range users {
+			if len(page) < limit && !user.CreatedAt.Before(from) {
+				page = append(page, user)
+			}
+		}
+		writeTestJSON(w, page)
+	})
+
+	return client, server.Close
+}
+
+// usersCreatedAt returns count users created at the times given by at.
+func usersCreatedAt(count int, at func(i int) time.Time) []User {
+	users := make([]User, count)
+	for i := range users {
+		users[i] = User{ID: fmt.Sprintf("user-%04d", i), CreatedAt: at(i)}
+	}
+	return users
+}
+
+func TestIterateUsersPagesPastUsersCreatedAtTheSameTime(t *testing.T) {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := usersCreatedAt(300, func(i int) time.Time {
+		if i >= 50 && i < 250 {
+			return start.Add(50 * time.Second)
+		}
+		return start.Add(time.Duration(i) * time.Second)
+	})
+
+	client, closeServer := newUsersStub(t, users)
+	defer closeServer()
+
+	seen := map[string]int{}
+	it := client.Users().IterateUsers(context.Background(), IterateUsersOptions{PageSize: 100})
+	for it.Next() {
+		seen[it.User().ID]++
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, user := range users {
+		if seen[user.ID] != 1 {
+			t.Fatalf("Expected %s to be returned once, got %d times", user.ID, seen[user.ID])
+		}
+	}
+	if len(seen) != len(users) {
+		t.Fatalf("Expected %d users, got %d", len(users), len(seen))
+	}
+}
+
+func TestIterateUsersFailsRatherThanDroppingUsers(t *testing.T) {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := usersCreatedAt(maxUsersPageSize+10, func(int) time.Time { return start })
+
+	client, closeServer := newUsersStub(t, users)
+	defer closeServer()
+
+	count := 0
+	it := client.Users().IterateUsers(context.Background(), IterateUsersOptions{PageSize: 100})
+	for it.Next() {
+		count++
+	}
+
+	if it.Err() == nil {
+		t.Fatalf("Expected an error after %d of %d users, got none", count, len(users))
+	}
+}
+
+func TestGetUsersWithRoleSeesEveryUser(t *testing.T) {
+	start := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	users := usersCreatedAt(150, func(int) time.Time { return start })
+
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/users"):
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			if limit > len(users) {
+				limit = len(users)
+			}
+			writeTestJSON(w, users[:limit])
+		case strings.HasSuffix(r.URL.Path, "/roles"):
+			writeTestJSON(w, []map[string]interface{}{{"role_name": "admin", "scope": "global"}})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	defer server.Close()
+
+	assignments, err := client.Roles().GetUsersWithRole(context.Background(), "admin", RoleScopeGlobal, GetUsersWithRoleOptions{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(assignments) != len(users) {
+		t.Fatalf("Expected %d assignments, got %d", len(users), len(assignments))
+	}
+}
+
+func TestGetUsersPresenceBoundsConcurrentLookups(t *testing.T) {
+	var inFlight, maxInFlight int32
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			max := atomic.LoadInt32(&maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		userID := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		writeTestJSON(w, map[string]interface{}{"state": "online", "user_id": userID})
+	}, WithLookupConcurrency(2))
+	defer server.Close()
+
+	userIDs := make([]string, 10)
+	for i := range userIDs {
+		userIDs[i] = fmt.Sprintf("user-%d", i)
+	}
+
+	presences, err := client.GetUsersPresence(context.Background(), userIDs)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i, presence := range presences {
+		if presence.UserID != userIDs[i] || presence.State != "online" {
+			t.Errorf("Expected %s to be online at %d, got %+v", userIDs[i], i, presence)
+		}
+	}
+	if max := atomic.LoadInt32(&maxInFlight); max > 2 {
+		t.Fatalf("Expected at most 2 lookups at once, got %d", max)
+	}
+}
+
+// newLookupStub returns a client whose instance serves users by ID in the reverse of the order
+// they were requested in, leaving out users whose IDs start with "missing" and failing requests
+// for users whose IDs start with "fail".
+func newLookupStub(t *testing.T) (*Client, func()) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		ids := r.URL.Query()["id"]
+		users := []User{}
+		for i := len(ids) - 1; i >= 0; i-- {
+			if strings.HasPrefix(ids[i], "fail") {
+				w.WriteHeader(http.StatusBadRequest)
+				writeTestJSON(w, map[string]string{"error": "services/chatkit/bad_request"})
+				return
+			}
+			if !strings.HasPrefix(ids[i], "missing") {
+				users = append(users, User{ID: ids[i]})
+			}
+		}
+		writeTestJSON(w, users)
+	})
+
+	return client, server.Close
+}
+
+func userIDsOf(users []User) []string {
+	ids := make([]string, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+	return ids
+}
+
+func TestGetUsersByIDReturnsSmallLookupsAsChatkitDoes(t *testing.T) {
+	client, closeServer := newLookupStub(t)
+	defer closeServer()
+
+	users, err := client.Users().GetUsersByID(context.Background(), []string{"alice", "bob", "alice"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := strings.Join(userIDsOf(users), ","); ids != "alice,bob,alice" {
+		t.Errorf("Expected the users as returned by Chatkit, got %s", ids)
+	}
+}
+
+func TestGetUsersByIDOrdersChunkedLookups(t *testing.T) {
+	client, closeServer := newLookupStub(t)
+	defer closeServer()
+
+	userIDs := []string{}
+	for i := 0; i < 250; i++ {
+		userIDs = append(userIDs, fmt.Sprintf("user-%03d", i))
+	}
+	expected := strings.Join(userIDs, ",")
+	userIDs = append(userIDs, "user-000", "missing-1")
+
+	users, err := client.Users().GetUsersByID(context.Background(), userIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := strings.Join(userIDsOf(users), ","); ids != expected {
+		t.Errorf("Expected the users in the order of their IDs, without duplicates or missing users, got %s", ids)
+	}
+}
+
+func TestGetUsersByIDReturnsPartialResults(t *testing.T) {
+	client, closeServer := newLookupStub(t)
+	defer closeServer()
+
+	userIDs := []string{}
+	for i := 0; i < 200; i++ {
+		userIDs = append(userIDs, fmt.Sprintf("user-%03d", i))
+	}
+	// The second chunk fails.
+	userIDs[150] = "fail-150"
+
+	users, err := client.Users().GetUsersByID(context.Background(), userIDs)
+	batchErr, ok := err.(*BatchError)
+	if !ok {
+		t.Fatalf("Expected a *BatchError, got %v", err)
+	}
+
+	if len(batchErr.Errors) != 100 || batchErr.Errors["fail-150"] == nil || batchErr.Errors["user-199"] == nil {
+		t.Errorf("Expected the users of the second chunk to have failed, got %v", batchErr.Errors)
+	}
+	if ids := strings.Join(userIDsOf(users), ","); ids != strings.Join(userIDs[:100], ",") {
+		t.Errorf("Expected the users of the first chunk, got %s", ids)
+	}
+}
+
+func TestGetUsersPresenceReturnsPartialResults(t *testing.T) {
+	client, server := newStubServer(t, func(w http.ResponseWriter, r *http.Request) {
+		userID := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		if userID == "bob" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		writeTestJSON(w, map[string]interface{}{"user_id": userID, "state": "online"})
+	})
+	defer server.Close()
+
+	presences, err := client.GetUsersPresence(context.Background(), []string{"carol", "bob", "alice", "carol"})
+
+	batchErr, ok := err.(*BatchError)
+	if !ok || len(batchErr.Errors) != 1 || batchErr.Errors["bob"] == nil {
+		t.Fatalf("Expected a *BatchError for bob, got %v", err)
+	}
+	if len(presences) != 2 || presences[0].UserID != "carol" || presences[1].UserID != "alice" {
+		t.Errorf("Expected the presence of carol and alice, got %+v", presences)
+	}
+}
@@ -0,0 +1,269 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBridgeBatchSize     = 100
+	defaultBridgeFlushInterval = time.Second
+	defaultBridgeMaxAttempts   = 5
+	defaultBridgeRetryBackoff  = 100 * time.Millisecond
+)
+
+// ErrWebhookBridgeClosed is returned when publishing an event through a WebhookBridge that has
+// been closed.
+var ErrWebhookBridgeClosed = errors.New("Webhook bridge is closed")
+
+// Publisher publishes batches of webhook events to a message queue or event pipeline.
+// For example, with Kafka (github.com/segmentio/kafka-go):
+//
+//	chatkit.PublisherFunc(func(ctx context.Context, events []chatkit.WebhookEvent) error {
+//		messages := make([]kafka.Message, len(events))
+//		for i, event := range events {
+//			value, _ := json.Marshal(event)
+//			messages[i] = kafka.Message{Key: []byte(event.Metadata.EventType), Value: value}
+//		}
+//		return writer.WriteMessages(ctx, messages...)
+//	})
+//
+// With NATS (github.com/nats-io/nats.go), publishing each event to a subject named after its type:
+//
+//	for _, event := range events {
+//		value, _ := json.Marshal(event)
+//		if err := conn.Publish("chatkit."+event.Metadata.EventType, value); err != nil {
+//			return err
+//		}
+//	}
+//	return conn.FlushWithContext(ctx)
+//
+// With SQS (github.com/aws/aws-sdk-go), in batches of up to 10 entries as SQS requires, so with
+// a BatchSize of 10:
+//
+//	entries := make([]*sqs.SendMessageBatchRequestEntry, len(events))
+//	for i, event := range events {
+//		value, _ := json.Marshal(event)
+//		entries[i] = &sqs.SendMessageBatchRequestEntry{
+//			Id:          aws.String(strconv.Itoa(i)),
+//			MessageBody: aws.String(string(value)),
+//		}
+//	}
+//	_, err := client.SendMessageBatchWithContext(ctx, &sqs.SendMessageBatchInput{
+//		QueueUrl: aws.String(queueURL),
+//		Entries:  entries,
+//	})
+//	return err
+//
+// Implementations must be safe for concurrent use. An error fails the whole batch, which is
+// retried.
+type Publisher interface {
+	Publish(ctx context.Context, events []WebhookEvent) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface.
+type PublisherFunc func(ctx context.Context, events []WebhookEvent) error
+
+// Publish calls f.
+func (f PublisherFunc) Publish(ctx context.Context, events []WebhookEvent) error {
+	return f(ctx, events)
+}
+
+// WebhookBridgeOptions contains parameters to configure a WebhookBridge.
+type WebhookBridgeOptions struct {
+	// Maximum number of events published at once. Defaults to 100.
+	BatchSize int
+	// Longest an event waits for its batch to fill before it is published. Defaults to 1s.
+	FlushInterval time.Duration
+	// Number of times publishing a batch is attempted before it fails. Defaults to 5.
+	MaxAttempts int
+	// Delay before the first retry of a failed batch, doubled for each subsequent retry.
+	// Defaults to 100ms.
+	RetryBackoff time.Duration
+	// Optional function called when a batch has failed to be published.
+	OnError func(events []WebhookEvent, err error)
+}
+
+type bridgedEvent struct {
+	event WebhookEvent
+	done  chan error
+}
+
+// WebhookBridge publishes webhook events to a Publisher, in batches.
+//
+//	bridge := chatkit.NewWebhookBridge(publisher, chatkit.WebhookBridgeOptions{})
+//	bridge.Register(handler, chatkit.WebhookEventMessagesCreated, chatkit.WebhookEventUsersCreated)
+//
+// Publishing an event waits until its batch has been published, so when registered with a
+// WebhookHandler without workers, webhooks are only acknowledged once their event is published,
+// and Chatkit retries those that could not be.
+type WebhookBridge struct {
+	publisher Publisher
+	options   WebhookBridgeOptions
+
+	// ctx is passed to the publisher, and cancelled when Close gives up waiting for batches.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	pending  []bridgedEvent
+	timer    *time.Timer
+	closed   bool
+	inFlight sync.WaitGroup
+}
+
+// NewWebhookBridge returns a WebhookBridge that publishes events with publisher.
+func NewWebhookBridge(publisher Publisher, options WebhookBridgeOptions) *WebhookBridge {
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBridgeBatchSize
+	}
+
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = defaultBridgeFlushInterval
+	}
+
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = defaultBridgeMaxAttempts
+	}
+
+	if options.RetryBackoff <= 0 {
+		options.RetryBackoff = defaultBridgeRetryBackoff
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &WebhookBridge{publisher: publisher, options: options, ctx: ctx, cancel: cancel}
+}
+
+// Register makes handler publish the events of the given types through the bridge.
+func (b *WebhookBridge) Register(handler *WebhookHandler, eventTypes ...string) {
+	for _, eventType := range eventTypes {
+		handler.On(eventType, b.Publish)
+	}
+}
+
+// Publish adds an event to the current batch, and waits until the batch has been published or
+// ctx is done. The event may still be published after ctx is done.
+func (b *WebhookBridge) Publish(ctx context.Context, event WebhookEvent) error {
+	done := make(chan error, 1)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return ErrWebhookBridgeClosed
+	}
+
+	b.pending = append(b.pending, bridgedEvent{event: event, done: done})
+	if len(b.pending) >= b.options.BatchSize {
+		b.flushLocked()
+	} else if len(b.pending) == 1 {
+		b.timer = time.AfterFunc(b.options.FlushInterval, b.flush)
+	}
+	b.mu.Unlock()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close publishes the pending events, and waits for every batch to be published or ctx to be
+// done. If ctx is done first, batches still being published or retried are cancelled. Events
+// published afterwards are refused with ErrWebhookBridgeClosed.
+func (b *WebhookBridge) Close(ctx context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.flushLocked()
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.inFlight.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		b.cancel()
+		return nil
+	case <-ctx.Done():
+		b.cancel()
+		return ctx.Err()
+	}
+}
+
+func (b *WebhookBridge) flush() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.flushLocked()
+}
+
+// flushLocked starts publishing the pending events. b.mu must be held.
+func (b *WebhookBridge) flushLocked() {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+
+	if len(b.pending) == 0 {
+		return
+	}
+
+	batch := b.pending
+	b.pending = nil
+
+	b.inFlight.Add(1)
+	go func() {
+		defer b.inFlight.Done()
+
+		err := b.publish(batch)
+		for _, bridged := range batch {
+			bridged.done <- err
+		}
+	}()
+}
+
+// publish publishes a batch of events, retrying failures with exponential backoff.
+func (b *WebhookBridge) publish(batch []bridgedEvent) error {
+	events := make([]WebhookEvent, len(batch))
+	for i, bridged := range batch {
+		events[i] = bridged.event
+	}
+
+	var err error
+	for attempt := 0; attempt < b.options.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			if err = b.wait(b.options.RetryBackoff << uint(attempt-1)); err != nil {
+				break
+			}
+		}
+
+		err = b.publisher.Publish(b.ctx, events)
+		if err == nil {
+			return nil
+		}
+	}
+
+	if b.options.OnError != nil {
+		b.options.OnError(events, err)
+	}
+
+	return err
+}
+
+// wait waits for d, returning early with an error if the bridge is cancelled.
+func (b *WebhookBridge) wait(d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-b.ctx.Done():
+		return b.ctx.Err()
+	}
+}
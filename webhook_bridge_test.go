@@ -0,0 +1,72 @@
+package chatkit
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookBridgeCloseCancelsRetries(t *testing.T) {
+	var attempts int32
+	bridge := NewWebhookBridge(PublisherFunc(func(ctx context.Context, events []WebhookEvent) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("queue unavailable")
+	}), WebhookBridgeOptions{
+		BatchSize:    1,
+		MaxAttempts:  5,
+		RetryBackoff: time.Hour,
+	})
+
+	published := make(chan error, 1)
+	go func() {
+		published <- bridge.Publish(context.Background(), WebhookEvent{})
+	}()
+
+	for atomic.LoadInt32(&attempts) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	if err := bridge.Close(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("Expected Close to time out, got %v", err)
+	}
+
+	select {
+	case err := <-published:
+		if err != context.Canceled {
+			t.Fatalf("Expected the batch to be cancelled, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the retries to be cancelled")
+	}
+
+	if count := atomic.LoadInt32(&attempts); count != 1 {
+		t.Fatalf("Expected 1 attempt, got %d", count)
+	}
+}
+
+func TestWebhookBridgeRetriesFailedBatches(t *testing.T) {
+	var attempts int32
+	bridge := NewWebhookBridge(PublisherFunc(func(ctx context.Context, events []WebhookEvent) error {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			return errors.New("queue unavailable")
+		}
+		return nil
+	}), WebhookBridgeOptions{
+		BatchSize:    1,
+		RetryBackoff: time.Millisecond,
+	})
+	defer bridge.Close(context.Background())
+
+	if err := bridge.Publish(context.Background(), WebhookEvent{}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if count := atomic.LoadInt32(&attempts); count != 3 {
+		t.Fatalf("Expected 3 attempts, got %d", count)
+	}
+}
@@ -0,0 +1,304 @@
+package chatkit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Types of the events Chatkit sends webhooks for.
+const (
+	WebhookEventMessagesCreated      = "v1.messages_created"
+	WebhookEventUsersCreated         = "v1.users_created"
+	WebhookEventUsersDeleted         = "v1.users_deleted"
+	WebhookEventUsersAddedToRoom     = "v1.users_added_to_room"
+	WebhookEventUsersRemovedFromRoom = "v1.users_removed_from_room"
+)
+
+const defaultWebhookQueueSize = 100
+
+// ErrWebhookHandlerClosed is returned by WebhookHandler.Close when it has already been closed.
+var ErrWebhookHandlerClosed = errors.New("Webhook handler is closed")
+
+// WebhookMetadata describes a webhook event.
+type WebhookMetadata struct {
+	EventType      string    `json:"event_type"`
+	EventTimestamp time.Time `json:"event_timestamp"`
+	// Number of times Chatkit has attempted to deliver the event, starting at 1.
+	Attempt int `json:"attempt"`
+}
+
+// WebhookEvent is a webhook event as sent by Chatkit, with its payload left undecoded.
+type WebhookEvent struct {
+	Metadata WebhookMetadata `json:"metadata"`
+	Payload  json.RawMessage `json:"payload"`
+}
+
+// MessageCreatedEvent is passed to OnMessageCreated callbacks.
+type MessageCreatedEvent struct {
+	Metadata WebhookMetadata
+	Message  MultipartMessage
+}
+
+// UserEvent is passed to OnUserCreated and OnUserDeleted callbacks.
+type UserEvent struct {
+	Metadata WebhookMetadata
+	User     User
+}
+
+// RoomMembershipEvent is passed to OnUsersAddedToRoom and OnUsersRemovedFromRoom callbacks.
+type RoomMembershipEvent struct {
+	Metadata WebhookMetadata
+	Room     Room
+	Users    []User
+}
+
+// WebhookHandlerOptions contains parameters to configure a WebhookHandler.
+type WebhookHandlerOptions struct {
+	// Required secret of the webhook, used to verify requests.
+	Secret string
+	// Number of workers processing events asynchronously. By default events are processed before
+	// the request is responded to, and Chatkit retries those whose callbacks return an error.
+	// With workers, requests are responded to once their event is queued, and errors are only
+	// reported to OnError.
+	Workers int
+	// Number of events that can be queued for the workers before requests are refused with a
+	// 503, which Chatkit retries. Defaults to 100.
+	QueueSize int
+	// Optional function called when an event can't be decoded or a callback returns an error.
+	OnError func(event WebhookEvent, err error)
+}
+
+// WebhookHandler is an http.Handler that receives Chatkit webhooks: it verifies their
+// signature, decodes them, and calls the callbacks registered for their event type.
+//
+//	handler := chatkit.NewWebhookHandler(chatkit.WebhookHandlerOptions{Secret: secret})
+//	handler.OnMessageCreated(func(ctx context.Context, event chatkit.MessageCreatedEvent) error {
+//		return index(event.Message)
+//	})
+//	http.Handle("/webhooks", handler)
+//
+// Callbacks must be registered before the handler receives requests.
+type WebhookHandler struct {
+	options   WebhookHandlerOptions
+	callbacks map[string][]func(ctx context.Context, event WebhookEvent) error
+
+	queue   chan WebhookEvent
+	workers sync.WaitGroup
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// NewWebhookHandler returns a WebhookHandler, starting its workers if it has any.
+func NewWebhookHandler(options WebhookHandlerOptions) *WebhookHandler {
+	if options.QueueSize <= 0 {
+		options.QueueSize = defaultWebhookQueueSize
+	}
+
+	h := &WebhookHandler{
+		options:   options,
+		callbacks: map[string][]func(ctx context.Context, event WebhookEvent) error{},
+	}
+
+	if options.Workers > 0 {
+		h.queue = make(chan WebhookEvent, options.QueueSize)
+		for i := 0; i < options.Workers; i++ {
+			h.workers.Add(1)
+			go func() {
+				defer h.workers.Done()
+				for event := range h.queue {
+					if err := h.dispatch(context.Background(), event); err != nil {
+						h.reportError(event, err)
+					}
+				}
+			}()
+		}
+	}
+
+	return h
+}
+
+// On registers a callback for events of a type, which receives the event with its payload
+// undecoded, e.g. for event types the SDK has no callbacks for.
+func (h *WebhookHandler) On(eventType string, fn func(ctx context.Context, event WebhookEvent) error) {
+	h.callbacks[eventType] = append(h.callbacks[eventType], fn)
+}
+
+// OnMessageCreated registers a callback called for each message sent.
+func (h *WebhookHandler) OnMessageCreated(fn func(ctx context.Context, event MessageCreatedEvent) error) {
+	h.On(WebhookEventMessagesCreated, func(ctx context.Context, event WebhookEvent) error {
+		var payload struct {
+			Messages []MultipartMessage `json:"messages"`
+		}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("Failed to decode %s payload: %v", event.Metadata.EventType, err)
+		}
+
+		for _, message := range payload.Messages {
+			if err := fn(ctx, MessageCreatedEvent{Metadata: event.Metadata, Message: message}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// OnUserCreated registers a callback called for each user created.
+func (h *WebhookHandler) OnUserCreated(fn func(ctx context.Context, event UserEvent) error) {
+	h.On(WebhookEventUsersCreated, userEventCallback(fn))
+}
+
+// OnUserDeleted registers a callback called for each user deleted.
+func (h *WebhookHandler) OnUserDeleted(fn func(ctx context.Context, event UserEvent) error) {
+	h.On(WebhookEventUsersDeleted, userEventCallback(fn))
+}
+
+// OnUsersAddedToRoom registers a callback called when users are added to a room.
+func (h *WebhookHandler) OnUsersAddedToRoom(fn func(ctx context.Context, event RoomMembershipEvent) error) {
+	h.On(WebhookEventUsersAddedToRoom, roomMembershipEventCallback(fn))
+}
+
+// OnUsersRemovedFromRoom registers a callback called when users are removed from a room.
+func (h *WebhookHandler) OnUsersRemovedFromRoom(fn func(ctx context.Context, event RoomMembershipEvent) error) {
+	h.On(WebhookEventUsersRemovedFromRoom, roomMembershipEventCallback(fn))
+}
+
+func userEventCallback(fn func(ctx context.Context, event UserEvent) error) func(context.Context, WebhookEvent) error {
+	return func(ctx context.Context, event WebhookEvent) error {
+		var payload struct {
+			Users []User `json:"users"`
+		}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("Failed to decode %s payload: %v", event.Metadata.EventType, err)
+		}
+
+		for _, user := range payload.Users {
+			if err := fn(ctx, UserEvent{Metadata: event.Metadata, User: user}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+func roomMembershipEventCallback(
+	fn func(ctx context.Context, event RoomMembershipEvent) error,
+) func(context.Context, WebhookEvent) error {
+	return func(ctx context.Context, event WebhookEvent) error {
+		var payload struct {
+			Room  Room   `json:"room"`
+			Users []User `json:"users"`
+		}
+		if err := json.Unmarshal(event.Payload, &payload); err != nil {
+			return fmt.Errorf("Failed to decode %s payload: %v", event.Metadata.EventType, err)
+		}
+
+		return fn(ctx, RoomMembershipEvent{
+			Metadata: event.Metadata,
+			Room:     payload.Room,
+			Users:    payload.Users,
+		})
+	}
+}
+
+// ServeHTTP verifies and processes a webhook request.
+func (h *WebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := VerifyWebhookRequest(r, h.options.Secret)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var event WebhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		h.reportError(event, fmt.Errorf("Failed to decode webhook: %v", err))
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	w.WriteHeader(h.handle(r.Context(), event))
+}
+
+// handle processes an event, or queues it for the workers, and returns the status to respond
+// with.
+func (h *WebhookHandler) handle(ctx context.Context, event WebhookEvent) int {
+	if h.queue == nil {
+		if err := h.dispatch(ctx, event); err != nil {
+			h.reportError(event, err)
+			return http.StatusInternalServerError
+		}
+		return http.StatusOK
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return http.StatusServiceUnavailable
+	}
+
+	select {
+	case h.queue <- event:
+		return http.StatusOK
+	default:
+		return http.StatusServiceUnavailable
+	}
+}
+
+// dispatch calls the callbacks registered for an event, stopping at the first error.
+func (h *WebhookHandler) dispatch(ctx context.Context, event WebhookEvent) error {
+	for _, fn := range h.callbacks[event.Metadata.EventType] {
+		if err := fn(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (h *WebhookHandler) reportError(event WebhookEvent, err error) {
+	if h.options.OnError != nil {
+		h.options.OnError(event, err)
+	}
+}
+
+// Close stops the handler accepting events, and waits for the workers to process those already
+// queued or for ctx to be done. Requests received afterwards by a handler with workers are
+// refused with a 503.
+func (h *WebhookHandler) Close(ctx context.Context) error {
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		return ErrWebhookHandlerClosed
+	}
+	h.closed = true
+	if h.queue != nil {
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.workers.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,108 @@
+package chatkit
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIGatewayProxyRequest is the event AWS Lambda functions behind an API Gateway proxy
+// integration are invoked with. It has the same JSON encoding as the type of the same name in
+// github.com/aws/aws-lambda-go/events, with only the fields webhooks need, so that the SDK
+// doesn't depend on it.
+type APIGatewayProxyRequest struct {
+	HTTPMethod      string            `json:"httpMethod"`
+	Path            string            `json:"path"`
+	Headers         map[string]string `json:"headers"`
+	Body            string            `json:"body"`
+	IsBase64Encoded bool              `json:"isBase64Encoded"`
+}
+
+// APIGatewayProxyResponse is the response of AWS Lambda functions behind an API Gateway proxy
+// integration, with the same JSON encoding as the type of the same name in
+// github.com/aws/aws-lambda-go/events.
+type APIGatewayProxyResponse struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body"`
+}
+
+// HandleAPIGatewayProxyRequest processes a webhook delivered to an AWS Lambda function through
+// API Gateway, and can be passed to lambda.Start directly:
+//
+//	lambda.Start(handler.HandleAPIGatewayProxyRequest)
+//
+// Lambda freezes functions once they have responded, so handlers used this way must not have
+// Workers.
+func (h *WebhookHandler) HandleAPIGatewayProxyRequest(
+	ctx context.Context,
+	request APIGatewayProxyRequest,
+) (APIGatewayProxyResponse, error) {
+	body := []byte(request.Body)
+	if request.IsBase64Encoded {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(request.Body)
+		if err != nil {
+			return APIGatewayProxyResponse{}, fmt.Errorf("Failed to decode request body: %v", err)
+		}
+	}
+
+	method := request.HTTPMethod
+	if method == "" {
+		method = http.MethodPost
+	}
+
+	httpRequest, err := http.NewRequest(method, "/"+strings.TrimPrefix(request.Path, "/"), bytes.NewReader(body))
+	if err != nil {
+		return APIGatewayProxyResponse{}, err
+	}
+	for name, value := range request.Headers {
+		httpRequest.Header.Set(name, value)
+	}
+
+	recorder := &responseRecorder{header: http.Header{}, status: http.StatusOK}
+	h.ServeHTTP(recorder, httpRequest.WithContext(ctx))
+
+	response := APIGatewayProxyResponse{
+		StatusCode: recorder.status,
+		Headers:    map[string]string{},
+		Body:       recorder.body.String(),
+	}
+	for name := range recorder.header {
+		response.Headers[name] = recorder.header.Get(name)
+	}
+
+	return response, nil
+}
+
+// HandleCloudFunction processes a webhook delivered to a Google Cloud Function with an HTTP
+// trigger, whose entry point can be set to a function calling it. It is equivalent to ServeHTTP.
+func (h *WebhookHandler) HandleCloudFunction(w http.ResponseWriter, r *http.Request) {
+	h.ServeHTTP(w, r)
+}
+
+// responseRecorder is an http.ResponseWriter that keeps the response written to it.
+type responseRecorder struct {
+	header      http.Header
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (r *responseRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status, r.wroteHeader = status, true
+	}
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.WriteHeader(http.StatusOK)
+	return r.body.Write(data)
+}
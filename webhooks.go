@@ -0,0 +1,66 @@
+package chatkit
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+)
+
+// WebhookSignatureHeader is the header Chatkit sends the signature of webhook requests in.
+const WebhookSignatureHeader = "Webhook-Signature"
+
+// maxWebhookBodySize is the size above which webhook request bodies are refused.
+const maxWebhookBodySize = 1 << 20
+
+// ErrInvalidWebhookSignature is returned when a webhook request's signature is missing or does
+// not match its body, meaning it may not have been sent by Chatkit.
+var ErrInvalidWebhookSignature = errors.New("Invalid webhook signature")
+
+// VerifyWebhookRequest reads the body of a webhook request and checks it against the
+// Webhook-Signature header with the webhook's secret, and returns the body once verified.
+// It returns ErrInvalidWebhookSignature if the signature doesn't match. Bodies over 1MB are
+// refused.
+func VerifyWebhookRequest(r *http.Request, secret string) ([]byte, error) {
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, maxWebhookBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("Failed to read webhook body: %v", err)
+	}
+
+	if len(body) > maxWebhookBodySize {
+		return nil, errors.New("Webhook body is too large")
+	}
+
+	if err := VerifyWebhookSignature(body, r.Header.Get(WebhookSignatureHeader), secret); err != nil {
+		return nil, err
+	}
+
+	return body, nil
+}
+
+// VerifyWebhookSignature checks the signature of a webhook body, as sent in the
+// Webhook-Signature header, which is the hex encoded HMAC-SHA1 of the body keyed with the
+// webhook's secret. Signatures are compared in constant time. It returns
+// ErrInvalidWebhookSignature if the signature doesn't match.
+func VerifyWebhookSignature(body []byte, signature string, secret string) error {
+	if secret == "" {
+		return errors.New("You must provide the webhook secret")
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil || len(expected) == 0 {
+		return ErrInvalidWebhookSignature
+	}
+
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(expected, mac.Sum(nil)) {
+		return ErrInvalidWebhookSignature
+	}
+
+	return nil
+}